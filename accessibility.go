@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// defaultMinFontSize is the smallest font size in points check_accessibility accepts
+// unless told otherwise
+const defaultMinFontSize = 12
+
+// AccessibilityIssue is one problem check_accessibility found on a slide
+type AccessibilityIssue struct {
+	Type            string  `json:"type"` // missing_alt_text, low_contrast, small_font or missing_title
+	Message         string  `json:"message"`
+	ShapeIndex      *int    `json:"shape_index,omitempty"` // Not set for slide-level issues
+	ShapeID         string  `json:"shape_id,omitempty"`
+	Child           string  `json:"child,omitempty"` // Name of the shape inside a group the issue is about
+	FontSize        float64 `json:"font_size,omitempty"`
+	Contrast        float64 `json:"contrast,omitempty"`
+	TextColor       string  `json:"text_color,omitempty"`
+	BackgroundColor string  `json:"background_color,omitempty"`
+}
+
+// AccessibilityReport is the result of uno_check_accessibility.py; slides without
+// issues are left out
+type AccessibilityReport struct {
+	CheckedSlides int            `json:"checked_slides"`
+	IssueCount    int            `json:"issue_count"`
+	Summary       map[string]int `json:"summary"`
+	Slides        []struct {
+		SlideNumber int                  `json:"slide_number"`
+		Issues      []AccessibilityIssue `json:"issues"`
+	} `json:"slides"`
+	Message string `json:"message"`
+}
+
+// CheckAccessibilityDefinition defines the check_accessibility tool
+var CheckAccessibilityDefinition = ToolDefinition{
+	Name: "check_accessibility",
+	Description: `Check slides for common accessibility problems and report them per slide with the shape they concern:
+- missing_alt_text: a picture, chart or video without alternative text for screen readers
+- low_contrast: text whose color contrasts less with its background than WCAG AA asks (4.5:1, or 3:1 for large text)
+- small_font: text smaller than min_font_size points (12 by default)
+- missing_title: a slide without a title, which screen reader users navigate by
+
+Contrast is measured against the shape's fill, or the slide's background; text on gradient or picture backgrounds isn't rated. Grouped shapes are checked too and name the group's child.
+
+Fix missing alt text with set_alt_text (read_slide shows what a picture contains), contrast and font sizes with the format_text edit of apply_edits, and titles with edit_slide_text. Leave slides empty to check the whole deck.`,
+	InputSchema: CheckAccessibilityInputSchema,
+	Function:    CheckAccessibility,
+	ReadOnly:    true,
+}
+
+type CheckAccessibilityInput struct {
+	PresentationPath string  `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Slides           []int   `json:"slides,omitempty" jsonschema_description:"(Optional) Slide numbers to check (1-based); omit for the whole deck"`
+	MinFontSize      float64 `json:"min_font_size,omitempty" jsonschema_description:"(Optional) Smallest acceptable font size in points, defaults to 12"`
+}
+
+var CheckAccessibilityInputSchema = GenerateSchema[CheckAccessibilityInput]()
+
+func CheckAccessibility(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	checkInput := CheckAccessibilityInput{}
+	if err := json.Unmarshal(input, &checkInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if checkInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			checkInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+	if _, err := os.Stat(checkInput.PresentationPath); os.IsNotExist(err) {
+		return "", NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", checkInput.PresentationPath)
+	}
+
+	if checkInput.MinFontSize == 0 {
+		checkInput.MinFontSize = defaultMinFontSize
+	}
+	if checkInput.MinFontSize < 4 || checkInput.MinFontSize > 72 {
+		return "", NewToolError(ErrCodeInvalidInput, "min_font_size must be between 4 and 72 points")
+	}
+	for _, slideNumber := range checkInput.Slides {
+		if slideNumber < 1 {
+			return "", NewToolError(ErrCodeSlideOutOfRange, "slide numbers must be 1 or greater")
+		}
+	}
+	slides, err := json.Marshal(checkInput.Slides)
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to encode slides: %v", err)
+	}
+	if checkInput.Slides == nil {
+		slides = []byte("[]")
+	}
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_check_accessibility.py",
+		checkInput.PresentationPath,
+		string(slides),
+		fmt.Sprintf("%g", checkInput.MinFontSize))
+	if err != nil {
+		return "", scriptError("failed to check accessibility", err, output)
+	}
+
+	result, err := decodeScriptResult[AccessibilityReport](output)
+	if err != nil {
+		return "", err
+	}
+	return marshalResult(result)
+}
+
+// SetAltTextDefinition defines the set_alt_text tool
+var SetAltTextDefinition = ToolDefinition{
+	Name: "set_alt_text",
+	Description: `Set the alternative text of a picture, chart, video or other shape: the description screen readers announce in its place, as reported missing by check_accessibility.
+
+Describe what the shape shows and why it is on the slide in a sentence or two, e.g. "Bar chart: revenue doubled from 2022 to 2024". Look at the slide first (read_slide and the slide image) rather than guessing from file names. Replaces any existing alt text.`,
+	InputSchema: SetAltTextInputSchema,
+	Function:    SetAltText,
+	Mutating:    true,
+}
+
+type SetAltTextInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int    `json:"slide_number" jsonschema_description:"Slide containing the shape (1-based indexing)"`
+	ShapeID          string `json:"shape_id,omitempty" jsonschema_description:"(Optional) shape_id of the shape from read_slide or check_accessibility; used instead of shape_index"`
+	ShapeIndex       int    `json:"shape_index" jsonschema_description:"Shape index on the slide; ignored when shape_id is given"`
+	AltText          string `json:"alt_text" jsonschema_description:"Alternative text describing the shape"`
+}
+
+var SetAltTextInputSchema = GenerateSchema[SetAltTextInput]()
+
+func SetAltText(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	altTextInput := SetAltTextInput{}
+	if err := json.Unmarshal(input, &altTextInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if altTextInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			altTextInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if altTextInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	altTextInput.AltText = strings.TrimSpace(altTextInput.AltText)
+	if altTextInput.AltText == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "alt_text is required: describe what the shape shows")
+	}
+	if altTextInput.ShapeID != "" {
+		index, err := resolveShapeID(ctx, app, altTextInput.PresentationPath, altTextInput.SlideNumber, altTextInput.ShapeID)
+		if err != nil {
+			return "", err
+		}
+		altTextInput.ShapeIndex = index
+	}
+	if altTextInput.ShapeIndex < 0 {
+		return "", NewToolError(ErrCodeInvalidInput, "shape_index must be 0 or greater")
+	}
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_set_alt_text.py",
+		altTextInput.PresentationPath,
+		fmt.Sprintf("%d", altTextInput.SlideNumber),
+		fmt.Sprintf("%d", altTextInput.ShapeIndex),
+		altTextInput.AltText)
+	if err != nil {
+		return "", scriptError("failed to set alt text", err, output)
+	}
+
+	// Alt text doesn't show on the slide, so the previews stay as they are
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+	return marshalResult(result)
+}
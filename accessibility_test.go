@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestCheckAccessibilityPassesOptions(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_check_accessibility.py", `{"success": true, "checked_slides": 2, "issue_count": 1, "summary": {"missing_alt_text": 1},
+		"slides": [{"slide_number": 2, "issues": [{"type": "missing_alt_text", "message": "Has no alternative text for screen readers", "shape_index": 1, "shape_id": "Picture 2"}]}]}`)
+
+	output, err := CheckAccessibility(context.Background(), env.app, json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("CheckAccessibility failed: %v", err)
+	}
+	if _, err := CheckAccessibility(context.Background(), env.app, json.RawMessage(`{"slides": [2], "min_font_size": 18}`)); err != nil {
+		t.Fatalf("CheckAccessibility failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_check_accessibility.py")
+	if len(calls) != 2 ||
+		fmt.Sprint(calls[0].Args) != fmt.Sprint([]string{path, "[]", "12"}) ||
+		fmt.Sprint(calls[1].Args) != fmt.Sprint([]string{path, "[2]", "18"}) {
+		t.Fatalf("unexpected script calls: %+v", calls)
+	}
+
+	var report AccessibilityReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatal(err)
+	}
+	slides := report.Slides
+	if len(slides) != 1 || len(slides[0].Issues) != 1 || slides[0].Issues[0].ShapeIndex == nil || *slides[0].Issues[0].ShapeIndex != 1 {
+		t.Errorf("unexpected report: %s", output)
+	}
+
+	for _, bad := range []string{`{"min_font_size": 200}`, `{"slides": [0]}`} {
+		if _, err := CheckAccessibility(context.Background(), env.app, json.RawMessage(bad)); toolErrorCode(err) == "" {
+			t.Errorf("expected %s to be refused", bad)
+		}
+	}
+}
+
+func TestSetAltTextResolvesShapeID(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_set_alt_text.py", `{"success": true, "slide_number": 1, "shape_index": 1}`)
+
+	input := `{"slide_number": 1, "shape_id": "Content Placeholder 2", "alt_text": "  Team photo at the offsite  "}`
+	if _, err := SetAltText(context.Background(), env.app, json.RawMessage(input)); err != nil {
+		t.Fatalf("SetAltText failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_set_alt_text.py")
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint([]string{path, "1", "1", "Team photo at the offsite"}) {
+		t.Fatalf("unexpected script calls: %+v", calls)
+	}
+
+	_, err := SetAltText(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "shape_index": 0, "alt_text": " "}`))
+	if toolErrorCode(err) != ErrCodeInvalidInput {
+		t.Errorf("expected blank alt text to be refused, got %v", err)
+	}
+}
@@ -3,69 +3,180 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/invopop/jsonschema"
-	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 type ToolDefinition struct {
 	Name        string                         `json:"name"`
 	Description string                         `json:"description"`
 	InputSchema anthropic.ToolInputSchemaParam `json:"input_schema"`
-	Function    func(app *App, input json.RawMessage) (string, error)
+	Function    func(ctx context.Context, app *App, input json.RawMessage) (string, error)
+	Mutating    bool          // Modifies the presentation file; executed with backup and rollback
+	Timeout     time.Duration // How long one call may run; zero uses the default tool timeout
+	Screenshot  bool          // Attach a screenshot of the changed slide to successful results
+	Destructive bool          // Removes or rewrites content; needs the user's approval when confirmation is on
+	ReadOnly    bool          // Only reads the presentation, so calls can run alongside other read-only calls
+	Annotates   bool          // Only adds or resolves review comments, so it stays available in review mode
+	WritesFiles bool          // Writes files besides the presentation, so calls never run alongside others
+
+	ManagesHistory bool // Updates the undo history itself instead of getting a snapshot per call
+
+	// NeedsApproval asks for approval of the calls of a tool that is only destructive with
+	// some inputs, e.g. when it replaces an existing file
+	NeedsApproval func(app *App, input json.RawMessage) bool
+}
+
+// defaultToolTimeout bounds a tool call so a hung LibreOffice call can't block the agent loop
+const defaultToolTimeout = 2 * time.Minute
+
+// toolTimeoutEnv overrides defaultToolTimeout, as a Go duration such as "90s".
+// SLIDEPILOT_TOOL_TIMEOUT_<TOOL NAME> overrides the timeout of a single tool.
+const toolTimeoutEnv = "SLIDEPILOT_TOOL_TIMEOUT"
+
+// changesNothing reports whether a call of this tool leaves every file and the open
+// presentation as they were, so it is safe while edits wait for the user's approval
+func (t ToolDefinition) changesNothing() bool {
+	return t.ReadOnly && !t.WritesFiles && !t.Mutating
+}
+
+// timeout returns how long a call of this tool may run
+func (t ToolDefinition) timeout() time.Duration {
+	if d, ok := durationFromEnv(toolTimeoutEnv + "_" + strings.ToUpper(t.Name)); ok {
+		return d
+	}
+	if t.Timeout > 0 {
+		return t.Timeout
+	}
+	if d, ok := durationFromEnv(toolTimeoutEnv); ok {
+		return d
+	}
+	return defaultToolTimeout
+}
+
+// durationFromEnv parses a positive duration from an environment variable
+func durationFromEnv(name string) (time.Duration, bool) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, false
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		fmt.Printf("Warning: ignoring invalid %s=%q\n", name, value)
+		return 0, false
+	}
+	return d, true
 }
 
 type AIAgent struct {
-	client       *anthropic.Client
-	tools        []ToolDefinition
+	mu           sync.Mutex // Serializes turns; conversation and transaction belong to the running turn
+	llm          LLMClient
+	tools        *ToolRegistry // Tools offered to the model
 	conversation []anthropic.MessageParam
-	app          *App            // Reference to the main App
-	ctx          context.Context // For emitting events
+	app          *App             // Reference to the main App
+	ctx          context.Context  // For emitting events
+	transaction  *EditTransaction // Groups the mutating tool calls of the current turn
+	inferences   int              // Numbers inference requests so streamed text blocks get unique IDs
+	turnID       int              // Tags the undo history entries of the running turn
+
+	contextLimit      int // Context window of the model in tokens
+	lastContextTokens int // Tokens the last inference used, 0 when unknown
+
+	retry retryPolicy // How transient model request failures are retried
+
+	planning    bool // The running turn is in plan mode: edits go through submit_edit_plan
+	reviewing   bool // The running turn is in review mode: only comments may be added
+	runningPlan bool // An approved plan is running, so its steps don't ask for approval again
+	reviewPlans bool // Plans wait for the user's approval; off without a UI to answer
+
+	settingsMu sync.Mutex // Guards settings, which the user can change during a turn
+	settings   Settings   // Model, output token limit and temperature of requests
+
+	conversationPath string                              // Presentation the conversation belongs to
+	conversations    *ConversationStore                  // Persists conversations per presentation; nil keeps them in memory only
+	threads          map[string][]anthropic.MessageParam // Conversations of other presentations opened this session
 }
 
-func NewAIAgent(app *App) *AIAgent {
-	client := anthropic.NewClient()
-	tools := []ToolDefinition{
-		ListSlidesDefinition,
-		ReadSlideDefinition,
-		EditSlideTextDefinition,
-		ExportSlidesDefinition,
-		AddSlideDefinition,
-		DeleteSlideDefinition,
-	}
+// MessageDelta is the payload of "ai-message-delta" events: a piece of assistant text
+// appended to the chat bubble with the same ID as it streams in
+type MessageDelta struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
 
+func NewAIAgent(app *App, llm LLMClient) *AIAgent {
 	return &AIAgent{
-		client:       &client,
-		tools:        tools,
+		llm:          llm,
+		tools:        NewToolRegistry(builtinTools()...),
 		conversation: []anthropic.MessageParam{},
+		threads:      make(map[string][]anthropic.MessageParam),
 		app:          app,
 		ctx:          nil, // Will be set when SendMessage is called
+		contextLimit: contextTokenLimit(),
+		settings:     DefaultSettings(),
+		retry:        defaultRetryPolicy,
+		reviewPlans:  true,
 	}
 }
 
 func (a *AIAgent) SendMessage(ctx context.Context, userMessage string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
 	a.ctx = ctx // Store context for event emission
 
+	// A conversation belongs to one presentation; a deck opened during the last turn
+	// switches to its own thread now
+	if err := a.switchConversation(a.app.presentationPath()); err != nil {
+		a.logToFile("ERROR", "Failed to load the presentation's conversation", err.Error())
+	}
+	defer a.saveConversation()
+
+	// Make room for the new turn in long sessions
+	a.compactConversation(ctx)
+
 	// Log user message
 	a.logToFile("USER", userMessage, "")
 
 	// Enhance user message with current presentation context
 	enhancedMessage := userMessage
-	if a.app != nil && a.app.currentPresentationPath != "" {
-		enhancedMessage = fmt.Sprintf("Current presentation loaded: %s\n\nUser request: %s", a.app.currentPresentationPath, userMessage)
+	if currentPath := a.app.presentationPath(); currentPath != "" {
+		enhancedMessage = fmt.Sprintf("Current presentation loaded: %s\n\nUser request: %s", currentPath, userMessage)
 	}
 
 	// Add user message to conversation
 	userMsgParam := anthropic.NewUserMessage(anthropic.NewTextBlock(enhancedMessage))
 	a.conversation = append(a.conversation, userMsgParam)
 
+	// Group all edits made during this turn into a single transaction
+	a.transaction = NewEditTransaction()
+	a.turnID = int(time.Now().UnixNano())
+	settings := a.Settings()
+	a.reviewing = settings.ReviewMode
+	a.planning = settings.PlanMode && !a.reviewing // Review mode has no edits to plan
+	defer func() {
+		a.transaction = nil
+		a.turnID = 0
+		a.planning = false
+		a.reviewing = false
+	}()
+
+	// Export previews once at the end of the turn instead of after every edit
+	if a.app != nil && a.app.exports != nil {
+		a.app.exports.Hold()
+		defer a.app.exports.Release(ctx)
+	}
+
 	// Run inference
-	message, err := a.runInference(context.Background(), a.conversation)
+	message, streamed, err := a.runInference(ctx, a.conversation)
 	if err != nil {
 		a.logToFile("ERROR", "AI inference failed", err.Error())
 		return err
@@ -74,28 +185,28 @@ func (a *AIAgent) SendMessage(ctx context.Context, userMessage string) error {
 
 	// Process tool results in a loop until no more tool calls
 	currentMessage := message
+	currentStreamed := streamed
 
 	for {
-		toolResults := []anthropic.ContentBlockParamUnion{}
+		var toolCalls []anthropic.ContentBlockUnion
 
 		// Process current message content
 		for _, content := range currentMessage.Content {
 			switch content.Type {
 			case "text":
-				// Emit text content as event
+				// Emit text content as event, unless it already reached the UI as deltas
 				if content.Text != "" {
-					a.emitMessage(content.Text)
+					if currentStreamed {
+						a.logToFile("ASSISTANT", content.Text, "")
+					} else {
+						a.emitMessage(content.Text)
+					}
 				}
 			case "tool_use":
-				// Emit tool execution status as event
-				statusMsg := getToolDisplayName(content.Name)
-				a.emitMessage(statusMsg)
-
-				a.logToFile("TOOL_CALL", fmt.Sprintf("Tool: %s", content.Name), string(content.Input))
-				result := a.executeTool(content.ID, content.Name, content.Input)
-				toolResults = append(toolResults, result)
+				toolCalls = append(toolCalls, content)
 			}
 		}
+		toolResults := a.runToolCalls(ctx, toolCalls)
 
 		// If no tool calls were made, we're done
 		if len(toolResults) == 0 {
@@ -106,9 +217,17 @@ func (a *AIAgent) SendMessage(ctx context.Context, userMessage string) error {
 		a.logToFile("DEBUG", fmt.Sprintf("Running inference with %d tool results", len(toolResults)), "")
 		a.conversation = append(a.conversation, anthropic.NewUserMessage(toolResults...))
 
-		nextMessage, err := a.runInference(context.Background(), a.conversation)
+		// Stop before asking for more work if the turn was cancelled
+		if err := ctx.Err(); err != nil {
+			a.logToFile("ERROR", "Turn cancelled", err.Error())
+			a.rollbackTransaction(ctx, "the request was cancelled")
+			return err
+		}
+
+		nextMessage, nextStreamed, err := a.runInference(ctx, a.conversation)
 		if err != nil {
 			a.logToFile("ERROR", "Follow-up inference failed", err.Error())
+			a.rollbackTransaction(ctx, fmt.Sprintf("the turn was interrupted: %v", err))
 			return err
 		}
 		a.logToFile("DEBUG", "Follow-up inference completed successfully", "")
@@ -116,25 +235,251 @@ func (a *AIAgent) SendMessage(ctx context.Context, userMessage string) error {
 
 		// Set up for next iteration
 		currentMessage = nextMessage
+		currentStreamed = nextStreamed
 	}
 
+	// Verify the turn's edits before keeping them
+	if err := a.transaction.Verify(); err != nil {
+		a.logToFile("ERROR", "Transaction verification failed", err.Error())
+		a.rollbackTransaction(ctx, err.Error())
+		return err
+	}
+	a.transaction.Commit()
+
 	return nil
 }
 
+// runToolCall runs one tool call of a model response
+func (a *AIAgent) runToolCall(ctx context.Context, call anthropic.ContentBlockUnion) anthropic.ContentBlockParamUnion {
+	// Every tool_use needs a result, so cancelled calls are answered without running
+	if ctx.Err() != nil {
+		return anthropic.NewToolResultBlock(call.ID,
+			toolErrorEnvelope(NewToolError(ErrCodeCancelled, "the request was cancelled before this tool ran")), true)
+	}
+
+	// Emit tool execution status as event
+	a.emitMessage(getToolDisplayName(call.Name))
+
+	a.logToFile("TOOL_CALL", fmt.Sprintf("Tool: %s", call.Name), string(call.Input))
+	if a.planning {
+		return a.planModeToolUse(ctx, call.ID, call.Name, call.Input)
+	}
+	if a.reviewing {
+		return a.reviewModeToolUse(ctx, call.ID, call.Name, call.Input)
+	}
+	return a.executeTool(ctx, call.ID, call.Name, call.Input)
+}
+
+// RunTool executes a single tool call outside a chat turn, for clients that drive the
+// tools directly such as the MCP server. Like a turn, it waits for any running turn, gets
+// its own undo entry and refreshes the previews once it is done.
+func (a *AIAgent) RunTool(ctx context.Context, id, name string, input json.RawMessage) anthropic.ContentBlockParamUnion {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.turnID = int(time.Now().UnixNano())
+	defer func() {
+		a.turnID = 0
+	}()
+
+	if a.app != nil && a.app.exports != nil {
+		a.app.exports.Hold()
+		defer a.app.exports.Release(ctx)
+	}
+
+	a.logToFile("TOOL_CALL", fmt.Sprintf("Tool: %s (direct)", name), string(input))
+	return a.executeTool(ctx, id, name, input)
+}
+
+// saveConversation persists the conversation of the current presentation
+func (a *AIAgent) saveConversation() {
+	if a.conversations == nil || a.conversationPath == "" || len(a.conversation) == 0 {
+		return
+	}
+	if err := a.conversations.Save(a.conversationPath, a.conversation); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+}
+
+// LoadConversation switches to the conversation of a presentation and returns it as chat
+// bubbles. A presentation without a conversation starts empty.
+func (a *AIAgent) LoadConversation(presentationPath string) ([]ConversationMessage, error) {
+	if !a.mu.TryLock() {
+		return nil, fmt.Errorf("cannot load a conversation while a request is running")
+	}
+	defer a.mu.Unlock()
+
+	if err := a.switchConversation(presentationPath); err != nil {
+		return nil, err
+	}
+	return conversationTranscript(a.conversation), nil
+}
+
+// switchConversation makes a presentation's thread the current conversation, keeping the
+// previous one in memory for when its presentation is opened again. A thread not opened
+// this session is read from the store. On error the new thread starts empty. The caller
+// holds a.mu.
+func (a *AIAgent) switchConversation(presentationPath string) error {
+	if presentationPath == a.conversationPath {
+		return nil
+	}
+	if a.conversationPath != "" && len(a.conversation) > 0 {
+		a.threads[a.conversationPath] = a.conversation
+	}
+
+	messages, found := a.threads[presentationPath]
+	var err error
+	if !found && a.conversations != nil && presentationPath != "" {
+		messages, err = a.conversations.Load(presentationPath)
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+	}
+	if messages == nil || err != nil {
+		messages = []anthropic.MessageParam{}
+	}
+
+	a.conversation = messages
+	a.conversationPath = presentationPath
+	a.lastContextTokens = 0 // Measured on the previous thread
+	return err
+}
+
+// DeleteConversation forgets a presentation's conversation, in memory and on disk. Deleting
+// the current conversation starts it over.
+func (a *AIAgent) DeleteConversation(presentationPath string) error {
+	if !a.mu.TryLock() {
+		return fmt.Errorf("cannot delete a conversation while a request is running")
+	}
+	defer a.mu.Unlock()
+
+	delete(a.threads, presentationPath)
+	if presentationPath == a.conversationPath {
+		a.conversation = []anthropic.MessageParam{}
+		a.lastContextTokens = 0
+	}
+	if a.conversations != nil {
+		return a.conversations.Delete(presentationPath)
+	}
+	return nil
+}
+
+// failTransaction rolls the whole turn back after a failed step and returns an error
+// naming exactly which step failed and which earlier edits were reverted
+func (a *AIAgent) failTransaction(ctx context.Context, step TransactionStep, cause error) error {
+	applied := a.transaction.Steps()
+	report := describeRollback(step, cause, applied)
+	a.logToFile("TRANSACTION", fmt.Sprintf("Step %d (%s) failed", step.Number, step.Tool), report)
+
+	// A failure on the first edit only needs the per-tool restore, so keep the original code
+	if len(applied) == 0 {
+		a.transaction.Commit()
+		return cause
+	}
+
+	revertedSteps := make([]int, len(applied))
+	for i, appliedStep := range applied {
+		revertedSteps[i] = appliedStep.Number
+	}
+	toolErr := NewToolError(ErrCodeRolledBack, "%s", report).
+		WithDetail("failed_step", step.Number).
+		WithDetail("failed_tool", step.Tool).
+		WithDetail("cause_code", toolErrorCode(cause)).
+		WithDetail("reverted_steps", revertedSteps)
+
+	paths := a.transaction.Paths()
+	if err := a.transaction.Rollback(); err != nil {
+		toolErr.Message += fmt.Sprintf("\nWarning: %v", err)
+		return toolErr
+	}
+	a.discardTurnHistory(paths)
+	for _, path := range paths {
+		a.app.watcher.Acknowledge(path)
+	}
+	a.emitMessage(fmt.Sprintf("↩️ Rolled back %d edit(s) because step %d (%s) failed", len(applied), step.Number, step.Tool))
+	a.refreshPreviews(ctx, paths)
+	return toolErr
+}
+
+// rollbackTransaction reverts every edit made so far in this turn and refreshes previews
+func (a *AIAgent) rollbackTransaction(ctx context.Context, reason string) {
+	if a.transaction == nil || len(a.transaction.Steps()) == 0 {
+		return
+	}
+
+	paths := a.transaction.Paths()
+	stepCount := len(a.transaction.Steps())
+	if err := a.transaction.Rollback(); err != nil {
+		a.logToFile("ERROR", "Transaction rollback failed", err.Error())
+		return
+	}
+	a.discardTurnHistory(paths)
+	for _, path := range paths {
+		a.app.watcher.Acknowledge(path)
+	}
+
+	a.logToFile("TRANSACTION", fmt.Sprintf("Rolled back %d edit(s): %s", stepCount, reason), "")
+	a.emitMessage(fmt.Sprintf("↩️ Rolled back %d edit(s) from this request because %s", stepCount, reason))
+	a.refreshPreviews(ctx, paths)
+}
+
+// discardTurnHistory drops the undo entries of a rolled back turn, whose edits no longer exist
+func (a *AIAgent) discardTurnHistory(paths []string) {
+	if a.app.history == nil || a.turnID == 0 {
+		return
+	}
+	for _, path := range paths {
+		if err := a.app.history.DiscardTurn(path, a.turnID); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}
+}
+
+// refreshPreviews re-exports slide images if the loaded presentation is among the given paths.
+// It runs even when ctx is cancelled, since the restored deck must not keep stale previews.
+func (a *AIAgent) refreshPreviews(ctx context.Context, paths []string) {
+	currentPath := a.app.presentationPath()
+	if currentPath == "" {
+		return
+	}
+	for _, path := range paths {
+		if path == currentPath {
+			if a.app.exports != nil {
+				a.app.exports.Forget(path, 0)
+			}
+			if _, err := convertSlides(context.WithoutCancel(ctx), a.app, path, "slides"); err != nil {
+				fmt.Printf("Warning: Failed to refresh slides after rollback: %v\n", err)
+			}
+			return
+		}
+	}
+}
+
 func (a *AIAgent) emitMessage(message string) {
-	if a.ctx != nil {
-		runtime.EventsEmit(a.ctx, "ai-message", message)
+	if a.ctx != nil && a.app != nil && a.app.events != nil {
+		a.app.events.Emit(a.ctx, "ai-message", message)
 		// Also log for debugging
 		a.logToFile("ASSISTANT", message, "")
 	}
 }
 
+// emitDelta sends a piece of streamed assistant text to the frontend
+func (a *AIAgent) emitDelta(id, text string) {
+	if a.ctx != nil && a.app != nil && a.app.events != nil {
+		a.app.events.Emit(a.ctx, "ai-message-delta", MessageDelta{ID: id, Text: text})
+	}
+}
+
 func getToolDisplayName(toolName string) string {
 	switch toolName {
+	case submitEditPlanName:
+		return "📝 Proposing an edit plan"
 	case "list_slides":
 		return "📋 Listing slides"
 	case "read_slide":
 		return "👀 Reading slide content"
+	case "ocr_slide":
+		return "🔍 Reading text in slide images"
 	case "edit_slide_text":
 		return "✏️ Editing slide text"
 	case "export_slides":
@@ -143,6 +488,126 @@ func getToolDisplayName(toolName string) string {
 		return "➕ Adding new slide"
 	case "delete_slide":
 		return "🗑️ Deleting slide"
+	case "move_slide":
+		return "🔀 Moving slide"
+	case "generate_image":
+		return "🎨 Generating image"
+	case "search_stock_image":
+		return "🔎 Searching stock photos"
+	case "insert_stock_image":
+		return "📷 Inserting stock photo"
+	case "insert_image":
+		return "🖼️ Inserting image"
+	case "replace_image":
+		return "🔄 Replacing image"
+	case "insert_media":
+		return "🎬 Inserting media"
+	case "extract_media":
+		return "🗂️ Extracting media"
+	case "optimize_presentation":
+		return "🗜️ Optimizing presentation"
+	case "insert_table":
+		return "📊 Inserting table"
+	case "edit_table_cell":
+		return "✏️ Editing table cell"
+	case "add_shape":
+		return "🔷 Adding shape"
+	case "delete_shape":
+		return "🧹 Deleting shape"
+	case "group_shapes":
+		return "🔗 Grouping shapes"
+	case "ungroup_shapes":
+		return "✂️ Ungrouping shapes"
+	case "set_shape_order":
+		return "🗂️ Changing shape order"
+	case "align_shapes":
+		return "📐 Aligning shapes"
+	case "find_replace_all":
+		return "🔎 Replacing text across slides"
+	case "translate_presentation":
+		return "🌐 Translating presentation"
+	case "translate_slides":
+		return "🌐 Translating slides"
+	case "undo_last_change":
+		return "↩️ Undoing last change"
+	case "save_presentation_as":
+		return "💾 Saving a copy"
+	case "export_pdf":
+		return "📄 Exporting PDF"
+	case "get_presentation_info":
+		return "ℹ️ Reading presentation info"
+	case "set_presentation_info":
+		return "🏷️ Updating presentation info"
+	case "list_layouts":
+		return "🗂️ Listing layouts"
+	case "set_slide_layout":
+		return "🧩 Changing slide layout"
+	case "apply_template":
+		return "🖌️ Applying template"
+	case "read_master":
+		return "🏛️ Reading slide master"
+	case "edit_master_text":
+		return "🏛️ Editing slide master text"
+	case "edit_master_placeholder":
+		return "🏛️ Editing slide master placeholder"
+	case "import_slides":
+		return "📥 Importing slides"
+	case "extract_slides":
+		return "📤 Extracting slides"
+	case "list_sections":
+		return "🗂️ Listing sections"
+	case "add_section":
+		return "➕ Adding section"
+	case "rename_section":
+		return "🏷️ Renaming section"
+	case "delete_section":
+		return "🗑️ Deleting section"
+	case "move_slides_to_section":
+		return "🔀 Moving slides to section"
+	case "list_comments":
+		return "💬 Reading comments"
+	case "add_comment":
+		return "💬 Adding comment"
+	case "resolve_comment":
+		return "✅ Resolving comment"
+	case "check_accessibility":
+		return "♿ Checking accessibility"
+	case "set_alt_text":
+		return "🏷️ Setting alt text"
+	case "spellcheck_presentation":
+		return "🔤 Checking spelling"
+	case "lint_style":
+		return "📏 Checking style consistency"
+	case "check_text_overflow":
+		return "📦 Checking text overflow"
+	case "get_brand_profile":
+		return "🎨 Reading brand profile"
+	case "format_list":
+		return "🔢 Formatting list"
+	case "set_rich_text":
+		return "🅱️ Formatting text"
+	case "add_hyperlink":
+		return "🔗 Adding link"
+	case "set_transition":
+		return "🎞️ Setting transitions"
+	case "set_footer":
+		return "🦶 Setting footer"
+	case "add_animation":
+		return "✨ Adding animation"
+	case "insert_chart":
+		return "📈 Inserting chart"
+	case "edit_chart_data":
+		return "📉 Updating chart data"
+	case "import_data":
+		return "📥 Importing spreadsheet data"
+	case "apply_edits":
+		return "🧰 Applying edits"
+	case "fetch_artifact":
+		return "📦 Reading a stored result"
+	case "create_presentation":
+		return "🆕 Creating presentation"
+	case "generate_from_outline":
+		return "🪄 Building slides from outline"
 	default:
 		return fmt.Sprintf("🔧 Executing %s", toolName)
 	}
@@ -172,9 +637,18 @@ func (a *AIAgent) logToFile(msgType, message, details string) {
 	file.WriteString(logEntry)
 }
 
-func (a *AIAgent) runInference(ctx context.Context, conversation []anthropic.MessageParam) (*anthropic.Message, error) {
+// runInference asks the model for the next message. With a streaming client the text is
+// emitted as "ai-message-delta" events while it is generated, and streamed is true.
+func (a *AIAgent) runInference(ctx context.Context, conversation []anthropic.MessageParam) (message *anthropic.Message, streamed bool, err error) {
+	tools := a.tools.List()
+	if a.planning {
+		tools = a.planModeTools()
+	}
+	if a.reviewing {
+		tools = a.reviewModeTools()
+	}
 	anthropicTools := []anthropic.ToolUnionParam{}
-	for _, tool := range a.tools {
+	for _, tool := range tools {
 		anthropicTools = append(anthropicTools, anthropic.ToolUnionParam{
 			OfTool: &anthropic.ToolParam{
 				Name:        tool.Name,
@@ -184,45 +658,226 @@ func (a *AIAgent) runInference(ctx context.Context, conversation []anthropic.Mes
 		})
 	}
 
-	message, err := a.client.Messages.New(ctx, anthropic.MessageNewParams{
-		Model:     anthropic.ModelClaudeSonnet4_0,
-		MaxTokens: int64(2048),
-		Messages:  conversation,
-		Tools:     anthropicTools,
+	params := a.messageParams()
+	params.System = []anthropic.TextBlockParam{{Text: a.systemPrompt()}}
+	params.Messages = conversation
+	params.Tools = anthropicTools
+
+	span := profiler.Start("llm", "inference")
+	defer span.End()
+
+	defer func() {
+		if err == nil {
+			a.app.recordUsage(ctx, inferencePurpose(message), message)
+			usage := message.Usage
+			a.lastContextTokens = int(usage.InputTokens + usage.CacheReadInputTokens + usage.CacheCreationInputTokens + usage.OutputTokens)
+		}
+	}()
+
+	streamer, ok := a.llm.(LLMStreamer)
+	if !ok {
+		message, err = a.withRetry(ctx, func() (*anthropic.Message, bool, error) {
+			message, err := a.llm.CreateMessage(ctx, params)
+			return message, false, err
+		})
+		return message, false, err
+	}
+
+	message, err = a.withRetry(ctx, func() (*anthropic.Message, bool, error) {
+		a.inferences++
+		inference := a.inferences
+		showedText := false
+		message, err := streamer.StreamMessage(ctx, params, func(block int, text string) {
+			showedText = true
+			a.emitDelta(fmt.Sprintf("%d-%d", inference, block), text)
+		})
+		return message, showedText, err
 	})
-	return message, err
+	return message, true, err
 }
 
-func (a *AIAgent) executeTool(id, name string, input json.RawMessage) anthropic.ContentBlockParamUnion {
-	var toolDef ToolDefinition
-	var found bool
-	for _, tool := range a.tools {
-		if tool.Name == name {
-			toolDef = tool
-			found = true
-			break
+// inferencePurpose describes a model response for the usage log by the tools it called
+func inferencePurpose(message *anthropic.Message) string {
+	var tools []string
+	for _, content := range message.Content {
+		if content.Type == "tool_use" {
+			tools = append(tools, content.Name)
 		}
 	}
+	if len(tools) == 0 {
+		return "reply"
+	}
+	return "tool calls " + strings.Join(tools, ", ")
+}
+
+func (a *AIAgent) executeTool(ctx context.Context, id, name string, input json.RawMessage) (result anthropic.ContentBlockParamUnion) {
+	span := profiler.Start("tool", name)
+	defer func() {
+		span.EndWith(map[string]interface{}{"is_error": result.OfToolResult != nil && result.OfToolResult.IsError.Value})
+	}()
+
+	toolDef, found := a.tools.Lookup(name)
 	if !found {
 		a.logToFile("TOOL_ERROR", fmt.Sprintf("Tool not found: %s", name), "")
-		return anthropic.NewToolResultBlock(id, "tool not found", true)
+		return anthropic.NewToolResultBlock(id, toolErrorEnvelope(NewToolError(ErrCodeToolNotFound, "tool not found: %s", name)), true)
 	}
 
 	// Log current presentation path for debugging
-	currentPath := "none"
-	if a.app != nil && a.app.currentPresentationPath != "" {
-		currentPath = a.app.currentPresentationPath
+	currentPath := a.app.presentationPath()
+	if currentPath == "" {
+		currentPath = "none"
 	}
 	a.logToFile("TOOL_DEBUG", fmt.Sprintf("Executing %s with current presentation: %s", name, currentPath), string(input))
 
+	// Let the user veto destructive changes before anything is touched
+	if a.app.approvals != nil && a.app.approvals.Enabled() && !a.runningPlan && needsApproval(a.app, toolDef, input) {
+		if err := a.awaitApproval(ctx, id, name, input); err != nil {
+			return anthropic.NewToolResultBlock(id, toolErrorEnvelope(err), true)
+		}
+	}
+
+	// Back up the target file so a failed edit never leaves the deck in an unknown state
+	var backup *PresentationBackup
+	var step TransactionStep
+	if toolDef.Mutating {
+		if targetPath := toolTargetPath(a.app, input); targetPath != "" {
+			// Fail early with a clear error rather than a cryptic UNO save failure
+			if lock := detectPresentationLock(targetPath); lock != nil {
+				a.logToFile("TOOL_ERROR", fmt.Sprintf("Tool %s aborted: presentation is locked", name), lock.Reason)
+				return anthropic.NewToolResultBlock(id, toolErrorEnvelope(presentationLockedError(targetPath, lock)), true)
+			}
+
+			// Don't edit over changes another program saved since the app last read the file
+			if a.app.watcher.Changed(targetPath) {
+				a.app.watcher.Acknowledge(targetPath)
+				if a.app.events != nil {
+					a.app.events.Emit(ctx, "presentation-changed-externally", targetPath)
+				}
+				a.logToFile("TOOL_ERROR", fmt.Sprintf("Tool %s aborted: presentation changed externally", name), targetPath)
+				return anthropic.NewToolResultBlock(id, toolErrorEnvelope(NewToolError(ErrCodeFileChanged,
+					"%s was changed by another program since it was last read. Re-read the slides you are editing, then retry; retrying applies the edit to the changed file.", filepath.Base(targetPath)).
+					WithDetail("presentation_path", targetPath)), true)
+			}
+			// The tool's own writes aren't external changes
+			defer a.app.watcher.Acknowledge(targetPath)
+
+			// Keep the untouched original the first time a presentation is changed
+			if a.app.backups != nil {
+				backupPath, err := a.app.backups.BackupOnce(targetPath)
+				if err != nil {
+					a.logToFile("TOOL_ERROR", fmt.Sprintf("Tool %s aborted: backup of the original failed", name), err.Error())
+					return anthropic.NewToolResultBlock(id, toolErrorEnvelope(NewToolError(toolErrorCodeOr(err, ErrCodeBackupFailed), "%v", err)), true)
+				}
+				if backupPath != "" {
+					a.logToFile("BACKUP", fmt.Sprintf("Backed up %s to %s", targetPath, backupPath), "")
+				}
+			}
+
+			if a.transaction != nil {
+				if err := a.transaction.Begin(targetPath); err != nil {
+					a.logToFile("TOOL_ERROR", fmt.Sprintf("Tool %s aborted: transaction snapshot failed", name), err.Error())
+					return anthropic.NewToolResultBlock(id, toolErrorEnvelope(NewToolError(toolErrorCodeOr(err, ErrCodeBackupFailed), "%v", err)), true)
+				}
+				step = TransactionStep{Number: len(a.transaction.Steps()) + 1, Tool: name, Path: targetPath}
+			}
+
+			var err error
+			backupSpan := profiler.Start("tool", "backup")
+			backup, err = BackupPresentation(targetPath)
+			backupSpan.End()
+			if err != nil {
+				a.logToFile("TOOL_ERROR", fmt.Sprintf("Tool %s aborted: backup failed", name), err.Error())
+				return anthropic.NewToolResultBlock(id, toolErrorEnvelope(NewToolError(toolErrorCodeOr(err, ErrCodeBackupFailed), "%v", err)), true)
+			}
+			defer backup.Discard()
+		}
+	}
+
 	fmt.Printf("Executing tool: %s(%s)\n", name, input)
-	response, err := toolDef.Function(a.app, input)
+	timeout := toolDef.timeout()
+	toolCtx, cancel := context.WithTimeout(withToolCall(ctx, id, name), timeout)
+	response, err := toolDef.Function(toolCtx, a.app, input)
+	if err != nil && errors.Is(toolCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		err = NewToolError(ErrCodeUnoTimeout, "%s timed out after %v", name, timeout).
+			WithDetail("timeout_seconds", timeout.Seconds())
+	}
+	cancel()
+
+	// LibreOffice saves without the deck's sections
+	if backup != nil && err == nil && !resultReportsFailure(response) {
+		if restored, restoreErr := restoreSections(backup.BackupPath, backup.OriginalPath); restoreErr != nil {
+			fmt.Printf("Warning: Failed to restore sections after %s: %v\n", name, restoreErr)
+		} else if restored {
+			a.logToFile("TOOL_DEBUG", fmt.Sprintf("Restored the sections %s dropped", name), "")
+		}
+	}
+
+	// Re-open the saved file before reporting success, catching silent corruption early
+	if backup != nil && err == nil && !resultReportsFailure(response) {
+		verifySpan := profiler.Start("tool", "integrity_check")
+		report, verifyErr := VerifyPresentationIntegrity(ctx, a.app, backup.OriginalPath)
+		verifySpan.End()
+		if verifyErr != nil {
+			a.logToFile("TOOL_ERROR", fmt.Sprintf("Tool %s failed integrity check", name), verifyErr.Error())
+			err = NewToolError(ErrCodeIntegrityCheckFailed, "integrity check failed after %s: %v", name, verifyErr)
+		} else {
+			response = attachIntegrityReport(response, report)
+		}
+	}
+
+	if backup != nil && (err != nil || resultReportsFailure(response)) {
+		if restoreErr := backup.Restore(); restoreErr != nil {
+			a.logToFile("TOOL_ERROR", fmt.Sprintf("Tool %s rollback failed", name), restoreErr.Error())
+		} else {
+			a.logToFile("TOOL_ROLLBACK", fmt.Sprintf("Tool %s failed, restored %s from backup", name, backup.OriginalPath), "")
+			if err != nil {
+				err = NewToolError(toolErrorCode(err), "%v\n(The presentation was restored to its state before this tool call.)", err).
+					WithDetail("rolled_back", true)
+			}
+		}
+	}
+	if backup != nil && a.transaction != nil {
+		if err != nil || resultReportsFailure(response) {
+			cause := err
+			if cause == nil {
+				cause = NewToolError(ErrCodeScriptFailed, "tool reported failure: %s", response)
+			}
+			return anthropic.NewToolResultBlock(id, toolErrorEnvelope(a.failTransaction(ctx, step, cause)), true)
+		}
+		a.transaction.RecordStep(name, step.Path)
+	}
+
 	if err != nil {
-		a.logToFile("TOOL_ERROR", fmt.Sprintf("Tool %s failed", name), err.Error())
-		return anthropic.NewToolResultBlock(id, err.Error(), true)
+		a.logToFile("TOOL_ERROR", fmt.Sprintf("Tool %s failed (%s)", name, toolErrorCode(err)), err.Error())
+		return anthropic.NewToolResultBlock(id, toolErrorEnvelope(err), true)
 	}
 
+	response = normalizeToolResult(response)
 	a.logToFile("TOOL_RESULT", fmt.Sprintf("Tool %s completed", name), response)
+	response = a.limitToolResult(name, response)
+
+	// Keep the version from before this call so the change can be undone later
+	if backup != nil && !toolDef.ManagesHistory && a.app.history != nil {
+		if err := a.app.history.Record(backup.OriginalPath, backup.BackupPath, name, a.turnID); err != nil {
+			fmt.Printf("Warning: Failed to record undo history: %v\n", err)
+		}
+	}
+
+	// Let the model see the slide it just changed
+	if toolDef.Screenshot && backup != nil && visionFeedbackEnabled() {
+		if slideNumber := feedbackSlideNumber(input, response); slideNumber > 0 {
+			screenshotSpan := profiler.Start("tool", "screenshot")
+			imagePath, err := slideScreenshot(ctx, a.app, backup.OriginalPath, slideNumber)
+			if err == nil {
+				result, err = toolResultWithScreenshot(id, response, imagePath, slideNumber)
+			}
+			screenshotSpan.End()
+			if err == nil {
+				return result
+			}
+			fmt.Printf("Warning: Failed to attach screenshot of slide %d: %v\n", slideNumber, err)
+		}
+	}
 	return anthropic.NewToolResultBlock(id, response, false)
 }
 
@@ -0,0 +1,402 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendMessageRunsToolLoop(t *testing.T) {
+	env := newTestEnv(t,
+		toolUseResponse("toolu_1", "list_slides", `{}`),
+		textResponse("The deck has two slides."),
+	)
+	env.loadFixture(t, "two_slides.pptx")
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "How many slides?"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	if len(env.llm.Requests) != 2 {
+		t.Fatalf("expected 2 inference requests, got %d", len(env.llm.Requests))
+	}
+	// user, assistant tool_use, user tool_result, assistant text
+	if len(env.app.aiAgent.conversation) != 4 {
+		t.Errorf("expected 4 conversation messages, got %d", len(env.app.aiAgent.conversation))
+	}
+
+	messages := env.events.Messages("ai-message")
+	if len(messages) == 0 || messages[len(messages)-1] != "The deck has two slides." {
+		t.Errorf("expected final text to be emitted, got %v", messages)
+	}
+}
+
+func TestSendMessageStreamsTextDeltas(t *testing.T) {
+	env := newTestEnv(t,
+		toolUseResponse("toolu_1", "list_slides", `{}`),
+		textResponse("The deck has two slides."),
+	)
+	env.app.aiAgent.llm = FakeStreamingLLM{env.llm}
+	env.loadFixture(t, "two_slides.pptx")
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "How many slides?"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	var text strings.Builder
+	ids := map[string]bool{}
+	for _, message := range env.events.Messages("ai-message-delta") {
+		delta := message.(MessageDelta)
+		ids[delta.ID] = true
+		text.WriteString(delta.Text)
+	}
+	if text.String() != "The deck has two slides." || len(ids) != 1 {
+		t.Errorf("expected the final text streamed into one bubble, got %q in %d", text.String(), len(ids))
+	}
+
+	// Streamed text is not sent again as a whole message; tool status still is
+	for _, message := range env.events.Messages("ai-message") {
+		if message == "The deck has two slides." {
+			t.Error("streamed text was emitted twice")
+		}
+	}
+	if messages := env.events.Messages("ai-message"); len(messages) != 1 || messages[0] != getToolDisplayName("list_slides") {
+		t.Errorf("expected only the tool status message, got %v", messages)
+	}
+}
+
+func TestExecuteToolRestoresDeckWhenEditFails(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	original, _ := os.ReadFile(path)
+
+	// Simulate a script that corrupts the file and then fails
+	env.uno.Handle("uno_edit_slide.py", func(args []string) ([]byte, error) {
+		os.WriteFile(args[0], []byte("half-written"), 0644)
+		return []byte(`{"success": false, "error": "Error editing slide: Shape index 7 out of range (0-1)"}`), fmt.Errorf("exit status 1")
+	})
+
+	result := env.app.aiAgent.executeTool(context.Background(), "toolu_1", "edit_slide_text",
+		[]byte(`{"slide_number": 1, "target_type": "shape_index", "target_value": "7", "new_text": "x"}`))
+
+	if !result.OfToolResult.IsError.Value {
+		t.Fatal("expected an error result")
+	}
+	restored, _ := os.ReadFile(path)
+	if !bytes.Equal(original, restored) {
+		t.Fatal("presentation was not restored after the failed edit")
+	}
+	content := result.OfToolResult.Content[0].OfText.Text
+	if !strings.Contains(content, string(ErrCodeShapeNotFound)) {
+		t.Errorf("expected %s in result, got %s", ErrCodeShapeNotFound, content)
+	}
+}
+
+func TestExecuteToolTimesOutHungScript(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	original, _ := os.ReadFile(path)
+	t.Setenv("SLIDEPILOT_TOOL_TIMEOUT_EDIT_SLIDE_TEXT", "50ms")
+
+	// Simulate LibreOffice hanging after touching the file
+	release := make(chan struct{})
+	defer close(release)
+	env.uno.Handle("uno_edit_slide.py", func(args []string) ([]byte, error) {
+		os.WriteFile(args[0], []byte("half-written"), 0644)
+		<-release
+		return []byte(`{"success": true}`), nil
+	})
+
+	result := env.app.aiAgent.executeTool(context.Background(), "toolu_1", "edit_slide_text",
+		[]byte(`{"slide_number": 1, "target_type": "shape_index", "target_value": "0", "new_text": "x"}`))
+
+	if !result.OfToolResult.IsError.Value {
+		t.Fatal("expected an error result")
+	}
+	content := result.OfToolResult.Content[0].OfText.Text
+	if !strings.Contains(content, string(ErrCodeUnoTimeout)) || !strings.Contains(content, "timed out after 50ms") {
+		t.Errorf("expected a timeout error, got %s", content)
+	}
+	restored, _ := os.ReadFile(path)
+	if !bytes.Equal(original, restored) {
+		t.Fatal("presentation was not restored after the timeout")
+	}
+}
+
+func TestToolTimeoutPrecedence(t *testing.T) {
+	tool := ToolDefinition{Name: "export_slides", Timeout: 5 * time.Minute}
+	if got := tool.timeout(); got != 5*time.Minute {
+		t.Errorf("expected the tool's own timeout, got %v", got)
+	}
+	if got := (ToolDefinition{Name: "read_slide"}).timeout(); got != defaultToolTimeout {
+		t.Errorf("expected the default timeout, got %v", got)
+	}
+
+	t.Setenv("SLIDEPILOT_TOOL_TIMEOUT", "30s")
+	if got := (ToolDefinition{Name: "read_slide"}).timeout(); got != 30*time.Second {
+		t.Errorf("expected the configured default, got %v", got)
+	}
+	if got := tool.timeout(); got != 5*time.Minute {
+		t.Errorf("configured default should not override the tool's timeout, got %v", got)
+	}
+
+	t.Setenv("SLIDEPILOT_TOOL_TIMEOUT_EXPORT_SLIDES", "10m")
+	if got := tool.timeout(); got != 10*time.Minute {
+		t.Errorf("expected the per-tool override, got %v", got)
+	}
+}
+
+func TestTransactionRollsBackWholeTurn(t *testing.T) {
+	env := newTestEnv(t,
+		toolUseResponse("toolu_1", "edit_slide_text", `{"slide_number": 1, "target_type": "shape_index", "target_value": "0", "new_text": "First"}`),
+		toolUseResponse("toolu_2", "delete_slide", `{"slide_number": 5}`),
+		textResponse("Something went wrong."),
+	)
+	path := env.loadFixture(t, "two_slides.pptx")
+	original, _ := os.ReadFile(path)
+
+	// The first edit succeeds and changes the file (keeping it a valid package)
+	env.uno.Handle("uno_edit_slide.py", func(args []string) ([]byte, error) {
+		f, _ := os.OpenFile(args[0], os.O_APPEND|os.O_WRONLY, 0644)
+		f.Write([]byte{0})
+		f.Close()
+		return []byte(`{"success": true}`), nil
+	})
+	env.uno.Handle("uno_delete_slide.py", func(args []string) ([]byte, error) {
+		return []byte(`{"success": false, "error": "Error deleting slide: Invalid slide number 5. Presentation has 2 slides."}`), fmt.Errorf("exit status 1")
+	})
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Edit and delete"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	restored, _ := os.ReadFile(path)
+	if !bytes.Equal(original, restored) {
+		t.Fatal("expected the whole turn to be rolled back")
+	}
+
+	lastRequest := env.llm.Requests[len(env.llm.Requests)-1]
+	lastMessage := lastRequest.Messages[len(lastRequest.Messages)-1]
+	report := lastMessage.Content[0].OfToolResult.Content[0].OfText.Text
+	if !strings.Contains(report, string(ErrCodeRolledBack)) || !strings.Contains(report, "step 2 (delete_slide)") {
+		t.Errorf("expected rollback report naming step 2, got %s", report)
+	}
+}
+
+func TestTransactionRollbackIsNotAnExternalChange(t *testing.T) {
+	t.Setenv(visionFeedbackEnv, "0")
+	env := newTestEnv(t,
+		toolUseResponse("toolu_1", "edit_slide_text", `{"slide_number": 1, "target_type": "shape_index", "target_value": "0", "new_text": "First"}`),
+		toolUseResponse("toolu_2", "delete_slide", `{"slide_number": 5, "presentation_path": "other.pptx"}`),
+		textResponse("Something went wrong."),
+	)
+	path := env.loadFixture(t, "two_slides.pptx")
+	data, _ := os.ReadFile(path)
+	os.WriteFile(filepath.Join(env.dir, "other.pptx"), data, 0644)
+	appendingEdit(env)
+	env.uno.Handle("uno_delete_slide.py", func(args []string) ([]byte, error) {
+		return []byte(`{"success": false, "error": "Invalid slide number 5"}`), fmt.Errorf("exit status 1")
+	})
+
+	// The failed step is on another deck, so restoring the loaded one is the rollback's doing
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Edit both decks"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if restored, _ := os.ReadFile(path); !bytes.Equal(data, restored) {
+		t.Fatal("expected the whole turn to be rolled back")
+	}
+	if _, changed := env.app.watcher.Poll(); changed {
+		t.Error("the rollback's own write was reported as an external change")
+	}
+}
+
+func TestTransactionSnapshotsEachFileOnce(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	original, _ := os.ReadFile(path)
+	wd, _ := os.Getwd()
+	relative, err := filepath.Rel(wd, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The second name for the deck must not snapshot the already edited file
+	transaction := NewEditTransaction()
+	transaction.Begin(path)
+	os.WriteFile(path, append(original, 0), 0644)
+	transaction.Begin("./" + relative)
+	if paths := transaction.Paths(); len(paths) != 1 || paths[0] != path {
+		t.Fatalf("expected one snapshot of %s, got %v", path, paths)
+	}
+	if err := transaction.Rollback(); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if restored, _ := os.ReadFile(path); !bytes.Equal(original, restored) {
+		t.Error("expected the file as it was before the turn")
+	}
+}
+
+func TestRepeatedEditsExportOncePerTurn(t *testing.T) {
+	edit := func(id string, slide int) string {
+		return toolUseResponse(id, "edit_slide_text",
+			fmt.Sprintf(`{"slide_number": %d, "target_type": "shape_index", "target_value": "0", "new_text": "v%s"}`, slide, id))
+	}
+	env := newTestEnv(t,
+		edit("toolu_1", 1),
+		edit("toolu_2", 1),
+		edit("toolu_3", 2),
+		edit("toolu_4", 1),
+		textResponse("Done."),
+	)
+	env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_edit_slide.py", `{"success": true}`)
+	// Screenshots render each edited slide right away; this test covers the deferred export
+	t.Setenv(visionFeedbackEnv, "0")
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Polish the titles"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	if len(env.converter.Calls) != 0 {
+		t.Errorf("expected no full exports, got %d", len(env.converter.Calls))
+	}
+	if len(env.converter.RangeCalls) != 1 || env.converter.RangeCalls[0] != [2]int{0, 1} {
+		t.Errorf("expected one coalesced export of slides 1-2 at turn end, got %v", env.converter.RangeCalls)
+	}
+}
+
+func TestEditResultIncludesSlideScreenshot(t *testing.T) {
+	env := newTestEnv(t,
+		toolUseResponse("toolu_1", "edit_slide_text", `{"slide_number": 2, "target_type": "shape_index", "target_value": "0", "new_text": "Hello"}`),
+		textResponse("Done."),
+	)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Handle("uno_edit_slide.py", func(args []string) ([]byte, error) {
+		now := time.Now()
+		os.Chtimes(path, now, now)
+		return []byte(`{"success": true}`), nil
+	})
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Edit slide 2"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	// The edited slide is rendered once for the screenshot and not again at turn end
+	if len(env.converter.RangeCalls) != 1 || env.converter.RangeCalls[0] != [2]int{1, 1} {
+		t.Errorf("expected slide 2 to be rendered once, got %v", env.converter.RangeCalls)
+	}
+
+	toolResult := env.llm.Requests[1].Messages[2].Content[0].OfToolResult
+	if toolResult == nil || len(toolResult.Content) != 3 {
+		t.Fatalf("expected the result, a caption and an image, got %+v", toolResult)
+	}
+	image := toolResult.Content[2].OfImage
+	if image == nil || image.Source.OfBase64 == nil {
+		t.Fatal("expected a base64 image block")
+	}
+	if image.Source.OfBase64.Data != base64.StdEncoding.EncodeToString([]byte("rendered 1")) {
+		t.Errorf("expected the rendered preview of slide 2, got %q", image.Source.OfBase64.Data)
+	}
+}
+
+func TestScreenshotSkippedWhenDisabled(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_edit_slide.py", `{"success": true}`)
+	t.Setenv(visionFeedbackEnv, "false")
+
+	result := env.app.aiAgent.executeTool(context.Background(), "toolu_1", "edit_slide_text",
+		[]byte(`{"slide_number": 1, "target_type": "shape_index", "target_value": "0", "new_text": "x"}`))
+
+	if result.OfToolResult.IsError.Value || len(result.OfToolResult.Content) != 1 {
+		t.Errorf("expected a plain text result, got %+v", result.OfToolResult.Content)
+	}
+	if len(env.converter.RangeCalls) != 0 {
+		t.Errorf("expected no rendering before the export, got %v", env.converter.RangeCalls)
+	}
+}
+
+func TestCancelledTurnStopsAndRollsBack(t *testing.T) {
+	env := newTestEnv(t,
+		toolUseResponse("toolu_1", "edit_slide_text", `{"slide_number": 1, "target_type": "shape_index", "target_value": "0", "new_text": "First"}`),
+		toolUseResponse("toolu_2", "delete_slide", `{"slide_number": 2}`),
+	)
+	path := env.loadFixture(t, "two_slides.pptx")
+	original, _ := os.ReadFile(path)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env.uno.Handle("uno_edit_slide.py", func(args []string) ([]byte, error) {
+		f, _ := os.OpenFile(args[0], os.O_APPEND|os.O_WRONLY, 0644)
+		f.Write([]byte{0})
+		f.Close()
+		cancel() // the user cancels while the edit is running
+		return []byte(`{"success": true}`), nil
+	})
+
+	err := env.app.aiAgent.SendMessage(ctx, "Edit then delete")
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(env.llm.Requests) != 1 {
+		t.Errorf("expected no inference after cancellation, got %d requests", len(env.llm.Requests))
+	}
+	if len(env.uno.Calls("uno_delete_slide.py")) != 0 {
+		t.Error("no tools should run after cancellation")
+	}
+	restored, _ := os.ReadFile(path)
+	if !bytes.Equal(original, restored) {
+		t.Fatal("expected the cancelled turn's edits to be rolled back")
+	}
+}
+
+func TestCancelAIRequestStopsTheRunningTurn(t *testing.T) {
+	env := newTestEnv(t,
+		toolUseResponse("toolu_1", "edit_slide_text", `{"slide_number": 1, "target_type": "shape_index", "target_value": "0", "new_text": "First"}`),
+		textResponse("Never reached."),
+	)
+	env.loadFixture(t, "two_slides.pptx")
+	env.uno.Handle("uno_edit_slide.py", func(args []string) ([]byte, error) {
+		env.app.CancelAIRequest()
+		return []byte(`{"success": true}`), nil
+	})
+
+	if err := env.app.SendMessageToAI("Edit slide 1"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(env.llm.Requests) != 1 {
+		t.Errorf("expected no inference after cancellation, got %d requests", len(env.llm.Requests))
+	}
+	if cancelled := env.events.Messages("cancelled"); len(cancelled) != 1 {
+		t.Errorf("expected one cancelled event, got %v", cancelled)
+	}
+
+	// Cancelling while idle does nothing
+	env.app.CancelAIRequest()
+	if cancelled := env.events.Messages("cancelled"); len(cancelled) != 1 {
+		t.Errorf("expected no event while idle, got %v", cancelled)
+	}
+}
+
+func TestPythonUnoBridgeHonorsCancellation(t *testing.T) {
+	scriptsDir := t.TempDir()
+	os.WriteFile(scriptsDir+"/slow.py", []byte("import time\ntime.sleep(30)\n"), 0644)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := (PythonUnoBridge{ScriptsDir: scriptsDir}).Run(ctx, "slow.py"); err == nil {
+		t.Fatal("expected the cancelled script to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("script was not interrupted, ran for %v", elapsed)
+	}
+}
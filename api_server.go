@@ -0,0 +1,375 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// apiTokenEnv overrides the bearer token API clients must send, which otherwise comes
+// from Settings.APIToken
+const apiTokenEnv = "SLIDEPILOT_API_TOKEN"
+
+// defaultAPIAddr is where --api listens when no address is given
+const defaultAPIAddr = "localhost:8766"
+
+// maxAPIRequestBytes bounds request bodies
+const maxAPIRequestBytes = 16 * 1024 * 1024
+
+// EventBroadcaster fans the app's events out to subscribers, such as API clients
+// streaming a chat turn. Emitting never waits for a subscriber: one whose buffer is full
+// is dropped and its channel closed, so a stalled client can't hold up the agent.
+type EventBroadcaster struct {
+	mu          sync.Mutex
+	subscribers map[int]chan apiEvent
+	next        int
+}
+
+// apiEvent is one event delivered to a subscriber
+type apiEvent struct {
+	Name string
+	Data interface{}
+}
+
+// NewEventBroadcaster creates a broadcaster without subscribers
+func NewEventBroadcaster() *EventBroadcaster {
+	return &EventBroadcaster{subscribers: make(map[int]chan apiEvent)}
+}
+
+func (b *EventBroadcaster) Emit(ctx context.Context, event string, data ...interface{}) {
+	var payload interface{}
+	if len(data) > 0 {
+		payload = data[0]
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for id, events := range b.subscribers {
+		select {
+		case events <- apiEvent{Name: event, Data: payload}:
+		default:
+			delete(b.subscribers, id)
+			close(events)
+		}
+	}
+}
+
+// subscriberBuffer is how many events a subscriber may fall behind before it is dropped
+const subscriberBuffer = 256
+
+// Subscribe returns a channel receiving every event emitted until unsubscribe is called.
+// The channel is closed when the subscriber falls more than subscriberBuffer events behind.
+func (b *EventBroadcaster) Subscribe() (<-chan apiEvent, func()) {
+	events := make(chan apiEvent, subscriberBuffer)
+	b.mu.Lock()
+	id := b.next
+	b.next++
+	b.subscribers[id] = events
+	b.mu.Unlock()
+	return events, func() {
+		b.mu.Lock()
+		delete(b.subscribers, id)
+		b.mu.Unlock()
+	}
+}
+
+// APIServer exposes the app over HTTP for other applications: loading a presentation,
+// calling tools and streaming chat turns as server-sent events. Every request needs
+// "Authorization: Bearer <token>".
+type APIServer struct {
+	app    *App
+	events *EventBroadcaster
+	token  string
+	chat   sync.Mutex   // One chat turn at a time, so a stream only carries its own turn's events
+	calls  atomic.Int64 // Numbers tool calls for their tool_use IDs
+}
+
+// NewAPIServer creates a server for an app whose events go to the given broadcaster
+func NewAPIServer(app *App, events *EventBroadcaster, token string) *APIServer {
+	return &APIServer{app: app, events: events, token: token}
+}
+
+// Handler returns the API routes
+func (s *APIServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/presentation", s.handleLoadPresentation)
+	mux.HandleFunc("GET /api/tools", s.handleListTools)
+	mux.HandleFunc("POST /api/tools/{name}", s.handleCallTool)
+	mux.HandleFunc("POST /api/chat", s.handleChat)
+	mux.HandleFunc("POST /api/chat/cancel", s.handleCancelChat)
+	return s.authenticate(mux)
+}
+
+// authenticate rejects requests without the bearer token
+func (s *APIServer) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || s.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeAPIError(w, http.StatusUnauthorized, NewToolError(ErrCodeUnauthorized, "missing or invalid bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleLoadPresentation loads {"path": "..."} and renders its previews
+func (s *APIServer) handleLoadPresentation(w http.ResponseWriter, r *http.Request) {
+	var request OpenPresentationInput
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxAPIRequestBytes)).Decode(&request); err != nil || request.Path == "" {
+		writeAPIError(w, http.StatusBadRequest, NewToolError(ErrCodeInvalidInput, "body must be {\"path\": \"...\"}"))
+		return
+	}
+	if _, err := os.Stat(request.Path); err != nil {
+		writeAPIError(w, http.StatusNotFound, NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", request.Path))
+		return
+	}
+	slides, err := s.app.LoadPresentation(request.Path)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, NewToolError(toolErrorCodeOr(err, ErrCodeExportFailed), "%v", err))
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, ToolResult{Success: true, Data: map[string]interface{}{
+		"presentation_path": s.app.presentationPath(),
+		"total_slides":      len(slides),
+		"slides":            slides,
+	}})
+}
+
+// handleListTools lists the tools with their input schemas
+func (s *APIServer) handleListTools(w http.ResponseWriter, r *http.Request) {
+	tools := []map[string]interface{}{}
+	for _, tool := range s.app.aiAgent.tools.List() {
+		tools = append(tools, map[string]interface{}{
+			"name":         tool.Name,
+			"description":  tool.Description,
+			"input_schema": tool.InputSchema,
+			"mutating":     tool.Mutating,
+		})
+	}
+	writeAPIJSON(w, http.StatusOK, ToolResult{Success: true, Data: map[string]interface{}{"tools": tools}})
+}
+
+// handleCallTool runs a tool with the request body as its input and answers with the
+// tool's result envelope
+func (s *APIServer) handleCallTool(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, found := s.app.aiAgent.tools.Lookup(name); !found {
+		writeAPIError(w, http.StatusNotFound, NewToolError(ErrCodeToolNotFound, "unknown tool: %s", name))
+		return
+	}
+	input, err := io.ReadAll(io.LimitReader(r.Body, maxAPIRequestBytes))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, NewToolError(ErrCodeInvalidInput, "failed to read body: %v", err))
+		return
+	}
+	if len(strings.TrimSpace(string(input))) == 0 {
+		input = []byte("{}")
+	}
+	if !json.Valid(input) {
+		writeAPIError(w, http.StatusBadRequest, NewToolError(ErrCodeInvalidInput, "body must be the tool input as JSON"))
+		return
+	}
+
+	id := fmt.Sprintf("api_%d", s.calls.Add(1))
+	result := s.app.aiAgent.RunTool(r.Context(), id, name, json.RawMessage(input)).OfToolResult
+	status := http.StatusOK
+	if result.IsError.Value {
+		status = http.StatusUnprocessableEntity
+	}
+	for _, part := range result.Content {
+		if part.OfText != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			io.WriteString(w, part.OfText.Text)
+			return
+		}
+	}
+	writeAPIJSON(w, status, ToolResult{Success: !result.IsError.Value})
+}
+
+// handleChat runs a chat turn for {"message": "..."} and streams it as server-sent events:
+// "message" for status lines and complete replies, "delta" for streamed reply text, then
+// "done", "cancelled" or "error". Disconnecting cancels the turn.
+func (s *APIServer) handleChat(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxAPIRequestBytes)).Decode(&request); err != nil || strings.TrimSpace(request.Message) == "" {
+		writeAPIError(w, http.StatusBadRequest, NewToolError(ErrCodeInvalidInput, "body must be {\"message\": \"...\"}"))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeAPIError(w, http.StatusInternalServerError, NewToolError(ErrCodeInternal, "streaming unsupported"))
+		return
+	}
+
+	s.chat.Lock()
+	defer s.chat.Unlock()
+	events, unsubscribe := s.events.Subscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.app.SendMessageToAI(request.Message)
+	}()
+
+	disconnected := r.Context().Done()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				// Dropped for falling behind; the turn's outcome still follows
+				events = nil
+				continue
+			}
+			if writeChatEvent(w, event) {
+				flusher.Flush()
+			}
+		case <-disconnected:
+			s.app.CancelAIRequest()
+			disconnected = nil
+		case err := <-done:
+			unsubscribe()
+			// Events emitted just before the turn ended may still be buffered
+			for len(events) > 0 {
+				writeChatEvent(w, <-events)
+			}
+			if errors.Is(err, context.Canceled) {
+				writeSSE(w, "cancelled", map[string]interface{}{"presentation_path": s.app.presentationPath()})
+			} else if err != nil {
+				writeSSE(w, "error", map[string]interface{}{"error_code": toolErrorCodeOr(err, ErrCodeInternal), "error": err.Error()})
+			} else {
+				writeSSE(w, "done", map[string]interface{}{"presentation_path": s.app.presentationPath()})
+			}
+			flusher.Flush()
+			return
+		}
+	}
+}
+
+// writeChatEvent forwards an app event to a chat stream, reporting whether it was one a
+// chat client sees
+func writeChatEvent(w io.Writer, event apiEvent) bool {
+	switch event.Name {
+	case "ai-message":
+		writeSSE(w, "message", map[string]interface{}{"text": event.Data})
+	case "ai-message-delta":
+		writeSSE(w, "delta", event.Data)
+	case "conversion-progress":
+		writeSSE(w, "progress", event.Data)
+	default:
+		return false
+	}
+	return true
+}
+
+// handleCancelChat cancels the running chat turn
+func (s *APIServer) handleCancelChat(w http.ResponseWriter, r *http.Request) {
+	s.app.CancelAIRequest()
+	writeAPIJSON(w, http.StatusOK, ToolResult{Success: true})
+}
+
+// writeSSE writes one server-sent event with a JSON payload
+func writeSSE(w io.Writer, event string, data interface{}) {
+	payload, _ := json.Marshal(data)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeAPIError answers with the same envelope a failed tool returns
+func writeAPIError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	io.WriteString(w, toolErrorEnvelope(err))
+}
+
+// apiFlags returns the address requested with "--api[=<addr>]"; ok is false without it
+func apiFlags(args []string) (addr string, ok bool) {
+	for _, arg := range args {
+		arg = "-" + strings.TrimLeft(arg, "-")
+		switch {
+		case arg == "-api":
+			return defaultAPIAddr, true
+		case strings.HasPrefix(arg, "-api="):
+			return strings.TrimPrefix(arg, "-api="), true
+		}
+	}
+	return "", false
+}
+
+// apiToken returns the token API clients must send: SLIDEPILOT_API_TOKEN, else the one in
+// the settings. Without either a random token is generated and saved to the settings so it
+// survives restarts. The token itself only goes to a file readable by the user, never to
+// the output, which may end up in logs.
+func apiToken(app *App) string {
+	if token := os.Getenv(apiTokenEnv); token != "" {
+		return token
+	}
+	settings := app.aiAgent.Settings()
+	if settings.APIToken != "" {
+		return settings.APIToken
+	}
+	tokenBytes := make([]byte, 24)
+	rand.Read(tokenBytes)
+	settings.APIToken = hex.EncodeToString(tokenBytes)
+	if _, err := app.UpdateSettings(settings); err != nil {
+		fmt.Printf("Warning: API token not saved: %v\n", err)
+	}
+	path := apiTokenPath(app)
+	if err := os.WriteFile(path, []byte(settings.APIToken+"\n"), 0600); err != nil {
+		fmt.Printf("Generated an API token, but could not write it to %s: %v\n", path, err)
+	} else {
+		fmt.Printf("Generated an API token; clients can read it from %s\n", path)
+	}
+	return settings.APIToken
+}
+
+// apiTokenPath is the file a generated API token is written to, next to the settings
+func apiTokenPath(app *App) string {
+	return filepath.Join(filepath.Dir(app.settings.path), "api_token")
+}
+
+// runAPIServer starts LibreOffice without a window and serves the API until interrupted
+func runAPIServer(addr string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	events := NewEventBroadcaster()
+	app := NewHeadlessApp(events)
+	app.startup(ctx)
+	defer app.shutdown(context.Background())
+
+	httpServer := &http.Server{Addr: addr, Handler: NewAPIServer(app, events, apiToken(app)).Handler()}
+	go func() {
+		<-ctx.Done()
+		httpServer.Close()
+	}()
+	fmt.Printf("Serving API at http://%s/api\n", addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestAPIServer serves the API of a test app, routing its events through a broadcaster
+func newTestAPIServer(t *testing.T, env *testEnv) *httptest.Server {
+	t.Helper()
+	events := NewEventBroadcaster()
+	env.app.events = events
+	server := httptest.NewServer(NewAPIServer(env.app, events, "secret").Handler())
+	t.Cleanup(server.Close)
+	return server
+}
+
+// apiRequest sends an authenticated request and returns the status and body
+func apiRequest(t *testing.T, server *httptest.Server, method, path, token, body string) (int, string) {
+	t.Helper()
+	request, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	data, _ := io.ReadAll(response.Body)
+	return response.StatusCode, string(data)
+}
+
+func TestAPIServerRequiresBearerToken(t *testing.T) {
+	env := newTestEnv(t)
+	server := newTestAPIServer(t, env)
+
+	for _, token := range []string{"", "wrong"} {
+		status, body := apiRequest(t, server, "GET", "/api/tools", token, "")
+		if status != http.StatusUnauthorized || !strings.Contains(body, string(ErrCodeUnauthorized)) {
+			t.Errorf("token %q: expected 401, got %d %s", token, status, body)
+		}
+	}
+	if status, body := apiRequest(t, server, "GET", "/api/tools", "secret", ""); status != http.StatusOK || !strings.Contains(body, `"name":"list_slides"`) {
+		t.Errorf("expected the tool list, got %d %s", status, body)
+	}
+}
+
+func TestAPIToken(t *testing.T) {
+	env := newTestEnv(t)
+	env.app.settings = NewSettingsStore(filepath.Join(t.TempDir(), "settings.json"))
+	t.Setenv(apiTokenEnv, "")
+
+	// A generated token is saved, so the next start serves the same one
+	token := apiToken(env.app)
+	if saved, err := env.app.settings.Load(); err != nil || token == "" || saved.APIToken != token {
+		t.Fatalf("expected the generated token %q saved, got %+v (%v)", token, saved, err)
+	}
+	// It is written to a file only the user can read rather than printed
+	info, err := os.Stat(apiTokenPath(env.app))
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("expected the token in a 0600 file, got %v (%v)", info, err)
+	}
+	if data, _ := os.ReadFile(apiTokenPath(env.app)); strings.TrimSpace(string(data)) != token {
+		t.Errorf("expected the token file to hold %q, got %q", token, data)
+	}
+	if again := apiToken(env.app); again != token {
+		t.Errorf("expected the saved token %q, got %q", token, again)
+	}
+
+	t.Setenv(apiTokenEnv, "from-env")
+	if got := apiToken(env.app); got != "from-env" {
+		t.Errorf("expected %s to override the settings, got %q", apiTokenEnv, got)
+	}
+}
+
+func TestAPIServerCallsTools(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	server := newTestAPIServer(t, env)
+
+	status, body := apiRequest(t, server, "POST", "/api/tools/list_slides", "secret", "")
+	if status != http.StatusOK || !strings.Contains(body, `"total_slides":2`) {
+		t.Errorf("unexpected list_slides response %d %s", status, body)
+	}
+
+	status, body = apiRequest(t, server, "POST", "/api/tools/read_slide", "secret", `{"slide_number": 9}`)
+	if status != http.StatusUnprocessableEntity || !strings.Contains(body, string(ErrCodeSlideOutOfRange)) {
+		t.Errorf("expected the tool error envelope, got %d %s", status, body)
+	}
+
+	status, body = apiRequest(t, server, "POST", "/api/tools/no_such_tool", "secret", "{}")
+	if status != http.StatusNotFound || !strings.Contains(body, string(ErrCodeToolNotFound)) {
+		t.Errorf("expected an unknown tool error, got %d %s", status, body)
+	}
+}
+
+func TestAPIServerStreamsChat(t *testing.T) {
+	env := newTestEnv(t,
+		toolUseResponse("toolu_1", "list_slides", `{}`),
+		textResponse("The deck has two slides."),
+	)
+	env.loadFixture(t, "two_slides.pptx")
+	server := newTestAPIServer(t, env)
+
+	status, body := apiRequest(t, server, "POST", "/api/chat", "secret", `{"message": "How many slides?"}`)
+	if status != http.StatusOK {
+		t.Fatalf("expected 200, got %d %s", status, body)
+	}
+	listing := strings.Index(body, "event: message\ndata: {\"text\":\"📋 Listing slides\"}")
+	reply := strings.Index(body, "event: message\ndata: {\"text\":\"The deck has two slides.\"}")
+	done := strings.Index(body, "event: done")
+	if listing < 0 || reply < listing || done < reply {
+		t.Errorf("unexpected event stream:\n%s", body)
+	}
+}
+
+func TestEventBroadcasterDropsStalledSubscriber(t *testing.T) {
+	events := NewEventBroadcaster()
+	stalled, unsubscribeStalled := events.Subscribe()
+	reading, unsubscribeReading := events.Subscribe()
+	defer unsubscribeReading()
+
+	// A subscriber that stops reading must not block emitting or its own unsubscribe
+	finished := make(chan struct{})
+	go func() {
+		for i := 0; i < subscriberBuffer+10; i++ {
+			events.Emit(context.Background(), "ai-message-delta", i)
+			<-reading
+		}
+		unsubscribeStalled()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("emitting blocked on a subscriber that stopped reading")
+	}
+
+	received := 0
+	for range stalled {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Errorf("expected the stalled subscriber's channel closed after %d buffered events, got %d", subscriberBuffer, received)
+	}
+}
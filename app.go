@@ -2,12 +2,16 @@ package main
 
 import (
 	"context"
-	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
@@ -15,17 +19,92 @@ import (
 // App struct
 type App struct {
 	ctx                     context.Context
+	mu                      sync.RWMutex // Guards currentPresentationPath and cancelTurn
 	aiAgent                 *AIAgent
-	imageCache              map[string]string // Cache for base64 images
-	currentPresentationPath string            // Track currently loaded presentation
+	imageCache              *ImageCache            // Base64 data URIs of slide images, least recently used evicted first
+	currentPresentationPath string                 // Track currently loaded presentation
+	converter               SlideConverter         // Renders slide images
+	uno                     UnoBridge              // Runs UNO scripts against LibreOffice
+	events                  EventEmitter           // Delivers events to the frontend
+	exports                 *ExportScheduler       // Coalesces slide preview exports
+	cancelTurn              context.CancelFunc     // Cancels the running AI turn, nil when idle
+	approvals               *ApprovalGate          // Holds destructive tool calls for the user's approval
+	history                 *EditHistory           // Undo and redo snapshots of edited presentations
+	backups                 *BackupStore           // Copies of presentations before their first edit, nil to skip
+	watcher                 *PresentationWatcher   // Notices changes other programs make to the loaded presentation
+	usage                   *UsageTracker          // Token usage and estimated cost of this session
+	settings                *SettingsStore         // Persists the user's settings, nil to keep them in memory
+	artifacts               *ArtifactStore         // Full tool results too long for the conversation
+	recent                  *RecentStore           // Presentations opened last, nil to not remember them
+	libreOffice             *LibreOfficeSupervisor // Keeps the headless LibreOffice running, nil in tests
 }
 
 // NewApp creates a new App application struct
 func NewApp() *App {
+	return newAppFromEnv(WailsEmitter{})
+}
+
+// NewHeadlessApp creates an App that runs without a window, e.g. behind the MCP server or
+// the CLI. Destructive tool calls aren't held for approval, since there is no UI to answer
+// them; the client decides what to run.
+func NewHeadlessApp(events EventEmitter) *App {
+	app := newAppFromEnv(events)
+	app.approvals.SetEnabled(false)
+	app.aiAgent.reviewPlans = false
+	return app
+}
+
+// newAppFromEnv creates an App with the real backends configured from the environment
+func newAppFromEnv(events EventEmitter) *App {
+	var llm LLMClient
+	llm, err := NewLLMClientFromEnv()
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+		llm = unavailableLLM{err: err}
+	}
+	uno := NewUnoWorkerBridge(unoScriptsDir())
+	app := NewAppWithBackends(UnoConverter{Bridge: uno}, uno, llm, events)
+	app.aiAgent.conversations = NewConversationStore(conversationsDir())
+	app.backups = backupStoreFromEnv()
+	app.settings = NewSettingsStore(settingsPath())
+	app.settings.defaultModel = configuredModel(llm)
+	app.recent = NewRecentStore(recentDir())
+	if settings, err := app.settings.Load(); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	} else {
+		app.aiAgent.SetSettings(settings)
+	}
+	loadPlugins(app.aiAgent.tools, pluginsDir())
+	return app
+}
+
+// configuredModel returns the model the provider was configured with through
+// SLIDEPILOT_LLM_MODEL or its own default, which new settings start from
+func configuredModel(llm LLMClient) string {
+	switch client := llm.(type) {
+	case *AnthropicClient:
+		return client.model
+	case *OpenAIClient:
+		return client.model
+	}
+	return ""
+}
+
+// NewAppWithBackends creates an App with explicit backends, allowing fakes in tests
+func NewAppWithBackends(converter SlideConverter, uno UnoBridge, llm LLMClient, events EventEmitter) *App {
 	app := &App{
-		imageCache: make(map[string]string),
+		imageCache: NewImageCache(defaultImageCacheBytes),
+		converter:  converter,
+		uno:        uno,
+		events:     events,
+		approvals:  NewApprovalGate(),
+		history:    NewEditHistory(),
+		watcher:    NewPresentationWatcher(),
+		usage:      NewUsageTracker(),
+		artifacts:  NewArtifactStore(""),
 	}
-	app.aiAgent = NewAIAgent(app)
+	app.exports = NewExportScheduler(app)
+	app.aiAgent = NewAIAgent(app, llm)
 	return app
 }
 
@@ -34,13 +113,52 @@ func NewApp() *App {
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 
-	// Start LibreOffice headless service
-	if err := StartLibreOfficeHeadless(); err != nil {
+	// Start LibreOffice headless service and restart it whenever it dies. Resolved before
+	// any UNO script runs, since the scripts read the port from the environment.
+	a.libreOffice = NewLibreOfficeSupervisor(resolveLibreOfficeConfig(), func(status LibreOfficeStatus) {
+		if a.events != nil {
+			a.events.Emit(a.ctx, "libreoffice-status", status)
+		}
+	})
+	if err := a.libreOffice.Start(); err != nil {
 		fmt.Printf("Failed to start LibreOffice service: %v\n", err)
 	}
 
 	// Create slides directory if it doesn't exist
 	os.MkdirAll("slides", 0755)
+
+	// Remove temp files orphaned by crashed runs and previews nobody has looked at in a while
+	cleanupOnStartup()
+
+	go a.watchPresentation(ctx)
+}
+
+// shutdown is called when the app is closing
+func (a *App) shutdown(ctx context.Context) {
+	// Stop any running turn so its subprocesses are killed instead of outliving the window
+	a.CancelAIRequest()
+	if closer, ok := a.uno.(io.Closer); ok {
+		closer.Close()
+	}
+	// Stop the LibreOffice this app started, after the worker has dropped its connection
+	if a.libreOffice != nil {
+		if err := a.libreOffice.Stop(libreOfficeStopTimeout); err != nil {
+			fmt.Printf("Failed to stop LibreOffice service: %v\n", err)
+		}
+	}
+	cleanupOnShutdown()
+
+	if err := profiler.Flush(); err != nil {
+		fmt.Printf("Failed to write profile trace: %v\n", err)
+	}
+}
+
+// GetLibreOfficeStatus reports the state of the headless LibreOffice service
+func (a *App) GetLibreOfficeStatus() LibreOfficeStatus {
+	if a.libreOffice == nil {
+		return LibreOfficeStatus{State: libreOfficeStopped}
+	}
+	return a.libreOffice.Status()
 }
 
 // Greet returns a greeting for the given name
@@ -50,12 +168,178 @@ func (a *App) Greet(name string) string {
 
 // SendMessageToAI sends a message to the AI agent and returns the response
 func (a *App) SendMessageToAI(message string) error {
-	err := a.aiAgent.SendMessage(a.ctx, message)
-	// Clear image cache after AI interaction since slides might have been modified
-	a.ClearImageCache()
+	ctx, cancel := context.WithCancel(a.baseContext())
+	a.mu.Lock()
+	a.cancelTurn = cancel
+	a.mu.Unlock()
+	defer func() {
+		a.mu.Lock()
+		a.cancelTurn = nil
+		a.mu.Unlock()
+		cancel()
+	}()
+
+	err := a.aiAgent.SendMessage(ctx, message)
+	if err != nil && ctx.Err() != nil && a.events != nil {
+		a.events.Emit(a.baseContext(), "cancelled", err.Error())
+	}
+	if flushErr := profiler.Flush(); flushErr != nil {
+		fmt.Printf("Failed to write profile trace: %v\n", flushErr)
+	}
 	return err
 }
 
+// CancelAIRequest stops the running AI turn. In-flight inference, UNO scripts and exports
+// are interrupted and the turn's edits are rolled back; once the turn has stopped,
+// "cancelled" is emitted.
+func (a *App) CancelAIRequest() {
+	a.mu.RLock()
+	cancel := a.cancelTurn
+	a.mu.RUnlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// SetConfirmDestructive turns approval of destructive tool calls (deleting slides or
+// shapes, deck-wide replace, translation) on or off
+func (a *App) SetConfirmDestructive(enabled bool) {
+	a.approvals.SetEnabled(enabled)
+}
+
+// GetConfirmDestructive reports whether destructive tool calls need approval
+func (a *App) GetConfirmDestructive() bool {
+	return a.approvals.Enabled()
+}
+
+// RespondToolApproval answers a "tool-approval-request" event
+func (a *App) RespondToolApproval(id string, approved bool) error {
+	return a.approvals.Respond(id, approved)
+}
+
+// GetUsageStats returns the tokens used and the estimated cost of this session
+func (a *App) GetUsageStats() UsageStats {
+	return a.usage.Stats()
+}
+
+// GetSettings returns the model settings in use
+func (a *App) GetSettings() Settings {
+	return a.aiAgent.Settings()
+}
+
+// UpdateSettings validates and saves new settings. They apply from the next model request,
+// including one later in a running turn.
+func (a *App) UpdateSettings(settings Settings) (Settings, error) {
+	if err := settings.Validate(); err != nil {
+		return a.aiAgent.Settings(), err
+	}
+	if a.settings != nil {
+		if err := a.settings.Save(settings); err != nil {
+			return a.aiAgent.Settings(), err
+		}
+	}
+	a.aiAgent.SetSettings(settings)
+	return settings, nil
+}
+
+// ListModels returns the models the user can switch to, the current one first
+func (a *App) ListModels() []ModelOption {
+	ctx := a.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return listModels(ctx, a.aiAgent.llm, a.aiAgent.Settings().Model)
+}
+
+// Undo reverts the last change to the current presentation and returns the refreshed slides
+func (a *App) Undo() ([]string, error) {
+	return a.stepHistory(true)
+}
+
+// Redo reapplies the last undone change and returns the refreshed slides
+func (a *App) Redo() ([]string, error) {
+	return a.stepHistory(false)
+}
+
+// GetHistoryState reports whether the current presentation can be undone or redone
+func (a *App) GetHistoryState() HistoryState {
+	path := a.presentationPath()
+	if path == "" {
+		return HistoryState{}
+	}
+	return a.history.State(path)
+}
+
+func (a *App) stepHistory(undo bool) ([]string, error) {
+	path := a.presentationPath()
+	if path == "" {
+		return nil, fmt.Errorf("no presentation loaded")
+	}
+	// The running turn owns the file; it can undo through the undo_last_change tool
+	if !a.aiAgent.mu.TryLock() {
+		return nil, fmt.Errorf("cannot undo or redo while a request is running")
+	}
+	defer a.aiAgent.mu.Unlock()
+
+	var err error
+	if undo {
+		_, err = a.history.Undo(path, 0)
+	} else {
+		_, err = a.history.Redo(path, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.watcher.Acknowledge(path)
+	a.exports.Forget(path, 0)
+	return convertSlides(a.baseContext(), a, path, "slides")
+}
+
+// ListBackups returns the backups of the current presentation, newest first
+func (a *App) ListBackups() ([]BackupInfo, error) {
+	path := a.presentationPath()
+	if path == "" {
+		return nil, fmt.Errorf("no presentation loaded")
+	}
+	if a.backups == nil {
+		return []BackupInfo{}, nil
+	}
+	return a.backups.List(path)
+}
+
+// RestoreBackup replaces the current presentation with one of its backups and returns the
+// refreshed slides. The replaced version goes onto the undo stack, so a restore can be undone.
+func (a *App) RestoreBackup(backupPath string) ([]string, error) {
+	path := a.presentationPath()
+	if path == "" {
+		return nil, fmt.Errorf("no presentation loaded")
+	}
+	if a.backups == nil || !a.backups.Owns(path, backupPath) {
+		return nil, fmt.Errorf("%s is not a backup of %s", backupPath, filepath.Base(path))
+	}
+	if _, err := os.Stat(backupPath); err != nil {
+		return nil, fmt.Errorf("backup not found: %s", backupPath)
+	}
+	if !a.aiAgent.mu.TryLock() {
+		return nil, fmt.Errorf("cannot restore a backup while a request is running")
+	}
+	defer a.aiAgent.mu.Unlock()
+
+	if err := a.history.Record(path, path, "Restore backup", 0); err != nil {
+		return nil, err
+	}
+	if err := copyFile(backupPath, path); err != nil {
+		return nil, fmt.Errorf("failed to restore backup: %v", err)
+	}
+
+	a.watcher.Acknowledge(path)
+	a.exports.Forget(path, 0)
+	return convertSlides(a.baseContext(), a, path, "slides")
+}
+
 // GetSlides returns a list of slide image files in the slides directory
 func (a *App) GetSlides() ([]string, error) {
 	slidesDir := "slides"
@@ -92,15 +376,19 @@ func (a *App) GetSlides() ([]string, error) {
 	return slides, nil
 }
 
-// OpenPresentationDialog opens a file dialog to select a PowerPoint presentation
+// OpenPresentationDialog opens a file dialog to select a presentation
 func (a *App) OpenPresentationDialog() ([]string, error) {
 	selection, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
-		Title: "Select PowerPoint Presentation",
+		Title: "Select Presentation",
 		Filters: []runtime.FileFilter{
 			{
-				DisplayName: "PowerPoint Files (*.pptx)",
-				Pattern:     "*.pptx",
+				DisplayName: "Presentations (*.pptx, *.ppt, *.odp, *.key)",
+				Pattern:     "*.pptx;*.PPTX;*.ppt;*.PPT;*.odp;*.ODP;*.key;*.KEY",
 			},
+			{DisplayName: "PowerPoint Files (*.pptx)", Pattern: "*.pptx;*.PPTX"},
+			{DisplayName: "PowerPoint 97-2003 Files (*.ppt)", Pattern: "*.ppt;*.PPT"},
+			{DisplayName: "OpenDocument Presentations (*.odp)", Pattern: "*.odp;*.ODP"},
+			{DisplayName: "Keynote Presentations (*.key)", Pattern: "*.key;*.KEY"},
 		},
 	})
 	if err != nil {
@@ -115,29 +403,354 @@ func (a *App) OpenPresentationDialog() ([]string, error) {
 	return a.LoadPresentation(selection)
 }
 
-// LoadPresentation loads a PowerPoint file and exports slides to JPEG
+// LoadPresentation loads a presentation and exports slides to JPEG. .ppt, .odp and .key
+// files are converted to a working .pptx next to them, which is what gets loaded and edited.
 func (a *App) LoadPresentation(pptxPath string) ([]string, error) {
-	// Clear image cache since we're loading new slides
-	a.ClearImageCache()
-
 	// Ensure we have absolute path for AI tools
 	absPath, err := filepath.Abs(pptxPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get absolute path: %v", err)
 	}
 
-	slides, err := ConvertPPTXToJPEG(absPath, "slides")
+	absPath, conversion, err := prepareWorkingCopy(a.baseContext(), a, absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load presentation: %v", err)
+	}
+
+	slides, err := convertSlides(a.baseContext(), a, absPath, "slides")
 	if err != nil {
 		return nil, fmt.Errorf("failed to load presentation: %v", err)
 	}
 
 	// Store the absolute current presentation path for AI tools
-	a.currentPresentationPath = absPath
+	a.setPresentationPath(absPath)
 	fmt.Printf("Loaded presentation: %s\n", absPath)
+	if conversion != nil && a.events != nil {
+		a.events.Emit(a.baseContext(), "presentation-converted", *conversion)
+	}
+
+	if a.recent != nil {
+		firstSlide := ""
+		if len(slides) > 0 {
+			firstSlide = slides[0]
+		}
+		if err := a.recent.Record(absPath, len(slides), firstSlide); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}
+
+	// Switch to the presentation's conversation. A running turn, such as one that opened
+	// this deck, keeps its conversation and the next message switches.
+	if a.aiAgent.mu.TryLock() {
+		if err := a.aiAgent.switchConversation(absPath); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+		a.aiAgent.mu.Unlock()
+	}
 
 	return slides, nil
 }
 
+// ReloadPresentation loads the current presentation again, e.g. after another program
+// changed it, and returns the refreshed slides
+func (a *App) ReloadPresentation() ([]string, error) {
+	path := a.presentationPath()
+	if path == "" {
+		return nil, fmt.Errorf("no presentation loaded")
+	}
+	if !a.aiAgent.mu.TryLock() {
+		return nil, fmt.Errorf("cannot reload while a request is running")
+	}
+	defer a.aiAgent.mu.Unlock()
+	a.exports.Forget(path, 0)
+	return a.LoadPresentation(path)
+}
+
+// GetRecentPresentations returns the presentations opened last, most recent first, with a
+// thumbnail of each. Files that no longer exist are left out.
+func (a *App) GetRecentPresentations() ([]RecentPresentation, error) {
+	if a.recent == nil {
+		return []RecentPresentation{}, nil
+	}
+	return a.recent.List()
+}
+
+// OpenRecent loads a presentation from the recent list. A file that was moved or deleted
+// is dropped from the list.
+func (a *App) OpenRecent(path string) ([]string, error) {
+	if _, err := os.Stat(path); err != nil {
+		if a.recent != nil {
+			a.recent.Forget(path)
+		}
+		return nil, fmt.Errorf("%s no longer exists and was removed from the recent list", filepath.Base(path))
+	}
+	return a.LoadPresentation(path)
+}
+
+// NewPresentationDialog asks where to create a new presentation, creates it and loads it
+func (a *App) NewPresentationDialog(aspectRatio, template string) ([]string, error) {
+	directory := documentsDir()
+	if path := a.presentationPath(); path != "" {
+		directory = filepath.Dir(path)
+	}
+	selection, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:            "New Presentation",
+		DefaultDirectory: directory,
+		DefaultFilename:  "Untitled.pptx",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "PowerPoint Files (*.pptx)", Pattern: "*.pptx"},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file dialog: %v", err)
+	}
+	if selection == "" {
+		// User cancelled
+		return []string{}, nil
+	}
+	return a.NewPresentation(selection, aspectRatio, template)
+}
+
+// NewPresentation creates a presentation with one blank slide at path and loads it. The
+// slide size follows aspectRatio ("16:9" when empty, "4:3", "16:10" or "a4"), and template,
+// if given, is a .potx or .pptx whose masters and theme the presentation takes. An
+// existing file is never replaced.
+func (a *App) NewPresentation(path, aspectRatio, template string) ([]string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %v", err)
+	}
+	outputPath, err := createPresentationFile(a.baseContext(), a, CreatePresentationInput{
+		OutputPath:   absPath,
+		AspectRatio:  aspectRatio,
+		TemplatePath: template,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create presentation: %v", err)
+	}
+	fmt.Printf("Created presentation: %s\n", outputPath)
+	return a.LoadPresentation(outputPath)
+}
+
+// GenerateFromOutlineDialog asks for a Markdown or text outline file and adds its slides
+// to the end of the current presentation
+func (a *App) GenerateFromOutlineDialog() ([]string, error) {
+	path := a.presentationPath()
+	if path == "" {
+		return nil, fmt.Errorf("no presentation loaded")
+	}
+	selection, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:            "Select Outline",
+		DefaultDirectory: filepath.Dir(path),
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Outlines (*.md, *.txt)", Pattern: "*.md;*.markdown;*.txt"},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file dialog: %v", err)
+	}
+	if selection == "" {
+		// User cancelled
+		return []string{}, nil
+	}
+	if err := a.runTool("generate_from_outline", GenerateFromOutlineInput{OutlinePath: selection}); err != nil {
+		return nil, err
+	}
+	return a.GetSlides()
+}
+
+// GenerateFromOutline adds the slides of a Markdown or text outline to the end of the
+// current presentation and returns the refreshed slides. Like the AI's edits, it can be
+// undone.
+func (a *App) GenerateFromOutline(outline string) ([]string, error) {
+	if a.presentationPath() == "" {
+		return nil, fmt.Errorf("no presentation loaded")
+	}
+	if err := a.runTool("generate_from_outline", GenerateFromOutlineInput{Outline: outline}); err != nil {
+		return nil, err
+	}
+	return a.GetSlides()
+}
+
+// runTool runs a tool for a binding the way the MCP server does, as an undoable step of
+// its own, and returns the tool's error message as an error
+func (a *App) runTool(name string, input interface{}) error {
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	result := a.aiAgent.RunTool(a.baseContext(), fmt.Sprintf("ui_%d", time.Now().UnixNano()), name, inputJSON).OfToolResult
+	if !result.IsError.Value {
+		return nil
+	}
+	for _, part := range result.Content {
+		var envelope ToolResult
+		if part.OfText != nil && json.Unmarshal([]byte(part.OfText.Text), &envelope) == nil && envelope.Error != "" {
+			return fmt.Errorf("%s", envelope.Error)
+		}
+	}
+	return fmt.Errorf("%s failed", name)
+}
+
+// SavePresentationAsDialog asks where to save a copy of the current presentation and saves it
+func (a *App) SavePresentationAsDialog() (string, error) {
+	path := a.presentationPath()
+	if path == "" {
+		return "", fmt.Errorf("no presentation loaded")
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	selection, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:            "Save Presentation As",
+		DefaultDirectory: filepath.Dir(path),
+		DefaultFilename:  name + " copy.pptx",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "PowerPoint Files (*.pptx)", Pattern: "*.pptx"},
+			{DisplayName: "OpenDocument Presentations (*.odp)", Pattern: "*.odp"},
+			{DisplayName: "PDF Documents (*.pdf)", Pattern: "*.pdf"},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to open file dialog: %v", err)
+	}
+	if selection == "" {
+		// User cancelled
+		return "", nil
+	}
+	return a.SavePresentationAs(selection)
+}
+
+// SavePresentationAs writes a copy of the current presentation to path (.pptx, .odp or
+// .pdf), replacing any existing file, and returns the copy's absolute path. A .pptx copy
+// becomes the current presentation, so later edits leave the original untouched.
+func (a *App) SavePresentationAs(path string) (string, error) {
+	current := a.presentationPath()
+	if current == "" {
+		return "", fmt.Errorf("no presentation loaded")
+	}
+	// The running turn may be writing the file
+	if !a.aiAgent.mu.TryLock() {
+		return "", fmt.Errorf("cannot save while a request is running")
+	}
+	defer a.aiAgent.mu.Unlock()
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %v", err)
+	}
+	outputPath, _, err := savePresentationCopy(a.baseContext(), a, current, absPath, true)
+	if err != nil {
+		return "", err
+	}
+
+	if strings.EqualFold(filepath.Ext(outputPath), ".pptx") {
+		a.setPresentationPath(outputPath)
+		fmt.Printf("Saved presentation as %s and switched to it\n", outputPath)
+	} else {
+		fmt.Printf("Saved a copy of the presentation to %s\n", outputPath)
+	}
+	return outputPath, nil
+}
+
+// ExportPDFDialog asks where to save a PDF of the current presentation and exports it
+func (a *App) ExportPDFDialog(options PDFExportOptions) (string, error) {
+	path := a.presentationPath()
+	if path == "" {
+		return "", fmt.Errorf("no presentation loaded")
+	}
+	selection, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:            "Export PDF",
+		DefaultDirectory: filepath.Dir(path),
+		DefaultFilename:  filepath.Base(defaultPDFPath(path, options.WithDefaults(0))),
+		Filters: []runtime.FileFilter{
+			{DisplayName: "PDF Documents (*.pdf)", Pattern: "*.pdf"},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to open file dialog: %v", err)
+	}
+	if selection == "" {
+		// User cancelled
+		return "", nil
+	}
+	return a.ExportPDF(selection, options)
+}
+
+// ExportPDF writes a PDF of the current presentation to path, replacing any existing
+// file, and returns its absolute path. An empty path writes it next to the presentation.
+func (a *App) ExportPDF(path string, options PDFExportOptions) (string, error) {
+	current := a.presentationPath()
+	if current == "" {
+		return "", fmt.Errorf("no presentation loaded")
+	}
+	// The running turn may be writing the file
+	if !a.aiAgent.mu.TryLock() {
+		return "", fmt.Errorf("cannot export while a request is running")
+	}
+	defer a.aiAgent.mu.Unlock()
+
+	if path != "" {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to get absolute path: %v", err)
+		}
+		path = absPath
+	}
+	result, err := exportPresentationPDF(a.baseContext(), a, current, path, options, true)
+	if err != nil {
+		return "", err
+	}
+	fmt.Printf("Exported PDF to %s\n", result.OutputPath)
+	return result.OutputPath, nil
+}
+
+// ListConversations returns the saved AI conversations, most recent first
+func (a *App) ListConversations() ([]ConversationSummary, error) {
+	if a.aiAgent.conversations == nil {
+		return []ConversationSummary{}, nil
+	}
+	return a.aiAgent.conversations.List()
+}
+
+// DeleteConversation deletes the AI conversation of a presentation. An empty path means
+// the current presentation.
+func (a *App) DeleteConversation(presentationPath string) error {
+	if presentationPath == "" {
+		presentationPath = a.presentationPath()
+		if presentationPath == "" {
+			return fmt.Errorf("no presentation loaded")
+		}
+	}
+	absPath, err := filepath.Abs(presentationPath)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %v", err)
+	}
+	return a.aiAgent.DeleteConversation(absPath)
+}
+
+// LoadConversation resumes the saved AI conversation of a presentation, loading the
+// presentation if it isn't open. An empty path means the current presentation. The
+// returned messages are the chat history to display.
+func (a *App) LoadConversation(presentationPath string) ([]ConversationMessage, error) {
+	if presentationPath == "" {
+		presentationPath = a.presentationPath()
+		if presentationPath == "" {
+			return nil, fmt.Errorf("no presentation loaded")
+		}
+	}
+	absPath, err := filepath.Abs(presentationPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %v", err)
+	}
+	if absPath != a.presentationPath() {
+		if _, err := a.LoadPresentation(absPath); err != nil {
+			return nil, err
+		}
+		// A .ppt, .odp or .key file's conversation belongs to its working copy
+		absPath = a.presentationPath()
+	}
+	return a.aiAgent.LoadConversation(absPath)
+}
+
 // GetSlideImagePath returns the absolute path for a slide image
 func (a *App) GetSlideImagePath(slidePath string) (string, error) {
 	absPath, err := filepath.Abs(slidePath)
@@ -147,43 +760,42 @@ func (a *App) GetSlideImagePath(slidePath string) (string, error) {
 	return absPath, nil
 }
 
-// GetSlideImageAsBase64 reads a slide image and returns it as base64 data URI
+// GetSlideImageURL returns a streamed asset URL for a slide image. Prefer this over
+// GetSlideImageAsBase64: the image never passes through the JS bridge as a string.
+func (a *App) GetSlideImageURL(slidePath string) (string, error) {
+	return slideImageURL("slides", slidePath)
+}
+
+// GetSlideImageAsBase64 reads a slide image and returns it as base64 data URI.
+//
+// Deprecated: use GetSlideImageURL, which the app's own frontend uses; this stays for
+// frontends built against older bindings.
 func (a *App) GetSlideImageAsBase64(slidePath string) (string, error) {
-	// Check cache first
-	if cachedData, exists := a.imageCache[slidePath]; exists {
+	// Re-rendered images miss the cache, unchanged ones are served from it
+	if cachedData, exists := a.imageCache.Get(slidePath); exists {
 		return cachedData, nil
 	}
+	return a.loadSlideImage(slidePath)
+}
 
-	imageBytes, err := os.ReadFile(slidePath)
+// loadSlideImage encodes a slide image and caches it, keyed to the file's current version
+func (a *App) loadSlideImage(slidePath string) (string, error) {
+	info, err := os.Stat(slidePath)
 	if err != nil {
 		return "", fmt.Errorf("failed to read image file: %v", err)
 	}
-
-	// Determine the MIME type based on file extension
-	ext := filepath.Ext(slidePath)
-	var mimeType string
-	switch ext {
-	case ".jpg", ".jpeg":
-		mimeType = "image/jpeg"
-	case ".png":
-		mimeType = "image/png"
-	default:
-		mimeType = "image/jpeg" // default to jpeg
+	dataURI, err := encodeImageDataURI(slidePath)
+	if err != nil {
+		return "", err
 	}
-
-	// Convert to base64 data URI
-	base64Data := base64.StdEncoding.EncodeToString(imageBytes)
-	dataURI := fmt.Sprintf("data:%s;base64,%s", mimeType, base64Data)
-
-	// Cache the result
-	a.imageCache[slidePath] = dataURI
-
+	a.imageCache.Put(slidePath, dataURI, info)
 	return dataURI, nil
 }
 
-// ClearImageCache clears the image cache (useful when slides are updated)
+// ClearImageCache drops every cached image. Edits don't need it: changed images are
+// noticed by their modification time.
 func (a *App) ClearImageCache() {
-	a.imageCache = make(map[string]string)
+	a.imageCache.Clear()
 }
 
 // CheckSlideExists returns whether a slide file exists without logging large data
@@ -192,49 +804,59 @@ func (a *App) CheckSlideExists(slidePath string) bool {
 	return err == nil
 }
 
-// GetSlideImageQuiet loads and caches base64 data without logging it, returns simple status
+// GetSlideImageQuiet loads and caches base64 data without logging it, returns simple status.
+//
+// Deprecated: use GetSlideImageURL.
 func (a *App) GetSlideImageQuiet(slidePath string) (string, error) {
-	// Check cache first
-	if _, exists := a.imageCache[slidePath]; exists {
+	if _, exists := a.imageCache.Get(slidePath); exists {
 		return "CACHED_BASE64_DATA_AVAILABLE", nil
 	}
 
 	// Load image file directly (don't call GetSlideImageAsBase64 to avoid logging)
-	imageBytes, err := os.ReadFile(slidePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to read image file: %v", err)
-	}
-
-	// Determine MIME type
-	ext := filepath.Ext(slidePath)
-	var mimeType string
-	switch ext {
-	case ".jpg", ".jpeg":
-		mimeType = "image/jpeg"
-	case ".png":
-		mimeType = "image/png"
-	default:
-		mimeType = "image/jpeg"
+	if _, err := a.loadSlideImage(slidePath); err != nil {
+		return "", err
 	}
 
-	// Convert to base64 data URI and cache it
-	base64Data := base64.StdEncoding.EncodeToString(imageBytes)
-	dataURI := fmt.Sprintf("data:%s;base64,%s", mimeType, base64Data)
-	a.imageCache[slidePath] = dataURI
-
 	// Return simple status instead of the massive base64 string
 	return "BASE64_DATA_LOADED", nil
 }
 
 // GetCurrentPresentationName returns the name of currently loaded presentation
 func (a *App) GetCurrentPresentationName() string {
-	if a.currentPresentationPath == "" {
+	currentPath := a.presentationPath()
+	if currentPath == "" {
 		return ""
 	}
-	return filepath.Base(a.currentPresentationPath)
+	return filepath.Base(currentPath)
 }
 
 // HasPresentationLoaded returns whether a presentation is currently loaded
 func (a *App) HasPresentationLoaded() bool {
-	return a.currentPresentationPath != ""
+	return a.presentationPath() != ""
+}
+
+// presentationPath returns the loaded presentation, or "" if none is loaded or a is nil
+func (a *App) presentationPath() string {
+	if a == nil {
+		return ""
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.currentPresentationPath
+}
+
+// setPresentationPath records the loaded presentation
+func (a *App) setPresentationPath(path string) {
+	a.mu.Lock()
+	a.currentPresentationPath = path
+	a.mu.Unlock()
+	a.watcher.Watch(path)
+}
+
+// baseContext returns the Wails context, or a background context before startup
+func (a *App) baseContext() context.Context {
+	if a.ctx == nil {
+		return context.Background()
+	}
+	return a.ctx
 }
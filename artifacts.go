@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxToolResultCharsEnv overrides how long a tool result may be before it is stored as an artifact
+const maxToolResultCharsEnv = "SLIDEPILOT_MAX_TOOL_RESULT_CHARS"
+
+const (
+	defaultMaxToolResultChars = 20000
+	// artifactPreviewChars is how much of a stored result the model sees right away
+	artifactPreviewChars = 2000
+	// maxArtifactChunkChars caps one fetch_artifact call, keeping its own result under the limit
+	maxArtifactChunkChars = 15000
+)
+
+// artifactIDPattern matches the IDs handed out by ArtifactStore
+var artifactIDPattern = regexp.MustCompile(`^art_[0-9a-f]{16}$`)
+
+// ArtifactStore keeps the full text of tool results too large to put in the conversation.
+// Artifacts live in a temp directory for the life of the process; the janitor removes it.
+type ArtifactStore struct {
+	mu  sync.Mutex
+	dir string // Created on first use
+}
+
+// NewArtifactStore creates a store writing to dir, or to a new temp directory when dir is empty
+func NewArtifactStore(dir string) *ArtifactStore {
+	return &ArtifactStore{dir: dir}
+}
+
+// Put stores content and returns its ID. Identical results share an artifact.
+func (s *ArtifactStore) Put(content string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.dir == "" {
+		dir, err := os.MkdirTemp(sessionTempDir(), "artifacts-")
+		if err != nil {
+			return "", fmt.Errorf("failed to create artifacts directory: %v", err)
+		}
+		s.dir = dir
+	} else if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create artifacts directory: %v", err)
+	}
+
+	sum := sha256.Sum256([]byte(content))
+	id := "art_" + hex.EncodeToString(sum[:])[:16]
+	if err := os.WriteFile(filepath.Join(s.dir, id+".txt"), []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to store artifact: %v", err)
+	}
+	return id, nil
+}
+
+// Get returns the content of an artifact
+func (s *ArtifactStore) Get(id string) (string, error) {
+	if !artifactIDPattern.MatchString(id) {
+		return "", NewToolError(ErrCodeInvalidInput, "invalid artifact_id %q", id)
+	}
+	s.mu.Lock()
+	dir := s.dir
+	s.mu.Unlock()
+
+	data, err := os.ReadFile(filepath.Join(dir, id+".txt"))
+	if dir == "" || os.IsNotExist(err) {
+		return "", NewToolError(ErrCodeArtifactNotFound, "artifact %s doesn't exist; artifacts only last until the app closes, so run the tool that produced it again", id)
+	}
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to read artifact %s: %v", id, err)
+	}
+	return string(data), nil
+}
+
+// maxToolResultChars returns how long a tool result may be before it is stored as an artifact
+func maxToolResultChars() int {
+	if value := os.Getenv(maxToolResultCharsEnv); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > artifactPreviewChars {
+			return n
+		}
+		fmt.Printf("Warning: ignoring invalid %s=%q\n", maxToolResultCharsEnv, value)
+	}
+	return defaultMaxToolResultChars
+}
+
+// ArtifactReference replaces the data of a tool result that was too large for the conversation
+type ArtifactReference struct {
+	Truncated  bool                   `json:"truncated"`
+	ArtifactID string                 `json:"artifact_id"`
+	TotalChars int                    `json:"total_chars"`
+	Outline    map[string]interface{} `json:"outline,omitempty"` // Shape of the result's top-level fields
+	Preview    string                 `json:"preview"`
+	Hint       string                 `json:"hint"`
+}
+
+// limitToolResult stores a successful result longer than the limit as an artifact and
+// returns a reference to it with an outline and the start of the text. Shorter results,
+// and results that can't be stored, are returned as they are.
+func (a *AIAgent) limitToolResult(name, response string) string {
+	limit := maxToolResultChars()
+	if len(response) <= limit || name == FetchArtifactDefinition.Name || a.app.artifacts == nil {
+		return response
+	}
+	id, err := a.app.artifacts.Put(response)
+	if err != nil {
+		fmt.Printf("Warning: Failed to store the result of %s: %v\n", name, err)
+		return response
+	}
+	a.logToFile("ARTIFACT", fmt.Sprintf("Stored %d-character result of %s as %s", len(response), name, id), "")
+
+	var envelope ToolResult
+	json.Unmarshal([]byte(response), &envelope)
+	reference, _ := json.Marshal(ToolResult{Success: true, Data: ArtifactReference{
+		Truncated:  true,
+		ArtifactID: id,
+		TotalChars: len(response),
+		Outline:    outlineJSON(envelope.Data),
+		Preview:    truncateUTF8(response, artifactPreviewChars),
+		Hint: fmt.Sprintf("The result of %s was too long to include. Call fetch_artifact with artifact_id %q and an offset to read it in chunks, or narrow the call (e.g. fewer slides).",
+			name, id),
+	}})
+	return string(reference)
+}
+
+// outlineJSON describes the top-level fields of a decoded result: arrays by their length,
+// objects by their keys and scalars as they are
+func outlineJSON(data interface{}) map[string]interface{} {
+	fields, ok := data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	outline := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		switch value := value.(type) {
+		case []interface{}:
+			outline[key] = fmt.Sprintf("array of %d items", len(value))
+		case map[string]interface{}:
+			keys := make([]string, 0, len(value))
+			for k := range value {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			outline[key] = fmt.Sprintf("object with keys %v", keys)
+		case string:
+			outline[key] = truncateUTF8(value, 200)
+		default:
+			outline[key] = value
+		}
+	}
+	return outline
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a character
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// FetchArtifactDefinition defines the fetch_artifact tool
+var FetchArtifactDefinition = ToolDefinition{
+	Name: "fetch_artifact",
+	Description: `Read a stored tool result that was too long to include in full. Results over the size limit come back with truncated: true, an artifact_id, an outline and a preview; this tool returns the full text in chunks.
+
+Start at offset 0 and follow next_offset while has_more is true. Only fetch what you need: often the outline or a narrower call of the original tool is enough.`,
+	InputSchema: FetchArtifactInputSchema,
+	Function:    FetchArtifact,
+	ReadOnly:    true,
+}
+
+type FetchArtifactInput struct {
+	ArtifactID string `json:"artifact_id" jsonschema_description:"The artifact_id of a truncated tool result"`
+	Offset     int    `json:"offset,omitempty" jsonschema_description:"Character offset to start reading at (default 0)"`
+	Limit      int    `json:"limit,omitempty" jsonschema_description:"Maximum characters to return (default and maximum 15000)"`
+}
+
+var FetchArtifactInputSchema = GenerateSchema[FetchArtifactInput]()
+
+// ArtifactChunk is the fetch_artifact result
+type ArtifactChunk struct {
+	ArtifactID string `json:"artifact_id"`
+	Offset     int    `json:"offset"`
+	Returned   int    `json:"returned_chars"`
+	TotalChars int    `json:"total_chars"`
+	HasMore    bool   `json:"has_more"`
+	NextOffset *int   `json:"next_offset,omitempty"`
+	Content    string `json:"content"`
+}
+
+func FetchArtifact(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	fetchInput := FetchArtifactInput{}
+	if err := json.Unmarshal(input, &fetchInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+	if app.artifacts == nil {
+		return "", NewToolError(ErrCodeArtifactNotFound, "no artifacts are stored")
+	}
+	limit := fetchInput.Limit
+	if limit <= 0 || limit > maxArtifactChunkChars {
+		limit = maxArtifactChunkChars
+	}
+
+	content, err := app.artifacts.Get(fetchInput.ArtifactID)
+	if err != nil {
+		return "", err
+	}
+	if fetchInput.Offset < 0 || fetchInput.Offset > len(content) {
+		return "", NewToolError(ErrCodeInvalidInput, "offset %d is outside the artifact (0-%d)", fetchInput.Offset, len(content))
+	}
+
+	// Start and end on character boundaries
+	start := fetchInput.Offset
+	for start < len(content) && !utf8.RuneStart(content[start]) {
+		start++
+	}
+	chunk := truncateUTF8(content[start:], limit)
+	end := start + len(chunk)
+
+	result := ArtifactChunk{
+		ArtifactID: fetchInput.ArtifactID,
+		Offset:     start,
+		Returned:   len(chunk),
+		TotalChars: len(content),
+		HasMore:    end < len(content),
+		Content:    chunk,
+	}
+	if result.HasMore {
+		result.NextOffset = &end
+	}
+	return marshalResult(result)
+}
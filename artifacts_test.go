@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestOversizedToolResultsBecomeArtifacts(t *testing.T) {
+	t.Setenv(maxToolResultCharsEnv, "3000")
+	var probeInput []string
+	for i := 0; i < 200; i++ {
+		probeInput = append(probeInput, fmt.Sprintf(`"item number %d ééé"`, i))
+	}
+	big := `{"items": [` + strings.Join(probeInput, ",") + `], "count": 200}`
+
+	env := newTestEnv(t,
+		toolUseResponse("toolu_1", "probe_big", `{}`),
+		textResponse("Done."),
+	)
+	env.loadFixture(t, "two_slides.pptx")
+	env.app.artifacts = NewArtifactStore(t.TempDir())
+	env.app.aiAgent.tools.Register(ToolDefinition{
+		Name:        "probe_big",
+		InputSchema: GenerateSchema[struct{}](),
+		ReadOnly:    true,
+		Function: func(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+			return big, nil
+		},
+	})
+
+	if err := env.app.SendMessageToAI("Read it"); err != nil {
+		t.Fatal(err)
+	}
+	messages := env.llm.Requests[1].Messages
+	result := toolResultText(messages[len(messages)-1].Content[0])
+	if len(result) > 3000 {
+		t.Fatalf("expected the result to be cut below the limit, got %d characters", len(result))
+	}
+
+	var envelope struct {
+		Data ArtifactReference `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(result), &envelope); err != nil {
+		t.Fatal(err)
+	}
+	reference := envelope.Data
+	if !reference.Truncated || reference.ArtifactID == "" {
+		t.Fatalf("expected an artifact reference, got %s", result)
+	}
+	if reference.Outline["items"] != "array of 200 items" || reference.Outline["count"] != float64(200) {
+		t.Errorf("unexpected outline %v", reference.Outline)
+	}
+
+	// Reading the artifact in chunks gives back the full result
+	var full strings.Builder
+	offset := 0
+	for {
+		input, _ := json.Marshal(map[string]interface{}{"artifact_id": reference.ArtifactID, "offset": offset, "limit": 1000})
+		output, err := FetchArtifact(context.Background(), env.app, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var chunk ArtifactChunk
+		json.Unmarshal([]byte(output), &chunk)
+		full.WriteString(chunk.Content)
+		if !chunk.HasMore {
+			break
+		}
+		offset = *chunk.NextOffset
+	}
+	if full.Len() != reference.TotalChars || !strings.Contains(full.String(), "item number 199") {
+		t.Errorf("expected the full %d-character result back, got %d characters", reference.TotalChars, full.Len())
+	}
+}
+
+func TestFetchArtifactRejectsUnknownIDs(t *testing.T) {
+	env := newTestEnv(t)
+	for _, id := range []string{"art_0123456789abcdef", "../settings"} {
+		input, _ := json.Marshal(map[string]string{"artifact_id": id})
+		_, err := FetchArtifact(context.Background(), env.app, input)
+		if err == nil {
+			t.Errorf("expected an error for %q", id)
+		}
+	}
+	input, _ := json.Marshal(map[string]string{"artifact_id": "art_0123456789abcdef"})
+	if _, err := FetchArtifact(context.Background(), env.app, input); toolErrorCode(err) != ErrCodeArtifactNotFound {
+		t.Errorf("expected ARTIFACT_NOT_FOUND, got %v", err)
+	}
+}
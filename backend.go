@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// The interfaces below separate the tool layer and agent loop from LibreOffice,
+// the Anthropic API, and the Wails runtime so they can be swapped for in-memory
+// fakes in tests.
+
+// SlideConverter renders a presentation into slide images
+type SlideConverter interface {
+	ConvertToImages(ctx context.Context, pptxPath, outputDir string) ([]string, error)
+	// ConvertRange re-renders slides first..last (0-based, inclusive) and returns all previews in outputDir
+	ConvertRange(ctx context.Context, pptxPath, outputDir string, first, last int) ([]string, error)
+	// ExportImages renders slides first..last with custom options and returns all images of that format in outputDir
+	ExportImages(ctx context.Context, pptxPath, outputDir string, first, last int, options ExportOptions) ([]string, error)
+}
+
+// UnoBridge runs a UNO script by name and returns its combined output
+type UnoBridge interface {
+	Run(ctx context.Context, script string, args ...string) ([]byte, error)
+}
+
+// LLMClient sends a conversation to the language model
+type LLMClient interface {
+	CreateMessage(ctx context.Context, params anthropic.MessageNewParams) (*anthropic.Message, error)
+}
+
+// LLMStreamer is implemented by clients that can stream a response while it is generated.
+// onText receives each text delta with the index of the content block it belongs to.
+type LLMStreamer interface {
+	StreamMessage(ctx context.Context, params anthropic.MessageNewParams, onText func(block int, text string)) (*anthropic.Message, error)
+}
+
+// EventEmitter delivers events to the frontend
+type EventEmitter interface {
+	Emit(ctx context.Context, event string, data ...interface{})
+}
+
+// UnoConverter exports slide images through the running LibreOffice over a UNO bridge
+type UnoConverter struct {
+	Bridge UnoBridge
+}
+
+func (c UnoConverter) ConvertToImages(ctx context.Context, pptxPath, outputDir string) ([]string, error) {
+	return ConvertPPTXToJPEG(ctx, c.Bridge, pptxPath, outputDir)
+}
+
+func (c UnoConverter) ConvertRange(ctx context.Context, pptxPath, outputDir string, first, last int) ([]string, error) {
+	return ConvertSlideRangeToJPEG(ctx, c.Bridge, pptxPath, outputDir, first, last)
+}
+
+func (c UnoConverter) ExportImages(ctx context.Context, pptxPath, outputDir string, first, last int, options ExportOptions) ([]string, error) {
+	return ExportSlideImages(ctx, c.Bridge, pptxPath, outputDir, first, last, options)
+}
+
+// PythonUnoBridge runs UNO scripts with a fresh python3 process per call
+type PythonUnoBridge struct {
+	ScriptsDir string
+}
+
+func (b PythonUnoBridge) Run(ctx context.Context, script string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "python3", append([]string{filepath.Join(b.ScriptsDir, script)}, args...)...)
+	return cmd.CombinedOutput()
+}
+
+// AnthropicClient sends requests through the Anthropic SDK
+type AnthropicClient struct {
+	client *anthropic.Client
+	model  string // Used when the request names no model
+}
+
+// NewAnthropicClient creates a client using ANTHROPIC_API_KEY from the environment. The
+// SDK's own retries are off; the agent retries transient failures itself and reports them.
+func NewAnthropicClient() *AnthropicClient {
+	client := anthropic.NewClient(option.WithMaxRetries(0))
+	return &AnthropicClient{client: &client}
+}
+
+func (c *AnthropicClient) CreateMessage(ctx context.Context, params anthropic.MessageNewParams) (*anthropic.Message, error) {
+	if params.Model == "" {
+		params.Model = anthropic.Model(c.model)
+	}
+	return c.client.Messages.New(ctx, params)
+}
+
+func (c *AnthropicClient) StreamMessage(ctx context.Context, params anthropic.MessageNewParams, onText func(block int, text string)) (*anthropic.Message, error) {
+	if params.Model == "" {
+		params.Model = anthropic.Model(c.model)
+	}
+	stream := c.client.Messages.NewStreaming(ctx, params)
+	defer stream.Close()
+
+	message := anthropic.Message{}
+	for stream.Next() {
+		event := stream.Current()
+		if err := message.Accumulate(event); err != nil {
+			return nil, err
+		}
+		if event.Type == "content_block_delta" && event.Delta.Type == "text_delta" && event.Delta.Text != "" {
+			onText(int(event.Index), event.Delta.Text)
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// WailsEmitter emits events through the Wails runtime
+type WailsEmitter struct{}
+
+func (WailsEmitter) Emit(ctx context.Context, event string, data ...interface{}) {
+	runtime.EventsEmit(ctx, event, data...)
+}
+
+// NoopEmitter drops events, for running without a window
+type NoopEmitter struct{}
+
+func (NoopEmitter) Emit(ctx context.Context, event string, data ...interface{}) {}
+
+// unoBridge returns the app's UNO bridge, defaulting to python3
+func unoBridge(app *App) UnoBridge {
+	if app != nil && app.uno != nil {
+		return app.uno
+	}
+	return PythonUnoBridge{ScriptsDir: unoScriptsDir()}
+}
+
+// runUnoScript runs a UNO script through the app's bridge. While the supervisor is
+// restarting LibreOffice the call waits for it instead of failing to connect.
+func runUnoScript(ctx context.Context, app *App, script string, args ...string) ([]byte, error) {
+	defer profiler.Start("uno", script).End()
+
+	if app != nil && app.libreOffice != nil {
+		if err := app.libreOffice.WaitReady(ctx, libreOfficeStartTimeout); err != nil {
+			output, _ := json.Marshal(map[string]interface{}{
+				"success": false,
+				"error":   fmt.Sprintf("Could not connect to LibreOffice. %v", err),
+			})
+			return output, err
+		}
+	}
+	return unoBridge(app).Run(ctx, script, args...)
+}
+
+// convertSlides renders slide images through the app's converter, defaulting to a UNO export
+func convertSlides(ctx context.Context, app *App, pptxPath, outputDir string) ([]string, error) {
+	defer profiler.Start("export", "convert_slides").End()
+
+	ctx = app.withConversionProgress(ctx, pptxPath)
+	if app != nil && app.converter != nil {
+		return app.converter.ConvertToImages(ctx, pptxPath, outputDir)
+	}
+	return ConvertPPTXToJPEG(ctx, unoBridge(app), pptxPath, outputDir)
+}
+
+// convertSlideRange re-renders a range of slide images through the app's converter
+func convertSlideRange(ctx context.Context, app *App, pptxPath, outputDir string, first, last int) ([]string, error) {
+	defer profiler.Start("export", "convert_slide_range").End()
+
+	ctx = app.withConversionProgress(ctx, pptxPath)
+	if app != nil && app.converter != nil {
+		return app.converter.ConvertRange(ctx, pptxPath, outputDir, first, last)
+	}
+	return ConvertSlideRangeToJPEG(ctx, unoBridge(app), pptxPath, outputDir, first, last)
+}
+
+// exportSlideImagesWith renders slide images with custom options through the app's converter
+func exportSlideImagesWith(ctx context.Context, app *App, pptxPath, outputDir string, first, last int, options ExportOptions) ([]string, error) {
+	defer profiler.Start("export", "export_slide_images").End()
+
+	ctx = app.withConversionProgress(ctx, pptxPath)
+	if app != nil && app.converter != nil {
+		return app.converter.ExportImages(ctx, pptxPath, outputDir, first, last, options)
+	}
+	return ExportSlideImages(ctx, unoBridge(app), pptxPath, outputDir, first, last, options)
+}
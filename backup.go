@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// PresentationBackup is a temporary copy of a presentation taken before a mutating tool runs
+type PresentationBackup struct {
+	OriginalPath string
+	BackupPath   string
+}
+
+// BackupPresentation copies the presentation into a temporary file so it can be restored
+func BackupPresentation(presentationPath string) (*PresentationBackup, error) {
+	info, err := os.Stat(presentationPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot back up presentation: %v", err)
+	}
+	if err := checkDiskSpace(os.TempDir(), info.Size()); err != nil {
+		return nil, err
+	}
+
+	backupFile, err := os.CreateTemp(sessionTempDir(), "backup-*"+filepath.Ext(presentationPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup file: %v", err)
+	}
+	backupFile.Close()
+
+	if err := copyFile(presentationPath, backupFile.Name()); err != nil {
+		os.Remove(backupFile.Name())
+		return nil, fmt.Errorf("failed to back up presentation: %v", err)
+	}
+
+	return &PresentationBackup{
+		OriginalPath: presentationPath,
+		BackupPath:   backupFile.Name(),
+	}, nil
+}
+
+// Restore copies the backup over the original presentation
+func (b *PresentationBackup) Restore() error {
+	if err := copyFile(b.BackupPath, b.OriginalPath); err != nil {
+		return fmt.Errorf("failed to restore presentation from backup: %v", err)
+	}
+	return nil
+}
+
+// Discard removes the temporary backup file
+func (b *PresentationBackup) Discard() {
+	os.Remove(b.BackupPath)
+}
+
+// resultReportsFailure returns true when a tool's JSON result contains "success": false
+func resultReportsFailure(result string) bool {
+	var parsed struct {
+		Success *bool `json:"success"`
+	}
+	if err := json.Unmarshal([]byte(result), &parsed); err != nil {
+		return false
+	}
+	return parsed.Success != nil && !*parsed.Success
+}
+
+// toolTargetPath returns the presentation a tool call operates on, falling back to the loaded one
+func toolTargetPath(app *App, input json.RawMessage) string {
+	var target struct {
+		PresentationPath string `json:"presentation_path"`
+	}
+	if err := json.Unmarshal(input, &target); err == nil && target.PresentationPath != "" {
+		return target.PresentationPath
+	}
+	return app.presentationPath()
+}
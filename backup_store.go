@@ -0,0 +1,187 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Environment variables configuring the backups of original presentations
+const (
+	backupDirEnv    = "SLIDEPILOT_BACKUP_DIR"     // Where backups are kept
+	backupKeepEnv   = "SLIDEPILOT_BACKUP_KEEP"    // Backups kept per presentation; 0 turns backups off
+	backupMaxAgeEnv = "SLIDEPILOT_BACKUP_MAX_AGE" // Go duration after which backups are deleted
+)
+
+const (
+	defaultBackupKeep   = 10
+	defaultBackupMaxAge = 30 * 24 * time.Hour
+)
+
+// backupTimeFormat names backup files so they sort by creation time
+const backupTimeFormat = "20060102-150405.000"
+
+// BackupStore keeps timestamped copies of presentations as they were before the agent
+// first changed them in this session. Unlike the undo history these are never rewritten,
+// so the original survives even if the history is lost or undone past.
+type BackupStore struct {
+	mu     sync.Mutex
+	dir    string
+	keep   int
+	maxAge time.Duration
+	taken  map[string]bool // Presentations already backed up this session
+}
+
+// BackupInfo describes one backup for the frontend
+type BackupInfo struct {
+	Path      string    `json:"path"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	Size      int64     `json:"size"`
+}
+
+// NewBackupStore creates a store keeping up to keep backups per presentation in dir,
+// none older than maxAge
+func NewBackupStore(dir string, keep int, maxAge time.Duration) *BackupStore {
+	return &BackupStore{dir: dir, keep: keep, maxAge: maxAge, taken: make(map[string]bool)}
+}
+
+// backupStoreFromEnv creates the store configured by SLIDEPILOT_BACKUP_DIR, _KEEP and _MAX_AGE
+func backupStoreFromEnv() *BackupStore {
+	dir := os.Getenv(backupDirEnv)
+	if dir == "" {
+		if configDir, err := os.UserConfigDir(); err == nil {
+			dir = filepath.Join(configDir, "slidepilot", "backups")
+		} else {
+			dir = "backups"
+		}
+	}
+	keep := defaultBackupKeep
+	if value := os.Getenv(backupKeepEnv); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+			keep = n
+		} else {
+			fmt.Printf("Warning: ignoring invalid %s=%q\n", backupKeepEnv, value)
+		}
+	}
+	maxAge := defaultBackupMaxAge
+	if d, ok := durationFromEnv(backupMaxAgeEnv); ok {
+		maxAge = d
+	}
+	return NewBackupStore(dir, keep, maxAge)
+}
+
+// absPresentationPath makes a presentation path absolute and clean, so a relative path
+// from a tool input finds the same backups as the absolute one the app uses
+func absPresentationPath(presentationPath string) string {
+	if abs, err := filepath.Abs(presentationPath); err == nil {
+		return abs
+	}
+	return filepath.Clean(presentationPath)
+}
+
+// presentationDir returns the directory holding a presentation's backups
+func (s *BackupStore) presentationDir(presentationPath string) string {
+	presentationPath = absPresentationPath(presentationPath)
+	sum := sha256.Sum256([]byte(presentationPath))
+	name := strings.TrimSuffix(filepath.Base(presentationPath), filepath.Ext(presentationPath))
+	return filepath.Join(s.dir, name+"-"+hex.EncodeToString(sum[:])[:8])
+}
+
+// BackupOnce copies the presentation into the store unless it was already backed up this
+// session. It returns the new backup's path, or "" when none was needed.
+func (s *BackupStore) BackupOnce(presentationPath string) (string, error) {
+	presentationPath = absPresentationPath(presentationPath)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keep == 0 || s.taken[presentationPath] {
+		return "", nil
+	}
+
+	info, err := os.Stat(presentationPath)
+	if err != nil {
+		return "", fmt.Errorf("cannot back up presentation: %v", err)
+	}
+	dir := s.presentationDir(presentationPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %v", err)
+	}
+	if err := checkDiskSpace(dir, info.Size()); err != nil {
+		return "", err
+	}
+
+	backupPath := filepath.Join(dir, time.Now().Format(backupTimeFormat)+filepath.Ext(presentationPath))
+	if err := copyFile(presentationPath, backupPath); err != nil {
+		os.Remove(backupPath)
+		return "", fmt.Errorf("failed to back up presentation: %v", err)
+	}
+	s.taken[presentationPath] = true
+	s.prune(dir)
+	return backupPath, nil
+}
+
+// List returns a presentation's backups, newest first
+func (s *BackupStore) List(presentationPath string) ([]BackupInfo, error) {
+	dir := s.presentationDir(presentationPath)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []BackupInfo{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backups: %v", err)
+	}
+
+	backups := []BackupInfo{}
+	for _, entry := range entries {
+		name := entry.Name()
+		created, err := time.ParseInLocation(backupTimeFormat, strings.TrimSuffix(name, filepath.Ext(name)), time.Local)
+		if entry.IsDir() || err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, BackupInfo{
+			Path:      filepath.Join(dir, name),
+			Name:      name,
+			CreatedAt: created,
+			Size:      info.Size(),
+		})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+	return backups, nil
+}
+
+// Owns reports whether backupPath is one of the presentation's backups
+func (s *BackupStore) Owns(presentationPath, backupPath string) bool {
+	return filepath.Dir(filepath.Clean(backupPath)) == s.presentationDir(presentationPath)
+}
+
+// prune deletes backups beyond the retention limit and those older than maxAge; the
+// caller holds the lock
+func (s *BackupStore) prune(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	// Names are timestamps, so ReadDir's sorted order is oldest first
+	cutoff := time.Now().Add(-s.maxAge).Format(backupTimeFormat)
+	for i, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if i < len(entries)-s.keep || entry.Name() < cutoff {
+			os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+}
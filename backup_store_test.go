@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFirstEditBacksUpOriginalAndRestores(t *testing.T) {
+	t.Setenv(visionFeedbackEnv, "0")
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.app.backups = NewBackupStore(filepath.Join(env.dir, "backups"), 5, time.Hour)
+	appendingEdit(env)
+
+	original, _ := os.ReadFile(path)
+	env.app.aiAgent.executeTool(context.Background(), "toolu_1", "edit_slide_text", editSlideInput("one"))
+	env.app.aiAgent.executeTool(context.Background(), "toolu_2", "edit_slide_text", editSlideInput("two"))
+	edited, _ := os.ReadFile(path)
+
+	backups, err := env.app.ListBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("expected one backup for the session, got %d", len(backups))
+	}
+	if data, _ := os.ReadFile(backups[0].Path); !bytes.Equal(data, original) {
+		t.Error("backup does not hold the original presentation")
+	}
+
+	if _, err := env.app.RestoreBackup(filepath.Join(env.dir, "two_slides.pptx")); err == nil {
+		t.Error("expected a file outside the backup directory to be refused")
+	}
+	if _, err := env.app.RestoreBackup(backups[0].Path); err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); !bytes.Equal(data, original) {
+		t.Error("restore did not bring back the original")
+	}
+
+	// The restore itself can be undone
+	if _, err := env.app.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); !bytes.Equal(data, edited) {
+		t.Error("undoing the restore did not bring back the edits")
+	}
+}
+
+func TestBackupStorePrunesOldBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "deck.pptx")
+	os.WriteFile(path, []byte("deck"), 0644)
+	store := NewBackupStore(filepath.Join(dir, "backups"), 2, time.Hour)
+
+	backupDir := store.presentationDir(path)
+	os.MkdirAll(backupDir, 0755)
+	stale := time.Now().Add(-2 * time.Hour).Format(backupTimeFormat)
+	recent := time.Now().Add(-time.Minute)
+	for _, name := range []string{stale, recent.Add(-time.Second).Format(backupTimeFormat), recent.Format(backupTimeFormat)} {
+		os.WriteFile(filepath.Join(backupDir, name+".pptx"), []byte("old"), 0644)
+	}
+
+	created, err := store.BackupOnce(path)
+	if err != nil || created == "" {
+		t.Fatalf("expected a backup, got %q, %v", created, err)
+	}
+	if again, _ := store.BackupOnce(path); again != "" {
+		t.Errorf("expected one backup per session, got another at %s", again)
+	}
+
+	backups, _ := store.List(path)
+	if len(backups) != 2 || backups[0].Path != created || backups[1].Name != recent.Format(backupTimeFormat)+".pptx" {
+		t.Errorf("expected the newest two backups to be kept, got %+v", backups)
+	}
+}
+
+func TestBackupStoreNormalizesPaths(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.app.backups = NewBackupStore(filepath.Join(env.dir, "backups"), 5, time.Hour)
+
+	// Tools may name the deck by a relative path; the backup must still be listed under
+	// the absolute path the app uses, and taken only once
+	wd, _ := os.Getwd()
+	relative, err := filepath.Rel(wd, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created, err := env.app.backups.BackupOnce("./" + relative); err != nil || created == "" {
+		t.Fatalf("expected a backup, got %q, %v", created, err)
+	}
+	if again, _ := env.app.backups.BackupOnce(path); again != "" {
+		t.Errorf("expected the absolute path to share the session's backup, got another at %s", again)
+	}
+	backups, err := env.app.ListBackups()
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("expected one backup listed for the presentation, got %+v, %v", backups, err)
+	}
+	if _, err := env.app.RestoreBackup(backups[0].Path); err != nil {
+		t.Errorf("RestoreBackup failed: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPresentationBackupRestores(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deck.pptx")
+	os.WriteFile(path, []byte("original"), 0644)
+
+	backup, err := BackupPresentation(path)
+	if err != nil {
+		t.Fatalf("BackupPresentation failed: %v", err)
+	}
+	defer backup.Discard()
+	if filepath.Ext(backup.BackupPath) != ".pptx" {
+		t.Errorf("expected the backup to keep the extension, got %s", backup.BackupPath)
+	}
+
+	os.WriteFile(path, []byte("broken by a tool"), 0644)
+	if err := backup.Restore(); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "original" {
+		t.Errorf("expected the original content restored, got %q", data)
+	}
+
+	backup.Discard()
+	if _, err := os.Stat(backup.BackupPath); !os.IsNotExist(err) {
+		t.Error("expected Discard to remove the backup file")
+	}
+	if _, err := BackupPresentation(filepath.Join(t.TempDir(), "missing.pptx")); err == nil {
+		t.Error("expected an error backing up a missing file")
+	}
+}
+
+func TestResultReportsFailure(t *testing.T) {
+	for result, want := range map[string]bool{
+		`{"success": false, "error": "Invalid slide number"}`: true,
+		`{"success": true}`: false,
+		`{"slides": []}`:    false,
+		`not json`:          false,
+	} {
+		if got := resultReportsFailure(result); got != want {
+			t.Errorf("resultReportsFailure(%s) = %v, expected %v", result, got, want)
+		}
+	}
+}
+
+func TestToolTargetPath(t *testing.T) {
+	app := &App{currentPresentationPath: "loaded.pptx"}
+	if got := toolTargetPath(app, []byte(`{"presentation_path": "other.pptx"}`)); got != "other.pptx" {
+		t.Errorf("expected the path from the input, got %q", got)
+	}
+	if got := toolTargetPath(app, []byte(`{"slide_number": 1}`)); got != "loaded.pptx" {
+		t.Errorf("expected the loaded presentation, got %q", got)
+	}
+}
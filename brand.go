@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+// brandProfile returns the user's brand profile with its palette as #RRGGBB, or nil when
+// none is set up
+func (a *App) brandProfile() *BrandProfile {
+	brand := a.aiAgent.Settings().Brand
+	if brand == nil {
+		return nil
+	}
+	profile := *brand
+	profile.Palette = make([]string, len(brand.Palette))
+	for i, color := range brand.Palette {
+		profile.Palette[i] = "#" + strings.ToUpper(strings.TrimPrefix(color, "#"))
+	}
+	return &profile
+}
+
+// GetBrandProfileDefinition defines the get_brand_profile tool
+var GetBrandProfileDefinition = ToolDefinition{
+	Name: "get_brand_profile",
+	Description: `Get the user's brand profile from the settings: the heading and body fonts, the color palette (primary color first), the logo image and the footer text their decks use.
+
+add_slide applies the fonts and footer to new slides by itself, and lint_style checks against the fonts and palette when none are given. Use the profile for everything else: pick text and shape colors from the palette in format_text edits, and place the logo with insert_image when the user asks for it. configured is false when no profile is set up; fall back to the deck's own style then.`,
+	InputSchema: GetBrandProfileInputSchema,
+	Function:    GetBrandProfile,
+	ReadOnly:    true,
+}
+
+type GetBrandProfileInput struct{}
+
+var GetBrandProfileInputSchema = GenerateSchema[GetBrandProfileInput]()
+
+func GetBrandProfile(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	brand := app.brandProfile()
+	if brand == nil {
+		return marshalResult(map[string]interface{}{
+			"configured": false,
+			"message":    "No brand profile is set up; follow the deck's own fonts and colors",
+		})
+	}
+
+	result := map[string]interface{}{
+		"configured": true,
+		"profile":    brand,
+	}
+	// The logo may have been moved since it was chosen
+	if brand.LogoPath != "" {
+		_, err := os.Stat(brand.LogoPath)
+		result["logo_found"] = err == nil
+	}
+	return marshalResult(result)
+}
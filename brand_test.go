@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestBrandProfileValidate(t *testing.T) {
+	for _, brand := range []BrandProfile{
+		{Palette: []string{"#0B3D91", "blue"}},
+		{LogoPath: "logo.png"},
+		{Palette: make([]string, maxPaletteColors+1)},
+	} {
+		settings := DefaultSettings()
+		settings.Brand = &brand
+		if err := settings.Validate(); err == nil {
+			t.Errorf("expected %+v to be rejected", brand)
+		}
+	}
+
+	settings := DefaultSettings()
+	settings.Brand = &BrandProfile{HeadingFont: "Montserrat", Palette: []string{"#0B3D91", "fc3d21"}, LogoPath: "/brand/logo.png"}
+	if err := settings.Validate(); err != nil {
+		t.Errorf("expected a valid profile, got %v", err)
+	}
+}
+
+func TestGetBrandProfileTool(t *testing.T) {
+	env := newTestEnv(t)
+
+	output, err := GetBrandProfile(context.Background(), env.app, json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("GetBrandProfile failed: %v", err)
+	}
+	var result struct {
+		Configured bool          `json:"configured"`
+		Profile    *BrandProfile `json:"profile"`
+		LogoFound  *bool         `json:"logo_found"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil || result.Configured {
+		t.Fatalf("expected no profile, got %s (%v)", output, err)
+	}
+
+	settings := env.app.GetSettings()
+	settings.Brand = &BrandProfile{BodyFont: "Open Sans", Palette: []string{"0b3d91"}, LogoPath: "/missing/logo.png"}
+	env.app.aiAgent.SetSettings(settings)
+
+	output, err = GetBrandProfile(context.Background(), env.app, json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("GetBrandProfile failed: %v", err)
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatal(err)
+	}
+	if !result.Configured || result.Profile.BodyFont != "Open Sans" || result.Profile.Palette[0] != "#0B3D91" {
+		t.Errorf("expected the profile with a normalized palette, got %s", output)
+	}
+	if result.LogoFound == nil || *result.LogoFound {
+		t.Errorf("expected the missing logo to be reported, got %s", output)
+	}
+}
+
+func TestBrandProfileDefaults(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	settings := env.app.GetSettings()
+	settings.Brand = &BrandProfile{HeadingFont: "Montserrat", BodyFont: "Open Sans", Palette: []string{"#0B3D91"}, FooterText: "Acme"}
+	env.app.aiAgent.SetSettings(settings)
+
+	env.uno.Respond("uno_add_slide.py", `{"success": true, "new_slide_number": 3, "total_slides": 3}`)
+	if _, err := AddSlide(context.Background(), env.app, json.RawMessage(`{}`)); err != nil {
+		t.Fatalf("AddSlide failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_add_slide.py")
+	if len(calls) != 1 || len(calls[0].Args) != 5 || calls[0].Args[3] != "" {
+		t.Fatalf("expected an empty title before the brand argument, got %+v", calls)
+	}
+	var brand map[string]string
+	if err := json.Unmarshal([]byte(calls[0].Args[4]), &brand); err != nil {
+		t.Fatal(err)
+	}
+	if brand["heading_font"] != "Montserrat" || brand["body_font"] != "Open Sans" || brand["footer_text"] != "Acme" {
+		t.Errorf("expected the brand fonts and footer, got %v", brand)
+	}
+
+	// lint_style checks against the brand when no fonts or palette are given
+	env.uno.Respond("uno_lint_style.py", `{"success": true, "slides": [{"slide_number": 1, "shapes": [
+		{"shape_index": 0, "shape_id": "Title 1", "role": "title", "x": 0.5, "y": 0.5, "text": "Hello", "fonts": {"Arial": 5},
+		 "text_colors": {"#FF00FF": 5}}]}]}`)
+	output, err := LintStyle(context.Background(), env.app, json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("LintStyle failed: %v", err)
+	}
+	var result struct {
+		Summary map[string]int `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Summary["font"] != 1 || result.Summary["color"] != 1 {
+		t.Errorf("expected a font and a color issue, got %s", output)
+	}
+}
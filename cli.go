@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// cliUsage is printed for "slidepilot help" and invalid command lines
+const cliUsage = `Usage:
+  slidepilot run --pptx deck.pptx --prompt "..."      Run the agent on a presentation
+  slidepilot run --pptx deck.pptx --prompt-file -     Read the prompt from a file or stdin
+  slidepilot tool <name> [--pptx deck.pptx] --json '{...}'
+                                                      Run a single tool; --json - reads stdin
+  slidepilot tool                                     List the available tools
+
+Without a command the desktop app starts. Edits are saved to the presentation in place.`
+
+// cliOptions is a parsed CLI command line
+type cliOptions struct {
+	Command      string // "run", "tool" or "help"
+	Presentation string
+	Prompt       string
+	PromptFile   string
+	Tool         string // Empty lists the tools
+	Input        string
+}
+
+// cliCommand reports whether the command line asks for a CLI subcommand rather than the window
+func cliCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case "run", "tool", "help":
+		return true
+	}
+	return false
+}
+
+// parseCLIArgs parses the arguments of a CLI subcommand, args[0] being the command
+func parseCLIArgs(args []string) (cliOptions, error) {
+	options := cliOptions{Command: args[0]}
+	args = args[1:]
+	if options.Command == "help" {
+		return options, nil
+	}
+
+	flags := flag.NewFlagSet(options.Command, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&options.Presentation, "pptx", "", "presentation to work on")
+	flags.Bool("profile", false, "record timings") // Handled in main
+	switch options.Command {
+	case "run":
+		flags.StringVar(&options.Prompt, "prompt", "", "what the agent should do")
+		flags.StringVar(&options.PromptFile, "prompt-file", "", "file holding the prompt, - for stdin")
+	case "tool":
+		if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+			options.Tool = args[0]
+			args = args[1:]
+		}
+		flags.StringVar(&options.Input, "json", "{}", "tool input, - for stdin")
+	}
+	if err := flags.Parse(args); err != nil {
+		return options, err
+	}
+	if flags.NArg() > 0 {
+		return options, fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	if options.Command == "run" {
+		if options.Presentation == "" {
+			return options, fmt.Errorf("--pptx is required")
+		}
+		if (options.Prompt == "") == (options.PromptFile == "") {
+			return options, fmt.Errorf("give either --prompt or --prompt-file")
+		}
+	}
+	return options, nil
+}
+
+// runCLI runs a CLI subcommand against a headless app and returns the process exit code
+func runCLI(args []string) int {
+	options, err := parseCLIArgs(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "slidepilot %s: %v\n\n%s\n", args[0], err, cliUsage)
+		return 2
+	}
+	if options.Command == "help" {
+		fmt.Println(cliUsage)
+		return 0
+	}
+
+	// stdout carries the assistant's replies and tool results, so logs go to stderr
+	out := os.Stdout
+	os.Stdout = os.Stderr
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	emitter := &ConsoleEmitter{out: out}
+	app := NewHeadlessApp(emitter)
+	// Listing the tools needs no LibreOffice
+	if options.Command != "tool" || options.Tool != "" {
+		app.startup(ctx)
+		defer app.shutdown(context.Background())
+	}
+
+	err = runCLICommand(ctx, app, options, os.Stdin, out)
+	emitter.Finish()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "slidepilot %s: %v\n", options.Command, err)
+		return 1
+	}
+	return 0
+}
+
+// runCLICommand runs a parsed CLI command, reading prompts or tool input given as "-" from stdin
+func runCLICommand(ctx context.Context, app *App, options cliOptions, stdin io.Reader, out io.Writer) error {
+	if options.Presentation != "" {
+		path, err := filepath.Abs(options.Presentation)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("presentation file not found: %s", path)
+		}
+		// Previews aren't rendered up front; tools work on the file itself
+		app.setPresentationPath(path)
+	}
+
+	switch options.Command {
+	case "run":
+		prompt := options.Prompt
+		if options.PromptFile != "" {
+			var data []byte
+			var err error
+			if options.PromptFile == "-" {
+				data, err = io.ReadAll(stdin)
+			} else {
+				data, err = os.ReadFile(options.PromptFile)
+			}
+			if err != nil {
+				return err
+			}
+			prompt = string(data)
+		}
+		return app.aiAgent.SendMessage(ctx, strings.TrimSpace(prompt))
+
+	case "tool":
+		if options.Tool == "" {
+			for _, tool := range app.aiAgent.tools.List() {
+				fmt.Fprintf(out, "%-28s %s\n", tool.Name, firstSentence(tool.Description))
+			}
+			return nil
+		}
+		if _, found := app.aiAgent.tools.Lookup(options.Tool); !found {
+			return fmt.Errorf("unknown tool %s; run \"slidepilot tool\" to list the tools", options.Tool)
+		}
+		input := []byte(options.Input)
+		if options.Input == "-" {
+			var err error
+			if input, err = io.ReadAll(stdin); err != nil {
+				return err
+			}
+		}
+		if !json.Valid(input) {
+			return fmt.Errorf("--json is not valid JSON")
+		}
+
+		block := app.aiAgent.RunTool(ctx, "cli_1", options.Tool, json.RawMessage(input))
+		result := block.OfToolResult
+		for _, part := range result.Content {
+			if part.OfText != nil {
+				fmt.Fprintln(out, part.OfText.Text)
+			}
+		}
+		if result.IsError.Value {
+			return fmt.Errorf("tool %s failed", options.Tool)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown command %s", options.Command)
+}
+
+// firstSentence shortens a tool description for the tool listing
+func firstSentence(text string) string {
+	if index := strings.Index(text, ". "); index >= 0 {
+		return text[:index+1]
+	}
+	return text
+}
+
+// ConsoleEmitter prints the assistant's messages for CLI runs; other events are dropped
+type ConsoleEmitter struct {
+	mu        sync.Mutex
+	out       io.Writer
+	streaming bool // Streamed text was printed without its closing newline
+}
+
+func (e *ConsoleEmitter) Emit(ctx context.Context, event string, data ...interface{}) {
+	if len(data) == 0 {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	switch event {
+	case "ai-message-delta":
+		if delta, ok := data[0].(MessageDelta); ok {
+			fmt.Fprint(e.out, delta.Text)
+			e.streaming = true
+		}
+	case "ai-message":
+		if message, ok := data[0].(string); ok {
+			e.endStream()
+			fmt.Fprintln(e.out, message)
+		}
+	}
+}
+
+// Finish ends a streamed message that is still open
+func (e *ConsoleEmitter) Finish() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.endStream()
+}
+
+// endStream terminates streamed text with a newline; the caller holds the lock
+func (e *ConsoleEmitter) endStream() {
+	if e.streaming {
+		fmt.Fprintln(e.out)
+		e.streaming = false
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseCLIArgs(t *testing.T) {
+	options, err := parseCLIArgs([]string{"tool", "edit_slide_text", "--pptx", "deck.pptx", "--json", `{"slide_number": 1}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if options.Tool != "edit_slide_text" || options.Presentation != "deck.pptx" || options.Input != `{"slide_number": 1}` {
+		t.Errorf("unexpected options %+v", options)
+	}
+
+	for _, args := range [][]string{
+		{"run", "--prompt", "Fix typos"},
+		{"run", "--pptx", "deck.pptx"},
+		{"run", "--pptx", "deck.pptx", "--prompt", "a", "--prompt-file", "b"},
+		{"tool", "list_slides", "extra"},
+	} {
+		if _, err := parseCLIArgs(args); err == nil {
+			t.Errorf("expected %v to be rejected", args)
+		}
+	}
+}
+
+func TestRunCLICommandRunsToolAndAgent(t *testing.T) {
+	env := newTestEnv(t, textResponse("The deck has two slides."))
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.app.setPresentationPath("")
+
+	var out bytes.Buffer
+	err := runCLICommand(context.Background(), env.app, cliOptions{Command: "tool", Tool: "list_slides", Presentation: path, Input: "{}"}, nil, &out)
+	if err != nil {
+		t.Fatalf("tool command failed: %v", err)
+	}
+	if !strings.Contains(out.String(), `"total_slides":2`) || env.app.presentationPath() != path {
+		t.Errorf("unexpected tool output %s", out.String())
+	}
+
+	out.Reset()
+	err = runCLICommand(context.Background(), env.app, cliOptions{Command: "tool", Tool: "read_slide", Input: "-"}, strings.NewReader(`{"slide_number": 9}`), &out)
+	if err == nil || !strings.Contains(out.String(), string(ErrCodeSlideOutOfRange)) {
+		t.Errorf("expected the tool error to be printed and reported, got %v: %s", err, out.String())
+	}
+
+	err = runCLICommand(context.Background(), env.app, cliOptions{Command: "run", Prompt: "How many slides?"}, nil, &out)
+	if err != nil {
+		t.Fatalf("run command failed: %v", err)
+	}
+	if len(env.llm.Requests) != 1 {
+		t.Errorf("expected one inference request, got %d", len(env.llm.Requests))
+	}
+}
+
+func TestConsoleEmitterPrintsMessages(t *testing.T) {
+	var out bytes.Buffer
+	emitter := &ConsoleEmitter{out: &out}
+	emitter.Emit(context.Background(), "ai-message-delta", MessageDelta{ID: "1", Text: "Two "})
+	emitter.Emit(context.Background(), "ai-message-delta", MessageDelta{ID: "1", Text: "slides."})
+	emitter.Emit(context.Background(), "ai-message", "📋 Listing slides")
+	emitter.Emit(context.Background(), "libreoffice-status", LibreOfficeStatus{})
+	emitter.Finish()
+
+	if out.String() != "Two slides.\n📋 Listing slides\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// defaultCommentAuthor signs comments left without an author
+const defaultCommentAuthor = "SlidePilot"
+
+// ListCommentsDefinition defines the list_comments tool
+var ListCommentsDefinition = ToolDefinition{
+	Name: "list_comments",
+	Description: `List the review comments on the presentation's slides: each comment's comment_id, slide, author, date, text and, for threaded comments, the replies.
+
+Use this to read feedback left by reviewers before revising slides, and to find the comment_id for resolve_comment. Resolved comments are left out unless include_resolved is set.`,
+	InputSchema: ListCommentsInputSchema,
+	Function:    ListComments,
+	ReadOnly:    true,
+}
+
+type ListCommentsInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int    `json:"slide_number,omitempty" jsonschema_description:"(Optional) Only list the comments on this slide (1-based indexing)"`
+	IncludeResolved  bool   `json:"include_resolved,omitempty" jsonschema_description:"(Optional) Also list resolved comments"`
+}
+
+var ListCommentsInputSchema = GenerateSchema[ListCommentsInput]()
+
+func ListComments(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	listInput := ListCommentsInput{}
+	if err := json.Unmarshal(input, &listInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+	path, err := pptxDeckPath(app, listInput.PresentationPath, "comments")
+	if err != nil {
+		return "", err
+	}
+
+	pkg, err := openPPTX(path)
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to read presentation: %v", err)
+	}
+	defer pkg.Close()
+	if listInput.SlideNumber != 0 && (listInput.SlideNumber < 1 || listInput.SlideNumber > pkg.SlideCount()) {
+		return "", NewToolError(ErrCodeSlideOutOfRange, "slide_number %d is not between 1 and %d", listInput.SlideNumber, pkg.SlideCount())
+	}
+	all, err := pkg.Comments()
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to read comments: %v", err)
+	}
+
+	comments := []pptxComment{}
+	resolved := 0
+	for _, comment := range all {
+		if listInput.SlideNumber != 0 && comment.SlideNumber != listInput.SlideNumber {
+			continue
+		}
+		if comment.Resolved && !listInput.IncludeResolved {
+			resolved++
+			continue
+		}
+		comments = append(comments, comment)
+	}
+	result := map[string]interface{}{
+		"comments": comments,
+		"count":    len(comments),
+	}
+	if resolved > 0 {
+		result["hidden_resolved"] = resolved
+	}
+	return marshalResult(result)
+}
+
+// AddCommentDefinition defines the add_comment tool
+var AddCommentDefinition = ToolDefinition{
+	Name: "add_comment",
+	Description: `Leave a review comment on a slide for the user to read in PowerPoint or LibreOffice, instead of changing the slide.
+
+Use this to suggest a change, ask a question or flag a problem, e.g. "Consider shortening this title to fit one line". Give shape_id (from read_slide) to pin the comment next to the shape it is about; otherwise it sits at the slide's top-left corner.`,
+	InputSchema: AddCommentInputSchema,
+	Function:    AddComment,
+	Mutating:    true,
+	Annotates:   true,
+}
+
+type AddCommentInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int    `json:"slide_number" jsonschema_description:"Slide to comment on (1-based indexing)"`
+	Text             string `json:"text" jsonschema_description:"Text of the comment"`
+	ShapeID          string `json:"shape_id,omitempty" jsonschema_description:"(Optional) shape_id from read_slide of the shape the comment is about"`
+	Author           string `json:"author,omitempty" jsonschema_description:"(Optional) Author shown on the comment, defaults to SlidePilot"`
+}
+
+var AddCommentInputSchema = GenerateSchema[AddCommentInput]()
+
+func AddComment(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	addInput := AddCommentInput{}
+	if err := json.Unmarshal(input, &addInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+	path, err := pptxDeckPath(app, addInput.PresentationPath, "comments")
+	if err != nil {
+		return "", err
+	}
+	text := strings.TrimSpace(addInput.Text)
+	if text == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "text is required")
+	}
+	author := strings.TrimSpace(addInput.Author)
+	if author == "" {
+		author = defaultCommentAuthor
+	}
+
+	// Comments sit just off the top-left corner, or at the top-right corner of their shape
+	x, y := int64(15875), int64(15875)
+	if addInput.ShapeID != "" {
+		index, err := resolveShapeID(ctx, app, path, addInput.SlideNumber, addInput.ShapeID)
+		if err != nil {
+			return "", err
+		}
+		pkg, err := openPPTX(path)
+		if err != nil {
+			return "", NewToolError(ErrCodeInternal, "failed to read presentation: %v", err)
+		}
+		shapes, err := pkg.SlideShapes(addInput.SlideNumber)
+		pkg.Close()
+		if err != nil {
+			return "", NewToolError(ErrCodeInternal, "failed to read slide %d: %v", addInput.SlideNumber, err)
+		}
+		shape := shapes[index]
+		x, y = int64((shape.X+shape.Width)*914400), int64(shape.Y*914400)
+	}
+
+	comment, err := addComment(path, addInput.SlideNumber, author, commentInitials(author), text, x, y)
+	if errors.Is(err, errSlideOutOfRange) {
+		return "", NewToolError(ErrCodeSlideOutOfRange, "failed to add comment: %v", err)
+	}
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to add comment: %v", err)
+	}
+
+	return marshalResult(map[string]interface{}{
+		"comment": comment,
+		"message": fmt.Sprintf("Added a comment to slide %d", addInput.SlideNumber),
+	})
+}
+
+// commentInitials returns the initials shown on an author's comments: "JD" for "Jane Doe",
+// the first two letters of a single word
+func commentInitials(author string) string {
+	words := strings.Fields(author)
+	var initials []rune
+	for _, word := range words {
+		initials = append(initials, unicode.ToUpper([]rune(word)[0]))
+	}
+	if len(words) == 1 {
+		if letters := []rune(words[0]); len(letters) > 1 {
+			initials = append(initials, unicode.ToUpper(letters[1]))
+		}
+	}
+	return string(initials)
+}
+
+// ResolveCommentDefinition defines the resolve_comment tool
+var ResolveCommentDefinition = ToolDefinition{
+	Name: "resolve_comment",
+	Description: `Resolve a review comment once it has been addressed, by its comment_id from list_comments.
+
+Threaded comments (PowerPoint 365) are marked resolved and stay visible as resolved. Classic comments have no resolved state, so resolving one removes it from the slide.`,
+	InputSchema: ResolveCommentInputSchema,
+	Function:    ResolveComment,
+	Mutating:    true,
+	Annotates:   true,
+}
+
+type ResolveCommentInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	CommentID        string `json:"comment_id" jsonschema_description:"comment_id from list_comments"`
+	SlideNumber      int    `json:"slide_number,omitempty" jsonschema_description:"(Optional) Slide of the comment (1-based indexing)"`
+}
+
+var ResolveCommentInputSchema = GenerateSchema[ResolveCommentInput]()
+
+func ResolveComment(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	resolveInput := ResolveCommentInput{}
+	if err := json.Unmarshal(input, &resolveInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+	path, err := pptxDeckPath(app, resolveInput.PresentationPath, "comments")
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(resolveInput.CommentID) == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "comment_id is required")
+	}
+
+	comment, err := resolveComment(path, strings.TrimSpace(resolveInput.CommentID), resolveInput.SlideNumber)
+	if errors.Is(err, errCommentNotFound) {
+		return "", NewToolError(ErrCodeInvalidInput, "no comment with comment_id %q; use list_comments to see the comments", resolveInput.CommentID)
+	}
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to resolve comment: %v", err)
+	}
+
+	message := fmt.Sprintf("Marked the comment on slide %d as resolved", comment.SlideNumber)
+	if !comment.modern {
+		message = fmt.Sprintf("Removed the comment on slide %d", comment.SlideNumber)
+	}
+	return marshalResult(map[string]interface{}{
+		"comment": comment,
+		"message": message,
+	})
+}
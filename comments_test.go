@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runCommentTool runs a comment tool through the agent and returns its result data
+func runCommentTool(t *testing.T, env *testEnv, name, input string) map[string]json.RawMessage {
+	t.Helper()
+	result := env.app.aiAgent.executeTool(context.Background(), "toolu_1", name, []byte(input))
+	text := result.OfToolResult.Content[0].OfText.Text
+	if result.OfToolResult.IsError.Value {
+		t.Fatalf("%s failed: %s", name, text)
+	}
+	var decoded struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+		t.Fatalf("%s returned invalid JSON: %v\n%s", name, err, text)
+	}
+	return decoded.Data
+}
+
+// listedComments decodes the comments of a list_comments result
+func listedComments(t *testing.T, data map[string]json.RawMessage) []pptxComment {
+	t.Helper()
+	var comments []pptxComment
+	if err := json.Unmarshal(data["comments"], &comments); err != nil {
+		t.Fatal(err)
+	}
+	return comments
+}
+
+func TestCommentTools(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+
+	if comments := listedComments(t, runCommentTool(t, env, "list_comments", `{}`)); len(comments) != 0 {
+		t.Fatalf("expected no comments, got %+v", comments)
+	}
+
+	runCommentTool(t, env, "add_comment", `{"slide_number": 1, "text": "Shorten this title & keep it on one line"}`)
+	runCommentTool(t, env, "add_comment", `{"slide_number": 2, "text": "Add a date", "shape_id": "Title 1"}`)
+	runCommentTool(t, env, "add_comment", `{"slide_number": 2, "text": "Looks good", "author": "Jane Doe"}`)
+
+	comments := listedComments(t, runCommentTool(t, env, "list_comments", `{}`))
+	if len(comments) != 3 {
+		t.Fatalf("expected 3 comments, got %+v", comments)
+	}
+	first := comments[0]
+	if first.ID != "0-1" || first.SlideNumber != 1 || first.Author != "SlidePilot" || first.Initials != "SL" ||
+		first.Text != "Shorten this title & keep it on one line" || first.Date == "" {
+		t.Errorf("unexpected first comment: %+v", first)
+	}
+	if comments[1].ID != "0-2" || comments[2].ID != "1-1" || comments[2].Initials != "JD" {
+		t.Errorf("expected comments numbered per author, got %+v", comments[1:])
+	}
+	if onSlide := listedComments(t, runCommentTool(t, env, "list_comments", `{"slide_number": 2}`)); len(onSlide) != 2 {
+		t.Errorf("expected 2 comments on slide 2, got %+v", onSlide)
+	}
+
+	// Both comment parts and the authors part are declared in the package
+	pkg, err := openPPTX(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, part := range []string{"ppt/commentAuthors.xml", "ppt/comments/comment1.xml", "ppt/comments/comment2.xml"} {
+		if contentType, override := lookupContentType(t, pkg, part); !override || !strings.HasSuffix(contentType, "+xml") {
+			t.Errorf("expected a content type override for %s, got %q", part, contentType)
+		}
+	}
+	pkg.Close()
+
+	// Classic comments are removed when resolved
+	data := runCommentTool(t, env, "resolve_comment", `{"comment_id": "0-2"}`)
+	if !strings.Contains(string(data["message"]), "Removed the comment on slide 2") {
+		t.Errorf("unexpected resolve message: %s", data["message"])
+	}
+	comments = listedComments(t, runCommentTool(t, env, "list_comments", `{}`))
+	if len(comments) != 2 || comments[1].Text != "Looks good" {
+		t.Errorf("expected the resolved comment to be gone, got %+v", comments)
+	}
+
+	result := env.app.aiAgent.executeTool(context.Background(), "toolu_2", "resolve_comment", []byte(`{"comment_id": "0-2"}`))
+	if !result.OfToolResult.IsError.Value || !strings.Contains(result.OfToolResult.Content[0].OfText.Text, "list_comments") {
+		t.Errorf("expected an unknown comment to be refused, got %s", result.OfToolResult.Content[0].OfText.Text)
+	}
+	result = env.app.aiAgent.executeTool(context.Background(), "toolu_3", "add_comment", []byte(`{"slide_number": 3, "text": "Hi"}`))
+	if !result.OfToolResult.IsError.Value || !strings.Contains(result.OfToolResult.Content[0].OfText.Text, string(ErrCodeSlideOutOfRange)) {
+		t.Errorf("expected SLIDE_OUT_OF_RANGE, got %s", result.OfToolResult.Content[0].OfText.Text)
+	}
+}
+
+func TestModernCommentsAreMarkedResolved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deck.pptx")
+	if err := copyFile(filepath.Join("testdata", "two_slides.pptx"), path); err != nil {
+		t.Fatal(err)
+	}
+	// A PowerPoint 365 thread on slide 1: a comment with one reply
+	err := rewritePackage(path, func(pkg *pptxPackage) (map[string][]byte, error) {
+		replaced := map[string][]byte{
+			"ppt/authors.xml": []byte(`<p188:authorLst xmlns:p188="http://schemas.microsoft.com/office/powerpoint/2018/8/main">` +
+				`<p188:author id="{A1}" name="Ann Lee" initials="AL" userId="ann" providerId="None"/>` +
+				`<p188:author id="{B2}" name="Bo Chen" initials="BC" userId="bo" providerId="None"/></p188:authorLst>`),
+			"ppt/comments/modernComment_100_1.xml": []byte(`<p188:cmLst xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:p188="http://schemas.microsoft.com/office/powerpoint/2018/8/main">` +
+				`<p188:cm id="{C0FFEE}" authorId="{A1}" created="2025-03-01T10:00:00.000"><p188:replyLst>` +
+				`<p188:reply id="{D1}" authorId="{B2}" created="2025-03-02T10:00:00.000"><p188:txBody><a:bodyPr/><a:p><a:r><a:t>Done</a:t></a:r></a:p></p188:txBody></p188:reply>` +
+				`</p188:replyLst><p188:txBody><a:bodyPr/><a:p><a:r><a:t>Check the numbers</a:t></a:r></a:p></p188:txBody></p188:cm></p188:cmLst>`),
+		}
+		if err := addRelationship(pkg, replaced, "ppt/presentation.xml", relTypeModernAuthors, "ppt/authors.xml"); err != nil {
+			return nil, err
+		}
+		if err := addRelationship(pkg, replaced, "ppt/slides/slide1.xml", relTypeModernComments, "ppt/comments/modernComment_100_1.xml"); err != nil {
+			return nil, err
+		}
+		return replaced, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comment, err := resolveComment(path, "{c0ffee}", 0)
+	if err != nil {
+		t.Fatalf("resolveComment failed: %v", err)
+	}
+	if comment.Author != "Ann Lee" || comment.Text != "Check the numbers" || len(comment.Replies) != 1 || comment.Replies[0].Author != "Bo Chen" {
+		t.Errorf("unexpected comment: %+v", comment)
+	}
+	pkg, err := openPPTX(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pkg.Close()
+	comments, err := pkg.Comments()
+	if err != nil || len(comments) != 1 || !comments[0].Resolved {
+		t.Errorf("expected the thread to be kept as resolved, got %+v (%v)", comments, err)
+	}
+	if _, err := resolveComment(path, "C0FFEE", 2); !errors.Is(err, errCommentNotFound) {
+		t.Errorf("expected errCommentNotFound on another slide, got %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// contextTokensEnv overrides the model's context window size in tokens
+const contextTokensEnv = "SLIDEPILOT_CONTEXT_TOKENS"
+
+const (
+	defaultContextTokens = 200000
+	// compactionThreshold is the share of the context window that triggers compaction
+	compactionThreshold = 0.75
+	// keptTurns is how many of the latest turns are always kept word for word
+	keptTurns = 2
+	// Tool results of older turns longer than maxOldToolResultChars are cut to truncatedToolResultChars
+	maxOldToolResultChars    = 2000
+	truncatedToolResultChars = 500
+	// imageTokens approximates what one screenshot costs
+	imageTokens = 1600
+)
+
+// conversationSummaryPrefix starts the text block that replaces summarized turns
+const conversationSummaryPrefix = "Summary of the earlier conversation:\n"
+
+// contextTokenLimit returns the context window the conversation has to fit in
+func contextTokenLimit() int {
+	if value := os.Getenv(contextTokensEnv); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			return n
+		}
+		fmt.Printf("Warning: ignoring invalid %s=%q\n", contextTokensEnv, value)
+	}
+	return defaultContextTokens
+}
+
+// estimateConversationTokens roughly counts a conversation's tokens at four characters
+// per token, with screenshots at a fixed cost
+func estimateConversationTokens(messages []anthropic.MessageParam) int {
+	images := 0
+	for _, message := range messages {
+		for _, block := range message.Content {
+			if block.OfToolResult == nil {
+				continue
+			}
+			for _, part := range block.OfToolResult.Content {
+				if part.OfImage != nil {
+					images++
+				}
+			}
+		}
+	}
+	data, _ := json.Marshal(withoutScreenshots(messages))
+	return len(data)/4 + images*imageTokens
+}
+
+// turnStarts returns the index of every message that starts a turn: a user message
+// carrying the user's text rather than tool results
+func turnStarts(messages []anthropic.MessageParam) []int {
+	var starts []int
+	for i, message := range messages {
+		if message.Role != anthropic.MessageParamRoleUser {
+			continue
+		}
+		for _, block := range message.Content {
+			if block.OfText != nil {
+				starts = append(starts, i)
+				break
+			}
+		}
+	}
+	return starts
+}
+
+// truncateToolResults shortens long tool results in messages[:end], such as full slide
+// JSON the model has already acted on, and drops their screenshots. Messages are copied
+// before they are changed.
+func truncateToolResults(messages []anthropic.MessageParam, end int) []anthropic.MessageParam {
+	result := append(withoutScreenshots(messages[:end]), messages[end:]...)
+	for i := 0; i < end; i++ {
+		copied := false
+		for j, block := range result[i].Content {
+			if block.OfToolResult == nil {
+				continue
+			}
+			var content []anthropic.ToolResultBlockParamContentUnion
+			changed := false
+			for _, part := range block.OfToolResult.Content {
+				if part.OfText != nil && len(part.OfText.Text) > maxOldToolResultChars {
+					text := part.OfText.Text
+					part = anthropic.ToolResultBlockParamContentUnion{OfText: &anthropic.TextBlockParam{
+						Text: fmt.Sprintf("%s… [%d characters truncated to save context]", text[:truncatedToolResultChars], len(text)-truncatedToolResultChars),
+					}}
+					changed = true
+				}
+				content = append(content, part)
+			}
+			if !changed {
+				continue
+			}
+			if !copied {
+				result[i].Content = append([]anthropic.ContentBlockParamUnion(nil), result[i].Content...)
+				copied = true
+			}
+			toolResult := *block.OfToolResult
+			toolResult.Content = content
+			result[i].Content[j] = anthropic.ContentBlockParamUnion{OfToolResult: &toolResult}
+		}
+	}
+	return result
+}
+
+// compactConversation keeps the conversation within the context window. Once it passes
+// the threshold, long tool results of older turns are truncated; if that isn't enough,
+// everything before the latest turns is replaced by a model-written summary.
+func (a *AIAgent) compactConversation(ctx context.Context) {
+	limit := int(float64(a.contextLimit) * compactionThreshold)
+	if max(a.lastContextTokens, estimateConversationTokens(a.conversation)) <= limit {
+		return
+	}
+
+	starts := turnStarts(a.conversation)
+	if len(starts) <= keptTurns {
+		return
+	}
+	cut := starts[len(starts)-keptTurns]
+
+	a.conversation = truncateToolResults(a.conversation, cut)
+	a.lastContextTokens = 0
+	if estimateConversationTokens(a.conversation) <= limit {
+		a.logToFile("CONTEXT", "Truncated old tool results to fit the context window", "")
+		return
+	}
+
+	summary, err := a.summarizeConversation(ctx, a.conversation[:cut])
+	if err != nil {
+		a.logToFile("ERROR", "Conversation summary failed", err.Error())
+		return
+	}
+
+	kept := append([]anthropic.MessageParam(nil), a.conversation[cut:]...)
+	kept[0].Content = append([]anthropic.ContentBlockParamUnion{anthropic.NewTextBlock(conversationSummaryPrefix + summary)}, kept[0].Content...)
+	a.conversation = kept
+	a.logToFile("CONTEXT", fmt.Sprintf("Summarized %d earlier messages", cut), summary)
+	a.emitMessage("🗜️ Summarized earlier messages to stay within the model's context window")
+}
+
+// summarizeConversation asks the model for a summary of messages to stand in for them
+func (a *AIAgent) summarizeConversation(ctx context.Context, messages []anthropic.MessageParam) (string, error) {
+	prompt := `Summarize this slide-editing conversation so you can continue it without the original messages. Keep:
+- which presentation and slides were worked on
+- every edit made, with slide numbers and shape_ids
+- the user's instructions, preferences and style decisions
+- anything still open or promised
+
+Be concise and factual. Respond with the summary only.
+
+` + conversationText(messages)
+
+	params := a.messageParams()
+	params.MaxTokens = min(params.MaxTokens, 1024)
+	params.Messages = []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+	}
+	message, err := a.llm.CreateMessage(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	a.app.recordUsage(ctx, "conversation summary", message)
+
+	var summary strings.Builder
+	for _, content := range message.Content {
+		if content.Type == "text" {
+			summary.WriteString(content.Text)
+		}
+	}
+	if strings.TrimSpace(summary.String()) == "" {
+		return "", fmt.Errorf("the model returned an empty summary")
+	}
+	return strings.TrimSpace(summary.String()), nil
+}
+
+// conversationText renders messages as a plain transcript for summarizing, with tool
+// results shortened
+func conversationText(messages []anthropic.MessageParam) string {
+	var text strings.Builder
+	for _, message := range messages {
+		role := "Assistant"
+		if message.Role == anthropic.MessageParamRoleUser {
+			role = "User"
+		}
+		for _, block := range message.Content {
+			switch {
+			case block.OfText != nil:
+				fmt.Fprintf(&text, "%s: %s\n", role, block.OfText.Text)
+			case block.OfToolUse != nil:
+				input, _ := json.Marshal(block.OfToolUse.Input)
+				fmt.Fprintf(&text, "Assistant called %s %s\n", block.OfToolUse.Name, input)
+			case block.OfToolResult != nil:
+				for _, part := range block.OfToolResult.Content {
+					if part.OfText != nil {
+						result := part.OfText.Text
+						if len(result) > 300 {
+							result = result[:300] + "…"
+						}
+						fmt.Fprintf(&text, "Tool result: %s\n", result)
+					}
+				}
+			}
+		}
+	}
+	return text.String()
+}
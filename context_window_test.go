@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// longSession builds a conversation of finished turns, each reading a slide with a bulky result
+func longSession(turns int) []anthropic.MessageParam {
+	var messages []anthropic.MessageParam
+	for i := 1; i <= turns; i++ {
+		id := fmt.Sprintf("toolu_%d", i)
+		messages = append(messages,
+			anthropic.NewUserMessage(anthropic.NewTextBlock(fmt.Sprintf("Check slide %d", i))),
+			anthropic.NewAssistantMessage(anthropic.NewToolUseBlock(id, map[string]int{"slide_number": i}, "read_slide")),
+			anthropic.NewUserMessage(anthropic.NewToolResultBlock(id, strings.Repeat("x", 6000), false)),
+			anthropic.NewAssistantMessage(anthropic.NewTextBlock(fmt.Sprintf("Slide %d looks fine.", i))),
+		)
+	}
+	return messages
+}
+
+// toolResultLength returns the length of the tool result text in a message
+func toolResultLength(message anthropic.MessageParam) int {
+	return len(message.Content[0].OfToolResult.Content[0].OfText.Text)
+}
+
+func TestCompactionTruncatesOldToolResultsFirst(t *testing.T) {
+	env := newTestEnv(t)
+	agent := env.app.aiAgent
+	agent.conversation = longSession(3)
+	// Over the threshold as is, under it once the first turn's result is cut
+	agent.contextLimit = estimateConversationTokens(agent.conversation) * 10 / 9
+
+	agent.compactConversation(context.Background())
+
+	if len(env.llm.Requests) != 0 {
+		t.Errorf("expected no summary request, got %d", len(env.llm.Requests))
+	}
+	if length := toolResultLength(agent.conversation[2]); length >= maxOldToolResultChars {
+		t.Errorf("expected the oldest turn's tool result to be truncated, got %d characters", length)
+	}
+	for _, index := range []int{6, 10} {
+		if length := toolResultLength(agent.conversation[index]); length != 6000 {
+			t.Errorf("expected the latest turns to be kept whole, message %d has %d characters", index, length)
+		}
+	}
+}
+
+func TestCompactionSummarizesOlderTurns(t *testing.T) {
+	env := newTestEnv(t,
+		textResponse("Checked slide 1; it was fine."),
+		textResponse("Slide 5 looks fine too."),
+	)
+	env.loadFixture(t, "two_slides.pptx")
+	agent := env.app.aiAgent
+	agent.conversationPath = env.app.presentationPath()
+	agent.conversation = longSession(3)
+	agent.contextLimit = 1000
+
+	if err := agent.SendMessage(context.Background(), "Check slide 5"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	prompt := env.llm.Requests[0].Messages[0].Content[0].OfText.Text
+	if !strings.Contains(prompt, "Assistant called read_slide") || !strings.Contains(prompt, "Slide 1 looks fine.") || strings.Contains(prompt, "Slide 2") {
+		t.Errorf("expected only the turns before the latest two to be summarized:\n%s", prompt)
+	}
+
+	// The summary leads the first kept turn, so roles still alternate
+	first := agent.conversation[0]
+	if first.Role != anthropic.MessageParamRoleUser || first.Content[0].OfText == nil ||
+		first.Content[0].OfText.Text != conversationSummaryPrefix+"Checked slide 1; it was fine." {
+		t.Fatalf("expected the summary first, got %+v", first.Content[0])
+	}
+	if first.Content[1].OfText.Text != "Check slide 2" {
+		t.Errorf("expected the kept turn to follow the summary, got %q", first.Content[1].OfText.Text)
+	}
+	// Two kept turns (4 messages each) plus the new turn (2 messages)
+	if len(agent.conversation) != 10 {
+		t.Errorf("expected 10 messages after compaction, got %d", len(agent.conversation))
+	}
+
+	transcript := conversationTranscript(agent.conversation)
+	if !strings.HasPrefix(transcript[0].Content, "🗜️ Earlier messages were summarized") || transcript[0].Role != "assistant" {
+		t.Errorf("expected the summary to show as a note, got %+v", transcript[0])
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// ConversationStore keeps one AI conversation per presentation on disk, so an editing
+// session survives restarting the app. Each conversation is a JSON file named after a
+// hash of the presentation's absolute path.
+type ConversationStore struct {
+	dir string
+}
+
+// savedConversation is the on-disk form of a conversation
+type savedConversation struct {
+	PresentationPath string                   `json:"presentation_path"`
+	UpdatedAt        time.Time                `json:"updated_at"`
+	Messages         []anthropic.MessageParam `json:"messages"`
+}
+
+// ConversationSummary describes a saved conversation for the frontend
+type ConversationSummary struct {
+	PresentationPath string    `json:"presentation_path"`
+	Name             string    `json:"name"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	MessageCount     int       `json:"message_count"`
+	LastRequest      string    `json:"last_request"`
+}
+
+// ConversationMessage is one chat bubble of a restored conversation
+type ConversationMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// NewConversationStore creates a store keeping conversations in dir
+func NewConversationStore(dir string) *ConversationStore {
+	return &ConversationStore{dir: dir}
+}
+
+// conversationsDir is where conversations are kept: the user config directory, falling
+// back to the working directory
+func conversationsDir() string {
+	if configDir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(configDir, "slidepilot", "conversations")
+	}
+	return "conversations"
+}
+
+// path returns the file holding a presentation's conversation
+func (s *ConversationStore) path(presentationPath string) string {
+	sum := sha256.Sum256([]byte(presentationPath))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])[:16]+".json")
+}
+
+// Save writes a presentation's conversation, replacing the previous copy atomically
+func (s *ConversationStore) Save(presentationPath string, messages []anthropic.MessageParam) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create conversations directory: %v", err)
+	}
+
+	data, err := json.Marshal(savedConversation{
+		PresentationPath: presentationPath,
+		UpdatedAt:        time.Now(),
+		Messages:         withoutScreenshots(messages),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode conversation: %v", err)
+	}
+
+	target := s.path(presentationPath)
+	tmp, err := os.CreateTemp(s.dir, ".conversation-*")
+	if err != nil {
+		return fmt.Errorf("failed to save conversation: %v", err)
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save conversation: %v", errors.Join(writeErr, closeErr))
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save conversation: %v", err)
+	}
+	return nil
+}
+
+// withoutScreenshots drops the slide screenshots attached to tool results, which are only
+// useful within the turn that produced them and would make saved conversations huge
+func withoutScreenshots(messages []anthropic.MessageParam) []anthropic.MessageParam {
+	stripped := make([]anthropic.MessageParam, len(messages))
+	for i, message := range messages {
+		stripped[i] = message
+		copied := false
+		for j, block := range message.Content {
+			if block.OfToolResult == nil {
+				continue
+			}
+			var content []anthropic.ToolResultBlockParamContentUnion
+			for _, part := range block.OfToolResult.Content {
+				if part.OfImage == nil {
+					content = append(content, part)
+				}
+			}
+			if len(content) == len(block.OfToolResult.Content) {
+				continue
+			}
+			// Copy before modifying so the live conversation keeps its screenshots
+			if !copied {
+				stripped[i].Content = append([]anthropic.ContentBlockParamUnion(nil), message.Content...)
+				copied = true
+			}
+			result := *block.OfToolResult
+			result.Content = content
+			stripped[i].Content[j] = anthropic.ContentBlockParamUnion{OfToolResult: &result}
+		}
+	}
+	return stripped
+}
+
+// Load reads a presentation's conversation. A presentation without one returns an error
+// wrapping os.ErrNotExist.
+func (s *ConversationStore) Load(presentationPath string) ([]anthropic.MessageParam, error) {
+	saved, err := s.read(s.path(presentationPath))
+	if err != nil {
+		return nil, err
+	}
+	return saved.Messages, nil
+}
+
+// Delete removes a presentation's conversation. A presentation without one is not an error.
+func (s *ConversationStore) Delete(presentationPath string) error {
+	if err := os.Remove(s.path(presentationPath)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete conversation: %v", err)
+	}
+	return nil
+}
+
+// List returns all saved conversations, most recently updated first
+func (s *ConversationStore) List() ([]ConversationSummary, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := []ConversationSummary{}
+	for _, file := range files {
+		saved, err := s.read(file)
+		if err != nil {
+			fmt.Printf("Warning: skipping unreadable conversation %s: %v\n", file, err)
+			continue
+		}
+		summary := ConversationSummary{
+			PresentationPath: saved.PresentationPath,
+			Name:             filepath.Base(saved.PresentationPath),
+			UpdatedAt:        saved.UpdatedAt,
+			MessageCount:     len(saved.Messages),
+		}
+		for _, message := range conversationTranscript(saved.Messages) {
+			if message.Role == "user" {
+				summary.LastRequest = message.Content
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].UpdatedAt.After(summaries[j].UpdatedAt)
+	})
+	return summaries, nil
+}
+
+func (s *ConversationStore) read(file string) (*savedConversation, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read conversation: %w", err)
+	}
+	var saved savedConversation
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("invalid conversation file %s: %v", file, err)
+	}
+	return &saved, nil
+}
+
+// conversationTranscript turns a conversation into the chat bubbles the user saw: their
+// requests (without the presentation context prepended to them), the assistant's text,
+// and a status line per tool call
+func conversationTranscript(messages []anthropic.MessageParam) []ConversationMessage {
+	transcript := []ConversationMessage{}
+	for _, message := range messages {
+		for _, block := range message.Content {
+			switch {
+			case block.OfText != nil && block.OfText.Text != "":
+				role := "assistant"
+				text := block.OfText.Text
+				if summary, found := strings.CutPrefix(text, conversationSummaryPrefix); found {
+					text = "🗜️ Earlier messages were summarized:\n" + summary
+				} else if message.Role == anthropic.MessageParamRoleUser {
+					role = "user"
+					if _, request, found := strings.Cut(text, "User request: "); found && strings.HasPrefix(text, "Current presentation loaded: ") {
+						text = request
+					}
+				}
+				transcript = append(transcript, ConversationMessage{Role: role, Content: text})
+			case block.OfToolUse != nil:
+				transcript = append(transcript, ConversationMessage{Role: "assistant", Content: getToolDisplayName(block.OfToolUse.Name)})
+			}
+		}
+	}
+	return transcript
+}
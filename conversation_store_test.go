@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+func TestConversationResumesAfterRestart(t *testing.T) {
+	env := newTestEnv(t,
+		toolUseResponse("toolu_1", "list_slides", `{}`),
+		textResponse("The deck has two slides."),
+		textResponse("Still two."),
+	)
+	store := NewConversationStore(t.TempDir())
+	env.app.aiAgent.conversations = store
+	path := env.loadFixture(t, "two_slides.pptx")
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "How many slides?"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	// A fresh agent starts empty until the conversation is loaded
+	env.app.aiAgent = NewAIAgent(env.app, env.llm)
+	env.app.aiAgent.conversations = store
+	transcript, err := env.app.LoadConversation(path)
+	if err != nil {
+		t.Fatalf("LoadConversation failed: %v", err)
+	}
+
+	want := []ConversationMessage{
+		{Role: "user", Content: "How many slides?"},
+		{Role: "assistant", Content: getToolDisplayName("list_slides")},
+		{Role: "assistant", Content: "The deck has two slides."},
+	}
+	if len(transcript) != len(want) {
+		t.Fatalf("expected %d messages, got %+v", len(want), transcript)
+	}
+	for i := range want {
+		if transcript[i] != want[i] {
+			t.Errorf("message %d: expected %+v, got %+v", i, want[i], transcript[i])
+		}
+	}
+
+	// The next request continues the same conversation
+	if err := env.app.aiAgent.SendMessage(context.Background(), "And now?"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if got := len(env.llm.Requests[2].Messages); got != 5 {
+		t.Errorf("expected the resumed history plus the new request (5 messages), got %d", got)
+	}
+
+	summaries, err := env.app.ListConversations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 1 || summaries[0].PresentationPath != path || summaries[0].LastRequest != "And now?" || summaries[0].MessageCount != 6 {
+		t.Errorf("unexpected summaries %+v", summaries)
+	}
+}
+
+func TestConversationIsPerPresentation(t *testing.T) {
+	env := newTestEnv(t,
+		textResponse("Hello."),
+		textResponse("Hi again."),
+	)
+	env.app.aiAgent.conversations = NewConversationStore(t.TempDir())
+	env.loadFixture(t, "two_slides.pptx")
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Hi"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	// Switching decks starts over instead of carrying the first deck's history along
+	other := env.loadFixture(t, "mixed_shapes.pptx")
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Hi"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if got := len(env.llm.Requests[1].Messages); got != 1 {
+		t.Errorf("expected a fresh conversation for the second deck, got %d messages", got)
+	}
+
+	transcript, err := env.app.aiAgent.LoadConversation(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transcript) != 2 || transcript[1].Content != "Hi again." {
+		t.Errorf("unexpected transcript %+v", transcript)
+	}
+}
+
+func TestSwitchingPresentationsRestoresTheirThreads(t *testing.T) {
+	env := newTestEnv(t,
+		textResponse("Hello first deck."),
+		textResponse("Hello second deck."),
+		textResponse("Back on the first deck."),
+	)
+	first := env.loadFixture(t, "two_slides.pptx")
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Hi"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	second := env.loadFixture(t, "mixed_shapes.pptx")
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Hi"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	// Opening the first deck again brings its thread back, even without a store
+	if _, err := env.app.LoadPresentation(first); err != nil {
+		t.Fatalf("LoadPresentation failed: %v", err)
+	}
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Still there?"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	messages := env.llm.Requests[2].Messages
+	if len(messages) != 3 || messages[1].Content[0].OfText.Text != "Hello first deck." {
+		t.Errorf("expected the first deck's thread without the second's, got %d messages", len(messages))
+	}
+
+	// Deleting a thread forgets it
+	if err := env.app.DeleteConversation(second); err != nil {
+		t.Fatalf("DeleteConversation failed: %v", err)
+	}
+	transcript, err := env.app.aiAgent.LoadConversation(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transcript) != 0 {
+		t.Errorf("expected the deleted thread to start empty, got %+v", transcript)
+	}
+}
+
+func TestDeleteConversationRemovesTheSavedFile(t *testing.T) {
+	env := newTestEnv(t, textResponse("Hello."))
+	store := NewConversationStore(t.TempDir())
+	env.app.aiAgent.conversations = store
+	path := env.loadFixture(t, "two_slides.pptx")
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Hi"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	if err := env.app.DeleteConversation(""); err != nil {
+		t.Fatalf("DeleteConversation failed: %v", err)
+	}
+	if _, err := store.Load(path); !os.IsNotExist(errors.Unwrap(err)) {
+		t.Errorf("expected the saved conversation to be gone, got %v", err)
+	}
+	if len(env.app.aiAgent.conversation) != 0 {
+		t.Errorf("expected the current conversation to start over, got %d messages", len(env.app.aiAgent.conversation))
+	}
+	summaries, _ := env.app.ListConversations()
+	if len(summaries) != 0 {
+		t.Errorf("expected no saved conversations, got %+v", summaries)
+	}
+}
+
+func TestSavedConversationDropsScreenshots(t *testing.T) {
+	store := NewConversationStore(t.TempDir())
+	result, err := toolResultWithScreenshotForTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conversation := []anthropic.MessageParam{anthropic.NewUserMessage(result)}
+
+	if err := store.Save("/decks/a.pptx", conversation); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := store.Load("/decks/a.pptx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(loaded[0].Content[0].OfToolResult.Content); got != 2 {
+		t.Errorf("expected the result and caption without the image, got %d parts", got)
+	}
+	if got := len(conversation[0].Content[0].OfToolResult.Content); got != 3 {
+		t.Errorf("saving must not modify the live conversation, got %d parts", got)
+	}
+}
+
+// toolResultWithScreenshotForTest builds a tool result carrying a small screenshot
+func toolResultWithScreenshotForTest(t *testing.T) (anthropic.ContentBlockParamUnion, error) {
+	t.Helper()
+	imagePath := filepath.Join(t.TempDir(), "slide-000.jpg")
+	if err := os.WriteFile(imagePath, []byte("fake jpeg"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return toolResultWithScreenshot("toolu_1", `{"success": true}`, imagePath, 1)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// progressPollInterval is how often a running export checks how many slides it rendered
+const progressPollInterval = 250 * time.Millisecond
+
+// ConversionProgress is the payload of "conversion-progress" events, sent while slide
+// images are rendered: once before, as slides are written, and when it ends
+type ConversionProgress struct {
+	PresentationPath string `json:"presentation_path"`
+	Stage            string `json:"stage"` // "rendering", "done" or "failed"
+	Slide            int    `json:"slide"` // Slides rendered so far
+	Total            int    `json:"total"` // Slides to render, 0 when unknown
+	Percent          int    `json:"percent"`
+	ToolID           string `json:"tool_id,omitempty"` // The tool call the export runs for, if any
+	Tool             string `json:"tool,omitempty"`
+}
+
+// progressReporterKey and toolCallKey carry progress reporting through the converter,
+// whose functions only get a UnoBridge
+type progressReporterKey struct{}
+type toolCallKey struct{}
+
+// toolCall names the tool call a context belongs to
+type toolCall struct {
+	ID   string
+	Name string
+}
+
+// withToolCall marks ctx as belonging to a tool call, so its progress events name it
+func withToolCall(ctx context.Context, id, name string) context.Context {
+	return context.WithValue(ctx, toolCallKey{}, toolCall{ID: id, Name: name})
+}
+
+// withConversionProgress has exports run with ctx emit "conversion-progress" events for
+// the presentation
+func (a *App) withConversionProgress(ctx context.Context, pptxPath string) context.Context {
+	if a == nil || a.events == nil {
+		return ctx
+	}
+	call, _ := ctx.Value(toolCallKey{}).(toolCall)
+	return context.WithValue(ctx, progressReporterKey{}, func(progress ConversionProgress) {
+		progress.PresentationPath = pptxPath
+		progress.ToolID, progress.Tool = call.ID, call.Name
+		a.events.Emit(a.baseContext(), "conversion-progress", progress)
+	})
+}
+
+// reportConversionProgress sends progress to the reporter of ctx, if it has one
+func reportConversionProgress(ctx context.Context, stage string, slide, total int) {
+	report, ok := ctx.Value(progressReporterKey{}).(func(ConversionProgress))
+	if !ok {
+		return
+	}
+	progress := ConversionProgress{Stage: stage, Slide: slide, Total: total}
+	if stage == "done" {
+		progress.Percent = 100
+	} else if total > 0 {
+		progress.Percent = min(100, slide*100/total)
+	}
+	report(progress)
+}
+
+// watchRenderedSlides reports rendering progress by counting the images appearing in
+// renderDir until stop is closed. total is 0 when the slide count isn't known.
+func watchRenderedSlides(ctx context.Context, renderDir string, total int, stop <-chan struct{}) {
+	if _, ok := ctx.Value(progressReporterKey{}).(func(ConversionProgress)); !ok {
+		return
+	}
+	reportConversionProgress(ctx, "rendering", 0, total)
+
+	ticker := time.NewTicker(progressPollInterval)
+	defer ticker.Stop()
+	reported := 0
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			entries, err := os.ReadDir(renderDir)
+			if err != nil || len(entries) == reported {
+				continue
+			}
+			reported = len(entries)
+			reportConversionProgress(ctx, "rendering", reported, total)
+		}
+	}
+}
@@ -1,64 +1,353 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 )
 
-// ConvertPPTXToJPEG converts a PPTX file to JPEG slides using LibreOffice and ImageMagick
-func ConvertPPTXToJPEG(pptxPath string, outputDir ...string) ([]string, error) {
-	// Create slides output directory
-	slidesDir := "slides"
-	if len(outputDir) > 0 && outputDir[0] != "" {
-		slidesDir = outputDir[0]
+// Slides are exported page by page through the running LibreOffice (uno_export_slides.py),
+// so re-rendering a range of slides costs only those slides, and slides whose checksum
+// matches their existing image are skipped (see slide_checksums.go). Rendering still
+// happens inside a private temp directory under fixed slide-NNN.<ext> names and the
+// results are moved into place afterwards, so a failed export never leaves half-written
+// images behind.
+
+// ExportOptions controls how slide images are rendered
+type ExportOptions struct {
+	Format    string `json:"format"`     // jpeg, png or webp
+	DPI       int    `json:"dpi"`        // Pixels per inch of the slide
+	Quality   int    `json:"quality"`    // 1-100, for jpeg and webp
+	MaxWidth  int    `json:"max_width"`  // Scale down to fit, keeping the aspect ratio; 0 for no limit
+	MaxHeight int    `json:"max_height"` // Scale down to fit, keeping the aspect ratio; 0 for no limit
+}
+
+// previewExportOptions renders the app's JPEG slide previews
+var previewExportOptions = ExportOptions{Format: "jpeg", DPI: 150, Quality: 90}
+
+// exportFormatExtensions maps the supported formats to their file extensions
+var exportFormatExtensions = map[string]string{"jpeg": "jpg", "png": "png", "webp": "webp"}
+
+// WithDefaults fills unset fields from the preview settings and normalizes the format
+func (o ExportOptions) WithDefaults() ExportOptions {
+	o.Format = strings.ToLower(o.Format)
+	if o.Format == "" || o.Format == "jpg" {
+		o.Format = previewExportOptions.Format
+	}
+	if o.DPI == 0 {
+		o.DPI = previewExportOptions.DPI
+	}
+	if o.Quality == 0 {
+		o.Quality = previewExportOptions.Quality
+	}
+	return o
+}
+
+// Validate reports options the exporter can't honor
+func (o ExportOptions) Validate() error {
+	if _, ok := exportFormatExtensions[o.Format]; !ok {
+		return fmt.Errorf("unsupported export format %q (use jpeg, png or webp)", o.Format)
+	}
+	if o.DPI < 10 || o.DPI > 1200 {
+		return fmt.Errorf("dpi must be between 10 and 1200, got %d", o.DPI)
+	}
+	if o.Quality < 1 || o.Quality > 100 {
+		return fmt.Errorf("quality must be between 1 and 100, got %d", o.Quality)
+	}
+	if o.MaxWidth < 0 || o.MaxHeight < 0 {
+		return fmt.Errorf("max dimensions can't be negative")
+	}
+	return nil
+}
+
+// Extension returns the file extension of the images, without the dot
+func (o ExportOptions) Extension() string {
+	return exportFormatExtensions[o.Format]
+}
+
+// ConvertPPTXToJPEG renders the slides of a PPTX file to JPEG previews through
+// LibreOffice's UNO socket, skipping slides whose preview in outputDir is up to date
+func ConvertPPTXToJPEG(ctx context.Context, bridge UnoBridge, pptxPath, outputDir string) ([]string, error) {
+	return ExportSlideImages(ctx, bridge, pptxPath, outputDir, 0, math.MaxInt, previewExportOptions)
+}
+
+// ConvertSlideRangeToJPEG re-renders the previews of slides first..last (0-based,
+// inclusive) into outputDir, keeping their slide-%03d.jpg numbering
+func ConvertSlideRangeToJPEG(ctx context.Context, bridge UnoBridge, pptxPath, outputDir string, first, last int) ([]string, error) {
+	if first < 0 || last < first {
+		return nil, fmt.Errorf("invalid slide range %d-%d", first, last)
+	}
+	return ExportSlideImages(ctx, bridge, pptxPath, outputDir, first, last, previewExportOptions)
+}
+
+// ExportSlideImages renders slides first..last (0-based, inclusive; math.MaxInt for the
+// rest of the deck) into outputDir as slide-NNN.<ext> and returns all images of that
+// format in outputDir. Only slides whose content changed since their image was rendered
+// with the same options are exported. An export reaching the end of the deck also drops
+// images left over from a previously exported, longer deck. Progress goes to the reporter
+// of ctx (see conversion_progress.go).
+func ExportSlideImages(ctx context.Context, bridge UnoBridge, pptxPath, outputDir string, first, last int, options ExportOptions) (images []string, err error) {
+	defer func() {
+		if err != nil {
+			reportConversionProgress(ctx, "failed", 0, 0)
+		} else {
+			reportConversionProgress(ctx, "done", len(images), len(images))
+		}
+	}()
+
+	if first < 0 || last < first {
+		return nil, fmt.Errorf("invalid slide range %d-%d", first, last)
+	}
+	options = options.WithDefaults()
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	absPath, err := prepareConversion(pptxPath, outputDir)
+	if err != nil {
+		return nil, err
+	}
+	ext := options.Extension()
+
+	checksums, err := slideChecksums(absPath)
+	if err != nil {
+		// Without checksums every requested slide is exported and nothing is recorded
+		fmt.Printf("Warning: Exporting without skipping unchanged slides, cannot compute slide checksums: %v\n", err)
+		os.Remove(filepath.Join(outputDir, slideChecksumsFile))
+
+		var slides []int
+		if last != math.MaxInt {
+			for i := first; i <= last; i++ {
+				slides = append(slides, i)
+			}
+		}
+		rendered, err := exportSlideImages(ctx, bridge, absPath, outputDir, slides, len(slides), options)
+		if err != nil {
+			return nil, err
+		}
+		if slides == nil {
+			if rendered == 0 {
+				return nil, fmt.Errorf("no slide images were generated")
+			}
+			removeSlideImagesFrom(outputDir, rendered, ext)
+		}
+		return listSlideImages(outputDir, ext)
+	}
+
+	if last >= len(checksums)-1 {
+		if len(checksums) == 0 {
+			return nil, fmt.Errorf("no slide images were generated")
+		}
+		// Drop images left over from a previously exported, longer deck
+		removeSlideImagesFrom(outputDir, len(checksums), ext)
+	}
+
+	if err := exportChangedSlides(ctx, bridge, absPath, outputDir, checksums, first, last, options); err != nil {
+		return nil, err
+	}
+	return listSlideImages(outputDir, ext)
+}
+
+// prepareConversion creates the output directory, checks it has room for the images,
+// and returns the presentation's absolute path
+func prepareConversion(pptxPath, outputDir string) (string, error) {
+	absPath, err := filepath.Abs(pptxPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve presentation path: %v", err)
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create slides directory: %v", err)
+	}
+	if err := checkConversionSpace(absPath, outputDir); err != nil {
+		return "", err
 	}
-	if err := os.MkdirAll(slidesDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create slides directory: %v", err)
+	return absPath, nil
+}
+
+// checkConversionSpace makes sure the temp and output directories can hold a conversion.
+// The rendered images are estimated at a few times the size of the presentation.
+func checkConversionSpace(pptxPath, outputDir string) error {
+	estimate := 3 * fileSize(pptxPath)
+	if err := checkDiskSpace(os.TempDir(), estimate); err != nil {
+		return err
+	}
+	return checkDiskSpace(outputDir, estimate)
+}
+
+// exportChangedSlides exports the slides in first..last whose checksum differs from the
+// one recorded for their image, then records the new checksums
+func exportChangedSlides(ctx context.Context, bridge UnoBridge, absPath, outputDir string, checksums []string, first, last int, options ExportOptions) error {
+	recorded := readSlideChecksums(outputDir, absPath, options)
+	changed := changedSlides(outputDir, recorded, checksums, first, last, options.Extension())
+	if len(changed) == 0 {
+		fmt.Printf("Slide images %d-%d are up to date\n", first+1, min(last, len(checksums)-1)+1)
+		return nil
 	}
 
-	// Create temporary directory for PDF
-	tmpDir, err := os.MkdirTemp("", "slidepilot-*")
+	fmt.Printf("Exporting %d changed slide(s) to %s...\n", len(changed), strings.ToUpper(options.Format))
+	if _, err := exportSlideImages(ctx, bridge, absPath, outputDir, changed, len(changed), options); err != nil {
+		return err
+	}
+	updateSlideChecksums(outputDir, absPath, options, recorded, checksums, changed)
+	return nil
+}
+
+// exportSlideImages runs uno_export_slides.py into a temp directory for the given 0-based
+// slide indexes (all slides when nil) and moves the images into outputDir. It returns how
+// many slides were rendered. total is how many slides will be rendered, 0 when unknown.
+func exportSlideImages(ctx context.Context, bridge UnoBridge, absPath, outputDir string, slides []int, total int, options ExportOptions) (int, error) {
+	renderDir, err := os.MkdirTemp(sessionTempDir(), "render-*")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temp directory: %v", err)
+		return 0, fmt.Errorf("failed to create temp directory: %v", err)
 	}
-	defer os.RemoveAll(tmpDir)
+	defer os.RemoveAll(renderDir)
 
-	// Step 1: Convert PPTX to PDF using LibreOffice headless
-	fmt.Println("Converting PPTX to PDF...")
-	cmd := exec.Command("libreoffice", "--headless", "--convert-to", "pdf", 
-		"--outdir", tmpDir, pptxPath)
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("LibreOffice conversion failed: %v", err)
+	numbers := make([]string, len(slides))
+	for i, index := range slides {
+		numbers[i] = strconv.Itoa(index + 1)
 	}
+	optionsJSON, _ := json.Marshal(options)
 
-	// Find the generated PDF file
-	baseName := strings.TrimSuffix(filepath.Base(pptxPath), ".pptx")
-	pdfPath := filepath.Join(tmpDir, baseName+".pdf")
-	
-	if _, err := os.Stat(pdfPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("PDF file not found at %s", pdfPath)
+	stop := make(chan struct{})
+	watched := make(chan struct{})
+	go func() {
+		watchRenderedSlides(ctx, renderDir, total, stop)
+		close(watched)
+	}()
+	span := profiler.Start("export", "uno_slide_export")
+	output, err := bridge.Run(ctx, "uno_export_slides.py", absPath, renderDir, strings.Join(numbers, ","), string(optionsJSON))
+	span.End()
+	close(stop)
+	<-watched
+	if err != nil {
+		return 0, scriptError("slide export failed", err, output)
 	}
 
-	// Step 2: Convert PDF to JPEG using ImageMagick
-	fmt.Println("Converting PDF to JPEG slides...")
-	outputPattern := filepath.Join(slidesDir, "slide-%03d.jpg")
-	cmd = exec.Command("convert", "-density", "150", pdfPath, outputPattern)
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("ImageMagick conversion failed: %v", err)
+	return moveRenderedSlides(renderDir, outputDir, options.Extension())
+}
+
+// removeSlideImagesFrom deletes consecutive slide images starting at the 0-based index
+func removeSlideImagesFrom(dir string, index int, ext string) {
+	for i := index; ; i++ {
+		if err := os.Remove(slideImagePath(dir, i, ext)); err != nil {
+			break
+		}
 	}
+}
+
+// isSlidePreviewName reports whether a file name follows the slide-NNN.jpg pattern
+func isSlidePreviewName(name string) bool {
+	return isSlideImageName(name, "jpg")
+}
+
+// isSlideImageName reports whether a file name follows the slide-NNN.<ext> pattern
+func isSlideImageName(name, ext string) bool {
+	return strings.HasPrefix(name, "slide-") && strings.HasSuffix(name, "."+ext)
+}
 
-	// Find all generated JPEG files
-	jpegFiles, err := filepath.Glob(filepath.Join(slidesDir, "slide-*.jpg"))
+// listSlidePreviews returns the sorted JPEG slide previews in dir
+func listSlidePreviews(dir string) ([]string, error) {
+	return listSlideImages(dir, "jpg")
+}
+
+// listSlideImages returns the sorted slide-NNN.<ext> images in dir. It reads the directory
+// instead of globbing so directory names containing glob metacharacters work.
+func listSlideImages(dir, ext string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find JPEG files: %v", err)
+		return nil, fmt.Errorf("failed to find slide images: %v", err)
+	}
+	var images []string
+	for _, entry := range entries {
+		if !entry.IsDir() && isSlideImageName(entry.Name(), ext) {
+			images = append(images, filepath.Join(dir, entry.Name()))
+		}
 	}
+	sort.Strings(images)
+	return images, nil
+}
 
-	if len(jpegFiles) == 0 {
-		return nil, fmt.Errorf("no JPEG files were generated")
+// moveRenderedSlides moves slide images from the render directory into outputDir,
+// replacing existing files, and returns how many were moved
+func moveRenderedSlides(renderDir, outputDir, ext string) (int, error) {
+	rendered, err := listSlideImages(renderDir, ext)
+	if err != nil {
+		return 0, err
 	}
+	for _, source := range rendered {
+		target := filepath.Join(outputDir, filepath.Base(source))
+		// Rename fails across filesystems (temp dir on tmpfs), so fall back to copying
+		if err := os.Rename(source, target); err != nil {
+			if err := copyFile(source, target); err != nil {
+				return 0, fmt.Errorf("failed to move slide image: %v", err)
+			}
+		}
+	}
+	return len(rendered), nil
+}
+
+// slidePreviewPath returns the preview image path for a 0-based slide index
+func slidePreviewPath(dir string, index int) string {
+	return slideImagePath(dir, index, "jpg")
+}
+
+// slideImagePath returns the path of a 0-based slide's image with the given extension
+func slideImagePath(dir string, index int, ext string) string {
+	return filepath.Join(dir, fmt.Sprintf("slide-%03d.%s", index, ext))
+}
+
+// countSlidePreviews returns how many consecutive previews exist starting at slide-000.jpg
+func countSlidePreviews(dir string) int {
+	count := 0
+	for {
+		if _, err := os.Stat(slidePreviewPath(dir, count)); err != nil {
+			return count
+		}
+		count++
+	}
+}
+
+// shiftSlidePreviews renumbers the previews of slides at or after index from by delta,
+// so existing images line up with the deck after a slide is inserted or removed
+func shiftSlidePreviews(dir string, from, total, delta int) error {
+	if delta > 0 {
+		for i := total - 1; i >= from; i-- {
+			if err := os.Rename(slidePreviewPath(dir, i), slidePreviewPath(dir, i+delta)); err != nil {
+				return fmt.Errorf("failed to renumber slide preview: %v", err)
+			}
+		}
+		return nil
+	}
+	for i := from; i < total; i++ {
+		if err := os.Rename(slidePreviewPath(dir, i), slidePreviewPath(dir, i+delta)); err != nil {
+			return fmt.Errorf("failed to renumber slide preview: %v", err)
+		}
+	}
+	return nil
+}
+
+// copyFile copies a file's contents to dst, creating or truncating it
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
 
-	return jpegFiles, nil
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
 }
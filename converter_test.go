@@ -0,0 +1,361 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// awkwardNames exercise spaces, parentheses, brackets, percent signs, non-ASCII
+// characters, and upper-case extensions
+var awkwardNames = []string{
+	"Q3 Review (final).pptx",
+	"Überblick – Präsentation.pptx",
+	"会议 幻灯片.PPTX",
+	"deck [draft] 100%.pptx",
+}
+
+// handleSlideExport makes uno_export_slides.py write one placeholder JPEG per exported
+// slide into the render directory, like the real script
+func handleSlideExport(bridge *FakeUnoBridge, pages int) {
+	bridge.Handle("uno_export_slides.py", func(args []string) ([]byte, error) {
+		if _, err := os.Stat(args[0]); err != nil {
+			return []byte(fmt.Sprintf(`{"success": false, "error": %q}`, err.Error())), fmt.Errorf("exit status 1")
+		}
+		var numbers []int
+		if args[2] != "" {
+			for _, field := range strings.Split(args[2], ",") {
+				n, _ := strconv.Atoi(field)
+				numbers = append(numbers, n)
+			}
+		} else {
+			for n := 1; n <= pages; n++ {
+				numbers = append(numbers, n)
+			}
+		}
+		var options ExportOptions
+		json.Unmarshal([]byte(args[3]), &options)
+		for _, n := range numbers {
+			if n <= pages {
+				os.WriteFile(slideImagePath(args[1], n-1, options.Extension()), []byte(fmt.Sprintf("page %d", n)), 0644)
+			}
+		}
+		return []byte(fmt.Sprintf(`{"success": true, "total_slides": %d}`, pages)), nil
+	})
+}
+
+// exportedSlides returns the slide list passed to each uno_export_slides.py call, "all"
+// when no list was given
+func exportedSlides(bridge *FakeUnoBridge) []string {
+	var exported []string
+	for _, call := range bridge.Calls("uno_export_slides.py") {
+		if call.Args[2] != "" {
+			exported = append(exported, call.Args[2])
+		} else {
+			exported = append(exported, "all")
+		}
+	}
+	return exported
+}
+
+// rewritePart replaces one part of a presentation package
+func rewritePart(t *testing.T, presentationPath, partName string, edit func([]byte) []byte) {
+	t.Helper()
+	reader, err := zip.OpenReader(presentationPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, file := range reader.File {
+		in, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(in)
+		in.Close()
+		if file.Name == partName {
+			data = edit(data)
+		}
+		out, _ := writer.Create(file.Name)
+		out.Write(data)
+	}
+	reader.Close()
+	writer.Close()
+	if err := os.WriteFile(presentationPath, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConvertPPTXToJPEGHandlesAwkwardPaths(t *testing.T) {
+	for _, name := range awkwardNames {
+		t.Run(name, func(t *testing.T) {
+			env := newTestEnv(t)
+			handleSlideExport(env.uno, 2)
+			dir := filepath.Join(env.dir, "My Decks (2024) [shared]")
+			os.MkdirAll(dir, 0755)
+			pptxPath := filepath.Join(dir, name)
+			if err := copyFile(filepath.Join(fixtureDir, "two_slides.pptx"), pptxPath); err != nil {
+				t.Fatal(err)
+			}
+
+			outputDir := filepath.Join(env.dir, "out [1] %d")
+			slides, err := ConvertPPTXToJPEG(context.Background(), env.uno, pptxPath, outputDir)
+			if err != nil {
+				t.Fatalf("conversion failed: %v", err)
+			}
+			if len(slides) != 2 || filepath.Base(slides[1]) != "slide-001.jpg" {
+				t.Fatalf("unexpected slides: %v", slides)
+			}
+
+			slides, err = ConvertSlideRangeToJPEG(context.Background(), env.uno, pptxPath, outputDir, 1, 1)
+			if err != nil {
+				t.Fatalf("range conversion failed: %v", err)
+			}
+			if len(slides) != 2 {
+				t.Fatalf("unexpected slides after range conversion: %v", slides)
+			}
+		})
+	}
+}
+
+func TestConvertSlideRangeExportsOnlyThoseSlides(t *testing.T) {
+	env := newTestEnv(t)
+	handleSlideExport(env.uno, 2)
+	path := env.loadFixture(t, "two_slides.pptx")
+
+	if _, err := ConvertSlideRangeToJPEG(context.Background(), env.uno, path, "slides", 1, 1); err != nil {
+		t.Fatalf("range conversion failed: %v", err)
+	}
+
+	if exported := exportedSlides(env.uno); len(exported) != 1 || exported[0] != "2" {
+		t.Fatalf("expected only slide 2 to be exported, got %v", exported)
+	}
+	if slides, _ := listSlidePreviews("slides"); len(slides) != 1 || filepath.Base(slides[0]) != "slide-001.jpg" {
+		t.Errorf("unexpected previews %v", slides)
+	}
+}
+
+func TestExportSkipsUnchangedSlides(t *testing.T) {
+	env := newTestEnv(t)
+	handleSlideExport(env.uno, 2)
+	path := env.loadFixture(t, "two_slides.pptx")
+
+	// The first export renders everything, the second finds nothing changed
+	for i := 0; i < 2; i++ {
+		if _, err := ConvertPPTXToJPEG(context.Background(), env.uno, path, "slides"); err != nil {
+			t.Fatalf("conversion failed: %v", err)
+		}
+	}
+	if exported := exportedSlides(env.uno); len(exported) != 1 || exported[0] != "1,2" {
+		t.Fatalf("expected one export of both slides, got %v", exported)
+	}
+
+	// Changing slide 2 re-renders only slide 2, for full and range exports alike
+	rewritePart(t, path, "ppt/slides/slide2.xml", func(data []byte) []byte {
+		return bytes.Replace(data, []byte("</p:sld>"), []byte("<!-- edited --></p:sld>"), 1)
+	})
+	if _, err := ConvertPPTXToJPEG(context.Background(), env.uno, path, "slides"); err != nil {
+		t.Fatalf("conversion failed: %v", err)
+	}
+	if _, err := ConvertSlideRangeToJPEG(context.Background(), env.uno, path, "slides", 0, 1); err != nil {
+		t.Fatalf("range conversion failed: %v", err)
+	}
+	if exported := exportedSlides(env.uno); len(exported) != 2 || exported[1] != "2" {
+		t.Fatalf("expected only slide 2 to be re-exported once, got %v", exported)
+	}
+
+	// A missing preview is rendered again even though its slide is unchanged
+	os.Remove(slidePreviewPath("slides", 0))
+	if _, err := ConvertPPTXToJPEG(context.Background(), env.uno, path, "slides"); err != nil {
+		t.Fatalf("conversion failed: %v", err)
+	}
+	if exported := exportedSlides(env.uno); len(exported) != 3 || exported[2] != "1" {
+		t.Errorf("expected the missing preview to be re-exported, got %v", exported)
+	}
+}
+
+func TestExportOptionsChangeFormatAndInvalidateChecksums(t *testing.T) {
+	env := newTestEnv(t)
+	handleSlideExport(env.uno, 2)
+	path := env.loadFixture(t, "two_slides.pptx")
+
+	thumbnails := ExportOptions{Format: "png", MaxWidth: 320}
+	images, err := ExportSlideImages(context.Background(), env.uno, path, "exports", 0, math.MaxInt, thumbnails)
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if len(images) != 2 || filepath.Base(images[0]) != "slide-000.png" {
+		t.Fatalf("expected PNG images, got %v", images)
+	}
+
+	var options ExportOptions
+	json.Unmarshal([]byte(env.uno.Calls("uno_export_slides.py")[0].Args[3]), &options)
+	if options != (ExportOptions{Format: "png", DPI: 150, Quality: 90, MaxWidth: 320}) {
+		t.Errorf("expected defaults filled in, got %+v", options)
+	}
+
+	// The same options reuse the images, different ones render again
+	ExportSlideImages(context.Background(), env.uno, path, "exports", 0, math.MaxInt, thumbnails)
+	ExportSlideImages(context.Background(), env.uno, path, "exports", 0, math.MaxInt, ExportOptions{Format: "png", MaxWidth: 640})
+	if exported := exportedSlides(env.uno); len(exported) != 2 {
+		t.Errorf("expected a second export only for the new options, got %v", exported)
+	}
+}
+
+func TestExportOptionsValidate(t *testing.T) {
+	for _, options := range []ExportOptions{
+		{Format: "gif"},
+		{DPI: 5000},
+		{Quality: 101},
+		{MaxWidth: -1},
+	} {
+		if err := options.WithDefaults().Validate(); err == nil {
+			t.Errorf("expected %+v to be rejected", options)
+		}
+	}
+	if got := (ExportOptions{Format: "JPG"}).WithDefaults(); got != previewExportOptions {
+		t.Errorf("expected jpg to normalize to the preview options, got %+v", got)
+	}
+}
+
+func TestSlideChecksumsFollowLayoutChanges(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+
+	before, err := slideChecksums(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewritePart(t, path, "ppt/slideLayouts/slideLayout1.xml", func(data []byte) []byte {
+		return append(data, ' ')
+	})
+	after, err := slideChecksums(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed := 0
+	for i := range before {
+		if before[i] != after[i] {
+			changed++
+		}
+	}
+	if changed == 0 {
+		t.Error("expected slides using the edited layout to get new checksums")
+	}
+}
+
+func TestConvertPPTXToJPEGRemovesStalePreviews(t *testing.T) {
+	env := newTestEnv(t)
+	handleSlideExport(env.uno, 2)
+	path := env.loadFixture(t, "two_slides.pptx")
+	os.MkdirAll("slides", 0755)
+	for i := 0; i < 5; i++ {
+		os.WriteFile(slidePreviewPath("slides", i), []byte("old"), 0644)
+	}
+
+	slides, err := ConvertPPTXToJPEG(context.Background(), env.uno, path, "slides")
+	if err != nil {
+		t.Fatalf("conversion failed: %v", err)
+	}
+	if len(slides) != 2 {
+		t.Errorf("expected previews from the longer deck to be removed, got %v", slides)
+	}
+}
+
+func TestConvertPPTXToJPEGReportsExportFailure(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Handle("uno_export_slides.py", func(args []string) ([]byte, error) {
+		return []byte(`{"success": false, "error": "Could not connect to LibreOffice. Make sure it's running with UNO socket."}`), fmt.Errorf("exit status 1")
+	})
+
+	_, err := ConvertPPTXToJPEG(context.Background(), env.uno, path, "slides")
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) || toolErr.Code != ErrCodeUnoConnection {
+		t.Errorf("expected a UNO connection error, got %v", err)
+	}
+}
+
+func TestToolsHandleAwkwardPresentationNames(t *testing.T) {
+	for _, name := range awkwardNames {
+		t.Run(name, func(t *testing.T) {
+			env := newTestEnv(t)
+			path := filepath.Join(env.dir, name)
+			if err := copyFile(filepath.Join(fixtureDir, "two_slides.pptx"), path); err != nil {
+				t.Fatal(err)
+			}
+			env.app.setPresentationPath(path)
+			env.uno.Respond("uno_edit_slide.py", `{"success": true}`)
+
+			result := env.app.aiAgent.executeTool(context.Background(), "toolu_1", "edit_slide_text",
+				[]byte(`{"slide_number": 1, "target_type": "shape_index", "target_value": "0", "new_text": "Hi"}`))
+			if result.OfToolResult.IsError.Value {
+				t.Fatalf("edit failed: %s", result.OfToolResult.Content[0].OfText.Text)
+			}
+
+			calls := env.uno.Calls("uno_edit_slide.py")
+			if len(calls) != 1 || calls[0].Args[0] != path {
+				t.Fatalf("expected the script to receive %q unchanged, got %+v", path, calls)
+			}
+			if !strings.Contains(result.OfToolResult.Content[0].OfText.Text, `"verified":true`) {
+				t.Errorf("expected a passing integrity check, got %s", result.OfToolResult.Content[0].OfText.Text)
+			}
+		})
+	}
+}
+
+func TestExportReportsProgressPerSlide(t *testing.T) {
+	env := newTestEnv(t)
+	env.app.converter = UnoConverter{Bridge: env.uno}
+	path := env.loadFixture(t, "two_slides.pptx")
+	// Render slowly enough for the progress poll to see each page appear
+	env.uno.Handle("uno_export_slides.py", func(args []string) ([]byte, error) {
+		for n := 1; n <= 2; n++ {
+			os.WriteFile(slidePreviewPath(args[1], n-1), []byte(fmt.Sprintf("page %d", n)), 0644)
+			time.Sleep(2 * progressPollInterval)
+		}
+		return []byte(`{"success": true, "total_slides": 2}`), nil
+	})
+
+	ctx := withToolCall(context.Background(), "toolu_1", "export_slides")
+	if _, err := convertSlides(ctx, env.app, path, "slides"); err != nil {
+		t.Fatal(err)
+	}
+
+	var progress []ConversionProgress
+	for _, message := range env.events.Messages("conversion-progress") {
+		progress = append(progress, message.(ConversionProgress))
+	}
+	if len(progress) < 3 {
+		t.Fatalf("expected a start, per-slide and final event, got %+v", progress)
+	}
+	if first := progress[0]; first.Stage != "rendering" || first.Slide != 0 || first.Total != 2 {
+		t.Errorf("unexpected first event %+v", first)
+	}
+	sawHalf := false
+	for _, p := range progress {
+		if p.ToolID != "toolu_1" || p.Tool != "export_slides" || p.PresentationPath != path {
+			t.Errorf("expected events to name the tool call and presentation, got %+v", p)
+		}
+		sawHalf = sawHalf || (p.Stage == "rendering" && p.Slide == 1 && p.Percent == 50)
+	}
+	if !sawHalf {
+		t.Errorf("expected a 1 of 2 event, got %+v", progress)
+	}
+	if last := progress[len(progress)-1]; last.Stage != "done" || last.Percent != 100 {
+		t.Errorf("unexpected last event %+v", last)
+	}
+}
@@ -0,0 +1,489 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// dataFileExtensions are the spreadsheet formats import_data reads
+var dataFileExtensions = []string{".csv", ".tsv", ".txt", ".xlsx", ".xlsm"}
+
+// dataImportTargets are the shapes import_data fills
+var dataImportTargets = []string{"table", "chart"}
+
+// ImportDataDefinition defines the import_data tool
+var ImportDataDefinition = ToolDefinition{
+	Name: "import_data",
+	Description: `Read a CSV or Excel (.xlsx) file and put its data on a slide as a table or a native chart, new or replacing the data of an existing one, in one call.
+
+target is 'table' or 'chart'. Without shape_id or shape_index a new table or chart is inserted, placed like insert_table and insert_chart place them; with one, that table is resized to the data and filled, or that chart's categories and series are replaced (title and chart type stay unless title is given).
+
+Choosing the data:
+- sheet: worksheet name of an .xlsx file (defaults to the first); range: a block such as 'B2:F20' (defaults to everything)
+- has_header: whether the first row holds column names (defaults to true); transpose swaps rows and columns first, for sheets with one record per column
+- columns: the columns to use and their order, by header name or column letter ('A' is the first column of range); defaults to all
+- where: keep only rows whose column equals the value, e.g. {"Quarter": "Q3"}
+- rename: new names for columns, e.g. {"rev_q3": "Revenue"}; the table header and chart series names use them
+For charts, category_column gives the labels (defaults to the first column) and every other column is a series of numbers; '$', '%', thousands separators, decimal commas and (negative) parentheses are understood and empty cells count as 0. Excel dates and percentages are read as Excel shows them; other numbers keep their stored value.`,
+	InputSchema: ImportDataInputSchema,
+	Function:    ImportData,
+	Mutating:    true,
+	Screenshot:  true,
+}
+
+type ImportDataInput struct {
+	PresentationPath string            `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int               `json:"slide_number" jsonschema_description:"Slide to put the data on (1-based indexing)"`
+	FilePath         string            `json:"file_path" jsonschema_description:"Path to the .csv, .tsv or .xlsx file"`
+	Target           string            `json:"target" jsonschema_description:"'table' or 'chart'"`
+	ShapeID          string            `json:"shape_id,omitempty" jsonschema_description:"(Optional) shape_id of an existing table or chart to fill instead of inserting a new one"`
+	ShapeIndex       *int              `json:"shape_index,omitempty" jsonschema_description:"(Optional) Index of an existing table or chart; used when shape_id is not given"`
+	Sheet            string            `json:"sheet,omitempty" jsonschema_description:"(Optional) Worksheet of an .xlsx file, defaults to the first"`
+	Range            string            `json:"range,omitempty" jsonschema_description:"(Optional) Block of cells to read, e.g. 'B2:F20'"`
+	HasHeader        *bool             `json:"has_header,omitempty" jsonschema_description:"(Optional) Whether the first row holds column names, defaults to true"`
+	Transpose        bool              `json:"transpose,omitempty" jsonschema_description:"(Optional) Swap rows and columns before anything else"`
+	Columns          []string          `json:"columns,omitempty" jsonschema_description:"(Optional) Columns to use in order, by header name or column letter"`
+	Where            map[string]string `json:"where,omitempty" jsonschema_description:"(Optional) Keep only rows whose column equals the value, e.g. {\"Quarter\": \"Q3\"}"`
+	Rename           map[string]string `json:"rename,omitempty" jsonschema_description:"(Optional) New display names by column name or letter"`
+	CategoryColumn   string            `json:"category_column,omitempty" jsonschema_description:"(Optional) Charts: column with the category labels, defaults to the first column"`
+	ChartType        string            `json:"chart_type,omitempty" jsonschema_description:"(Optional) New charts: 'column', 'bar', 'line', 'area' or 'pie', defaults to 'column'"`
+	Title            *string           `json:"title,omitempty" jsonschema_description:"(Optional) Chart title; for an existing chart, empty removes it"`
+	HideLegend       bool              `json:"hide_legend,omitempty" jsonschema_description:"(Optional) New charts: leave out the legend"`
+	X                *float64          `json:"x,omitempty" jsonschema_description:"(Optional) New shapes: left position in inches"`
+	Y                *float64          `json:"y,omitempty" jsonschema_description:"(Optional) New shapes: top position in inches"`
+	Width            *float64          `json:"width,omitempty" jsonschema_description:"(Optional) New shapes: width in inches"`
+	Height           *float64          `json:"height,omitempty" jsonschema_description:"(Optional) New shapes: height in inches"`
+}
+
+var ImportDataInputSchema = GenerateSchema[ImportDataInput]()
+
+// dataTable is the block of cells chosen from a data file. Columns can be named by their
+// display header (after rename), their name in the file or their letter.
+type dataTable struct {
+	Headers []string
+	names   []string
+	letters []string
+	Rows    [][]string
+}
+
+func ImportData(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	importInput := ImportDataInput{}
+	if err := json.Unmarshal(input, &importInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if importInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			importInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if importInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if !slices.Contains(dataImportTargets, importInput.Target) {
+		return "", NewToolError(ErrCodeInvalidInput, "target must be 'table' or 'chart'")
+	}
+	if importInput.ChartType == "" {
+		importInput.ChartType = "column"
+	}
+	if !slices.Contains(chartTypes, importInput.ChartType) {
+		return "", NewToolError(ErrCodeInvalidInput, "unknown chart_type %q, must be one of %s", importInput.ChartType, strings.Join(chartTypes, ", ")).
+			WithDetail("valid_chart_types", chartTypes)
+	}
+	if (importInput.Width != nil && *importInput.Width <= 0) || (importInput.Height != nil && *importInput.Height <= 0) {
+		return "", NewToolError(ErrCodeInvalidInput, "width and height must be greater than 0")
+	}
+
+	if importInput.FilePath == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "file_path is required")
+	}
+	filePath, err := existingFile(importInput.FilePath, "data")
+	if err != nil {
+		return "", err
+	}
+	cells, err := readDataFile(filePath, importInput.Sheet)
+	if err != nil {
+		return "", err
+	}
+	table, err := selectData(cells, importInput)
+	if err != nil {
+		return "", err
+	}
+
+	shapeIndex := -1
+	if importInput.ShapeID != "" {
+		if shapeIndex, err = resolveShapeID(ctx, app, importInput.PresentationPath, importInput.SlideNumber, importInput.ShapeID); err != nil {
+			return "", err
+		}
+	} else if importInput.ShapeIndex != nil {
+		if shapeIndex = *importInput.ShapeIndex; shapeIndex < 0 {
+			return "", NewToolError(ErrCodeInvalidInput, "shape_index must be 0 or greater")
+		}
+	}
+
+	fmt.Printf("Importing %d rows from %s onto slide %d\n", len(table.Rows), filePath, importInput.SlideNumber)
+
+	var result *EditResult
+	var warnings []string
+	if importInput.Target == "table" {
+		result, err = importTable(ctx, app, importInput, shapeIndex, table)
+	} else {
+		result, warnings, err = importChart(ctx, app, importInput, shapeIndex, table)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if result.Details == nil {
+		result.Details = map[string]interface{}{}
+	}
+	result.Details["source"] = filePath
+	result.Details["imported_rows"] = len(table.Rows)
+	result.Details["imported_columns"] = table.Headers
+	if len(warnings) > 0 {
+		result.Details["warnings"] = warnings
+	}
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, importInput.PresentationPath, importInput.SlideNumber)
+
+	return marshalResult(result)
+}
+
+// importTable inserts a table holding the data, or resizes and fills an existing one
+func importTable(ctx context.Context, app *App, importInput ImportDataInput, shapeIndex int, table dataTable) (*EditResult, error) {
+	data := table.Rows
+	if importInput.HasHeader == nil || *importInput.HasHeader {
+		data = append([][]string{table.Headers}, data...)
+	}
+	if len(data) > maxTableRows || len(table.Headers) > maxTableColumns {
+		return nil, NewToolError(ErrCodeInvalidInput, "the data has %d rows and %d columns but a table takes at most %d and %d; narrow it down with range, columns or where", len(data), len(table.Headers), maxTableRows, maxTableColumns)
+	}
+	dataJSON, _ := json.Marshal(data)
+
+	var output []byte
+	var err error
+	if shapeIndex >= 0 {
+		output, err = runUnoScript(ctx, app, "uno_fill_table.py", importInput.PresentationPath,
+			fmt.Sprintf("%d", importInput.SlideNumber), fmt.Sprintf("%d", shapeIndex), string(dataJSON))
+	} else {
+		args := []string{
+			importInput.PresentationPath,
+			fmt.Sprintf("%d", importInput.SlideNumber),
+			fmt.Sprintf("%d", len(data)),
+			fmt.Sprintf("%d", len(table.Headers)),
+		}
+		args = append(args, optionalFloatArgs(importInput.X, importInput.Y, importInput.Width, importInput.Height)...)
+		output, err = runUnoScript(ctx, app, "uno_insert_table.py", append(args, string(dataJSON))...)
+	}
+	if err != nil {
+		return nil, scriptError("failed to import data into a table", err, output)
+	}
+	return editResultFromScript(output)
+}
+
+// importChart inserts a chart of the data, or replaces the data of an existing one
+func importChart(ctx context.Context, app *App, importInput ImportDataInput, shapeIndex int, table dataTable) (*EditResult, []string, error) {
+	categoryColumn := 0
+	if importInput.CategoryColumn != "" {
+		index, err := table.column(importInput.CategoryColumn)
+		if err != nil {
+			return nil, nil, err
+		}
+		categoryColumn = index
+	}
+	if len(table.Headers) < 2 {
+		return nil, nil, NewToolError(ErrCodeInvalidInput, "a chart needs a category column and at least one column of values")
+	}
+
+	var categories []string
+	for _, row := range table.Rows {
+		categories = append(categories, row[categoryColumn])
+	}
+	var series []ChartSeries
+	var warnings []string
+	for column, name := range table.Headers {
+		if column == categoryColumn {
+			continue
+		}
+		s := ChartSeries{Name: name}
+		for i, row := range table.Rows {
+			value, ok := parseDataNumber(row[column])
+			if !ok {
+				return nil, nil, NewToolError(ErrCodeInvalidInput, "%q in column %q, row %d is not a number; choose the value columns with columns", row[column], name, i+1)
+			}
+			if strings.TrimSpace(row[column]) == "" {
+				warnings = append(warnings, fmt.Sprintf("empty cell in column %q, row %d counted as 0", name, i+1))
+			}
+			s.Values = append(s.Values, value)
+		}
+		series = append(series, s)
+	}
+	if err := validateChartData(importInput.ChartType, categories, series); err != nil {
+		return nil, nil, err
+	}
+
+	var output []byte
+	var err error
+	if shapeIndex >= 0 {
+		changes := map[string]interface{}{"categories": categories, "series": series}
+		if importInput.Title != nil {
+			changes["title"] = *importInput.Title
+		}
+		changesJSON, _ := json.Marshal(changes)
+		output, err = runUnoScript(ctx, app, "uno_edit_chart_data.py", importInput.PresentationPath,
+			fmt.Sprintf("%d", importInput.SlideNumber), fmt.Sprintf("%d", shapeIndex), string(changesJSON))
+	} else {
+		title := ""
+		if importInput.Title != nil {
+			title = *importInput.Title
+		}
+		chartJSON, _ := json.Marshal(map[string]interface{}{
+			"categories": categories,
+			"series":     series,
+			"title":      title,
+			"legend":     !importInput.HideLegend,
+		})
+		args := []string{
+			importInput.PresentationPath,
+			fmt.Sprintf("%d", importInput.SlideNumber),
+			importInput.ChartType,
+			string(chartJSON),
+		}
+		args = append(args, optionalFloatArgs(importInput.X, importInput.Y, importInput.Width, importInput.Height)...)
+		output, err = runUnoScript(ctx, app, "uno_insert_chart.py", args...)
+	}
+	if err != nil {
+		return nil, nil, scriptError("failed to import data into a chart", err, output)
+	}
+	result, err := editResultFromScript(output)
+	return result, warnings, err
+}
+
+// readDataFile reads the cells of a CSV/TSV file or of an .xlsx worksheet
+func readDataFile(filePath, sheet string) ([][]string, error) {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if !slices.Contains(dataFileExtensions, ext) {
+		return nil, NewToolError(ErrCodeInvalidInput, "can't read %s files; use CSV, TSV or .xlsx (save older .xls workbooks as .xlsx)", ext)
+	}
+
+	if ext == ".xlsx" || ext == ".xlsm" {
+		workbook, err := openXLSX(filePath)
+		if err != nil {
+			return nil, NewToolError(ErrCodeInvalidInput, "failed to read workbook: %v", err)
+		}
+		defer workbook.Close()
+		if sheet != "" && !slices.ContainsFunc(workbook.SheetNames(), func(name string) bool { return strings.EqualFold(name, sheet) }) {
+			return nil, NewToolError(ErrCodeInvalidInput, "no sheet named %q", sheet).WithDetail("sheets", workbook.SheetNames())
+		}
+		cells, err := workbook.ReadSheet(sheet)
+		if err != nil {
+			return nil, NewToolError(ErrCodeInvalidInput, "failed to read sheet: %v", err)
+		}
+		return cells, nil
+	}
+
+	if sheet != "" {
+		return nil, NewToolError(ErrCodeInvalidInput, "sheet only applies to .xlsx files")
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, NewToolError(ErrCodeFileNotFound, "failed to read data file: %v", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = sniffDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	cells, err := reader.ReadAll()
+	if err != nil {
+		return nil, NewToolError(ErrCodeInvalidInput, "failed to parse %s: %v", filepath.Base(filePath), err)
+	}
+	width := 0
+	for _, row := range cells {
+		width = max(width, len(row))
+	}
+	for i := range cells {
+		for len(cells[i]) < width {
+			cells[i] = append(cells[i], "")
+		}
+	}
+	return cells, nil
+}
+
+// sniffDelimiter picks the field separator of delimited text from its first line: tab,
+// semicolon (common in locales with decimal commas) or comma
+func sniffDelimiter(data []byte) rune {
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	delimiter, most := ',', bytes.Count(line, []byte(","))
+	for _, candidate := range []rune{'\t', ';'} {
+		if n := bytes.Count(line, []byte(string(candidate))); n > most {
+			delimiter, most = candidate, n
+		}
+	}
+	return delimiter
+}
+
+// selectData applies the range, transpose, header, where, rename and columns options to
+// the cells of a data file
+func selectData(cells [][]string, importInput ImportDataInput) (dataTable, error) {
+	if importInput.Range != "" {
+		first, last, ok := strings.Cut(importInput.Range, ":")
+		firstColumn, firstRow, firstErr := parseCellRef(strings.TrimSpace(first))
+		lastColumn, lastRow, lastErr := parseCellRef(strings.TrimSpace(last))
+		if !ok || firstErr != nil || lastErr != nil {
+			return dataTable{}, NewToolError(ErrCodeInvalidInput, "range must be a block of cells such as 'B2:F20', got %q", importInput.Range)
+		}
+		cells = cropCells(cells, firstRow, lastRow, firstColumn, lastColumn)
+	}
+	if importInput.Transpose {
+		cells = transposeCells(cells)
+	}
+	for len(cells) > 0 && isEmptyRow(cells[0]) {
+		cells = cells[1:]
+	}
+	if len(cells) == 0 || len(cells[0]) == 0 {
+		return dataTable{}, NewToolError(ErrCodeInvalidInput, "no data found in the file; check range and sheet")
+	}
+
+	table := dataTable{letters: make([]string, len(cells[0]))}
+	for i := range table.letters {
+		table.letters[i] = columnLetter(i)
+	}
+	if importInput.HasHeader == nil || *importInput.HasHeader {
+		for _, name := range cells[0] {
+			table.names = append(table.names, strings.TrimSpace(name))
+		}
+		cells = cells[1:]
+	} else {
+		table.names = slices.Clone(table.letters)
+	}
+	table.Headers = slices.Clone(table.names)
+	for _, row := range cells {
+		if !isEmptyRow(row) {
+			table.Rows = append(table.Rows, row)
+		}
+	}
+
+	for name, value := range importInput.Where {
+		column, err := table.column(name)
+		if err != nil {
+			return dataTable{}, err
+		}
+		table.Rows = slices.DeleteFunc(table.Rows, func(row []string) bool {
+			return !strings.EqualFold(strings.TrimSpace(row[column]), strings.TrimSpace(value))
+		})
+	}
+	if len(table.Rows) == 0 {
+		return dataTable{}, NewToolError(ErrCodeInvalidInput, "no rows left to import; check where and range")
+	}
+
+	for name, newName := range importInput.Rename {
+		column, err := table.column(name)
+		if err != nil {
+			return dataTable{}, err
+		}
+		table.Headers[column] = newName
+	}
+
+	if len(importInput.Columns) > 0 {
+		picked := dataTable{Rows: make([][]string, len(table.Rows))}
+		for _, name := range importInput.Columns {
+			column, err := table.column(name)
+			if err != nil {
+				return dataTable{}, err
+			}
+			picked.Headers = append(picked.Headers, table.Headers[column])
+			picked.names = append(picked.names, table.names[column])
+			picked.letters = append(picked.letters, table.letters[column])
+			for i, row := range table.Rows {
+				picked.Rows[i] = append(picked.Rows[i], row[column])
+			}
+		}
+		table = picked
+	}
+	return table, nil
+}
+
+// column returns the index of a column by its header, its name in the file or its letter,
+// ignoring case
+func (t dataTable) column(name string) (int, error) {
+	name = strings.TrimSpace(name)
+	for _, candidates := range [][]string{t.Headers, t.names, t.letters} {
+		if index := slices.IndexFunc(candidates, func(candidate string) bool { return strings.EqualFold(candidate, name) }); index >= 0 {
+			return index, nil
+		}
+	}
+	return 0, NewToolError(ErrCodeInvalidInput, "no column named %q", name).WithDetail("columns", t.Headers)
+}
+
+// cropCells returns the block of cells between two corners, inclusive, in either order
+func cropCells(cells [][]string, firstRow, lastRow, firstColumn, lastColumn int) [][]string {
+	firstRow, lastRow = min(firstRow, lastRow), max(firstRow, lastRow)
+	firstColumn, lastColumn = min(firstColumn, lastColumn), max(firstColumn, lastColumn)
+	var block [][]string
+	for row := firstRow; row <= lastRow && row < len(cells); row++ {
+		values := make([]string, lastColumn-firstColumn+1)
+		for column := firstColumn; column <= lastColumn && column < len(cells[row]); column++ {
+			values[column-firstColumn] = cells[row][column]
+		}
+		block = append(block, values)
+	}
+	return block
+}
+
+// transposeCells swaps the rows and columns of a block of cells
+func transposeCells(cells [][]string) [][]string {
+	if len(cells) == 0 {
+		return cells
+	}
+	transposed := make([][]string, len(cells[0]))
+	for column := range transposed {
+		transposed[column] = make([]string, len(cells))
+		for row := range cells {
+			transposed[column][row] = cells[row][column]
+		}
+	}
+	return transposed
+}
+
+// parseDataNumber reads a spreadsheet number such as "1,234.5", "1.234,5", "$12", "45%" or
+// "(300)"; blank cells read as 0
+func parseDataNumber(text string) (float64, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, true
+	}
+	negative := strings.HasPrefix(text, "(") && strings.HasSuffix(text, ")")
+	if negative {
+		text = text[1 : len(text)-1]
+	}
+	text = strings.TrimSuffix(strings.TrimSpace(text), "%")
+	text = strings.TrimLeft(text, "$€£¥ ")
+	if comma, dot := strings.LastIndex(text, ","), strings.LastIndex(text, "."); comma > dot && (dot >= 0 || len(text)-comma-1 != 3) {
+		// A comma after the last dot, or not followed by three digits, is a decimal comma
+		text = strings.ReplaceAll(text[:comma], ".", "") + "." + text[comma+1:]
+	}
+	text = strings.ReplaceAll(text, ",", "")
+	value, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil {
+		return 0, false
+	}
+	if negative {
+		value = -value
+	}
+	return value, true
+}
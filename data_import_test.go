@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadXLSX(t *testing.T) {
+	workbook, err := openXLSX(filepath.Join(fixtureDir, "sales.xlsx"))
+	if err != nil {
+		t.Fatalf("openXLSX failed: %v", err)
+	}
+	defer workbook.Close()
+
+	if names := workbook.SheetNames(); fmt.Sprint(names) != "[Notes Sales]" {
+		t.Errorf("unexpected sheets: %v", names)
+	}
+	notes, err := workbook.ReadSheet("")
+	if err != nil || len(notes) != 1 || notes[0][0] != "Figures are in $K" {
+		t.Errorf("expected the first sheet by default, got %q (%v)", notes, err)
+	}
+
+	rows, err := workbook.ReadSheet("sales")
+	if err != nil {
+		t.Fatalf("ReadSheet failed: %v", err)
+	}
+	want := [][]string{
+		{"Region", "Quarter", "Revenue", "Growth", "Updated"},
+		{"North", "Q3", "1200.5", "12.5%", "2024-09-30"},
+		{"South", "Q3", "980", "30%", "2024-09-30"},
+		{"", "", "", "", ""},
+		{"North", "Q4", "1350", "5%", "2024-12-31"},
+		{"West", "Q3", "1000.3", "", "2024-09-30"},
+	}
+	if fmt.Sprintf("%q", rows) != fmt.Sprintf("%q", want) {
+		t.Errorf("ReadSheet = %q, want %q", rows, want)
+	}
+
+	if _, err := workbook.ReadSheet("Budget"); err == nil {
+		t.Error("expected an error for a missing sheet")
+	}
+}
+
+func TestReadXLSXBoundsSparseSheets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sparse.xlsx")
+	data, err := os.ReadFile(filepath.Join(fixtureDir, "sales.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(path, data, 0644)
+	setNotes := func(cells string) {
+		rewritePart(t, path, "xl/worksheets/sheet1.xml", func([]byte) []byte {
+			return []byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + cells + `</sheetData></worksheet>`)
+		})
+	}
+
+	// Formatted empty cells in the last row and column don't widen the grid
+	setNotes(`<row r="1"><c r="A1" t="inlineStr"><is><t>Region</t></is></c><c r="C1" t="inlineStr"><is><t> </t></is></c></row>` +
+		`<row r="1048576"><c r="XFD1048576" s="1"/></row>`)
+	workbook, err := openXLSX(path)
+	if err != nil {
+		t.Fatalf("openXLSX failed: %v", err)
+	}
+	rows, err := workbook.ReadSheet("")
+	workbook.Close()
+	if err != nil || fmt.Sprintf("%q", rows) != `[["Region"]]` {
+		t.Errorf("expected trailing empty rows and columns dropped, got %q (%v)", rows, err)
+	}
+
+	// A value far out is refused rather than read into a 1048576 x 16384 grid
+	setNotes(`<row r="1"><c r="A1" t="inlineStr"><is><t>Region</t></is></c></row>` +
+		`<row r="1048576"><c r="XFD1048576"><v>1</v></c></row>`)
+	workbook, err = openXLSX(path)
+	if err != nil {
+		t.Fatalf("openXLSX failed: %v", err)
+	}
+	defer workbook.Close()
+	if _, err := workbook.ReadSheet(""); toolErrorCode(err) != ErrCodeInvalidInput {
+		t.Errorf("expected %s for a sheet beyond the limits, got %v", ErrCodeInvalidInput, err)
+	}
+}
+
+func TestImportDataTable(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "mixed_shapes.pptx")
+	env.uno.Respond("uno_insert_table.py", `{"success": true, "slide_number": 1, "shape_index": 7, "shape_id": "Table 7"}`)
+	env.uno.Respond("uno_fill_table.py", `{"success": true, "slide_number": 1, "shape_index": 3, "rows": 3, "columns": 2}`)
+	workbook := filepath.Join(fixtureDir, "sales.xlsx")
+
+	input := fmt.Sprintf(`{"slide_number": 1, "file_path": %q, "target": "table", "sheet": "Sales", "where": {"Quarter": "q3"},
+		"columns": ["Region", "Sales", "D"], "rename": {"C": "Sales"}, "width": 6}`, workbook)
+	output, err := ImportData(context.Background(), env.app, json.RawMessage(input))
+	if err != nil {
+		t.Fatalf("ImportData failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_insert_table.py")
+	want := []string{path, "1", "4", "3", "", "", "6", "", `[["Region","Sales","Growth"],["North","1200.5","12.5%"],["South","980","30%"],["West","1000.3",""]]`}
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint(want) {
+		t.Fatalf("expected one call with %v, got %+v", want, calls)
+	}
+	var result EditResult
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.ShapeID != "Table 7" || result.Details["imported_rows"] != float64(3) {
+		t.Errorf("unexpected result: %s", output)
+	}
+
+	// An existing table is filled in place, here without a header row
+	input = fmt.Sprintf(`{"slide_number": 1, "file_path": %q, "target": "table", "shape_id": "Table 6", "sheet": "Sales",
+		"range": "A2:C3", "has_header": false}`, workbook)
+	if _, err := ImportData(context.Background(), env.app, json.RawMessage(input)); err != nil {
+		t.Fatalf("ImportData failed on an existing table: %v", err)
+	}
+	calls = env.uno.Calls("uno_fill_table.py")
+	want = []string{path, "1", "3", `[["North","Q3","1200.5"],["South","Q3","980"]]`}
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint(want) {
+		t.Fatalf("expected one call with %v, got %+v", want, calls)
+	}
+
+	for input, wantCode := range map[string]ToolErrorCode{
+		`{"slide_number": 1, "file_path": "missing.csv", "target": "table"}`:                                         ErrCodeFileNotFound,
+		fmt.Sprintf(`{"slide_number": 1, "file_path": %q, "target": "list"}`, workbook):                              ErrCodeInvalidInput,
+		fmt.Sprintf(`{"slide_number": 1, "file_path": %q, "target": "table", "sheet": "Budget"}`, workbook):          ErrCodeInvalidInput,
+		fmt.Sprintf(`{"slide_number": 1, "file_path": %q, "target": "table", "range": "A1-C3"}`, workbook):           ErrCodeInvalidInput,
+		fmt.Sprintf(`{"slide_number": 1, "file_path": %q, "target": "table", "columns": ["Profit"]}`, workbook):      ErrCodeInvalidInput,
+		fmt.Sprintf(`{"slide_number": 1, "file_path": %q, "target": "table", "where": {"Quarter": "Q1"}}`, workbook): ErrCodeInvalidInput,
+	} {
+		_, err := ImportData(context.Background(), env.app, json.RawMessage(input))
+		if code := toolErrorCode(err); code != wantCode {
+			t.Errorf("expected %s for %s, got %s (%v)", wantCode, input, code, err)
+		}
+	}
+}
+
+func TestImportDataChart(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_insert_chart.py", `{"success": true, "slide_number": 2, "shape_index": 2}`)
+	env.uno.Respond("uno_edit_chart_data.py", `{"success": true, "changed": true}`)
+
+	if err := os.WriteFile("regions.tsv", []byte("\xef\xbb\xbfRegion\tQ3\tQ4\tNotes\nNorth\t$1,200\t(50)\tgood\nSouth\t12%\t\tflat\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	input := `{"slide_number": 2, "file_path": "regions.tsv", "target": "chart", "chart_type": "line", "columns": ["Q3", "Q4", "Region"], "category_column": "Region", "title": "Sales"}`
+	output, err := ImportData(context.Background(), env.app, json.RawMessage(input))
+	if err != nil {
+		t.Fatalf("ImportData failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_insert_chart.py")
+	want := []string{path, "2", "line", `{"categories":["North","South"],"legend":true,"series":[{"name":"Q3","values":[1200,12]},{"name":"Q4","values":[-50,0]}],"title":"Sales"}`, "", "", "", ""}
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint(want) {
+		t.Fatalf("expected one call with %v, got %+v", want, calls)
+	}
+	var result EditResult
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatal(err)
+	}
+	if warnings, _ := result.Details["warnings"].([]interface{}); len(warnings) != 1 {
+		t.Errorf("expected a warning for the empty cell, got %s", output)
+	}
+
+	// Semicolon-separated files with decimal commas replace an existing chart's data
+	if err := os.WriteFile("regions.csv", []byte("Region;Revenue\nNorth;1.200,5\nSouth;980,25\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportData(context.Background(), env.app, json.RawMessage(`{"slide_number": 2, "file_path": "regions.csv", "target": "chart", "shape_index": 1}`)); err != nil {
+		t.Fatalf("ImportData failed on an existing chart: %v", err)
+	}
+	calls = env.uno.Calls("uno_edit_chart_data.py")
+	want = []string{path, "2", "1", `{"categories":["North","South"],"series":[{"name":"Revenue","values":[1200.5,980.25]}]}`}
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint(want) {
+		t.Fatalf("expected one call with %v, got %+v", want, calls)
+	}
+
+	for _, bad := range []string{
+		`{"slide_number": 2, "file_path": "regions.tsv", "target": "chart"}`,
+		`{"slide_number": 2, "file_path": "regions.tsv", "target": "chart", "columns": ["Region"]}`,
+		`{"slide_number": 2, "file_path": "regions.tsv", "target": "chart", "chart_type": "pie", "columns": ["Region", "Q3", "Q4"]}`,
+	} {
+		_, err := ImportData(context.Background(), env.app, json.RawMessage(bad))
+		if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+			t.Errorf("expected %s for %s, got %s (%v)", ErrCodeInvalidInput, bad, code, err)
+		}
+	}
+}
+
+func TestParseDataNumber(t *testing.T) {
+	for text, want := range map[string]float64{
+		"1,234.5":   1234.5,
+		"1.234,5":   1234.5,
+		"1,234":     1234,
+		"0,75":      0.75,
+		"$12":       12,
+		"€ 3,50":    3.5,
+		"45%":       45,
+		"(300)":     -300,
+		"-2.5e3":    -2500,
+		"  ":        0,
+		"1,200,000": 1200000,
+	} {
+		if got, ok := parseDataNumber(text); !ok || got != want {
+			t.Errorf("parseDataNumber(%q) = %v, %v, want %v", text, got, ok, want)
+		}
+	}
+	if _, ok := parseDataNumber("n/a"); ok {
+		t.Error("expected n/a not to be a number")
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// minFreeDiskSpace is the headroom kept free on top of what an operation needs
+const minFreeDiskSpace = 100 << 20
+
+// checkDiskSpace fails with DISK_SPACE_LOW when dir's filesystem cannot hold needed
+// bytes plus minFreeDiskSpace. Filesystems that can't report free space are not blocked.
+func checkDiskSpace(dir string, needed int64) error {
+	available, err := availableDiskSpace(dir)
+	if err != nil {
+		fmt.Printf("Warning: could not check free disk space in %s: %v\n", dir, err)
+		return nil
+	}
+
+	required := uint64(needed) + minFreeDiskSpace
+	if available < required {
+		return NewToolError(ErrCodeDiskSpaceLow, "not enough free disk space in %s: %s available, %s required",
+			dir, formatBytes(available), formatBytes(required)).
+			WithDetail("available_bytes", available).
+			WithDetail("required_bytes", required)
+	}
+	return nil
+}
+
+// fileSize returns the size of a file, or 0 if it can't be read
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// formatBytes renders a byte count for error messages
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// exportIdleWindow is how long the scheduler waits for more edits before exporting
+// when no AI turn is holding it
+const exportIdleWindow = 300 * time.Millisecond
+
+// ExportScheduler coalesces preview export requests. Edits record which slides need
+// re-rendering; while an AI turn holds the scheduler nothing is exported until the turn
+// ends, otherwise pending exports run once after a short idle window. Editing the same
+// slide five times in one turn therefore renders it once.
+type ExportScheduler struct {
+	mu      sync.Mutex
+	app     *App
+	held    int
+	pending map[string]*pendingExport // By presentation path
+	timer   *time.Timer
+}
+
+// pendingExport is the set of slides awaiting export for one presentation
+type pendingExport struct {
+	full   bool
+	slides map[int]bool // 1-based slide numbers
+}
+
+// NewExportScheduler creates a scheduler rendering through the app's converter
+func NewExportScheduler(app *App) *ExportScheduler {
+	return &ExportScheduler{
+		app:     app,
+		pending: make(map[string]*pendingExport),
+	}
+}
+
+// Request marks slides of a presentation as needing a fresh preview. With no slide
+// numbers the whole deck is re-exported.
+func (s *ExportScheduler) Request(presentationPath string, slideNumbers ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	export, ok := s.pending[presentationPath]
+	if !ok {
+		export = &pendingExport{slides: make(map[int]bool)}
+		s.pending[presentationPath] = export
+	}
+	if len(slideNumbers) == 0 {
+		export.full = true
+	}
+	for _, slideNumber := range slideNumbers {
+		export.slides[slideNumber] = true
+	}
+
+	if s.held == 0 {
+		s.scheduleLocked()
+	}
+}
+
+// Hold defers all exports until the matching Release, e.g. for the duration of an AI turn
+func (s *ExportScheduler) Hold() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.held++
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+}
+
+// Release ends a Hold and exports everything that was requested while it was held
+func (s *ExportScheduler) Release(ctx context.Context) {
+	s.mu.Lock()
+	s.held--
+	release := s.held == 0
+	s.mu.Unlock()
+
+	if release {
+		s.Flush(ctx)
+	}
+}
+
+// Forget drops pending exports for slides at or after the 0-based index, used when a
+// structural change re-renders the tail of the deck itself
+func (s *ExportScheduler) Forget(presentationPath string, fromIndex int) {
+	s.ForgetRange(presentationPath, fromIndex, math.MaxInt)
+}
+
+// ForgetRange drops pending exports for slides first..last (0-based, inclusive), used
+// when a change re-renders that range itself
+func (s *ExportScheduler) ForgetRange(presentationPath string, first, last int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	export, ok := s.pending[presentationPath]
+	if !ok {
+		return
+	}
+	for slideNumber := range export.slides {
+		if slideNumber-1 >= first && slideNumber-1 <= last {
+			delete(export.slides, slideNumber)
+		}
+	}
+	if first == 0 && last == math.MaxInt {
+		export.full = false
+	}
+	if !export.full && len(export.slides) == 0 {
+		delete(s.pending, presentationPath)
+	}
+}
+
+// Flush runs all pending exports now
+func (s *ExportScheduler) Flush(ctx context.Context) {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[string]*pendingExport)
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.mu.Unlock()
+
+	for presentationPath, export := range pending {
+		if err := s.export(ctx, presentationPath, export); err != nil {
+			fmt.Printf("Warning: Failed to export slide previews: %v\n", err)
+		}
+	}
+}
+
+// scheduleLocked (re)starts the idle timer; s.mu must be held
+func (s *ExportScheduler) scheduleLocked() {
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.timer = time.AfterFunc(exportIdleWindow, func() { s.Flush(context.Background()) })
+}
+
+// export renders one presentation's pending slides, as a single range covering them all
+func (s *ExportScheduler) export(ctx context.Context, presentationPath string, export *pendingExport) error {
+	if export.full || len(export.slides) == 0 {
+		fmt.Printf("Exporting all slide previews for %s\n", presentationPath)
+		_, err := convertSlides(ctx, s.app, presentationPath, "slides")
+		return err
+	}
+
+	slideNumbers := make([]int, 0, len(export.slides))
+	for slideNumber := range export.slides {
+		slideNumbers = append(slideNumbers, slideNumber)
+	}
+	sort.Ints(slideNumbers)
+
+	fmt.Printf("Exporting slide previews %v for %s\n", slideNumbers, presentationPath)
+	_, err := convertSlideRange(ctx, s.app, presentationPath, "slides", slideNumbers[0]-1, slideNumbers[len(slideNumbers)-1]-1)
+	return err
+}
+
+// schedulePreviewExport requests a coalesced preview export, exporting immediately
+// when the app has no scheduler
+func schedulePreviewExport(ctx context.Context, app *App, presentationPath string, slideNumbers ...int) {
+	if app != nil && app.exports != nil {
+		app.exports.Request(presentationPath, slideNumbers...)
+		return
+	}
+	if _, err := convertSlides(ctx, app, presentationPath, "slides"); err != nil {
+		fmt.Printf("Warning: Failed to export slide previews: %v\n", err)
+	}
+}
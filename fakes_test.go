@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// FakeUnoBridge answers UNO script calls from registered handlers instead of LibreOffice
+type FakeUnoBridge struct {
+	mu       sync.Mutex
+	handlers map[string]func(args []string) ([]byte, error)
+	calls    []FakeScriptCall
+}
+
+// FakeScriptCall records one script invocation
+type FakeScriptCall struct {
+	Script string
+	Args   []string
+}
+
+func NewFakeUnoBridge() *FakeUnoBridge {
+	bridge := &FakeUnoBridge{handlers: make(map[string]func(args []string) ([]byte, error))}
+	// Integrity checks report the slide count of the package on disk
+	bridge.Handle("uno_verify.py", func(args []string) ([]byte, error) {
+		slides, err := verifyPackage(args[0])
+		if err != nil {
+			return []byte(fmt.Sprintf(`{"success": false, "error": %q}`, err.Error())), fmt.Errorf("exit status 1")
+		}
+		return []byte(fmt.Sprintf(`{"success": true, "slide_count": %d}`, slides)), nil
+	})
+	return bridge
+}
+
+// Handle registers the handler for a script
+func (b *FakeUnoBridge) Handle(script string, handler func(args []string) ([]byte, error)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[script] = handler
+}
+
+// Respond registers a script that always prints the given JSON
+func (b *FakeUnoBridge) Respond(script, output string) {
+	b.Handle(script, func(args []string) ([]byte, error) {
+		return []byte(output), nil
+	})
+}
+
+func (b *FakeUnoBridge) Run(ctx context.Context, script string, args ...string) ([]byte, error) {
+	b.mu.Lock()
+	b.calls = append(b.calls, FakeScriptCall{Script: script, Args: args})
+	handler, ok := b.handlers[script]
+	b.mu.Unlock()
+
+	if !ok {
+		return []byte(fmt.Sprintf(`{"success": false, "error": "no fake handler for %s"}`, script)), fmt.Errorf("exit status 1")
+	}
+
+	// Like a killed subprocess, a cancelled call returns without waiting for the handler
+	type result struct {
+		output []byte
+		err    error
+	}
+	results := make(chan result, 1)
+	go func() {
+		output, err := handler(args)
+		results <- result{output, err}
+	}()
+	select {
+	case res := <-results:
+		return res.output, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Calls returns the invocations of a script
+func (b *FakeUnoBridge) Calls(script string) []FakeScriptCall {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var calls []FakeScriptCall
+	for _, call := range b.calls {
+		if call.Script == script {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// FakeConverter writes placeholder slide images instead of exporting through LibreOffice
+type FakeConverter struct {
+	mu         sync.Mutex
+	SlideCount int
+	Calls      []string
+	RangeCalls [][2]int
+	Exports    []ExportOptions
+}
+
+func (c *FakeConverter) ConvertToImages(ctx context.Context, pptxPath, outputDir string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Calls = append(c.Calls, pptxPath)
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, err
+	}
+	var slides []string
+	for i := 0; i < c.SlideCount; i++ {
+		slidePath := filepath.Join(outputDir, fmt.Sprintf("slide-%03d.jpg", i))
+		if err := os.WriteFile(slidePath, []byte("fake jpeg"), 0644); err != nil {
+			return nil, err
+		}
+		slides = append(slides, slidePath)
+	}
+	return slides, nil
+}
+
+func (c *FakeConverter) ConvertRange(ctx context.Context, pptxPath, outputDir string, first, last int) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.RangeCalls = append(c.RangeCalls, [2]int{first, last})
+
+	for i := first; i <= last; i++ {
+		if err := os.WriteFile(slidePreviewPath(outputDir, i), []byte(fmt.Sprintf("rendered %d", i)), 0644); err != nil {
+			return nil, err
+		}
+	}
+	return listSlidePreviews(outputDir)
+}
+
+func (c *FakeConverter) ExportImages(ctx context.Context, pptxPath, outputDir string, first, last int, options ExportOptions) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Exports = append(c.Exports, options)
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, err
+	}
+	for i := first; i <= last && i < c.SlideCount; i++ {
+		if err := os.WriteFile(slideImagePath(outputDir, i, options.Extension()), placeholderImage(options.Format), 0644); err != nil {
+			return nil, err
+		}
+	}
+	return listSlideImages(outputDir, options.Extension())
+}
+
+// placeholderImage returns a tiny real JPEG, so callers that embed exported images can
+// decode it; other formats get placeholder bytes
+func placeholderImage(format string) []byte {
+	if format != "jpeg" {
+		return []byte("fake image")
+	}
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 16, 9)), nil)
+	return buf.Bytes()
+}
+
+// FakeLLM replays scripted assistant messages and records the requests it received
+type FakeLLM struct {
+	mu        sync.Mutex
+	responses []string
+	Failures  []error // Returned by the next requests, before any scripted response
+	Requests  []anthropic.MessageNewParams
+}
+
+// NewFakeLLM creates a fake model that returns the given message JSON documents in order
+func NewFakeLLM(responses ...string) *FakeLLM {
+	return &FakeLLM{responses: responses}
+}
+
+func (f *FakeLLM) CreateMessage(ctx context.Context, params anthropic.MessageNewParams) (*anthropic.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Requests = append(f.Requests, params)
+
+	if len(f.Failures) > 0 {
+		err := f.Failures[0]
+		f.Failures = f.Failures[1:]
+		return nil, err
+	}
+	if len(f.responses) == 0 {
+		return nil, fmt.Errorf("fake LLM has no more scripted responses")
+	}
+	response := f.responses[0]
+	f.responses = f.responses[1:]
+
+	var message anthropic.Message
+	if err := json.Unmarshal([]byte(response), &message); err != nil {
+		return nil, fmt.Errorf("invalid scripted response: %v", err)
+	}
+	return &message, nil
+}
+
+// FakeStreamingLLM replays scripted messages like FakeLLM, streaming each text block
+// word by word
+type FakeStreamingLLM struct {
+	*FakeLLM
+}
+
+func (f FakeStreamingLLM) StreamMessage(ctx context.Context, params anthropic.MessageNewParams, onText func(block int, text string)) (*anthropic.Message, error) {
+	message, err := f.CreateMessage(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	for i, block := range message.Content {
+		if block.Type != "text" {
+			continue
+		}
+		for _, word := range strings.SplitAfter(block.Text, " ") {
+			onText(i, word)
+		}
+	}
+	return message, nil
+}
+
+// textResponse builds an assistant message containing only text
+func textResponse(text string) string {
+	return fmt.Sprintf(`{"id":"msg_text","type":"message","role":"assistant","model":"fake","stop_reason":"end_turn",
+		"content":[{"type":"text","text":%q}],"usage":{"input_tokens":1,"output_tokens":1}}`, text)
+}
+
+// toolUseResponse builds an assistant message that calls one tool
+func toolUseResponse(id, name, input string) string {
+	return fmt.Sprintf(`{"id":"msg_tool","type":"message","role":"assistant","model":"fake","stop_reason":"tool_use",
+		"content":[{"type":"tool_use","id":%q,"name":%q,"input":%s}],"usage":{"input_tokens":1,"output_tokens":1}}`, id, name, input)
+}
+
+// FakeEmitter records emitted events
+type FakeEmitter struct {
+	mu     sync.Mutex
+	Events []FakeEvent
+}
+
+// FakeEvent is one recorded event
+type FakeEvent struct {
+	Name string
+	Data []interface{}
+}
+
+func (e *FakeEmitter) Emit(ctx context.Context, event string, data ...interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.Events = append(e.Events, FakeEvent{Name: event, Data: data})
+}
+
+// Messages returns the payloads of all events with the given name
+func (e *FakeEmitter) Messages(event string) []interface{} {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var messages []interface{}
+	for _, recorded := range e.Events {
+		if recorded.Name == event && len(recorded.Data) > 0 {
+			messages = append(messages, recorded.Data[0])
+		}
+	}
+	return messages
+}
+
+// testEnv bundles an App wired to fakes with a scratch working directory
+type testEnv struct {
+	app       *App
+	uno       *FakeUnoBridge
+	converter *FakeConverter
+	llm       *FakeLLM
+	events    *FakeEmitter
+	dir       string
+}
+
+// newTestEnv creates an App backed by fakes, running inside a temporary working directory
+// so logs and slide images never touch the repository
+func newTestEnv(t *testing.T, responses ...string) *testEnv {
+	t.Helper()
+
+	env := &testEnv{
+		uno:       NewFakeUnoBridge(),
+		converter: &FakeConverter{SlideCount: 2},
+		llm:       NewFakeLLM(responses...),
+		events:    &FakeEmitter{},
+		dir:       t.TempDir(),
+	}
+	env.app = NewAppWithBackends(env.converter, env.uno, env.llm, env.events)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(env.dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	// Run exports still waiting on the idle timer before leaving the scratch directory
+	t.Cleanup(func() { env.app.exports.Flush(context.Background()) })
+
+	return env
+}
+
+// loadFixture copies a testdata fixture into the scratch directory and makes it the current presentation
+func (env *testEnv) loadFixture(t *testing.T, name string) string {
+	t.Helper()
+
+	source := filepath.Join(fixtureDir, name)
+	target := filepath.Join(env.dir, name)
+	if err := copyFile(source, target); err != nil {
+		t.Fatalf("failed to copy fixture: %v", err)
+	}
+	env.app.setPresentationPath(target)
+	return target
+}
+
+// fixtureDir is the absolute testdata directory, resolved before tests change directory
+var fixtureDir = func() string {
+	dir, err := filepath.Abs("testdata")
+	if err != nil {
+		panic(err)
+	}
+	return dir
+}()
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PresentationLock describes evidence that another application has a presentation open
+type PresentationLock struct {
+	LockFile string // Lock file found next to the presentation, if any
+	LockedBy string // Owner recorded in the lock file, if readable
+	Reason   string
+}
+
+// detectPresentationLock looks for LibreOffice (.~lock.<name>#) and Microsoft Office
+// (~$<name>) owner files next to the presentation, then checks that the file itself
+// can be opened for writing. It returns nil when the file looks safe to edit.
+func detectPresentationLock(presentationPath string) *PresentationLock {
+	dir := filepath.Dir(presentationPath)
+	name := filepath.Base(presentationPath)
+
+	libreOfficeLock := filepath.Join(dir, ".~lock."+name+"#")
+	if content, err := os.ReadFile(libreOfficeLock); err == nil {
+		return &PresentationLock{
+			LockFile: libreOfficeLock,
+			LockedBy: libreOfficeLockOwner(string(content)),
+			Reason:   "open in LibreOffice",
+		}
+	}
+
+	// Office drops the first two characters of long names to fit "~$" in
+	officeLocks := []string{filepath.Join(dir, "~$"+name)}
+	if len([]rune(name)) > 2 {
+		officeLocks = append(officeLocks, filepath.Join(dir, "~$"+string([]rune(name)[2:])))
+	}
+	for _, officeLock := range officeLocks {
+		if _, err := os.Stat(officeLock); err == nil {
+			return &PresentationLock{
+				LockFile: officeLock,
+				LockedBy: officeLockOwner(officeLock),
+				Reason:   "open in Microsoft PowerPoint",
+			}
+		}
+	}
+
+	// Windows refuses write access to files another process holds open
+	file, err := os.OpenFile(presentationPath, os.O_WRONLY, 0)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return &PresentationLock{Reason: "write access denied: " + err.Error()}
+	}
+	file.Close()
+	return nil
+}
+
+// libreOfficeLockOwner extracts the user from a LibreOffice lock file ("C,user,host,date,dir;")
+func libreOfficeLockOwner(content string) string {
+	fields := strings.Split(content, ",")
+	if len(fields) < 3 {
+		return ""
+	}
+	user := strings.TrimSpace(fields[1])
+	host := strings.TrimSpace(fields[2])
+	if host == "" {
+		return user
+	}
+	return user + "@" + host
+}
+
+// officeLockOwner reads the user name Office stores as a length-prefixed string at the start of the owner file
+func officeLockOwner(lockPath string) string {
+	content, err := os.ReadFile(lockPath)
+	if err != nil || len(content) < 2 {
+		return ""
+	}
+	length := int(content[0])
+	if length == 0 || 1+length > len(content) {
+		return ""
+	}
+	return strings.TrimSpace(string(content[1 : 1+length]))
+}
+
+// presentationLockedError reports a locked presentation with guidance the agent can relay
+func presentationLockedError(presentationPath string, lock *PresentationLock) *ToolError {
+	message := "file is locked by another application (" + lock.Reason
+	if lock.LockedBy != "" {
+		message += ", locked by " + lock.LockedBy
+	}
+	message += "): " + presentationPath + ". Close it there and retry the request."
+
+	toolErr := NewToolError(ErrCodeFileLocked, "%s", message).
+		WithDetail("retryable", true).
+		WithDetail("retry_hint", "ask the user to close the presentation in the other application, then call the tool again")
+	if lock.LockFile != "" {
+		toolErr.WithDetail("lock_file", lock.LockFile)
+	}
+	if lock.LockedBy != "" {
+		toolErr.WithDetail("locked_by", lock.LockedBy)
+	}
+	return toolErr
+}
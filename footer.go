@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SetFooterDefinition defines the set_footer tool
+var SetFooterDefinition = ToolDefinition{
+	Name: "set_footer",
+	Description: `Set the footer text, slide numbers and date shown at the bottom of slides, for the whole deck or chosen slides - e.g. "add slide numbers and our confidentiality footer".
+
+footer_text sets the footer and shows it ('' clears and hides it). show_slide_number, show_footer and show_date turn each field on or off. date_text shows a fixed date text; show_date without date_text shows the current date, updated whenever the deck is opened. Settings left out stay as they are.
+
+Leave slides empty to change every slide; skip_title_slides then leaves slides on a title layout alone, as PowerPoint's "Don't show on title slide" does. The fields appear where the slide master places its footer, slide number and date placeholders.`,
+	InputSchema: SetFooterInputSchema,
+	Function:    SetFooter,
+	Mutating:    true,
+}
+
+type SetFooterInput struct {
+	PresentationPath string  `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Slides           []int   `json:"slides,omitempty" jsonschema_description:"(Optional) Slide numbers to change (1-based); omit for the whole deck"`
+	FooterText       *string `json:"footer_text,omitempty" jsonschema_description:"(Optional) Footer text, e.g. 'Confidential'; '' clears and hides the footer"`
+	ShowFooter       *bool   `json:"show_footer,omitempty" jsonschema_description:"(Optional) Show or hide the footer text"`
+	ShowSlideNumber  *bool   `json:"show_slide_number,omitempty" jsonschema_description:"(Optional) Show or hide the slide number"`
+	ShowDate         *bool   `json:"show_date,omitempty" jsonschema_description:"(Optional) Show or hide the date"`
+	DateText         *string `json:"date_text,omitempty" jsonschema_description:"(Optional) Fixed date text, e.g. 'March 2025'; '' switches to the current date"`
+	SkipTitleSlides  bool    `json:"skip_title_slides,omitempty" jsonschema_description:"(Optional) With slides omitted, leave slides on a title layout unchanged"`
+}
+
+var SetFooterInputSchema = GenerateSchema[SetFooterInput]()
+
+func SetFooter(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	footerInput := SetFooterInput{}
+	err := json.Unmarshal(input, &footerInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if footerInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			footerInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+	if _, err := os.Stat(footerInput.PresentationPath); os.IsNotExist(err) {
+		return "", NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", footerInput.PresentationPath)
+	}
+
+	if footerInput.FooterText == nil && footerInput.ShowFooter == nil && footerInput.ShowSlideNumber == nil &&
+		footerInput.ShowDate == nil && footerInput.DateText == nil {
+		return "", NewToolError(ErrCodeInvalidInput, "give at least one of footer_text, show_footer, show_slide_number, show_date and date_text")
+	}
+	for _, slideNumber := range footerInput.Slides {
+		if slideNumber < 1 {
+			return "", NewToolError(ErrCodeSlideOutOfRange, "slide numbers must be 1 or greater")
+		}
+	}
+	if footerInput.SkipTitleSlides && len(footerInput.Slides) > 0 {
+		return "", NewToolError(ErrCodeInvalidInput, "skip_title_slides only applies when slides is omitted")
+	}
+
+	// Text implies showing the field unless the caller says otherwise
+	showFooter := footerInput.ShowFooter
+	if showFooter == nil && footerInput.FooterText != nil {
+		show := strings.TrimSpace(*footerInput.FooterText) != ""
+		showFooter = &show
+	}
+	showDate := footerInput.ShowDate
+	if showDate == nil && footerInput.DateText != nil {
+		show := true
+		showDate = &show
+	}
+
+	slides := footerInput.Slides
+	var skipped []int
+	if footerInput.SkipTitleSlides {
+		slides, skipped, err = slidesWithoutTitleLayout(footerInput.PresentationPath)
+		if err != nil {
+			return "", NewToolError(ErrCodeInternal, "failed to read slide layouts: %v", err)
+		}
+		if len(slides) == 0 {
+			return "", NewToolError(ErrCodeInvalidInput, "every slide uses a title layout; give slides explicitly")
+		}
+	}
+
+	slidesJSON, _ := json.Marshal(slides)
+	if slides == nil {
+		slidesJSON = []byte("[]")
+	}
+	settingsJSON, _ := json.Marshal(map[string]interface{}{
+		"footer_text":       footerInput.FooterText,
+		"show_footer":       showFooter,
+		"show_slide_number": footerInput.ShowSlideNumber,
+		"show_date":         showDate,
+		"date_text":         footerInput.DateText,
+	})
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_set_footer.py", footerInput.PresentationPath,
+		string(slidesJSON), string(settingsJSON))
+	if err != nil {
+		return "", scriptError("failed to set footer", err, output)
+	}
+
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+	if len(skipped) > 0 {
+		if result.Details == nil {
+			result.Details = map[string]interface{}{}
+		}
+		result.Details["skipped_title_slides"] = skipped
+	}
+
+	schedulePreviewExport(ctx, app, footerInput.PresentationPath, slides...)
+	return marshalResult(result)
+}
+
+// slidesWithoutTitleLayout splits the deck's slides into those not on a title layout and
+// those on one, read natively
+func slidesWithoutTitleLayout(presentationPath string) ([]int, []int, error) {
+	if !isOOXMLPackage(presentationPath) {
+		return nil, nil, fmt.Errorf("skip_title_slides needs a .pptx presentation")
+	}
+	pkg, err := openPPTX(presentationPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer pkg.Close()
+	layouts, err := pkg.Layouts()
+	if err != nil {
+		return nil, nil, err
+	}
+	title := map[int]bool{}
+	for _, layout := range layouts {
+		if layout.Type == "title" {
+			for _, slideNumber := range layout.Slides {
+				title[slideNumber] = true
+			}
+		}
+	}
+	var slides, skipped []int
+	for slideNumber := 1; slideNumber <= pkg.SlideCount(); slideNumber++ {
+		if title[slideNumber] {
+			skipped = append(skipped, slideNumber)
+		} else {
+			slides = append(slides, slideNumber)
+		}
+	}
+	return slides, skipped, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestSetFooterPassesSettings(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_set_footer.py", `{"success": true, "slides": [1, 2]}`)
+
+	if _, err := SetFooter(context.Background(), env.app, json.RawMessage(`{"footer_text": "Confidential", "show_slide_number": true}`)); err != nil {
+		t.Fatalf("SetFooter failed: %v", err)
+	}
+	if _, err := SetFooter(context.Background(), env.app, json.RawMessage(`{"slides": [2], "date_text": "March 2025"}`)); err != nil {
+		t.Fatalf("SetFooter failed: %v", err)
+	}
+	// No slide uses a title layout, so skipping them names every slide
+	if _, err := SetFooter(context.Background(), env.app, json.RawMessage(`{"show_footer": false, "skip_title_slides": true}`)); err != nil {
+		t.Fatalf("SetFooter failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_set_footer.py")
+	if len(calls) != 3 ||
+		fmt.Sprint(calls[0].Args) != fmt.Sprint([]string{path, "[]", `{"date_text":null,"footer_text":"Confidential","show_date":null,"show_footer":true,"show_slide_number":true}`}) ||
+		fmt.Sprint(calls[1].Args) != fmt.Sprint([]string{path, "[2]", `{"date_text":"March 2025","footer_text":null,"show_date":true,"show_footer":null,"show_slide_number":null}`}) ||
+		fmt.Sprint(calls[2].Args) != fmt.Sprint([]string{path, "[1,2]", `{"date_text":null,"footer_text":null,"show_date":null,"show_footer":false,"show_slide_number":null}`}) {
+		t.Fatalf("unexpected script calls: %+v", calls)
+	}
+
+	rewritePart(t, path, "ppt/slideLayouts/slideLayout1.xml", func(data []byte) []byte {
+		return bytes.Replace(data, []byte(`type="obj"`), []byte(`type="title"`), 1)
+	})
+	for _, bad := range []string{
+		`{}`,
+		`{"slides": [0], "show_date": true}`,
+		`{"slides": [1], "show_date": true, "skip_title_slides": true}`,
+		`{"show_date": true, "skip_title_slides": true}`,
+	} {
+		_, err := SetFooter(context.Background(), env.app, json.RawMessage(bad))
+		if code := toolErrorCode(err); code != ErrCodeInvalidInput && code != ErrCodeSlideOutOfRange {
+			t.Errorf("expected the input to be refused for %s, got %s (%v)", bad, code, err)
+		}
+	}
+	if len(env.uno.Calls("uno_set_footer.py")) != 3 {
+		t.Error("refused input should not reach the script")
+	}
+}
@@ -4,12 +4,47 @@ import {
   LoadPresentation,
   OpenPresentationDialog,
   SendMessageToAI,
-  GetSlideImageQuiet,
-  GetSlideImageAsBase64,
+  GetSlideImageURL,
   GetCurrentPresentationName,
   HasPresentationLoaded,
+  LoadConversation,
+  Undo,
+  Redo,
+  GetHistoryState,
+  GetLibreOfficeStatus,
+  SavePresentationAsDialog,
+  ExportPDFDialog,
+  ReloadPresentation,
+  GetUsageStats,
+  GetSettings,
+  ListModels,
+  UpdateSettings,
+  GetRecentPresentations,
+  OpenRecent,
+  NewPresentationDialog,
+  GenerateFromOutlineDialog,
 } from "../wailsjs/go/main/App";
+import { main } from "../wailsjs/go/models";
 import { EventsOn } from "../wailsjs/runtime/runtime";
+
+// Payload of "presentation-converted" events
+interface PresentationConversion {
+  source_path: string;
+  working_path: string;
+  format: string;
+  reused: boolean;
+}
+
+// Payload of "conversion-progress" events
+interface ConversionProgress {
+  presentation_path: string;
+  stage: "rendering" | "done" | "failed";
+  slide: number;
+  total: number;
+  percent: number;
+  tool_id?: string;
+  tool?: string;
+}
 import ChatPanel from "./components/ChatPanel";
 
 function App() {
@@ -21,6 +56,16 @@ function App() {
   const [presentationName, setPresentationName] = useState<string>("");
   const [hasPresentationLoaded, setHasPresentationLoaded] = useState(false);
   const [streamingMessages, setStreamingMessages] = useState<string[]>([]);
+  const [chatHistory, setChatHistory] = useState<main.ConversationMessage[]>([]);
+  const [historyState, setHistoryState] = useState<main.HistoryState | null>(null);
+  const [libreOfficeStatus, setLibreOfficeStatus] = useState<main.LibreOfficeStatus | null>(null);
+  const [usageStats, setUsageStats] = useState<main.UsageStats | null>(null);
+  const [settings, setSettings] = useState<main.Settings | null>(null);
+  const [models, setModels] = useState<main.ModelOption[]>([]);
+  const [conversion, setConversion] = useState<ConversionProgress | null>(null);
+  const [recentPresentations, setRecentPresentations] = useState<main.RecentPresentation[]>([]);
+  const [newAspectRatio, setNewAspectRatio] = useState("16:9");
+  const [convertedFrom, setConvertedFrom] = useState<PresentationConversion | null>(null);
 
   useEffect(() => {
     // Load initial slides if they exist
@@ -31,6 +76,49 @@ function App() {
     EventsOn("ai-message", (message: string) => {
       setStreamingMessages(prev => [...prev, message]);
     });
+
+    // Track the LibreOffice service so a crash or restart is visible
+    GetLibreOfficeStatus().then(setLibreOfficeStatus).catch(() => {});
+    EventsOn("libreoffice-status", (status: main.LibreOfficeStatus) => {
+      setLibreOfficeStatus(status);
+    });
+
+    // Show what the session has cost so far
+    GetUsageStats().then(setUsageStats).catch(() => {});
+    EventsOn("usage-updated", (stats: main.UsageStats) => {
+      setUsageStats(stats);
+    });
+
+    // Show how far slide rendering got, for loading a deck and for tool calls alike
+    EventsOn("conversion-progress", (progress: ConversionProgress) => {
+      setConversion(progress.stage === "rendering" ? progress : null);
+    });
+
+    // .ppt, .odp and .key files are edited as a converted .pptx; say so
+    EventsOn("presentation-converted", (conversion: PresentationConversion) => {
+      setConvertedFrom(conversion);
+    });
+
+    // Offer the decks opened last on the welcome screen
+    GetRecentPresentations().then(setRecentPresentations).catch(() => {});
+
+    // Offer the provider's models; switching applies from the next request
+    GetSettings().then(setSettings).catch(() => {});
+    ListModels().then(setModels).catch(() => {});
+
+    // Another program saved the open deck; offer to show its version
+    EventsOn("presentation-changed-externally", async (path: string) => {
+      const name = path.split(/[\\/]/).pop();
+      if (!window.confirm(`${name} was changed by another program. Reload it?`)) return;
+      try {
+        const slideList = await ReloadPresentation();
+        setSlides(slideList);
+        setCurrentSlide((current) => Math.min(current, Math.max(slideList.length - 1, 0)));
+        setHistoryState(await GetHistoryState());
+      } catch (error) {
+        console.error("Failed to reload presentation:", error);
+      }
+    });
   }, []);
 
   useEffect(() => {
@@ -44,17 +132,9 @@ function App() {
     if (slides.length === 0) return;
 
     try {
-      // First call the quiet method to cache data without logging base64
-      const status = await GetSlideImageQuiet(slides[currentSlide]);
-
-      // Then get the actual base64 data from cache
-      if (
-        status === "CACHED_BASE64_DATA_AVAILABLE" ||
-        status === "BASE64_DATA_LOADED"
-      ) {
-        const imageData = await GetSlideImageAsBase64(slides[currentSlide]);
-        setCurrentSlideImage(imageData);
-      }
+      // Stream the image through the asset server instead of a base64 string
+      const imageURL = await GetSlideImageURL(slides[currentSlide]);
+      setCurrentSlideImage(imageURL);
     } catch (error) {
       console.error("Failed to load slide image:", error);
       setCurrentSlideImage("");
@@ -85,6 +165,7 @@ function App() {
       if (hasLoaded) {
         const name = await GetCurrentPresentationName();
         setPresentationName(name);
+        setHistoryState(await GetHistoryState());
       } else {
         setPresentationName("");
       }
@@ -93,11 +174,16 @@ function App() {
     }
   };
 
-  const handleLoadPresentation = async () => {
+  const handleLoadPresentation = () => openPresentation(OpenPresentationDialog);
+
+  const handleOpenRecent = (path: string) => openPresentation(() => OpenRecent(path));
+
+  const handleNewPresentation = () => openPresentation(() => NewPresentationDialog(newAspectRatio, ""));
+
+  const openPresentation = async (open: () => Promise<string[]>) => {
     setLoading(true);
     try {
-      // Open file dialog to select presentation
-      const slideList = await OpenPresentationDialog();
+      const slideList = await open();
       setSlides(slideList);
       if (slideList.length > 0) {
         setCurrentSlide(0);
@@ -105,13 +191,68 @@ function App() {
       
       // Update presentation state after loading
       await updatePresentationState();
+
+      // Resume the conversation saved for this presentation, if any
+      if (slideList.length > 0) {
+        setChatHistory(await LoadConversation(""));
+      }
     } catch (error) {
       console.error("Failed to load presentation:", error);
+      window.alert(`Failed to open the presentation: ${error}`);
+    } finally {
+      setLoading(false);
+      GetRecentPresentations().then(setRecentPresentations).catch(() => {});
+    }
+  };
+
+  const handleHistoryStep = async (undo: boolean) => {
+    try {
+      const slideList = undo ? await Undo() : await Redo();
+      setSlides(slideList);
+      setCurrentSlide((current) => Math.min(current, Math.max(slideList.length - 1, 0)));
+      setHistoryState(await GetHistoryState());
+    } catch (error) {
+      console.error(undo ? "Failed to undo:" : "Failed to redo:", error);
+    }
+  };
+
+  const handleSaveAs = async () => {
+    try {
+      const savedPath = await SavePresentationAsDialog();
+      if (savedPath) {
+        // A .pptx copy becomes the current presentation
+        updatePresentationState();
+        setHistoryState(await GetHistoryState());
+      }
+    } catch (error) {
+      console.error("Failed to save presentation:", error);
+    }
+  };
+
+  const handleGenerateFromOutline = async () => {
+    setLoading(true);
+    try {
+      const slideList = await GenerateFromOutlineDialog();
+      if (slideList.length > 0) {
+        setSlides(slideList);
+        setHistoryState(await GetHistoryState());
+      }
+    } catch (error) {
+      console.error("Failed to generate slides from outline:", error);
+      window.alert(`Failed to generate slides: ${error}`);
     } finally {
       setLoading(false);
     }
   };
 
+  const handleExportPDF = async (layout: string) => {
+    try {
+      await ExportPDFDialog(main.PDFExportOptions.createFrom({ layout }));
+    } catch (error) {
+      console.error("Failed to export PDF:", error);
+    }
+  };
+
   const handleSendMessage = async (message: string, onMessage: (message: string) => void) => {
     try {
       // Clear previous streaming messages
@@ -162,6 +303,71 @@ function App() {
                 {presentationName}
               </span>
             )}
+            {presentationName && convertedFrom && convertedFrom.working_path.endsWith(presentationName) && (
+              <span
+                className="text-xs text-gray-500"
+                title={`Edits are saved to ${convertedFrom.working_path}; ${convertedFrom.source_path} is left as it is`}
+              >
+                converted from {convertedFrom.format}
+              </span>
+            )}
+            {conversion && (
+              <span className="flex items-center text-sm text-gray-600" title={conversion.tool ? `Rendering for ${conversion.tool}` : undefined}>
+                {conversion.total > 0
+                  ? `Rendering slide ${Math.min(conversion.slide + 1, conversion.total)} of ${conversion.total}`
+                  : "Rendering slides..."}
+                <span className="ml-2 w-24 h-1.5 bg-gray-200 rounded overflow-hidden">
+                  <span className="block h-full bg-blue-600 transition-all" style={{ width: `${conversion.percent}%` }} />
+                </span>
+              </span>
+            )}
+            {libreOfficeStatus &&
+              ["starting", "restarting", "failed"].includes(libreOfficeStatus.state) && (
+                <span
+                  className={`text-sm px-2 py-1 rounded ${
+                    libreOfficeStatus.state === "failed"
+                      ? "text-red-700 bg-red-50"
+                      : "text-amber-700 bg-amber-50"
+                  }`}
+                  title={libreOfficeStatus.error}
+                >
+                  {libreOfficeStatus.state === "failed"
+                    ? "LibreOffice unavailable"
+                    : libreOfficeStatus.state === "restarting"
+                    ? "Restarting LibreOffice..."
+                    : "Starting LibreOffice..."}
+                </span>
+              )}
+            {usageStats && usageStats.requests > 0 && (
+              <span
+                className="text-sm text-gray-500"
+                title={`${usageStats.input_tokens.toLocaleString()} input and ${usageStats.output_tokens.toLocaleString()} output tokens in ${usageStats.requests} requests`}
+              >
+                ~${usageStats.estimated_cost_usd.toFixed(2)} this session
+              </span>
+            )}
+            {settings && models.length > 0 && (
+              <select
+                value={settings.model}
+                onChange={async (e) => {
+                  try {
+                    // Start from the saved settings so other changes, like the instructions, are kept
+                    const current = await GetSettings();
+                    setSettings(await UpdateSettings(main.Settings.createFrom({ ...current, model: e.target.value })));
+                  } catch (error) {
+                    console.error("Failed to switch model:", error);
+                  }
+                }}
+                className="text-sm text-gray-600 border border-gray-200 rounded px-1 py-0.5"
+                title="Model used for the next request"
+              >
+                {models.map((model) => (
+                  <option key={model.id} value={model.id}>
+                    {model.display_name || model.id}
+                  </option>
+                ))}
+              </select>
+            )}
           </div>
           <button
             onClick={() => setChatOpen(!chatOpen)}
@@ -247,6 +453,50 @@ function App() {
             )}
 
             <div className="flex items-center space-x-2 ml-6">
+              <button
+                onClick={() => handleHistoryStep(true)}
+                disabled={!historyState?.can_undo}
+                title={historyState?.can_undo ? `Undo ${historyState.undo_label}` : "Nothing to undo"}
+                className="px-3 py-1 hover:bg-gray-200 rounded-md transition-colors text-sm font-medium disabled:opacity-40"
+              >
+                Undo
+              </button>
+              <button
+                onClick={() => handleHistoryStep(false)}
+                disabled={!historyState?.can_redo}
+                title={historyState?.can_redo ? `Redo ${historyState.redo_label}` : "Nothing to redo"}
+                className="px-3 py-1 hover:bg-gray-200 rounded-md transition-colors text-sm font-medium disabled:opacity-40"
+              >
+                Redo
+              </button>
+              <button
+                onClick={handleSaveAs}
+                disabled={!hasPresentationLoaded}
+                title="Save a copy as .pptx, .odp or .pdf"
+                className="px-3 py-1 hover:bg-gray-200 rounded-md transition-colors text-sm font-medium disabled:opacity-40"
+              >
+                Save As
+              </button>
+              <button
+                onClick={handleGenerateFromOutline}
+                disabled={!hasPresentationLoaded || loading}
+                title="Add slides from a Markdown or text outline"
+                className="px-3 py-1 hover:bg-gray-200 rounded-md transition-colors text-sm font-medium disabled:opacity-40"
+              >
+                From Outline
+              </button>
+              <select
+                value=""
+                onChange={(e) => e.target.value && handleExportPDF(e.target.value)}
+                disabled={!hasPresentationLoaded}
+                title="Export the presentation to PDF"
+                className="px-2 py-1 bg-transparent hover:bg-gray-200 rounded-md transition-colors text-sm font-medium disabled:opacity-40"
+              >
+                <option value="">Export PDF</option>
+                <option value="slides">Slides</option>
+                <option value="notes">Notes pages</option>
+                <option value="handout">Handout (6 per page)</option>
+              </select>
               <button className="p-2 hover:bg-gray-200 rounded-md transition-colors">
                 <svg
                   className="w-5 h-5"
@@ -320,6 +570,55 @@ function App() {
                   Click 'Open Presentation' to load a PowerPoint file and see
                   the slide parsing in action.
                 </p>
+                <div className="flex items-center justify-center space-x-2 mb-8">
+                  <button
+                    onClick={handleNewPresentation}
+                    disabled={loading}
+                    className="px-4 py-2 border border-gray-300 hover:bg-gray-100 text-gray-700 rounded-lg font-medium disabled:opacity-50"
+                  >
+                    New Presentation
+                  </button>
+                  <select
+                    value={newAspectRatio}
+                    onChange={(e) => setNewAspectRatio(e.target.value)}
+                    disabled={loading}
+                    className="px-2 py-2 border border-gray-300 rounded-lg text-sm text-gray-700"
+                  >
+                    <option value="16:9">16:9</option>
+                    <option value="4:3">4:3</option>
+                    <option value="16:10">16:10</option>
+                    <option value="a4">A4</option>
+                  </select>
+                </div>
+                {recentPresentations.length > 0 && (
+                  <div className="text-left">
+                    <h3 className="text-sm font-medium text-gray-700 mb-2">Recent presentations</h3>
+                    <ul className="space-y-2">
+                      {recentPresentations.map((recent) => (
+                        <li key={recent.path}>
+                          <button
+                            onClick={() => handleOpenRecent(recent.path)}
+                            disabled={loading}
+                            title={recent.path}
+                            className="w-full flex items-center p-2 rounded-lg border border-gray-200 hover:bg-gray-50 disabled:opacity-50"
+                          >
+                            {recent.thumbnail ? (
+                              <img src={recent.thumbnail} alt="" className="w-20 h-auto rounded border border-gray-200 mr-3" />
+                            ) : (
+                              <div className="w-20 h-11 rounded bg-gray-100 mr-3" />
+                            )}
+                            <div className="min-w-0 text-left">
+                              <div className="text-sm font-medium text-gray-900 truncate">{recent.name}</div>
+                              <div className="text-xs text-gray-500">
+                                {recent.slide_count} slides · {new Date(recent.last_opened).toLocaleString()}
+                              </div>
+                            </div>
+                          </button>
+                        </li>
+                      ))}
+                    </ul>
+                  </div>
+                )}
               </div>
             </div>
           ) : (
@@ -413,7 +712,7 @@ function App() {
       {/* AI Chat Panel */}
       {chatOpen && (
         <div className="w-96 border-l border-gray-200 bg-white">
-          <ChatPanel onSendMessage={handleSendMessage} />
+          <ChatPanel onSendMessage={handleSendMessage} history={chatHistory} />
         </div>
       )}
     </div>
@@ -1,4 +1,7 @@
 import { useState, useRef, useEffect } from 'react';
+import { CancelAIRequest, GetConfirmDestructive, GetSettings, RespondToolApproval, SetConfirmDestructive, UpdateSettings } from '../../wailsjs/go/main/App';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
+import { main } from '../../wailsjs/go/models';
 
 interface ChatMessage {
     id: string;
@@ -7,11 +10,53 @@ interface ChatMessage {
     timestamp: Date;
 }
 
+// Payload of "ai-message-delta" events: a piece of assistant text for the bubble with this id
+interface MessageDelta {
+    id: string;
+    text: string;
+}
+
+// Payload of "tool-approval-request" events: a destructive tool call waiting for the user
+interface ToolApprovalRequest {
+    id: string;
+    tool: string;
+    display_name: string;
+    input: Record<string, unknown>;
+}
+
+// Payload of "edit-plan" events: the edits the agent proposes in plan mode
+interface EditPlan {
+    id: string;
+    summary: string;
+    steps: { tool: string; description: string; input: Record<string, unknown> }[];
+    review: boolean; // Waits for RespondToolApproval(id, approved)
+}
+
+// Payload of "plan-progress" events
+interface PlanProgress {
+    plan_id: string;
+    step: number;
+    total: number;
+    status: 'running' | 'done' | 'failed' | 'skipped';
+    error?: string;
+}
+
+// Payload of "retrying" events: a failed model request about to be repeated
+interface RetryStatus {
+    attempt: number;
+    max_attempts: number;
+    delay_seconds: number;
+    reason: string;
+}
+
+const planStatusIcons: Record<string, string> = { running: '⏳', done: '✅', failed: '❌', skipped: '⏭️' };
+
 interface ChatPanelProps {
     onSendMessage: (message: string, onMessage: (message: string) => void) => Promise<void>;
+    history?: main.ConversationMessage[]; // Saved conversation of the opened presentation
 }
 
-const ChatPanel: React.FC<ChatPanelProps> = ({ onSendMessage }) => {
+const ChatPanel: React.FC<ChatPanelProps> = ({ onSendMessage, history }) => {
     const [messages, setMessages] = useState<ChatMessage[]>([
         {
             id: '1',
@@ -28,6 +73,14 @@ const ChatPanel: React.FC<ChatPanelProps> = ({ onSendMessage }) => {
     ]);
     const [inputMessage, setInputMessage] = useState('');
     const [isLoading, setIsLoading] = useState(false);
+    const [confirmDestructive, setConfirmDestructive] = useState(false);
+    const [approvals, setApprovals] = useState<ToolApprovalRequest[]>([]);
+    const [plans, setPlans] = useState<EditPlan[]>([]);
+    const [planStatus, setPlanStatus] = useState<Record<string, string[]>>({}); // Step statuses by plan ID
+    const [pendingPlans, setPendingPlans] = useState<string[]>([]);
+    const [planMode, setPlanMode] = useState(false);
+    const [reviewMode, setReviewMode] = useState(false);
+    const [retry, setRetry] = useState<RetryStatus | null>(null);
     const messagesEndRef = useRef<HTMLDivElement>(null);
 
     const scrollToBottom = () => {
@@ -38,6 +91,207 @@ const ChatPanel: React.FC<ChatPanelProps> = ({ onSendMessage }) => {
         scrollToBottom();
     }, [messages]);
 
+    useEffect(() => {
+        GetConfirmDestructive().then(setConfirmDestructive);
+        // Destructive tool calls wait here until the user approves or denies them
+        return EventsOn("tool-approval-request", (request: ToolApprovalRequest) => {
+            setApprovals(prev => [...prev, request]);
+        });
+    }, []);
+
+    useEffect(() => {
+        GetSettings().then(settings => {
+            setPlanMode(settings.plan_mode);
+            setReviewMode(settings.review_mode);
+        }).catch(() => {});
+        // Plans are shown with their steps and, when reviewed, wait for an answer
+        const offPlan = EventsOn("edit-plan", (plan: EditPlan) => {
+            setPlans(prev => [...prev, plan]);
+            if (plan.review) setPendingPlans(prev => [...prev, plan.id]);
+        });
+        const offProgress = EventsOn("plan-progress", (progress: PlanProgress) => {
+            setPlanStatus(prev => {
+                const statuses = [...(prev[progress.plan_id] || [])];
+                statuses[progress.step - 1] = progress.status;
+                return { ...prev, [progress.plan_id]: statuses };
+            });
+        });
+        return () => {
+            offPlan();
+            offProgress();
+        };
+    }, []);
+
+    const togglePlanMode = async (enabled: boolean) => {
+        try {
+            const settings = await GetSettings();
+            await UpdateSettings(main.Settings.createFrom({ ...settings, plan_mode: enabled }));
+            setPlanMode(enabled);
+        } catch (error) {
+            console.error('Failed to change plan mode:', error);
+        }
+    };
+
+    const toggleReviewMode = async (enabled: boolean) => {
+        try {
+            const settings = await GetSettings();
+            await UpdateSettings(main.Settings.createFrom({ ...settings, review_mode: enabled }));
+            setReviewMode(enabled);
+        } catch (error) {
+            console.error('Failed to change review mode:', error);
+        }
+    };
+
+    const answerPlan = async (id: string, approved: boolean) => {
+        setPendingPlans(prev => prev.filter(planID => planID !== id));
+        try {
+            await RespondToolApproval(id, approved);
+        } catch (error) {
+            // The turn was cancelled while the plan was open
+            console.error('Plan approval failed:', error);
+        }
+    };
+
+    const [systemPrompt, setSystemPrompt] = useState('');
+    const [systemPromptSaved, setSystemPromptSaved] = useState(true);
+
+    useEffect(() => {
+        GetSettings().then(settings => setSystemPrompt(settings.system_prompt || '')).catch(() => {});
+    }, []);
+
+    const saveSystemPrompt = async () => {
+        try {
+            const settings = await GetSettings();
+            await UpdateSettings(main.Settings.createFrom({ ...settings, system_prompt: systemPrompt.trim() }));
+            setSystemPromptSaved(true);
+        } catch (error) {
+            console.error('Failed to save instructions:', error);
+        }
+    };
+
+    const [brand, setBrand] = useState({ heading_font: '', body_font: '', palette: '', logo_path: '', footer_text: '' });
+    const [brandSaved, setBrandSaved] = useState(true);
+    const [brandError, setBrandError] = useState('');
+
+    useEffect(() => {
+        GetSettings().then(settings => {
+            const profile = settings.brand;
+            if (!profile) return;
+            setBrand({
+                heading_font: profile.heading_font || '',
+                body_font: profile.body_font || '',
+                palette: (profile.palette || []).join(', '),
+                logo_path: profile.logo_path || '',
+                footer_text: profile.footer_text || '',
+            });
+        }).catch(() => {});
+    }, []);
+
+    const updateBrand = (field: keyof typeof brand, value: string) => {
+        setBrand(prev => ({ ...prev, [field]: value }));
+        setBrandSaved(false);
+    };
+
+    const saveBrand = async () => {
+        const profile = main.BrandProfile.createFrom({
+            heading_font: brand.heading_font.trim(),
+            body_font: brand.body_font.trim(),
+            palette: brand.palette.split(',').map(color => color.trim()).filter(color => color),
+            logo_path: brand.logo_path.trim(),
+            footer_text: brand.footer_text.trim(),
+        });
+        // An all-empty profile removes it
+        const empty = !profile.heading_font && !profile.body_font && !profile.palette?.length && !profile.logo_path && !profile.footer_text;
+        try {
+            const settings = await GetSettings();
+            await UpdateSettings(main.Settings.createFrom({ ...settings, brand: empty ? undefined : profile }));
+            setBrandSaved(true);
+            setBrandError('');
+        } catch (error) {
+            setBrandError(String(error));
+        }
+    };
+
+    const [stockKeys, setStockKeys] = useState({ unsplash_access_key: '', pexels_api_key: '' });
+    const [stockKeysSaved, setStockKeysSaved] = useState(true);
+    const [stockKeysError, setStockKeysError] = useState('');
+
+    useEffect(() => {
+        GetSettings().then(settings => setStockKeys({
+            unsplash_access_key: settings.unsplash_access_key || '',
+            pexels_api_key: settings.pexels_api_key || '',
+        })).catch(() => {});
+    }, []);
+
+    const updateStockKey = (field: keyof typeof stockKeys, value: string) => {
+        setStockKeys(prev => ({ ...prev, [field]: value }));
+        setStockKeysSaved(false);
+    };
+
+    const saveStockKeys = async () => {
+        try {
+            const settings = await GetSettings();
+            await UpdateSettings(main.Settings.createFrom({
+                ...settings,
+                unsplash_access_key: stockKeys.unsplash_access_key.trim(),
+                pexels_api_key: stockKeys.pexels_api_key.trim(),
+            }));
+            setStockKeysSaved(true);
+            setStockKeysError('');
+        } catch (error) {
+            setStockKeysError(String(error));
+        }
+    };
+
+    const toggleConfirmDestructive = async (enabled: boolean) => {
+        await SetConfirmDestructive(enabled);
+        setConfirmDestructive(enabled);
+    };
+
+    const answerApproval = async (id: string, approved: boolean) => {
+        setApprovals(prev => prev.filter(request => request.id !== id));
+        try {
+            await RespondToolApproval(id, approved);
+        } catch (error) {
+            // The turn was cancelled while the request was open
+            console.error('Tool approval failed:', error);
+        }
+    };
+
+    useEffect(() => {
+        // Show the resumed conversation after the welcome messages
+        if (!history || history.length === 0) return;
+        const restored: ChatMessage[] = history.map((message, index) => ({
+            id: `history-${index}`,
+            role: message.role === 'user' ? 'user' : 'assistant',
+            content: message.content,
+            timestamp: new Date()
+        }));
+        setMessages(prev => [...prev.filter(message => message.id === '1' || message.id === '2'), ...restored]);
+    }, [history]);
+
+    useEffect(() => {
+        // Show in the thinking indicator that a failed model request is being repeated
+        return EventsOn("retrying", (status: RetryStatus) => setRetry(status));
+    }, []);
+
+    useEffect(() => {
+        // Render assistant text token by token as it streams in
+        return EventsOn("ai-message-delta", (delta: MessageDelta) => {
+            setRetry(null);
+            const id = `stream-${delta.id}`;
+            setMessages(prev => {
+                const existing = prev.find(message => message.id === id);
+                if (!existing) {
+                    return [...prev, { id, role: 'assistant', content: delta.text, timestamp: new Date() }];
+                }
+                return prev.map(message =>
+                    message.id === id ? { ...message, content: message.content + delta.text } : message
+                );
+            });
+        });
+    }, []);
+
     const handleSendMessage = async () => {
         if (!inputMessage.trim() || isLoading) return;
 
@@ -67,10 +321,14 @@ const ChatPanel: React.FC<ChatPanelProps> = ({ onSendMessage }) => {
 
             await onSendMessage(userMessageText, onStreamMessage);
         } catch (error) {
+            // A stopped request isn't a failure; its edits were already rolled back
+            const cancelled = String(error).includes('context canceled');
             const errorMessage: ChatMessage = {
                 id: (Date.now() + 1).toString(),
                 role: 'assistant',
-                content: 'Sorry, I encountered an error while processing your request. Please try again.',
+                content: cancelled
+                    ? 'Request cancelled. Changes made during it were undone.'
+                    : 'Sorry, I encountered an error while processing your request. Please try again.',
                 timestamp: new Date()
             };
 
@@ -78,6 +336,7 @@ const ChatPanel: React.FC<ChatPanelProps> = ({ onSendMessage }) => {
             console.error('Chat error:', error);
         } finally {
             setIsLoading(false);
+            setRetry(null);
         }
     };
 
@@ -113,6 +372,101 @@ const ChatPanel: React.FC<ChatPanelProps> = ({ onSendMessage }) => {
                         <span className="text-sm text-gray-600">Online</span>
                     </div>
                 </div>
+                <label className="flex items-center space-x-2 text-xs text-gray-600">
+                    <input
+                        type="checkbox"
+                        checked={confirmDestructive}
+                        onChange={(e) => toggleConfirmDestructive(e.target.checked)}
+                    />
+                    <span>Confirm destructive operations</span>
+                </label>
+                <label className="flex items-center space-x-2 text-xs text-gray-600">
+                    <input
+                        type="checkbox"
+                        checked={planMode}
+                        onChange={(e) => togglePlanMode(e.target.checked)}
+                    />
+                    <span>Review a plan before editing</span>
+                </label>
+                <label className="flex items-center space-x-2 text-xs text-gray-600">
+                    <input
+                        type="checkbox"
+                        checked={reviewMode}
+                        onChange={(e) => toggleReviewMode(e.target.checked)}
+                    />
+                    <span>Suggest changes as comments instead of editing</span>
+                </label>
+                <details className="mt-1 text-xs text-gray-600">
+                    <summary className="cursor-pointer">Custom instructions</summary>
+                    <textarea
+                        value={systemPrompt}
+                        onChange={(e) => { setSystemPrompt(e.target.value); setSystemPromptSaved(false); }}
+                        placeholder="Leave empty to use the built-in instructions"
+                        rows={6}
+                        className="mt-1 w-full border border-gray-200 rounded p-2 text-xs"
+                    />
+                    <button
+                        onClick={saveSystemPrompt}
+                        disabled={systemPromptSaved}
+                        className="px-2 py-1 bg-blue-600 hover:bg-blue-700 disabled:bg-gray-300 rounded text-white"
+                    >
+                        Save
+                    </button>
+                </details>
+                <details className="mt-1 text-xs text-gray-600">
+                    <summary className="cursor-pointer">Brand profile</summary>
+                    {([
+                        ['heading_font', 'Heading font', 'e.g. Montserrat'],
+                        ['body_font', 'Body font', 'e.g. Open Sans'],
+                        ['palette', 'Colors', '#0B3D91, #FC3D21 (primary first)'],
+                        ['logo_path', 'Logo file', '/path/to/logo.png'],
+                        ['footer_text', 'Footer', 'e.g. Acme Corp – Confidential'],
+                    ] as [keyof typeof brand, string, string][]).map(([field, label, placeholder]) => (
+                        <label key={field} className="mt-1 flex items-center space-x-2">
+                            <span className="w-20">{label}</span>
+                            <input
+                                value={brand[field]}
+                                onChange={(e) => updateBrand(field, e.target.value)}
+                                placeholder={placeholder}
+                                className="flex-1 border border-gray-200 rounded px-2 py-1 text-xs"
+                            />
+                        </label>
+                    ))}
+                    {brandError && <div className="mt-1 text-red-600">{brandError}</div>}
+                    <button
+                        onClick={saveBrand}
+                        disabled={brandSaved}
+                        className="mt-1 px-2 py-1 bg-blue-600 hover:bg-blue-700 disabled:bg-gray-300 rounded text-white"
+                    >
+                        Save
+                    </button>
+                </details>
+                <details className="mt-1 text-xs text-gray-600">
+                    <summary className="cursor-pointer">Stock photos</summary>
+                    {([
+                        ['unsplash_access_key', 'Unsplash', 'Unsplash access key'],
+                        ['pexels_api_key', 'Pexels', 'Pexels API key'],
+                    ] as [keyof typeof stockKeys, string, string][]).map(([field, label, placeholder]) => (
+                        <label key={field} className="mt-1 flex items-center space-x-2">
+                            <span className="w-20">{label}</span>
+                            <input
+                                type="password"
+                                value={stockKeys[field]}
+                                onChange={(e) => updateStockKey(field, e.target.value)}
+                                placeholder={placeholder}
+                                className="flex-1 border border-gray-200 rounded px-2 py-1 text-xs"
+                            />
+                        </label>
+                    ))}
+                    {stockKeysError && <div className="mt-1 text-red-600">{stockKeysError}</div>}
+                    <button
+                        onClick={saveStockKeys}
+                        disabled={stockKeysSaved}
+                        className="mt-1 px-2 py-1 bg-blue-600 hover:bg-blue-700 disabled:bg-gray-300 rounded text-white"
+                    >
+                        Save
+                    </button>
+                </details>
             </div>
 
             {/* Messages */}
@@ -141,6 +495,60 @@ const ChatPanel: React.FC<ChatPanelProps> = ({ onSendMessage }) => {
                     </div>
                 ))}
                 
+                {approvals.map((request) => (
+                    <div key={request.id} className="border border-amber-300 bg-amber-50 rounded-lg p-3">
+                        <div className="text-sm font-medium text-gray-900 mb-1">
+                            Approve {request.display_name}?
+                        </div>
+                        <pre className="text-xs text-gray-700 whitespace-pre-wrap mb-2">
+                            {JSON.stringify(request.input, null, 2)}
+                        </pre>
+                        <div className="flex space-x-2">
+                            <button
+                                onClick={() => answerApproval(request.id, true)}
+                                className="px-3 py-1 bg-blue-600 hover:bg-blue-700 rounded text-white text-sm"
+                            >
+                                Approve
+                            </button>
+                            <button
+                                onClick={() => answerApproval(request.id, false)}
+                                className="px-3 py-1 bg-gray-200 hover:bg-gray-300 rounded text-gray-900 text-sm"
+                            >
+                                Deny
+                            </button>
+                        </div>
+                    </div>
+                ))}
+
+                {plans.map((plan) => (
+                    <div key={plan.id} className="border border-blue-200 bg-blue-50 rounded-lg p-3">
+                        <div className="text-sm font-medium text-gray-900 mb-1">{plan.summary}</div>
+                        <ol className="text-xs text-gray-700 space-y-1 mb-2">
+                            {plan.steps.map((step, index) => (
+                                <li key={index} title={JSON.stringify(step.input, null, 2)}>
+                                    {planStatusIcons[planStatus[plan.id]?.[index]] || `${index + 1}.`} {step.description || step.tool}
+                                </li>
+                            ))}
+                        </ol>
+                        {pendingPlans.includes(plan.id) && (
+                            <div className="flex space-x-2">
+                                <button
+                                    onClick={() => answerPlan(plan.id, true)}
+                                    className="px-3 py-1 bg-blue-600 hover:bg-blue-700 rounded text-white text-sm"
+                                >
+                                    Run plan
+                                </button>
+                                <button
+                                    onClick={() => answerPlan(plan.id, false)}
+                                    className="px-3 py-1 bg-gray-200 hover:bg-gray-300 rounded text-gray-900 text-sm"
+                                >
+                                    Reject
+                                </button>
+                            </div>
+                        )}
+                    </div>
+                ))}
+
                 {isLoading && (
                     <div className="flex items-start space-x-3">
                         <div className="w-8 h-8 bg-blue-600 rounded-full flex items-center justify-center text-white text-sm font-medium">
@@ -153,7 +561,9 @@ const ChatPanel: React.FC<ChatPanelProps> = ({ onSendMessage }) => {
                                     <div className="w-2 h-2 bg-gray-400 rounded-full animate-bounce" style={{ animationDelay: '0.1s' }}></div>
                                     <div className="w-2 h-2 bg-gray-400 rounded-full animate-bounce" style={{ animationDelay: '0.2s' }}></div>
                                 </div>
-                                <span className="text-sm text-gray-600">AI is thinking...</span>
+                                <span className="text-sm text-gray-600">
+                                    {retry ? `Retrying (attempt ${retry.attempt} of ${retry.max_attempts})...` : 'AI is thinking...'}
+                                </span>
                             </div>
                         </div>
                     </div>
@@ -173,13 +583,22 @@ const ChatPanel: React.FC<ChatPanelProps> = ({ onSendMessage }) => {
                         className="flex-1 px-3 py-2 border border-gray-300 rounded-lg text-gray-900 placeholder-gray-500 focus:outline-none focus:border-blue-500 focus:ring-1 focus:ring-blue-500"
                         disabled={isLoading}
                     />
-                    <button
-                        onClick={handleSendMessage}
-                        disabled={!inputMessage.trim() || isLoading}
-                        className="px-4 py-2 bg-blue-600 hover:bg-blue-700 disabled:bg-gray-300 disabled:cursor-not-allowed rounded-lg text-white font-medium transition-colors"
-                    >
-                        Send
-                    </button>
+                    {isLoading ? (
+                        <button
+                            onClick={() => CancelAIRequest()}
+                            className="px-4 py-2 bg-red-600 hover:bg-red-700 rounded-lg text-white font-medium transition-colors"
+                        >
+                            Stop
+                        </button>
+                    ) : (
+                        <button
+                            onClick={handleSendMessage}
+                            disabled={!inputMessage.trim()}
+                            className="px-4 py-2 bg-blue-600 hover:bg-blue-700 disabled:bg-gray-300 disabled:cursor-not-allowed rounded-lg text-white font-medium transition-colors"
+                        >
+                            Send
+                        </button>
+                    )}
                 </div>
                 
                 {/* Suggestions */}
@@ -1,26 +1,85 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {main} from '../models';
+
+export function CancelAIRequest():Promise<void>;
 
 export function CheckSlideExists(arg1:string):Promise<boolean>;
 
 export function ClearImageCache():Promise<void>;
 
+export function DeleteConversation(arg1:string):Promise<void>;
+
+export function ExportPDF(arg1:string,arg2:main.PDFExportOptions):Promise<string>;
+
+export function ExportPDFDialog(arg1:main.PDFExportOptions):Promise<string>;
+
+export function GenerateFromOutline(arg1:string):Promise<Array<string>>;
+
+export function GenerateFromOutlineDialog():Promise<Array<string>>;
+
+export function GetConfirmDestructive():Promise<boolean>;
+
 export function GetCurrentPresentationName():Promise<string>;
 
+export function GetHistoryState():Promise<main.HistoryState>;
+
+export function GetLibreOfficeStatus():Promise<main.LibreOfficeStatus>;
+
+export function GetRecentPresentations():Promise<Array<main.RecentPresentation>>;
+
+export function GetSettings():Promise<main.Settings>;
+
 export function GetSlideImageAsBase64(arg1:string):Promise<string>;
 
 export function GetSlideImagePath(arg1:string):Promise<string>;
 
 export function GetSlideImageQuiet(arg1:string):Promise<string>;
 
+export function GetSlideImageURL(arg1:string):Promise<string>;
+
 export function GetSlides():Promise<Array<string>>;
 
+export function GetUsageStats():Promise<main.UsageStats>;
+
 export function Greet(arg1:string):Promise<string>;
 
 export function HasPresentationLoaded():Promise<boolean>;
 
+export function ListBackups():Promise<Array<main.BackupInfo>>;
+
+export function ListModels():Promise<Array<main.ModelOption>>;
+
+export function ListConversations():Promise<Array<main.ConversationSummary>>;
+
+export function LoadConversation(arg1:string):Promise<Array<main.ConversationMessage>>;
+
 export function LoadPresentation(arg1:string):Promise<Array<string>>;
 
+export function NewPresentation(arg1:string,arg2:string,arg3:string):Promise<Array<string>>;
+
+export function NewPresentationDialog(arg1:string,arg2:string):Promise<Array<string>>;
+
 export function OpenPresentationDialog():Promise<Array<string>>;
 
+export function OpenRecent(arg1:string):Promise<Array<string>>;
+
+export function Redo():Promise<Array<string>>;
+
+export function ReloadPresentation():Promise<Array<string>>;
+
+export function RespondToolApproval(arg1:string,arg2:boolean):Promise<void>;
+
+export function RestoreBackup(arg1:string):Promise<Array<string>>;
+
+export function SavePresentationAs(arg1:string):Promise<string>;
+
+export function SavePresentationAsDialog():Promise<string>;
+
 export function SendMessageToAI(arg1:string):Promise<void>;
+
+export function SetConfirmDestructive(arg1:boolean):Promise<void>;
+
+export function Undo():Promise<Array<string>>;
+
+export function UpdateSettings(arg1:main.Settings):Promise<main.Settings>;
@@ -2,6 +2,10 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function CancelAIRequest() {
+  return window['go']['main']['App']['CancelAIRequest']();
+}
+
 export function CheckSlideExists(arg1) {
   return window['go']['main']['App']['CheckSlideExists'](arg1);
 }
@@ -10,10 +14,50 @@ export function ClearImageCache() {
   return window['go']['main']['App']['ClearImageCache']();
 }
 
+export function DeleteConversation(arg1) {
+  return window['go']['main']['App']['DeleteConversation'](arg1);
+}
+
+export function ExportPDF(arg1, arg2) {
+  return window['go']['main']['App']['ExportPDF'](arg1, arg2);
+}
+
+export function ExportPDFDialog(arg1) {
+  return window['go']['main']['App']['ExportPDFDialog'](arg1);
+}
+
+export function GenerateFromOutline(arg1) {
+  return window['go']['main']['App']['GenerateFromOutline'](arg1);
+}
+
+export function GenerateFromOutlineDialog() {
+  return window['go']['main']['App']['GenerateFromOutlineDialog']();
+}
+
+export function GetConfirmDestructive() {
+  return window['go']['main']['App']['GetConfirmDestructive']();
+}
+
 export function GetCurrentPresentationName() {
   return window['go']['main']['App']['GetCurrentPresentationName']();
 }
 
+export function GetHistoryState() {
+  return window['go']['main']['App']['GetHistoryState']();
+}
+
+export function GetLibreOfficeStatus() {
+  return window['go']['main']['App']['GetLibreOfficeStatus']();
+}
+
+export function GetRecentPresentations() {
+  return window['go']['main']['App']['GetRecentPresentations']();
+}
+
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
 export function GetSlideImageAsBase64(arg1) {
   return window['go']['main']['App']['GetSlideImageAsBase64'](arg1);
 }
@@ -26,10 +70,18 @@ export function GetSlideImageQuiet(arg1) {
   return window['go']['main']['App']['GetSlideImageQuiet'](arg1);
 }
 
+export function GetSlideImageURL(arg1) {
+  return window['go']['main']['App']['GetSlideImageURL'](arg1);
+}
+
 export function GetSlides() {
   return window['go']['main']['App']['GetSlides']();
 }
 
+export function GetUsageStats() {
+  return window['go']['main']['App']['GetUsageStats']();
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
@@ -38,14 +90,78 @@ export function HasPresentationLoaded() {
   return window['go']['main']['App']['HasPresentationLoaded']();
 }
 
+export function ListBackups() {
+  return window['go']['main']['App']['ListBackups']();
+}
+
+export function ListModels() {
+  return window['go']['main']['App']['ListModels']();
+}
+
+export function ListConversations() {
+  return window['go']['main']['App']['ListConversations']();
+}
+
+export function LoadConversation(arg1) {
+  return window['go']['main']['App']['LoadConversation'](arg1);
+}
+
 export function LoadPresentation(arg1) {
   return window['go']['main']['App']['LoadPresentation'](arg1);
 }
 
+export function NewPresentation(arg1, arg2, arg3) {
+  return window['go']['main']['App']['NewPresentation'](arg1, arg2, arg3);
+}
+
+export function NewPresentationDialog(arg1, arg2) {
+  return window['go']['main']['App']['NewPresentationDialog'](arg1, arg2);
+}
+
 export function OpenPresentationDialog() {
   return window['go']['main']['App']['OpenPresentationDialog']();
 }
 
+export function OpenRecent(arg1) {
+  return window['go']['main']['App']['OpenRecent'](arg1);
+}
+
+export function Redo() {
+  return window['go']['main']['App']['Redo']();
+}
+
+export function ReloadPresentation() {
+  return window['go']['main']['App']['ReloadPresentation']();
+}
+
+export function RespondToolApproval(arg1, arg2) {
+  return window['go']['main']['App']['RespondToolApproval'](arg1, arg2);
+}
+
+export function RestoreBackup(arg1) {
+  return window['go']['main']['App']['RestoreBackup'](arg1);
+}
+
+export function SavePresentationAs(arg1) {
+  return window['go']['main']['App']['SavePresentationAs'](arg1);
+}
+
+export function SavePresentationAsDialog() {
+  return window['go']['main']['App']['SavePresentationAsDialog']();
+}
+
 export function SendMessageToAI(arg1) {
   return window['go']['main']['App']['SendMessageToAI'](arg1);
 }
+
+export function SetConfirmDestructive(arg1) {
+  return window['go']['main']['App']['SetConfirmDestructive'](arg1);
+}
+
+export function Undo() {
+  return window['go']['main']['App']['Undo']();
+}
+
+export function UpdateSettings(arg1) {
+  return window['go']['main']['App']['UpdateSettings'](arg1);
+}
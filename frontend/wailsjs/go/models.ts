@@ -0,0 +1,335 @@
+export namespace main {
+	
+	export class BackupInfo {
+	    path: string;
+	    name: string;
+	    // Go type: time
+	    created_at: any;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.name = source["name"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.size = source["size"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BrandProfile {
+	    heading_font?: string;
+	    body_font?: string;
+	    palette?: string[];
+	    logo_path?: string;
+	    footer_text?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BrandProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.heading_font = source["heading_font"];
+	        this.body_font = source["body_font"];
+	        this.palette = source["palette"];
+	        this.logo_path = source["logo_path"];
+	        this.footer_text = source["footer_text"];
+	    }
+	}
+	export class ConversationMessage {
+	    role: string;
+	    content: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConversationMessage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.role = source["role"];
+	        this.content = source["content"];
+	    }
+	}
+	export class ConversationSummary {
+	    presentation_path: string;
+	    name: string;
+	    // Go type: time
+	    updated_at: any;
+	    message_count: number;
+	    last_request: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConversationSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.presentation_path = source["presentation_path"];
+	        this.name = source["name"];
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	        this.message_count = source["message_count"];
+	        this.last_request = source["last_request"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HistoryState {
+	    can_undo: boolean;
+	    can_redo: boolean;
+	    undo_label: string;
+	    redo_label: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.can_undo = source["can_undo"];
+	        this.can_redo = source["can_redo"];
+	        this.undo_label = source["undo_label"];
+	        this.redo_label = source["redo_label"];
+	    }
+	}
+	export class LibreOfficeStatus {
+	    state: string;
+	    pid: number;
+	    restarts: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LibreOfficeStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.state = source["state"];
+	        this.pid = source["pid"];
+	        this.restarts = source["restarts"];
+	        this.error = source["error"];
+	    }
+	}
+	export class ModelOption {
+	    id: string;
+	    display_name: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelOption(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.display_name = source["display_name"];
+	    }
+	}
+	export class PDFExportOptions {
+	    layout: string;
+	    slides_per_page: number;
+	    first_slide: number;
+	    last_slide: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PDFExportOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.layout = source["layout"];
+	        this.slides_per_page = source["slides_per_page"];
+	        this.first_slide = source["first_slide"];
+	        this.last_slide = source["last_slide"];
+	    }
+	}
+	export class RecentPresentation {
+	    path: string;
+	    name: string;
+	    // Go type: time
+	    last_opened: any;
+	    slide_count: number;
+	    thumbnail?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RecentPresentation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.name = source["name"];
+	        this.last_opened = this.convertValues(source["last_opened"], null);
+	        this.slide_count = source["slide_count"];
+	        this.thumbnail = source["thumbnail"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Settings {
+	    model: string;
+	    max_tokens: number;
+	    temperature?: number;
+	    system_prompt?: string;
+	    plan_mode: boolean;
+	    review_mode: boolean;
+	    brand?: BrandProfile;
+	    unsplash_access_key?: string;
+	    pexels_api_key?: string;
+	    api_token?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = source["model"];
+	        this.max_tokens = source["max_tokens"];
+	        this.temperature = source["temperature"];
+	        this.system_prompt = source["system_prompt"];
+	        this.plan_mode = source["plan_mode"];
+	        this.review_mode = source["review_mode"];
+	        this.brand = this.convertValues(source["brand"], BrandProfile);
+	        this.unsplash_access_key = source["unsplash_access_key"];
+	        this.pexels_api_key = source["pexels_api_key"];
+	        this.api_token = source["api_token"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TokenUsage {
+	    requests: number;
+	    input_tokens: number;
+	    output_tokens: number;
+	    cache_creation_tokens: number;
+	    cache_read_tokens: number;
+	    estimated_cost_usd: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TokenUsage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.requests = source["requests"];
+	        this.input_tokens = source["input_tokens"];
+	        this.output_tokens = source["output_tokens"];
+	        this.cache_creation_tokens = source["cache_creation_tokens"];
+	        this.cache_read_tokens = source["cache_read_tokens"];
+	        this.estimated_cost_usd = source["estimated_cost_usd"];
+	    }
+	}
+	export class UsageStats {
+	    requests: number;
+	    input_tokens: number;
+	    output_tokens: number;
+	    cache_creation_tokens: number;
+	    cache_read_tokens: number;
+	    estimated_cost_usd: number;
+	    by_model: Record<string, TokenUsage>;
+	    unpriced_models?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new UsageStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.requests = source["requests"];
+	        this.input_tokens = source["input_tokens"];
+	        this.output_tokens = source["output_tokens"];
+	        this.cache_creation_tokens = source["cache_creation_tokens"];
+	        this.cache_read_tokens = source["cache_read_tokens"];
+	        this.estimated_cost_usd = source["estimated_cost_usd"];
+	        this.by_model = this.convertValues(source["by_model"], TokenUsage, true);
+	        this.unpriced_models = source["unpriced_models"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// historyDirName is created next to each edited presentation to hold its snapshots
+const historyDirName = ".slidepilot"
+
+// maxHistoryEntries bounds the undo stack of a presentation; older snapshots are deleted
+const maxHistoryEntries = 50
+
+// errNothingToUndo and errNothingToRedo are returned when a stack is empty
+var (
+	errNothingToUndo = errors.New("nothing to undo")
+	errNothingToRedo = errors.New("nothing to redo")
+)
+
+// EditHistory keeps undo and redo snapshots of presentations. Before every successful
+// mutating tool call the previous version of the file is saved under
+// <presentation dir>/.slidepilot/history/<name>-<hash>/, so edits can be stepped back
+// and forth across restarts.
+type EditHistory struct {
+	mu sync.Mutex
+}
+
+// historyEntry is one saved version of a presentation
+type historyEntry struct {
+	File  string    `json:"file"`  // Snapshot file name inside the history directory
+	Label string    `json:"label"` // The change that followed (undo) or was undone (redo)
+	Time  time.Time `json:"time"`
+	Turn  int       `json:"turn"` // AI turn that recorded the entry, so a rolled back turn can drop it
+}
+
+// historyIndex is the on-disk state of one presentation's history
+type historyIndex struct {
+	Undo   []historyEntry `json:"undo"` // Oldest first
+	Redo   []historyEntry `json:"redo"` // Oldest first
+	NextID int            `json:"next_id"`
+}
+
+// HistoryState describes what Undo and Redo would do, for the frontend
+type HistoryState struct {
+	CanUndo   bool   `json:"can_undo"`
+	CanRedo   bool   `json:"can_redo"`
+	UndoLabel string `json:"undo_label"`
+	RedoLabel string `json:"redo_label"`
+}
+
+// NewEditHistory creates the history manager
+func NewEditHistory() *EditHistory {
+	return &EditHistory{}
+}
+
+// historyDir returns where a presentation's snapshots live; the path is made absolute
+// first so a relative name for the same deck shares its history
+func historyDir(presentationPath string) string {
+	presentationPath = absPresentationPath(presentationPath)
+	sum := sha256.Sum256([]byte(presentationPath))
+	name := strings.TrimSuffix(filepath.Base(presentationPath), filepath.Ext(presentationPath))
+	return filepath.Join(filepath.Dir(presentationPath), historyDirName, "history", name+"-"+hex.EncodeToString(sum[:])[:8])
+}
+
+// Record saves snapshotPath, the presentation's content before a change, as the newest
+// undo entry and clears the redo stack
+func (h *EditHistory) Record(presentationPath, snapshotPath, label string, turn int) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	dir := historyDir(presentationPath)
+	index, err := readHistoryIndex(dir)
+	if err != nil {
+		return err
+	}
+	entry, err := index.store(dir, snapshotPath, label, turn)
+	if err != nil {
+		return err
+	}
+	index.Undo = append(index.Undo, entry)
+	index.dropRedo(dir)
+
+	for len(index.Undo) > maxHistoryEntries {
+		os.Remove(filepath.Join(dir, index.Undo[0].File))
+		index.Undo = index.Undo[1:]
+	}
+	return index.write(dir)
+}
+
+// Undo restores the newest undo snapshot, keeping the current content for Redo, and
+// returns the label of the undone change
+func (h *EditHistory) Undo(presentationPath string, turn int) (string, error) {
+	return h.step(presentationPath, turn, true)
+}
+
+// Redo reapplies the newest undone change and returns its label
+func (h *EditHistory) Redo(presentationPath string, turn int) (string, error) {
+	return h.step(presentationPath, turn, false)
+}
+
+func (h *EditHistory) step(presentationPath string, turn int, undo bool) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	dir := historyDir(presentationPath)
+	index, err := readHistoryIndex(dir)
+	if err != nil {
+		return "", err
+	}
+
+	from, to := &index.Undo, &index.Redo
+	if !undo {
+		from, to = &index.Redo, &index.Undo
+	}
+	if len(*from) == 0 {
+		if undo {
+			return "", errNothingToUndo
+		}
+		return "", errNothingToRedo
+	}
+	target := (*from)[len(*from)-1]
+
+	// Save the current content on the opposite stack before overwriting it
+	current, err := index.store(dir, presentationPath, target.Label, turn)
+	if err != nil {
+		return "", err
+	}
+	if err := copyFile(filepath.Join(dir, target.File), presentationPath); err != nil {
+		os.Remove(filepath.Join(dir, current.File))
+		return "", fmt.Errorf("failed to restore snapshot: %v", err)
+	}
+
+	os.Remove(filepath.Join(dir, target.File))
+	*from = (*from)[:len(*from)-1]
+	*to = append(*to, current)
+	return target.Label, index.write(dir)
+}
+
+// DiscardTurn drops the entries an AI turn added, after the turn was rolled back and the
+// file is back at its state from before the turn
+func (h *EditHistory) DiscardTurn(presentationPath string, turn int) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	dir := historyDir(presentationPath)
+	index, err := readHistoryIndex(dir)
+	if err != nil {
+		return err
+	}
+	keep := func(entries []historyEntry) []historyEntry {
+		var kept []historyEntry
+		for _, entry := range entries {
+			if entry.Turn == turn {
+				os.Remove(filepath.Join(dir, entry.File))
+				continue
+			}
+			kept = append(kept, entry)
+		}
+		return kept
+	}
+	index.Undo = keep(index.Undo)
+	index.Redo = keep(index.Redo)
+	return index.write(dir)
+}
+
+// State reports what Undo and Redo would do for a presentation
+func (h *EditHistory) State(presentationPath string) HistoryState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	index, err := readHistoryIndex(historyDir(presentationPath))
+	if err != nil {
+		return HistoryState{}
+	}
+	state := HistoryState{CanUndo: len(index.Undo) > 0, CanRedo: len(index.Redo) > 0}
+	if state.CanUndo {
+		state.UndoLabel = index.Undo[len(index.Undo)-1].Label
+	}
+	if state.CanRedo {
+		state.RedoLabel = index.Redo[len(index.Redo)-1].Label
+	}
+	return state
+}
+
+// store copies a file into the history directory as a new snapshot
+func (index *historyIndex) store(dir, source, label string, turn int) (historyEntry, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return historyEntry{}, fmt.Errorf("failed to create history directory: %v", err)
+	}
+	index.NextID++
+	entry := historyEntry{
+		File:  fmt.Sprintf("%06d%s", index.NextID, filepath.Ext(source)),
+		Label: label,
+		Time:  time.Now(),
+		Turn:  turn,
+	}
+	if err := copyFile(source, filepath.Join(dir, entry.File)); err != nil {
+		return historyEntry{}, fmt.Errorf("failed to save history snapshot: %v", err)
+	}
+	return entry, nil
+}
+
+// dropRedo deletes the redo stack, which no longer applies after a new change
+func (index *historyIndex) dropRedo(dir string) {
+	for _, entry := range index.Redo {
+		os.Remove(filepath.Join(dir, entry.File))
+	}
+	index.Redo = nil
+}
+
+func readHistoryIndex(dir string) (*historyIndex, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return &historyIndex{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edit history: %v", err)
+	}
+	var index historyIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("invalid edit history %s: %v", dir, err)
+	}
+	return &index, nil
+}
+
+func (index *historyIndex) write(dir string) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode edit history: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %v", err)
+	}
+	tmp := filepath.Join(dir, "index.json.tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write edit history: %v", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, "index.json")); err != nil {
+		return fmt.Errorf("failed to write edit history: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// appendingEdit makes uno_edit_slide.py change the file by appending a byte
+func appendingEdit(env *testEnv) {
+	env.uno.Handle("uno_edit_slide.py", func(args []string) ([]byte, error) {
+		f, _ := os.OpenFile(args[0], os.O_APPEND|os.O_WRONLY, 0644)
+		f.Write([]byte{0})
+		f.Close()
+		return []byte(`{"success": true}`), nil
+	})
+}
+
+func editSlideInput(text string) []byte {
+	return []byte(fmt.Sprintf(`{"slide_number": 1, "target_type": "shape_index", "target_value": "0", "new_text": %q}`, text))
+}
+
+func TestUndoRedoRestoresEdits(t *testing.T) {
+	t.Setenv(visionFeedbackEnv, "0")
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	appendingEdit(env)
+
+	original, _ := os.ReadFile(path)
+	env.app.aiAgent.executeTool(context.Background(), "toolu_1", "edit_slide_text", editSlideInput("one"))
+	afterFirst, _ := os.ReadFile(path)
+	env.app.aiAgent.executeTool(context.Background(), "toolu_2", "edit_slide_text", editSlideInput("two"))
+	afterSecond, _ := os.ReadFile(path)
+
+	if state := env.app.GetHistoryState(); !state.CanUndo || state.CanRedo || state.UndoLabel != "edit_slide_text" {
+		t.Errorf("unexpected history state %+v", state)
+	}
+
+	for _, want := range [][]byte{afterFirst, original} {
+		if _, err := env.app.Undo(); err != nil {
+			t.Fatalf("Undo failed: %v", err)
+		}
+		if got, _ := os.ReadFile(path); !bytes.Equal(got, want) {
+			t.Fatalf("undo restored %d bytes, expected %d", len(got), len(want))
+		}
+	}
+	if _, err := env.app.Undo(); err == nil {
+		t.Error("expected an error with nothing left to undo")
+	}
+
+	for _, want := range [][]byte{afterFirst, afterSecond} {
+		if _, err := env.app.Redo(); err != nil {
+			t.Fatalf("Redo failed: %v", err)
+		}
+		if got, _ := os.ReadFile(path); !bytes.Equal(got, want) {
+			t.Fatalf("redo restored %d bytes, expected %d", len(got), len(want))
+		}
+	}
+
+	// A new change after undoing drops the redo stack
+	env.app.Undo()
+	env.app.aiAgent.executeTool(context.Background(), "toolu_3", "edit_slide_text", editSlideInput("three"))
+	if state := env.app.GetHistoryState(); state.CanRedo {
+		t.Errorf("expected the redo stack to be cleared, got %+v", state)
+	}
+}
+
+func TestRolledBackTurnLeavesNoHistory(t *testing.T) {
+	env := newTestEnv(t,
+		toolUseResponse("toolu_1", "edit_slide_text", string(editSlideInput("First"))),
+		toolUseResponse("toolu_2", "delete_slide", `{"slide_number": 5}`),
+		textResponse("Something went wrong."),
+	)
+	env.loadFixture(t, "two_slides.pptx")
+	appendingEdit(env)
+	env.uno.Handle("uno_delete_slide.py", func(args []string) ([]byte, error) {
+		return []byte(`{"success": false, "error": "Invalid slide number 5"}`), fmt.Errorf("exit status 1")
+	})
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Edit and delete"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if state := env.app.GetHistoryState(); state.CanUndo || state.CanRedo {
+		t.Errorf("the rolled back edit should not be undoable, got %+v", state)
+	}
+}
+
+func TestUndoLastChangeTool(t *testing.T) {
+	t.Setenv(visionFeedbackEnv, "0")
+	env := newTestEnv(t,
+		toolUseResponse("toolu_1", "edit_slide_text", string(editSlideInput("Oops"))),
+		toolUseResponse("toolu_2", "undo_last_change", `{}`),
+		textResponse("Reverted."),
+	)
+	path := env.loadFixture(t, "two_slides.pptx")
+	appendingEdit(env)
+	original, _ := os.ReadFile(path)
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Edit, then revert"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, original) {
+		t.Error("expected the edit to be undone")
+	}
+	if state := env.app.GetHistoryState(); state.CanUndo || !state.CanRedo {
+		t.Errorf("expected only a redo entry, got %+v", state)
+	}
+
+	result := env.app.aiAgent.executeTool(context.Background(), "toolu_3", "undo_last_change", []byte(`{}`))
+	if content := result.OfToolResult.Content[0].OfText.Text; !strings.Contains(content, string(ErrCodeNothingToUndo)) {
+		t.Errorf("expected NOTHING_TO_UNDO, got %s", content)
+	}
+}
+
+func TestHistoryNormalizesPaths(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	original, _ := os.ReadFile(path)
+
+	// A change recorded under a relative name must be undoable from the absolute path the app uses
+	wd, _ := os.Getwd()
+	relative, err := filepath.Rel(wd, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.app.history.Record("./"+relative, path, "edit_slide_text", 0); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	os.WriteFile(path, []byte("changed"), 0644)
+
+	if state := env.app.GetHistoryState(); !state.CanUndo {
+		t.Fatalf("expected the change to be undoable, got %+v", state)
+	}
+	if _, err := env.app.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, original) {
+		t.Error("expected undo to restore the recorded snapshot")
+	}
+}
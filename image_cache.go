@@ -0,0 +1,117 @@
+package main
+
+import (
+	"container/list"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultImageCacheBytes caps the data URIs kept in memory
+const defaultImageCacheBytes = 32 << 20
+
+// ImageCache keeps slide image data URIs, evicting the least recently used ones once their
+// total size passes the cap. Each entry remembers the file's modification time and size,
+// so an image re-rendered on disk is a miss while previews the export skipped stay cached.
+type ImageCache struct {
+	mu       sync.Mutex
+	maxBytes int
+	size     int
+	order    *list.List // Front is the most recently used
+	entries  map[string]*list.Element
+}
+
+// imageCacheEntry is one cached image
+type imageCacheEntry struct {
+	path     string
+	dataURI  string
+	modTime  time.Time
+	fileSize int64
+}
+
+// NewImageCache creates a cache holding up to maxBytes of data URIs
+func NewImageCache(maxBytes int) *ImageCache {
+	return &ImageCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns the data URI of an image, unless it isn't cached or the file changed since
+func (c *ImageCache) Get(path string) (string, bool) {
+	info, err := os.Stat(path)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[path]
+	if !ok {
+		return "", false
+	}
+	entry := element.Value.(*imageCacheEntry)
+	if err != nil || !info.ModTime().Equal(entry.modTime) || info.Size() != entry.fileSize {
+		c.removeLocked(element)
+		return "", false
+	}
+	c.order.MoveToFront(element)
+	return entry.dataURI, true
+}
+
+// Put caches the data URI of an image read from a file with the given info. URIs larger
+// than the whole cache aren't kept.
+func (c *ImageCache) Put(path, dataURI string, info os.FileInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[path]; ok {
+		c.removeLocked(element)
+	}
+	if len(dataURI) > c.maxBytes {
+		return
+	}
+
+	c.entries[path] = c.order.PushFront(&imageCacheEntry{path: path, dataURI: dataURI, modTime: info.ModTime(), fileSize: info.Size()})
+	c.size += len(dataURI)
+	for c.size > c.maxBytes {
+		c.removeLocked(c.order.Back())
+	}
+}
+
+// Invalidate drops the given images
+func (c *ImageCache) Invalidate(paths ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, path := range paths {
+		if element, ok := c.entries[path]; ok {
+			c.removeLocked(element)
+		}
+	}
+}
+
+// Clear drops every image
+func (c *ImageCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+	c.size = 0
+}
+
+// Len returns the number of cached images
+func (c *ImageCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Size returns the total length of the cached data URIs
+func (c *ImageCache) Size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+func (c *ImageCache) removeLocked(element *list.Element) {
+	entry := c.order.Remove(element).(*imageCacheEntry)
+	delete(c.entries, entry.path)
+	c.size -= len(entry.dataURI)
+}
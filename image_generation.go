@@ -0,0 +1,371 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// assetsDir is where generated images are stored alongside the slides directory
+const assetsDir = "assets"
+
+// ImageProvider generates an image from a text prompt and returns the encoded image bytes
+type ImageProvider interface {
+	Name() string
+	Generate(ctx context.Context, prompt, size string) ([]byte, error)
+}
+
+// NewImageProviderFromEnv builds the image provider configured through environment variables.
+//
+// SLIDEPILOT_IMAGE_PROVIDER selects the provider: "openai" (the default), "stability" for the
+// Stability AI API, or "stable-diffusion" for a local AUTOMATIC1111 or Forge web UI started
+// with --api. SLIDEPILOT_IMAGE_API_KEY holds the key (falling back to OPENAI_API_KEY or
+// STABILITY_API_KEY), SLIDEPILOT_IMAGE_MODEL overrides the model, and SLIDEPILOT_IMAGE_ENDPOINT
+// points at a compatible API or the local server.
+func NewImageProviderFromEnv() (ImageProvider, error) {
+	provider := strings.ToLower(os.Getenv("SLIDEPILOT_IMAGE_PROVIDER"))
+	if provider == "" {
+		provider = "openai"
+	}
+
+	switch provider {
+	case "openai":
+		apiKey := os.Getenv("SLIDEPILOT_IMAGE_API_KEY")
+		if apiKey == "" {
+			apiKey = os.Getenv("OPENAI_API_KEY")
+		}
+		if apiKey == "" {
+			return nil, fmt.Errorf("image generation requires SLIDEPILOT_IMAGE_API_KEY or OPENAI_API_KEY to be set")
+		}
+
+		endpoint := os.Getenv("SLIDEPILOT_IMAGE_ENDPOINT")
+		if endpoint == "" {
+			endpoint = "https://api.openai.com/v1"
+		}
+
+		model := os.Getenv("SLIDEPILOT_IMAGE_MODEL")
+		if model == "" {
+			model = "gpt-image-1"
+		}
+
+		return &OpenAIImageProvider{
+			endpoint: strings.TrimSuffix(endpoint, "/"),
+			apiKey:   apiKey,
+			model:    model,
+			client:   &http.Client{Timeout: 2 * time.Minute},
+		}, nil
+	case "stability":
+		apiKey := os.Getenv("SLIDEPILOT_IMAGE_API_KEY")
+		if apiKey == "" {
+			apiKey = os.Getenv("STABILITY_API_KEY")
+		}
+		if apiKey == "" {
+			return nil, fmt.Errorf("Stability image generation requires SLIDEPILOT_IMAGE_API_KEY or STABILITY_API_KEY to be set")
+		}
+
+		endpoint := os.Getenv("SLIDEPILOT_IMAGE_ENDPOINT")
+		if endpoint == "" {
+			endpoint = "https://api.stability.ai"
+		}
+
+		model := os.Getenv("SLIDEPILOT_IMAGE_MODEL")
+		if model == "" {
+			model = "core"
+		}
+
+		return &StabilityImageProvider{
+			endpoint: strings.TrimSuffix(endpoint, "/"),
+			apiKey:   apiKey,
+			model:    model,
+			client:   &http.Client{Timeout: 2 * time.Minute},
+		}, nil
+	case "stable-diffusion":
+		endpoint := os.Getenv("SLIDEPILOT_IMAGE_ENDPOINT")
+		if endpoint == "" {
+			endpoint = "http://127.0.0.1:7860"
+		}
+
+		return &StableDiffusionImageProvider{
+			endpoint: strings.TrimSuffix(endpoint, "/"),
+			model:    os.Getenv("SLIDEPILOT_IMAGE_MODEL"),
+			client:   &http.Client{Timeout: 5 * time.Minute}, // Local GPUs can be slow
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown image provider: %s", provider)
+	}
+}
+
+// parseImageSize reads a WIDTHxHEIGHT size such as 1536x1024, defaulting to a square
+func parseImageSize(size string) (width, height int, err error) {
+	if size == "" {
+		return 1024, 1024, nil
+	}
+	w, h, ok := strings.Cut(strings.ToLower(size), "x")
+	width, widthErr := strconv.Atoi(strings.TrimSpace(w))
+	height, heightErr := strconv.Atoi(strings.TrimSpace(h))
+	if !ok || widthErr != nil || heightErr != nil || width < 64 || height < 64 || width > 4096 || height > 4096 {
+		return 0, 0, fmt.Errorf("size must be WIDTHxHEIGHT in pixels between 64 and 4096, e.g. 1024x1024, got %q", size)
+	}
+	return width, height, nil
+}
+
+// OpenAIImageProvider calls the OpenAI images API (or any compatible endpoint)
+type OpenAIImageProvider struct {
+	endpoint string
+	apiKey   string
+	model    string
+	client   *http.Client
+}
+
+func (p *OpenAIImageProvider) Name() string {
+	return "openai"
+}
+
+func (p *OpenAIImageProvider) Generate(ctx context.Context, prompt, size string) ([]byte, error) {
+	if size == "" {
+		size = "1024x1024"
+	}
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"model":  p.model,
+		"prompt": prompt,
+		"size":   size,
+		"n":      1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.endpoint+"/images/generations", bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create image request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("image request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image API returned %s: %s", resp.Status, string(body))
+	}
+
+	var result struct {
+		Data []struct {
+			B64JSON string `json:"b64_json"`
+			URL     string `json:"url"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("invalid image API response: %v", err)
+	}
+	if len(result.Data) == 0 {
+		return nil, fmt.Errorf("image API returned no images")
+	}
+
+	// Some models only return a URL instead of inline base64 data
+	if result.Data[0].B64JSON != "" {
+		return base64.StdEncoding.DecodeString(result.Data[0].B64JSON)
+	}
+	return p.download(result.Data[0].URL)
+}
+
+func (p *OpenAIImageProvider) download(url string) ([]byte, error) {
+	if url == "" {
+		return nil, fmt.Errorf("image API returned neither image data nor URL")
+	}
+
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download generated image: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download generated image: %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// stabilityAspectRatios are the aspect ratios the Stability stable-image API generates
+var stabilityAspectRatios = []string{"21:9", "16:9", "3:2", "5:4", "1:1", "4:5", "2:3", "9:16", "9:21"}
+
+// StabilityImageProvider calls the Stability AI stable-image generation API
+type StabilityImageProvider struct {
+	endpoint string
+	apiKey   string
+	model    string // core, ultra, or an SD3 model such as sd3.5-large
+	client   *http.Client
+}
+
+func (p *StabilityImageProvider) Name() string {
+	return "stability"
+}
+
+func (p *StabilityImageProvider) Generate(ctx context.Context, prompt, size string) ([]byte, error) {
+	width, height, err := parseImageSize(size)
+	if err != nil {
+		return nil, err
+	}
+
+	// The API takes an aspect ratio rather than a size; use the closest one
+	aspectRatio := stabilityAspectRatios[0]
+	closest := math.Inf(1)
+	for _, ratio := range stabilityAspectRatios {
+		var w, h float64
+		fmt.Sscanf(ratio, "%g:%g", &w, &h)
+		if distance := math.Abs(math.Log(w/h) - math.Log(float64(width)/float64(height))); distance < closest {
+			aspectRatio, closest = ratio, distance
+		}
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	fields := map[string]string{"prompt": prompt, "aspect_ratio": aspectRatio, "output_format": "png"}
+	route := p.model
+	if strings.HasPrefix(p.model, "sd3") {
+		route = "sd3"
+		fields["model"] = p.model
+	}
+	for name, value := range fields {
+		form.WriteField(name, value)
+	}
+	form.Close()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.endpoint+"/v2beta/stable-image/generate/"+route, &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create image request: %v", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	req.Header.Set("Accept", "image/*")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("image request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image API returned %s: %s", resp.Status, string(data))
+	}
+	return data, nil
+}
+
+// StableDiffusionImageProvider calls the txt2img API of a local AUTOMATIC1111 or Forge web UI
+type StableDiffusionImageProvider struct {
+	endpoint string
+	model    string // Checkpoint to switch to; "" keeps the one loaded
+	client   *http.Client
+}
+
+func (p *StableDiffusionImageProvider) Name() string {
+	return "stable-diffusion"
+}
+
+func (p *StableDiffusionImageProvider) Generate(ctx context.Context, prompt, size string) ([]byte, error) {
+	width, height, err := parseImageSize(size)
+	if err != nil {
+		return nil, err
+	}
+
+	request := map[string]interface{}{
+		"prompt":     prompt,
+		"width":      width,
+		"height":     height,
+		"batch_size": 1,
+	}
+	if p.model != "" {
+		request["override_settings"] = map[string]string{"sd_model_checkpoint": p.model}
+	}
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.endpoint+"/sdapi/v1/txt2img", bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create image request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("image request failed (is the web UI running with --api?): %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image API returned %s: %s", resp.Status, string(body))
+	}
+
+	var result struct {
+		Images []string `json:"images"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("invalid image API response: %v", err)
+	}
+	if len(result.Images) == 0 {
+		return nil, fmt.Errorf("image API returned no images")
+	}
+	return base64.StdEncoding.DecodeString(result.Images[0])
+}
+
+// saveGeneratedImage writes image bytes into the assets directory and returns the absolute path
+func saveGeneratedImage(data []byte, name string) (string, error) {
+	if err := os.MkdirAll(assetsDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create assets directory: %v", err)
+	}
+
+	// Detect the actual format so the file extension matches the content
+	ext := ".png"
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		ext = ".jpg"
+	case "image/webp":
+		ext = ".webp"
+	}
+
+	if name == "" {
+		name = fmt.Sprintf("generated-%s", time.Now().Format("20060102-150405"))
+	}
+	name = strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+
+	imagePath, err := filepath.Abs(filepath.Join(assetsDir, name+ext))
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(imagePath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to save generated image: %v", err)
+	}
+
+	return imagePath, nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenAIImageProvider(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\nimage")
+	var request map[string]interface{}
+	var auth string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/images/generations":
+			auth = r.Header.Get("Authorization")
+			json.NewDecoder(r.Body).Decode(&request)
+			// Some models answer with a URL to download instead of inline data
+			if request["prompt"] == "by url" {
+				json.NewEncoder(w).Encode(map[string]interface{}{"data": []map[string]string{{"url": server.URL + "/download"}}})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": []map[string]string{{"b64_json": base64.StdEncoding.EncodeToString(png)}}})
+		case "/download":
+			w.Write(png)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("SLIDEPILOT_IMAGE_PROVIDER", "")
+	t.Setenv("SLIDEPILOT_IMAGE_API_KEY", "sk-test")
+	t.Setenv("SLIDEPILOT_IMAGE_ENDPOINT", server.URL+"/")
+	t.Setenv("SLIDEPILOT_IMAGE_MODEL", "")
+	provider, err := NewImageProviderFromEnv()
+	if err != nil {
+		t.Fatalf("NewImageProviderFromEnv failed: %v", err)
+	}
+
+	data, err := provider.Generate(context.Background(), "a lighthouse", "")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !bytes.Equal(data, png) || auth != "Bearer sk-test" {
+		t.Errorf("unexpected image %q with authorization %q", data, auth)
+	}
+	if request["model"] != "gpt-image-1" || request["size"] != "1024x1024" {
+		t.Errorf("expected the default model and size, got %v", request)
+	}
+	if data, err := provider.Generate(context.Background(), "by url", "1536x1024"); err != nil || !bytes.Equal(data, png) {
+		t.Errorf("expected the image downloaded from its URL, got %q, %v", data, err)
+	}
+
+	t.Setenv("SLIDEPILOT_IMAGE_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "")
+	if _, err := NewImageProviderFromEnv(); err == nil {
+		t.Error("expected an error without an API key")
+	}
+}
+
+func TestSaveGeneratedImageMatchesExtensionToContent(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	jpeg := []byte("\xff\xd8\xff\xe0jpeg data")
+	path, err := saveGeneratedImage(jpeg, "sunset.png")
+	if err != nil {
+		t.Fatalf("saveGeneratedImage failed: %v", err)
+	}
+	if filepath.Base(path) != "sunset.jpg" || filepath.Base(filepath.Dir(path)) != assetsDir {
+		t.Errorf("expected assets/sunset.jpg, got %s", path)
+	}
+	if saved, _ := os.ReadFile(path); !bytes.Equal(saved, jpeg) {
+		t.Errorf("expected the image bytes saved, got %q", saved)
+	}
+}
+
+func TestStabilityImageProvider(t *testing.T) {
+	var got map[string]string
+	var path, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.Path, r.Header.Get("Authorization")
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("expected a multipart form: %v", err)
+		}
+		got = map[string]string{}
+		for name, values := range r.MultipartForm.Value {
+			got[name] = values[0]
+		}
+		w.Write([]byte("png bytes"))
+	}))
+	defer server.Close()
+
+	t.Setenv("SLIDEPILOT_IMAGE_PROVIDER", "stability")
+	t.Setenv("SLIDEPILOT_IMAGE_API_KEY", "")
+	t.Setenv("STABILITY_API_KEY", "sk-test")
+	t.Setenv("SLIDEPILOT_IMAGE_ENDPOINT", server.URL+"/")
+	t.Setenv("SLIDEPILOT_IMAGE_MODEL", "sd3.5-large")
+	provider, err := NewImageProviderFromEnv()
+	if err != nil {
+		t.Fatalf("NewImageProviderFromEnv failed: %v", err)
+	}
+
+	data, err := provider.Generate(context.Background(), "a lighthouse", "1536x1024")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if string(data) != "png bytes" || path != "/v2beta/stable-image/generate/sd3" || auth != "Bearer sk-test" {
+		t.Errorf("unexpected request to %s with %q, returning %q", path, auth, data)
+	}
+	if got["prompt"] != "a lighthouse" || got["aspect_ratio"] != "3:2" || got["model"] != "sd3.5-large" {
+		t.Errorf("unexpected form: %v", got)
+	}
+
+	t.Setenv("STABILITY_API_KEY", "")
+	if _, err := NewImageProviderFromEnv(); err == nil {
+		t.Error("expected an error without an API key")
+	}
+}
+
+func TestStableDiffusionImageProvider(t *testing.T) {
+	var request map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sdapi/v1/txt2img" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &request)
+		json.NewEncoder(w).Encode(map[string][]string{"images": {base64.StdEncoding.EncodeToString([]byte("png bytes"))}})
+	}))
+	defer server.Close()
+
+	t.Setenv("SLIDEPILOT_IMAGE_PROVIDER", "Stable-Diffusion")
+	t.Setenv("SLIDEPILOT_IMAGE_ENDPOINT", server.URL)
+	t.Setenv("SLIDEPILOT_IMAGE_MODEL", "")
+	provider, err := NewImageProviderFromEnv()
+	if err != nil {
+		t.Fatalf("NewImageProviderFromEnv failed: %v", err)
+	}
+
+	data, err := provider.Generate(context.Background(), "a lighthouse", "768x512")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if string(data) != "png bytes" {
+		t.Errorf("expected the decoded image, got %q", data)
+	}
+	if request["width"] != float64(768) || request["height"] != float64(512) || request["override_settings"] != nil {
+		t.Errorf("unexpected request: %v", request)
+	}
+
+	for _, size := range []string{"large", "1024", "32x32", "1024x9000"} {
+		if _, err := provider.Generate(context.Background(), "a lighthouse", size); err == nil {
+			t.Errorf("expected an error for size %q", size)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// importFormats are the presentation formats LibreOffice reads that are converted to a
+// working .pptx on load, since the native tools only understand PowerPoint packages
+var importFormats = map[string]string{
+	".ppt": "PowerPoint 97-2003",
+	".odp": "OpenDocument",
+	".key": "Keynote",
+}
+
+// PresentationConversion is the payload of "presentation-converted" events, sent when a
+// loaded file was converted and edits go to the working copy
+type PresentationConversion struct {
+	SourcePath  string `json:"source_path"`
+	WorkingPath string `json:"working_path"`
+	Format      string `json:"format"`
+	Reused      bool   `json:"reused"` // An up-to-date working copy from an earlier load was opened
+}
+
+// workingCopyPath returns where the .pptx converted from a presentation in another format
+// is kept: next to it, named after it and its format so decks of the same name don't clash
+func workingCopyPath(sourcePath string) string {
+	ext := filepath.Ext(sourcePath)
+	name := strings.TrimSuffix(filepath.Base(sourcePath), ext)
+	return filepath.Join(filepath.Dir(sourcePath), fmt.Sprintf("%s (converted from %s).pptx", name, strings.ToLower(ext[1:])))
+}
+
+// prepareWorkingCopy returns the .pptx to work on for a presentation: the file itself for
+// .pptx, otherwise a converted copy. A copy at least as new as its source is reused, so
+// edits made to it survive reopening the original.
+func prepareWorkingCopy(ctx context.Context, app *App, sourcePath string) (string, *PresentationConversion, error) {
+	ext := strings.ToLower(filepath.Ext(sourcePath))
+	if ext == ".pptx" {
+		return sourcePath, nil, nil
+	}
+	format, ok := importFormats[ext]
+	if !ok {
+		return "", nil, NewToolError(ErrCodeInvalidInput, "unsupported presentation format %q; open a .pptx, .ppt, .odp or .key file", ext)
+	}
+	source, err := os.Stat(sourcePath)
+	if err != nil {
+		return "", nil, NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", sourcePath)
+	}
+
+	conversion := &PresentationConversion{SourcePath: sourcePath, WorkingPath: workingCopyPath(sourcePath), Format: format}
+	if working, err := os.Stat(conversion.WorkingPath); err == nil && !working.ModTime().Before(source.ModTime()) {
+		conversion.Reused = true
+		return conversion.WorkingPath, conversion, nil
+	}
+
+	fmt.Printf("Converting %s presentation %s to %s\n", format, filepath.Base(sourcePath), filepath.Base(conversion.WorkingPath))
+	if _, _, err := savePresentationCopy(ctx, app, sourcePath, conversion.WorkingPath, true); err != nil {
+		if ext == ".key" {
+			return "", nil, NewToolError(toolErrorCode(err),
+				"LibreOffice couldn't import the Keynote file (its Keynote import filter only reads some versions); export it from Keynote as PowerPoint instead: %v", err)
+		}
+		return "", nil, NewToolError(toolErrorCode(err), "failed to convert the %s presentation to .pptx: %v", format, err)
+	}
+	return conversion.WorkingPath, conversion, nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// handleSaveAs makes uno_save_as.py write the two-slide fixture as the converted copy
+func handleSaveAs(bridge *FakeUnoBridge) {
+	bridge.Handle("uno_save_as.py", func(args []string) ([]byte, error) {
+		if err := copyFile(filepath.Join(fixtureDir, "two_slides.pptx"), args[1]); err != nil {
+			return []byte(fmt.Sprintf(`{"success": false, "error": %q}`, err.Error())), fmt.Errorf("exit status 1")
+		}
+		return []byte(fmt.Sprintf(`{"success": true, "output_path": %q, "format": "pptx", "slide_count": 2}`, args[1])), nil
+	})
+}
+
+func TestLoadPresentationConvertsOtherFormats(t *testing.T) {
+	env := newTestEnv(t)
+	handleSaveAs(env.uno)
+	source := filepath.Join(env.dir, "Deck.odp")
+	os.WriteFile(source, []byte("odp"), 0644)
+
+	slides, err := env.app.LoadPresentation(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	working := filepath.Join(env.dir, "Deck (converted from odp).pptx")
+	if len(slides) != 2 || env.app.presentationPath() != working {
+		t.Fatalf("expected the working copy to be loaded, got %s with %d slides", env.app.presentationPath(), len(slides))
+	}
+	converted := env.events.Messages("presentation-converted")
+	if len(converted) != 1 || converted[0].(PresentationConversion).WorkingPath != working || converted[0].(PresentationConversion).Reused {
+		t.Fatalf("expected a presentation-converted event, got %v", converted)
+	}
+
+	// Opening the original again reuses the working copy and its edits
+	if _, err := env.app.LoadPresentation(source); err != nil {
+		t.Fatal(err)
+	}
+	if calls := env.uno.Calls("uno_save_as.py"); len(calls) != 1 {
+		t.Errorf("expected the working copy to be reused, converted %d times", len(calls))
+	}
+
+	// A source changed since is converted again
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(source, later, later)
+	if _, err := env.app.LoadPresentation(source); err != nil {
+		t.Fatal(err)
+	}
+	if calls := env.uno.Calls("uno_save_as.py"); len(calls) != 2 {
+		t.Errorf("expected a changed source to be converted again, converted %d times", len(calls))
+	}
+}
+
+func TestLoadPresentationRejectsUnknownFormats(t *testing.T) {
+	env := newTestEnv(t)
+	os.WriteFile("notes.txt", []byte("text"), 0644)
+	if _, err := env.app.LoadPresentation("notes.txt"); err == nil || !strings.Contains(err.Error(), "unsupported presentation format") {
+		t.Errorf("expected an unsupported format error, got %v", err)
+	}
+
+	// Keynote files LibreOffice can't import point to exporting from Keynote
+	env.uno.Handle("uno_save_as.py", func(args []string) ([]byte, error) {
+		return []byte(`{"success": false, "error": "Error saving presentation: could not load"}`), fmt.Errorf("exit status 1")
+	})
+	os.WriteFile("Talk.key", []byte("key"), 0644)
+	if _, err := env.app.LoadPresentation("Talk.key"); err == nil || !strings.Contains(err.Error(), "export it from Keynote as PowerPoint") {
+		t.Errorf("expected a Keynote hint, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// IntegrityReport summarizes a successful post-save verification
+type IntegrityReport struct {
+	Verified        bool `json:"verified"`
+	PackageSlides   int  `json:"package_slides"`
+	LibreOfficeRead bool `json:"libreoffice_read"`
+	SlideCount      int  `json:"slide_count"`
+}
+
+// VerifyPresentationIntegrity re-opens a saved presentation to catch silent corruption.
+// It validates the zip central directory and slide list, then does a quick read-only UNO open
+// and checks that both agree on the slide count.
+func VerifyPresentationIntegrity(ctx context.Context, app *App, presentationPath string) (*IntegrityReport, error) {
+	report := &IntegrityReport{}
+
+	if isOOXMLPackage(presentationPath) {
+		packageSlides, err := verifyPackage(presentationPath)
+		if err != nil {
+			return nil, err
+		}
+		report.PackageSlides = packageSlides
+	}
+
+	output, err := runUnoScript(ctx, app, "uno_verify.py", presentationPath)
+	if err != nil {
+		return nil, fmt.Errorf("presentation could not be re-opened after save: %v\nOutput: %s", err, string(output))
+	}
+
+	var verifyResult struct {
+		SlideCount int `json:"slide_count"`
+	}
+	if err := json.Unmarshal(output, &verifyResult); err != nil {
+		return nil, fmt.Errorf("invalid JSON output from UNO script: %v", err)
+	}
+	report.LibreOfficeRead = true
+	report.SlideCount = verifyResult.SlideCount
+
+	if isOOXMLPackage(presentationPath) && report.PackageSlides != report.SlideCount {
+		return nil, fmt.Errorf("slide count mismatch after save: package lists %d slides but LibreOffice reads %d",
+			report.PackageSlides, report.SlideCount)
+	}
+
+	report.Verified = true
+	return report, nil
+}
+
+// isOOXMLPackage reports whether the file is a zip-based Office Open XML presentation
+func isOOXMLPackage(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".pptx", ".potx", ".ppsx", ".pptm":
+		return true
+	}
+	return false
+}
+
+// verifyPackage cheaply validates the zip structure of a .pptx and returns its slide count.
+// Only the central directory and presentation.xml are read, not every part.
+func verifyPackage(presentationPath string) (int, error) {
+	reader, err := zip.OpenReader(presentationPath)
+	if err != nil {
+		return 0, fmt.Errorf("presentation is not a valid zip package: %v", err)
+	}
+	defer reader.Close()
+
+	parts := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		parts[file.Name] = file
+	}
+
+	for _, required := range []string{"[Content_Types].xml", "ppt/presentation.xml"} {
+		if _, ok := parts[required]; !ok {
+			return 0, fmt.Errorf("presentation package is missing %s", required)
+		}
+	}
+
+	presentationFile, err := parts["ppt/presentation.xml"].Open()
+	if err != nil {
+		return 0, fmt.Errorf("failed to open presentation.xml: %v", err)
+	}
+	defer presentationFile.Close()
+
+	var presentation struct {
+		SlideIDs []struct {
+			ID string `xml:"id,attr"`
+		} `xml:"sldIdLst>sldId"`
+	}
+	if err := xml.NewDecoder(presentationFile).Decode(&presentation); err != nil {
+		return 0, fmt.Errorf("presentation.xml is unreadable: %v", err)
+	}
+
+	return len(presentation.SlideIDs), nil
+}
+
+// attachIntegrityReport adds the verification result to a tool's JSON result
+func attachIntegrityReport(result string, report *IntegrityReport) string {
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(result), &parsed); err != nil {
+		return result
+	}
+	parsed["integrity_check"] = report
+
+	enhanced, err := json.Marshal(parsed)
+	if err != nil {
+		return result
+	}
+	return string(enhanced)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePackage writes a zip with the given parts and returns its path
+func writePackage(t *testing.T, name string, parts map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	writer := zip.NewWriter(file)
+	for partName, content := range parts {
+		part, _ := writer.Create(partName)
+		part.Write([]byte(content))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestVerifyPackage(t *testing.T) {
+	presentation := `<p:presentation xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main">` +
+		`<p:sldIdLst><p:sldId id="256"/><p:sldId id="257"/></p:sldIdLst></p:presentation>`
+	path := writePackage(t, "deck.pptx", map[string]string{
+		"[Content_Types].xml":  "<Types/>",
+		"ppt/presentation.xml": presentation,
+	})
+	if slides, err := verifyPackage(path); err != nil || slides != 2 {
+		t.Errorf("expected 2 slides, got %d, %v", slides, err)
+	}
+
+	missing := writePackage(t, "missing.pptx", map[string]string{"[Content_Types].xml": "<Types/>"})
+	if _, err := verifyPackage(missing); err == nil || !strings.Contains(err.Error(), "ppt/presentation.xml") {
+		t.Errorf("expected the missing part reported, got %v", err)
+	}
+
+	truncated := filepath.Join(t.TempDir(), "truncated.pptx")
+	data, _ := os.ReadFile(path)
+	os.WriteFile(truncated, data[:len(data)/2], 0644)
+	if _, err := verifyPackage(truncated); err == nil {
+		t.Error("expected a truncated package to fail")
+	}
+}
+
+func TestIsOOXMLPackage(t *testing.T) {
+	for path, want := range map[string]bool{"deck.pptx": true, "DECK.POTX": true, "deck.odp": false, "deck.ppt": false} {
+		if got := isOOXMLPackage(path); got != want {
+			t.Errorf("isOOXMLPackage(%s) = %v, expected %v", path, got, want)
+		}
+	}
+}
+
+func TestAttachIntegrityReport(t *testing.T) {
+	result := attachIntegrityReport(`{"success": true}`, &IntegrityReport{Verified: true, SlideCount: 2})
+	if !strings.Contains(result, `"integrity_check":{`) || !strings.Contains(result, `"slide_count":2`) {
+		t.Errorf("expected the report attached, got %s", result)
+	}
+	if result := attachIntegrityReport("not json", &IntegrityReport{}); result != "not json" {
+		t.Errorf("expected a non-JSON result unchanged, got %s", result)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Each run keeps its temp files and directories in its own slidepilot-<pid> directory, so
+// cleaning up never touches the files of another instance running at the same time.
+const tempPrefix = "slidepilot-"
+
+// sessionDirPattern matches the name of a run's temp directory
+var sessionDirPattern = regexp.MustCompile(`^slidepilot-([0-9]+)$`)
+
+// previewMaxAge is how long unused slide previews are kept
+const previewMaxAge = 7 * 24 * time.Hour
+
+// sessionTempPath is this run's temp directory, which may not exist yet
+func sessionTempPath() string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("%s%d", tempPrefix, os.Getpid()))
+}
+
+// sessionTempDir returns this run's temp directory, creating it when needed, for use as the
+// dir argument of os.CreateTemp and os.MkdirTemp. It falls back to the system temp
+// directory when it can't be created.
+func sessionTempDir() string {
+	dir := sessionTempPath()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return os.TempDir()
+	}
+	return dir
+}
+
+// cleanupOnStartup removes the temp directories of runs that are no longer running and
+// stale previews
+func cleanupOnStartup() {
+	removed := removeOrphanedSessions()
+	removed += removeStalePreviews("slides", previewMaxAge)
+	if removed > 0 {
+		fmt.Printf("Janitor: removed %d orphaned temp entries and stale previews\n", removed)
+	}
+}
+
+// cleanupOnShutdown removes this run's temp directory with whatever was never cleaned up
+func cleanupOnShutdown() {
+	dir := sessionTempPath()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	if err := os.RemoveAll(dir); err == nil && len(entries) > 0 {
+		fmt.Printf("Janitor: removed %d leftover temp entries\n", len(entries))
+	}
+}
+
+// removeOrphanedSessions deletes the temp directories of other runs whose process is gone
+func removeOrphanedSessions() int {
+	entries, err := os.ReadDir(os.TempDir())
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		match := sessionDirPattern.FindStringSubmatch(entry.Name())
+		if match == nil || !entry.IsDir() {
+			continue
+		}
+		pid, err := strconv.Atoi(match[1])
+		if err != nil || pid == os.Getpid() || processAlive(pid) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(os.TempDir(), entry.Name())); err == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+// removeStalePreviews deletes the slide previews in dir when none of them were rewritten
+// within maxAge. Previews are removed as a set so a deck is never left half-rendered.
+func removeStalePreviews(dir string, maxAge time.Duration) int {
+	previews, err := listSlidePreviews(dir)
+	if err != nil || len(previews) == 0 {
+		return 0
+	}
+
+	for _, preview := range previews {
+		info, err := os.Stat(preview)
+		if err != nil || time.Since(info.ModTime()) <= maxAge {
+			return 0
+		}
+	}
+
+	removed := 0
+	for _, preview := range previews {
+		if err := os.Remove(preview); err == nil {
+			removed++
+		}
+	}
+	return removed
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCleanupRemovesOnlyOrphanedSessions(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	// A process that has exited leaves its session directory orphaned
+	exited := exec.Command("sh", "-c", "exit 0")
+	if err := exited.Run(); err != nil {
+		t.Fatal(err)
+	}
+	orphaned := filepath.Join(tmp, fmt.Sprintf("slidepilot-%d", exited.Process.Pid))
+	// Another instance that is still running, however old its files are
+	running := filepath.Join(tmp, fmt.Sprintf("slidepilot-%d", os.Getppid()))
+	for _, dir := range []string{orphaned, running} {
+		os.MkdirAll(filepath.Join(dir, "artifacts-1"), 0755)
+		old := time.Now().Add(-48 * time.Hour)
+		os.Chtimes(dir, old, old)
+	}
+	other := filepath.Join(tmp, "slidepilot-backup-1.pptx")
+	os.WriteFile(other, []byte("x"), 0644)
+
+	if removed := removeOrphanedSessions(); removed != 1 {
+		t.Errorf("expected 1 orphaned session removed, got %d", removed)
+	}
+	if _, err := os.Stat(orphaned); !os.IsNotExist(err) {
+		t.Error("expected the exited process's directory removed")
+	}
+	if _, err := os.Stat(running); err != nil {
+		t.Error("a running instance's directory must be kept")
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Error("entries that aren't session directories must be left alone")
+	}
+
+	// Shutdown removes this run's directory and nothing else
+	backup, err := BackupPresentation(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(backup.BackupPath) != sessionTempPath() {
+		t.Errorf("expected the backup in the session directory, got %s", backup.BackupPath)
+	}
+	cleanupOnShutdown()
+	if _, err := os.Stat(sessionTempPath()); !os.IsNotExist(err) {
+		t.Error("expected the session directory removed at shutdown")
+	}
+	if _, err := os.Stat(running); err != nil {
+		t.Error("shutdown must not remove another instance's directory")
+	}
+}
+
+func TestRemoveStalePreviewsKeepsDecksWhole(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-2 * previewMaxAge)
+	for i := 0; i < 3; i++ {
+		os.WriteFile(slidePreviewPath(dir, i), []byte("x"), 0644)
+		os.Chtimes(slidePreviewPath(dir, i), old, old)
+	}
+
+	// One recently edited slide keeps the whole deck
+	os.Chtimes(slidePreviewPath(dir, 1), time.Now(), time.Now())
+	if removed := removeStalePreviews(dir, previewMaxAge); removed != 0 {
+		t.Errorf("expected previews to be kept, removed %d", removed)
+	}
+
+	os.Chtimes(slidePreviewPath(dir, 1), old, old)
+	if removed := removeStalePreviews(dir, previewMaxAge); removed != 3 {
+		t.Errorf("expected all 3 stale previews removed, got %d", removed)
+	}
+}
+
+func TestCheckDiskSpaceReportsLowSpace(t *testing.T) {
+	if err := checkDiskSpace(t.TempDir(), 0); err != nil {
+		t.Fatalf("expected enough space for an empty operation: %v", err)
+	}
+
+	err := checkDiskSpace(t.TempDir(), 1<<62)
+	if code := toolErrorCode(err); code != ErrCodeDiskSpaceLow {
+		t.Fatalf("expected %s, got %s (%v)", ErrCodeDiskSpaceLow, code, err)
+	}
+}
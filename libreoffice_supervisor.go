@@ -0,0 +1,441 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// unoPortEnv fixes the port of the UNO socket; by default a free one is picked. The port
+// in use is exported under the same name so the UNO scripts connect to it.
+const unoPortEnv = "SLIDEPILOT_UNO_PORT"
+
+// libreOfficeProfileEnv sets the user profile directory of the headless LibreOffice
+const libreOfficeProfileEnv = "SLIDEPILOT_LIBREOFFICE_PROFILE"
+
+const (
+	defaultUnoPort = 8100 // First port tried when picking one
+	unoPortRange   = 100  // Ports tried from defaultUnoPort before asking the OS for one
+)
+
+const (
+	libreOfficePollInterval      = 2 * time.Second  // How often the socket is checked
+	libreOfficeStartTimeout      = 10 * time.Second // How long a fresh process may take to open the socket
+	libreOfficeStopTimeout       = 5 * time.Second  // How long soffice may take to exit before it is killed
+	libreOfficeUnresponsivePolls = 3                // Failed checks before a running process is restarted
+	libreOfficeMaxBackoff        = time.Minute      // Longest wait between failed restarts
+)
+
+// LibreOfficeConfig is where the headless LibreOffice listens and which profile it uses.
+// Each SlidePilot instance gets its own port and profile so it neither collides with the
+// user's own LibreOffice nor with another SlidePilot: two soffice processes sharing a
+// profile hand their work to whichever started first.
+type LibreOfficeConfig struct {
+	Port       int
+	ProfileDir string
+}
+
+// Address returns the UNO socket address
+func (c LibreOfficeConfig) Address() string {
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(c.Port))
+}
+
+// resolveLibreOfficeConfig reads the LibreOffice settings from the environment, picking a
+// free port and a per-port profile when they aren't set, and exports the port for the
+// UNO scripts
+func resolveLibreOfficeConfig() LibreOfficeConfig {
+	var config LibreOfficeConfig
+	if value := os.Getenv(unoPortEnv); value != "" {
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 1 || port > 65535 {
+			fmt.Printf("Warning: ignoring invalid %s=%q\n", unoPortEnv, value)
+		} else {
+			config.Port = port
+		}
+	}
+	if config.Port == 0 {
+		config.Port = pickUnoPort(defaultUnoPort, unoPortRange)
+	}
+
+	config.ProfileDir = os.Getenv(libreOfficeProfileEnv)
+	if config.ProfileDir == "" {
+		// Keyed by port so instances running side by side don't share a profile, while
+		// the usual port keeps reusing an initialized one
+		config.ProfileDir = filepath.Join(scriptsCacheRoot(), "libreoffice", fmt.Sprintf("profile-%d", config.Port))
+	}
+
+	os.Setenv(unoPortEnv, strconv.Itoa(config.Port))
+	return config
+}
+
+// pickUnoPort returns the first port from first on that nothing listens on, or one the OS
+// picks when all count are taken
+func pickUnoPort(first, count int) int {
+	for port := first; port < first+count; port++ {
+		listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+		if err == nil {
+			listener.Close()
+			return port
+		}
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return first
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// sofficeArgs start LibreOffice headless with config's profile, listening on its port
+func sofficeArgs(config LibreOfficeConfig) []string {
+	return []string{
+		"-env:UserInstallation=" + fileURL(config.ProfileDir),
+		"--headless",
+		"--invisible",
+		"--nodefault",
+		"--nolockcheck",
+		"--nologo",
+		"--norestore",
+		fmt.Sprintf("--accept=socket,host=127.0.0.1,port=%d;urp;StarOffice.ServiceManager", config.Port),
+	}
+}
+
+// fileURL converts a local path to the file:// URL LibreOffice expects
+func fileURL(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // Windows drive letters
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// LibreOffice service states reported in LibreOfficeStatus
+const (
+	libreOfficeStopped    = "stopped"
+	libreOfficeStarting   = "starting"
+	libreOfficeRunning    = "running"    // The supervisor's own process is serving the socket
+	libreOfficeExternal   = "external"   // Another LibreOffice already served the socket
+	libreOfficeRestarting = "restarting" // The service died or hung and is being restarted
+	libreOfficeFailed     = "failed"     // Starting failed; retried with backoff
+)
+
+// LibreOfficeStatus is the payload of "libreoffice-status" events and GetLibreOfficeStatus
+type LibreOfficeStatus struct {
+	State    string `json:"state"`
+	PID      int    `json:"pid"` // 0 unless the supervisor owns the process
+	Restarts int    `json:"restarts"`
+	Error    string `json:"error,omitempty"`
+}
+
+// LibreOfficeSupervisor owns the headless soffice process the UNO scripts talk to. It
+// polls the UNO socket and restarts the process when it exits or stops answering, so a
+// LibreOffice crash mid-session costs a few seconds instead of an app restart.
+type LibreOfficeSupervisor struct {
+	address      string
+	command      string
+	args         []string
+	pollInterval time.Duration
+	startTimeout time.Duration
+	onStatus     func(LibreOfficeStatus) // Called on every status change
+
+	mu        sync.Mutex
+	cmd       *exec.Cmd
+	exited    chan struct{} // Closed when cmd has exited
+	status    LibreOfficeStatus
+	ready     chan struct{} // Closed while the socket is being served
+	done      chan struct{} // Closed by Close
+	watching  chan struct{} // Closed when the watcher has returned; nil before Start
+	closeOnce sync.Once
+}
+
+// NewLibreOfficeSupervisor creates a supervisor for soffice with config
+func NewLibreOfficeSupervisor(config LibreOfficeConfig, onStatus func(LibreOfficeStatus)) *LibreOfficeSupervisor {
+	return &LibreOfficeSupervisor{
+		address:      config.Address(),
+		command:      "soffice",
+		args:         sofficeArgs(config),
+		pollInterval: libreOfficePollInterval,
+		startTimeout: libreOfficeStartTimeout,
+		onStatus:     onStatus,
+		status:       LibreOfficeStatus{State: libreOfficeStopped},
+		ready:        make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// Start brings LibreOffice up, or adopts an instance already listening on the socket, and
+// starts watching it. The watcher keeps retrying even when the first start fails.
+func (s *LibreOfficeSupervisor) Start() error {
+	var err error
+	if isPortOpen(s.address) {
+		fmt.Printf("LibreOffice headless already running on %s\n", s.address)
+		s.setStatus(libreOfficeExternal, 0, nil)
+	} else {
+		err = s.launch(libreOfficeStarting)
+	}
+	watching := make(chan struct{})
+	s.mu.Lock()
+	s.watching = watching
+	s.mu.Unlock()
+	go s.watch(watching)
+	return err
+}
+
+// Close stops watching LibreOffice, leaving the process running
+func (s *LibreOfficeSupervisor) Close() {
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+// Stop stops watching LibreOffice and shuts down the process this supervisor started; an
+// adopted instance belongs to someone else and is left alone. soffice is asked to exit and
+// killed after timeout, then Stop waits for the UNO port to close so the next launch can
+// bind it.
+func (s *LibreOfficeSupervisor) Stop(timeout time.Duration) error {
+	s.Close()
+	s.mu.Lock()
+	watching := s.watching
+	s.mu.Unlock()
+	if watching != nil {
+		// The watcher may be mid-restart; wait so its process can't outlive Stop
+		<-watching
+	}
+
+	s.mu.Lock()
+	cmd, exited := s.cmd, s.exited
+	s.mu.Unlock()
+	if cmd == nil {
+		s.setStatus(libreOfficeStopped, 0, nil)
+		return nil
+	}
+
+	fmt.Println("Stopping LibreOffice headless service...")
+	if err := terminateProcess(cmd); err != nil {
+		killProcess(cmd)
+	}
+	select {
+	case <-exited:
+	case <-time.After(timeout):
+		fmt.Println("LibreOffice did not exit in time, killing it")
+		killProcess(cmd)
+		<-exited
+	}
+	s.forgetProcess()
+	s.setStatus(libreOfficeStopped, 0, nil)
+
+	if !waitForPortClosed(s.address, timeout) {
+		return fmt.Errorf("UNO port %s still in use after stopping LibreOffice", s.address)
+	}
+	return nil
+}
+
+// Status returns the current state of the service
+func (s *LibreOfficeSupervisor) Status() LibreOfficeStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// WaitReady blocks until the socket is being served, ctx ends, or timeout passes
+func (s *LibreOfficeSupervisor) WaitReady(ctx context.Context, timeout time.Duration) error {
+	s.mu.Lock()
+	ready := s.ready
+	s.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		status := s.Status()
+		if status.Error != "" {
+			return fmt.Errorf("LibreOffice is %s: %s", status.State, status.Error)
+		}
+		return fmt.Errorf("LibreOffice is %s", status.State)
+	}
+}
+
+// launch starts a new soffice process and waits for it to open the socket
+func (s *LibreOfficeSupervisor) launch(state string) error {
+	fmt.Println("Starting LibreOffice headless service...")
+	s.setStatus(state, 0, nil)
+
+	cmd := exec.Command(s.command, s.args...)
+	configureSofficeCommand(cmd)
+	if err := cmd.Start(); err != nil {
+		err = fmt.Errorf("failed to start LibreOffice: %v", err)
+		s.setStatus(libreOfficeFailed, 0, err)
+		return err
+	}
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+
+	s.mu.Lock()
+	s.cmd, s.exited = cmd, exited
+	s.mu.Unlock()
+
+	deadline := time.Now().Add(s.startTimeout)
+	for time.Now().Before(deadline) {
+		if isPortOpen(s.address) {
+			fmt.Println("LibreOffice headless service ready")
+			s.setStatus(libreOfficeRunning, cmd.Process.Pid, nil)
+			return nil
+		}
+		select {
+		case <-exited:
+			err := fmt.Errorf("LibreOffice exited during startup")
+			s.forgetProcess()
+			s.setStatus(libreOfficeFailed, 0, err)
+			return err
+		case <-s.done:
+			return fmt.Errorf("LibreOffice supervisor closed")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	killProcess(cmd)
+	<-exited
+	s.forgetProcess()
+	err := fmt.Errorf("LibreOffice headless service failed to start within %v", s.startTimeout)
+	s.setStatus(libreOfficeFailed, 0, err)
+	return err
+}
+
+// watch restarts LibreOffice when the owned process exits or the socket stops answering
+func (s *LibreOfficeSupervisor) watch(watching chan struct{}) {
+	defer close(watching)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	unresponsive := 0
+	backoff := time.Second
+	for {
+		s.mu.Lock()
+		exited := s.exited
+		s.mu.Unlock()
+
+		select {
+		case <-s.done:
+			return
+		case <-exited:
+		case <-ticker.C:
+		}
+
+		if s.processExited() {
+			s.forgetProcess()
+			if isPortOpen(s.address) {
+				// soffice handed the socket to another process (or one was already running)
+				s.setStatus(libreOfficeExternal, 0, nil)
+				continue
+			}
+			fmt.Println("LibreOffice exited unexpectedly")
+			unresponsive = libreOfficeUnresponsivePolls
+		} else if isPortOpen(s.address) {
+			unresponsive = 0
+			backoff = time.Second
+			if status := s.Status(); status.State != libreOfficeRunning && status.State != libreOfficeExternal {
+				s.setStatus(libreOfficeExternal, 0, nil)
+			}
+			continue
+		} else {
+			unresponsive++
+		}
+
+		if unresponsive < libreOfficeUnresponsivePolls {
+			continue
+		}
+
+		// Replace a hung process instead of waiting for it forever
+		s.mu.Lock()
+		cmd, exited := s.cmd, s.exited
+		s.mu.Unlock()
+		if cmd != nil {
+			killProcess(cmd)
+			<-exited
+		}
+		s.forgetProcess()
+
+		s.mu.Lock()
+		s.status.Restarts++
+		s.mu.Unlock()
+		if err := s.launch(libreOfficeRestarting); err != nil {
+			fmt.Printf("Failed to restart LibreOffice, retrying in %v: %v\n", backoff, err)
+			select {
+			case <-s.done:
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(2*backoff, libreOfficeMaxBackoff)
+			continue
+		}
+		unresponsive = 0
+		backoff = time.Second
+	}
+}
+
+// processExited reports whether the owned process has exited
+func (s *LibreOfficeSupervisor) processExited() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.exited == nil {
+		return false
+	}
+	select {
+	case <-s.exited:
+		return true
+	default:
+		return false
+	}
+}
+
+// forgetProcess drops the handle of a process that exited or was killed
+func (s *LibreOfficeSupervisor) forgetProcess() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cmd, s.exited = nil, nil
+}
+
+// setStatus records a state change, updates readiness and notifies onStatus
+func (s *LibreOfficeSupervisor) setStatus(state string, pid int, err error) {
+	s.mu.Lock()
+	status := LibreOfficeStatus{State: state, PID: pid, Restarts: s.status.Restarts}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	changed := status != s.status
+	s.status = status
+
+	serving := state == libreOfficeRunning || state == libreOfficeExternal
+	select {
+	case <-s.ready:
+		if !serving {
+			s.ready = make(chan struct{})
+		}
+	default:
+		if serving {
+			close(s.ready)
+		}
+	}
+	s.mu.Unlock()
+
+	if changed && s.onStatus != nil {
+		s.onStatus(status)
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSofficeEnv makes the test binary act as soffice, serving the given address
+const fakeSofficeEnv = "SLIDEPILOT_FAKE_SOFFICE_ADDR"
+
+// TestHelperSoffice is not a real test: started by the supervisor as its "soffice", it
+// listens on the address from fakeSofficeEnv until it is killed
+func TestHelperSoffice(t *testing.T) {
+	address := os.Getenv(fakeSofficeEnv)
+	if address == "" {
+		t.Skip("helper process for the LibreOffice supervisor tests")
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		os.Exit(1)
+	}
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			os.Exit(1)
+		}
+		conn.Close()
+	}
+}
+
+// newTestSupervisor returns a supervisor starting the fake soffice on a free port
+func newTestSupervisor(t *testing.T) (*LibreOfficeSupervisor, *[]LibreOfficeStatus, *sync.Mutex) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := LibreOfficeConfig{Port: listener.Addr().(*net.TCPAddr).Port, ProfileDir: t.TempDir()}
+	listener.Close()
+	t.Setenv(fakeSofficeEnv, config.Address())
+
+	var mu sync.Mutex
+	var events []LibreOfficeStatus
+	supervisor := NewLibreOfficeSupervisor(config, func(status LibreOfficeStatus) {
+		mu.Lock()
+		events = append(events, status)
+		mu.Unlock()
+	})
+	supervisor.command = os.Args[0]
+	supervisor.args = []string{"-test.run=^TestHelperSoffice$"}
+	supervisor.pollInterval = 20 * time.Millisecond
+
+	t.Cleanup(func() { supervisor.Stop(time.Second) })
+	return supervisor, &events, &mu
+}
+
+// waitForStatus polls until the supervisor reports a status matching want
+func waitForStatus(t *testing.T, supervisor *LibreOfficeSupervisor, want func(LibreOfficeStatus) bool) LibreOfficeStatus {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if status := supervisor.Status(); want(status) {
+			return status
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("supervisor never reached the expected status, last %+v", supervisor.Status())
+	return LibreOfficeStatus{}
+}
+
+func TestSupervisorRestartsCrashedLibreOffice(t *testing.T) {
+	supervisor, events, mu := newTestSupervisor(t)
+
+	if err := supervisor.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	first := supervisor.Status()
+	if first.State != libreOfficeRunning || first.PID == 0 {
+		t.Fatalf("expected a running owned process, got %+v", first)
+	}
+
+	// Simulate a crash; the supervisor starts a replacement
+	supervisor.mu.Lock()
+	supervisor.cmd.Process.Kill()
+	supervisor.mu.Unlock()
+
+	restarted := waitForStatus(t, supervisor, func(status LibreOfficeStatus) bool {
+		return status.State == libreOfficeRunning && status.PID != first.PID
+	})
+	if restarted.Restarts != 1 {
+		t.Errorf("expected one restart, got %+v", restarted)
+	}
+	if err := supervisor.WaitReady(context.Background(), time.Second); err != nil {
+		t.Errorf("expected the restarted service to be ready: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var states []string
+	for _, event := range *events {
+		states = append(states, event.State)
+	}
+	want := []string{libreOfficeStarting, libreOfficeRunning, libreOfficeRestarting, libreOfficeRunning}
+	if len(states) != len(want) {
+		t.Fatalf("expected status events %v, got %v", want, states)
+	}
+	for i := range want {
+		if states[i] != want[i] {
+			t.Errorf("expected status events %v, got %v", want, states)
+			break
+		}
+	}
+}
+
+func TestSupervisorAdoptsRunningInstance(t *testing.T) {
+	supervisor, _, _ := newTestSupervisor(t)
+	listener, err := net.Listen("tcp", supervisor.address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	if err := supervisor.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if status := supervisor.Status(); status.State != libreOfficeExternal || status.PID != 0 {
+		t.Errorf("expected the existing instance to be adopted, got %+v", status)
+	}
+
+	// When the other instance goes away the supervisor starts its own
+	listener.Close()
+	waitForStatus(t, supervisor, func(status LibreOfficeStatus) bool {
+		return status.State == libreOfficeRunning && status.PID != 0
+	})
+}
+
+func TestWaitReadyTimesOutWhileFailed(t *testing.T) {
+	supervisor, _, _ := newTestSupervisor(t)
+	supervisor.command = "/nonexistent/soffice"
+	supervisor.pollInterval = time.Hour
+
+	if err := supervisor.Start(); err == nil {
+		t.Fatal("expected starting a missing binary to fail")
+	}
+	err := supervisor.WaitReady(context.Background(), 20*time.Millisecond)
+	if err == nil || supervisor.Status().State != libreOfficeFailed {
+		t.Errorf("expected a failed, unready service, got %v and %+v", err, supervisor.Status())
+	}
+}
+
+func TestStopTerminatesOwnedLibreOffice(t *testing.T) {
+	supervisor, _, _ := newTestSupervisor(t)
+	if err := supervisor.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	supervisor.mu.Lock()
+	exited := supervisor.exited
+	supervisor.mu.Unlock()
+
+	if err := supervisor.Stop(time.Second); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	select {
+	case <-exited:
+	default:
+		t.Error("expected the owned process to have exited")
+	}
+	if isPortOpen(supervisor.address) {
+		t.Error("expected the UNO port to be released")
+	}
+	if status := supervisor.Status(); status.State != libreOfficeStopped || status.PID != 0 {
+		t.Errorf("expected a stopped service, got %+v", status)
+	}
+}
+
+func TestStopLeavesAdoptedInstanceRunning(t *testing.T) {
+	supervisor, _, _ := newTestSupervisor(t)
+	listener, err := net.Listen("tcp", supervisor.address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	if err := supervisor.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := supervisor.Stop(time.Second); err != nil {
+		t.Errorf("Stop failed: %v", err)
+	}
+	if !isPortOpen(supervisor.address) {
+		t.Error("expected the other LibreOffice instance to keep running")
+	}
+}
+
+func TestResolveLibreOfficeConfig(t *testing.T) {
+	t.Setenv(unoPortEnv, "9123")
+	t.Setenv(libreOfficeProfileEnv, "/tmp/lo-profile")
+	config := resolveLibreOfficeConfig()
+	if config.Port != 9123 || config.ProfileDir != "/tmp/lo-profile" {
+		t.Errorf("expected the configured port and profile, got %+v", config)
+	}
+
+	args := strings.Join(sofficeArgs(config), " ")
+	for _, want := range []string{"-env:UserInstallation=file:///tmp/lo-profile", "port=9123;"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("expected soffice args to contain %q, got %s", want, args)
+		}
+	}
+
+	// An invalid port falls back to a picked one, with a profile of its own, and the port
+	// in use is exported for the UNO scripts
+	t.Setenv(unoPortEnv, "not-a-port")
+	t.Setenv(libreOfficeProfileEnv, "")
+	config = resolveLibreOfficeConfig()
+	if config.Port == 0 || !strings.HasSuffix(config.ProfileDir, fmt.Sprintf("profile-%d", config.Port)) {
+		t.Errorf("expected a picked port and per-port profile, got %+v", config)
+	}
+	if got := os.Getenv(unoPortEnv); got != strconv.Itoa(config.Port) {
+		t.Errorf("expected %s=%d to be exported, got %q", unoPortEnv, config.Port, got)
+	}
+}
+
+func TestPickUnoPortSkipsPortsInUse(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	busy := listener.Addr().(*net.TCPAddr).Port
+
+	if port := pickUnoPort(busy, 1); port == busy || port == 0 {
+		t.Errorf("expected a port other than the busy %d, got %d", busy, port)
+	}
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// NewLLMClientFromEnv builds the chat model client configured through environment variables.
+//
+// SLIDEPILOT_LLM_PROVIDER selects the backend:
+//   - "anthropic" (default) uses ANTHROPIC_API_KEY
+//   - "openai" uses the OpenAI chat completions API with SLIDEPILOT_LLM_API_KEY (or OPENAI_API_KEY)
+//   - "azure" uses an Azure OpenAI deployment: SLIDEPILOT_LLM_ENDPOINT is the resource URL,
+//     SLIDEPILOT_LLM_MODEL the deployment name and SLIDEPILOT_LLM_API_VERSION the API version
+//   - "openai-compatible" talks to any OpenAI-style server such as Ollama or vLLM at
+//     SLIDEPILOT_LLM_ENDPOINT; the API key is optional
+//
+// SLIDEPILOT_LLM_MODEL sets the default model of every provider; the model picked in the
+// settings takes precedence.
+func NewLLMClientFromEnv() (LLMClient, error) {
+	provider := strings.ToLower(os.Getenv("SLIDEPILOT_LLM_PROVIDER"))
+	if provider == "" {
+		provider = "anthropic"
+	}
+	model := os.Getenv("SLIDEPILOT_LLM_MODEL")
+	endpoint := strings.TrimSuffix(os.Getenv("SLIDEPILOT_LLM_ENDPOINT"), "/")
+	apiKey := os.Getenv("SLIDEPILOT_LLM_API_KEY")
+
+	switch provider {
+	case "anthropic":
+		client := NewAnthropicClient()
+		client.model = model
+		return client, nil
+	case "openai":
+		if apiKey == "" {
+			apiKey = os.Getenv("OPENAI_API_KEY")
+		}
+		if apiKey == "" {
+			return nil, fmt.Errorf("the openai provider requires SLIDEPILOT_LLM_API_KEY or OPENAI_API_KEY to be set")
+		}
+		if endpoint == "" {
+			endpoint = "https://api.openai.com/v1"
+		}
+		if model == "" {
+			model = "gpt-4.1"
+		}
+		return &OpenAIClient{
+			url:            endpoint + "/chat/completions",
+			modelsURL:      endpoint + "/models",
+			headers:        map[string]string{"Authorization": "Bearer " + apiKey},
+			model:          model,
+			maxTokensField: "max_completion_tokens",
+			client:         &http.Client{Timeout: 5 * time.Minute},
+		}, nil
+	case "azure":
+		if apiKey == "" {
+			apiKey = os.Getenv("AZURE_OPENAI_API_KEY")
+		}
+		if endpoint == "" || model == "" || apiKey == "" {
+			return nil, fmt.Errorf("the azure provider requires SLIDEPILOT_LLM_ENDPOINT, SLIDEPILOT_LLM_MODEL (the deployment name) and SLIDEPILOT_LLM_API_KEY or AZURE_OPENAI_API_KEY to be set")
+		}
+		apiVersion := os.Getenv("SLIDEPILOT_LLM_API_VERSION")
+		if apiVersion == "" {
+			apiVersion = "2024-10-21"
+		}
+		return &OpenAIClient{
+			url: fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+				endpoint, url.PathEscape(model), url.QueryEscape(apiVersion)),
+			headers:        map[string]string{"api-key": apiKey},
+			model:          model,
+			maxTokensField: "max_completion_tokens",
+			client:         &http.Client{Timeout: 5 * time.Minute},
+		}, nil
+	case "openai-compatible":
+		if endpoint == "" || model == "" {
+			return nil, fmt.Errorf("the openai-compatible provider requires SLIDEPILOT_LLM_ENDPOINT and SLIDEPILOT_LLM_MODEL to be set")
+		}
+		headers := map[string]string{}
+		if apiKey != "" {
+			headers["Authorization"] = "Bearer " + apiKey
+		}
+		return &OpenAIClient{
+			url:            endpoint + "/chat/completions",
+			modelsURL:      endpoint + "/models",
+			headers:        headers,
+			model:          model,
+			maxTokensField: "max_tokens",
+			client:         &http.Client{Timeout: 10 * time.Minute}, // Local models can be slow
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider: %s", provider)
+	}
+}
+
+// unavailableLLM stands in for a misconfigured provider so the app still starts and the
+// configuration error is reported when the user sends a message
+type unavailableLLM struct {
+	err error
+}
+
+func (u unavailableLLM) CreateMessage(ctx context.Context, params anthropic.MessageNewParams) (*anthropic.Message, error) {
+	return nil, u.err
+}
+
+// OpenAIClient sends conversations to an OpenAI-style chat completions API. The agent
+// loop speaks the Anthropic message format, so requests and responses are translated.
+type OpenAIClient struct {
+	url            string
+	modelsURL      string // Lists the server's models; empty for Azure, whose deployment is the model
+	headers        map[string]string
+	model          string // Used when the request names no model
+	maxTokensField string // "max_completion_tokens" for OpenAI, "max_tokens" for older servers
+	client         *http.Client
+}
+
+// ListModels lists the models the server offers; Azure deployments have none to pick from
+func (c *OpenAIClient) ListModels(ctx context.Context) ([]ModelOption, error) {
+	if c.modelsURL == "" {
+		return nil, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", c.modelsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create models request: %v", err)
+	}
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("models request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read models response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &LLMStatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: string(body), Header: resp.Header}
+	}
+
+	var result struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("invalid models response: %v", err)
+	}
+	models := []ModelOption{}
+	for _, model := range result.Data {
+		models = append(models, ModelOption{ID: model.ID, DisplayName: model.ID})
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	return models, nil
+}
+
+// openAIMessage is one chat completions message
+type openAIMessage struct {
+	Role       string           `json:"role"`
+	Content    interface{}      `json:"content,omitempty"` // A string or a list of content parts
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+type openAIToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+func (c *OpenAIClient) CreateMessage(ctx context.Context, params anthropic.MessageNewParams) (*anthropic.Message, error) {
+	messages, err := openAIMessages(params)
+	if err != nil {
+		return nil, err
+	}
+
+	model := string(params.Model)
+	if model == "" {
+		model = c.model
+	}
+	request := map[string]interface{}{
+		"model":          model,
+		"messages":       messages,
+		c.maxTokensField: params.MaxTokens,
+	}
+	if params.Temperature.Valid() {
+		request["temperature"] = params.Temperature.Value
+	}
+	if len(params.Tools) > 0 {
+		tools := make([]map[string]interface{}, 0, len(params.Tools))
+		for _, tool := range params.Tools {
+			if tool.OfTool == nil {
+				continue
+			}
+			tools = append(tools, map[string]interface{}{
+				"type": "function",
+				"function": map[string]interface{}{
+					"name":        tool.OfTool.Name,
+					"description": tool.OfTool.Description.Value,
+					"parameters":  tool.OfTool.InputSchema,
+				},
+			})
+		}
+		request["tools"] = tools
+	}
+
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode chat request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url, bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chat request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("chat request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chat response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &LLMStatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: string(body), Header: resp.Header}
+	}
+
+	var result struct {
+		ID      string `json:"id"`
+		Model   string `json:"model"`
+		Choices []struct {
+			Message      openAIMessage `json:"message"`
+			FinishReason string        `json:"finish_reason"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int64 `json:"prompt_tokens"`
+			CompletionTokens int64 `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("invalid chat API response: %v", err)
+	}
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("chat API returned no choices")
+	}
+
+	choice := result.Choices[0]
+	content := []map[string]interface{}{}
+	if text, _ := choice.Message.Content.(string); text != "" {
+		content = append(content, map[string]interface{}{"type": "text", "text": text})
+	}
+	for _, call := range choice.Message.ToolCalls {
+		input := json.RawMessage(call.Function.Arguments)
+		if !json.Valid(input) {
+			input = json.RawMessage(`{}`)
+		}
+		content = append(content, map[string]interface{}{"type": "tool_use", "id": call.ID, "name": call.Function.Name, "input": input})
+	}
+
+	stopReason := "end_turn"
+	switch {
+	case len(choice.Message.ToolCalls) > 0:
+		stopReason = "tool_use"
+	case choice.FinishReason == "length":
+		stopReason = "max_tokens"
+	}
+
+	// Build the message through its JSON form, which is how the SDK populates it
+	converted, err := json.Marshal(map[string]interface{}{
+		"id":          result.ID,
+		"type":        "message",
+		"role":        "assistant",
+		"model":       result.Model,
+		"content":     content,
+		"stop_reason": stopReason,
+		"usage":       map[string]int64{"input_tokens": result.Usage.PromptTokens, "output_tokens": result.Usage.CompletionTokens},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert chat response: %v", err)
+	}
+	var message anthropic.Message
+	if err := json.Unmarshal(converted, &message); err != nil {
+		return nil, fmt.Errorf("failed to convert chat response: %v", err)
+	}
+	return &message, nil
+}
+
+// openAIMessages converts an Anthropic conversation to chat completions messages. Tool
+// results become "tool" messages; since those can only hold text, images attached to a
+// result (slide screenshots) follow in a user message.
+func openAIMessages(params anthropic.MessageNewParams) ([]openAIMessage, error) {
+	var messages []openAIMessage
+
+	if len(params.System) > 0 {
+		var system []string
+		for _, block := range params.System {
+			system = append(system, block.Text)
+		}
+		messages = append(messages, openAIMessage{Role: "system", Content: strings.Join(system, "\n\n")})
+	}
+
+	for _, param := range params.Messages {
+		if param.Role == anthropic.MessageParamRoleAssistant {
+			message := openAIMessage{Role: "assistant"}
+			var text []string
+			for _, block := range param.Content {
+				switch {
+				case block.OfText != nil:
+					text = append(text, block.OfText.Text)
+				case block.OfToolUse != nil:
+					arguments, err := json.Marshal(block.OfToolUse.Input)
+					if err != nil {
+						return nil, fmt.Errorf("failed to encode tool call: %v", err)
+					}
+					call := openAIToolCall{ID: block.OfToolUse.ID, Type: "function"}
+					call.Function.Name = block.OfToolUse.Name
+					call.Function.Arguments = string(arguments)
+					message.ToolCalls = append(message.ToolCalls, call)
+				}
+			}
+			if len(text) > 0 {
+				message.Content = strings.Join(text, "\n")
+			}
+			messages = append(messages, message)
+			continue
+		}
+
+		var parts []map[string]interface{}
+		for _, block := range param.Content {
+			switch {
+			case block.OfText != nil:
+				parts = append(parts, openAITextPart(block.OfText.Text))
+			case block.OfImage != nil:
+				parts = append(parts, openAIImagePart(block.OfImage))
+			case block.OfToolResult != nil:
+				var text []string
+				for _, content := range block.OfToolResult.Content {
+					switch {
+					case content.OfText != nil:
+						text = append(text, content.OfText.Text)
+					case content.OfImage != nil:
+						parts = append(parts, openAIImagePart(content.OfImage))
+					}
+				}
+				messages = append(messages, openAIMessage{
+					Role:       "tool",
+					ToolCallID: block.OfToolResult.ToolUseID,
+					Content:    strings.Join(text, "\n"),
+				})
+			}
+		}
+		if len(parts) > 0 {
+			messages = append(messages, openAIMessage{Role: "user", Content: parts})
+		}
+	}
+	return messages, nil
+}
+
+func openAITextPart(text string) map[string]interface{} {
+	return map[string]interface{}{"type": "text", "text": text}
+}
+
+// openAIImagePart inlines a base64 image as a data URL
+func openAIImagePart(image *anthropic.ImageBlockParam) map[string]interface{} {
+	source := image.Source.OfBase64
+	if source == nil {
+		return openAITextPart("[image omitted]")
+	}
+	return map[string]interface{}{
+		"type":      "image_url",
+		"image_url": map[string]string{"url": fmt.Sprintf("data:%s;base64,%s", source.MediaType, source.Data)},
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+func TestOpenAIClientTranslatesToolLoop(t *testing.T) {
+	var request map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" || r.Header.Get("Authorization") != "Bearer sk-test" {
+			t.Errorf("unexpected request %s with auth %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &request)
+		w.Write([]byte(`{"id": "chatcmpl-1", "model": "gpt-test", "choices": [{"finish_reason": "tool_calls", "message": {
+			"role": "assistant", "content": "Reading it.",
+			"tool_calls": [{"id": "call_2", "type": "function", "function": {"name": "read_slide", "arguments": "{\"slide_number\": 2}"}}]}}],
+			"usage": {"prompt_tokens": 10, "completion_tokens": 5}}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("SLIDEPILOT_LLM_PROVIDER", "openai")
+	t.Setenv("SLIDEPILOT_LLM_API_KEY", "sk-test")
+	t.Setenv("SLIDEPILOT_LLM_ENDPOINT", server.URL+"/v1")
+	t.Setenv("SLIDEPILOT_LLM_MODEL", "gpt-test")
+	client, err := NewLLMClientFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A previous tool call whose result carries a screenshot
+	var previous anthropic.Message
+	json.Unmarshal([]byte(toolUseResponse("call_1", "list_slides", `{}`)), &previous)
+	toolResult := anthropic.NewToolResultBlock("call_1", `{"success": true}`, false)
+	image := anthropic.NewImageBlockBase64("image/jpeg", "aGVsbG8=")
+	toolResult.OfToolResult.Content = append(toolResult.OfToolResult.Content,
+		anthropic.ToolResultBlockParamContentUnion{OfImage: image.OfImage})
+
+	message, err := client.CreateMessage(context.Background(), anthropic.MessageNewParams{
+		MaxTokens: 2048,
+		Messages: []anthropic.MessageParam{
+			anthropic.NewUserMessage(anthropic.NewTextBlock("How many slides?")),
+			previous.ToParam(),
+			anthropic.NewUserMessage(toolResult),
+		},
+		Tools: []anthropic.ToolUnionParam{{OfTool: &anthropic.ToolParam{
+			Name: "read_slide", Description: anthropic.String("Read a slide"), InputSchema: ReadSlideInputSchema,
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("CreateMessage failed: %v", err)
+	}
+
+	// The response comes back as an Anthropic tool_use message
+	if message.StopReason != anthropic.StopReasonToolUse || len(message.Content) != 2 {
+		t.Fatalf("expected text and tool_use, got %+v", message)
+	}
+	if message.Content[0].Text != "Reading it." || message.Content[1].Name != "read_slide" || message.Content[1].ID != "call_2" {
+		t.Errorf("unexpected content %+v", message.Content)
+	}
+	if string(message.Content[1].Input) != `{"slide_number":2}` {
+		t.Errorf("unexpected tool input %s", message.Content[1].Input)
+	}
+
+	// user, assistant tool_calls, tool result, user message holding the screenshot
+	encoded, _ := json.Marshal(request["messages"])
+	messages := []openAIMessage{}
+	json.Unmarshal(encoded, &messages)
+	roles := []string{}
+	for _, m := range messages {
+		roles = append(roles, m.Role)
+	}
+	if strings.Join(roles, ",") != "user,assistant,tool,user" {
+		t.Fatalf("unexpected message roles %v", roles)
+	}
+	if len(messages[1].ToolCalls) != 1 || messages[1].ToolCalls[0].Function.Name != "list_slides" {
+		t.Errorf("expected the list_slides call, got %+v", messages[1].ToolCalls)
+	}
+	if messages[2].ToolCallID != "call_1" || messages[2].Content != `{"success": true}` {
+		t.Errorf("unexpected tool message %+v", messages[2])
+	}
+	if !strings.Contains(string(encoded), "data:image/jpeg;base64,aGVsbG8=") {
+		t.Errorf("expected the screenshot as an image part, got %s", encoded)
+	}
+	if request["max_completion_tokens"] != float64(2048) || request["model"] != "gpt-test" {
+		t.Errorf("unexpected request options %v", request)
+	}
+	tools, _ := json.Marshal(request["tools"])
+	if !strings.Contains(string(tools), `"name":"read_slide"`) || !strings.Contains(string(tools), `"slide_number"`) {
+		t.Errorf("expected read_slide with its schema, got %s", tools)
+	}
+}
+
+func TestNewLLMClientFromEnvValidatesProviders(t *testing.T) {
+	tests := []struct {
+		provider string
+		env      map[string]string
+		wantErr  string
+	}{
+		{provider: "", env: nil},
+		{provider: "openai-compatible", env: map[string]string{"SLIDEPILOT_LLM_ENDPOINT": "http://localhost:11434/v1", "SLIDEPILOT_LLM_MODEL": "llama3.1"}},
+		{provider: "openai-compatible", env: nil, wantErr: "SLIDEPILOT_LLM_ENDPOINT"},
+		{provider: "azure", env: map[string]string{"SLIDEPILOT_LLM_ENDPOINT": "https://example.openai.azure.com"}, wantErr: "deployment name"},
+		{provider: "openai", env: nil, wantErr: "OPENAI_API_KEY"},
+		{provider: "bard", env: nil, wantErr: "unknown LLM provider"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			for _, name := range []string{"SLIDEPILOT_LLM_ENDPOINT", "SLIDEPILOT_LLM_MODEL", "SLIDEPILOT_LLM_API_KEY", "OPENAI_API_KEY", "AZURE_OPENAI_API_KEY"} {
+				t.Setenv(name, tt.env[name])
+			}
+			t.Setenv("SLIDEPILOT_LLM_PROVIDER", tt.provider)
+
+			_, err := NewLLMClientFromEnv()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAzureClientUsesDeploymentURL(t *testing.T) {
+	var path, apiVersion, apiKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, apiVersion, apiKey = r.URL.Path, r.URL.Query().Get("api-version"), r.Header.Get("api-key")
+		w.Write([]byte(`{"id": "x", "choices": [{"finish_reason": "stop", "message": {"role": "assistant", "content": "Hi"}}]}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("SLIDEPILOT_LLM_PROVIDER", "azure")
+	t.Setenv("SLIDEPILOT_LLM_ENDPOINT", server.URL)
+	t.Setenv("SLIDEPILOT_LLM_MODEL", "slides-gpt")
+	t.Setenv("SLIDEPILOT_LLM_API_KEY", "azure-key")
+	client, err := NewLLMClientFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message, err := client.CreateMessage(context.Background(), anthropic.MessageNewParams{
+		MaxTokens: 10,
+		Messages:  []anthropic.MessageParam{anthropic.NewUserMessage(anthropic.NewTextBlock("Hello"))},
+	})
+	if err != nil {
+		t.Fatalf("CreateMessage failed: %v", err)
+	}
+	if path != "/openai/deployments/slides-gpt/chat/completions" || apiVersion == "" || apiKey != "azure-key" {
+		t.Errorf("unexpected request to %s (api-version %q, key %q)", path, apiVersion, apiKey)
+	}
+	if message.StopReason != anthropic.StopReasonEndTurn || message.Content[0].Text != "Hi" {
+		t.Errorf("unexpected message %+v", message)
+	}
+}
+
+func TestOpenAIClientUsesPickedModel(t *testing.T) {
+	var models []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/models" {
+			w.Write([]byte(`{"object": "list", "data": [{"id": "gpt-4.1-mini"}, {"id": "gpt-4.1"}]}`))
+			return
+		}
+		var request struct {
+			Model string `json:"model"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		models = append(models, request.Model)
+		w.Write([]byte(`{"id": "x", "choices": [{"finish_reason": "stop", "message": {"role": "assistant", "content": "Hi"}}]}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("SLIDEPILOT_LLM_PROVIDER", "openai")
+	t.Setenv("SLIDEPILOT_LLM_API_KEY", "sk-test")
+	t.Setenv("SLIDEPILOT_LLM_ENDPOINT", server.URL+"/v1")
+	t.Setenv("SLIDEPILOT_LLM_MODEL", "gpt-4.1")
+	client, err := NewLLMClientFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The configured model is only the default; a model picked in the settings wins
+	messages := []anthropic.MessageParam{anthropic.NewUserMessage(anthropic.NewTextBlock("Hello"))}
+	for _, model := range []anthropic.Model{"", "gpt-4.1-mini"} {
+		if _, err := client.CreateMessage(context.Background(), anthropic.MessageNewParams{Model: model, MaxTokens: 10, Messages: messages}); err != nil {
+			t.Fatalf("CreateMessage failed: %v", err)
+		}
+	}
+	if strings.Join(models, " ") != "gpt-4.1 gpt-4.1-mini" {
+		t.Errorf("expected the default, then the picked model, got %v", models)
+	}
+	if configuredModel(client) != "gpt-4.1" {
+		t.Errorf("expected new settings to start from gpt-4.1, got %q", configuredModel(client))
+	}
+
+	listed := listModels(context.Background(), client, "gpt-4.1-mini")
+	if len(listed) != 2 || listed[0].ID != "gpt-4.1-mini" || listed[1].ID != "gpt-4.1" {
+		t.Errorf("expected the server's models, the current one first, got %+v", listed)
+	}
+
+	// Azure deployments have no models to pick from, and Claude models aren't offered instead
+	t.Setenv("SLIDEPILOT_LLM_PROVIDER", "azure")
+	t.Setenv("SLIDEPILOT_LLM_ENDPOINT", server.URL)
+	azure, err := NewLLMClientFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if listed := listModels(context.Background(), azure, "gpt-4.1"); len(listed) != 0 {
+		t.Errorf("expected no model picker for Azure, got %+v", listed)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// retryPolicy decides how often and how long to wait before repeating a failed model request
+type retryPolicy struct {
+	MaxAttempts int           // Attempts including the first one
+	BaseDelay   time.Duration // Wait before the first retry, doubled for each later one
+	MaxDelay    time.Duration // Longest wait between attempts, also caps Retry-After
+}
+
+var defaultRetryPolicy = retryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   time.Second,
+	MaxDelay:    30 * time.Second,
+}
+
+// RetryStatus is the payload of "retrying" events, sent while waiting to repeat a
+// model request that failed with a transient error
+type RetryStatus struct {
+	Attempt      int    `json:"attempt"`      // The attempt about to be made, starting at 2
+	MaxAttempts  int    `json:"max_attempts"` // Attempts before giving up
+	DelaySeconds int    `json:"delay_seconds"`
+	Reason       string `json:"reason"` // "rate_limited", "overloaded", "server_error" or "connection_error"
+}
+
+// LLMStatusError is an unsuccessful HTTP response from an OpenAI-style chat API
+type LLMStatusError struct {
+	StatusCode int
+	Status     string
+	Body       string
+	Header     http.Header
+}
+
+func (e *LLMStatusError) Error() string {
+	return fmt.Sprintf("chat API returned %s: %s", e.Status, e.Body)
+}
+
+// transientLLMError reports whether a failed model request is worth repeating, with the
+// reason and any wait the server asked for. Rate limits (429), overload (529 and the
+// overloaded_error of a stream), server errors and dropped connections are transient;
+// bad requests, authentication errors and cancellation are not.
+func transientLLMError(err error) (reason string, retryAfter time.Duration, ok bool) {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return "", 0, false
+	}
+
+	var apiErr *anthropic.Error
+	var statusErr *LLMStatusError
+	switch {
+	case errors.As(err, &apiErr):
+		var header http.Header
+		if apiErr.Response != nil {
+			header = apiErr.Response.Header
+		}
+		return transientStatus(apiErr.StatusCode, header)
+	case errors.As(err, &statusErr):
+		return transientStatus(statusErr.StatusCode, statusErr.Header)
+	}
+
+	// Errors sent inside an event stream only carry the error type
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "rate_limit_error"):
+		return "rate_limited", 0, true
+	case strings.Contains(message, "overloaded_error"):
+		return "overloaded", 0, true
+	case strings.Contains(message, "received error while streaming") && strings.Contains(message, "api_error"):
+		return "server_error", 0, true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, net.ErrClosed) {
+		return "connection_error", 0, true
+	}
+	return "", 0, false
+}
+
+// transientStatus classifies an HTTP status code, reading the wait from Retry-After
+func transientStatus(status int, header http.Header) (string, time.Duration, bool) {
+	var reason string
+	switch {
+	case status == http.StatusTooManyRequests:
+		reason = "rate_limited"
+	case status == 529:
+		reason = "overloaded"
+	case status == http.StatusRequestTimeout || status >= 500:
+		reason = "server_error"
+	default:
+		return "", 0, false
+	}
+	return reason, retryAfter(header), true
+}
+
+// retryAfter parses the wait a server asked for, in milliseconds or seconds
+func retryAfter(header http.Header) time.Duration {
+	if header == nil {
+		return 0
+	}
+	if ms, err := strconv.ParseFloat(header.Get("retry-after-ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+	if seconds, err := strconv.ParseFloat(header.Get("retry-after"), 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	return 0
+}
+
+// delay returns how long to wait before the given attempt: the server's Retry-After when
+// it sent one, otherwise exponential backoff with jitter
+func (p retryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, p.MaxDelay)
+	}
+	backoff := p.BaseDelay << (attempt - 2)
+	if backoff <= 0 || backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+	// Up to 25% jitter keeps clients that failed together from retrying together
+	if jitter := int64(backoff / 4); jitter > 0 {
+		backoff += time.Duration(rand.Int63n(jitter))
+	}
+	return min(backoff, p.MaxDelay)
+}
+
+// withRetry runs request until it succeeds, fails with an error that isn't transient, or
+// runs out of attempts. Before each retry it emits "retrying" and a status message, then
+// waits unless ctx is cancelled. A streamed request that already showed text isn't
+// repeated, since the text would appear twice.
+func (a *AIAgent) withRetry(ctx context.Context, request func() (*anthropic.Message, bool, error)) (*anthropic.Message, error) {
+	policy := a.retry
+	for attempt := 1; ; attempt++ {
+		message, showedText, err := request()
+		if err == nil {
+			return message, nil
+		}
+		reason, wait, transient := transientLLMError(err)
+		if !transient || showedText || attempt >= policy.MaxAttempts {
+			return nil, err
+		}
+
+		delay := policy.delay(attempt+1, wait)
+		seconds := int((delay + time.Second - 1) / time.Second)
+		a.logToFile("RETRY", fmt.Sprintf("Model request failed (%s), retrying in %v (attempt %d of %d)", reason, delay, attempt+1, policy.MaxAttempts), err.Error())
+		if a.app != nil && a.app.events != nil && ctx != nil {
+			a.app.events.Emit(ctx, "retrying", RetryStatus{
+				Attempt:      attempt + 1,
+				MaxAttempts:  policy.MaxAttempts,
+				DelaySeconds: seconds,
+				Reason:       reason,
+			})
+		}
+		a.emitMessage(fmt.Sprintf("⏳ %s, retrying in %ds...", retryReasonText(reason), seconds))
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// retryReasonText describes a retry reason for the chat
+func retryReasonText(reason string) string {
+	switch reason {
+	case "rate_limited":
+		return "Rate limited by the model provider"
+	case "overloaded":
+		return "The model is overloaded"
+	case "connection_error":
+		return "Lost the connection to the model provider"
+	default:
+		return "The model provider had an error"
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// fastRetries retries without waiting
+var fastRetries = retryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+func TestTransientLLMErrorClassification(t *testing.T) {
+	header := http.Header{}
+	header.Set("retry-after", "7")
+	cases := []struct {
+		err        error
+		reason     string
+		retryAfter time.Duration
+	}{
+		{&LLMStatusError{StatusCode: 429, Status: "429 Too Many Requests", Header: header}, "rate_limited", 7 * time.Second},
+		{&LLMStatusError{StatusCode: 529, Status: "529"}, "overloaded", 0},
+		{&LLMStatusError{StatusCode: 503, Status: "503 Service Unavailable"}, "server_error", 0},
+		{fmt.Errorf(`received error while streaming: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`), "overloaded", 0},
+		{&LLMStatusError{StatusCode: 400, Status: "400 Bad Request"}, "", 0},
+		{&LLMStatusError{StatusCode: 401, Status: "401 Unauthorized"}, "", 0},
+		{context.Canceled, "", 0},
+	}
+	for _, c := range cases {
+		reason, retryAfter, ok := transientLLMError(c.err)
+		if reason != c.reason || retryAfter != c.retryAfter || ok != (c.reason != "") {
+			t.Errorf("%v: expected %q after %v, got %q after %v (%v)", c.err, c.reason, c.retryAfter, reason, retryAfter, ok)
+		}
+	}
+}
+
+func TestRetryDelayBacksOffAndHonorsRetryAfter(t *testing.T) {
+	policy := retryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	for attempt, base := range map[int]time.Duration{2: time.Second, 3: 2 * time.Second, 4: 4 * time.Second} {
+		if delay := policy.delay(attempt, 0); delay < base || delay > base*5/4 {
+			t.Errorf("attempt %d: expected %v plus jitter, got %v", attempt, base, delay)
+		}
+	}
+	if delay := policy.delay(10, 0); delay != 10*time.Second {
+		t.Errorf("expected the delay to be capped, got %v", delay)
+	}
+	if delay := policy.delay(2, 3*time.Second); delay != 3*time.Second {
+		t.Errorf("expected Retry-After to be used, got %v", delay)
+	}
+}
+
+func TestSendMessageRetriesOverloadedRequests(t *testing.T) {
+	env := newTestEnv(t, textResponse("Done."))
+	env.llm.Failures = []error{
+		&LLMStatusError{StatusCode: 529, Status: "529"},
+		&LLMStatusError{StatusCode: 429, Status: "429 Too Many Requests"},
+	}
+	env.app.aiAgent.retry = fastRetries
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Hi"); err != nil {
+		t.Fatalf("expected the turn to succeed after retries, got %v", err)
+	}
+	if len(env.llm.Requests) != 3 {
+		t.Errorf("expected 3 attempts, got %d", len(env.llm.Requests))
+	}
+	retries := env.events.Messages("retrying")
+	if len(retries) != 2 || retries[0].(RetryStatus).Reason != "overloaded" || retries[1].(RetryStatus).Attempt != 3 {
+		t.Errorf("expected a retrying event per retry, got %+v", retries)
+	}
+}
+
+func TestSendMessageGivesUpAfterMaxAttempts(t *testing.T) {
+	env := newTestEnv(t, textResponse("Never reached."))
+	overloaded := &LLMStatusError{StatusCode: 529, Status: "529"}
+	env.llm.Failures = []error{overloaded, overloaded, overloaded}
+	env.app.aiAgent.retry = fastRetries
+
+	err := env.app.aiAgent.SendMessage(context.Background(), "Hi")
+	if !errors.Is(err, overloaded) {
+		t.Fatalf("expected the last error, got %v", err)
+	}
+	if len(env.llm.Requests) != 3 {
+		t.Errorf("expected 3 attempts, got %d", len(env.llm.Requests))
+	}
+}
+
+func TestSendMessageDoesNotRetryPermanentErrors(t *testing.T) {
+	env := newTestEnv(t, textResponse("Never reached."))
+	env.llm.Failures = []error{&LLMStatusError{StatusCode: 400, Status: "400 Bad Request"}}
+	env.app.aiAgent.retry = fastRetries
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Hi"); err == nil {
+		t.Fatal("expected the bad request to fail the turn")
+	}
+	if len(env.llm.Requests) != 1 || len(env.events.Messages("retrying")) != 0 {
+		t.Errorf("expected no retries, got %d requests", len(env.llm.Requests))
+	}
+}
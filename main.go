@@ -2,6 +2,8 @@ package main
 
 import (
 	"embed"
+	"fmt"
+	"os"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
@@ -12,6 +14,35 @@ import (
 var assets embed.FS
 
 func main() {
+	// --profile records per-tool and per-stage timings into profiles/ for bug reports
+	if profilingRequested(os.Args[1:]) {
+		profiler = NewProfiler()
+		fmt.Printf("Profiling enabled, writing trace to %s\n", profiler.tracePath)
+	}
+
+	// "run", "tool" and "help" work on presentations from the command line without the window
+	if cliCommand(os.Args[1:]) {
+		os.Exit(runCLI(os.Args[1:]))
+	}
+
+	// --mcp (stdio) or --mcp-sse=<addr> serves the slide tools to MCP clients instead of opening the window
+	if transport, addr, ok := mcpFlags(os.Args[1:]); ok {
+		if err := runMCPServer(transport, addr); err != nil {
+			fmt.Fprintf(os.Stderr, "MCP server failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// --api[=<addr>] serves the REST and chat streaming API for other applications
+	if addr, ok := apiFlags(os.Args[1:]); ok {
+		if err := runAPIServer(addr); err != nil {
+			fmt.Fprintf(os.Stderr, "API server failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Create an instance of the app structure
 	app := NewApp()
 
@@ -22,9 +53,12 @@ func main() {
 		Height: 768,
 		AssetServer: &assetserver.Options{
 			Assets: assets,
+			// Slide previews are streamed from disk rather than sent as base64 strings
+			Handler: NewSlideImageHandler("slides"),
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
 		OnStartup:        app.startup,
+		OnShutdown:       app.shutdown,
 		Bind: []interface{}{
 			app,
 		},
@@ -0,0 +1,458 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// masterPart is a slide master, or one of its layouts, that a master editing tool changes
+type masterPart struct {
+	Part       string
+	MasterPart string
+	Master     int    // 1-based
+	Layout     string // "" for the master itself
+}
+
+// label names the master or layout for messages
+func (m masterPart) label() string {
+	if m.Layout != "" {
+		return fmt.Sprintf("layout '%s' of master %d", m.Layout, m.Master)
+	}
+	return fmt.Sprintf("master %d", m.Master)
+}
+
+// findMasterPart looks up a 1-based slide master (0 means the first) and, when layout is
+// given, its layout with that name or type
+func findMasterPart(pkg *pptxPackage, master int, layout string) (masterPart, error) {
+	refs, err := pkg.masterRefs()
+	if err != nil {
+		return masterPart{}, NewToolError(ErrCodeInternal, "failed to read slide masters: %v", err)
+	}
+	if master == 0 {
+		master = 1
+	}
+	if master < 1 || master > len(refs) {
+		return masterPart{}, NewToolError(ErrCodeInvalidInput, "master %d is not between 1 and %d", master, len(refs))
+	}
+	target := masterPart{Part: refs[master-1].Part, MasterPart: refs[master-1].Part, Master: master}
+	if layout == "" {
+		return target, nil
+	}
+
+	layouts, err := pkg.Layouts()
+	if err != nil {
+		return masterPart{}, NewToolError(ErrCodeInternal, "failed to read layouts: %v", err)
+	}
+	layouts = slices.DeleteFunc(layouts, func(l pptxLayout) bool { return l.Master != master })
+	found, ok := findLayout(layouts, layout)
+	if !ok {
+		names := make([]string, len(layouts))
+		for i, l := range layouts {
+			names[i] = l.Name
+		}
+		return masterPart{}, NewToolError(ErrCodeInvalidInput, "master %d has no layout %q; its layouts are: %s", master, layout, strings.Join(names, ", "))
+	}
+	target.Part = found.part
+	target.Layout = found.Name
+	return target, nil
+}
+
+// findMasterShape picks a shape of a master or layout by placeholder type, shape_id or,
+// failing both, shape_index
+func findMasterShape(shapes []masterShapeInfo, placeholder, shapeID string, shapeIndex int, where string) (masterShapeInfo, error) {
+	switch {
+	case placeholder != "":
+		for _, shape := range shapes {
+			if strings.EqualFold(shape.Placeholder, placeholder) {
+				return shape, nil
+			}
+		}
+		return masterShapeInfo{}, NewToolError(ErrCodeShapeNotFound, "%s has no %s placeholder", where, placeholder)
+	case shapeID != "":
+		for _, shape := range shapes {
+			if shape.ShapeID == shapeID {
+				return shape, nil
+			}
+		}
+		return masterShapeInfo{}, NewToolError(ErrCodeShapeNotFound, "%s has no shape %q; use read_master to find its shape_ids", where, shapeID)
+	}
+	if shapeIndex < 0 || shapeIndex >= len(shapes) {
+		return masterShapeInfo{}, NewToolError(ErrCodeShapeNotFound, "shape index %d out of range for %s (0-%d)", shapeIndex, where, len(shapes)-1)
+	}
+	return shapes[shapeIndex], nil
+}
+
+// masterEditError turns an error from editing master XML into a tool error
+func masterEditError(err error, shape masterShapeInfo) error {
+	var toolErr *ToolError
+	switch {
+	case errors.As(err, &toolErr):
+		return err
+	case errors.Is(err, errTextHasField):
+		return NewToolError(ErrCodeNotEditable, "shape %q shows a slide number or date field; restyle or move it with edit_master_placeholder and turn it on or off with set_footer", shape.ShapeID)
+	case errors.Is(err, errNoTextBody):
+		return NewToolError(ErrCodeNotEditable, "shape %q (%s) has no text to edit", shape.ShapeID, shape.Kind)
+	}
+	return NewToolError(ErrCodeInternal, "failed to edit the master: %v", err)
+}
+
+// ReadMasterDefinition defines the read_master tool
+var ReadMasterDefinition = ToolDefinition{
+	Name: "read_master",
+	Description: `Read the slide masters of the presentation: the shapes on each master (title, body, footer, date and slide number placeholders, logos and other decoration shown on every slide), the title, body and other text styles (font, size in points, color, bold, italic) slides inherit, and each layout with its own shapes and the slides using it.
+
+Use this before edit_master_text and edit_master_placeholder to find shape_ids and placeholder types (title, body, ftr, dt, sldNum, ...). Layout placeholders with inherits_position sit where the master's placeholder of the same type is. Theme fonts are reported by their typeface; colors are #RRGGBB or a theme color such as tx1. Most decks have one master; master limits the result to one of them.`,
+	InputSchema: ReadMasterInputSchema,
+	Function:    ReadMaster,
+	ReadOnly:    true,
+}
+
+type ReadMasterInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Master           int    `json:"master,omitempty" jsonschema_description:"(Optional) Only read this slide master (1-based); omit for all"`
+}
+
+var ReadMasterInputSchema = GenerateSchema[ReadMasterInput]()
+
+func ReadMaster(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	readInput := ReadMasterInput{}
+	if err := json.Unmarshal(input, &readInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+	path, err := pptxDeckPath(app, readInput.PresentationPath, "slide masters")
+	if err != nil {
+		return "", err
+	}
+
+	pkg, err := openPPTX(path)
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to read presentation: %v", err)
+	}
+	defer pkg.Close()
+	masters, err := pkg.Masters()
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to read slide masters: %v", err)
+	}
+	total := len(masters)
+	if readInput.Master != 0 {
+		if readInput.Master < 1 || readInput.Master > total {
+			return "", NewToolError(ErrCodeInvalidInput, "master %d is not between 1 and %d", readInput.Master, total)
+		}
+		masters = masters[readInput.Master-1 : readInput.Master]
+	}
+
+	return marshalResult(map[string]interface{}{
+		"total_masters": total,
+		"masters":       masters,
+	})
+}
+
+// EditMasterTextDefinition defines the edit_master_text tool
+var EditMasterTextDefinition = ToolDefinition{
+	Name: "edit_master_text",
+	Description: `Replace the text of a shape on a slide master or layout, such as the company name in the master's footer or a confidentiality notice, so the change shows on every slide using it instead of being made slide by slide.
+
+Find the shape with read_master and pass its shape_id (or shape_index); give layout (a name or type from read_master) to edit a layout's shape instead of the master's. Each line of text becomes a paragraph formatted like the shape's first paragraph. When a master placeholder changes, layout placeholders of the same type that showed the same text follow; the result lists them.
+
+Text on a master placeholder is only what slides show until they get text of their own: the master title's text is just a prompt, and slides with their own footer text keep it (set_footer changes slide footers). Slide number and date fields can't be edited here.`,
+	InputSchema: EditMasterTextInputSchema,
+	Function:    EditMasterText,
+	Mutating:    true,
+}
+
+type EditMasterTextInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Master           int    `json:"master,omitempty" jsonschema_description:"(Optional) Slide master to edit (1-based, default 1)"`
+	Layout           string `json:"layout,omitempty" jsonschema_description:"(Optional) Name or type of one of the master's layouts to edit instead of the master"`
+	ShapeID          string `json:"shape_id,omitempty" jsonschema_description:"(Optional) shape_id of the shape from read_master; used instead of shape_index"`
+	ShapeIndex       int    `json:"shape_index" jsonschema_description:"Shape index on the master or layout; ignored when shape_id is given"`
+	Text             string `json:"text" jsonschema_description:"New text; each line becomes a paragraph"`
+}
+
+var EditMasterTextInputSchema = GenerateSchema[EditMasterTextInput]()
+
+func EditMasterText(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	editInput := EditMasterTextInput{}
+	if err := json.Unmarshal(input, &editInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+	path, err := pptxDeckPath(app, editInput.PresentationPath, "slide masters")
+	if err != nil {
+		return "", err
+	}
+
+	var target masterPart
+	var shape masterShapeInfo
+	var updatedLayouts []string
+	err = rewritePackage(path, func(pkg *pptxPackage) (map[string][]byte, error) {
+		part, err := findMasterPart(pkg, editInput.Master, editInput.Layout)
+		if err != nil {
+			return nil, err
+		}
+		target = part
+		shapes, err := pkg.masterShapes(target.Part)
+		if err != nil {
+			return nil, err
+		}
+		if shape, err = findMasterShape(shapes, "", editInput.ShapeID, editInput.ShapeIndex, target.label()); err != nil {
+			return nil, err
+		}
+
+		replaced := map[string][]byte{}
+		setText := func(part string, index int) error {
+			data, err := pkg.readPart(part)
+			if err != nil {
+				return err
+			}
+			a := dmlPrefix(data)
+			edited, err := editShapeXML(data, index, func(shape []byte) ([]byte, error) {
+				return withShapeText(shape, editInput.Text, a)
+			})
+			if err != nil {
+				return err
+			}
+			replaced[part] = edited
+			return nil
+		}
+		if err := setText(target.Part, shape.ShapeIndex); err != nil {
+			return nil, err
+		}
+
+		// Layout copies of a master placeholder that still show its text follow it
+		if target.Layout != "" || shape.Placeholder == "" {
+			return replaced, nil
+		}
+		layouts, err := pkg.Layouts()
+		if err != nil {
+			return nil, err
+		}
+		for _, layout := range layouts {
+			if layout.Master != target.Master {
+				continue
+			}
+			layoutShapes, err := pkg.masterShapes(layout.part)
+			if err != nil {
+				return nil, err
+			}
+			for _, layoutShape := range layoutShapes {
+				if layoutShape.Placeholder != shape.Placeholder || layoutShape.Text != shape.Text || shape.Text == "" {
+					continue
+				}
+				if err := setText(layout.part, layoutShape.ShapeIndex); err != nil {
+					return nil, err
+				}
+				updatedLayouts = append(updatedLayouts, layout.Name)
+			}
+		}
+		return replaced, nil
+	})
+	if err != nil {
+		return "", masterEditError(err, shape)
+	}
+
+	// Every slide using the master or layout may show the text
+	schedulePreviewExport(ctx, app, path)
+
+	message := fmt.Sprintf("Changed the text of %q on %s", shape.ShapeID, target.label())
+	if len(updatedLayouts) > 0 {
+		message += fmt.Sprintf(" and on %d layout(s)", len(updatedLayouts))
+	}
+	return marshalResult(map[string]interface{}{
+		"master":          target.Master,
+		"layout":          target.Layout,
+		"shape_index":     shape.ShapeIndex,
+		"shape_id":        shape.ShapeID,
+		"layouts_updated": updatedLayouts,
+		"message":         message,
+	})
+}
+
+// EditMasterPlaceholderDefinition defines the edit_master_placeholder tool
+var EditMasterPlaceholderDefinition = ToolDefinition{
+	Name: "edit_master_placeholder",
+	Description: `Move, resize or restyle a placeholder (or other shape, such as a logo) on a slide master or layout, so titles, body text or footers change on every slide using it - e.g. "make all titles Georgia 36pt navy" or "move the footer to the left".
+
+Pick the shape by placeholder type (title, body, ftr, dt, sldNum, ... as read_master reports them), shape_id or shape_index; give layout to change one of the master's layouts instead. Position and size are in inches; values left out stay as they are. font, font_size (points), color (#RRGGBB), bold and italic restyle a placeholder's text: on the master the title and body placeholders change the master's title and body text styles, at every level, which every layout and slide inherits; other placeholders, and placeholders on layouts, change the text typed into them.
+
+Slides whose own text sets a different font, size or color keep it; check with lint_style afterwards.`,
+	InputSchema: EditMasterPlaceholderInputSchema,
+	Function:    EditMasterPlaceholder,
+	Mutating:    true,
+}
+
+type EditMasterPlaceholderInput struct {
+	PresentationPath string   `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Master           int      `json:"master,omitempty" jsonschema_description:"(Optional) Slide master to edit (1-based, default 1)"`
+	Layout           string   `json:"layout,omitempty" jsonschema_description:"(Optional) Name or type of one of the master's layouts to edit instead of the master"`
+	Placeholder      string   `json:"placeholder,omitempty" jsonschema_description:"(Optional) Placeholder type to edit, e.g. 'title', 'body' or 'ftr'; used instead of shape_id"`
+	ShapeID          string   `json:"shape_id,omitempty" jsonschema_description:"(Optional) shape_id of the shape from read_master; used instead of shape_index"`
+	ShapeIndex       int      `json:"shape_index" jsonschema_description:"Shape index on the master or layout; ignored when placeholder or shape_id is given"`
+	X                *float64 `json:"x,omitempty" jsonschema_description:"(Optional) Left position in inches"`
+	Y                *float64 `json:"y,omitempty" jsonschema_description:"(Optional) Top position in inches"`
+	Width            *float64 `json:"width,omitempty" jsonschema_description:"(Optional) Width in inches"`
+	Height           *float64 `json:"height,omitempty" jsonschema_description:"(Optional) Height in inches"`
+	Font             string   `json:"font,omitempty" jsonschema_description:"(Optional) Font family, e.g. 'Georgia'"`
+	FontSize         float64  `json:"font_size,omitempty" jsonschema_description:"(Optional) Font size in points"`
+	Color            string   `json:"color,omitempty" jsonschema_description:"(Optional) Text color as hex, e.g. '#1F3864'"`
+	Bold             *bool    `json:"bold,omitempty" jsonschema_description:"(Optional) Make the text bold or not"`
+	Italic           *bool    `json:"italic,omitempty" jsonschema_description:"(Optional) Make the text italic or not"`
+}
+
+var EditMasterPlaceholderInputSchema = GenerateSchema[EditMasterPlaceholderInput]()
+
+func EditMasterPlaceholder(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	editInput := EditMasterPlaceholderInput{}
+	if err := json.Unmarshal(input, &editInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+	path, err := pptxDeckPath(app, editInput.PresentationPath, "slide masters")
+	if err != nil {
+		return "", err
+	}
+
+	moves := editInput.X != nil || editInput.Y != nil || editInput.Width != nil || editInput.Height != nil
+	style := runStyle{
+		Font:   strings.TrimSpace(editInput.Font),
+		Size:   editInput.FontSize,
+		Color:  strings.TrimPrefix(editInput.Color, "#"),
+		Bold:   editInput.Bold,
+		Italic: editInput.Italic,
+	}
+	restyles := style != runStyle{}
+	if !moves && !restyles {
+		return "", NewToolError(ErrCodeInvalidInput, "give a position or size (x, y, width, height) or text formatting (font, font_size, color, bold, italic)")
+	}
+	if (editInput.Width != nil && *editInput.Width <= 0) || (editInput.Height != nil && *editInput.Height <= 0) {
+		return "", NewToolError(ErrCodeInvalidInput, "width and height must be greater than 0")
+	}
+	if editInput.FontSize != 0 && (editInput.FontSize < 1 || editInput.FontSize > 400) {
+		return "", NewToolError(ErrCodeInvalidInput, "font_size must be between 1 and 400 points")
+	}
+	if editInput.Color != "" && !hexColorPattern.MatchString(editInput.Color) {
+		return "", NewToolError(ErrCodeInvalidInput, "color must be a hex color like '#1F3864'")
+	}
+
+	var target masterPart
+	var shape masterShapeInfo
+	var geometry [4]float64
+	var textStyle string
+	err = rewritePackage(path, func(pkg *pptxPackage) (map[string][]byte, error) {
+		part, err := findMasterPart(pkg, editInput.Master, editInput.Layout)
+		if err != nil {
+			return nil, err
+		}
+		target = part
+		shapes, err := pkg.masterShapes(target.Part)
+		if err != nil {
+			return nil, err
+		}
+		if shape, err = findMasterShape(shapes, editInput.Placeholder, editInput.ShapeID, editInput.ShapeIndex, target.label()); err != nil {
+			return nil, err
+		}
+
+		data, err := pkg.readPart(target.Part)
+		if err != nil {
+			return nil, err
+		}
+		a := dmlPrefix(data)
+
+		if moves {
+			if shape.Kind != shapeKindShape && shape.Kind != shapeKindPicture && shape.Kind != shapeKindConnector {
+				return nil, NewToolError(ErrCodeNotEditable, "shape %q (%s) can't be moved here", shape.ShapeID, shape.Kind)
+			}
+			geometry = [4]float64{shape.X, shape.Y, shape.Width, shape.Height}
+			if shape.Inherited {
+				// Start from where the master's placeholder of the same type puts it
+				known := false
+				if target.Layout != "" {
+					masterShapes, err := pkg.masterShapes(target.MasterPart)
+					if err != nil {
+						return nil, err
+					}
+					for _, masterShape := range masterShapes {
+						if masterShape.Placeholder == shape.Placeholder && !masterShape.Inherited {
+							geometry = [4]float64{masterShape.X, masterShape.Y, masterShape.Width, masterShape.Height}
+							known = true
+							break
+						}
+					}
+				}
+				if !known && (editInput.X == nil || editInput.Y == nil || editInput.Width == nil || editInput.Height == nil) {
+					return nil, NewToolError(ErrCodeInvalidInput, "shape %q takes its position from the master; give all of x, y, width and height", shape.ShapeID)
+				}
+			}
+			for i, value := range []*float64{editInput.X, editInput.Y, editInput.Width, editInput.Height} {
+				if value != nil {
+					geometry[i] = *value
+				}
+			}
+			emu := func(inches float64) int64 { return int64(math.Round(inches * emuPerInch)) }
+			data, err = editShapeXML(data, shape.ShapeIndex, func(shapeXML []byte) ([]byte, error) {
+				return withShapeGeometry(shapeXML, emu(geometry[0]), emu(geometry[1]), emu(geometry[2]), emu(geometry[3]), a)
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if restyles {
+			if shape.Kind != shapeKindShape {
+				return nil, errNoTextBody
+			}
+			// A master's title and body placeholders take their text from its text styles
+			if target.Layout == "" {
+				switch shape.Placeholder {
+				case "title", "ctrTitle":
+					textStyle = "titleStyle"
+				case "body", "subTitle", "obj":
+					textStyle = "bodyStyle"
+				}
+			}
+			data, err = editShapeXML(data, shape.ShapeIndex, func(shapeXML []byte) ([]byte, error) {
+				return withShapeTextStyle(shapeXML, style, textStyle == "", a)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if textStyle != "" {
+				if data, err = withMasterTextStyle(data, textStyle, style, a); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return map[string][]byte{target.Part: data}, nil
+	})
+	if err != nil {
+		return "", masterEditError(err, shape)
+	}
+
+	// Every slide using the master or layout may change
+	schedulePreviewExport(ctx, app, path)
+
+	verb := "Moved"
+	if moves && restyles {
+		verb = "Moved and restyled"
+	} else if restyles {
+		verb = "Restyled"
+	}
+	result := map[string]interface{}{
+		"master":      target.Master,
+		"layout":      target.Layout,
+		"shape_index": shape.ShapeIndex,
+		"shape_id":    shape.ShapeID,
+		"placeholder": shape.Placeholder,
+		"message":     fmt.Sprintf("%s %q on %s", verb, shape.ShapeID, target.label()),
+	}
+	if moves {
+		result["x"], result["y"], result["width"], result["height"] = geometry[0], geometry[1], geometry[2], geometry[3]
+	}
+	if textStyle != "" {
+		result["text_style"] = strings.TrimSuffix(textStyle, "Style")
+	}
+	return marshalResult(result)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestReadMaster(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "master_shapes.pptx")
+
+	output, err := ReadMaster(context.Background(), env.app, json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("ReadMaster failed: %v", err)
+	}
+	var result struct {
+		TotalMasters int          `json:"total_masters"`
+		Masters      []masterInfo `json:"masters"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.TotalMasters != 1 || len(result.Masters) != 1 {
+		t.Fatalf("expected one master, got %s", output)
+	}
+	master := result.Masters[0]
+	if len(master.Shapes) != 5 || master.Shapes[2].Placeholder != "ftr" || master.Shapes[2].Text != "Acme Corp" || master.Shapes[4].Kind != shapeKindPicture {
+		t.Errorf("unexpected master shapes: %+v", master.Shapes)
+	}
+	if title := master.TextStyles["title"]; title.Font != "Calibri Light" || title.Size != 44 || title.Color != "tx1" {
+		t.Errorf("expected the theme's heading font in the title style, got %+v", title)
+	}
+	if body := master.TextStyles["body"]; body.Font != "Calibri" || body.Size != 28 {
+		t.Errorf("unexpected body style: %+v", body)
+	}
+	if len(master.Layouts) != 1 || len(master.Layouts[0].Slides) != 2 || !master.Layouts[0].Shapes[0].Inherited {
+		t.Errorf("expected the layout with its inherited title, got %+v", master.Layouts)
+	}
+
+	_, err = ReadMaster(context.Background(), env.app, json.RawMessage(`{"master": 2}`))
+	if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+		t.Errorf("expected %s for a missing master, got %s (%v)", ErrCodeInvalidInput, code, err)
+	}
+}
+
+func TestEditMasterTextFollowsLayouts(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "master_shapes.pptx")
+
+	output, err := EditMasterText(context.Background(), env.app, json.RawMessage(`{"shape_id": "Footer Placeholder 3", "text": "Globex & Co"}`))
+	if err != nil {
+		t.Fatalf("EditMasterText failed: %v", err)
+	}
+	if !strings.Contains(output, `"layouts_updated":["Title and Content"]`) {
+		t.Errorf("expected the layout's footer to follow, got %s", output)
+	}
+
+	pkg, err := openPPTX(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	masters, err := pkg.Masters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	slideShapes, _ := pkg.SlideShapes(1)
+	pkg.Close()
+	if text := masters[0].Shapes[2].Text; text != "Globex & Co" {
+		t.Errorf("expected the master footer to change, got %q", text)
+	}
+	if text := masters[0].Layouts[0].Shapes[1].Text; text != "Globex & Co" {
+		t.Errorf("expected the layout footer to change, got %q", text)
+	}
+	if slideShapes[1].Text != "Acme Corp" {
+		t.Errorf("slides keep their own footer text, got %q", slideShapes[1].Text)
+	}
+
+	// A layout placeholder without text of its own gets some
+	if _, err := EditMasterText(context.Background(), env.app, json.RawMessage(`{"layout": "obj", "shape_index": 0, "text": "Quarter"}`)); err != nil {
+		t.Fatalf("EditMasterText failed on the layout: %v", err)
+	}
+	pkg, _ = openPPTX(path)
+	layouts, _ := pkg.Layouts()
+	shapes, _ := pkg.masterShapes(layouts[0].part)
+	pkg.Close()
+	if shapes[0].Text != "Quarter" {
+		t.Errorf("expected the layout title's text, got %+v", shapes[0])
+	}
+
+	for input, want := range map[string]ToolErrorCode{
+		`{"shape_id": "Slide Number Placeholder 4", "text": "Page"}`:    ErrCodeNotEditable,
+		`{"shape_id": "Logo", "text": "Acme"}`:                          ErrCodeNotEditable,
+		`{"shape_id": "Subtitle 9", "text": "Acme"}`:                    ErrCodeShapeNotFound,
+		`{"layout": "Blank", "shape_index": 0, "text": "Acme"}`:         ErrCodeInvalidInput,
+		`{"master": 3, "shape_id": "Footer Placeholder 3", "text": ""}`: ErrCodeInvalidInput,
+	} {
+		_, err := EditMasterText(context.Background(), env.app, json.RawMessage(input))
+		if code := toolErrorCode(err); code != want {
+			t.Errorf("expected %q for %s, got %q (%v)", want, input, code, err)
+		}
+	}
+}
+
+func TestEditMasterPlaceholder(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "master_shapes.pptx")
+
+	output, err := EditMasterPlaceholder(context.Background(), env.app, json.RawMessage(`{"placeholder": "title", "font": "Georgia", "font_size": 36, "color": "#1f3864", "bold": true}`))
+	if err != nil {
+		t.Fatalf("EditMasterPlaceholder failed: %v", err)
+	}
+	if !strings.Contains(output, `"text_style":"title"`) {
+		t.Errorf("expected the master title style to change, got %s", output)
+	}
+	// The layout's footer takes its position from the master until it is moved
+	if _, err := EditMasterPlaceholder(context.Background(), env.app, json.RawMessage(`{"layout": "Title and Content", "placeholder": "ftr", "x": 0.5}`)); err != nil {
+		t.Fatalf("EditMasterPlaceholder failed: %v", err)
+	}
+
+	pkg, err := openPPTX(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	masters, err := pkg.Masters()
+	pkg.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	title := masters[0].TextStyles["title"]
+	if title.Font != "Georgia" || title.Size != 36 || title.Color != "#1F3864" || !title.Bold {
+		t.Errorf("unexpected title style: %+v", title)
+	}
+	if body := masters[0].TextStyles["body"]; body.Font != "Calibri" || body.Size != 28 {
+		t.Errorf("the body style should be unchanged, got %+v", body)
+	}
+	footer := masters[0].Layouts[0].Shapes[1]
+	if footer.Inherited || footer.X != 0.5 || footer.Y != 6.95 || footer.Width != 4.5 {
+		t.Errorf("expected the footer moved from the master's position, got %+v", footer)
+	}
+
+	for _, input := range []string{
+		`{"placeholder": "title"}`,
+		`{"placeholder": "title", "color": "navy"}`,
+		`{"placeholder": "title", "font_size": 0.5}`,
+	} {
+		if _, err := EditMasterPlaceholder(context.Background(), env.app, json.RawMessage(input)); toolErrorCode(err) != ErrCodeInvalidInput {
+			t.Errorf("expected %s for %s, got %v", ErrCodeInvalidInput, input, err)
+		}
+	}
+	_, err = EditMasterPlaceholder(context.Background(), env.app, json.RawMessage(`{"shape_id": "Logo", "bold": true}`))
+	if code := toolErrorCode(err); code != ErrCodeNotEditable {
+		t.Errorf("expected %s for restyling a picture, got %s (%v)", ErrCodeNotEditable, code, err)
+	}
+}
+
+func TestWithRunStyle(t *testing.T) {
+	bold := true
+	for _, test := range []struct {
+		in    string
+		style runStyle
+		want  string
+	}{
+		{`<a:defRPr/>`, runStyle{Size: 20}, `<a:defRPr sz="2000"/>`},
+		{`<a:rPr lang="en-US" b="0"/>`, runStyle{Bold: &bold, Font: "Georgia"}, `<a:rPr lang="en-US" b="1"><a:latin typeface="Georgia"/></a:rPr>`},
+		{
+			`<a:defRPr sz="4400"><a:ln w="1"/><a:solidFill><a:schemeClr val="tx1"/></a:solidFill><a:latin typeface="+mj-lt"/><a:ea typeface="+mj-ea"/></a:defRPr>`,
+			runStyle{Color: "ff0000", Font: "Georgia"},
+			`<a:defRPr sz="4400"><a:ln w="1"/><a:solidFill><a:srgbClr val="FF0000"/></a:solidFill><a:latin typeface="Georgia"/><a:ea typeface="+mj-ea"/></a:defRPr>`,
+		},
+	} {
+		if got := string(withRunStyle([]byte(test.in), test.style, "a:")); got != test.want {
+			t.Errorf("withRunStyle(%s) = %s, want %s", test.in, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,429 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// mcpProtocolVersions are the Model Context Protocol revisions the server speaks, newest first
+var mcpProtocolVersions = []string{"2025-03-26", "2024-11-05"}
+
+// mcpTokenEnv sets a bearer token clients of the SSE transport must send. Without it the
+// transport only listens on loopback addresses.
+const mcpTokenEnv = "SLIDEPILOT_MCP_TOKEN"
+
+// JSON-RPC error codes used by the MCP server
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+// openPresentationTool lets MCP clients choose the presentation the other tools default to,
+// which the desktop app does through its file dialog
+var openPresentationTool = ToolDefinition{
+	Name:        "open_presentation",
+	Description: "Open a presentation so the other tools work on it by default and render its slide previews. Give an absolute path to a .pptx, .ppt, .odp or .key file; other formats are converted to a .pptx next to the file, which the tools then edit.",
+	InputSchema: GenerateSchema[OpenPresentationInput](),
+	Function: func(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+		var openInput OpenPresentationInput
+		if err := json.Unmarshal(input, &openInput); err != nil {
+			return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+		}
+		if openInput.Path == "" {
+			return "", NewToolError(ErrCodeInvalidInput, "path is required")
+		}
+		if _, err := os.Stat(openInput.Path); err != nil {
+			return "", NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", openInput.Path)
+		}
+		slides, err := app.LoadPresentation(openInput.Path)
+		if err != nil {
+			return "", NewToolError(toolErrorCodeOr(err, ErrCodeExportFailed), "%v", err)
+		}
+		return marshalResult(map[string]interface{}{
+			"presentation_path": app.presentationPath(),
+			"total_slides":      len(slides),
+		})
+	},
+}
+
+type OpenPresentationInput struct {
+	Path string `json:"path" jsonschema_description:"Path to the presentation file"`
+}
+
+// rpcMessage is a JSON-RPC 2.0 request or notification; notifications have no ID
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// mcpContent is one item of a tools/call result
+type mcpContent struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Data     string `json:"data,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// MCPServer serves the agent's tools to Model Context Protocol clients such as Claude
+// Desktop or Cursor. Tool calls run through AIAgent.RunTool, so they get the same locking,
+// backups, rollback and undo history as calls made by the built-in chat.
+type MCPServer struct {
+	app   *App
+	calls atomic.Int64 // Numbers tool calls for their tool_use IDs
+}
+
+// NewMCPServer creates a server for the app's tools
+func NewMCPServer(app *App) *MCPServer {
+	return &MCPServer{app: app}
+}
+
+// tools returns the tools offered to MCP clients: the agent's tools plus open_presentation
+func (s *MCPServer) tools() []ToolDefinition {
+	return append(s.app.aiAgent.tools.List(), openPresentationTool)
+}
+
+// handle answers one JSON-RPC message; notifications get a nil response
+func (s *MCPServer) handle(ctx context.Context, data []byte) *rpcResponse {
+	var message rpcMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return &rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: rpcParseError, Message: err.Error()}}
+	}
+	if len(message.ID) == 0 {
+		// Notifications such as notifications/initialized need no answer
+		return nil
+	}
+
+	response := &rpcResponse{JSONRPC: "2.0", ID: message.ID}
+	result, err := s.dispatch(ctx, message)
+	if err != nil {
+		response.Error = err
+	} else {
+		response.Result = result
+	}
+	return response
+}
+
+func (s *MCPServer) dispatch(ctx context.Context, message rpcMessage) (interface{}, *rpcError) {
+	if message.JSONRPC != "2.0" {
+		return nil, &rpcError{Code: rpcInvalidRequest, Message: "jsonrpc must be \"2.0\""}
+	}
+
+	switch message.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		json.Unmarshal(message.Params, &params)
+		version := mcpProtocolVersions[0]
+		if slices.Contains(mcpProtocolVersions, params.ProtocolVersion) {
+			version = params.ProtocolVersion
+		}
+		return map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{"listChanged": false}},
+			"serverInfo":      map[string]interface{}{"name": "slidepilot", "version": appVersion()},
+			"instructions":    "Call open_presentation first, or pass presentation_path to each tool. Use list_slides and read_slide to find slide numbers and shape_ids before editing.",
+		}, nil
+
+	case "ping":
+		return map[string]interface{}{}, nil
+
+	case "tools/list":
+		tools := make([]map[string]interface{}, 0)
+		for _, tool := range s.tools() {
+			schema, _ := json.Marshal(tool.InputSchema)
+			tools = append(tools, map[string]interface{}{
+				"name":        tool.Name,
+				"description": tool.Description,
+				"inputSchema": json.RawMessage(schema),
+			})
+		}
+		return map[string]interface{}{"tools": tools}, nil
+
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(message.Params, &params); err != nil || params.Name == "" {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: "tools/call needs a tool name"}
+		}
+		if len(params.Arguments) == 0 || string(params.Arguments) == "null" {
+			params.Arguments = json.RawMessage("{}")
+		}
+		return s.callTool(ctx, params.Name, params.Arguments), nil
+	}
+
+	return nil, &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("method not found: %s", message.Method)}
+}
+
+// callTool runs a tool and converts its result to MCP content
+func (s *MCPServer) callTool(ctx context.Context, name string, arguments json.RawMessage) map[string]interface{} {
+	id := fmt.Sprintf("mcp_%d", s.calls.Add(1))
+
+	var block anthropic.ContentBlockParamUnion
+	if name == openPresentationTool.Name {
+		if result, err := openPresentationTool.Function(ctx, s.app, arguments); err != nil {
+			block = anthropic.NewToolResultBlock(id, toolErrorEnvelope(err), true)
+		} else {
+			block = anthropic.NewToolResultBlock(id, normalizeToolResult(result), false)
+		}
+	} else {
+		block = s.app.aiAgent.RunTool(ctx, id, name, arguments)
+	}
+
+	content := []mcpContent{}
+	isError := false
+	if result := block.OfToolResult; result != nil {
+		isError = result.IsError.Value
+		for _, part := range result.Content {
+			switch {
+			case part.OfText != nil:
+				content = append(content, mcpContent{Type: "text", Text: part.OfText.Text})
+			case part.OfImage != nil && part.OfImage.Source.OfBase64 != nil:
+				source := part.OfImage.Source.OfBase64
+				content = append(content, mcpContent{Type: "image", Data: source.Data, MimeType: string(source.MediaType)})
+			}
+		}
+	}
+	return map[string]interface{}{"content": content, "isError": isError}
+}
+
+// ServeStdio answers newline-delimited JSON-RPC messages from in until it is closed
+func (s *MCPServer) ServeStdio(ctx context.Context, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	encoder := json.NewEncoder(out)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if response := s.handle(ctx, []byte(line)); response != nil {
+			if err := encoder.Encode(response); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// mcpSession is one SSE client connection
+type mcpSession struct {
+	events chan []byte
+	done   chan struct{} // Closed when the client disconnects, so pending responses are dropped
+}
+
+// SSEHandler serves the HTTP+SSE transport: clients open GET /sse, receive the URL to
+// post messages to as an "endpoint" event, and get responses as "message" events. A
+// non-empty token must be sent as "Authorization: Bearer <token>".
+func (s *MCPServer) SSEHandler(ctx context.Context, token string) http.Handler {
+	var mu sync.Mutex
+	sessions := make(map[string]*mcpSession)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sse", func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		idBytes := make([]byte, 16)
+		rand.Read(idBytes)
+		id := hex.EncodeToString(idBytes)
+		session := &mcpSession{events: make(chan []byte, 16), done: make(chan struct{})}
+		mu.Lock()
+		sessions[id] = session
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			delete(sessions, id)
+			mu.Unlock()
+			close(session.done)
+		}()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		fmt.Fprintf(w, "event: endpoint\ndata: /message?sessionId=%s\n\n", id)
+		flusher.Flush()
+
+		for {
+			select {
+			case event := <-session.events:
+				fmt.Fprintf(w, "event: message\ndata: %s\n\n", event)
+				flusher.Flush()
+			case <-r.Context().Done():
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+	mux.HandleFunc("POST /message", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		session := sessions[r.URL.Query().Get("sessionId")]
+		mu.Unlock()
+		if session == nil {
+			http.Error(w, "unknown session", http.StatusNotFound)
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(r.Body, 16*1024*1024))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+
+		// Tool calls can take minutes, so answer over the stream once done
+		go func() {
+			if response := s.handle(ctx, data); response != nil {
+				encoded, _ := json.Marshal(response)
+				select {
+				case session.events <- encoded:
+				case <-session.done:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	})
+	return guardSSE(token, mux)
+}
+
+// guardSSE rejects requests from web pages on other sites, which a browser would
+// otherwise send to a server on localhost, and requests without the token when one is set
+func guardSSE(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" && !isLocalOrigin(origin) {
+			http.Error(w, "cross-origin requests are not allowed", http.StatusForbidden)
+			return
+		}
+		if token != "" {
+			sent, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !found || subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "missing or invalid bearer token", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLocalOrigin reports whether an Origin header names a page served from this machine
+func isLocalOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	return err == nil && isLoopbackHost(u.Hostname())
+}
+
+// isLoopbackAddr reports whether a listen address such as localhost:8765 only accepts
+// connections from this machine; ":8765" listens on every interface
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	return err == nil && isLoopbackHost(host)
+}
+
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// appVersion reports the version the binary was built as, "(devel)" for local builds
+func appVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+// mcpFlags returns the MCP transport requested on the command line: "--mcp" for stdio
+// or "--mcp-sse=<addr>" for HTTP+SSE. ok is false when neither was given.
+func mcpFlags(args []string) (transport, addr string, ok bool) {
+	for _, arg := range args {
+		arg = "-" + strings.TrimLeft(arg, "-")
+		switch {
+		case arg == "-mcp":
+			return "stdio", "", true
+		case arg == "-mcp-sse":
+			return "sse", "localhost:8765", true
+		case strings.HasPrefix(arg, "-mcp-sse="):
+			return "sse", strings.TrimPrefix(arg, "-mcp-sse="), true
+		}
+	}
+	return "", "", false
+}
+
+// runMCPServer starts LibreOffice without a window and serves the tools until stdin closes
+// (stdio) or the process is interrupted
+func runMCPServer(transport, addr string) error {
+	token := os.Getenv(mcpTokenEnv)
+	if transport == "sse" && token == "" && !isLoopbackAddr(addr) {
+		return fmt.Errorf("refusing to serve MCP on %s without a token: set %s, or listen on localhost", addr, mcpTokenEnv)
+	}
+
+	// stdout carries the protocol, so everything else the app prints goes to stderr
+	protocol := os.Stdout
+	os.Stdout = os.Stderr
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app := NewHeadlessApp(NoopEmitter{})
+	app.startup(ctx)
+	defer app.shutdown(context.Background())
+	server := NewMCPServer(app)
+
+	if transport == "stdio" {
+		fmt.Println("Serving MCP over stdio")
+		return server.ServeStdio(ctx, os.Stdin, protocol)
+	}
+
+	httpServer := &http.Server{Addr: addr, Handler: server.SSEHandler(ctx, token)}
+	go func() {
+		<-ctx.Done()
+		httpServer.Close()
+	}()
+	fmt.Printf("Serving MCP over SSE at http://%s/sse\n", addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mcpExchange sends newline-delimited requests over the stdio transport and returns the responses by ID
+func mcpExchange(t *testing.T, server *MCPServer, requests ...string) map[string]rpcResponse {
+	t.Helper()
+	var out bytes.Buffer
+	if err := server.ServeStdio(context.Background(), strings.NewReader(strings.Join(requests, "\n")), &out); err != nil {
+		t.Fatalf("ServeStdio failed: %v", err)
+	}
+
+	responses := make(map[string]rpcResponse)
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var response struct {
+			rpcResponse
+			Result json.RawMessage `json:"result"`
+		}
+		if err := decoder.Decode(&response); err != nil {
+			t.Fatalf("invalid response: %v", err)
+		}
+		response.rpcResponse.Result = response.Result
+		responses[string(response.ID)] = response.rpcResponse
+	}
+	return responses
+}
+
+func TestMCPServerListsAndCallsTools(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	server := NewMCPServer(env.app)
+
+	responses := mcpExchange(t, server,
+		`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2024-11-05"}}`,
+		`{"jsonrpc": "2.0", "method": "notifications/initialized"}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "tools/call", "params": {"name": "list_slides", "arguments": {}}}`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "tools/call", "params": {"name": "read_slide", "arguments": {"slide_number": 9}}}`,
+		`{"jsonrpc": "2.0", "id": 5, "method": "resources/list"}`,
+	)
+	if len(responses) != 5 {
+		t.Fatalf("expected 5 responses (none for the notification), got %d", len(responses))
+	}
+
+	var initialize struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	json.Unmarshal(responses["1"].Result.(json.RawMessage), &initialize)
+	if initialize.ProtocolVersion != "2024-11-05" {
+		t.Errorf("expected the client's protocol version to be accepted, got %q", initialize.ProtocolVersion)
+	}
+
+	var list struct {
+		Tools []struct {
+			Name        string          `json:"name"`
+			InputSchema json.RawMessage `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	json.Unmarshal(responses["2"].Result.(json.RawMessage), &list)
+	names := make(map[string]bool)
+	for _, tool := range list.Tools {
+		names[tool.Name] = true
+	}
+	if !names["list_slides"] || !names["edit_slide_text"] || !names["open_presentation"] {
+		t.Errorf("expected the agent's tools plus open_presentation, got %v", names)
+	}
+
+	type callResult struct {
+		Content []mcpContent `json:"content"`
+		IsError bool         `json:"isError"`
+	}
+	var listSlides callResult
+	json.Unmarshal(responses["3"].Result.(json.RawMessage), &listSlides)
+	if listSlides.IsError || len(listSlides.Content) == 0 || !strings.Contains(listSlides.Content[0].Text, `"total_slides":2`) {
+		t.Errorf("unexpected list_slides result %+v", listSlides)
+	}
+
+	var readSlide callResult
+	json.Unmarshal(responses["4"].Result.(json.RawMessage), &readSlide)
+	if !readSlide.IsError || !strings.Contains(readSlide.Content[0].Text, string(ErrCodeSlideOutOfRange)) {
+		t.Errorf("expected an out of range tool error, got %+v", readSlide)
+	}
+
+	if responses["5"].Error == nil || responses["5"].Error.Code != rpcMethodNotFound {
+		t.Errorf("expected method not found, got %+v", responses["5"])
+	}
+}
+
+func TestMCPFlags(t *testing.T) {
+	for _, test := range []struct {
+		args      []string
+		transport string
+		addr      string
+		ok        bool
+	}{
+		{nil, "", "", false},
+		{[]string{"--mcp"}, "stdio", "", true},
+		{[]string{"-mcp-sse"}, "sse", "localhost:8765", true},
+		{[]string{"--mcp-sse=0.0.0.0:9000"}, "sse", "0.0.0.0:9000", true},
+	} {
+		transport, addr, ok := mcpFlags(test.args)
+		if transport != test.transport || addr != test.addr || ok != test.ok {
+			t.Errorf("mcpFlags(%v) = %q, %q, %v", test.args, transport, addr, ok)
+		}
+	}
+}
+
+func TestMCPSSERejectsOtherOriginsAndMissingTokens(t *testing.T) {
+	env := newTestEnv(t)
+	request := func(handler http.Handler, origin, token string) int {
+		r := httptest.NewRequest("POST", "/message?sessionId=none", strings.NewReader("{}"))
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// Requests that get past the guard fail on the unknown session
+	open := NewMCPServer(env.app).SSEHandler(context.Background(), "")
+	for origin, want := range map[string]int{
+		"":                      http.StatusNotFound,
+		"http://localhost:3000": http.StatusNotFound,
+		"http://127.0.0.1":      http.StatusNotFound,
+		"http://[::1]:8080":     http.StatusNotFound,
+		"https://evil.example":  http.StatusForbidden,
+		"http://localhost.evil": http.StatusForbidden,
+		"null":                  http.StatusForbidden,
+	} {
+		if got := request(open, origin, ""); got != want {
+			t.Errorf("origin %q: expected %d, got %d", origin, want, got)
+		}
+	}
+
+	secured := NewMCPServer(env.app).SSEHandler(context.Background(), "secret")
+	for token, want := range map[string]int{"": http.StatusUnauthorized, "wrong": http.StatusUnauthorized, "secret": http.StatusNotFound} {
+		if got := request(secured, "", token); got != want {
+			t.Errorf("token %q: expected %d, got %d", token, want, got)
+		}
+	}
+}
+
+func TestMCPSSERefusesPublicAddressesWithoutToken(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"localhost:8765": true,
+		"127.0.0.1:8765": true,
+		"[::1]:8765":     true,
+		":8765":          false,
+		"0.0.0.0:8765":   false,
+		"192.168.1.5:80": false,
+	} {
+		if got := isLoopbackAddr(addr); got != want {
+			t.Errorf("isLoopbackAddr(%q) = %v, want %v", addr, got, want)
+		}
+	}
+
+	t.Setenv(mcpTokenEnv, "")
+	if err := runMCPServer("sse", "0.0.0.0:8765"); err == nil || !strings.Contains(err.Error(), mcpTokenEnv) {
+		t.Errorf("expected a public address without a token to be refused, got %v", err)
+	}
+}
+
+// sseGoroutines counts the goroutines running in the SSE handler
+func sseGoroutines() int {
+	buf := make([]byte, 1<<20)
+	stacks := string(buf[:runtime.Stack(buf, true)])
+	count := 0
+	for _, stack := range strings.Split(stacks, "\n\n") {
+		if strings.Contains(stack, "SSEHandler.func") {
+			count++
+		}
+	}
+	return count
+}
+
+func TestMCPSSEDropsResponsesAfterDisconnect(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	release := make(chan struct{})
+	env.app.aiAgent.tools.Register(ToolDefinition{
+		Name:        "probe_slow",
+		InputSchema: GenerateSchema[struct{}](),
+		Function: func(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+			<-release
+			return `{"success": true}`, nil
+		},
+	})
+	server := httptest.NewServer(NewMCPServer(env.app).SSEHandler(context.Background(), ""))
+	defer server.Close()
+
+	ctx, disconnect := context.WithCancel(context.Background())
+	defer disconnect()
+	request, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/sse", nil)
+	stream, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := bufio.NewReader(stream.Body)
+	var endpoint string
+	for endpoint == "" {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("no endpoint event: %v", err)
+		}
+		if data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: "); ok {
+			endpoint = data
+		}
+	}
+
+	// More calls than the session buffers answers for, still running when the client leaves
+	for i := 0; i < 40; i++ {
+		body := fmt.Sprintf(`{"jsonrpc": "2.0", "id": %d, "method": "tools/call", "params": {"name": "probe_slow", "arguments": {}}}`, i)
+		response, err := http.Post(server.URL+endpoint, "application/json", strings.NewReader(body))
+		if err != nil || response.StatusCode != http.StatusAccepted {
+			t.Fatalf("call %d not accepted: %v", i, err)
+		}
+		response.Body.Close()
+	}
+	disconnect()
+	stream.Body.Close()
+	close(release)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for sseGoroutines() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines are still waiting to answer a disconnected client", sseGoroutines())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
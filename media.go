@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ExtractMediaDefinition defines the extract_media tool
+var ExtractMediaDefinition = ToolDefinition{
+	Name: "extract_media",
+	Description: `Save the pictures, audio and video embedded in the presentation as files in a folder, and report each file's path, kind, size and the slides it appears on.
+
+Use this to reuse a deck's images elsewhere, or to get an image file to look at or pass to insert_image and replace_image. The folder defaults to '<name> media' next to the presentation; a relative output_dir is resolved against the presentation's folder. Files keep their names inside the deck, e.g. image3.png. The presentation itself is not changed.
+
+slides limits the extraction to media on those slides and kinds to 'image', 'video' or 'audio'. Media only the slide masters or layouts use (logos, backgrounds) is included unless slides is given; it is reported with on_masters.`,
+	InputSchema: ExtractMediaInputSchema,
+	Function:    ExtractMedia,
+	ReadOnly:    true,
+	WritesFiles: true,
+}
+
+type ExtractMediaInput struct {
+	PresentationPath string   `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	OutputDir        string   `json:"output_dir,omitempty" jsonschema_description:"(Optional) Folder to write the files to, defaults to '<name> media' next to the presentation"`
+	Slides           []int    `json:"slides,omitempty" jsonschema_description:"(Optional) Only extract media on these slides (1-based)"`
+	Kinds            []string `json:"kinds,omitempty" jsonschema_description:"(Optional) Kinds of media to extract: 'image', 'video' and/or 'audio'; defaults to all"`
+	Overwrite        bool     `json:"overwrite,omitempty" jsonschema_description:"(Optional) Replace files that already exist in the folder"`
+}
+
+var ExtractMediaInputSchema = GenerateSchema[ExtractMediaInput]()
+
+func ExtractMedia(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	extractInput := ExtractMediaInput{}
+	if err := json.Unmarshal(input, &extractInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+	path, err := pptxDeckPath(app, extractInput.PresentationPath, "embedded media")
+	if err != nil {
+		return "", err
+	}
+	for _, kind := range extractInput.Kinds {
+		if !slices.Contains(mediaKinds, kind) {
+			return "", NewToolError(ErrCodeInvalidInput, "kinds must be among: %s", strings.Join(mediaKinds, ", "))
+		}
+	}
+
+	pkg, err := openPPTX(path)
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to read presentation: %v", err)
+	}
+	defer pkg.Close()
+	for _, slideNumber := range extractInput.Slides {
+		if slideNumber < 1 || slideNumber > pkg.SlideCount() {
+			return "", NewToolError(ErrCodeSlideOutOfRange, "slide %d is not between 1 and %d", slideNumber, pkg.SlideCount())
+		}
+	}
+	all, err := pkg.Media()
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to read media: %v", err)
+	}
+
+	var media []pptxMedia
+	for _, item := range all {
+		if len(extractInput.Kinds) > 0 && !slices.Contains(extractInput.Kinds, item.Kind) {
+			continue
+		}
+		if len(extractInput.Slides) > 0 && !slices.ContainsFunc(item.Slides, func(n int) bool { return slices.Contains(extractInput.Slides, n) }) {
+			continue
+		}
+		media = append(media, item)
+	}
+	if len(media) == 0 {
+		return marshalResult(map[string]interface{}{
+			"files":   []extractedMedia{},
+			"count":   0,
+			"message": "No matching embedded media found",
+		})
+	}
+
+	outputDir := extractInput.OutputDir
+	if outputDir == "" {
+		outputDir = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + " media"
+	}
+	if !filepath.IsAbs(outputDir) {
+		outputDir = filepath.Join(filepath.Dir(path), outputDir)
+	}
+	files, err := extractMedia(pkg, media, filepath.Clean(outputDir), extractInput.Overwrite)
+	if errors.Is(err, errMediaFileExists) {
+		return "", NewToolError(ErrCodeInvalidInput, "%v; set overwrite to replace it or choose another output_dir", err)
+	}
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to extract media: %v", err)
+	}
+
+	return marshalResult(map[string]interface{}{
+		"output_dir": filepath.Clean(outputDir),
+		"files":      files,
+		"count":      len(files),
+	})
+}
+
+// OptimizePresentationDefinition defines the optimize_presentation tool
+var OptimizePresentationDefinition = ToolDefinition{
+	Name: "optimize_presentation",
+	Description: `Make the presentation file smaller so it can be emailed or uploaded: scale down pictures stored at a higher resolution than their frames show, remove slide layouts no slide uses, and drop media and other parts nothing uses any more. Reports the file size before and after, each picture scaled down and each layout removed.
+
+Pictures are scaled to max_dpi pixels per inch of the size they are shown at on the slides (150 by default, enough for projection and screens; use 220 or more for print) and keep their format; JPEG pictures are re-encoded at jpeg_quality. Cropped pictures keep enough pixels for the visible part. Pictures on slide masters and layouts, in shape fills or in groups are left alone, as are formats other than PNG and JPEG. A master keeps at least one layout.
+
+Scaling pictures down can't be undone other than through undo, so set dry_run first to see what would change and how much it saves.`,
+	InputSchema: OptimizePresentationInputSchema,
+	Function:    OptimizePresentation,
+	Mutating:    true,
+	Destructive: true,
+}
+
+type OptimizePresentationInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	MaxDPI           int    `json:"max_dpi,omitempty" jsonschema_description:"(Optional) Pixels per inch of their shown size to scale pictures down to, between 72 and 600; defaults to 150"`
+	JPEGQuality      int    `json:"jpeg_quality,omitempty" jsonschema_description:"(Optional) Quality to re-encode scaled JPEG pictures at, between 30 and 100; defaults to 85"`
+	KeepLayouts      bool   `json:"keep_layouts,omitempty" jsonschema_description:"(Optional) Keep slide layouts no slide uses"`
+	DryRun           bool   `json:"dry_run,omitempty" jsonschema_description:"(Optional) Only report what would change and the resulting size without changing the presentation"`
+}
+
+var OptimizePresentationInputSchema = GenerateSchema[OptimizePresentationInput]()
+
+func OptimizePresentation(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	optimizeInput := OptimizePresentationInput{}
+	if err := json.Unmarshal(input, &optimizeInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+	path, err := pptxDeckPath(app, optimizeInput.PresentationPath, "optimization")
+	if err != nil {
+		return "", err
+	}
+	options := optimizeOptions{
+		MaxDPI:        optimizeInput.MaxDPI,
+		JPEGQuality:   optimizeInput.JPEGQuality,
+		RemoveLayouts: !optimizeInput.KeepLayouts,
+		DryRun:        optimizeInput.DryRun,
+	}
+	if options.MaxDPI == 0 {
+		options.MaxDPI = defaultOptimizeDPI
+	}
+	if options.JPEGQuality == 0 {
+		options.JPEGQuality = defaultOptimizeQuality
+	}
+	if options.MaxDPI < 72 || options.MaxDPI > 600 {
+		return "", NewToolError(ErrCodeInvalidInput, "max_dpi must be between 72 and 600")
+	}
+	if options.JPEGQuality < 30 || options.JPEGQuality > 100 {
+		return "", NewToolError(ErrCodeInvalidInput, "jpeg_quality must be between 30 and 100")
+	}
+
+	result, err := optimizePresentation(path, options)
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to optimize presentation: %v", err)
+	}
+
+	saved := result.SizeBefore - result.SizeAfter
+	verb := "Reduced"
+	if options.DryRun {
+		verb = "Optimizing would reduce"
+	}
+	message := fmt.Sprintf("%s the file from %s to %s: %d picture(s) scaled down, %d unused layout(s) and %d unused part(s) removed",
+		verb, formatBytes(uint64(result.SizeBefore)), formatBytes(uint64(result.SizeAfter)),
+		len(result.Images), len(result.RemovedLayouts), len(result.RemovedParts))
+	if saved <= 0 {
+		message = fmt.Sprintf("Nothing to optimize; the file stays at %s", formatBytes(uint64(result.SizeBefore)))
+	}
+
+	if !options.DryRun && len(result.Images) > 0 {
+		var changed []int
+		for _, image := range result.Images {
+			for _, slide := range image.Slides {
+				if !slices.Contains(changed, slide) {
+					changed = append(changed, slide)
+				}
+			}
+		}
+		slices.Sort(changed)
+		schedulePreviewExport(ctx, app, path, changed...)
+	}
+
+	return marshalResult(map[string]interface{}{
+		"size_before":     result.SizeBefore,
+		"size_after":      result.SizeAfter,
+		"saved_bytes":     max(saved, 0),
+		"images":          result.Images,
+		"removed_layouts": result.RemovedLayouts,
+		"removed_parts":   result.RemovedParts,
+		"dry_run":         options.DryRun,
+		"message":         message,
+	})
+}
+
+// videoExtensions are the video files insert_media accepts; audio files are those in
+// audioExtensions
+var videoExtensions = []string{".mp4", ".m4v", ".mov", ".avi", ".wmv", ".mkv", ".webm", ".mpg", ".mpeg"}
+
+// InsertMediaDefinition defines the insert_media tool
+var InsertMediaDefinition = ToolDefinition{
+	Name: "insert_media",
+	Description: `Place a video (MP4, MOV, WMV, ...) or audio file (MP3, WAV, M4A, ...) on a slide, for requests like "add the demo clip to slide 5".
+
+The file is embedded in the presentation by default, so the deck plays anywhere but grows by the file's size. Set link to only point to the file instead; the deck then stays small but plays the media only where the file is found at the same path.
+
+Position and size are in inches. Video defaults to a 16:9 frame 60% of the slide's width, centered; giving only width or height keeps 16:9. Audio shows as a 1 inch icon, centered unless x and y are given. poster_image sets the picture shown before the video plays. autoplay starts playing as the slide shows rather than on a click; loop repeats the media until the slide ends.`,
+	InputSchema: InsertMediaInputSchema,
+	Function:    InsertMedia,
+	Mutating:    true,
+	Screenshot:  true,
+}
+
+type InsertMediaInput struct {
+	PresentationPath string   `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int      `json:"slide_number" jsonschema_description:"Slide to insert the media on (1-based indexing)"`
+	MediaPath        string   `json:"media_path" jsonschema_description:"Path to the video or audio file to insert"`
+	X                *float64 `json:"x,omitempty" jsonschema_description:"(Optional) Left position in inches"`
+	Y                *float64 `json:"y,omitempty" jsonschema_description:"(Optional) Top position in inches"`
+	Width            *float64 `json:"width,omitempty" jsonschema_description:"(Optional) Width in inches"`
+	Height           *float64 `json:"height,omitempty" jsonschema_description:"(Optional) Height in inches"`
+	Link             bool     `json:"link,omitempty" jsonschema_description:"(Optional) Link to the file instead of embedding it in the presentation"`
+	PosterImage      string   `json:"poster_image,omitempty" jsonschema_description:"(Optional) Path to an image shown before the video plays"`
+	Autoplay         bool     `json:"autoplay,omitempty" jsonschema_description:"(Optional) Start playing when the slide shows instead of on a click"`
+	Loop             bool     `json:"loop,omitempty" jsonschema_description:"(Optional) Repeat the media until the slide ends"`
+}
+
+var InsertMediaInputSchema = GenerateSchema[InsertMediaInput]()
+
+func InsertMedia(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	mediaInput := InsertMediaInput{}
+	if err := json.Unmarshal(input, &mediaInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if mediaInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			mediaInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+	if _, err := os.Stat(mediaInput.PresentationPath); os.IsNotExist(err) {
+		return "", NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", mediaInput.PresentationPath)
+	}
+
+	if mediaInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if mediaInput.MediaPath == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "media_path is required")
+	}
+	if (mediaInput.Width != nil && *mediaInput.Width <= 0) || (mediaInput.Height != nil && *mediaInput.Height <= 0) {
+		return "", NewToolError(ErrCodeInvalidInput, "width and height must be greater than 0")
+	}
+
+	var kind string
+	switch ext := strings.ToLower(filepath.Ext(mediaInput.MediaPath)); {
+	case slices.Contains(videoExtensions, ext):
+		kind = "video"
+	case slices.Contains(audioExtensions, ext):
+		kind = "audio"
+	default:
+		return "", NewToolError(ErrCodeInvalidInput, "media_path must be a video (%s) or audio file (%s)",
+			strings.Join(videoExtensions, ", "), strings.Join(audioExtensions, ", "))
+	}
+
+	// The script runs in its own working directory, so hand it absolute paths
+	mediaPath, err := existingFile(mediaInput.MediaPath, "media")
+	if err != nil {
+		return "", err
+	}
+	posterPath := ""
+	if mediaInput.PosterImage != "" {
+		if posterPath, err = existingFile(mediaInput.PosterImage, "poster image"); err != nil {
+			return "", err
+		}
+	}
+
+	settings, err := json.Marshal(map[string]interface{}{
+		"x":            mediaInput.X,
+		"y":            mediaInput.Y,
+		"width":        mediaInput.Width,
+		"height":       mediaInput.Height,
+		"link":         mediaInput.Link,
+		"poster_image": posterPath,
+		"autoplay":     mediaInput.Autoplay,
+		"loop":         mediaInput.Loop,
+	})
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to encode settings: %v", err)
+	}
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_insert_media.py", mediaInput.PresentationPath,
+		fmt.Sprintf("%d", mediaInput.SlideNumber), mediaPath, kind, string(settings))
+	if err != nil {
+		return "", scriptError("failed to insert media", err, output)
+	}
+
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, mediaInput.PresentationPath, mediaInput.SlideNumber)
+
+	return marshalResult(result)
+}
+
+// existingFile returns the absolute path of a file the user named, or a file-not-found
+// error describing it as what
+func existingFile(name, what string) (string, error) {
+	path, err := filepath.Abs(name)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "invalid %s path: %v", what, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return "", NewToolError(ErrCodeFileNotFound, "%s file not found: %s", what, name).
+			WithDetail("path", path)
+	}
+	return path, nil
+}
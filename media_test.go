@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractMediaTool(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "mixed_shapes.pptx")
+
+	result := env.app.aiAgent.executeTool(context.Background(), "toolu_1", "extract_media", []byte(`{}`))
+	text := result.OfToolResult.Content[0].OfText.Text
+	if result.OfToolResult.IsError.Value {
+		t.Fatalf("extract_media failed: %s", text)
+	}
+	var decoded struct {
+		Data struct {
+			OutputDir string           `json:"output_dir"`
+			Files     []extractedMedia `json:"files"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	wantPath := filepath.Join(filepath.Dir(path), name+" media", "image1.png")
+	files := decoded.Data.Files
+	if len(files) != 1 || files[0].Path != wantPath || files[0].Kind != "image" || len(files[0].Slides) != 1 || files[0].Slides[0] != 1 {
+		t.Fatalf("unexpected files: %+v", files)
+	}
+	pkg, err := openPPTX(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original, _ := pkg.readPart("ppt/media/image1.png")
+	pkg.Close()
+	if written, err := os.ReadFile(wantPath); err != nil || !bytes.Equal(written, original) {
+		t.Errorf("expected the image's bytes in %s (%v)", wantPath, err)
+	}
+
+	result = env.app.aiAgent.executeTool(context.Background(), "toolu_2", "extract_media", []byte(`{}`))
+	if !result.OfToolResult.IsError.Value || !strings.Contains(result.OfToolResult.Content[0].OfText.Text, "already exists") {
+		t.Errorf("expected existing files to be kept, got %s", result.OfToolResult.Content[0].OfText.Text)
+	}
+	result = env.app.aiAgent.executeTool(context.Background(), "toolu_3", "extract_media", []byte(`{"overwrite": true}`))
+	if result.OfToolResult.IsError.Value {
+		t.Errorf("expected overwrite to replace the files, got %s", result.OfToolResult.Content[0].OfText.Text)
+	}
+	result = env.app.aiAgent.executeTool(context.Background(), "toolu_4", "extract_media", []byte(`{"kinds": ["video"], "output_dir": "videos"}`))
+	if text := result.OfToolResult.Content[0].OfText.Text; result.OfToolResult.IsError.Value || !strings.Contains(text, "No matching") {
+		t.Errorf("expected no videos, got %s", text)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(path), "videos")); !os.IsNotExist(err) {
+		t.Error("no folder should be created when nothing matches")
+	}
+	result = env.app.aiAgent.executeTool(context.Background(), "toolu_5", "extract_media", []byte(`{"kinds": ["gif"]}`))
+	if !result.OfToolResult.IsError.Value || !strings.Contains(result.OfToolResult.Content[0].OfText.Text, string(ErrCodeInvalidInput)) {
+		t.Errorf("expected an unknown kind to be refused, got %s", result.OfToolResult.Content[0].OfText.Text)
+	}
+}
+
+func TestMediaOnMasters(t *testing.T) {
+	pkg, err := openPPTX(filepath.Join("testdata", "template.potx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pkg.Close()
+	media, err := pkg.Media()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(media) != 1 || media[0].Part != "ppt/media/image1.png" || !media[0].OnMasters || len(media[0].Slides) != 0 {
+		t.Errorf("expected the master's image, got %+v", media)
+	}
+}
+
+func TestOptimizePresentationTool(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "mixed_shapes.pptx")
+	// The picture's frame is 2in x 1in, so 150 DPI needs 300x150 pixels
+	rewritePart(t, path, "ppt/media/image1.png", func([]byte) []byte {
+		img := image.NewRGBA(image.Rect(0, 0, 1200, 600))
+		for y := 0; y < 600; y++ {
+			for x := 0; x < 1200; x++ {
+				img.Set(x, y, color.RGBA{uint8(x * 7), uint8(y * 13), uint8(x ^ y), 255})
+			}
+		}
+		var buf bytes.Buffer
+		png.Encode(&buf, img)
+		return buf.Bytes()
+	})
+	env.app.setPresentationPath(path)
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded struct {
+		Data struct {
+			SizeBefore int64            `json:"size_before"`
+			SizeAfter  int64            `json:"size_after"`
+			Images     []optimizedImage `json:"images"`
+		} `json:"data"`
+	}
+	result := env.app.aiAgent.executeTool(context.Background(), "toolu_1", "optimize_presentation", []byte(`{"dry_run": true}`))
+	text := result.OfToolResult.Content[0].OfText.Text
+	if result.OfToolResult.IsError.Value {
+		t.Fatalf("optimize_presentation failed: %s", text)
+	}
+	if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Data.SizeBefore != int64(len(before)) || decoded.Data.SizeAfter >= decoded.Data.SizeBefore || len(decoded.Data.Images) != 1 {
+		t.Errorf("expected the dry run to report a smaller file, got %s", text)
+	}
+	if unchanged, _ := os.ReadFile(path); !bytes.Equal(unchanged, before) {
+		t.Error("a dry run must not change the presentation")
+	}
+
+	result = env.app.aiAgent.executeTool(context.Background(), "toolu_2", "optimize_presentation", []byte(`{}`))
+	text = result.OfToolResult.Content[0].OfText.Text
+	if result.OfToolResult.IsError.Value {
+		t.Fatalf("optimize_presentation failed: %s", text)
+	}
+	if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	images := decoded.Data.Images
+	if len(images) != 1 || images[0].Part != "ppt/media/image1.png" || images[0].NewWidth != 300 || images[0].NewHeight != 150 || len(images[0].Slides) != 1 {
+		t.Fatalf("unexpected images: %+v", images)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != decoded.Data.SizeAfter || info.Size() >= int64(len(before)) {
+		t.Errorf("expected the file to shrink to the reported size (%v)", err)
+	}
+	pkg, err := openPPTX(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pkg.Close()
+	data, _ := pkg.readPart("ppt/media/image1.png")
+	if config, err := png.DecodeConfig(bytes.NewReader(data)); err != nil || config.Width != 300 || config.Height != 150 {
+		t.Errorf("expected a 300x150 PNG, got %+v (%v)", config, err)
+	}
+
+	result = env.app.aiAgent.executeTool(context.Background(), "toolu_3", "optimize_presentation", []byte(`{"max_dpi": 1000}`))
+	if !result.OfToolResult.IsError.Value || !strings.Contains(result.OfToolResult.Content[0].OfText.Text, string(ErrCodeInvalidInput)) {
+		t.Errorf("expected an out of range max_dpi to be refused, got %s", result.OfToolResult.Content[0].OfText.Text)
+	}
+}
+
+func TestOptimizeRemovesUnusedLayouts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "template.pptx")
+	if err := copyFile(filepath.Join("testdata", "template.potx"), path); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := optimizePresentation(path, optimizeOptions{MaxDPI: defaultOptimizeDPI, JPEGQuality: defaultOptimizeQuality, RemoveLayouts: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without slides every layout is unused, but the master keeps its first one
+	if len(result.RemovedLayouts) != 1 || len(result.Images) != 0 {
+		t.Errorf("expected one layout removed and the master's logo left alone, got %+v", result)
+	}
+	if len(result.RemovedParts) != 1 || result.RemovedParts[0] != "ppt/slideLayouts/slideLayout2.xml" {
+		t.Errorf("unexpected removed parts: %v", result.RemovedParts)
+	}
+
+	pkg, err := openPPTX(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pkg.Close()
+	layouts, err := pkg.Layouts()
+	if err != nil || len(layouts) != 1 {
+		t.Fatalf("expected one layout left, got %+v (%v)", layouts, err)
+	}
+	if _, ok := lookupContentType(t, pkg, "ppt/slideLayouts/slideLayout2.xml"); ok {
+		t.Error("expected the removed layout's content type to go")
+	}
+	if _, ok := pkg.parts["ppt/media/image1.png"]; !ok {
+		t.Error("expected the master's logo to stay")
+	}
+}
+
+func TestInsertMedia(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_insert_media.py", `{"success": true, "slide_number": 2, "shape_index": 3, "shape_id": "Video 1", "linked": false, "poster_applied": true}`)
+	dir := t.TempDir()
+	clipPath := filepath.Join(dir, "demo.MP4")
+	posterPath := filepath.Join(dir, "poster.png")
+	for _, path := range []string{clipPath, posterPath} {
+		if err := os.WriteFile(path, []byte("media"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	input, _ := json.Marshal(map[string]interface{}{"slide_number": 2, "media_path": clipPath, "poster_image": posterPath, "width": 6, "autoplay": true})
+	output, err := InsertMedia(context.Background(), env.app, input)
+	if err != nil {
+		t.Fatalf("InsertMedia failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_insert_media.py")
+	if len(calls) != 1 || calls[0].Args[1] != "2" || calls[0].Args[2] != clipPath || calls[0].Args[3] != "video" {
+		t.Fatalf("expected the clip to be inserted as video on slide 2, got %+v", calls)
+	}
+	var settings map[string]interface{}
+	if err := json.Unmarshal([]byte(calls[0].Args[4]), &settings); err != nil {
+		t.Fatal(err)
+	}
+	if settings["poster_image"] != posterPath || settings["autoplay"] != true || settings["width"] != 6.0 || settings["x"] != nil {
+		t.Errorf("unexpected settings: %s", calls[0].Args[4])
+	}
+	var result EditResult
+	if err := json.Unmarshal([]byte(output), &result); err != nil || result.ShapeID != "Video 1" {
+		t.Errorf("expected the media's shape_id, got %s (%v)", output, err)
+	}
+
+	for input, want := range map[string]ToolErrorCode{
+		`{"slide_number": 1, "media_path": "` + posterPath + `"}`:                              ErrCodeInvalidInput,
+		`{"slide_number": 1, "media_path": "` + filepath.Join(dir, "missing.mp3") + `"}`:       ErrCodeFileNotFound,
+		`{"slide_number": 1, "media_path": "` + clipPath + `", "poster_image": "missing.png"}`: ErrCodeFileNotFound,
+		`{"slide_number": 1, "media_path": "` + clipPath + `", "height": 0}`:                   ErrCodeInvalidInput,
+	} {
+		if _, err := InsertMedia(context.Background(), env.app, json.RawMessage(input)); toolErrorCode(err) != want {
+			t.Errorf("expected %s for %s, got %v", want, input, err)
+		}
+	}
+	if calls := env.uno.Calls("uno_insert_media.py"); len(calls) != 1 {
+		t.Errorf("script should only run for valid input, got %d calls", len(calls))
+	}
+}
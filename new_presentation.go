@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// slideSizes are the slide sizes new presentations can have, in inches, matching
+// PowerPoint's presets
+var slideSizes = map[string][2]float64{
+	"16:9":  {13.333, 7.5},
+	"4:3":   {10, 7.5},
+	"16:10": {10, 6.25},
+	"a4":    {10.833, 7.5},
+}
+
+// defaultAspectRatio is the slide size of new presentations unless another is asked for
+const defaultAspectRatio = "16:9"
+
+// CreatePresentationDefinition defines the create_presentation tool
+var CreatePresentationDefinition = ToolDefinition{
+	Name: "create_presentation",
+	Description: `Create a new presentation with a single slide and open it, so the following tools work on it. Use this when the user wants a new deck rather than changes to the loaded one.
+
+The slide size follows aspect_ratio (16:9 by default, or 4:3, 16:10, a4). With template_path, the template's masters, layouts and theme are applied, so the new slides look like the template. With title, the first slide is a title slide showing it; otherwise it is blank. Add the other slides with add_slide afterwards. An existing file is never overwritten.`,
+	InputSchema: CreatePresentationInputSchema,
+	Function:    CreatePresentation,
+	Timeout:     2 * time.Minute,
+}
+
+type CreatePresentationInput struct {
+	OutputPath   string `json:"output_path" jsonschema_description:"Where to create the .pptx file. A relative path is resolved against the loaded presentation's folder, or the user's Documents folder when none is loaded"`
+	AspectRatio  string `json:"aspect_ratio,omitempty" jsonschema_description:"Slide size: 16:9 (default), 4:3, 16:10 or a4"`
+	TemplatePath string `json:"template_path,omitempty" jsonschema_description:"Optional .potx or .pptx template whose masters, layouts and theme to use"`
+	Title        string `json:"title,omitempty" jsonschema_description:"Optional title of the first slide"`
+}
+
+var CreatePresentationInputSchema = GenerateSchema[CreatePresentationInput]()
+
+func CreatePresentation(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	createInput := CreatePresentationInput{}
+	if err := json.Unmarshal(input, &createInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	outputPath, err := createPresentationFile(ctx, app, createInput)
+	if err != nil {
+		return "", err
+	}
+	slides, err := app.LoadPresentation(outputPath)
+	if err != nil {
+		return "", NewToolError(toolErrorCodeOr(err, ErrCodeExportFailed), "created %s but failed to open it: %v", outputPath, err).
+			WithDetail("presentation_path", outputPath)
+	}
+
+	return marshalResult(map[string]interface{}{
+		"presentation_path": outputPath,
+		"aspect_ratio":      normalizedAspectRatio(createInput.AspectRatio),
+		"total_slides":      len(slides),
+		"template":          createInput.TemplatePath,
+		"message":           fmt.Sprintf("Created %s and opened it; further tools work on it by default", filepath.Base(outputPath)),
+	})
+}
+
+// createPresentationFile creates a one-slide .pptx as the input describes and returns its
+// absolute path. Nothing is left behind when a step fails.
+func createPresentationFile(ctx context.Context, app *App, input CreatePresentationInput) (string, error) {
+	aspectRatio := normalizedAspectRatio(input.AspectRatio)
+	size, ok := slideSizes[aspectRatio]
+	if !ok {
+		ratios := make([]string, 0, len(slideSizes))
+		for ratio := range slideSizes {
+			ratios = append(ratios, ratio)
+		}
+		sort.Strings(ratios)
+		return "", NewToolError(ErrCodeInvalidInput, "unsupported aspect_ratio %q (use %s)", input.AspectRatio, strings.Join(ratios, ", "))
+	}
+
+	outputPath, err := newPresentationPath(app, input.OutputPath)
+	if err != nil {
+		return "", err
+	}
+
+	var templatePath string
+	if input.TemplatePath != "" {
+		if templatePath, err = filepath.Abs(input.TemplatePath); err != nil {
+			return "", NewToolError(ErrCodeInvalidInput, "invalid template_path: %v", err)
+		}
+		if ext := strings.ToLower(filepath.Ext(templatePath)); ext != ".potx" && ext != ".pptx" {
+			return "", NewToolError(ErrCodeInvalidInput, "template_path must be a .potx or .pptx file")
+		}
+		if _, err := os.Stat(templatePath); err != nil {
+			return "", NewToolError(ErrCodeFileNotFound, "template file not found: %s", input.TemplatePath).
+				WithDetail("template_path", templatePath)
+		}
+	}
+
+	output, err := runUnoScript(ctx, app, "uno_create_presentation.py", outputPath,
+		strconv.FormatFloat(size[0], 'f', -1, 64), strconv.FormatFloat(size[1], 'f', -1, 64), input.Title)
+	if err != nil {
+		os.Remove(outputPath)
+		return "", scriptError("failed to create presentation", err, output)
+	}
+
+	if templatePath != "" {
+		if _, err := applyTemplate(outputPath, templatePath); err != nil {
+			os.Remove(outputPath)
+			return "", NewToolError(ErrCodeInternal, "failed to apply template: %v", err)
+		}
+	}
+	return outputPath, nil
+}
+
+// normalizedAspectRatio returns an aspect ratio as slideSizes names it
+func normalizedAspectRatio(aspectRatio string) string {
+	aspectRatio = strings.ToLower(strings.TrimSpace(aspectRatio))
+	if aspectRatio == "" {
+		return defaultAspectRatio
+	}
+	return strings.ReplaceAll(aspectRatio, "x", ":")
+}
+
+// newPresentationPath resolves where a new presentation goes: a relative path is taken
+// against the loaded presentation's folder, or the Documents folder when none is loaded.
+// .pptx is added when the path has no extension, and existing files are refused.
+func newPresentationPath(app *App, outputPath string) (string, error) {
+	if strings.TrimSpace(outputPath) == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "output_path is required")
+	}
+	switch strings.ToLower(filepath.Ext(outputPath)) {
+	case "":
+		outputPath += ".pptx"
+	case ".pptx":
+	default:
+		return "", NewToolError(ErrCodeInvalidInput, "output_path must end in .pptx")
+	}
+
+	if !filepath.IsAbs(outputPath) {
+		base := documentsDir()
+		if current := app.presentationPath(); current != "" {
+			base = filepath.Dir(current)
+		}
+		outputPath = filepath.Join(base, outputPath)
+	}
+	outputPath = filepath.Clean(outputPath)
+
+	if _, err := os.Stat(outputPath); err == nil {
+		return "", NewToolError(ErrCodeInvalidInput, "%s already exists; choose another output_path", outputPath).
+			WithDetail("output_path", outputPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to create output folder: %v", err)
+	}
+	return outputPath, nil
+}
+
+// documentsDir is where new presentations go by default: the user's Documents folder,
+// falling back to the home and then the working directory
+func documentsDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	if info, err := os.Stat(filepath.Join(home, "Documents")); err == nil && info.IsDir() {
+		return filepath.Join(home, "Documents")
+	}
+	return home
+}
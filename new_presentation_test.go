@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// handleCreatePresentation makes uno_create_presentation.py write the two-slide fixture
+func handleCreatePresentation(bridge *FakeUnoBridge) {
+	bridge.Handle("uno_create_presentation.py", func(args []string) ([]byte, error) {
+		if err := copyFile(filepath.Join(fixtureDir, "two_slides.pptx"), args[0]); err != nil {
+			return []byte(fmt.Sprintf(`{"success": false, "error": %q}`, err.Error())), fmt.Errorf("exit status 1")
+		}
+		return []byte(fmt.Sprintf(`{"success": true, "output_path": %q, "slide_count": 1}`, args[0])), nil
+	})
+}
+
+func TestCreatePresentationOpensTheNewDeck(t *testing.T) {
+	env := newTestEnv(t)
+	handleCreatePresentation(env.uno)
+	output := filepath.Join(env.dir, "decks", "Pitch")
+
+	result := env.app.aiAgent.executeTool(context.Background(), "toolu_1", "create_presentation",
+		[]byte(fmt.Sprintf(`{"output_path": %q, "aspect_ratio": "4:3", "title": "Q3 Review", "template_path": %q}`,
+			output, filepath.Join(fixtureDir, "template.potx"))))
+	if result.OfToolResult.IsError.Value {
+		t.Fatalf("create_presentation failed: %s", result.OfToolResult.Content[0].OfText.Text)
+	}
+
+	created := output + ".pptx"
+	if env.app.presentationPath() != created {
+		t.Fatalf("expected %s to be loaded, got %q", created, env.app.presentationPath())
+	}
+	calls := env.uno.Calls("uno_create_presentation.py")
+	if len(calls) != 1 || strings.Join(calls[0].Args[1:], " ") != "10 7.5 Q3 Review" {
+		t.Errorf("expected a 4:3 slide titled Q3 Review, got %v", calls)
+	}
+
+	pkg, err := openPPTX(created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pkg.Close()
+	layouts, err := pkg.Layouts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layouts) != 2 {
+		t.Errorf("expected the template's two layouts, got %d", len(layouts))
+	}
+
+	// Relative paths land next to the loaded presentation
+	result = env.app.aiAgent.executeTool(context.Background(), "toolu_2", "create_presentation", []byte(`{"output_path": "Appendix.pptx"}`))
+	if result.OfToolResult.IsError.Value {
+		t.Fatalf("create_presentation failed: %s", result.OfToolResult.Content[0].OfText.Text)
+	}
+	if appendix := filepath.Join(env.dir, "decks", "Appendix.pptx"); env.app.presentationPath() != appendix {
+		t.Errorf("expected %s to be loaded, got %q", appendix, env.app.presentationPath())
+	}
+	if calls := env.uno.Calls("uno_create_presentation.py"); strings.Join(calls[1].Args[1:3], "x") != "13.333x7.5" {
+		t.Errorf("expected a 16:9 slide by default, got %v", calls[1].Args)
+	}
+}
+
+func TestCreatePresentationRefusesBadInput(t *testing.T) {
+	env := newTestEnv(t)
+	handleCreatePresentation(env.uno)
+	existing := env.loadFixture(t, "two_slides.pptx")
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{fmt.Sprintf(`{"output_path": %q}`, existing), "already exists"},
+		{`{"output_path": "Deck.odp"}`, "must end in .pptx"},
+		{`{"output_path": "Deck.pptx", "aspect_ratio": "21:9"}`, "unsupported aspect_ratio"},
+		{`{"output_path": "Deck.pptx", "template_path": "missing.potx"}`, string(ErrCodeFileNotFound)},
+		{`{}`, "output_path is required"},
+	}
+	for _, tt := range tests {
+		result := env.app.aiAgent.executeTool(context.Background(), "toolu_1", "create_presentation", []byte(tt.input))
+		if content := result.OfToolResult.Content[0].OfText.Text; !result.OfToolResult.IsError.Value || !strings.Contains(content, tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %s", tt.input, tt.want, content)
+		}
+	}
+	if calls := env.uno.Calls("uno_create_presentation.py"); len(calls) != 0 {
+		t.Errorf("expected no presentation to be created, got %v", calls)
+	}
+
+	// A failed script leaves nothing behind
+	env.uno.Handle("uno_create_presentation.py", func(args []string) ([]byte, error) {
+		os.WriteFile(args[0], []byte("half-written"), 0644)
+		return []byte(`{"success": false, "error": "Error creating presentation: disk full"}`), fmt.Errorf("exit status 1")
+	})
+	result := env.app.aiAgent.executeTool(context.Background(), "toolu_1", "create_presentation", []byte(`{"output_path": "New.pptx"}`))
+	if !result.OfToolResult.IsError.Value {
+		t.Fatal("expected an error result")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(existing), "New.pptx")); !os.IsNotExist(err) {
+		t.Errorf("expected the half-written file to be removed, got %v", err)
+	}
+	if env.app.presentationPath() != existing {
+		t.Errorf("expected %s to stay loaded, got %s", existing, env.app.presentationPath())
+	}
+}
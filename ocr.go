@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// tesseractEnv names the tesseract executable to run, defaulting to "tesseract" on the PATH
+const tesseractEnv = "SLIDEPILOT_TESSERACT"
+
+// ocrDPI is the resolution slides are rendered at for OCR; tesseract reads small text
+// best at around 300 pixels per inch
+const ocrDPI = 300
+
+// ocrLanguagePattern matches tesseract language codes such as "eng" or "eng+deu"
+var ocrLanguagePattern = regexp.MustCompile(`^[A-Za-z_]+(\+[A-Za-z_]+)*$`)
+
+// OCRSlideDefinition defines the ocr_slide tool
+var OCRSlideDefinition = ToolDefinition{
+	Name: "ocr_slide",
+	Description: `Read the text shown in a slide's pictures with OCR: the slide is rendered as an image and run through tesseract, and the recognized lines are returned with their confidence and position on the slide in inches.
+
+Use this on slides that are screenshots or scans, where read_slide finds pictures but little or no text, to see what they say before rebuilding them as editable text. The result includes the slide's real text too, since the whole rendered slide is read.
+
+language takes tesseract language codes, e.g. 'deu' or 'eng+fra' (defaults to 'eng'); the language data must be installed. Words recognized with less than min_confidence (0-100) are left out. Needs tesseract installed, or SLIDEPILOT_TESSERACT pointing at it.`,
+	InputSchema: OCRSlideInputSchema,
+	Function:    OCRSlide,
+	ReadOnly:    true,
+}
+
+type OCRSlideInput struct {
+	PresentationPath string  `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int     `json:"slide_number" jsonschema_description:"Slide to read (1-based)"`
+	Language         string  `json:"language,omitempty" jsonschema_description:"(Optional) Tesseract language code(s), e.g. 'eng' or 'eng+deu'; defaults to 'eng'"`
+	MinConfidence    float64 `json:"min_confidence,omitempty" jsonschema_description:"(Optional) Leave out words recognized with less than this confidence, 0-100"`
+}
+
+var OCRSlideInputSchema = GenerateSchema[OCRSlideInput]()
+
+// OCRLine is one line of recognized text; positions are in inches from the slide's top left
+type OCRLine struct {
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"`
+	X          float64 `json:"x"`
+	Y          float64 `json:"y"`
+	Width      float64 `json:"width"`
+	Height     float64 `json:"height"`
+	Paragraph  int     `json:"paragraph"`
+}
+
+// OCRResult is the text recognized on a slide
+type OCRResult struct {
+	SlideNumber int       `json:"slide_number"`
+	Language    string    `json:"language"`
+	Text        string    `json:"text"`
+	WordCount   int       `json:"word_count"`
+	Confidence  float64   `json:"confidence"`
+	Lines       []OCRLine `json:"lines"`
+	Message     string    `json:"message,omitempty"`
+}
+
+func OCRSlide(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	ocrInput := OCRSlideInput{}
+	if err := json.Unmarshal(input, &ocrInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if ocrInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			ocrInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+	if _, err := os.Stat(ocrInput.PresentationPath); err != nil {
+		return "", NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", ocrInput.PresentationPath)
+	}
+	if ocrInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeSlideOutOfRange, "slide_number must be 1 or greater")
+	}
+	if ocrInput.Language == "" {
+		ocrInput.Language = "eng"
+	}
+	if !ocrLanguagePattern.MatchString(ocrInput.Language) {
+		return "", NewToolError(ErrCodeInvalidInput, "language must be tesseract language codes such as 'eng' or 'eng+deu', got %q", ocrInput.Language)
+	}
+	if ocrInput.MinConfidence < 0 || ocrInput.MinConfidence > 100 {
+		return "", NewToolError(ErrCodeInvalidInput, "min_confidence must be between 0 and 100")
+	}
+
+	renderDir, err := os.MkdirTemp(sessionTempDir(), "ocr-")
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(renderDir)
+
+	options := ExportOptions{Format: "png", DPI: ocrDPI}.WithDefaults()
+	index := ocrInput.SlideNumber - 1
+	images, err := exportSlideImagesWith(ctx, app, ocrInput.PresentationPath, renderDir, index, index, options)
+	if err != nil {
+		return "", NewToolError(toolErrorCodeOr(err, ErrCodeExportFailed), "failed to render slide %d: %v", ocrInput.SlideNumber, err)
+	}
+	imagePath := slideImagePath(renderDir, index, options.Extension())
+	if !slices.Contains(images, imagePath) {
+		return "", NewToolError(ErrCodeSlideOutOfRange, "slide %d is not in the presentation", ocrInput.SlideNumber)
+	}
+
+	fmt.Printf("Running OCR on slide %d of: %s\n", ocrInput.SlideNumber, ocrInput.PresentationPath)
+
+	tsv, err := runTesseract(ctx, imagePath, ocrInput.Language)
+	if err != nil {
+		return "", err
+	}
+	result := parseTesseractTSV(tsv, ocrDPI, ocrInput.MinConfidence)
+	result.SlideNumber = ocrInput.SlideNumber
+	result.Language = ocrInput.Language
+	if result.WordCount == 0 {
+		result.Message = "No text was recognized on the slide"
+	}
+	return marshalResult(result)
+}
+
+// runTesseract runs tesseract over an image and returns its TSV output
+func runTesseract(ctx context.Context, imagePath, language string) ([]byte, error) {
+	command := os.Getenv(tesseractEnv)
+	if command == "" {
+		command = "tesseract"
+	}
+
+	cmd := exec.CommandContext(ctx, command, imagePath, "stdout", "-l", language, "tsv")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, NewToolError(ErrCodeProviderError, "OCR requires tesseract; install it or set %s to its path", tesseractEnv)
+		}
+		if ctx.Err() != nil {
+			return nil, NewToolError(ErrCodeCancelled, "OCR was cancelled: %v", ctx.Err())
+		}
+		return nil, NewToolError(ErrCodeProviderError, "tesseract failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// parseTesseractTSV groups the words of tesseract's TSV output into lines, converting
+// pixel boxes at the given DPI to inches. Words below minConfidence are dropped.
+func parseTesseractTSV(tsv []byte, dpi int, minConfidence float64) OCRResult {
+	type lineKey struct{ block, paragraph, line int }
+	type box struct{ left, top, right, bottom int }
+
+	var keys []lineKey
+	words := map[lineKey][]string{}
+	confidence := map[lineKey]float64{}
+	boxes := map[lineKey]box{}
+	var total float64
+	var count int
+
+	for i, row := range strings.Split(string(tsv), "\n") {
+		fields := strings.Split(strings.TrimRight(row, "\r"), "\t")
+		// Columns: level page_num block_num par_num line_num word_num left top width height conf text
+		if i == 0 || len(fields) < 12 || fields[0] != "5" {
+			continue
+		}
+		text := strings.TrimSpace(fields[11])
+		conf, err := strconv.ParseFloat(fields[10], 64)
+		if text == "" || err != nil || conf < 0 || conf < minConfidence {
+			continue
+		}
+		var n [8]int
+		for j := range n {
+			n[j], _ = strconv.Atoi(fields[j+2])
+		}
+		key := lineKey{n[0], n[1], n[2]}
+		left, top, width, height := n[4], n[5], n[6], n[7]
+		if b, ok := boxes[key]; ok {
+			boxes[key] = box{min(b.left, left), min(b.top, top), max(b.right, left+width), max(b.bottom, top+height)}
+		} else {
+			keys = append(keys, key)
+			boxes[key] = box{left, top, left + width, top + height}
+		}
+		words[key] = append(words[key], text)
+		confidence[key] += conf
+		total += conf
+		count++
+	}
+
+	toInches := func(pixels int) float64 {
+		return math.Round(float64(pixels)/float64(dpi)*100) / 100
+	}
+	result := OCRResult{Lines: []OCRLine{}, WordCount: count}
+	var text strings.Builder
+	paragraph := 0
+	for i, key := range keys {
+		if i == 0 || key.block != keys[i-1].block || key.paragraph != keys[i-1].paragraph {
+			paragraph++
+			if i > 0 {
+				text.WriteString("\n")
+			}
+		}
+		line := strings.Join(words[key], " ")
+		text.WriteString(line + "\n")
+		b := boxes[key]
+		result.Lines = append(result.Lines, OCRLine{
+			Text:       line,
+			Confidence: math.Round(confidence[key]/float64(len(words[key]))*10) / 10,
+			X:          toInches(b.left),
+			Y:          toInches(b.top),
+			Width:      toInches(b.right - b.left),
+			Height:     toInches(b.bottom - b.top),
+			Paragraph:  paragraph,
+		})
+	}
+	result.Text = strings.TrimSuffix(text.String(), "\n")
+	if count > 0 {
+		result.Confidence = math.Round(total/float64(count)*10) / 10
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// tesseractTSV is tesseract's TSV output for a slide with a two-line heading and a caption
+const tesseractTSV = "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n" +
+	"1\t1\t0\t0\t0\t0\t0\t0\t4000\t2250\t-1\t\n" +
+	"4\t1\t1\t1\t1\t0\t300\t300\t1200\t150\t-1\t\n" +
+	"5\t1\t1\t1\t1\t1\t300\t300\t600\t150\t96.5\tQuarterly\n" +
+	"5\t1\t1\t1\t1\t2\t960\t300\t540\t150\t93.5\tResults\n" +
+	"5\t1\t1\t1\t2\t1\t300\t480\t450\t120\t91\t2024\n" +
+	"5\t1\t2\t1\t1\t1\t300\t1800\t300\t60\t88\tSource:\n" +
+	"5\t1\t2\t1\t1\t2\t630\t1800\t60\t60\t12\t~\n"
+
+func TestOCRSlide(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+
+	// A stub tesseract that records its arguments and prints canned TSV
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+	if err := os.WriteFile(filepath.Join(dir, "tsv"), []byte(tesseractTSV), 0644); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\ncat " + filepath.Join(dir, "tsv") + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "tesseract"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(tesseractEnv, filepath.Join(dir, "tesseract"))
+
+	output, err := OCRSlide(context.Background(), env.app, json.RawMessage(`{"slide_number": 2, "language": "eng+deu", "min_confidence": 50}`))
+	if err != nil {
+		t.Fatalf("OCRSlide failed: %v", err)
+	}
+	if exports := env.converter.Exports; len(exports) != 1 || exports[0].Format != "png" || exports[0].DPI != ocrDPI {
+		t.Errorf("expected the slide rendered as a %d DPI PNG, got %+v", ocrDPI, exports)
+	}
+	args, _ := os.ReadFile(argsFile)
+	if !strings.Contains(string(args), "slide-001.png stdout -l eng+deu tsv") {
+		t.Errorf("unexpected tesseract arguments: %s", args)
+	}
+
+	var result OCRResult
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Text != "Quarterly Results\n2024\n\nSource:" || result.WordCount != 4 {
+		t.Errorf("unexpected text without the low-confidence word: %q (%d words)", result.Text, result.WordCount)
+	}
+	if len(result.Lines) != 3 {
+		t.Fatalf("expected three lines, got %+v", result.Lines)
+	}
+	if first := result.Lines[0]; first.X != 1 || first.Y != 1 || first.Width != 4 || first.Height != 0.5 || first.Confidence != 95 {
+		t.Errorf("expected the heading's box in inches, got %+v", first)
+	}
+	if result.Lines[2].Paragraph != 2 {
+		t.Errorf("expected the caption in its own paragraph, got %+v", result.Lines[2])
+	}
+
+	for input, want := range map[string]ToolErrorCode{
+		`{"slide_number": 3}`:                        ErrCodeSlideOutOfRange,
+		`{"slide_number": 0}`:                        ErrCodeSlideOutOfRange,
+		`{"slide_number": 1, "language": "eng; x"}`:  ErrCodeInvalidInput,
+		`{"slide_number": 1, "min_confidence": 101}`: ErrCodeInvalidInput,
+	} {
+		_, err := OCRSlide(context.Background(), env.app, json.RawMessage(input))
+		if code := toolErrorCode(err); code != want {
+			t.Errorf("expected %s for %s, got %s (%v)", want, input, code, err)
+		}
+	}
+
+	t.Setenv(tesseractEnv, filepath.Join(dir, "missing"))
+	_, err = OCRSlide(context.Background(), env.app, json.RawMessage(`{"slide_number": 1}`))
+	if code := toolErrorCode(err); code != ErrCodeProviderError {
+		t.Errorf("expected %s without tesseract, got %s (%v)", ErrCodeProviderError, code, err)
+	}
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// maxOutlineSlides caps the slides one generate_from_outline call adds
+	maxOutlineSlides = 50
+	// maxOutlineLevel is the deepest bullet level; deeper list items are kept at it
+	maxOutlineLevel = 4
+)
+
+var (
+	outlineHeadingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	outlineListItemPattern = regexp.MustCompile(`^([-*+]|\d{1,3}[.)])\s+(.*)$`)
+	outlineImagePattern    = regexp.MustCompile(`!\[([^\]]*)\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	outlineRulePattern     = regexp.MustCompile(`^(?:-{3,}|\*{3,}|_{3,})$`)
+	outlineLinkPattern     = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	outlineEmphasisPattern = regexp.MustCompile(`(\*\*|__|~~)(.+?)(\*\*|__|~~)|\*([^*\s][^*]*?)\*|` + "`([^`]*)`")
+)
+
+// OutlineSlide is one slide of a parsed outline, as uno_generate_slides.py builds it
+type OutlineSlide struct {
+	Title   string            `json:"title,omitempty"`
+	Bullets []OutlineBullet   `json:"bullets,omitempty"`
+	Images  []string          `json:"images,omitempty"`
+	Layout  map[string]string `json:"layout"`
+
+	kind string // "title", "secHead", "obj" or "titleOnly", resolved to Layout
+}
+
+// OutlineBullet is one paragraph of a slide's body at its list level (0 = top)
+type OutlineBullet struct {
+	Text  string `json:"text"`
+	Level int    `json:"level"`
+}
+
+// GenerateFromOutlineDefinition defines the generate_from_outline tool
+var GenerateFromOutlineDefinition = ToolDefinition{
+	Name: "generate_from_outline",
+	Description: `Turn a Markdown or plain-text outline into slides in one step. Use this whenever the user gives notes, an agenda or a document outline to make slides from, instead of adding and filling slides one by one.
+
+Markdown: every heading starts a slide and is its title, list items become bullets (indentation sets the level), other text lines become top-level bullets and images (![alt](path)) are placed on the slide next to the text. A heading without content becomes a title or section slide. Plain text without headings: unindented lines are slide titles and indented or "-" lines their bullets.
+
+Slides use the template's title, section header, content and title-only layouts. They are added at position (default: the end); nothing is added if any slide fails. Image paths are resolved against the outline file's folder, or the presentation's folder for inline outlines.`,
+	InputSchema: GenerateFromOutlineInputSchema,
+	Function:    GenerateFromOutline,
+	Mutating:    true,
+	Timeout:     3 * time.Minute,
+}
+
+type GenerateFromOutlineInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Outline          string `json:"outline,omitempty" jsonschema_description:"The outline as Markdown or indented text; give this or outline_path"`
+	OutlinePath      string `json:"outline_path,omitempty" jsonschema_description:"(Optional) A .md or .txt file holding the outline, used when outline is empty"`
+	Position         int    `json:"position,omitempty" jsonschema_description:"(Optional) Slide number the first new slide gets, defaults to the end (1-based indexing)"`
+}
+
+var GenerateFromOutlineInputSchema = GenerateSchema[GenerateFromOutlineInput]()
+
+func GenerateFromOutline(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	outlineInput := GenerateFromOutlineInput{}
+	if err := json.Unmarshal(input, &outlineInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if outlineInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			outlineInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+	if outlineInput.Position < 0 {
+		return "", NewToolError(ErrCodeInvalidInput, "position must be 1 or greater")
+	}
+
+	// Images are relative to the outline file, or the presentation for inline outlines
+	outline, baseDir := outlineInput.Outline, filepath.Dir(outlineInput.PresentationPath)
+	if strings.TrimSpace(outline) == "" {
+		if outlineInput.OutlinePath == "" {
+			return "", NewToolError(ErrCodeInvalidInput, "outline or outline_path is required")
+		}
+		outlinePath := outlineInput.OutlinePath
+		if !filepath.IsAbs(outlinePath) {
+			outlinePath = filepath.Join(baseDir, outlinePath)
+		}
+		data, err := os.ReadFile(outlinePath)
+		if err != nil {
+			return "", NewToolError(ErrCodeFileNotFound, "failed to read outline: %v", err).WithDetail("outline_path", outlinePath)
+		}
+		outline, baseDir = string(data), filepath.Dir(outlinePath)
+	}
+
+	slides, err := parseOutline(outline, baseDir)
+	if err != nil {
+		return "", err
+	}
+	if err := resolveOutlineLayouts(outlineInput.PresentationPath, slides); err != nil {
+		return "", err
+	}
+	slidesJSON, _ := json.Marshal(slides)
+
+	position := ""
+	if outlineInput.Position > 0 {
+		position = fmt.Sprintf("%d", outlineInput.Position)
+	}
+	output, err := runUnoScript(ctx, app, "uno_generate_slides.py", outlineInput.PresentationPath, string(slidesJSON), position)
+	if err != nil {
+		return "", scriptError("failed to generate slides", err, output)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return "", invalidScriptOutput(err)
+	}
+
+	// Re-render previews from the first new slide onward
+	firstSlide, _ := result["first_slide"].(float64)
+	totalSlides, _ := result["total_slides"].(float64)
+	if _, err := refreshPreviewsAfterStructuralChange(ctx, app, outlineInput.PresentationPath, int(firstSlide)-1, len(slides), int(totalSlides)); err != nil {
+		// Don't fail the generation if export fails, just warn
+		fmt.Printf("Warning: Failed to export slides for preview: %v\n", err)
+	}
+
+	return marshalResult(result)
+}
+
+// parseOutline splits an outline into slides. Markdown headings start slides; an outline
+// without any is read as plain text, where unindented lines are the slide titles.
+// Image paths are resolved against baseDir and must exist.
+func parseOutline(outline, baseDir string) ([]OutlineSlide, error) {
+	lines := strings.Split(strings.ReplaceAll(outline, "\r\n", "\n"), "\n")
+	markdown := false
+	for _, line := range lines {
+		if outlineHeadingPattern.MatchString(line) {
+			markdown = true
+			break
+		}
+	}
+
+	var slides []OutlineSlide
+	var current *OutlineSlide
+	var indents []int // Indentation of each open list level of the current slide
+	inFence := false
+	startSlide := func(title, kind string) {
+		slides = append(slides, OutlineSlide{Title: title, kind: kind})
+		current = &slides[len(slides)-1]
+		indents = nil
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if trimmed == "" || (!inFence && outlineRulePattern.MatchString(trimmed)) {
+			continue
+		}
+		indent := outlineIndent(line)
+
+		if !inFence {
+			if match := outlineHeadingPattern.FindStringSubmatch(line); match != nil {
+				kind := "secHead"
+				if len(match[1]) == 1 || len(slides) == 0 {
+					kind = "title"
+				}
+				startSlide(cleanOutlineText(match[2]), kind)
+				continue
+			}
+			if !markdown && indent == 0 && !outlineListItemPattern.MatchString(trimmed) {
+				startSlide(cleanOutlineText(trimmed), "title")
+				continue
+			}
+		}
+		if current == nil {
+			// Text before the first heading gets a slide without a title
+			startSlide("", "obj")
+		}
+		if inFence {
+			// Code keeps its lines as they are
+			current.Bullets = append(current.Bullets, OutlineBullet{Text: strings.TrimRight(line, " \t")})
+			continue
+		}
+
+		// Images are placed on the slide; the rest of their line, if any, is text
+		for _, match := range outlineImagePattern.FindAllStringSubmatch(trimmed, -1) {
+			imagePath, err := resolveOutlineImage(match[2], baseDir)
+			if err != nil {
+				return nil, err
+			}
+			current.Images = append(current.Images, imagePath)
+		}
+		trimmed = strings.TrimSpace(outlineImagePattern.ReplaceAllString(trimmed, ""))
+		if trimmed == "" {
+			continue
+		}
+
+		text, listItem := trimmed, false
+		if match := outlineListItemPattern.FindStringSubmatch(trimmed); match != nil {
+			text, listItem = match[2], true
+		}
+		// Indented Markdown text under a list item continues it
+		if markdown && !listItem && indent > 0 && len(current.Bullets) > 0 {
+			last := &current.Bullets[len(current.Bullets)-1]
+			last.Text += " " + cleanOutlineText(text)
+			continue
+		}
+
+		for len(indents) > 0 && indents[len(indents)-1] > indent {
+			indents = indents[:len(indents)-1]
+		}
+		if len(indents) == 0 || indents[len(indents)-1] < indent {
+			indents = append(indents, indent)
+		}
+		current.Bullets = append(current.Bullets, OutlineBullet{Text: cleanOutlineText(text), Level: min(len(indents)-1, maxOutlineLevel)})
+	}
+
+	if len(slides) == 0 {
+		return nil, NewToolError(ErrCodeInvalidInput, "the outline has no content to make slides from")
+	}
+	if len(slides) > maxOutlineSlides {
+		return nil, NewToolError(ErrCodeInvalidInput, "the outline has %d slides; split it into parts of at most %d", len(slides), maxOutlineSlides)
+	}
+	for i := range slides {
+		slide := &slides[i]
+		switch {
+		case len(slide.Bullets) > 0:
+			slide.kind = "obj"
+		case len(slide.Images) > 0:
+			slide.kind = "titleOnly"
+		}
+	}
+	return slides, nil
+}
+
+// outlineIndent returns the width of a line's leading whitespace, counting tabs as four
+// spaces
+func outlineIndent(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// cleanOutlineText strips Markdown emphasis, code marks and links down to their text
+func cleanOutlineText(text string) string {
+	text = outlineLinkPattern.ReplaceAllString(text, "$1")
+	for {
+		cleaned := outlineEmphasisPattern.ReplaceAllString(text, "$2$4$5")
+		if cleaned == text {
+			break
+		}
+		text = cleaned
+	}
+	return strings.TrimSpace(text)
+}
+
+// resolveOutlineImage returns the absolute path of an image an outline refers to
+func resolveOutlineImage(imagePath, baseDir string) (string, error) {
+	if strings.Contains(imagePath, "://") {
+		return "", NewToolError(ErrCodeInvalidInput, "image %s is not a local file; download it first", imagePath)
+	}
+	if !filepath.IsAbs(imagePath) {
+		imagePath = filepath.Join(baseDir, imagePath)
+	}
+	if _, err := os.Stat(imagePath); err != nil {
+		return "", NewToolError(ErrCodeFileNotFound, "image file not found: %s", imagePath).WithDetail("image_path", imagePath)
+	}
+	return imagePath, nil
+}
+
+// resolveOutlineLayouts picks the template layout of each slide's kind, by type. A kind
+// the template has no layout for is passed by type alone, which sets the matching Impress
+// AutoLayout.
+func resolveOutlineLayouts(presentationPath string, slides []OutlineSlide) error {
+	var layouts []pptxLayout
+	if isOOXMLPackage(presentationPath) {
+		pkg, err := openPPTX(presentationPath)
+		if err != nil {
+			return NewToolError(ErrCodeFileNotFound, "failed to open presentation: %v", err)
+		}
+		layouts, err = pkg.Layouts()
+		pkg.Close()
+		if err != nil {
+			return NewToolError(ErrCodeInternal, "failed to read layouts: %v", err)
+		}
+	}
+
+	for i := range slides {
+		slides[i].Layout = map[string]string{"type": slides[i].kind}
+		for _, layout := range layouts {
+			if strings.EqualFold(layout.Type, slides[i].kind) {
+				slides[i].Layout = map[string]string{"name": layout.Name, "type": layout.Type}
+				break
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseOutlineMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "chart.png"), []byte("png"), 0644)
+
+	slides, err := parseOutline(`# Quarterly Review
+
+## Highlights
+- Revenue up **12%**
+  - Driven by [renewals](https://example.com)
+    continuing into Q4
+- Churn down
+1. Hiring on track
+
+## Results
+![Revenue chart](chart.png)
+
+### Next steps ###
+Plan the *offsite*
+`, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []OutlineSlide{
+		{Title: "Quarterly Review", kind: "title"},
+		{Title: "Highlights", kind: "obj", Bullets: []OutlineBullet{
+			{Text: "Revenue up 12%", Level: 0},
+			{Text: "Driven by renewals continuing into Q4", Level: 1},
+			{Text: "Churn down", Level: 0},
+			{Text: "Hiring on track", Level: 0},
+		}},
+		{Title: "Results", kind: "titleOnly", Images: []string{filepath.Join(dir, "chart.png")}},
+		{Title: "Next steps", kind: "obj", Bullets: []OutlineBullet{{Text: "Plan the offsite", Level: 0}}},
+	}
+	if !reflect.DeepEqual(slides, want) {
+		t.Errorf("unexpected slides:\n got %+v\nwant %+v", slides, want)
+	}
+}
+
+func TestParseOutlinePlainText(t *testing.T) {
+	slides, err := parseOutline("Agenda\n  Welcome\n  Roadmap\n    Q1\nWrap-up\n", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []OutlineSlide{
+		{Title: "Agenda", kind: "obj", Bullets: []OutlineBullet{{"Welcome", 0}, {"Roadmap", 0}, {"Q1", 1}}},
+		{Title: "Wrap-up", kind: "title"},
+	}
+	if !reflect.DeepEqual(slides, want) {
+		t.Errorf("unexpected slides:\n got %+v\nwant %+v", slides, want)
+	}
+
+	if _, err := parseOutline("# Slide\n![logo](missing.png)", t.TempDir()); toolErrorCode(err) != ErrCodeFileNotFound {
+		t.Errorf("expected a missing image to be reported, got %v", err)
+	}
+	if _, err := parseOutline(" \n\n", ""); toolErrorCode(err) != ErrCodeInvalidInput {
+		t.Errorf("expected an empty outline to be refused, got %v", err)
+	}
+}
+
+func TestGenerateFromOutlineAddsSlides(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	if _, err := convertSlides(context.Background(), env.app, path, "slides"); err != nil {
+		t.Fatal(err)
+	}
+	env.converter.Calls = nil
+	env.uno.Respond("uno_generate_slides.py", `{"success": true, "first_slide": 3, "slides_added": 3, "total_slides": 5}`)
+	os.WriteFile(filepath.Join(env.dir, "notes.md"), []byte("# Launch\n## Plan\n- Build\n- Ship\n## Team\n- Ana\n"), 0644)
+
+	result := env.app.aiAgent.executeTool(context.Background(), "toolu_1", "generate_from_outline", []byte(`{"outline_path": "notes.md"}`))
+	if result.OfToolResult.IsError.Value {
+		t.Fatalf("generate_from_outline failed: %s", result.OfToolResult.Content[0].OfText.Text)
+	}
+
+	calls := env.uno.Calls("uno_generate_slides.py")
+	if len(calls) != 1 || calls[0].Args[0] != path || calls[0].Args[2] != "" {
+		t.Fatalf("expected one call appending to %s, got %v", path, calls)
+	}
+	var slides []OutlineSlide
+	if err := json.Unmarshal([]byte(calls[0].Args[1]), &slides); err != nil {
+		t.Fatal(err)
+	}
+	if len(slides) != 3 || slides[0].Layout["type"] != "title" || slides[1].Layout["type"] != "obj" || len(slides[2].Bullets) != 1 {
+		t.Errorf("unexpected slides passed to the script: %+v", slides)
+	}
+	// The fixture has a "Title and Content" layout, which content slides use
+	if slides[1].Layout["name"] != "Title and Content" {
+		t.Errorf("expected content slides to use the template's layout, got %v", slides[1].Layout)
+	}
+	// Only the new slides are rendered
+	if len(env.converter.Calls) != 0 || len(env.converter.RangeCalls) != 1 || env.converter.RangeCalls[0] != [2]int{2, 4} {
+		t.Errorf("expected slides 3-5 to be rendered, got %v and %v", env.converter.Calls, env.converter.RangeCalls)
+	}
+	if countSlidePreviews("slides") != 5 {
+		t.Errorf("expected 5 previews, got %d", countSlidePreviews("slides"))
+	}
+
+	// The binding refuses an outline the tool can't use, leaving the deck alone
+	if _, err := env.app.GenerateFromOutline("  "); err == nil || !strings.Contains(err.Error(), "outline or outline_path is required") {
+		t.Errorf("expected the tool's error, got %v", err)
+	}
+	if calls := env.uno.Calls("uno_generate_slides.py"); len(calls) != 1 {
+		t.Errorf("expected no further generation, got %d calls", len(calls))
+	}
+}
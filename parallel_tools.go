@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// toolWorkersEnv sets how many read-only tool calls of one model response run at once
+const toolWorkersEnv = "SLIDEPILOT_TOOL_WORKERS"
+
+const defaultToolWorkers = 4
+
+// toolWorkers returns how many read-only tool calls may run at once; 1 runs them in turn
+func toolWorkers() int {
+	if value := os.Getenv(toolWorkersEnv); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			return n
+		}
+		fmt.Printf("Warning: ignoring invalid %s=%q\n", toolWorkersEnv, value)
+	}
+	return defaultToolWorkers
+}
+
+// runsConcurrently reports whether a tool call can run alongside others: it only reads,
+// never waits for the user and writes no files two calls could both be writing
+func (a *AIAgent) runsConcurrently(call anthropic.ContentBlockUnion) bool {
+	tool, found := a.tools.Lookup(call.Name)
+	return found && tool.ReadOnly && !tool.WritesFiles && !tool.Mutating && !tool.Destructive
+}
+
+// runToolCalls runs the tool calls of a model response and returns their results in the
+// same order. Consecutive read-only calls, such as reading several slides, run
+// concurrently on a bounded number of workers; any other call runs on its own, after the
+// calls before it have finished, so edits keep their order.
+func (a *AIAgent) runToolCalls(ctx context.Context, calls []anthropic.ContentBlockUnion) []anthropic.ContentBlockParamUnion {
+	results := make([]anthropic.ContentBlockParamUnion, len(calls))
+	workers := toolWorkers()
+	for start := 0; start < len(calls); {
+		end := start + 1
+		if workers > 1 && a.runsConcurrently(calls[start]) {
+			for end < len(calls) && a.runsConcurrently(calls[end]) {
+				end++
+			}
+		}
+		if end-start == 1 {
+			results[start] = a.runToolCall(ctx, calls[start])
+		} else {
+			a.logToFile("DEBUG", fmt.Sprintf("Running %d read-only tool calls concurrently", end-start), "")
+			sem := make(chan struct{}, workers)
+			var wg sync.WaitGroup
+			for i := start; i < end; i++ {
+				wg.Add(1)
+				sem <- struct{}{}
+				go func(i int) {
+					defer func() {
+						<-sem
+						wg.Done()
+					}()
+					results[i] = a.runToolCall(ctx, calls[i])
+				}(i)
+			}
+			wg.Wait()
+		}
+		start = end
+	}
+	return results
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// toolUsesResponse builds an assistant message that calls several tools, given as name and input pairs
+func toolUsesResponse(calls ...[2]string) string {
+	var blocks []string
+	for i, call := range calls {
+		blocks = append(blocks, fmt.Sprintf(`{"type":"tool_use","id":"toolu_%d","name":%q,"input":%s}`, i+1, call[0], call[1]))
+	}
+	return fmt.Sprintf(`{"id":"msg_tools","type":"message","role":"assistant","model":"fake","stop_reason":"tool_use",
+		"content":[%s],"usage":{"input_tokens":1,"output_tokens":1}}`, strings.Join(blocks, ","))
+}
+
+// registerProbe adds a tool that records how many of its calls run at the same time
+func registerProbe(t *testing.T, env *testEnv, name string, readOnly bool, running, peak *int, mu *sync.Mutex) {
+	t.Helper()
+	err := env.app.aiAgent.tools.Register(ToolDefinition{
+		Name:        name,
+		InputSchema: GenerateSchema[struct{}](),
+		ReadOnly:    readOnly,
+		Function: func(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+			mu.Lock()
+			*running++
+			*peak = max(*peak, *running)
+			mu.Unlock()
+			time.Sleep(50 * time.Millisecond)
+			mu.Lock()
+			*running--
+			mu.Unlock()
+			return string(input), nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadOnlyToolCallsRunConcurrently(t *testing.T) {
+	env := newTestEnv(t,
+		toolUsesResponse(
+			[2]string{"probe_read", `{"n": 1}`},
+			[2]string{"probe_read", `{"n": 2}`},
+			[2]string{"probe_read", `{"n": 3}`},
+		),
+		textResponse("Done."),
+	)
+	env.loadFixture(t, "two_slides.pptx")
+	var mu sync.Mutex
+	var running, peak int
+	registerProbe(t, env, "probe_read", true, &running, &peak, &mu)
+
+	if err := env.app.SendMessageToAI("Read everything"); err != nil {
+		t.Fatal(err)
+	}
+	if peak < 2 {
+		t.Errorf("expected read-only calls to overlap, at most %d ran at once", peak)
+	}
+
+	// Results come back in the order of the calls
+	results := env.llm.Requests[1].Messages[len(env.llm.Requests[1].Messages)-1].Content
+	if len(results) != 3 {
+		t.Fatalf("expected 3 tool results, got %d", len(results))
+	}
+	for i, block := range results {
+		if id := block.OfToolResult.ToolUseID; id != fmt.Sprintf("toolu_%d", i+1) {
+			t.Errorf("result %d answers %s", i+1, id)
+		}
+		if text := toolResultText(block); !strings.Contains(text, fmt.Sprintf(`"n": %d`, i+1)) && !strings.Contains(text, fmt.Sprintf(`"n":%d`, i+1)) {
+			t.Errorf("result %d has the wrong output: %s", i+1, text)
+		}
+	}
+}
+
+func TestOtherToolCallsRunOneAtATime(t *testing.T) {
+	t.Setenv(toolWorkersEnv, "4")
+	env := newTestEnv(t,
+		toolUsesResponse(
+			[2]string{"probe_write", `{}`},
+			[2]string{"probe_write", `{}`},
+			[2]string{"probe_write", `{}`},
+		),
+		textResponse("Done."),
+	)
+	env.loadFixture(t, "two_slides.pptx")
+	var mu sync.Mutex
+	var running, peak int
+	registerProbe(t, env, "probe_write", false, &running, &peak, &mu)
+
+	if err := env.app.SendMessageToAI("Do things"); err != nil {
+		t.Fatal(err)
+	}
+	if peak != 1 {
+		t.Errorf("expected calls that aren't read-only to run one at a time, %d ran at once", peak)
+	}
+}
+
+func TestToolWorkersOfOneRunsReadsInTurn(t *testing.T) {
+	t.Setenv(toolWorkersEnv, "1")
+	env := newTestEnv(t,
+		toolUsesResponse(
+			[2]string{"probe_read", `{}`},
+			[2]string{"probe_read", `{}`},
+		),
+		textResponse("Done."),
+	)
+	env.loadFixture(t, "two_slides.pptx")
+	var mu sync.Mutex
+	var running, peak int
+	registerProbe(t, env, "probe_read", true, &running, &peak, &mu)
+
+	if err := env.app.SendMessageToAI("Read everything"); err != nil {
+		t.Fatal(err)
+	}
+	if peak != 1 {
+		t.Errorf("expected one call at a time, %d ran at once", peak)
+	}
+}
+
+// overlapConverter records how many exports run at the same time
+type overlapConverter struct {
+	SlideConverter
+	mu            sync.Mutex
+	running, peak int
+}
+
+func (c *overlapConverter) ExportImages(ctx context.Context, pptxPath, outputDir string, first, last int, options ExportOptions) ([]string, error) {
+	c.mu.Lock()
+	c.running++
+	c.peak = max(c.peak, c.running)
+	c.mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	defer func() {
+		c.mu.Lock()
+		c.running--
+		c.mu.Unlock()
+	}()
+	return c.SlideConverter.ExportImages(ctx, pptxPath, outputDir, first, last, options)
+}
+
+func TestToolCallsWritingFilesRunOneAtATime(t *testing.T) {
+	t.Setenv(toolWorkersEnv, "4")
+	env := newTestEnv(t,
+		toolUsesResponse(
+			[2]string{"export_slides", `{"format": "png", "output_dir": "out"}`},
+			[2]string{"export_slides", `{"format": "png", "output_dir": "out"}`},
+		),
+		textResponse("Done."),
+	)
+	env.loadFixture(t, "two_slides.pptx")
+	converter := &overlapConverter{SlideConverter: env.converter}
+	env.app.converter = converter
+
+	if err := env.app.SendMessageToAI("Export the slides twice"); err != nil {
+		t.Fatal(err)
+	}
+	if len(env.converter.Exports) != 2 {
+		t.Fatalf("expected 2 exports, got %d", len(env.converter.Exports))
+	}
+	if converter.peak != 1 {
+		t.Errorf("expected exports into the same folder to run one at a time, %d ran at once", converter.peak)
+	}
+}
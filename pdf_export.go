@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// PDF export renders the deck through LibreOffice's impress_pdf_Export filter, which
+// handles slides and notes pages. The filter has no handout mode, so handouts are laid
+// out here from slide images exported through the same UNO pipeline as the previews.
+
+// PDF layouts
+const (
+	pdfLayoutSlides  = "slides"  // One slide per page
+	pdfLayoutNotes   = "notes"   // Notes pages: each slide above its speaker notes
+	pdfLayoutHandout = "handout" // Several slides per page
+)
+
+// handoutGrids maps the supported slides per handout page to their columns and rows
+var handoutGrids = map[int][2]int{1: {1, 1}, 2: {1, 2}, 3: {1, 3}, 4: {2, 2}, 6: {2, 3}, 9: {3, 3}}
+
+// defaultHandoutSlides is how many slides a handout page shows unless told otherwise
+const defaultHandoutSlides = 6
+
+// handoutImageOptions renders the slides placed on handout pages
+var handoutImageOptions = ExportOptions{Format: "jpeg", DPI: 150, Quality: 85}
+
+// Handout page geometry in PDF points: US Letter portrait
+const (
+	handoutPageWidth  = 612.0
+	handoutPageHeight = 792.0
+	handoutMargin     = 36.0
+	handoutGap        = 18.0
+)
+
+// PDFExportOptions selects what a PDF export contains
+type PDFExportOptions struct {
+	Layout        string `json:"layout"`          // slides (default), notes or handout
+	SlidesPerPage int    `json:"slides_per_page"` // Handout only: 1, 2, 3, 4, 6 (default) or 9
+	FirstSlide    int    `json:"first_slide"`     // 1-based; 0 for the first slide
+	LastSlide     int    `json:"last_slide"`      // 1-based, inclusive; 0 for the last slide
+}
+
+// WithDefaults fills in the layout, the handout size and a range covering all slideCount slides
+func (o PDFExportOptions) WithDefaults(slideCount int) PDFExportOptions {
+	o.Layout = strings.ToLower(o.Layout)
+	if o.Layout == "" {
+		o.Layout = pdfLayoutSlides
+	}
+	if o.Layout == pdfLayoutHandout && o.SlidesPerPage == 0 {
+		o.SlidesPerPage = defaultHandoutSlides
+	}
+	if o.FirstSlide == 0 {
+		o.FirstSlide = 1
+	}
+	if o.LastSlide == 0 {
+		o.LastSlide = slideCount
+	}
+	return o
+}
+
+// Validate checks the layout of options already passed through WithDefaults; the slide
+// range is checked against the deck by the caller
+func (o PDFExportOptions) Validate() error {
+	switch o.Layout {
+	case pdfLayoutSlides, pdfLayoutNotes:
+		if o.SlidesPerPage != 0 {
+			return fmt.Errorf("slides_per_page only applies to the handout layout")
+		}
+	case pdfLayoutHandout:
+		if _, ok := handoutGrids[o.SlidesPerPage]; !ok {
+			return fmt.Errorf("slides_per_page must be 1, 2, 3, 4, 6 or 9")
+		}
+	default:
+		return fmt.Errorf("unsupported layout %q (use slides, notes or handout)", o.Layout)
+	}
+	return nil
+}
+
+// defaultPDFPath returns where a PDF of presentationPath goes when no path is given
+func defaultPDFPath(presentationPath string, options PDFExportOptions) string {
+	name := strings.TrimSuffix(filepath.Base(presentationPath), filepath.Ext(presentationPath))
+	if options.Layout != pdfLayoutSlides {
+		name += " " + options.Layout
+	}
+	return filepath.Join(filepath.Dir(presentationPath), name+".pdf")
+}
+
+// exportPDF writes a PDF of presentationPath to outputPath and returns a summary of it.
+// Options must have been validated.
+func exportPDF(ctx context.Context, app *App, presentationPath, outputPath string, options PDFExportOptions) (*ExportResult, error) {
+	result := &ExportResult{
+		Format:     "pdf",
+		OutputPath: outputPath,
+		Layout:     options.Layout,
+		FirstSlide: options.FirstSlide,
+		LastSlide:  options.LastSlide,
+	}
+
+	if options.Layout == pdfLayoutHandout {
+		pages, err := exportHandoutPDF(ctx, app, presentationPath, outputPath, options)
+		if err != nil {
+			return nil, err
+		}
+		result.SlidesPerPage = options.SlidesPerPage
+		result.Pages = pages
+		return result, nil
+	}
+
+	scriptOptions, _ := json.Marshal(map[string]interface{}{
+		"page_range": fmt.Sprintf("%d-%d", options.FirstSlide, options.LastSlide),
+		"notes":      options.Layout == pdfLayoutNotes,
+	})
+	output, err := runUnoScript(ctx, app, "uno_export_pdf.py", presentationPath, outputPath, string(scriptOptions))
+	if err != nil {
+		return nil, scriptError("failed to export PDF", err, output)
+	}
+	if _, err := decodeScriptResult[map[string]interface{}](output); err != nil {
+		return nil, err
+	}
+	result.Pages = options.LastSlide - options.FirstSlide + 1
+	return result, nil
+}
+
+// exportHandoutPDF renders the slides in range and lays them out several per page,
+// returning the number of pages written
+func exportHandoutPDF(ctx context.Context, app *App, presentationPath, outputPath string, options PDFExportOptions) (int, error) {
+	renderDir, err := os.MkdirTemp(sessionTempDir(), "handout-")
+	if err != nil {
+		return 0, NewToolError(ErrCodeInternal, "failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(renderDir)
+
+	images, err := exportSlideImagesWith(ctx, app, presentationPath, renderDir, options.FirstSlide-1, options.LastSlide-1, handoutImageOptions)
+	if err != nil {
+		return 0, NewToolError(ErrCodeExportFailed, "failed to render slides for the handout: %v", err)
+	}
+	slices.Sort(images)
+
+	pages, err := writeHandoutPDF(outputPath, images, options.SlidesPerPage)
+	if err != nil {
+		return 0, NewToolError(ErrCodeExportFailed, "failed to write handout: %v", err)
+	}
+	return pages, nil
+}
+
+// handoutImage is a JPEG placed on a handout page
+type handoutImage struct {
+	data          []byte
+	width, height int
+	colorSpace    string
+}
+
+// readHandoutImage loads a JPEG slide image; PDF embeds JPEGs as they are
+func readHandoutImage(path string) (handoutImage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return handoutImage{}, err
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return handoutImage{}, fmt.Errorf("%s: %v", filepath.Base(path), err)
+	}
+	if format != "jpeg" {
+		return handoutImage{}, fmt.Errorf("%s: expected a JPEG, got %s", filepath.Base(path), format)
+	}
+
+	colorSpace := "DeviceRGB"
+	switch config.ColorModel {
+	case color.GrayModel:
+		colorSpace = "DeviceGray"
+	case color.CMYKModel:
+		colorSpace = "DeviceCMYK"
+	}
+	return handoutImage{data: data, width: config.Width, height: config.Height, colorSpace: colorSpace}, nil
+}
+
+// writeHandoutPDF writes the images perPage to a page, in a grid from handoutGrids, and
+// returns the number of pages
+func writeHandoutPDF(outputPath string, imagePaths []string, perPage int) (int, error) {
+	grid, ok := handoutGrids[perPage]
+	if !ok {
+		return 0, fmt.Errorf("unsupported slides per page: %d", perPage)
+	}
+	if len(imagePaths) == 0 {
+		return 0, fmt.Errorf("no slides to lay out")
+	}
+
+	images := make([]handoutImage, len(imagePaths))
+	for i, path := range imagePaths {
+		img, err := readHandoutImage(path)
+		if err != nil {
+			return 0, err
+		}
+		images[i] = img
+	}
+
+	// Object numbers: 1 catalog, 2 page tree, then per page its page and content stream,
+	// then one XObject per image
+	pageCount := (len(images) + perPage - 1) / perPage
+	firstImageObject := 3 + 2*pageCount
+
+	pdf := &pdfWriter{}
+	pdf.header()
+	pdf.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, pageCount)
+	for page := range pageCount {
+		kids[page] = fmt.Sprintf("%d 0 R", 3+2*page)
+	}
+	pdf.object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pageCount))
+
+	columns, rows := grid[0], grid[1]
+	cellWidth := (handoutPageWidth - 2*handoutMargin - float64(columns-1)*handoutGap) / float64(columns)
+	cellHeight := (handoutPageHeight - 2*handoutMargin - float64(rows-1)*handoutGap) / float64(rows)
+
+	for page := range pageCount {
+		pageObject := 3 + 2*page
+		var content, resources strings.Builder
+		for slot := range perPage {
+			i := page*perPage + slot
+			if i >= len(images) {
+				break
+			}
+			img := images[i]
+
+			// Fit the slide in its cell, keeping its aspect ratio, centered
+			column, row := slot%columns, slot/columns
+			scale := min(cellWidth/float64(img.width), cellHeight/float64(img.height))
+			width, height := float64(img.width)*scale, float64(img.height)*scale
+			x := handoutMargin + float64(column)*(cellWidth+handoutGap) + (cellWidth-width)/2
+			top := handoutPageHeight - handoutMargin - float64(row)*(cellHeight+handoutGap) - (cellHeight-height)/2
+			y := top - height
+
+			fmt.Fprintf(&content, "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n", width, height, x, y, i)
+			fmt.Fprintf(&content, "q 0.5 w 0.6 G %.2f %.2f %.2f %.2f re S Q\n", x, y, width, height)
+			fmt.Fprintf(&resources, "/Im%d %d 0 R ", i, firstImageObject+i)
+		}
+
+		pdf.object(pageObject, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /XObject << %s>> >> /Contents %d 0 R >>",
+			handoutPageWidth, handoutPageHeight, resources.String(), pageObject+1))
+		pdf.stream(pageObject+1, "", []byte(content.String()))
+	}
+
+	for i, img := range images {
+		pdf.stream(firstImageObject+i, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /DCTDecode ",
+			img.width, img.height, img.colorSpace), img.data)
+	}
+	pdf.trailer(firstImageObject + len(images) - 1)
+
+	tmp := outputPath + ".tmp"
+	if err := os.WriteFile(tmp, pdf.buf, 0644); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp, outputPath); err != nil {
+		os.Remove(tmp)
+		return 0, err
+	}
+	return pageCount, nil
+}
+
+// pdfWriter assembles a PDF file, recording object offsets for the cross-reference table
+type pdfWriter struct {
+	buf     []byte
+	offsets map[int]int
+}
+
+func (w *pdfWriter) header() {
+	w.offsets = map[int]int{}
+	w.buf = append(w.buf, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n"...)
+}
+
+func (w *pdfWriter) object(number int, body string) {
+	w.offsets[number] = len(w.buf)
+	w.buf = fmt.Appendf(w.buf, "%d 0 obj\n%s\nendobj\n", number, body)
+}
+
+func (w *pdfWriter) stream(number int, dict string, data []byte) {
+	w.offsets[number] = len(w.buf)
+	w.buf = fmt.Appendf(w.buf, "%d 0 obj\n<< %s/Length %d >>\nstream\n", number, dict, len(data))
+	w.buf = append(w.buf, data...)
+	w.buf = append(w.buf, "\nendstream\nendobj\n"...)
+}
+
+// trailer writes the cross-reference table for objects 1..last
+func (w *pdfWriter) trailer(last int) {
+	xref := len(w.buf)
+	w.buf = fmt.Appendf(w.buf, "xref\n0 %d\n0000000000 65535 f \n", last+1)
+	for number := 1; number <= last; number++ {
+		w.buf = fmt.Appendf(w.buf, "%010d 00000 n \n", w.offsets[number])
+	}
+	w.buf = fmt.Appendf(w.buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", last+1, xref)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// writeTestJPEG writes a JPEG of the given size and returns its path
+func writeTestJPEG(t *testing.T, dir, name string, width, height int) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height)), nil); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestWriteHandoutPDF(t *testing.T) {
+	dir := t.TempDir()
+	var images []string
+	for i := range 7 {
+		images = append(images, writeTestJPEG(t, dir, "slide-"+strconv.Itoa(i)+".jpg", 160, 90))
+	}
+	output := filepath.Join(dir, "handout.pdf")
+
+	pages, err := writeHandoutPDF(output, images, 6)
+	if err != nil {
+		t.Fatalf("writeHandoutPDF failed: %v", err)
+	}
+	if pages != 2 {
+		t.Errorf("expected 7 slides at 6 per page to take 2 pages, got %d", pages)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Error("expected a complete PDF file")
+	}
+	if !bytes.Contains(data, []byte("/Count 2")) {
+		t.Error("expected a page tree with 2 pages")
+	}
+	if n := bytes.Count(data, []byte("/Subtype /Image")); n != 7 {
+		t.Errorf("expected 7 embedded slide images, got %d", n)
+	}
+
+	// Every cross-reference entry points at the object it names
+	xref := regexp.MustCompile(`(?m)^(\d{10}) 00000 n $`).FindAllSubmatch(data, -1)
+	if len(xref) != 3+2*pages+len(images)-1 {
+		t.Fatalf("expected an xref entry per object, got %d", len(xref))
+	}
+	for i, entry := range xref {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if want := strconv.Itoa(i+1) + " 0 obj"; !bytes.HasPrefix(data[offset:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q", i+1, data[offset:min(offset+10, len(data))])
+		}
+	}
+}
+
+func TestWriteHandoutPDFRejectsNonJPEG(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "slide-000.png")
+	os.WriteFile(path, []byte("not an image"), 0644)
+
+	if _, err := writeHandoutPDF(filepath.Join(dir, "out.pdf"), []string{path}, 1); err == nil {
+		t.Error("expected an undecodable image to fail")
+	}
+	if _, err := writeHandoutPDF(filepath.Join(dir, "out.pdf"), []string{path}, 5); err == nil {
+		t.Error("expected 5 slides per page to be rejected")
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// submitEditPlanName is the tool the model hands its plan to in plan mode
+const submitEditPlanName = "submit_edit_plan"
+
+// maxPlanSteps caps the tool calls of one plan
+const maxPlanSteps = 100
+
+// planModePrompt is added to the system prompt while plan mode is on
+const planModePrompt = `Plan mode is on: the user reviews every edit before it is made. Inspect the presentation with the read-only tools, then call submit_edit_plan once with every tool call the request needs, in order. Each step is run exactly as given, so inputs must be complete: take shape_ids from read_slide and account for slide numbers shifting when earlier steps add, delete or move slides. You only have the read-only tools and submit_edit_plan; the plan's result tells you which steps ran.`
+
+// EditPlanStep is one tool call of a plan
+type EditPlanStep struct {
+	Tool        string                 `json:"tool" jsonschema_description:"Name of the tool to call, e.g. edit_slide_text"`
+	Input       map[string]interface{} `json:"input" jsonschema_description:"The complete input of the tool call"`
+	Description string                 `json:"description" jsonschema_description:"What this step changes, for the user, e.g. 'Shorten the title of slide 3'"`
+}
+
+// SubmitEditPlanInput is the model's plan
+type SubmitEditPlanInput struct {
+	Summary string         `json:"summary" jsonschema_description:"One or two sentences on what the plan does"`
+	Steps   []EditPlanStep `json:"steps" jsonschema_description:"Tool calls to run in order"`
+}
+
+// EditPlan is the payload of "edit-plan" events. When plans are reviewed the frontend
+// answers with RespondToolApproval(ID, approved).
+type EditPlan struct {
+	ID      string         `json:"id"`
+	Summary string         `json:"summary"`
+	Steps   []EditPlanStep `json:"steps"`
+	Review  bool           `json:"review"` // Whether the plan waits for approval
+}
+
+// PlanProgress is the payload of "plan-progress" events, sent as each step of an approved
+// plan starts and ends
+type PlanProgress struct {
+	PlanID      string `json:"plan_id"`
+	Step        int    `json:"step"` // 1-based
+	Total       int    `json:"total"`
+	Tool        string `json:"tool"`
+	Description string `json:"description"`
+	Status      string `json:"status"` // "running", "done", "failed" or "skipped"
+	Error       string `json:"error,omitempty"`
+}
+
+// planStepResult reports one step back to the model
+type planStepResult struct {
+	Step   int             `json:"step"`
+	Tool   string          `json:"tool"`
+	Status string          `json:"status"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+var SubmitEditPlanDefinition = ToolDefinition{
+	Name: submitEditPlanName,
+	Description: `Submit the edits for the user's request as a plan. The user reviews the plan; once approved, every step runs in order and the result reports each step's outcome. If a step fails, the remaining steps are skipped and the edits of the turn are rolled back.
+
+Call this once, after inspecting the slides, with every mutating tool call the request needs.`,
+	InputSchema: GenerateSchema[SubmitEditPlanInput](),
+}
+
+// planModeTools returns the tools offered while planning: the tools that change nothing
+// and submit_edit_plan
+func (a *AIAgent) planModeTools() []ToolDefinition {
+	var tools []ToolDefinition
+	for _, tool := range a.tools.List() {
+		if tool.changesNothing() {
+			tools = append(tools, tool)
+		}
+	}
+	return append(tools, SubmitEditPlanDefinition)
+}
+
+// planModeToolUse runs a tool call of a turn in plan mode. Edits, saved copies, exports
+// and anything else that writes only happen through a submitted plan.
+func (a *AIAgent) planModeToolUse(ctx context.Context, id, name string, input json.RawMessage) anthropic.ContentBlockParamUnion {
+	if name == submitEditPlanName {
+		return a.runEditPlan(ctx, id, input)
+	}
+	if tool, found := a.tools.Lookup(name); found && !tool.changesNothing() {
+		return anthropic.NewToolResultBlock(id, toolErrorEnvelope(NewToolError(ErrCodeInvalidInput,
+			"plan mode is on: %s can't be called directly; add it as a step of submit_edit_plan", name)), true)
+	}
+	return a.executeTool(ctx, id, name, input)
+}
+
+// runEditPlan validates a plan, has the user review it, then runs its steps in order,
+// emitting "plan-progress" for each. The first failing step stops the plan.
+func (a *AIAgent) runEditPlan(ctx context.Context, id string, input json.RawMessage) anthropic.ContentBlockParamUnion {
+	var plan SubmitEditPlanInput
+	if err := json.Unmarshal(input, &plan); err != nil {
+		return anthropic.NewToolResultBlock(id, toolErrorEnvelope(NewToolError(ErrCodeInvalidInput, "invalid plan: %v", err)), true)
+	}
+	if err := a.validatePlan(plan); err != nil {
+		return anthropic.NewToolResultBlock(id, toolErrorEnvelope(err), true)
+	}
+
+	review := a.reviewPlans && a.app.approvals != nil
+	a.logToFile("PLAN", fmt.Sprintf("Plan with %d steps: %s", len(plan.Steps), plan.Summary), string(input))
+	if a.app.events != nil {
+		a.app.events.Emit(a.ctx, "edit-plan", EditPlan{ID: id, Summary: plan.Summary, Steps: plan.Steps, Review: review})
+	}
+	if review {
+		approved, err := a.app.approvals.Await(ctx, id)
+		if err != nil {
+			return anthropic.NewToolResultBlock(id, toolErrorEnvelope(NewToolError(ErrCodeCancelled, "the request was cancelled while the plan was waiting for approval")), true)
+		}
+		if !approved {
+			a.logToFile("PLAN", "User rejected the plan", "")
+			return anthropic.NewToolResultBlock(id, toolErrorEnvelope(NewToolError(ErrCodeUserDenied,
+				"the user rejected the plan; nothing was changed. Ask the user what to change instead of resubmitting it")), true)
+		}
+		a.logToFile("PLAN", "User approved the plan", "")
+	}
+
+	// Approving the plan approves its destructive steps too
+	a.runningPlan = true
+	defer func() { a.runningPlan = false }()
+
+	results := []planStepResult{}
+	failed := false
+	for i, step := range plan.Steps {
+		progress := PlanProgress{PlanID: id, Step: i + 1, Total: len(plan.Steps), Tool: step.Tool, Description: step.Description}
+		if failed || ctx.Err() != nil {
+			progress.Status = "skipped"
+			a.emitPlanProgress(progress)
+			results = append(results, planStepResult{Step: i + 1, Tool: step.Tool, Status: "skipped"})
+			continue
+		}
+
+		progress.Status = "running"
+		a.emitPlanProgress(progress)
+		a.emitMessage(getToolDisplayName(step.Tool))
+		stepInput, _ := json.Marshal(step.Input)
+		result := a.executeTool(ctx, fmt.Sprintf("%s_step_%d", id, i+1), step.Tool, stepInput)
+
+		text := toolResultText(result)
+		progress.Status = "done"
+		if result.OfToolResult.IsError.Value {
+			failed = true
+			progress.Status = "failed"
+			progress.Error = text
+		}
+		a.emitPlanProgress(progress)
+		results = append(results, planStepResult{Step: i + 1, Tool: step.Tool, Status: progress.Status, Result: jsonOrString(text)})
+	}
+
+	completed := 0
+	for _, result := range results {
+		if result.Status == "done" {
+			completed++
+		}
+	}
+	if ctx.Err() != nil {
+		return anthropic.NewToolResultBlock(id, toolErrorEnvelope(NewToolError(ErrCodeCancelled,
+			"the request was cancelled after %d of %d plan steps", completed, len(plan.Steps)).
+			WithDetail("steps", results)), true)
+	}
+	if failed {
+		return anthropic.NewToolResultBlock(id, toolErrorEnvelope(NewToolError(ErrCodePlanStepFailed,
+			"step %d of the plan failed and the remaining steps were skipped; see steps for each outcome", completed+1).
+			WithDetail("steps", results)), true)
+	}
+	report, _ := json.Marshal(map[string]interface{}{
+		"completed_steps": completed,
+		"steps":           results,
+	})
+	return anthropic.NewToolResultBlock(id, normalizeToolResult(string(report)), false)
+}
+
+// validatePlan checks that a plan only calls known tools before the user sees it
+func (a *AIAgent) validatePlan(plan SubmitEditPlanInput) error {
+	if len(plan.Steps) == 0 {
+		return NewToolError(ErrCodeInvalidInput, "the plan has no steps")
+	}
+	if len(plan.Steps) > maxPlanSteps {
+		return NewToolError(ErrCodeInvalidInput, "the plan has %d steps; split it into plans of at most %d", len(plan.Steps), maxPlanSteps)
+	}
+	var unknown []string
+	for i, step := range plan.Steps {
+		if step.Tool == submitEditPlanName {
+			return NewToolError(ErrCodeInvalidInput, "step %d: plans can't contain %s", i+1, submitEditPlanName)
+		}
+		if _, found := a.tools.Lookup(step.Tool); !found {
+			unknown = append(unknown, fmt.Sprintf("step %d: %s", i+1, step.Tool))
+		}
+	}
+	if len(unknown) > 0 {
+		return NewToolError(ErrCodeToolNotFound, "unknown tools in the plan (%s)", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// emitPlanProgress sends a "plan-progress" event
+func (a *AIAgent) emitPlanProgress(progress PlanProgress) {
+	if a.ctx != nil && a.app.events != nil {
+		a.app.events.Emit(a.ctx, "plan-progress", progress)
+	}
+}
+
+// toolResultText returns the text of a tool result
+func toolResultText(result anthropic.ContentBlockParamUnion) string {
+	var text strings.Builder
+	if result.OfToolResult != nil {
+		for _, part := range result.OfToolResult.Content {
+			if part.OfText != nil {
+				text.WriteString(part.OfText.Text)
+			}
+		}
+	}
+	return text.String()
+}
+
+// jsonOrString embeds text in a JSON document as is when it is JSON, otherwise as a string
+func jsonOrString(text string) json.RawMessage {
+	if json.Valid([]byte(text)) {
+		return json.RawMessage(text)
+	}
+	quoted, _ := json.Marshal(text)
+	return quoted
+}
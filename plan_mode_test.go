@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// planResponse builds a submit_edit_plan call editing the text of slide 1 once per text
+func planResponse(id string, texts ...string) string {
+	var steps []string
+	for _, text := range texts {
+		steps = append(steps, fmt.Sprintf(`{"tool": "edit_slide_text", "description": "Set the title to %s", "input": %s}`, text, editSlideInput(text)))
+	}
+	return toolUseResponse(id, submitEditPlanName, fmt.Sprintf(`{"summary": "Retitle slide 1", "steps": [%s]}`, strings.Join(steps, ",")))
+}
+
+// answerPlan waits for a plan to be proposed and approves or rejects it
+func answerPlan(t *testing.T, env *testEnv, id string, approved bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, message := range env.events.Messages("edit-plan") {
+			if plan := message.(EditPlan); plan.ID == id {
+				if err := env.app.RespondToolApproval(id, approved); err != nil {
+					t.Errorf("RespondToolApproval failed: %v", err)
+				}
+				return
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Errorf("no plan %s was proposed", id)
+}
+
+// planModeEnv creates a test environment with plan mode turned on
+func planModeEnv(t *testing.T, responses ...string) *testEnv {
+	t.Setenv(visionFeedbackEnv, "0")
+	env := newTestEnv(t, responses...)
+	env.loadFixture(t, "two_slides.pptx")
+	settings := env.app.GetSettings()
+	settings.PlanMode = true
+	env.app.aiAgent.SetSettings(settings)
+	return env
+}
+
+// planStatuses returns the status of every plan-progress event in order
+func planStatuses(env *testEnv) []string {
+	var statuses []string
+	for _, message := range env.events.Messages("plan-progress") {
+		progress := message.(PlanProgress)
+		statuses = append(statuses, fmt.Sprintf("%d:%s", progress.Step, progress.Status))
+	}
+	return statuses
+}
+
+func TestApprovedPlanRunsStepByStep(t *testing.T) {
+	env := planModeEnv(t, planResponse("toolu_plan", "One", "Two"), textResponse("Done."))
+	appendingEdit(env)
+
+	go answerPlan(t, env, "toolu_plan", true)
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Retitle slide 1 twice"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	// Planning offers the read-only tools and submit_edit_plan, but no edits
+	var offered []string
+	for _, tool := range env.llm.Requests[0].Tools {
+		offered = append(offered, tool.OfTool.Name)
+	}
+	joined := strings.Join(offered, " ")
+	if !strings.Contains(joined, "read_slide") || !strings.Contains(joined, submitEditPlanName) || strings.Contains(joined, "edit_slide_text") {
+		t.Errorf("unexpected tools while planning: %v", offered)
+	}
+	if system := env.llm.Requests[0].System[0].Text; !strings.Contains(system, planModePrompt) {
+		t.Error("expected the plan mode instructions in the system prompt")
+	}
+
+	if calls := len(env.uno.Calls("uno_edit_slide.py")); calls != 2 {
+		t.Errorf("expected both steps to run, got %d edits", calls)
+	}
+	if statuses := strings.Join(planStatuses(env), " "); statuses != "1:running 1:done 2:running 2:done" {
+		t.Errorf("unexpected progress %s", statuses)
+	}
+	result := env.llm.Requests[1].Messages[2].Content[0].OfToolResult
+	if result.IsError.Value || !strings.Contains(result.Content[0].OfText.Text, `"completed_steps":2`) {
+		t.Errorf("expected a successful plan result, got %s", result.Content[0].OfText.Text)
+	}
+}
+
+func TestRejectedPlanChangesNothing(t *testing.T) {
+	env := planModeEnv(t, planResponse("toolu_plan", "One"), textResponse("What should I change?"))
+	appendingEdit(env)
+
+	go answerPlan(t, env, "toolu_plan", false)
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Retitle slide 1"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	if calls := len(env.uno.Calls("uno_edit_slide.py")); calls != 0 {
+		t.Errorf("expected no edits, got %d", calls)
+	}
+	result := env.llm.Requests[1].Messages[2].Content[0].OfToolResult
+	if !result.IsError.Value || !strings.Contains(result.Content[0].OfText.Text, string(ErrCodeUserDenied)) {
+		t.Errorf("expected the rejection to be reported, got %s", result.Content[0].OfText.Text)
+	}
+}
+
+func TestFailedPlanStepSkipsTheRest(t *testing.T) {
+	env := planModeEnv(t, planResponse("toolu_plan", "One", "Two", "Three"), textResponse("Step 2 failed."))
+	env.app.aiAgent.reviewPlans = false
+	edits := 0
+	env.uno.Handle("uno_edit_slide.py", func(args []string) ([]byte, error) {
+		edits++
+		if edits == 2 {
+			return []byte(`{"success": false, "error": "Error editing slide: Shape index 0 out of range (0-0)"}`), fmt.Errorf("exit status 1")
+		}
+		f, _ := os.OpenFile(args[0], os.O_APPEND|os.O_WRONLY, 0644)
+		f.Write([]byte{0})
+		f.Close()
+		return []byte(`{"success": true}`), nil
+	})
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Retitle slide 1 three times"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	if edits != 2 {
+		t.Errorf("expected the plan to stop at the failing step, got %d edits", edits)
+	}
+	if statuses := strings.Join(planStatuses(env), " "); statuses != "1:running 1:done 2:running 2:failed 3:skipped" {
+		t.Errorf("unexpected progress %s", statuses)
+	}
+	text := env.llm.Requests[1].Messages[2].Content[0].OfToolResult.Content[0].OfText.Text
+	if !strings.Contains(text, string(ErrCodePlanStepFailed)) || !strings.Contains(text, `"status":"skipped"`) {
+		t.Errorf("expected the failed plan to be reported, got %s", text)
+	}
+}
+
+func TestPlanModeRefusesDirectEdits(t *testing.T) {
+	env := planModeEnv(t,
+		toolUseResponse("toolu_1", "edit_slide_text", string(editSlideInput("Direct"))),
+		textResponse("I'll make a plan."),
+	)
+	appendingEdit(env)
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Retitle slide 1"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if calls := len(env.uno.Calls("uno_edit_slide.py")); calls != 0 {
+		t.Errorf("expected the direct edit to be refused, got %d edits", calls)
+	}
+}
+
+func TestPlanModeRefusesToolsThatWriteFiles(t *testing.T) {
+	env := planModeEnv(t,
+		toolUseResponse("toolu_1", "save_presentation_as", `{"output_path": "copy.pptx", "overwrite": true, "open_copy": true}`),
+		textResponse("I'll make a plan."),
+	)
+	path := env.app.presentationPath()
+	env.uno.Respond("uno_save_as.py", `{"success": true}`)
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Save a copy"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	for _, tool := range env.llm.Requests[0].Tools {
+		if name := tool.OfTool.Name; name == "save_presentation_as" || name == "extract_slides" || name == "create_presentation" {
+			t.Errorf("expected %s to be left out while planning", name)
+		}
+	}
+	if calls := len(env.uno.Calls("uno_save_as.py")); calls != 0 || env.app.presentationPath() != path {
+		t.Errorf("expected the copy to be refused, got %d saves and %s open", calls, env.app.presentationPath())
+	}
+}
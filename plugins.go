@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// pluginsDirEnv overrides where plugin tools are loaded from
+const pluginsDirEnv = "SLIDEPILOT_PLUGINS_DIR"
+
+// pluginManifestFile describes a plugin tool inside its directory
+const pluginManifestFile = "plugin.json"
+
+// pluginManifest is a plugin's plugin.json. The command runs in the plugin directory
+// once per call; it reads a pluginRequest from stdin and writes its JSON result to
+// stdout, exiting non-zero with {"error": "...", "error_code": "..."} on failure.
+type pluginManifest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	InputSchema struct {
+		Properties map[string]interface{} `json:"properties"`
+		Required   []string               `json:"required"`
+	} `json:"input_schema"`
+	Command     []string `json:"command"`
+	Mutating    bool     `json:"mutating"`
+	Destructive bool     `json:"destructive"`
+	ReadOnly    bool     `json:"read_only"` // Calls can run alongside other read-only calls
+	Screenshot  bool     `json:"screenshot"`
+	Timeout     string   `json:"timeout"` // Go duration such as "90s"; empty uses the default tool timeout
+}
+
+// pluginRequest is what a plugin command receives on stdin
+type pluginRequest struct {
+	Tool             string          `json:"tool"`
+	Input            json.RawMessage `json:"input"`
+	PresentationPath string          `json:"presentation_path"` // The loaded presentation, if any
+}
+
+// pluginsDir is where plugins are loaded from: SLIDEPILOT_PLUGINS_DIR, or plugins in the
+// user config directory
+func pluginsDir() string {
+	if dir := os.Getenv(pluginsDirEnv); dir != "" {
+		return dir
+	}
+	if configDir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(configDir, "slidepilot", "plugins")
+	}
+	return "plugins"
+}
+
+// loadPlugins registers a tool for every subdirectory of dir holding a plugin.json.
+// A broken plugin is reported and skipped so it can't keep the app from starting.
+func loadPlugins(registry *ToolRegistry, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Printf("Warning: failed to read plugins directory %s: %v\n", dir, err)
+		}
+		return nil
+	}
+
+	var loaded []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pluginDir := filepath.Join(dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(pluginDir, pluginManifestFile)); err != nil {
+			continue
+		}
+		tool, err := loadPluginTool(pluginDir)
+		if err == nil {
+			err = registry.Register(tool)
+		}
+		if err != nil {
+			fmt.Printf("Warning: skipping plugin %s: %v\n", pluginDir, err)
+			continue
+		}
+		fmt.Printf("Loaded plugin tool %s from %s\n", tool.Name, pluginDir)
+		loaded = append(loaded, tool.Name)
+	}
+	return loaded
+}
+
+// loadPluginTool builds the tool definition of the plugin in pluginDir
+func loadPluginTool(pluginDir string) (ToolDefinition, error) {
+	data, err := os.ReadFile(filepath.Join(pluginDir, pluginManifestFile))
+	if err != nil {
+		return ToolDefinition{}, err
+	}
+	var manifest pluginManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return ToolDefinition{}, fmt.Errorf("invalid %s: %v", pluginManifestFile, err)
+	}
+	if manifest.Name == "" || manifest.Description == "" {
+		return ToolDefinition{}, fmt.Errorf("%s needs a name and a description", pluginManifestFile)
+	}
+	if len(manifest.Command) == 0 {
+		return ToolDefinition{}, fmt.Errorf("%s needs a command", pluginManifestFile)
+	}
+
+	var timeout time.Duration
+	if manifest.Timeout != "" {
+		timeout, err = time.ParseDuration(manifest.Timeout)
+		if err != nil || timeout <= 0 {
+			return ToolDefinition{}, fmt.Errorf("invalid timeout %q", manifest.Timeout)
+		}
+	}
+
+	return ToolDefinition{
+		Name:        manifest.Name,
+		Description: manifest.Description,
+		InputSchema: anthropic.ToolInputSchemaParam{
+			Properties: manifest.InputSchema.Properties,
+			Required:   manifest.InputSchema.Required,
+		},
+		Function: func(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+			return runPlugin(ctx, app, pluginDir, manifest, input)
+		},
+		Mutating:    manifest.Mutating,
+		Destructive: manifest.Destructive,
+		ReadOnly:    manifest.ReadOnly && !manifest.Mutating,
+		Screenshot:  manifest.Screenshot,
+		Timeout:     timeout,
+	}, nil
+}
+
+// runPlugin runs one call of a plugin tool
+func runPlugin(ctx context.Context, app *App, pluginDir string, manifest pluginManifest, input json.RawMessage) (string, error) {
+	presentationPath := toolTargetPath(app, input)
+	request, _ := json.Marshal(pluginRequest{
+		Tool:             manifest.Name,
+		Input:            input,
+		PresentationPath: presentationPath,
+	})
+
+	cmd := exec.CommandContext(ctx, manifest.Command[0], manifest.Command[1:]...)
+	cmd.Dir = pluginDir
+	cmd.Stdin = bytes.NewReader(request)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if stderr.Len() > 0 {
+		fmt.Printf("Plugin %s: %s\n", manifest.Name, bytes.TrimSpace(stderr.Bytes()))
+	}
+	if err != nil {
+		output := stdout.Bytes()
+		if len(bytes.TrimSpace(output)) == 0 {
+			output = stderr.Bytes()
+		}
+		return "", scriptError(fmt.Sprintf("plugin %s failed", manifest.Name), err, output)
+	}
+
+	// Results must be JSON objects; mutating plugins can name the slides they changed in
+	// slide_numbers, otherwise the whole deck is re-rendered
+	var result struct {
+		SlideNumbers []int `json:"slide_numbers"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return "", NewToolError(ErrCodeScriptFailed, "invalid JSON output from plugin %s: %v", manifest.Name, err)
+	}
+	if manifest.Mutating && presentationPath != "" {
+		schedulePreviewExport(ctx, app, presentationPath, result.SlideNumbers...)
+	}
+
+	return stdout.String(), nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestToolRegistryRegisterAndUnregister(t *testing.T) {
+	registry := NewToolRegistry(builtinTools()...)
+	count := len(registry.List())
+
+	if err := registry.Register(ListSlidesDefinition); err == nil {
+		t.Error("expected registering a duplicate name to fail")
+	}
+	if err := registry.Register(ToolDefinition{Name: "no_function"}); err == nil {
+		t.Error("expected a tool without a function to be rejected")
+	}
+
+	if !registry.Unregister("list_slides") || registry.Unregister("list_slides") {
+		t.Error("expected list_slides to be unregistered exactly once")
+	}
+	if _, found := registry.Lookup("list_slides"); found {
+		t.Error("unregistered tool is still found")
+	}
+	if len(registry.List()) != count-1 {
+		t.Errorf("expected %d tools, got %d", count-1, len(registry.List()))
+	}
+}
+
+// writePlugin creates a plugin directory whose command is a shell script
+func writePlugin(t *testing.T, dir, name, manifest, script string) {
+	t.Helper()
+	pluginDir := filepath.Join(dir, name)
+	if err := os.MkdirAll(pluginDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(pluginDir, pluginManifestFile), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(pluginDir, "tool.sh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadPluginsRegistersSubprocessTools(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	dir := t.TempDir()
+
+	writePlugin(t, dir, "disclaimer", `{
+		"name": "insert_disclaimer",
+		"description": "Insert the compliance disclaimer slide",
+		"input_schema": {"properties": {"text": {"type": "string"}}, "required": ["text"]},
+		"command": ["sh", "tool.sh"],
+		"mutating": true,
+		"timeout": "30s"
+	}`, "cat > request.json\necho '{\"message\": \"Inserted\", \"slide_numbers\": [2]}'\n")
+	writePlugin(t, dir, "broken", `{"name": "broken"}`, "")
+	writePlugin(t, dir, "failing", `{"name": "always_fails", "description": "Fails", "command": ["sh", "tool.sh"]}`,
+		"echo '{\"error\": \"Slide number 9 out of range (1-2)\"}'\nexit 1\n")
+
+	registry := NewToolRegistry()
+	loaded := loadPlugins(registry, dir)
+	if strings.Join(loaded, ",") != "insert_disclaimer,always_fails" {
+		t.Fatalf("expected the two valid plugins to load, got %v", loaded)
+	}
+
+	tool, _ := registry.Lookup("insert_disclaimer")
+	if !tool.Mutating || tool.Timeout.Seconds() != 30 {
+		t.Errorf("manifest settings not applied: %+v", tool)
+	}
+	result, err := tool.Function(context.Background(), env.app, json.RawMessage(`{"text": "Not investment advice"}`))
+	if err != nil {
+		t.Fatalf("plugin call failed: %v", err)
+	}
+	if !strings.Contains(result, "Inserted") {
+		t.Errorf("unexpected result %s", result)
+	}
+
+	var request pluginRequest
+	data, _ := os.ReadFile(filepath.Join(dir, "disclaimer", "request.json"))
+	if err := json.Unmarshal(data, &request); err != nil {
+		t.Fatalf("plugin got invalid request %s: %v", data, err)
+	}
+	if request.Tool != "insert_disclaimer" || request.PresentationPath != path || string(request.Input) != `{"text":"Not investment advice"}` {
+		t.Errorf("unexpected request %+v", request)
+	}
+	env.app.exports.Flush(context.Background())
+	if len(env.converter.RangeCalls) != 1 || env.converter.RangeCalls[0] != [2]int{1, 1} {
+		t.Errorf("expected only slide 2 to be exported, got %v", env.converter.RangeCalls)
+	}
+
+	failing, _ := registry.Lookup("always_fails")
+	_, err = failing.Function(context.Background(), env.app, json.RawMessage(`{}`))
+	if code := toolErrorCode(err); code != ErrCodeSlideOutOfRange {
+		t.Errorf("expected %s, got %s (%v)", ErrCodeSlideOutOfRange, code, err)
+	}
+}
@@ -0,0 +1,498 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Review comments are read and written on the package directly. New comments use the
+// classic format (ppt/comments/commentN.xml with authors in ppt/commentAuthors.xml), which
+// LibreOffice reads and writes back, so they survive the edits of other tools. Classic
+// comments have no resolved state: resolving one removes it. Modern comments, which
+// PowerPoint 365 writes with threads and a status, are listed and resolved by setting
+// their status.
+
+const (
+	relTypeCommentAuthors = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/commentAuthors"
+	relTypeModernAuthors  = "http://schemas.microsoft.com/office/2018/10/relationships/authors"
+
+	commentsContentType       = "application/vnd.openxmlformats-officedocument.presentationml.comments+xml"
+	commentAuthorsContentType = "application/vnd.openxmlformats-officedocument.presentationml.commentAuthors+xml"
+
+	presentationNamespace = "http://schemas.openxmlformats.org/presentationml/2006/main"
+
+	// emuPerCommentUnit converts EMUs to the 1/576 inch units of classic comment positions
+	emuPerCommentUnit = 914400.0 / 576
+)
+
+// errCommentNotFound is returned for comment ids no slide has
+var errCommentNotFound = errors.New("comment not found")
+
+// pptxComment is a review comment on a slide
+type pptxComment struct {
+	ID          string             `json:"comment_id"`
+	SlideNumber int                `json:"slide_number"`
+	Author      string             `json:"author"`
+	Initials    string             `json:"initials,omitempty"`
+	Date        string             `json:"date,omitempty"`
+	Text        string             `json:"text"`
+	Resolved    bool               `json:"resolved"`
+	Replies     []pptxCommentReply `json:"replies,omitempty"`
+
+	part   string // Comments part holding the comment
+	modern bool
+	index  string // idx of a classic comment
+	author string // authorId as written
+}
+
+// pptxCommentReply is a reply in a modern comment's thread
+type pptxCommentReply struct {
+	Author string `json:"author"`
+	Date   string `json:"date,omitempty"`
+	Text   string `json:"text"`
+}
+
+// xmlCommentAuthor is an author of classic or modern comments
+type xmlCommentAuthor struct {
+	ID       string `xml:"id,attr"`
+	Name     string `xml:"name,attr"`
+	Initials string `xml:"initials,attr"`
+	LastIdx  int    `xml:"lastIdx,attr"`
+}
+
+var (
+	commentElementPattern = regexp.MustCompile(`(?s)<(\w+:)?cm\b[^>]*>.*?</(?:\w+:)?cm>`)
+	commentStartPattern   = regexp.MustCompile(`<(\w+:)?cm\b[^>]*>`)
+	commentListPattern    = regexp.MustCompile(`<(\w+:)?cmLst\b[^>]*?(/?)>`)
+	authorListEndPattern  = regexp.MustCompile(`</(\w+:)?cmAuthorLst>|<(\w+:)?cmAuthorLst\b[^>]*/>`)
+	statusAttrPattern     = regexp.MustCompile(`\sstatus="[^"]*"`)
+)
+
+// attrValue returns the value of an attribute in a start tag, "" when it is missing
+func attrValue(tag []byte, name string) string {
+	match := regexp.MustCompile(`\s` + regexp.QuoteMeta(name) + `="([^"]*)"`).FindSubmatch(tag)
+	if match == nil {
+		return ""
+	}
+	return string(match[1])
+}
+
+// partByRelType returns the first part a part links to with a relationship type, "" when
+// there is none
+func (p *pptxPackage) partByRelType(partName, relType string) (string, error) {
+	rels, err := p.rawRelationships(partName)
+	if err != nil {
+		return "", err
+	}
+	for _, rel := range rels {
+		if rel.Type == relType && rel.TargetMode != "External" {
+			return resolvePartTarget(partName, rel.Target), nil
+		}
+	}
+	return "", nil
+}
+
+// commentAuthors returns the authors of a classic or modern authors part by id
+func (p *pptxPackage) commentAuthors(relType string) (map[string]xmlCommentAuthor, error) {
+	authors := map[string]xmlCommentAuthor{}
+	part, err := p.partByRelType("ppt/presentation.xml", relType)
+	if err != nil || part == "" {
+		return authors, err
+	}
+	var list struct {
+		Authors []xmlCommentAuthor `xml:",any"`
+	}
+	if err := p.decode(part, &list); err != nil {
+		return nil, err
+	}
+	for _, author := range list.Authors {
+		authors[author.ID] = author
+	}
+	return authors, nil
+}
+
+// Comments returns the review comments of every slide, in slide order
+func (p *pptxPackage) Comments() ([]pptxComment, error) {
+	classicAuthors, err := p.commentAuthors(relTypeCommentAuthors)
+	if err != nil {
+		return nil, err
+	}
+	modernAuthors, err := p.commentAuthors(relTypeModernAuthors)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []pptxComment
+	for i, slidePart := range p.slides {
+		rels, err := p.rawRelationships(slidePart)
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range rels {
+			part := resolvePartTarget(slidePart, rel.Target)
+			var found []pptxComment
+			switch rel.Type {
+			case relTypeComments:
+				found, err = p.classicComments(part, classicAuthors)
+			case relTypeModernComments:
+				found, err = p.modernComments(part, modernAuthors)
+			default:
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			for _, comment := range found {
+				comment.SlideNumber = i + 1
+				comments = append(comments, comment)
+			}
+		}
+	}
+	return comments, nil
+}
+
+// classicComments reads a ppt/comments/commentN.xml part
+func (p *pptxPackage) classicComments(part string, authors map[string]xmlCommentAuthor) ([]pptxComment, error) {
+	var list struct {
+		Comments []struct {
+			AuthorID string `xml:"authorId,attr"`
+			Date     string `xml:"dt,attr"`
+			Index    string `xml:"idx,attr"`
+			Text     string `xml:"text"`
+		} `xml:"cm"`
+	}
+	if err := p.decode(part, &list); err != nil {
+		return nil, err
+	}
+	comments := make([]pptxComment, 0, len(list.Comments))
+	for _, cm := range list.Comments {
+		author := authors[cm.AuthorID]
+		comments = append(comments, pptxComment{
+			ID:       cm.AuthorID + "-" + cm.Index,
+			Author:   author.Name,
+			Initials: author.Initials,
+			Date:     cm.Date,
+			Text:     cm.Text,
+			part:     part,
+			index:    cm.Index,
+			author:   cm.AuthorID,
+		})
+	}
+	return comments, nil
+}
+
+// modernComments reads a ppt/comments/modernComment_*.xml part
+func (p *pptxPackage) modernComments(part string, authors map[string]xmlCommentAuthor) ([]pptxComment, error) {
+	type xmlReply struct {
+		AuthorID string      `xml:"authorId,attr"`
+		Created  string      `xml:"created,attr"`
+		TextBody xmlTextBody `xml:"txBody"`
+	}
+	var list struct {
+		Comments []struct {
+			ID       string      `xml:"id,attr"`
+			AuthorID string      `xml:"authorId,attr"`
+			Created  string      `xml:"created,attr"`
+			Status   string      `xml:"status,attr"`
+			TextBody xmlTextBody `xml:"txBody"`
+			Replies  []xmlReply  `xml:"replyLst>reply"`
+		} `xml:"cm"`
+	}
+	if err := p.decode(part, &list); err != nil {
+		return nil, err
+	}
+	comments := make([]pptxComment, 0, len(list.Comments))
+	for _, cm := range list.Comments {
+		author := authors[cm.AuthorID]
+		comment := pptxComment{
+			ID:       strings.Trim(cm.ID, "{}"),
+			Author:   author.Name,
+			Initials: author.Initials,
+			Date:     cm.Created,
+			Text:     cm.TextBody.text(),
+			Resolved: cm.Status == "resolved",
+			part:     part,
+			modern:   true,
+			author:   cm.AuthorID,
+		}
+		for _, reply := range cm.Replies {
+			comment.Replies = append(comment.Replies, pptxCommentReply{
+				Author: authors[reply.AuthorID].Name,
+				Date:   reply.Created,
+				Text:   reply.TextBody.text(),
+			})
+		}
+		comments = append(comments, comment)
+	}
+	return comments, nil
+}
+
+// addComment adds a classic comment by author to a slide at a position in EMUs and
+// returns it
+func addComment(presentationPath string, slideNumber int, author, initials, text string, x, y int64) (pptxComment, error) {
+	var added pptxComment
+	err := rewritePackage(presentationPath, func(pkg *pptxPackage) (map[string][]byte, error) {
+		slidePart, err := pkg.slidePart(slideNumber)
+		if err != nil {
+			return nil, err
+		}
+		replaced := map[string][]byte{}
+		var types contentTypes
+		if err := pkg.decode("[Content_Types].xml", &types); err != nil {
+			return nil, err
+		}
+		typesChanged := false
+
+		// The author, added to ppt/commentAuthors.xml when new, numbers the comment
+		authorsPart, err := pkg.partByRelType("ppt/presentation.xml", relTypeCommentAuthors)
+		if err != nil {
+			return nil, err
+		}
+		if authorsPart == "" {
+			authorsPart = "ppt/commentAuthors.xml"
+			replaced[authorsPart] = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+				`<p:cmAuthorLst xmlns:p="` + presentationNamespace + `"/>`)
+			if err := addRelationship(pkg, replaced, "ppt/presentation.xml", relTypeCommentAuthors, authorsPart); err != nil {
+				return nil, err
+			}
+			types.Overrides = append(types.Overrides, contentTypeOverride{PartName: "/" + authorsPart, ContentType: commentAuthorsContentType})
+			typesChanged = true
+		} else if replaced[authorsPart], err = pkg.readPart(authorsPart); err != nil {
+			return nil, err
+		}
+		authors, err := pkg.commentAuthors(relTypeCommentAuthors)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]string, 0, len(authors))
+		for id := range authors {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		authorID, index := "", 1
+		nextID := 0
+		for _, id := range ids {
+			if n, err := strconv.Atoi(id); err == nil && n >= nextID {
+				nextID = n + 1
+			}
+			if authors[id].Name == author && authorID == "" {
+				authorID, index = id, authors[id].LastIdx+1
+				initials = authors[id].Initials
+			}
+		}
+		if authorID == "" {
+			authorID = strconv.Itoa(nextID)
+			replaced[authorsPart] = withCommentAuthor(replaced[authorsPart], authorID, author, initials)
+		} else {
+			replaced[authorsPart] = withAuthorLastIdx(replaced[authorsPart], authorID, index)
+		}
+
+		comment := fmt.Sprintf(`<p:cm authorId="%s" dt="%s" idx="%d"><p:pos x="%d" y="%d"/><p:text>%s</p:text></p:cm>`,
+			authorID, time.Now().Format("2006-01-02T15:04:05.000"), index,
+			int64(math.Round(float64(x)/emuPerCommentUnit)), int64(math.Round(float64(y)/emuPerCommentUnit)), xmlAttr(text))
+
+		commentsPart, err := pkg.partByRelType(slidePart, relTypeComments)
+		if err != nil {
+			return nil, err
+		}
+		if commentsPart == "" {
+			commentsPart = freePartName("ppt/comments/comment1.xml", func(name string) bool { return pkg.parts[name] != nil })
+			replaced[commentsPart] = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+				`<p:cmLst xmlns:p="` + presentationNamespace + `">` + comment + `</p:cmLst>`)
+			if err := addRelationship(pkg, replaced, slidePart, relTypeComments, commentsPart); err != nil {
+				return nil, err
+			}
+			types.Overrides = append(types.Overrides, contentTypeOverride{PartName: "/" + commentsPart, ContentType: commentsContentType})
+			typesChanged = true
+		} else {
+			data, err := pkg.readPart(commentsPart)
+			if err != nil {
+				return nil, err
+			}
+			if replaced[commentsPart], err = withComment(data, comment); err != nil {
+				return nil, err
+			}
+		}
+		if typesChanged {
+			replaced["[Content_Types].xml"] = types.marshal()
+		}
+
+		added = pptxComment{
+			ID:          fmt.Sprintf("%s-%d", authorID, index),
+			SlideNumber: slideNumber,
+			Author:      author,
+			Initials:    initials,
+			Text:        text,
+		}
+		return replaced, nil
+	})
+	return added, err
+}
+
+// addRelationship adds a relationship from one part to another to the part's .rels,
+// taking it from replaced when it was already changed
+func addRelationship(pkg *pptxPackage, replaced map[string][]byte, fromPart, relType, toPart string) error {
+	rels, err := pkg.rawRelationships(fromPart)
+	if err != nil {
+		return err
+	}
+	used := map[string]bool{}
+	for _, rel := range rels {
+		used[rel.ID] = true
+	}
+	next := len(rels) + 1
+	for used[fmt.Sprintf("rId%d", next)] {
+		next++
+	}
+	rels = append(rels, pptxRelationship{ID: fmt.Sprintf("rId%d", next), Type: relType, Target: relativePartTarget(fromPart, toPart)})
+	replaced[relsPartName(fromPart)] = marshalRelationships(rels)
+	return nil
+}
+
+// withCommentAuthor appends an author to a commentAuthors part
+func withCommentAuthor(data []byte, id, name, initials string) []byte {
+	loc := authorListEndPattern.FindSubmatchIndex(data)
+	if loc == nil {
+		return data
+	}
+	match := data[loc[0]:loc[1]]
+	prefix := ""
+	if loc[2] >= 0 {
+		prefix = string(data[loc[2]:loc[3]])
+	} else if loc[4] >= 0 {
+		prefix = string(data[loc[4]:loc[5]])
+	}
+	author := fmt.Sprintf(`<%scmAuthor id="%s" name="%s" initials="%s" lastIdx="1" clrIdx="%s"/>`,
+		prefix, id, xmlAttr(name), xmlAttr(initials), id)
+	if bytes.HasSuffix(match, []byte("/>")) {
+		// <p:cmAuthorLst/> opens up to hold the author
+		open := string(bytes.TrimSuffix(match, []byte("/>"))) + ">"
+		return spliceBytes(data, loc[0], loc[1], []byte(open+author+"</"+prefix+"cmAuthorLst>"))
+	}
+	return spliceBytes(data, loc[0], loc[0], []byte(author))
+}
+
+// withAuthorLastIdx sets the lastIdx of an author in a commentAuthors part
+func withAuthorLastIdx(data []byte, id string, lastIdx int) []byte {
+	pattern := regexp.MustCompile(`<(?:\w+:)?cmAuthor\b[^>]*\sid="` + regexp.QuoteMeta(id) + `"[^>]*>`)
+	loc := pattern.FindIndex(data)
+	if loc == nil {
+		return data
+	}
+	tag := data[loc[0]:loc[1]]
+	lastIdxAttr := regexp.MustCompile(`\slastIdx="\d*"`)
+	updated := fmt.Sprintf(` lastIdx="%d"`, lastIdx)
+	var edited []byte
+	if lastIdxAttr.Match(tag) {
+		edited = lastIdxAttr.ReplaceAll(tag, []byte(updated))
+	} else {
+		end := len(tag) - 1
+		if bytes.HasSuffix(tag, []byte("/>")) {
+			end--
+		}
+		edited = spliceBytes(tag, end, end, []byte(updated))
+	}
+	return spliceBytes(data, loc[0], loc[1], edited)
+}
+
+// withComment appends a comment element, written with the p: prefix, to a comments part
+func withComment(data []byte, comment string) ([]byte, error) {
+	loc := commentListPattern.FindSubmatchIndex(data)
+	if loc == nil {
+		return nil, fmt.Errorf("comments part has no comment list")
+	}
+	prefix := ""
+	if loc[2] >= 0 {
+		prefix = string(data[loc[2]:loc[3]])
+	}
+	comment = strings.ReplaceAll(comment, "<p:", "<"+prefix)
+	comment = strings.ReplaceAll(comment, "</p:", "</"+prefix)
+	if loc[5] > loc[4] {
+		// A self-closing <p:cmLst/>
+		open := bytes.TrimSuffix(data[loc[0]:loc[1]], []byte("/>"))
+		return spliceBytes(data, loc[0], loc[1], []byte(string(open)+">"+comment+"</"+prefix+"cmLst>")), nil
+	}
+	end := bytes.LastIndex(data, []byte("</"+prefix+"cmLst>"))
+	if end < 0 {
+		return nil, fmt.Errorf("comments part has no end of its comment list")
+	}
+	return spliceBytes(data, end, end, []byte(comment)), nil
+}
+
+// resolveComment resolves a comment: classic comments are removed, modern ones get the
+// resolved status. slideNumber narrows the search when it is not 0.
+func resolveComment(presentationPath, commentID string, slideNumber int) (pptxComment, error) {
+	var resolved pptxComment
+	err := rewritePackage(presentationPath, func(pkg *pptxPackage) (map[string][]byte, error) {
+		comments, err := pkg.Comments()
+		if err != nil {
+			return nil, err
+		}
+		var matches []pptxComment
+		for _, comment := range comments {
+			if strings.EqualFold(comment.ID, strings.Trim(commentID, "{}")) && (slideNumber == 0 || comment.SlideNumber == slideNumber) {
+				matches = append(matches, comment)
+			}
+		}
+		switch {
+		case len(matches) == 0:
+			return nil, errCommentNotFound
+		case len(matches) > 1:
+			return nil, fmt.Errorf("comment %s is on more than one slide; give slide_number", commentID)
+		}
+		resolved = matches[0]
+
+		data, err := pkg.readPart(resolved.part)
+		if err != nil {
+			return nil, err
+		}
+		if resolved.modern {
+			data = withCommentResolved(data, resolved.ID)
+		} else {
+			data = withoutComment(data, resolved.author, resolved.index)
+		}
+		resolved.Resolved = true
+		return map[string][]byte{resolved.part: data}, nil
+	})
+	return resolved, err
+}
+
+// withoutComment removes the classic comment with an author and index
+func withoutComment(data []byte, authorID, index string) []byte {
+	for _, loc := range commentElementPattern.FindAllIndex(data, -1) {
+		start := commentStartPattern.Find(data[loc[0]:loc[1]])
+		if attrValue(start, "authorId") == authorID && attrValue(start, "idx") == index {
+			return spliceBytes(data, loc[0], loc[1], nil)
+		}
+	}
+	return data
+}
+
+// withCommentResolved sets the status of the modern comment with an id to resolved
+func withCommentResolved(data []byte, id string) []byte {
+	for _, loc := range commentStartPattern.FindAllIndex(data, -1) {
+		tag := data[loc[0]:loc[1]]
+		if !strings.EqualFold(strings.Trim(attrValue(tag, "id"), "{}"), id) {
+			continue
+		}
+		var edited []byte
+		if statusAttrPattern.Match(tag) {
+			edited = statusAttrPattern.ReplaceAll(tag, []byte(` status="resolved"`))
+		} else {
+			end := len(tag) - 1
+			if bytes.HasSuffix(tag, []byte("/>")) {
+				end--
+			}
+			edited = spliceBytes(tag, end, end, []byte(` status="resolved"`))
+		}
+		return spliceBytes(data, loc[0], loc[1], edited)
+	}
+	return data
+}
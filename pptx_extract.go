@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// extract_slides writes a copy of the package that lists only the chosen slides. Parts only
+// the other slides used (their notes, comments, pictures and media) are left out, while
+// masters, layouts and themes are kept so the copy looks like the deck.
+
+// extractResult summarizes an extraction
+type extractResult struct {
+	OutputPath string `json:"output_path"`
+	Slides     []int  `json:"slides"` // Deck slide numbers, in the copy's order
+	Dropped    int    `json:"parts_dropped"`
+}
+
+// extractSlides writes the given slides (1-based, in the order given) of the presentation
+// to outputPath as a standalone .pptx; the presentation itself is not changed
+func extractSlides(presentationPath, outputPath string, slides []int) (extractResult, error) {
+	deck, err := openPPTX(presentationPath)
+	if err != nil {
+		return extractResult{}, err
+	}
+	defer deck.Close()
+
+	kept := map[string]bool{}
+	for _, n := range slides {
+		if n < 1 || n > len(deck.slides) {
+			return extractResult{}, fmt.Errorf("%w: %d is not between 1 and %d", errSlideOutOfRange, n, len(deck.slides))
+		}
+		if kept[deck.slides[n-1]] {
+			return extractResult{}, fmt.Errorf("slide %d is listed twice", n)
+		}
+		kept[deck.slides[n-1]] = true
+	}
+	var removed []string
+	for _, part := range deck.slides {
+		if !kept[part] {
+			removed = append(removed, part)
+		}
+	}
+
+	// Rebuild the slide list in the requested order and forget the other slides' ids
+	presentationXML, err := deck.readPart("ppt/presentation.xml")
+	if err != nil {
+		return extractResult{}, err
+	}
+	match := slideIDListPattern.FindSubmatchIndex(presentationXML)
+	if match == nil {
+		return extractResult{}, fmt.Errorf("presentation.xml has no slide list")
+	}
+	list := presentationXML[match[0]:match[1]]
+	entries := slideIDPattern.FindAll(list, -1)
+	if len(entries) != len(deck.slides) {
+		return extractResult{}, fmt.Errorf("presentation.xml lists %d slides but %d were read", len(entries), len(deck.slides))
+	}
+	prefix := string(presentationXML[max(match[2], 0):max(match[3], 0)])
+	var slideList bytes.Buffer
+	fmt.Fprintf(&slideList, "<%ssldIdLst>", prefix)
+	for _, n := range slides {
+		slideList.Write(entries[n-1])
+	}
+	fmt.Fprintf(&slideList, "</%ssldIdLst>", prefix)
+	presentationXML = spliceBytes(presentationXML, match[0], match[1], slideList.Bytes())
+
+	// Sections list slides by id as well
+	for i, entry := range entries {
+		if kept[deck.slides[i]] {
+			continue
+		}
+		if id := idAttrPattern.FindSubmatch(entry); id != nil {
+			sectionEntry := regexp.MustCompile(`<(?:\w+:)?sldId\s+id="` + string(id[2]) + `"\s*/>`)
+			presentationXML = sectionEntry.ReplaceAll(presentationXML, nil)
+		}
+	}
+
+	replaced := map[string][]byte{"ppt/presentation.xml": presentationXML}
+	relsOf := map[string][]pptxRelationship{}
+	presentationRels, err := deck.rawRelationships("ppt/presentation.xml")
+	if err != nil {
+		return extractResult{}, err
+	}
+	keptRels := presentationRels[:0]
+	for _, rel := range presentationRels {
+		target := resolvePartTarget("ppt/presentation.xml", rel.Target)
+		if rel.Type == relTypeSlide && !kept[target] {
+			continue
+		}
+		keptRels = append(keptRels, rel)
+	}
+	relsOf["ppt/presentation.xml"] = keptRels
+
+	// Links from the kept slides to the others point at the slide itself
+	for part := range kept {
+		rels, err := deck.rawRelationships(part)
+		if err != nil {
+			return extractResult{}, err
+		}
+		changed := false
+		for i, rel := range rels {
+			target := resolvePartTarget(part, rel.Target)
+			if rel.Type == relTypeSlide && rel.TargetMode != "External" && !kept[target] {
+				rels[i].Target = path.Base(part)
+				changed = true
+			}
+		}
+		if changed {
+			relsOf[part] = rels
+		}
+	}
+	for part, rels := range relsOf {
+		replaced[relsPartName(part)] = marshalRelationships(rels)
+	}
+
+	// Drop whatever only the removed slides reached
+	candidates, err := deck.closure(removed)
+	if err != nil {
+		return extractResult{}, err
+	}
+	reachable, err := reachableParts(deck, relsOf)
+	if err != nil {
+		return extractResult{}, err
+	}
+	dropped := map[string]bool{}
+	for _, part := range candidates {
+		if !reachable[part] {
+			dropped[part] = true
+			dropped[relsPartName(part)] = true
+		}
+	}
+
+	var types contentTypes
+	if err := deck.decode("[Content_Types].xml", &types); err != nil {
+		return extractResult{}, err
+	}
+	overrides := types.Overrides[:0]
+	for _, override := range types.Overrides {
+		if !dropped[strings.TrimPrefix(override.PartName, "/")] {
+			overrides = append(overrides, override)
+		}
+	}
+	types.Overrides = overrides
+	replaced["[Content_Types].xml"] = types.marshal()
+
+	// Write next to the output and rename, so a failed write leaves no half-written file
+	tempFile, err := os.CreateTemp(filepath.Dir(outputPath), ".slidepilot-extract-*.pptx")
+	if err != nil {
+		return extractResult{}, fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	tempPath := tempFile.Name()
+	err = writeMergedPackage(tempFile, deck, nil, nil, nil, replaced, dropped)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tempPath, outputPath)
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return extractResult{}, fmt.Errorf("failed to write %s: %v", filepath.Base(outputPath), err)
+	}
+
+	droppedParts := 0
+	for part := range dropped {
+		if _, ok := deck.parts[part]; ok && !strings.HasSuffix(part, ".rels") {
+			droppedParts++
+		}
+	}
+	return extractResult{OutputPath: outputPath, Slides: slides, Dropped: droppedParts}, nil
+}
+
+// reachableParts returns the parts of a package reachable from its root relationships,
+// using relsOf in place of the relationships of the parts it has
+func reachableParts(pkg *pptxPackage, relsOf map[string][]pptxRelationship) (map[string]bool, error) {
+	reachable := map[string]bool{}
+	var reach func(part string) error
+	reach = func(part string) error {
+		if reachable[part] {
+			return nil
+		}
+		reachable[part] = true
+		rels, ok := relsOf[part]
+		if !ok {
+			var err error
+			if rels, err = pkg.rawRelationships(part); err != nil {
+				return err
+			}
+		}
+		for _, rel := range rels {
+			target := resolvePartTarget(part, rel.Target)
+			if _, inPackage := pkg.parts[target]; inPackage && rel.TargetMode != "External" {
+				if err := reach(target); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	rootRels, err := pkg.rawRelationships("")
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range rootRels {
+		if rel.TargetMode != "External" {
+			if err := reach(resolvePartTarget("", rel.Target)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return reachable, nil
+}
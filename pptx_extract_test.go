@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractSlidesKeepsOnlyChosenSlides(t *testing.T) {
+	dir := t.TempDir()
+	deckPath := filepath.Join(dir, "deck.pptx")
+	if err := copyFile(filepath.Join("testdata", "two_slides.pptx"), deckPath); err != nil {
+		t.Fatal(err)
+	}
+	original, err := os.ReadFile(deckPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Slides come out in the order asked for
+	reorderedPath := filepath.Join(dir, "reordered.pptx")
+	if _, err := extractSlides(deckPath, reorderedPath, []int{2, 1}); err != nil {
+		t.Fatalf("extractSlides failed: %v", err)
+	}
+	reordered, err := openPPTX(reorderedPath)
+	if err != nil {
+		t.Fatalf("extracted deck does not open: %v", err)
+	}
+	defer reordered.Close()
+	if shapes, err := reordered.SlideShapes(2); err != nil || slideTitle(shapes) != "Quarterly Review" {
+		t.Errorf("expected the first slide last, got %q (%v)", slideTitle(shapes), err)
+	}
+
+	singlePath := filepath.Join(dir, "single.pptx")
+	result, err := extractSlides(deckPath, singlePath, []int{2})
+	if err != nil {
+		t.Fatalf("extractSlides failed: %v", err)
+	}
+	if result.Dropped != 1 {
+		t.Errorf("expected the other slide to be dropped, got %+v", result)
+	}
+	single, err := openPPTX(singlePath)
+	if err != nil {
+		t.Fatalf("extracted deck does not open: %v", err)
+	}
+	defer single.Close()
+	if single.SlideCount() != 1 || single.slides[0] != "ppt/slides/slide2.xml" {
+		t.Errorf("expected only slide2.xml, got %v", single.slides)
+	}
+	if _, ok := single.parts["ppt/slides/slide1.xml"]; ok {
+		t.Error("expected slide1.xml to be left out")
+	}
+	if _, override := lookupContentType(t, single, "ppt/slides/slide1.xml"); override {
+		t.Error("expected the dropped slide's content type override to be removed")
+	}
+	layouts, err := single.Layouts()
+	if err != nil || len(layouts) != 1 || fmt.Sprint(layouts[0].Slides) != "[1]" {
+		t.Errorf("expected the deck's layout to be kept, got %+v (%v)", layouts, err)
+	}
+
+	// The presentation itself is untouched
+	if after, _ := os.ReadFile(deckPath); string(after) != string(original) {
+		t.Error("extractSlides changed the presentation")
+	}
+
+	if _, err := extractSlides(deckPath, filepath.Join(dir, "bad.pptx"), []int{3}); !errors.Is(err, errSlideOutOfRange) {
+		t.Errorf("expected errSlideOutOfRange, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "bad.pptx")); !os.IsNotExist(err) {
+		t.Error("a failed extraction should not leave a file behind")
+	}
+}
+
+func TestExtractSlidesTool(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+
+	result := env.app.aiAgent.executeTool(context.Background(), "toolu_1", "extract_slides", []byte(`{"slides": [1, 2]}`))
+	if result.OfToolResult.IsError.Value {
+		t.Fatalf("extract_slides failed: %s", result.OfToolResult.Content[0].OfText.Text)
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if _, err := os.Stat(filepath.Join(filepath.Dir(path), name+" (slides 1-2).pptx")); err != nil {
+		t.Errorf("expected the copy next to the presentation: %v", err)
+	}
+
+	// The default name is taken now
+	result = env.app.aiAgent.executeTool(context.Background(), "toolu_2", "extract_slides", []byte(`{"slides": [1, 2]}`))
+	if !result.OfToolResult.IsError.Value || !strings.Contains(result.OfToolResult.Content[0].OfText.Text, "already exists") {
+		t.Errorf("expected an existing file to be refused, got %s", result.OfToolResult.Content[0].OfText.Text)
+	}
+	result = env.app.aiAgent.executeTool(context.Background(), "toolu_3", "extract_slides", []byte(`{"slides": [5], "output_path": "five.pptx"}`))
+	if !result.OfToolResult.IsError.Value || !strings.Contains(result.OfToolResult.Content[0].OfText.Text, string(ErrCodeSlideOutOfRange)) {
+		t.Errorf("expected SLIDE_OUT_OF_RANGE, got %s", result.OfToolResult.Content[0].OfText.Text)
+	}
+}
+
+func TestSlideListLabel(t *testing.T) {
+	for _, tc := range []struct {
+		slides []int
+		want   string
+	}{
+		{[]int{3}, "slide 3"},
+		{[]int{4, 5, 6, 7, 8, 9}, "slides 4-9"},
+		{[]int{1, 3, 5, 6, 7}, "slides 1, 3, 5-7"},
+		{[]int{2, 1}, "slides 2, 1"},
+	} {
+		if got := slideListLabel(tc.slides); got != tc.want {
+			t.Errorf("slideListLabel(%v) = %q, want %q", tc.slides, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,488 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// import_slides works on the packages directly as well: LibreOffice only copies slides
+// between documents through the clipboard of a visible window. The chosen slides are
+// copied with every part they use (pictures, charts, media, notes) under names free in the
+// deck and listed in presentation.xml at the requested position. Each slide is moved onto
+// the deck layout matching its own, or keeps its source layout, master and theme, which are
+// then copied as well.
+
+const (
+	relTypeSlide          = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/slide"
+	relTypeNotesSlide     = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/notesSlide"
+	relTypeNotesMaster    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/notesMaster"
+	relTypeComments       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/comments"
+	relTypeModernComments = "http://schemas.microsoft.com/office/2018/10/relationships/comments"
+)
+
+var (
+	slideIDListPattern = regexp.MustCompile(`(?s)<(\w+:)?sldIdLst\b(?:/>|.*?</(?:\w+:)?sldIdLst>)`)
+	slideIDPattern     = regexp.MustCompile(`(?s)<(?:\w+:)?sldId\b[^>]*?(?:/>|>.*?</(?:\w+:)?sldId>)`)
+	slideSizePattern   = regexp.MustCompile(`<(?:\w+:)?sldSz\b[^>]*?\scx="(\d+)"[^>]*?\scy="(\d+)"`)
+	slideSizeTagStart  = regexp.MustCompile(`<(?:\w+:)?sldSz\b`)
+	// idAttrPattern matches the numeric id attribute of slide, master and layout list
+	// entries; r:id is preceded by a colon rather than a space, so it doesn't match
+	idAttrPattern       = regexp.MustCompile(`(\sid=")(\d+)(")`)
+	masterIDPattern     = regexp.MustCompile(`<(?:\w+:)?sldMasterId\b[^>]*>`)
+	layoutIDPattern     = regexp.MustCompile(`<(?:\w+:)?sldLayoutId\b[^>]*>`)
+	masterIDListClosing = regexp.MustCompile(`</(?:\w+:)?sldMasterIdLst>`)
+)
+
+// importedSlide records where a source slide ended up and on which layout
+type importedSlide struct {
+	SourceSlide int    `json:"source_slide"`
+	Slide       int    `json:"slide"`
+	Layout      string `json:"layout"`
+}
+
+// importResult summarizes an import
+type importResult struct {
+	FirstSlide  int             `json:"first_slide"`
+	Imported    int             `json:"imported"`
+	TotalSlides int             `json:"total_slides"`
+	Slides      []importedSlide `json:"slides"`
+	Masters     int             `json:"masters_added,omitempty"` // Source masters copied to keep the formatting
+	Warnings    []string        `json:"warnings,omitempty"`
+}
+
+// importSlides copies slides from through to (1-based, inclusive) of the source .pptx into
+// the presentation, so that the first becomes slide number position (0 appends them).
+// With keepFormatting the slides keep their source layouts, masters and themes; otherwise
+// they move onto the deck layout matching theirs like apply_template does.
+func importSlides(presentationPath, sourcePath string, from, to, position int, keepFormatting bool) (importResult, error) {
+	tempPath, result, err := writeImportedCopy(presentationPath, sourcePath, from, to, position, keepFormatting)
+	if err != nil {
+		return importResult{}, err
+	}
+	if err := os.Rename(tempPath, presentationPath); err != nil {
+		os.Remove(tempPath)
+		return importResult{}, fmt.Errorf("failed to replace presentation: %v", err)
+	}
+	return result, nil
+}
+
+// writeImportedCopy writes the deck with the slides imported to a temporary file next to
+// it and returns that file's path
+func writeImportedCopy(presentationPath, sourcePath string, from, to, position int, keepFormatting bool) (string, importResult, error) {
+	deck, err := openPPTX(presentationPath)
+	if err != nil {
+		return "", importResult{}, err
+	}
+	defer deck.Close()
+	source, err := openPPTX(sourcePath)
+	if err != nil {
+		return "", importResult{}, fmt.Errorf("source: %w", err)
+	}
+	defer source.Close()
+
+	if to == 0 {
+		to = source.SlideCount()
+	}
+	if from < 1 || to < from || to > source.SlideCount() {
+		return "", importResult{}, fmt.Errorf("%w: slides %d-%d are not within the source's 1-%d", errSlideOutOfRange, from, to, source.SlideCount())
+	}
+	index := len(deck.slides)
+	if position > 0 && position <= len(deck.slides) {
+		index = position - 1
+	}
+
+	deckLayouts, err := deck.Layouts()
+	if err != nil {
+		return "", importResult{}, err
+	}
+	if len(deckLayouts) == 0 && !keepFormatting {
+		return "", importResult{}, fmt.Errorf("the presentation has no slide layouts; keep the source formatting instead")
+	}
+	sourceLayouts, err := source.Layouts()
+	if err != nil {
+		return "", importResult{}, fmt.Errorf("source: %w", err)
+	}
+	layoutOf := map[int]pptxLayout{}
+	for _, layout := range sourceLayouts {
+		for _, slideNumber := range layout.Slides {
+			layoutOf[slideNumber] = layout
+		}
+	}
+
+	presentationRels, err := deck.rawRelationships("ppt/presentation.xml")
+	if err != nil {
+		return "", importResult{}, err
+	}
+	notesMaster := ""
+	for _, rel := range presentationRels {
+		if rel.Type == relTypeNotesMaster {
+			notesMaster = resolvePartTarget("ppt/presentation.xml", rel.Target)
+		}
+	}
+
+	// Work out the parts to copy and give each a name that is free in the deck
+	selected := map[string]bool{}
+	for n := from; n <= to; n++ {
+		selected[source.slides[n-1]] = true
+	}
+	imported := map[string]string{}
+	var copied []string
+	assigned := map[string]bool{}
+	taken := func(name string) bool {
+		_, inDeck := deck.parts[name]
+		return inDeck || assigned[name]
+	}
+	var walk func(part string) error
+	walk = func(part string) error {
+		if _, done := imported[part]; done {
+			return nil
+		}
+		name := freePartName(part, taken)
+		assigned[name] = true
+		imported[part] = name
+		copied = append(copied, part)
+
+		rels, err := source.rawRelationships(part)
+		if err != nil {
+			return err
+		}
+		for _, rel := range rels {
+			target := resolvePartTarget(part, rel.Target)
+			if _, ok := source.parts[target]; !ok || rel.TargetMode == "External" {
+				continue
+			}
+			switch rel.Type {
+			case relTypeSlide, relTypeNotesSlide, relTypeNotesMaster, relTypeComments, relTypeModernComments:
+				// Other slides, notes and comments are handled separately or dropped
+				continue
+			case relTypeSlideLayout:
+				if selected[part] && !keepFormatting {
+					continue
+				}
+			}
+			if err := walk(target); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var warnings []string
+	notesDropped := false
+	for n := from; n <= to; n++ {
+		slidePart := source.slides[n-1]
+		if err := walk(slidePart); err != nil {
+			return "", importResult{}, fmt.Errorf("source: %w", err)
+		}
+		rels, err := source.rawRelationships(slidePart)
+		if err != nil {
+			return "", importResult{}, fmt.Errorf("source: %w", err)
+		}
+		for _, rel := range rels {
+			if rel.Type != relTypeNotesSlide {
+				continue
+			}
+			if notesMaster == "" {
+				notesDropped = true
+				continue
+			}
+			if err := walk(resolvePartTarget(slidePart, rel.Target)); err != nil {
+				return "", importResult{}, fmt.Errorf("source: %w", err)
+			}
+		}
+	}
+	if notesDropped {
+		warnings = append(warnings, "speaker notes were not imported because the presentation has no notes master")
+	}
+
+	// Point the copies' relationships at the copied parts and the deck's layouts
+	result := importResult{FirstSlide: index + 1, Imported: to - from + 1, TotalSlides: len(deck.slides) + to - from + 1}
+	layoutFor := map[string]pptxLayout{}
+	for n := from; n <= to; n++ {
+		layout := layoutOf[n]
+		if !keepFormatting {
+			layout = matchTemplateLayout(deckLayouts, layout)
+		}
+		layoutFor[source.slides[n-1]] = layout
+		result.Slides = append(result.Slides, importedSlide{SourceSlide: n, Slide: index + 1 + n - from, Layout: layout.Name})
+	}
+
+	replaced := map[string][]byte{}
+	for _, part := range copied {
+		rels, err := source.rawRelationships(part)
+		if err != nil {
+			return "", importResult{}, fmt.Errorf("source: %w", err)
+		}
+		if rels == nil {
+			continue
+		}
+		newPart := imported[part]
+		kept := rels[:0]
+		for _, rel := range rels {
+			target := resolvePartTarget(part, rel.Target)
+			switch {
+			case rel.TargetMode == "External":
+			case rel.Type == relTypeComments || rel.Type == relTypeModernComments:
+				// Comment authors are listed per presentation; the comments stay behind
+				continue
+			case rel.Type == relTypeSlideLayout && selected[part] && !keepFormatting:
+				rel.Target = relativePartTarget(newPart, layoutFor[part].part)
+			case rel.Type == relTypeNotesMaster:
+				rel.Target = relativePartTarget(newPart, notesMaster)
+			case rel.Type == relTypeSlide:
+				// Links to slides that weren't imported point at the slide itself
+				linked, ok := imported[target]
+				if !ok {
+					linked = imported[source.slides[from-1]]
+					if selected[part] {
+						linked = newPart
+					}
+				}
+				rel.Target = relativePartTarget(newPart, linked)
+			default:
+				newTarget, ok := imported[target]
+				if !ok {
+					if _, inSource := source.parts[target]; inSource {
+						// Notes left behind
+						continue
+					}
+					break
+				}
+				rel.Target = relativePartTarget(newPart, newTarget)
+			}
+			kept = append(kept, rel)
+		}
+		replaced[relsPartName(newPart)] = marshalRelationships(kept)
+	}
+
+	// List the new slides, and any copied masters, in presentation.xml
+	presentationXML, err := deck.readPart("ppt/presentation.xml")
+	if err != nil {
+		return "", importResult{}, err
+	}
+	relPrefix := relPrefixPattern.FindSubmatch(presentationXML)
+	if relPrefix == nil {
+		return "", importResult{}, fmt.Errorf("presentation.xml declares no relationships namespace")
+	}
+	usedRelIDs := map[string]bool{}
+	for _, rel := range presentationRels {
+		usedRelIDs[rel.ID] = true
+	}
+	nextRel := 1
+	newRelID := func() string {
+		for usedRelIDs[fmt.Sprintf("rId%d", nextRel)] {
+			nextRel++
+		}
+		id := fmt.Sprintf("rId%d", nextRel)
+		usedRelIDs[id] = true
+		return id
+	}
+
+	prefix := "p:"
+	var slideEntries [][]byte
+	nextSlideID := 256
+	if match := slideIDListPattern.FindSubmatch(presentationXML); match != nil {
+		prefix = string(match[1])
+		slideEntries = slideIDPattern.FindAll(match[0], -1)
+	}
+	for _, entry := range slideEntries {
+		if id := idAttrPattern.FindSubmatch(entry); id != nil {
+			if n, _ := strconv.Atoi(string(id[2])); n >= nextSlideID {
+				nextSlideID = n + 1
+			}
+		}
+	}
+	var newEntries [][]byte
+	var newIDs []string
+	for n := from; n <= to; n++ {
+		relID := newRelID()
+		presentationRels = append(presentationRels, pptxRelationship{
+			ID:     relID,
+			Type:   relTypeSlide,
+			Target: relativePartTarget("ppt/presentation.xml", imported[source.slides[n-1]]),
+		})
+		newEntries = append(newEntries, []byte(fmt.Sprintf(`<%ssldId id="%d" %s:id="%s"/>`, prefix, nextSlideID, relPrefix[1], relID)))
+		newIDs = append(newIDs, strconv.Itoa(nextSlideID))
+		nextSlideID++
+	}
+	var slideList bytes.Buffer
+	fmt.Fprintf(&slideList, "<%ssldIdLst>", prefix)
+	for i, entry := range slideEntries {
+		if i == index {
+			slideList.Write(bytes.Join(newEntries, nil))
+		}
+		slideList.Write(entry)
+	}
+	if index >= len(slideEntries) {
+		slideList.Write(bytes.Join(newEntries, nil))
+	}
+	fmt.Fprintf(&slideList, "</%ssldIdLst>", prefix)
+
+	if loc := slideIDListPattern.FindIndex(presentationXML); loc != nil {
+		presentationXML = spliceBytes(presentationXML, loc[0], loc[1], slideList.Bytes())
+	} else if loc := slideSizeTagStart.FindIndex(presentationXML); loc != nil {
+		// A deck without slides may have no list at all; it goes right before the slide size
+		presentationXML = spliceBytes(presentationXML, loc[0], loc[0], slideList.Bytes())
+	} else {
+		return "", importResult{}, fmt.Errorf("presentation.xml has no slide list")
+	}
+
+	// The new slides join the section of the slide before them
+	if sections, ok := parseSections(presentationXML); ok && len(sections) > 0 {
+		after := ""
+		if index > 0 {
+			after = slideIDsOf(presentationXML)[index-1]
+		}
+		presentationXML = withSections(presentationXML, placeInSections(sections, after, newIDs))
+	}
+
+	if keepFormatting {
+		sourceMasters, err := source.masterRefs()
+		if err != nil {
+			return "", importResult{}, fmt.Errorf("source: %w", err)
+		}
+		nextID, err := nextMasterID(deck, presentationXML)
+		if err != nil {
+			return "", importResult{}, err
+		}
+		var masterEntries bytes.Buffer
+		for _, master := range sourceMasters {
+			newPart, ok := imported[master.Part]
+			if !ok {
+				continue
+			}
+			// Master and layout ids share one number space across the presentation
+			masterXML, err := source.readPart(master.Part)
+			if err != nil {
+				return "", importResult{}, fmt.Errorf("source: %w", err)
+			}
+			replaced[newPart] = layoutIDPattern.ReplaceAllFunc(masterXML, func(entry []byte) []byte {
+				entry = idAttrPattern.ReplaceAll(entry, []byte(fmt.Sprintf("${1}%d${3}", nextID)))
+				nextID++
+				return entry
+			})
+
+			relID := newRelID()
+			presentationRels = append(presentationRels, pptxRelationship{
+				ID:     relID,
+				Type:   relTypeSlideMaster,
+				Target: relativePartTarget("ppt/presentation.xml", newPart),
+			})
+			fmt.Fprintf(&masterEntries, `<%ssldMasterId id="%d" %s:id="%s"/>`, prefix, nextID, relPrefix[1], relID)
+			nextID++
+			result.Masters++
+		}
+		if masterEntries.Len() > 0 {
+			loc := masterIDListClosing.FindIndex(presentationXML)
+			if loc == nil {
+				return "", importResult{}, fmt.Errorf("presentation.xml has no slide master list")
+			}
+			presentationXML = spliceBytes(presentationXML, loc[0], loc[0], masterEntries.Bytes())
+		}
+	}
+	replaced["ppt/presentation.xml"] = presentationXML
+	replaced[relsPartName("ppt/presentation.xml")] = marshalRelationships(presentationRels)
+
+	if deckSize, sourceSize := slideSize(deck), slideSize(source); deckSize != sourceSize && deckSize != "" && sourceSize != "" {
+		warnings = append(warnings, fmt.Sprintf("the source slides are %s and the presentation's %s; check that the imported content still fits", sourceSize, deckSize))
+	}
+	result.Warnings = warnings
+
+	// Register the new parts' content types
+	var deckTypes, sourceTypes contentTypes
+	if err := deck.decode("[Content_Types].xml", &deckTypes); err != nil {
+		return "", importResult{}, err
+	}
+	if err := source.decode("[Content_Types].xml", &sourceTypes); err != nil {
+		return "", importResult{}, fmt.Errorf("source: %w", err)
+	}
+	for _, part := range copied {
+		contentType, isOverride := sourceTypes.lookup(part)
+		if contentType == "" {
+			continue
+		}
+		if existing, _ := deckTypes.lookup(imported[part]); !isOverride && existing == contentType {
+			continue
+		}
+		if isOverride {
+			deckTypes.Overrides = append(deckTypes.Overrides, contentTypeOverride{"/" + imported[part], contentType})
+		} else {
+			deckTypes.Defaults = append(deckTypes.Defaults, contentTypeDefault{strings.TrimPrefix(path.Ext(part), "."), contentType})
+		}
+	}
+	replaced["[Content_Types].xml"] = deckTypes.marshal()
+
+	tempFile, err := os.CreateTemp(filepath.Dir(presentationPath), ".slidepilot-import-*.pptx")
+	if err != nil {
+		return "", importResult{}, fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	tempPath := tempFile.Name()
+	err = writeMergedPackage(tempFile, deck, source, copied, imported, replaced, nil)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return "", importResult{}, fmt.Errorf("failed to write presentation: %v", err)
+	}
+	return tempPath, result, nil
+}
+
+// nextMasterID returns the first id above every slide master and layout id of the deck
+func nextMasterID(deck *pptxPackage, presentationXML []byte) (int, error) {
+	next := 2147483648
+	bump := func(entry []byte) {
+		if id := idAttrPattern.FindSubmatch(entry); id != nil {
+			if n, _ := strconv.Atoi(string(id[2])); n >= next {
+				next = n + 1
+			}
+		}
+	}
+	for _, entry := range masterIDPattern.FindAll(presentationXML, -1) {
+		bump(entry)
+	}
+	masters, err := deck.masterRefs()
+	if err != nil {
+		return 0, err
+	}
+	for _, master := range masters {
+		masterXML, err := deck.readPart(master.Part)
+		if err != nil {
+			return 0, err
+		}
+		for _, entry := range layoutIDPattern.FindAll(masterXML, -1) {
+			bump(entry)
+		}
+	}
+	return next, nil
+}
+
+// slideSize returns the slide size of a package in inches, e.g. "13.33x7.5 in", or ""
+// when presentation.xml doesn't give one
+func slideSize(pkg *pptxPackage) string {
+	presentationXML, err := pkg.readPart("ppt/presentation.xml")
+	if err != nil {
+		return ""
+	}
+	match := slideSizePattern.FindSubmatch(presentationXML)
+	if match == nil {
+		return ""
+	}
+	cx, _ := strconv.Atoi(string(match[1]))
+	cy, _ := strconv.Atoi(string(match[2]))
+	return fmt.Sprintf("%sx%s in", strconv.FormatFloat(float64(cx)/emuPerInch, 'f', -1, 64), strconv.FormatFloat(float64(cy)/emuPerInch, 'f', 2, 64))
+}
+
+// spliceBytes returns data with data[start:end] replaced by insert
+func spliceBytes(data []byte, start, end int, insert []byte) []byte {
+	spliced := make([]byte, 0, len(data)-(end-start)+len(insert))
+	spliced = append(spliced, data[:start]...)
+	spliced = append(spliced, insert...)
+	return append(spliced, data[end:]...)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImportSlidesUsesDeckLayouts(t *testing.T) {
+	dir := t.TempDir()
+	deckPath := filepath.Join(dir, "deck.pptx")
+	if err := copyFile(filepath.Join("testdata", "two_slides.pptx"), deckPath); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := importSlides(deckPath, filepath.Join("testdata", "two_slides.pptx"), 1, 0, 2, false)
+	if err != nil {
+		t.Fatalf("importSlides failed: %v", err)
+	}
+	if result.FirstSlide != 2 || result.Imported != 2 || result.TotalSlides != 4 || result.Masters != 0 || len(result.Warnings) != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(result.Slides) != 2 || result.Slides[1].SourceSlide != 2 || result.Slides[1].Slide != 3 || result.Slides[1].Layout != "Title and Content" {
+		t.Errorf("unexpected slides: %+v", result.Slides)
+	}
+
+	pkg, err := openPPTX(deckPath)
+	if err != nil {
+		t.Fatalf("merged deck does not open: %v", err)
+	}
+	defer pkg.Close()
+
+	// The copies sit between the original slides and use the deck's only layout
+	titles := make([]string, pkg.SlideCount())
+	for i := range titles {
+		shapes, err := pkg.SlideShapes(i + 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		titles[i] = slideTitle(shapes)
+	}
+	if titles[0] != titles[1] || titles[0] != "Quarterly Review" || titles[2] != titles[3] {
+		t.Errorf("expected the imported slides at 2-3, got titles %q", titles)
+	}
+	layouts, err := pkg.Layouts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layouts) != 1 || fmt.Sprint(layouts[0].Slides) != "[1 2 3 4]" {
+		t.Errorf("expected every slide on the deck's layout, got %+v", layouts)
+	}
+	for _, part := range []string{"ppt/slides/slide3.xml", "ppt/slides/slide4.xml"} {
+		if contentType, override := lookupContentType(t, pkg, part); !override || !strings.HasSuffix(contentType, "slide+xml") {
+			t.Errorf("expected a slide override for %s, got %q", part, contentType)
+		}
+	}
+	if _, ok := pkg.parts["ppt/slideMasters/slideMaster2.xml"]; ok {
+		t.Error("the source master should not be copied")
+	}
+
+	if _, err := importSlides(deckPath, filepath.Join("testdata", "two_slides.pptx"), 2, 3, 0, false); !errors.Is(err, errSlideOutOfRange) {
+		t.Errorf("expected errSlideOutOfRange, got %v", err)
+	}
+}
+
+func TestImportSlidesKeepsSourceFormatting(t *testing.T) {
+	dir := t.TempDir()
+	deckPath := filepath.Join(dir, "deck.pptx")
+	sourcePath := filepath.Join(dir, "branded.pptx")
+	for _, path := range []string{deckPath, sourcePath} {
+		if err := copyFile(filepath.Join("testdata", "two_slides.pptx"), path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := applyTemplate(sourcePath, filepath.Join("testdata", "template.potx")); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := importSlides(deckPath, sourcePath, 2, 2, 0, true)
+	if err != nil {
+		t.Fatalf("importSlides failed: %v", err)
+	}
+	if result.FirstSlide != 3 || result.Imported != 1 || result.Masters != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	pkg, err := openPPTX(deckPath)
+	if err != nil {
+		t.Fatalf("merged deck does not open: %v", err)
+	}
+	defer pkg.Close()
+
+	masters, err := pkg.masterRefs()
+	if err != nil || len(masters) != 2 || masters[0].ID != "2147483648" || masters[1].ID != "2147483652" {
+		t.Fatalf("expected the source master after the deck's with a fresh id, got %+v (%v)", masters, err)
+	}
+	masterXML, err := pkg.readPart(masters[1].Part)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(masterXML), `<p:sldLayoutId id="2147483650" r:id="rId1"/><p:sldLayoutId id="2147483651" r:id="rId2"/>`) {
+		t.Errorf("expected the copied master's layouts to be renumbered, got %s", masterXML)
+	}
+
+	layouts, err := pkg.Layouts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layouts) != 3 || fmt.Sprint(layouts[0].Slides) != "[1 2]" || layouts[2].Name != "Title and Content" || fmt.Sprint(layouts[2].Slides) != "[3]" {
+		t.Errorf("expected the imported slide on the source layout, got %+v", layouts)
+	}
+	// The source master's logo comes along under a free name
+	if contentType, _ := lookupContentType(t, pkg, "ppt/media/image1.png"); contentType != "image/png" {
+		t.Errorf("expected the master's picture to be copied, got content type %q", contentType)
+	}
+}
+
+func TestImportSlidesTool(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	if _, err := convertSlides(context.Background(), env.app, path, "slides"); err != nil {
+		t.Fatal(err)
+	}
+	env.converter.Calls = nil
+
+	result := env.app.aiAgent.executeTool(context.Background(), "toolu_1", "import_slides",
+		[]byte(`{"source_path": "`+filepath.ToSlash(filepath.Join(fixtureDir, "mixed_shapes.pptx"))+`", "position": 1}`))
+	if result.OfToolResult.IsError.Value {
+		t.Fatalf("import_slides failed: %s", result.OfToolResult.Content[0].OfText.Text)
+	}
+	if text := result.OfToolResult.Content[0].OfText.Text; !strings.Contains(text, `"first_slide":1`) {
+		t.Errorf("expected the slides at the front, got %s", text)
+	}
+
+	pkg, err := openPPTX(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := pkg.SlideCount()
+	pkg.Close()
+	if total != 3 {
+		t.Fatalf("expected the source's slide to be added, got %d slides", total)
+	}
+	// The previews are shifted rather than all exported again
+	if len(env.converter.Calls) != 0 || len(env.converter.RangeCalls) != 1 || env.converter.RangeCalls[0] != [2]int{0, 2} {
+		t.Errorf("expected a range render from the new slide, got %v and %v", env.converter.Calls, env.converter.RangeCalls)
+	}
+	if countSlidePreviews("slides") != 3 {
+		t.Errorf("expected 3 previews, got %d", countSlidePreviews("slides"))
+	}
+
+	result = env.app.aiAgent.executeTool(context.Background(), "toolu_2", "import_slides", []byte(`{"source_path": "missing.pptx"}`))
+	if !result.OfToolResult.IsError.Value || !strings.Contains(result.OfToolResult.Content[0].OfText.Text, string(ErrCodeFileNotFound)) {
+		t.Errorf("expected a missing source to be reported, got %s", result.OfToolResult.Content[0].OfText.Text)
+	}
+}
+
+// lookupContentType returns a part's content type from the package's [Content_Types].xml
+func lookupContentType(t *testing.T, pkg *pptxPackage, part string) (string, bool) {
+	t.Helper()
+	var types contentTypes
+	if err := pkg.decode("[Content_Types].xml", &types); err != nil {
+		t.Fatal(err)
+	}
+	return types.lookup(part)
+}
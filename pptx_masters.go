@@ -0,0 +1,545 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Slide masters and layouts are read and edited in the package directly, like
+// apply_template: LibreOffice turns every layout a deck uses into a master page of its
+// own when it imports the deck, so UNO has no single master to edit. Text, positions and
+// text styles are changed in the master and layout XML, and slides pick the changes up
+// wherever they don't override them.
+
+var (
+	errNoTextBody   = errors.New("shape has no text")
+	errTextHasField = errors.New("shape shows a slide number or date field")
+)
+
+var (
+	txBodyPattern       = regexp.MustCompile(`(?s)(<(?:\w+:)?txBody\b[^>]*>)(.*?)(</(?:\w+:)?txBody>)`)
+	paragraphPattern    = regexp.MustCompile(`(?s)<(?:\w+:)?p\b[^>]*?(?:/>|>.*?</(?:\w+:)?p>)`)
+	paraPropsPattern    = regexp.MustCompile(`(?s)<(?:\w+:)?pPr\b[^>]*?(?:/>|>.*?</(?:\w+:)?pPr>)`)
+	textRunPropsPattern = regexp.MustCompile(`(?s)<(?:\w+:)?rPr\b[^>]*?(?:/>|>.*?</(?:\w+:)?rPr>)`)
+	endParaPropsPattern = regexp.MustCompile(`(?s)<(?:\w+:)?endParaRPr\b[^>]*?(?:/>|>.*?</(?:\w+:)?endParaRPr>)`)
+	runPropsPattern     = regexp.MustCompile(`(?s)<(?:\w+:)?(?:rPr|endParaRPr|defRPr)\b[^>]*?(?:/>|>.*?</(?:\w+:)?(?:rPr|endParaRPr|defRPr)>)`)
+	fieldPattern        = regexp.MustCompile(`<(?:\w+:)?fld\b`)
+	listStylePattern    = regexp.MustCompile(`(?s)<(?:\w+:)?lstStyle\b[^>]*?(?:/>|>.*?</(?:\w+:)?lstStyle>)`)
+	levelPropsPattern   = regexp.MustCompile(`(?s)<(?:\w+:)?lvl\dpPr\b[^>]*?(?:/>|>.*?</(?:\w+:)?lvl\dpPr>)`)
+	bodyPropsPattern    = regexp.MustCompile(`(?s)<(?:\w+:)?bodyPr\b[^>]*?(?:/>|>.*?</(?:\w+:)?bodyPr>)`)
+	shapePropsPattern   = regexp.MustCompile(`(?s)<(?:\w+:)?spPr\b[^>]*?(?:/>|>.*?</(?:\w+:)?spPr>)`)
+	transformPattern    = regexp.MustCompile(`(?s)<(?:\w+:)?xfrm\b[^>]*?(?:/>|>.*?</(?:\w+:)?xfrm>)`)
+	fillPattern         = regexp.MustCompile(`(?s)<(?:\w+:)?(?:noFill|solidFill|gradFill|blipFill|pattFill|grpFill)\b[^>]*?(?:/>|>.*?</(?:\w+:)?(?:noFill|solidFill|gradFill|blipFill|pattFill|grpFill)>)`)
+	outlinePattern      = regexp.MustCompile(`(?s)<(?:\w+:)?ln\b[^>]*?(?:/>|>.*?</(?:\w+:)?ln>)`)
+	latinFontPattern    = regexp.MustCompile(`(?s)<(?:\w+:)?latin\b[^>]*?(?:/>|>.*?</(?:\w+:)?latin>)`)
+	afterLatinPattern   = regexp.MustCompile(`<(?:\w+:)?(?:ea|cs|sym|hlinkClick|hlinkMouseOver|rtl|extLst)\b`)
+	startTagPattern     = regexp.MustCompile(`^<((?:\w+:)?\w+)\b([^>]*?)(/?)>`)
+	drawingMLPrefix     = regexp.MustCompile(`xmlns:(\w+)="http://schemas.openxmlformats.org/drawingml/2006/main"`)
+)
+
+// masterTextStyle is the character formatting of a master's title, body or other text
+type masterTextStyle struct {
+	Font   string  `json:"font,omitempty"`  // Theme fonts are resolved to their typeface
+	Size   float64 `json:"size,omitempty"`  // Points
+	Color  string  `json:"color,omitempty"` // #RRGGBB, or a theme color such as tx1
+	Bold   bool    `json:"bold,omitempty"`
+	Italic bool    `json:"italic,omitempty"`
+}
+
+// masterShapeInfo is a shape of a slide master or layout as read_master reports it
+type masterShapeInfo struct {
+	ShapeIndex  int     `json:"shape_index"`
+	ShapeID     string  `json:"shape_id"`
+	Name        string  `json:"name"`
+	Kind        string  `json:"kind"`
+	Placeholder string  `json:"placeholder,omitempty"`
+	Text        string  `json:"text,omitempty"`
+	X           float64 `json:"x"`
+	Y           float64 `json:"y"`
+	Width       float64 `json:"width"`
+	Height      float64 `json:"height"`
+	Inherited   bool    `json:"inherits_position,omitempty"` // A layout placeholder placed where the master's is
+}
+
+// masterLayoutInfo is a layout of a slide master with its shapes
+type masterLayoutInfo struct {
+	Name   string            `json:"name"`
+	Type   string            `json:"type"`
+	Slides []int             `json:"slides"`
+	Shapes []masterShapeInfo `json:"shapes"`
+}
+
+// masterInfo is a slide master with its shapes, text styles and layouts
+type masterInfo struct {
+	Master     int                        `json:"master"`
+	Name       string                     `json:"name,omitempty"`
+	Shapes     []masterShapeInfo          `json:"shapes"`
+	TextStyles map[string]masterTextStyle `json:"text_styles"` // title, body and other
+	Layouts    []masterLayoutInfo         `json:"layouts"`
+}
+
+// xmlRunProperties is the part of a defRPr or rPr element read_master reports
+type xmlRunProperties struct {
+	Size   int    `xml:"sz,attr"`
+	Bold   string `xml:"b,attr"`
+	Italic string `xml:"i,attr"`
+	Latin  *struct {
+		Typeface string `xml:"typeface,attr"`
+	} `xml:"latin"`
+	RGB *struct {
+		Val string `xml:"val,attr"`
+	} `xml:"solidFill>srgbClr"`
+	Scheme *struct {
+		Val string `xml:"val,attr"`
+	} `xml:"solidFill>schemeClr"`
+}
+
+// style describes the run properties, resolving the theme's +mj-lt and +mn-lt fonts
+func (r xmlRunProperties) style(majorFont, minorFont string) masterTextStyle {
+	style := masterTextStyle{
+		Size:   float64(r.Size) / 100,
+		Bold:   r.Bold == "1" || r.Bold == "true",
+		Italic: r.Italic == "1" || r.Italic == "true",
+	}
+	if r.Latin != nil {
+		switch r.Latin.Typeface {
+		case "+mj-lt":
+			style.Font = majorFont
+		case "+mn-lt":
+			style.Font = minorFont
+		default:
+			style.Font = r.Latin.Typeface
+		}
+	}
+	if r.RGB != nil {
+		style.Color = "#" + strings.ToUpper(r.RGB.Val)
+	} else if r.Scheme != nil {
+		style.Color = r.Scheme.Val
+	}
+	return style
+}
+
+// masterShapes describes the shapes of a master or layout part
+func (p *pptxPackage) masterShapes(partName string) ([]masterShapeInfo, error) {
+	shapes, err := p.partShapes(partName)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(shapes))
+	for i, shape := range shapes {
+		names[i] = shape.Name
+	}
+	ids := shapeIDs(names)
+
+	infos := make([]masterShapeInfo, len(shapes))
+	for i, shape := range shapes {
+		infos[i] = masterShapeInfo{
+			ShapeIndex:  i,
+			ShapeID:     ids[i],
+			Name:        shape.Name,
+			Kind:        shapeKind(shape),
+			Placeholder: shape.Placeholder,
+			Text:        shape.Text,
+			X:           shape.X,
+			Y:           shape.Y,
+			Width:       shape.Width,
+			Height:      shape.Height,
+			Inherited:   shape.Placeholder != "" && shape.Width == 0 && shape.Height == 0,
+		}
+	}
+	return infos, nil
+}
+
+// themeFonts returns the major (heading) and minor (body) Latin fonts of a master's theme
+func (p *pptxPackage) themeFonts(masterPart string) (string, string) {
+	theme, err := p.partByRelType(masterPart, relTypeTheme)
+	if err != nil || theme == "" {
+		return "", ""
+	}
+	var scheme struct {
+		Major struct {
+			Typeface string `xml:"typeface,attr"`
+		} `xml:"themeElements>fontScheme>majorFont>latin"`
+		Minor struct {
+			Typeface string `xml:"typeface,attr"`
+		} `xml:"themeElements>fontScheme>minorFont>latin"`
+	}
+	if err := p.decode(theme, &scheme); err != nil {
+		return "", ""
+	}
+	return scheme.Major.Typeface, scheme.Minor.Typeface
+}
+
+// Masters describes the slide masters of the deck with their layouts
+func (p *pptxPackage) Masters() ([]masterInfo, error) {
+	refs, err := p.masterRefs()
+	if err != nil {
+		return nil, err
+	}
+	layouts, err := p.Layouts()
+	if err != nil {
+		return nil, err
+	}
+
+	masters := make([]masterInfo, 0, len(refs))
+	for i, ref := range refs {
+		var master struct {
+			CSld struct {
+				Name string `xml:"name,attr"`
+			} `xml:"cSld"`
+			Title xmlRunProperties `xml:"txStyles>titleStyle>lvl1pPr>defRPr"`
+			Body  xmlRunProperties `xml:"txStyles>bodyStyle>lvl1pPr>defRPr"`
+			Other xmlRunProperties `xml:"txStyles>otherStyle>lvl1pPr>defRPr"`
+		}
+		if err := p.decode(ref.Part, &master); err != nil {
+			return nil, err
+		}
+		shapes, err := p.masterShapes(ref.Part)
+		if err != nil {
+			return nil, err
+		}
+		major, minor := p.themeFonts(ref.Part)
+
+		info := masterInfo{
+			Master: i + 1,
+			Name:   master.CSld.Name,
+			Shapes: shapes,
+			TextStyles: map[string]masterTextStyle{
+				"title": master.Title.style(major, minor),
+				"body":  master.Body.style(major, minor),
+				"other": master.Other.style(major, minor),
+			},
+			Layouts: []masterLayoutInfo{},
+		}
+		for _, layout := range layouts {
+			if layout.Master != i+1 {
+				continue
+			}
+			shapes, err := p.masterShapes(layout.part)
+			if err != nil {
+				return nil, err
+			}
+			info.Layouts = append(info.Layouts, masterLayoutInfo{
+				Name:   layout.Name,
+				Type:   layout.Type,
+				Slides: layout.Slides,
+				Shapes: shapes,
+			})
+		}
+		masters = append(masters, info)
+	}
+	return masters, nil
+}
+
+// spTreeShapeSpans returns the byte ranges of the top-level shapes of a slide, layout or
+// master part, in the order partShapes reports them
+func spTreeShapeSpans(data []byte) ([][2]int, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var path []string
+	var spans [][2]int
+	start, depth := -1, 0
+	for {
+		offset := int(decoder.InputOffset())
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			if start < 0 && len(path) >= 2 && path[len(path)-2] == "cSld" && path[len(path)-1] == "spTree" {
+				switch element.Name.Local {
+				case "nvGrpSpPr", "grpSpPr", "extLst":
+				default:
+					start, depth = offset, len(path)
+				}
+			}
+			path = append(path, element.Name.Local)
+		case xml.EndElement:
+			path = path[:len(path)-1]
+			if start >= 0 && len(path) == depth {
+				spans = append(spans, [2]int{start, int(decoder.InputOffset())})
+				start = -1
+			}
+		}
+	}
+	return spans, nil
+}
+
+// editShapeXML applies edit to the XML of the top-level shape at index in a part
+func editShapeXML(data []byte, index int, edit func(shape []byte) ([]byte, error)) ([]byte, error) {
+	spans, err := spTreeShapeSpans(data)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(spans) {
+		return nil, fmt.Errorf("shape index %d out of range (0-%d)", index, len(spans)-1)
+	}
+	span := spans[index]
+	shape, err := edit(data[span[0]:span[1]])
+	if err != nil {
+		return nil, err
+	}
+	return spliceBytes(data, span[0], span[1], shape), nil
+}
+
+// localName returns the name of an element without its namespace prefix
+func localName(element []byte) string {
+	match := startTagPattern.FindSubmatch(element)
+	if match == nil {
+		return ""
+	}
+	name := string(match[1])
+	return name[strings.Index(name, ":")+1:]
+}
+
+// prefixOf returns the namespace prefix of an element such as <a:p>, including the colon
+func prefixOf(element []byte) string {
+	match := startTagPattern.FindSubmatch(element)
+	if match == nil {
+		return ""
+	}
+	name := string(match[1])
+	if i := strings.Index(name, ":"); i >= 0 {
+		return name[:i+1]
+	}
+	return ""
+}
+
+// dmlPrefix returns the prefix a part uses for DrawingML elements, "a:" by default
+func dmlPrefix(data []byte) string {
+	if match := drawingMLPrefix.FindSubmatch(data); match != nil {
+		return string(match[1]) + ":"
+	}
+	return "a:"
+}
+
+// withShapeText replaces the text of a shape with the lines of text, one paragraph each.
+// The new paragraphs keep the paragraph and run properties of the first paragraph, so
+// they look like the text they replace.
+func withShapeText(shape []byte, text, a string) ([]byte, error) {
+	if localName(shape) != "sp" {
+		return nil, errNoTextBody
+	}
+	if fieldPattern.Match(shape) {
+		return nil, errTextHasField
+	}
+
+	body := txBodyPattern.FindSubmatchIndex(shape)
+	if body == nil {
+		// A placeholder that only takes its text from the layout or master; give it a body
+		p := prefixOf(shape)
+		end := bytes.LastIndex(shape, []byte("</"+p+"sp>"))
+		if end < 0 {
+			return nil, errNoTextBody
+		}
+		txBody := []byte("<" + p + "txBody><" + a + "bodyPr/><" + a + "lstStyle/></" + p + "txBody>")
+		shape = spliceBytes(shape, end, end, txBody)
+		body = txBodyPattern.FindSubmatchIndex(shape)
+	}
+	content := shape[body[4]:body[5]]
+
+	// Keep the body properties and list styles in front of the first paragraph
+	var head, pPr, rPr []byte
+	if first := paragraphPattern.FindIndex(content); first != nil {
+		head = content[:first[0]]
+		paragraph := content[first[0]:first[1]]
+		pPr = paraPropsPattern.Find(paragraph)
+		if rPr = textRunPropsPattern.Find(paragraph); rPr == nil {
+			// An empty paragraph's end properties become the runs' properties
+			if endPr := endParaPropsPattern.Find(paragraph); endPr != nil {
+				rPr = bytes.Replace(endPr, []byte("endParaRPr"), []byte("rPr"), 2)
+			}
+		}
+	} else {
+		head = content
+	}
+
+	var paragraphs bytes.Buffer
+	for _, line := range strings.Split(text, "\n") {
+		paragraphs.WriteString("<" + a + "p>")
+		paragraphs.Write(pPr)
+		if line != "" {
+			paragraphs.WriteString("<" + a + "r>")
+			paragraphs.Write(rPr)
+			paragraphs.WriteString("<" + a + "t>" + xmlAttr(line) + "</" + a + "t></" + a + "r>")
+		} else if rPr != nil {
+			paragraphs.Write(bytes.Replace(rPr, []byte("rPr"), []byte("endParaRPr"), 2))
+		}
+		paragraphs.WriteString("</" + a + "p>")
+	}
+
+	newContent := append(append([]byte{}, head...), paragraphs.Bytes()...)
+	return spliceBytes(shape, body[4], body[5], newContent), nil
+}
+
+// withShapeGeometry sets the position and size of a shape, in EMUs
+func withShapeGeometry(shape []byte, x, y, cx, cy int64, a string) ([]byte, error) {
+	spPr := shapePropsPattern.FindIndex(shape)
+	if spPr == nil {
+		return nil, errors.New("shape has no position of its own")
+	}
+	props := shape[spPr[0]:spPr[1]]
+	offsets := fmt.Sprintf(`<%soff x="%d" y="%d"/><%sext cx="%d" cy="%d"/>`, a, x, y, a, cx, cy)
+
+	if xfrm := transformPattern.FindIndex(props); xfrm != nil {
+		// Keep rotation and flips
+		tag := startTagPattern.FindSubmatch(props[xfrm[0]:xfrm[1]])
+		replacement := fmt.Sprintf("<%s%s>%s</%s>", tag[1], tag[2], offsets, tag[1])
+		props = spliceBytes(props, xfrm[0], xfrm[1], []byte(replacement))
+	} else {
+		tag := startTagPattern.FindSubmatchIndex(props)
+		xfrm := "<" + a + "xfrm>" + offsets + "</" + a + "xfrm>"
+		if tag[7] > tag[6] {
+			// Self-closing <p:spPr/>
+			name := string(props[tag[2]:tag[3]])
+			props = []byte("<" + name + string(props[tag[4]:tag[5]]) + ">" + xfrm + "</" + name + ">")
+		} else {
+			props = spliceBytes(props, tag[1], tag[1], []byte(xfrm))
+		}
+	}
+	return spliceBytes(shape, spPr[0], spPr[1], props), nil
+}
+
+// runStyle is a change to character formatting; zero values leave a property as it is
+type runStyle struct {
+	Font   string
+	Size   float64 // Points
+	Color  string  // RRGGBB
+	Bold   *bool
+	Italic *bool
+}
+
+// setAttr sets an attribute in the attribute list of a start tag
+func setAttr(attrs, name, value string) string {
+	pattern := regexp.MustCompile(`\s` + regexp.QuoteMeta(name) + `="[^"]*"`)
+	attr := fmt.Sprintf(` %s="%s"`, name, value)
+	if pattern.MatchString(attrs) {
+		return pattern.ReplaceAllLiteralString(attrs, attr)
+	}
+	return attrs + attr
+}
+
+// withRunStyle applies a style to an rPr, endParaRPr or defRPr element
+func withRunStyle(element []byte, style runStyle, a string) []byte {
+	tag := startTagPattern.FindSubmatchIndex(element)
+	if tag == nil {
+		return element
+	}
+	name := string(element[tag[2]:tag[3]])
+	attrs := string(element[tag[4]:tag[5]])
+	selfClosing := tag[7] > tag[6]
+	var children []byte
+	if !selfClosing {
+		children = element[tag[1] : len(element)-len("</"+name+">")]
+	}
+
+	if style.Size > 0 {
+		attrs = setAttr(attrs, "sz", strconv.Itoa(int(style.Size*100+0.5)))
+	}
+	for attr, value := range map[string]*bool{"b": style.Bold, "i": style.Italic} {
+		if value != nil {
+			attrs = setAttr(attrs, attr, map[bool]string{true: "1", false: "0"}[*value])
+		}
+	}
+	if style.Color != "" {
+		children = fillPattern.ReplaceAll(children, nil)
+		fill := []byte(fmt.Sprintf(`<%ssolidFill><%ssrgbClr val="%s"/></%ssolidFill>`, a, a, strings.ToUpper(style.Color), a))
+		at := 0
+		if outline := outlinePattern.FindIndex(children); outline != nil {
+			at = outline[1]
+		}
+		children = spliceBytes(children, at, at, fill)
+	}
+	if style.Font != "" {
+		children = latinFontPattern.ReplaceAll(children, nil)
+		latin := []byte(fmt.Sprintf(`<%slatin typeface="%s"/>`, a, xmlAttr(style.Font)))
+		at := len(children)
+		if next := afterLatinPattern.FindIndex(children); next != nil {
+			at = next[0]
+		}
+		children = spliceBytes(children, at, at, latin)
+	}
+
+	if len(children) == 0 {
+		return []byte("<" + name + attrs + "/>")
+	}
+	return []byte("<" + name + attrs + ">" + string(children) + "</" + name + ">")
+}
+
+// withLevelStyles applies a style to every level of a list style (lstStyle, or a master's
+// titleStyle or bodyStyle), adding a first level when the list styles none
+func withLevelStyles(list []byte, style runStyle, a string) []byte {
+	tag := startTagPattern.FindSubmatchIndex(list)
+	name := string(list[tag[2]:tag[3]])
+	if tag[7] > tag[6] || !levelPropsPattern.Match(list) {
+		level := fmt.Sprintf("<%slvl1pPr>%s</%slvl1pPr>", a, withRunStyle([]byte("<"+a+"defRPr/>"), style, a), a)
+		if tag[7] > tag[6] {
+			return []byte("<" + name + string(list[tag[4]:tag[5]]) + ">" + level + "</" + name + ">")
+		}
+		return spliceBytes(list, tag[1], tag[1], []byte(level))
+	}
+	return levelPropsPattern.ReplaceAllFunc(list, func(level []byte) []byte {
+		if runPropsPattern.Match(level) {
+			return runPropsPattern.ReplaceAllFunc(level, func(props []byte) []byte {
+				return withRunStyle(props, style, a)
+			})
+		}
+		defRPr := withRunStyle([]byte("<"+a+"defRPr/>"), style, a)
+		levelTag := startTagPattern.FindSubmatchIndex(level)
+		levelName := string(level[levelTag[2]:levelTag[3]])
+		if levelTag[7] > levelTag[6] {
+			return []byte("<" + levelName + string(level[levelTag[4]:levelTag[5]]) + ">" + string(defRPr) + "</" + levelName + ">")
+		}
+		// defRPr goes last, before any extLst
+		at := bytes.LastIndex(level, []byte("</"+levelName+">"))
+		if ext := bytes.Index(level, []byte("extLst")); ext >= 0 {
+			at = bytes.LastIndex(level[:ext], []byte("<"))
+		}
+		return spliceBytes(level, at, at, defRPr)
+	})
+}
+
+// withShapeTextStyle applies a style to a shape's text: its runs, and with ownLevels
+// the list styles of its text body, which is where layout placeholders and a master's
+// footer placeholders keep the formatting of text typed into them
+func withShapeTextStyle(shape []byte, style runStyle, ownLevels bool, a string) ([]byte, error) {
+	body := txBodyPattern.FindSubmatchIndex(shape)
+	if body == nil {
+		return nil, errNoTextBody
+	}
+	content := runPropsPattern.ReplaceAllFunc(shape[body[4]:body[5]], func(props []byte) []byte {
+		return withRunStyle(props, style, a)
+	})
+	if ownLevels {
+		if list := listStylePattern.FindIndex(content); list != nil {
+			content = spliceBytes(content, list[0], list[1], withLevelStyles(content[list[0]:list[1]], style, a))
+		} else {
+			at := 0
+			if bodyPr := bodyPropsPattern.FindIndex(content); bodyPr != nil {
+				at = bodyPr[1]
+			}
+			content = spliceBytes(content, at, at, withLevelStyles([]byte("<"+a+"lstStyle/>"), style, a))
+		}
+	}
+	return spliceBytes(shape, body[4], body[5], content), nil
+}
+
+// withMasterTextStyle applies a style to one of a master's text styles: titleStyle,
+// bodyStyle or otherStyle
+func withMasterTextStyle(master []byte, styleName string, style runStyle, a string) ([]byte, error) {
+	pattern := regexp.MustCompile(`(?s)<(?:\w+:)?` + styleName + `\b[^>]*?(?:/>|>.*?</(?:\w+:)?` + styleName + `>)`)
+	found := pattern.FindIndex(master)
+	if found == nil {
+		return nil, fmt.Errorf("the master has no %s", styleName)
+	}
+	return spliceBytes(master, found[0], found[1], withLevelStyles(master[found[0]:found[1]], style, a)), nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Embedded pictures, audio and video are parts of their own (usually under ppt/media) that
+// slides, layouts and masters link to. They are found through those relationships rather
+// than by folder, so only media the deck actually uses is reported.
+
+const (
+	relTypeImage = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"
+	relTypeVideo = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/video"
+	relTypeAudio = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/audio"
+	relTypeMedia = "http://schemas.microsoft.com/office/2007/relationships/media"
+)
+
+// mediaKinds are the kinds of embedded media, as reported by pptxMedia.Kind
+var mediaKinds = []string{"image", "video", "audio"}
+
+// audioExtensions tells audio from video for relationships that don't say which
+var audioExtensions = []string{".mp3", ".wav", ".m4a", ".wma", ".aac", ".ogg", ".flac", ".mid", ".midi", ".aif", ".aiff"}
+
+// pptxMedia is an embedded picture, audio or video part and where the deck uses it
+type pptxMedia struct {
+	Part      string `json:"part"`
+	Kind      string `json:"kind"` // image, video or audio
+	SizeBytes int64  `json:"size_bytes"`
+	Slides    []int  `json:"slides,omitempty"`     // Slides linking to it
+	OnMasters bool   `json:"on_masters,omitempty"` // Used by a slide master or layout, so it can show on every slide using them
+}
+
+// mediaKind returns the kind of media a relationship links to, "" for other relationships
+func mediaKind(rel pptxRelationship) string {
+	switch rel.Type {
+	case relTypeImage:
+		return "image"
+	case relTypeAudio:
+		return "audio"
+	case relTypeVideo:
+		return "video"
+	case relTypeMedia:
+		if slices.Contains(audioExtensions, strings.ToLower(path.Ext(rel.Target))) {
+			return "audio"
+		}
+		return "video"
+	}
+	return ""
+}
+
+// Media returns the embedded media of the deck in the order the slides, then the masters
+// and layouts, first use them
+func (p *pptxPackage) Media() ([]pptxMedia, error) {
+	var media []*pptxMedia
+	byPart := map[string]*pptxMedia{}
+	collect := func(partName string, use func(*pptxMedia)) error {
+		rels, err := p.rawRelationships(partName)
+		if err != nil {
+			return err
+		}
+		for _, rel := range rels {
+			kind := mediaKind(rel)
+			if kind == "" || rel.TargetMode == "External" {
+				continue
+			}
+			target := resolvePartTarget(partName, rel.Target)
+			file, ok := p.parts[target]
+			if !ok {
+				continue
+			}
+			item := byPart[target]
+			if item == nil {
+				item = &pptxMedia{Part: target, Kind: kind, SizeBytes: int64(file.UncompressedSize64)}
+				byPart[target] = item
+				media = append(media, item)
+			}
+			use(item)
+		}
+		return nil
+	}
+
+	for i, slidePart := range p.slides {
+		slideNumber := i + 1
+		err := collect(slidePart, func(item *pptxMedia) {
+			if !slices.Contains(item.Slides, slideNumber) {
+				item.Slides = append(item.Slides, slideNumber)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	var templateParts []string
+	for name := range p.parts {
+		if (strings.HasPrefix(name, "ppt/slideMasters/") || strings.HasPrefix(name, "ppt/slideLayouts/")) && strings.HasSuffix(name, ".xml") {
+			templateParts = append(templateParts, name)
+		}
+	}
+	slices.Sort(templateParts)
+	for _, name := range templateParts {
+		if err := collect(name, func(item *pptxMedia) { item.OnMasters = true }); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]pptxMedia, len(media))
+	for i, item := range media {
+		result[i] = *item
+	}
+	return result, nil
+}
+
+// errMediaFileExists is returned when extracting would replace an existing file
+var errMediaFileExists = errors.New("file already exists")
+
+// extractedMedia is a media part written out by extractMedia
+type extractedMedia struct {
+	Path string `json:"path"`
+	pptxMedia
+}
+
+// extractMedia copies the given media parts of a deck into outputDir under their own file
+// names, refusing to replace existing files unless overwrite is set
+func extractMedia(pkg *pptxPackage, media []pptxMedia, outputDir string, overwrite bool) ([]extractedMedia, error) {
+	extracted := make([]extractedMedia, 0, len(media))
+	taken := map[string]bool{}
+	for _, item := range media {
+		// Media of other folders may share a file name with ppt/media
+		name := freePartName(path.Base(item.Part), func(name string) bool { return taken[name] })
+		taken[name] = true
+		target := filepath.Join(outputDir, name)
+		if _, err := os.Stat(target); err == nil && !overwrite {
+			return nil, fmt.Errorf("%w: %s", errMediaFileExists, target)
+		}
+		extracted = append(extracted, extractedMedia{Path: target, pptxMedia: item})
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, err
+	}
+	for _, item := range extracted {
+		if err := copyPart(pkg, item.Part, item.Path); err != nil {
+			return nil, err
+		}
+	}
+	return extracted, nil
+}
+
+// copyPart writes a part's bytes to a file
+func copyPart(pkg *pptxPackage, partName, target string) error {
+	reader, err := pkg.parts[partName].Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", partName, err)
+	}
+	defer reader.Close()
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, reader); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
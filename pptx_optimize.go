@@ -0,0 +1,446 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Decks mostly grow through photos pasted at full camera resolution and shown a few inches
+// wide, and through layouts and media nothing uses any more. Optimizing scales pictures down
+// to what their frames need at a given resolution and drops the parts no slide reaches.
+
+const (
+	defaultOptimizeDPI     = 150
+	defaultOptimizeQuality = 85
+
+	// Pictures already within this share of the size they need aren't recompressed
+	minDownscale = 0.9
+)
+
+// relIDAttrPattern matches the r:id attribute of a list entry such as sldLayoutId
+var relIDAttrPattern = regexp.MustCompile(`\s\w+:id="([^"]*)"`)
+
+// embedAttrPattern matches the r:embed and r:link attributes of blips, wherever they are
+var embedAttrPattern = regexp.MustCompile(`\s\w+:(?:embed|link)="([^"]*)"`)
+
+// optimizeOptions controls what optimizePresentation changes
+type optimizeOptions struct {
+	MaxDPI        int  // Pixels per inch of the shown size pictures are scaled down to
+	JPEGQuality   int  // Quality JPEG pictures are re-encoded with
+	RemoveLayouts bool // Remove layouts no slide uses
+	DryRun        bool // Work out the result without replacing the presentation
+}
+
+// optimizedImage is a picture optimizePresentation scaled down
+type optimizedImage struct {
+	Part        string `json:"part"`
+	Slides      []int  `json:"slides"`
+	OldWidth    int    `json:"old_width"`
+	OldHeight   int    `json:"old_height"`
+	NewWidth    int    `json:"new_width"`
+	NewHeight   int    `json:"new_height"`
+	BytesBefore int64  `json:"bytes_before"`
+	BytesAfter  int64  `json:"bytes_after"`
+}
+
+// removedLayout is a layout optimizePresentation removed
+type removedLayout struct {
+	Name   string `json:"name"`
+	Master int    `json:"master"`
+}
+
+// optimizeResult reports what optimizePresentation changed and the file size it saved
+type optimizeResult struct {
+	SizeBefore     int64            `json:"size_before"`
+	SizeAfter      int64            `json:"size_after"`
+	Images         []optimizedImage `json:"images"`
+	RemovedLayouts []removedLayout  `json:"removed_layouts"`
+	RemovedParts   []string         `json:"removed_parts"` // Parts nothing used any more, such as media of the removed layouts
+}
+
+// pictureUse is how large the slides show an image, in pixels at the target resolution
+type pictureUse struct {
+	width, height float64
+	slides        []int
+}
+
+// optimizePresentation scales down pictures larger than the slides show them, removes
+// unused layouts and drops the parts nothing reaches, then replaces the presentation
+// unless it is a dry run. The result gives the file size before and after either way.
+func optimizePresentation(presentationPath string, options optimizeOptions) (optimizeResult, error) {
+	tempPath, result, err := writeOptimizedPackage(presentationPath, options)
+	if err != nil || tempPath == "" {
+		return result, err
+	}
+	if options.DryRun {
+		os.Remove(tempPath)
+		return result, nil
+	}
+	if err := replacePresentation(tempPath, presentationPath); err != nil {
+		return optimizeResult{}, err
+	}
+	return result, nil
+}
+
+// writeOptimizedPackage writes the optimized package to a temporary file next to the
+// presentation and returns that file's path, or "" when there is nothing to change
+func writeOptimizedPackage(presentationPath string, options optimizeOptions) (string, optimizeResult, error) {
+	info, err := os.Stat(presentationPath)
+	if err != nil {
+		return "", optimizeResult{}, err
+	}
+	result := optimizeResult{
+		SizeBefore:     info.Size(),
+		SizeAfter:      info.Size(),
+		Images:         []optimizedImage{},
+		RemovedLayouts: []removedLayout{},
+		RemovedParts:   []string{},
+	}
+
+	pkg, err := openPPTX(presentationPath)
+	if err != nil {
+		return "", optimizeResult{}, err
+	}
+	defer pkg.Close()
+
+	replaced := map[string][]byte{}
+	uses, order, err := pictureUses(pkg, float64(options.MaxDPI))
+	if err != nil {
+		return "", optimizeResult{}, err
+	}
+	for _, part := range order {
+		data, err := pkg.readPart(part)
+		if err != nil {
+			return "", optimizeResult{}, err
+		}
+		resized, ok := downscaleImage(data, uses[part], options.JPEGQuality)
+		if !ok {
+			continue
+		}
+		replaced[part] = resized.data
+		result.Images = append(result.Images, optimizedImage{
+			Part:        part,
+			Slides:      uses[part].slides,
+			OldWidth:    resized.oldWidth,
+			OldHeight:   resized.oldHeight,
+			NewWidth:    resized.newWidth,
+			NewHeight:   resized.newHeight,
+			BytesBefore: int64(len(data)),
+			BytesAfter:  int64(len(resized.data)),
+		})
+	}
+
+	relsOf := map[string][]pptxRelationship{}
+	if options.RemoveLayouts {
+		removed, err := removeUnusedLayouts(pkg, replaced, relsOf)
+		if err != nil {
+			return "", optimizeResult{}, err
+		}
+		result.RemovedLayouts = removed
+	}
+	for part, rels := range relsOf {
+		replaced[relsPartName(part)] = marshalRelationships(rels)
+	}
+
+	// Drop whatever the deck no longer reaches, including media that was already orphaned
+	reachable, err := reachableParts(pkg, relsOf)
+	if err != nil {
+		return "", optimizeResult{}, err
+	}
+	dropped := map[string]bool{}
+	for name := range pkg.parts {
+		if reachable[name] || name == "[Content_Types].xml" || strings.HasSuffix(name, ".rels") || strings.HasSuffix(name, "/") {
+			continue
+		}
+		dropped[name] = true
+		dropped[relsPartName(name)] = true
+		result.RemovedParts = append(result.RemovedParts, name)
+	}
+	slices.Sort(result.RemovedParts)
+	if len(replaced) == 0 && len(dropped) == 0 {
+		return "", result, nil
+	}
+	if len(dropped) > 0 {
+		var types contentTypes
+		if err := pkg.decode("[Content_Types].xml", &types); err != nil {
+			return "", optimizeResult{}, err
+		}
+		overrides := types.Overrides[:0]
+		for _, override := range types.Overrides {
+			if !dropped[strings.TrimPrefix(override.PartName, "/")] {
+				overrides = append(overrides, override)
+			}
+		}
+		types.Overrides = overrides
+		replaced["[Content_Types].xml"] = types.marshal()
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(presentationPath), ".slidepilot-optimize-*.pptx")
+	if err != nil {
+		return "", optimizeResult{}, fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	tempPath := tempFile.Name()
+	err = writeMergedPackage(tempFile, pkg, nil, nil, nil, replaced, dropped)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		info, err = os.Stat(tempPath)
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return "", optimizeResult{}, fmt.Errorf("failed to write presentation: %v", err)
+	}
+	result.SizeAfter = info.Size()
+	return tempPath, result, nil
+}
+
+// pictureUses returns, for every image only top-level pictures on slides show, the pixel
+// size those pictures need at dpi, along with the images in the order the slides use them.
+// Images anything else uses (masters, layouts, shape fills, grouped pictures) are left out,
+// since their shown size isn't known.
+func pictureUses(pkg *pptxPackage, dpi float64) (map[string]*pictureUse, []string, error) {
+	uses := map[string]*pictureUse{}
+	unknown := map[string]bool{}
+	var order []string
+	slideParts := map[string]bool{}
+
+	for i, slidePart := range pkg.slides {
+		slideParts[slidePart] = true
+		slideXML, err := pkg.readPart(slidePart)
+		if err != nil {
+			return nil, nil, err
+		}
+		shapes, err := pkg.SlideShapes(i + 1)
+		if err != nil {
+			return nil, nil, err
+		}
+		rels, err := pkg.rawRelationships(slidePart)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// A relationship the slide refers to more often than its pictures do also fills something else
+		references := map[string]int{}
+		for _, match := range embedAttrPattern.FindAllSubmatch(slideXML, -1) {
+			references[string(match[1])]++
+		}
+		pictures := map[string][]pptxShape{}
+		for _, shape := range shapes {
+			if shape.Kind == "pic" && shape.ImageRel != "" {
+				pictures[shape.ImageRel] = append(pictures[shape.ImageRel], shape)
+			}
+		}
+
+		for _, rel := range rels {
+			if mediaKind(rel) != "image" || rel.TargetMode == "External" {
+				continue
+			}
+			target := resolvePartTarget(slidePart, rel.Target)
+			if references[rel.ID] != len(pictures[rel.ID]) {
+				unknown[target] = true
+				continue
+			}
+			for _, shape := range pictures[rel.ID] {
+				// A cropped picture shows part of the image at its frame's size
+				shownWidth := 1 - shape.ImageCrop[0] - shape.ImageCrop[2]
+				shownHeight := 1 - shape.ImageCrop[1] - shape.ImageCrop[3]
+				if shape.Width <= 0 || shape.Height <= 0 || shownWidth <= 0 || shownHeight <= 0 {
+					unknown[target] = true
+					continue
+				}
+				use := uses[target]
+				if use == nil {
+					use = &pictureUse{}
+					uses[target] = use
+					order = append(order, target)
+				}
+				use.width = max(use.width, shape.Width*dpi/shownWidth)
+				use.height = max(use.height, shape.Height*dpi/shownHeight)
+				if !slices.Contains(use.slides, i+1) {
+					use.slides = append(use.slides, i+1)
+				}
+			}
+		}
+	}
+
+	for name := range pkg.parts {
+		if slideParts[name] || strings.HasSuffix(name, ".rels") {
+			continue
+		}
+		rels, err := pkg.rawRelationships(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, rel := range rels {
+			if mediaKind(rel) == "image" && rel.TargetMode != "External" {
+				unknown[resolvePartTarget(name, rel.Target)] = true
+			}
+		}
+	}
+
+	order = slices.DeleteFunc(order, func(part string) bool { return unknown[part] })
+	for part := range unknown {
+		delete(uses, part)
+	}
+	return uses, order, nil
+}
+
+// downscaledImage is an image re-encoded at a smaller size
+type downscaledImage struct {
+	data                []byte
+	oldWidth, oldHeight int
+	newWidth, newHeight int
+}
+
+// downscaleImage scales a PNG or JPEG image down to the pixel size a picture needs,
+// keeping its aspect ratio and format. It reports false when the image isn't larger than
+// needed, can't be decoded, or wouldn't get smaller.
+func downscaleImage(data []byte, use *pictureUse, quality int) (downscaledImage, bool) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || (format != "png" && format != "jpeg") || config.Width == 0 || config.Height == 0 {
+		return downscaledImage{}, false
+	}
+	scale := max(use.width/float64(config.Width), use.height/float64(config.Height))
+	if scale >= minDownscale {
+		return downscaledImage{}, false
+	}
+	width := max(1, int(math.Ceil(float64(config.Width)*scale)))
+	height := max(1, int(math.Ceil(float64(config.Height)*scale)))
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return downscaledImage{}, false
+	}
+	scaled := boxResize(img, width, height)
+
+	var out bytes.Buffer
+	if format == "jpeg" {
+		err = jpeg.Encode(&out, scaled, &jpeg.Options{Quality: quality})
+	} else {
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&out, scaled)
+	}
+	if err != nil || out.Len() >= len(data) {
+		return downscaledImage{}, false
+	}
+	return downscaledImage{
+		data:      out.Bytes(),
+		oldWidth:  config.Width,
+		oldHeight: config.Height,
+		newWidth:  width,
+		newHeight: height,
+	}, true
+}
+
+// boxResize scales an image down by averaging the source pixels each target pixel covers,
+// which keeps photos smooth where picking single pixels would alias
+func boxResize(img image.Image, width, height int) *image.RGBA {
+	bounds := img.Bounds()
+	source := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(source, source.Bounds(), img, bounds.Min, draw.Src)
+	sourceWidth, sourceHeight := bounds.Dx(), bounds.Dy()
+
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * sourceHeight / height
+		y1 := max(y0+1, (y+1)*sourceHeight/height)
+		for x := 0; x < width; x++ {
+			x0 := x * sourceWidth / width
+			x1 := max(x0+1, (x+1)*sourceWidth/width)
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := source.Pix[sy*source.Stride+x0*4 : sy*source.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					sum[0] += int(row[i])
+					sum[1] += int(row[i+1])
+					sum[2] += int(row[i+2])
+					sum[3] += int(row[i+3])
+				}
+			}
+			count := (y1 - y0) * (x1 - x0)
+			offset := y*scaled.Stride + x*4
+			for i := range sum {
+				scaled.Pix[offset+i] = uint8((sum[i] + count/2) / count)
+			}
+		}
+	}
+	return scaled
+}
+
+// removeUnusedLayouts takes the layouts no slide uses out of their masters, adding the
+// rewritten masters to replaced and their relationships to relsOf. A master keeps its first
+// layout when none of its layouts are used, since a master needs at least one.
+func removeUnusedLayouts(pkg *pptxPackage, replaced map[string][]byte, relsOf map[string][]pptxRelationship) ([]removedLayout, error) {
+	layouts, err := pkg.Layouts()
+	if err != nil {
+		return nil, err
+	}
+	masters, err := pkg.masterRefs()
+	if err != nil {
+		return nil, err
+	}
+
+	removed := []removedLayout{}
+	for index, master := range masters {
+		var unused []pptxLayout
+		keptOne := false
+		for _, layout := range layouts {
+			if layout.Master != index+1 {
+				continue
+			}
+			if len(layout.Slides) > 0 {
+				keptOne = true
+			} else {
+				unused = append(unused, layout)
+			}
+		}
+		if !keptOne && len(unused) > 0 {
+			unused = unused[1:]
+		}
+		if len(unused) == 0 {
+			continue
+		}
+
+		gone := map[string]bool{}
+		for _, layout := range unused {
+			gone[layout.part] = true
+			removed = append(removed, removedLayout{Name: layout.Name, Master: layout.Master})
+		}
+		rels, err := pkg.rawRelationships(master.Part)
+		if err != nil {
+			return nil, err
+		}
+		goneRels := map[string]bool{}
+		keptRels := rels[:0]
+		for _, rel := range rels {
+			if rel.Type == relTypeSlideLayout && gone[resolvePartTarget(master.Part, rel.Target)] {
+				goneRels[rel.ID] = true
+				continue
+			}
+			keptRels = append(keptRels, rel)
+		}
+		relsOf[master.Part] = keptRels
+
+		masterXML, err := pkg.readPart(master.Part)
+		if err != nil {
+			return nil, err
+		}
+		replaced[master.Part] = layoutIDPattern.ReplaceAllFunc(masterXML, func(entry []byte) []byte {
+			if id := relIDAttrPattern.FindSubmatch(entry); id != nil && goneRels[string(id[1])] {
+				return nil
+			}
+			return entry
+		})
+	}
+	return removed, nil
+}
@@ -0,0 +1,1097 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The read-only tools parse .pptx packages directly instead of going through LibreOffice:
+// a slide is a zip entry holding DrawingML XML, so listing and reading slides needs no
+// Python runtime and takes milliseconds. Shapes are reported in spTree order, which is
+// the order LibreOffice imports them in, so shape indexes line up with the UNO-based
+// editing tools.
+
+// emuPerInch converts DrawingML English Metric Units to inches
+const emuPerInch = 914400
+
+// errSlideOutOfRange is returned for slide numbers outside the deck
+var errSlideOutOfRange = errors.New("slide number out of range")
+
+// pptxPackage is an open .pptx file with its slides resolved in presentation order
+type pptxPackage struct {
+	reader *zip.ReadCloser
+	parts  map[string]*zip.File
+	slides []string // Part names of the slides, e.g. ppt/slides/slide1.xml
+}
+
+// pptxShape is one top-level shape of a slide
+type pptxShape struct {
+	Kind        string // sp, pic, graphicFrame, grpSp, cxnSp, ...
+	ID          int    // cNvPr id; unique within the slide but renumbered whenever LibreOffice saves
+	Name        string
+	AltText     string // cNvPr descr
+	Placeholder string // Placeholder type such as title or body, "" if not a placeholder
+	HasText     bool   // Whether the shape has a text body
+	Text        string
+	Table       [][]string // Cell texts when the shape is a table
+	Children    int        // Number of shapes in a group
+	X, Y        float64    // Position in inches
+	Width       float64
+	Height      float64
+
+	ImageRel  string     // Relationship of a picture's image
+	ImageCrop [4]float64 // Share of a picture's image cropped off the left, top, right and bottom
+	ChartRel  string     // Relationship of a chart frame's chart part
+	Image     *pptxImage // Filled in by describeContent
+	Chart     *pptxChart // Filled in by describeContent
+}
+
+// pptxImage is the picture embedded in a pic shape
+type pptxImage struct {
+	Part        string `json:"part"`
+	PixelWidth  int    `json:"pixel_width,omitempty"` // 0 for formats Go can't decode, e.g. EMF or SVG
+	PixelHeight int    `json:"pixel_height,omitempty"`
+}
+
+// pptxChart summarizes the chart part behind a chart frame
+type pptxChart struct {
+	Part       string            `json:"part"`
+	Type       string            `json:"chart_type"` // column, bar, line, pie, ...; the first plot of combination charts
+	Title      string            `json:"title,omitempty"`
+	Categories []string          `json:"categories"`
+	Series     []pptxChartSeries `json:"series"`
+}
+
+// pptxChartSeries is one series of a chart with the values cached in the chart part
+type pptxChartSeries struct {
+	Name   string     `json:"name"`
+	Values []*float64 `json:"values"` // nil for empty points
+}
+
+// openPPTX opens a presentation package and resolves its slide order
+func openPPTX(presentationPath string) (*pptxPackage, error) {
+	reader, err := zip.OpenReader(presentationPath)
+	if err != nil {
+		return nil, fmt.Errorf("presentation is not a valid zip package: %w", err)
+	}
+
+	pkg := &pptxPackage{reader: reader, parts: make(map[string]*zip.File, len(reader.File))}
+	for _, file := range reader.File {
+		pkg.parts[file.Name] = file
+	}
+
+	var presentation struct {
+		SlideIDs []struct {
+			RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sldIdLst>sldId"`
+	}
+	if err := pkg.decode("ppt/presentation.xml", &presentation); err != nil {
+		reader.Close()
+		return nil, err
+	}
+
+	rels, err := pkg.relationships("ppt/presentation.xml")
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+	for _, slideID := range presentation.SlideIDs {
+		target, ok := rels[slideID.RelID]
+		if !ok {
+			reader.Close()
+			return nil, fmt.Errorf("presentation.xml references missing relationship %s", slideID.RelID)
+		}
+		pkg.slides = append(pkg.slides, target)
+	}
+
+	return pkg, nil
+}
+
+// Close releases the underlying zip file
+func (p *pptxPackage) Close() error {
+	return p.reader.Close()
+}
+
+// SlideCount returns the number of slides in the deck
+func (p *pptxPackage) SlideCount() int {
+	return len(p.slides)
+}
+
+// decode unmarshals an XML part of the package
+func (p *pptxPackage) decode(partName string, v interface{}) error {
+	file, ok := p.parts[partName]
+	if !ok {
+		return fmt.Errorf("presentation package is missing %s", partName)
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", partName, err)
+	}
+	defer reader.Close()
+
+	if err := xml.NewDecoder(reader).Decode(v); err != nil && err != io.EOF {
+		return fmt.Errorf("%s is unreadable: %v", partName, err)
+	}
+	return nil
+}
+
+// relationships returns a part's relationship targets by ID, resolved to part names.
+// Parts without relationships have none.
+func (p *pptxPackage) relationships(partName string) (map[string]string, error) {
+	relsName := relsPartName(partName)
+
+	targets := make(map[string]string)
+	if _, ok := p.parts[relsName]; !ok {
+		return targets, nil
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID         string `xml:"Id,attr"`
+			Target     string `xml:"Target,attr"`
+			TargetMode string `xml:"TargetMode,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := p.decode(relsName, &rels); err != nil {
+		return nil, err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.TargetMode == "External" {
+			continue
+		}
+		targets[rel.ID] = resolvePartTarget(partName, rel.Target)
+	}
+	return targets, nil
+}
+
+// slidePart returns the part name of a 1-based slide number
+func (p *pptxPackage) slidePart(slideNumber int) (string, error) {
+	if slideNumber < 1 || slideNumber > len(p.slides) {
+		return "", fmt.Errorf("%w: %d is not between 1 and %d", errSlideOutOfRange, slideNumber, len(p.slides))
+	}
+	return p.slides[slideNumber-1], nil
+}
+
+// SlideShapes returns the top-level shapes of a 1-based slide number
+func (p *pptxPackage) SlideShapes(slideNumber int) ([]pptxShape, error) {
+	partName, err := p.slidePart(slideNumber)
+	if err != nil {
+		return nil, err
+	}
+	return p.partShapes(partName)
+}
+
+// partShapes returns the top-level shapes of a slide, layout or master part
+func (p *pptxPackage) partShapes(partName string) ([]pptxShape, error) {
+	var slide struct {
+		SpTree struct {
+			Shapes []xmlShape `xml:",any"`
+		} `xml:"cSld>spTree"`
+	}
+	if err := p.decode(partName, &slide); err != nil {
+		return nil, err
+	}
+
+	shapes := make([]pptxShape, 0, len(slide.SpTree.Shapes))
+	for _, element := range slide.SpTree.Shapes {
+		// The tree's own properties are not shapes
+		if element.XMLName.Local == "nvGrpSpPr" || element.XMLName.Local == "grpSpPr" || element.XMLName.Local == "extLst" {
+			continue
+		}
+		shapes = append(shapes, element.toShape())
+	}
+	return shapes, nil
+}
+
+// SlideLayoutName returns the name of the layout a slide uses, or "" if it has none
+func (p *pptxPackage) SlideLayoutName(slideNumber int) string {
+	partName, err := p.slidePart(slideNumber)
+	if err != nil {
+		return ""
+	}
+	rels, err := p.relationships(partName)
+	if err != nil {
+		return ""
+	}
+
+	for _, target := range rels {
+		if !strings.HasPrefix(path.Base(target), "slideLayout") {
+			continue
+		}
+		var layout struct {
+			CSld struct {
+				Name string `xml:"name,attr"`
+			} `xml:"cSld"`
+		}
+		if err := p.decode(target, &layout); err == nil {
+			return layout.CSld.Name
+		}
+	}
+	return ""
+}
+
+// xmlShape captures the parts of any spTree child the reader cares about. Each shape kind
+// keeps its non-visual and transform properties under a differently named element.
+type xmlShape struct {
+	XMLName          xml.Name
+	NvSpPr           *xmlNonVisual `xml:"nvSpPr"`
+	NvPicPr          *xmlNonVisual `xml:"nvPicPr"`
+	NvGraphicFramePr *xmlNonVisual `xml:"nvGraphicFramePr"`
+	NvGrpSpPr        *xmlNonVisual `xml:"nvGrpSpPr"`
+	NvCxnSpPr        *xmlNonVisual `xml:"nvCxnSpPr"`
+	SpPrXfrm         *xmlTransform `xml:"spPr>xfrm"`
+	GrpSpPrXfrm      *xmlTransform `xml:"grpSpPr>xfrm"`
+	FrameXfrm        *xmlTransform `xml:"xfrm"`
+	TxBody           *xmlTextBody  `xml:"txBody"`
+	Blip             *struct {
+		Embed string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships embed,attr"`
+	} `xml:"blipFill>blip"`
+	SrcRect *struct {
+		L int `xml:"l,attr"`
+		T int `xml:"t,attr"`
+		R int `xml:"r,attr"`
+		B int `xml:"b,attr"`
+	} `xml:"blipFill>srcRect"`
+	GraphicData struct {
+		URI   string `xml:"uri,attr"`
+		Chart *struct {
+			RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"chart"`
+		TableRows []struct {
+			Cells []struct {
+				TxBody xmlTextBody `xml:"txBody"`
+			} `xml:"tc"`
+		} `xml:"tbl>tr"`
+	} `xml:"graphic>graphicData"`
+	Children []xmlShape `xml:",any"`
+}
+
+type xmlNonVisual struct {
+	CNvPr struct {
+		ID    int    `xml:"id,attr"`
+		Name  string `xml:"name,attr"`
+		Descr string `xml:"descr,attr"`
+	} `xml:"cNvPr"`
+	Placeholder *struct {
+		Type string `xml:"type,attr"`
+	} `xml:"nvPr>ph"`
+}
+
+type xmlTransform struct {
+	Offset struct {
+		X int64 `xml:"x,attr"`
+		Y int64 `xml:"y,attr"`
+	} `xml:"off"`
+	Extent struct {
+		CX int64 `xml:"cx,attr"`
+		CY int64 `xml:"cy,attr"`
+	} `xml:"ext"`
+}
+
+type xmlTextBody struct {
+	Paragraphs []struct {
+		Content []struct {
+			XMLName xml.Name
+			Text    string `xml:"t"`
+		} `xml:",any"`
+	} `xml:"p"`
+}
+
+// text joins the paragraphs of a text body with newlines, as LibreOffice's getString does
+func (b *xmlTextBody) text() string {
+	if b == nil {
+		return ""
+	}
+	paragraphs := make([]string, 0, len(b.Paragraphs))
+	for _, paragraph := range b.Paragraphs {
+		var line strings.Builder
+		for _, content := range paragraph.Content {
+			switch content.XMLName.Local {
+			case "r", "fld":
+				line.WriteString(content.Text)
+			case "br":
+				line.WriteString("\n")
+			}
+		}
+		paragraphs = append(paragraphs, line.String())
+	}
+	return strings.Join(paragraphs, "\n")
+}
+
+func (s xmlShape) toShape() pptxShape {
+	shape := pptxShape{Kind: s.XMLName.Local}
+
+	for _, nonVisual := range []*xmlNonVisual{s.NvSpPr, s.NvPicPr, s.NvGraphicFramePr, s.NvGrpSpPr, s.NvCxnSpPr} {
+		if nonVisual != nil {
+			shape.ID = nonVisual.CNvPr.ID
+			shape.Name = nonVisual.CNvPr.Name
+			shape.AltText = nonVisual.CNvPr.Descr
+			if nonVisual.Placeholder != nil {
+				shape.Placeholder = nonVisual.Placeholder.Type
+				// A placeholder without a type is a body placeholder
+				if shape.Placeholder == "" {
+					shape.Placeholder = "body"
+				}
+			}
+			break
+		}
+	}
+
+	for _, transform := range []*xmlTransform{s.SpPrXfrm, s.GrpSpPrXfrm, s.FrameXfrm} {
+		if transform != nil {
+			shape.X = emuToInches(transform.Offset.X)
+			shape.Y = emuToInches(transform.Offset.Y)
+			shape.Width = emuToInches(transform.Extent.CX)
+			shape.Height = emuToInches(transform.Extent.CY)
+			break
+		}
+	}
+
+	switch shape.Kind {
+	case "sp":
+		shape.HasText = true
+		shape.Text = s.TxBody.text()
+	case "grpSp":
+		for _, child := range s.Children {
+			switch child.XMLName.Local {
+			case "sp", "pic", "graphicFrame", "grpSp", "cxnSp", "contentPart", "AlternateContent":
+				shape.Children++
+			}
+		}
+	case "pic":
+		if s.Blip != nil {
+			shape.ImageRel = s.Blip.Embed
+		}
+		if s.SrcRect != nil {
+			// In thousandths of a percent; negative values pad the image instead
+			for i, value := range []int{s.SrcRect.L, s.SrcRect.T, s.SrcRect.R, s.SrcRect.B} {
+				shape.ImageCrop[i] = max(0, float64(value)/100000)
+			}
+		}
+	case "graphicFrame":
+		for _, row := range s.GraphicData.TableRows {
+			cells := make([]string, 0, len(row.Cells))
+			for _, cell := range row.Cells {
+				cells = append(cells, cell.TxBody.text())
+			}
+			shape.Table = append(shape.Table, cells)
+		}
+		if s.GraphicData.Chart != nil {
+			shape.ChartRel = s.GraphicData.Chart.RelID
+		}
+	}
+
+	return shape
+}
+
+// describeContent fills in the image and chart details of a slide's shapes. It is kept
+// out of SlideShapes so listing slides doesn't pay for decoding images and charts.
+// Missing or unreadable parts leave the details empty.
+func (p *pptxPackage) describeContent(slideNumber int, shapes []pptxShape) {
+	partName, err := p.slidePart(slideNumber)
+	if err != nil {
+		return
+	}
+	rels, err := p.relationships(partName)
+	if err != nil {
+		return
+	}
+
+	for i := range shapes {
+		shape := &shapes[i]
+		if target, ok := rels[shape.ImageRel]; ok && shape.ImageRel != "" {
+			shape.Image = p.imageInfo(target)
+		}
+		if target, ok := rels[shape.ChartRel]; ok && shape.ChartRel != "" {
+			if chart, err := p.chartInfo(target); err == nil {
+				shape.Chart = chart
+			}
+		}
+	}
+}
+
+// imageInfo describes a media part, with its pixel size when Go can decode the format
+func (p *pptxPackage) imageInfo(partName string) *pptxImage {
+	info := &pptxImage{Part: partName}
+	file, ok := p.parts[partName]
+	if !ok {
+		return nil
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return info
+	}
+	defer reader.Close()
+	if config, _, err := image.DecodeConfig(reader); err == nil {
+		info.PixelWidth, info.PixelHeight = config.Width, config.Height
+	}
+	return info
+}
+
+// xmlChartPoint is a cached category label or value of a chart series
+type xmlChartPoint struct {
+	Index int    `xml:"idx,attr"`
+	Value string `xml:"v"`
+}
+
+// chartInfo reads the type, title, categories and series of a chart part
+func (p *pptxPackage) chartInfo(partName string) (*pptxChart, error) {
+	var chartSpace struct {
+		Chart struct {
+			Title *struct {
+				Rich xmlTextBody `xml:"tx>rich"`
+			} `xml:"title"`
+			PlotArea struct {
+				Plots []struct {
+					XMLName xml.Name
+					BarDir  struct {
+						Val string `xml:"val,attr"`
+					} `xml:"barDir"`
+					Series []struct {
+						Name          []string        `xml:"tx>strRef>strCache>pt>v"`
+						LiteralName   string          `xml:"tx>v"`
+						StrCategories []xmlChartPoint `xml:"cat>strRef>strCache>pt"`
+						NumCategories []xmlChartPoint `xml:"cat>numRef>numCache>pt"`
+						Values        []xmlChartPoint `xml:"val>numRef>numCache>pt"`
+					} `xml:"ser"`
+				} `xml:",any"`
+			} `xml:"plotArea"`
+		} `xml:"chart"`
+	}
+	if err := p.decode(partName, &chartSpace); err != nil {
+		return nil, err
+	}
+
+	chart := &pptxChart{Part: partName, Categories: []string{}, Series: []pptxChartSeries{}}
+	if chartSpace.Chart.Title != nil {
+		chart.Title = chartSpace.Chart.Title.Rich.text()
+	}
+	for _, plot := range chartSpace.Chart.PlotArea.Plots {
+		kind, ok := strings.CutSuffix(plot.XMLName.Local, "Chart")
+		if !ok {
+			continue // layout, axes, ...
+		}
+		kind = strings.ToLower(strings.TrimSuffix(kind, "3D"))
+		if kind == "bar" && plot.BarDir.Val != "bar" {
+			kind = "column"
+		}
+		if chart.Type == "" {
+			chart.Type = kind
+		}
+
+		for _, series := range plot.Series {
+			name := series.LiteralName
+			if len(series.Name) > 0 {
+				name = series.Name[0]
+			}
+			categories := series.StrCategories
+			if len(categories) == 0 {
+				categories = series.NumCategories
+			}
+			if len(chart.Categories) == 0 {
+				chart.Categories = chartPointLabels(categories)
+			}
+			count := max(len(chart.Categories), len(series.Values))
+			values := make([]*float64, count)
+			for _, point := range series.Values {
+				if value, err := strconv.ParseFloat(point.Value, 64); err == nil && point.Index >= 0 && point.Index < count {
+					values[point.Index] = &value
+				}
+			}
+			chart.Series = append(chart.Series, pptxChartSeries{Name: name, Values: values})
+		}
+	}
+	return chart, nil
+}
+
+// chartPointLabels orders cached category labels by index
+func chartPointLabels(points []xmlChartPoint) []string {
+	count := 0
+	for _, point := range points {
+		count = max(count, point.Index+1)
+	}
+	labels := make([]string, count)
+	for _, point := range points {
+		if point.Index >= 0 {
+			labels[point.Index] = point.Value
+		}
+	}
+	return labels
+}
+
+// emuToInches converts EMUs to inches rounded to two decimals, like the UNO scripts report
+func emuToInches(emu int64) float64 {
+	return math.Round(float64(emu)/emuPerInch*100) / 100
+}
+
+// Shape classification mirrors scripts/slide_analyzer.py so the native and UNO readers
+// describe shapes the same way and the edit hints stay valid.
+const (
+	shapeTypeTitle      = "title"
+	shapeTypeBulletList = "bullet_list"
+	shapeTypeTextBox    = "text_box"
+	shapeTypeTable      = "table"
+	shapeTypeNonText    = "non_text"
+	shapeTypeEmptyText  = "empty_text"
+)
+
+var (
+	bulletMarkerPattern = regexp.MustCompile(`^[•·*-]\s*`)
+	bulletLinePattern   = regexp.MustCompile(`^\s*[•·*-]\s+`)
+)
+
+// slideShapeInfo is a shape as reported by read_slide
+type slideShapeInfo struct {
+	ShapeIndex   int               `json:"shape_index"`
+	ShapeID      string            `json:"shape_id"`
+	Kind         string            `json:"kind"`
+	ShapeType    string            `json:"shape_type"`
+	Text         string            `json:"text"`
+	Description  string            `json:"description"`
+	BulletPoints []bulletPointInfo `json:"bullet_points,omitempty"`
+	EditHint     string            `json:"edit_hint,omitempty"`
+	Name         string            `json:"name,omitempty"`
+	Placeholder  string            `json:"placeholder,omitempty"`
+	AltText      string            `json:"alt_text,omitempty"`
+	Table        [][]string        `json:"table,omitempty"`
+	Image        *pptxImage        `json:"image,omitempty"`
+	Chart        *pptxChart        `json:"chart,omitempty"`
+	X            float64           `json:"x"`
+	Y            float64           `json:"y"`
+	Width        float64           `json:"width"`
+	Height       float64           `json:"height"`
+}
+
+type bulletPointInfo struct {
+	Index int    `json:"index"`
+	Text  string `json:"text"`
+}
+
+// Shape kinds reported by read_slide next to the text classification; keep in sync
+// with shape_kind in scripts/uno_read_slide.py
+const (
+	shapeKindShape     = "shape"
+	shapeKindPicture   = "picture"
+	shapeKindTable     = "table"
+	shapeKindChart     = "chart"
+	shapeKindGroup     = "group"
+	shapeKindConnector = "connector"
+	shapeKindGraphic   = "graphic" // Other graphic frames: SmartArt, OLE objects, media
+)
+
+// shapeKind names what a shape is, whatever its text
+func shapeKind(shape pptxShape) string {
+	switch {
+	case shape.Table != nil:
+		return shapeKindTable
+	case shape.ChartRel != "":
+		return shapeKindChart
+	}
+	switch shape.Kind {
+	case "pic":
+		return shapeKindPicture
+	case "grpSp":
+		return shapeKindGroup
+	case "cxnSp":
+		return shapeKindConnector
+	case "graphicFrame":
+		return shapeKindGraphic
+	}
+	return shapeKindShape
+}
+
+// shapeIDs returns the stable ID of each shape: its name, followed by " #n" (the nth
+// shape of that name) when the name isn't unique on the slide. Unlike cNvPr ids, which
+// LibreOffice renumbers on every save, names survive edits. Mirrors shape_ids in
+// scripts/uno_read_slide.py.
+func shapeIDs(names []string) []string {
+	counts := map[string]int{}
+	for _, name := range names {
+		counts[name]++
+	}
+	seen := map[string]int{}
+	ids := make([]string, len(names))
+	for i, name := range names {
+		seen[name]++
+		switch {
+		case name == "":
+			ids[i] = fmt.Sprintf("#%d", seen[name])
+		case counts[name] > 1:
+			ids[i] = fmt.Sprintf("%s #%d", name, seen[name])
+		default:
+			ids[i] = name
+		}
+	}
+	return ids
+}
+
+// analyzeShape classifies a shape and suggests how to edit it
+func analyzeShape(shape pptxShape, index int) slideShapeInfo {
+	text := strings.TrimSpace(shape.Text)
+	info := slideShapeInfo{
+		ShapeIndex:  index,
+		Kind:        shapeKind(shape),
+		Text:        text,
+		Name:        shape.Name,
+		AltText:     shape.AltText,
+		Image:       shape.Image,
+		Chart:       shape.Chart,
+		Placeholder: shape.Placeholder,
+		X:           shape.X,
+		Y:           shape.Y,
+		Width:       shape.Width,
+		Height:      shape.Height,
+	}
+
+	switch {
+	case shape.Table != nil:
+		info.ShapeType = shapeTypeTable
+		info.Table = shape.Table
+		info.Description = fmt.Sprintf("Table with %d rows", len(shape.Table))
+		info.EditHint = fmt.Sprintf("Use edit_table_cell with shape_index=%d to edit cells", index)
+	case !shape.HasText:
+		info.ShapeType = shapeTypeNonText
+		info.Description = "Non-text shape (image, chart, etc.)"
+		switch info.Kind {
+		case shapeKindGroup:
+			info.Description = fmt.Sprintf("Group of %d shapes", shape.Children)
+		case shapeKindPicture:
+			info.Description = "Picture"
+			if shape.Image != nil {
+				info.Description = fmt.Sprintf("Picture (%s", path.Base(shape.Image.Part))
+				if shape.Image.PixelWidth > 0 {
+					info.Description += fmt.Sprintf(", %dx%d px", shape.Image.PixelWidth, shape.Image.PixelHeight)
+				}
+				info.Description += ")"
+			}
+		case shapeKindChart:
+			info.Description = "Chart"
+			if shape.Chart != nil {
+				info.Description = fmt.Sprintf("%s chart with %d series and %d categories", shape.Chart.Type, len(shape.Chart.Series), len(shape.Chart.Categories))
+				if shape.Chart.Title != "" {
+					info.Description += fmt.Sprintf(": %s", shape.Chart.Title)
+				}
+			}
+			info.EditHint = fmt.Sprintf("Use edit_chart_data with shape_index=%d to read or update its data", index)
+		case shapeKindConnector:
+			info.Description = "Connector line"
+		}
+	case text == "":
+		info.ShapeType = shapeTypeEmptyText
+		info.Description = "Empty text shape"
+	case isTitleContent(text):
+		info.ShapeType = shapeTypeTitle
+		info.Description = "Main slide title"
+		info.EditHint = fmt.Sprintf("Use target_type='shape_index' with target_value='%d' to edit", index)
+	case isBulletContent(text):
+		info.ShapeType = shapeTypeBulletList
+		info.Description = "Bullet list content"
+		info.BulletPoints = parseBulletPoints(text)
+		info.EditHint = fmt.Sprintf("Use target_type='bullet_list' with target_value='%d' for proper bullet formatting. Provide text WITHOUT bullet characters - LibreOffice will add them automatically.", index)
+	default:
+		info.ShapeType = shapeTypeTextBox
+		info.Description = fmt.Sprintf("Text box containing: %s...", truncateRunes(text, 50))
+		info.EditHint = fmt.Sprintf("Use target_type='shape_index' with target_value='%d' to edit", index)
+	}
+
+	return info
+}
+
+// isTitleContent reports whether text looks like a title: short, single-line, no bullets
+func isTitleContent(text string) bool {
+	return len([]rune(text)) < 100 && !strings.Contains(text, "\n") && !isBulletContent(text)
+}
+
+// isBulletContent reports whether text looks like a bullet list
+func isBulletContent(text string) bool {
+	if strings.ContainsAny(text, "•·*-") || strings.Count(text, "\n") > 1 {
+		return true
+	}
+	bulletLines := 0
+	for _, line := range strings.Split(text, "\n") {
+		if bulletLinePattern.MatchString(line) {
+			bulletLines++
+		}
+	}
+	return bulletLines >= 2
+}
+
+// parseBulletPoints splits text into bullet points, indexed by line
+func parseBulletPoints(text string) []bulletPointInfo {
+	var points []bulletPointInfo
+	for i, line := range strings.Split(text, "\n") {
+		clean := bulletMarkerPattern.ReplaceAllString(strings.TrimSpace(line), "")
+		if clean != "" {
+			points = append(points, bulletPointInfo{Index: i, Text: clean})
+		}
+	}
+	return points
+}
+
+// truncateRunes shortens text to at most n characters
+func truncateRunes(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n])
+}
+
+// slideTitle returns a slide's title: the title placeholder's text, else the first shape's
+// text, shortened for listings
+func slideTitle(shapes []pptxShape) string {
+	title := ""
+	for _, shape := range shapes {
+		if shape.Placeholder == "title" || shape.Placeholder == "ctrTitle" {
+			title = strings.TrimSpace(shape.Text)
+			break
+		}
+	}
+	if title == "" && len(shapes) > 0 {
+		title = strings.TrimSpace(shapes[0].Text)
+	}
+	if title == "" {
+		return "Untitled"
+	}
+	if len([]rune(title)) > 50 {
+		return truncateRunes(title, 50) + "..."
+	}
+	return title
+}
+
+// listSlidesNative builds the list_slides result for slides offset+1 .. offset+limit
+func listSlidesNative(presentationPath string, offset, limit int, titlesOnly bool) (string, error) {
+	pkg, err := openPPTX(presentationPath)
+	if err != nil {
+		return "", err
+	}
+	defer pkg.Close()
+
+	total := pkg.SlideCount()
+	end := min(total, offset+limit)
+	sections, err := pkg.Sections()
+	if err != nil {
+		return "", err
+	}
+	var sectionOf map[int]string
+	if len(sections) > 0 {
+		ids, err := pkg.slideIDs()
+		if err != nil {
+			return "", err
+		}
+		sectionOf = sectionNames(sections, ids)
+	}
+	slides := make([]SlideSummary, 0, max(end-offset, 0))
+	for slideNumber := offset + 1; slideNumber <= end; slideNumber++ {
+		shapes, err := pkg.SlideShapes(slideNumber)
+		if err != nil {
+			return "", err
+		}
+		summary := SlideSummary{SlideNumber: slideNumber, Title: slideTitle(shapes), Section: sectionOf[slideNumber]}
+		if !titlesOnly {
+			textShapes := 0
+			for _, shape := range shapes {
+				if shape.HasText {
+					textShapes++
+				}
+			}
+			summary.TextShapes = &textShapes
+			summary.Layout = pkg.SlideLayoutName(slideNumber)
+			if summary.Layout == "" {
+				summary.Layout = "Unknown Layout"
+			}
+		}
+		slides = append(slides, summary)
+	}
+
+	result := SlideList{
+		TotalSlides: total,
+		Offset:      offset,
+		Returned:    len(slides),
+		HasMore:     end < total,
+		Slides:      slides,
+	}
+	if end < total {
+		result.NextOffset = &end
+	}
+	return marshalResult(result)
+}
+
+// readSlideNative builds the read_slide result for a 1-based slide number
+func readSlideNative(presentationPath string, slideNumber int) (string, error) {
+	pkg, err := openPPTX(presentationPath)
+	if err != nil {
+		return "", err
+	}
+	defer pkg.Close()
+
+	shapes, err := pkg.SlideShapes(slideNumber)
+	if err != nil {
+		return "", err
+	}
+	pkg.describeContent(slideNumber, shapes)
+
+	names := make([]string, len(shapes))
+	for i, shape := range shapes {
+		names[i] = shape.Name
+	}
+	ids := shapeIDs(names)
+
+	infos := make([]slideShapeInfo, 0, len(shapes))
+	for i, shape := range shapes {
+		info := analyzeShape(shape, i)
+		info.ShapeID = ids[i]
+		infos = append(infos, info)
+	}
+
+	return marshalResult(SlideDetail{
+		SlideNumber: slideNumber,
+		TotalShapes: len(shapes),
+		Shapes:      infos,
+	})
+}
+
+// presentationInfo is the get_presentation_info result: document properties, slide size
+// and slide count
+type presentationInfo struct {
+	Title          string  `json:"title"`
+	Author         string  `json:"author"`
+	Subject        string  `json:"subject"`
+	Company        string  `json:"company"`
+	Keywords       string  `json:"keywords,omitempty"`
+	LastModifiedBy string  `json:"last_modified_by,omitempty"`
+	Created        string  `json:"created,omitempty"`
+	Modified       string  `json:"modified,omitempty"`
+	SlideCount     int     `json:"slide_count"`
+	SlideWidth     float64 `json:"slide_width"`  // Inches
+	SlideHeight    float64 `json:"slide_height"` // Inches
+	AspectRatio    string  `json:"aspect_ratio"`
+}
+
+// commonAspectRatios are reported by name when a slide size matches them
+var commonAspectRatios = []struct {
+	name  string
+	ratio float64
+}{
+	{"16:9", 16.0 / 9}, {"16:10", 16.0 / 10}, {"4:3", 4.0 / 3}, {"3:2", 3.0 / 2}, {"1:1", 1}, {"9:16", 9.0 / 16}, {"3:4", 3.0 / 4},
+}
+
+// aspectRatioName names the aspect ratio of a slide size, e.g. "16:9", or gives it as
+// "1.85:1" when it isn't a common one
+func aspectRatioName(width, height float64) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	ratio := width / height
+	for _, common := range commonAspectRatios {
+		if math.Abs(ratio-common.ratio)/common.ratio < 0.01 {
+			return common.name
+		}
+	}
+	return fmt.Sprintf("%.2f:1", ratio)
+}
+
+// presentationInfoNative reads the document properties and slide size of a .pptx.
+// Missing property parts leave their fields empty.
+func presentationInfoNative(presentationPath string) (presentationInfo, error) {
+	pkg, err := openPPTX(presentationPath)
+	if err != nil {
+		return presentationInfo{}, err
+	}
+	defer pkg.Close()
+
+	var presentation struct {
+		SlideSize struct {
+			Width  int64 `xml:"cx,attr"`
+			Height int64 `xml:"cy,attr"`
+		} `xml:"sldSz"`
+	}
+	if err := pkg.decode("ppt/presentation.xml", &presentation); err != nil {
+		return presentationInfo{}, err
+	}
+	info := presentationInfo{
+		SlideCount:  pkg.SlideCount(),
+		SlideWidth:  emuToInches(presentation.SlideSize.Width),
+		SlideHeight: emuToInches(presentation.SlideSize.Height),
+	}
+	info.AspectRatio = aspectRatioName(float64(presentation.SlideSize.Width), float64(presentation.SlideSize.Height))
+
+	if _, ok := pkg.parts["docProps/core.xml"]; ok {
+		var core struct {
+			Title          string `xml:"http://purl.org/dc/elements/1.1/ title"`
+			Creator        string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+			Subject        string `xml:"http://purl.org/dc/elements/1.1/ subject"`
+			Keywords       string `xml:"http://schemas.openxmlformats.org/package/2006/metadata/core-properties keywords"`
+			LastModifiedBy string `xml:"http://schemas.openxmlformats.org/package/2006/metadata/core-properties lastModifiedBy"`
+			Created        string `xml:"http://purl.org/dc/terms/ created"`
+			Modified       string `xml:"http://purl.org/dc/terms/ modified"`
+		}
+		if err := pkg.decode("docProps/core.xml", &core); err != nil {
+			return presentationInfo{}, err
+		}
+		info.Title, info.Author, info.Subject = core.Title, core.Creator, core.Subject
+		info.Keywords, info.LastModifiedBy = core.Keywords, core.LastModifiedBy
+		info.Created, info.Modified = core.Created, core.Modified
+	}
+
+	if _, ok := pkg.parts["docProps/app.xml"]; ok {
+		var app struct {
+			Company string `xml:"Company"`
+		}
+		if err := pkg.decode("docProps/app.xml", &app); err != nil {
+			return presentationInfo{}, err
+		}
+		info.Company = app.Company
+	}
+
+	return info, nil
+}
+
+// pptxLayout is a slide layout of the deck's template
+type pptxLayout struct {
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`   // OOXML layout type such as title, obj or twoObj; "cust" when unset
+	Master       int      `json:"master"` // 1-based slide master the layout belongs to
+	Placeholders []string `json:"placeholders"`
+	Slides       []int    `json:"slides"` // Slides using the layout
+	part         string
+}
+
+// Layouts returns the slide layouts of every master, in template order
+func (p *pptxPackage) Layouts() ([]pptxLayout, error) {
+	var presentation struct {
+		MasterIDs []struct {
+			RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sldMasterIdLst>sldMasterId"`
+	}
+	if err := p.decode("ppt/presentation.xml", &presentation); err != nil {
+		return nil, err
+	}
+	presentationRels, err := p.relationships("ppt/presentation.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	var layouts []pptxLayout
+	byPart := map[string]int{}
+	for masterIndex, masterID := range presentation.MasterIDs {
+		masterPart, ok := presentationRels[masterID.RelID]
+		if !ok {
+			return nil, fmt.Errorf("presentation.xml references missing relationship %s", masterID.RelID)
+		}
+		var master struct {
+			LayoutIDs []struct {
+				RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+			} `xml:"sldLayoutIdLst>sldLayoutId"`
+		}
+		if err := p.decode(masterPart, &master); err != nil {
+			return nil, err
+		}
+		masterRels, err := p.relationships(masterPart)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, layoutID := range master.LayoutIDs {
+			layoutPart, ok := masterRels[layoutID.RelID]
+			if !ok {
+				continue
+			}
+			var layout struct {
+				Type string `xml:"type,attr"`
+				CSld struct {
+					Name   string     `xml:"name,attr"`
+					Shapes []xmlShape `xml:"spTree>sp"`
+				} `xml:"cSld"`
+			}
+			if err := p.decode(layoutPart, &layout); err != nil {
+				return nil, err
+			}
+			if layout.Type == "" {
+				layout.Type = "cust"
+			}
+
+			placeholders := []string{}
+			for _, shape := range layout.CSld.Shapes {
+				placeholder := shape.toShape().Placeholder
+				switch placeholder {
+				case "", "dt", "ftr", "sldNum":
+					// Not a placeholder, or one the footer settings control
+				default:
+					placeholders = append(placeholders, placeholder)
+				}
+			}
+
+			byPart[layoutPart] = len(layouts)
+			layouts = append(layouts, pptxLayout{
+				Name:         layout.CSld.Name,
+				Type:         layout.Type,
+				Master:       masterIndex + 1,
+				Placeholders: placeholders,
+				Slides:       []int{},
+				part:         layoutPart,
+			})
+		}
+	}
+
+	for i, slidePart := range p.slides {
+		rels, err := p.relationships(slidePart)
+		if err != nil {
+			return nil, err
+		}
+		for _, target := range rels {
+			if index, ok := byPart[target]; ok {
+				layouts[index].Slides = append(layouts[index].Slides, i+1)
+			}
+		}
+	}
+	return layouts, nil
+}
+
+// findLayout returns the layout called name, or failing that the first of that type.
+// Names and types are matched case-insensitively.
+func findLayout(layouts []pptxLayout, name string) (pptxLayout, bool) {
+	for _, layout := range layouts {
+		if strings.EqualFold(layout.Name, name) {
+			return layout, true
+		}
+	}
+	for _, layout := range layouts {
+		if strings.EqualFold(layout.Type, name) {
+			return layout, true
+		}
+	}
+	return pptxLayout{}, false
+}
+
+// listLayoutsNative builds the list_layouts result
+func listLayoutsNative(presentationPath string) (string, error) {
+	pkg, err := openPPTX(presentationPath)
+	if err != nil {
+		return "", err
+	}
+	defer pkg.Close()
+
+	layouts, err := pkg.Layouts()
+	if err != nil {
+		return "", err
+	}
+	resultJSON, _ := json.Marshal(map[string]interface{}{
+		"total_layouts": len(layouts),
+		"layouts":       layouts,
+	})
+	return string(resultJSON), nil
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadSlideNativeClassifiesShapes(t *testing.T) {
+	result, err := readSlideNative(filepath.Join("testdata", "two_slides.pptx"), 1)
+	if err != nil {
+		t.Fatalf("readSlideNative failed: %v", err)
+	}
+
+	var slide struct {
+		TotalShapes int              `json:"total_shapes"`
+		Shapes      []slideShapeInfo `json:"shapes"`
+	}
+	if err := json.Unmarshal([]byte(result), &slide); err != nil {
+		t.Fatalf("invalid result %s: %v", result, err)
+	}
+	if slide.TotalShapes != 2 {
+		t.Fatalf("expected 2 shapes, got %d", slide.TotalShapes)
+	}
+
+	title := slide.Shapes[0]
+	if title.ShapeType != shapeTypeTitle || title.Text != "Quarterly Review" || title.Placeholder != "title" {
+		t.Errorf("unexpected title shape: %+v", title)
+	}
+	if title.X != 0.92 || title.Width != 11.5 {
+		t.Errorf("expected position in inches, got x=%v width=%v", title.X, title.Width)
+	}
+
+	body := slide.Shapes[1]
+	if body.ShapeType != shapeTypeBulletList || len(body.BulletPoints) != 3 || body.BulletPoints[2].Text != "Two new regions" {
+		t.Errorf("unexpected body shape: %+v", body)
+	}
+}
+
+func TestReadSlideNativeKeepsShapeIndexesOfNonTextShapes(t *testing.T) {
+	pkg, err := openPPTX(filepath.Join("testdata", "mixed_shapes.pptx"))
+	if err != nil {
+		t.Fatalf("openPPTX failed: %v", err)
+	}
+	defer pkg.Close()
+
+	shapes, err := pkg.SlideShapes(1)
+	if err != nil {
+		t.Fatalf("SlideShapes failed: %v", err)
+	}
+
+	// Every top-level spTree child is one shape, in document order, like LibreOffice imports them
+	expected := []struct {
+		kind, shapeType string
+	}{
+		{"sp", shapeTypeTitle},
+		{"pic", shapeTypeNonText},
+		{"grpSp", shapeTypeNonText},
+		{"graphicFrame", shapeTypeTable},
+		{"cxnSp", shapeTypeNonText},
+		{"sp", shapeTypeEmptyText},
+		{"pic", shapeTypeNonText},
+		{"graphicFrame", shapeTypeNonText},
+	}
+	if len(shapes) != len(expected) {
+		t.Fatalf("expected %d shapes, got %d: %+v", len(expected), len(shapes), shapes)
+	}
+	for i, want := range expected {
+		info := analyzeShape(shapes[i], i)
+		if shapes[i].Kind != want.kind || info.ShapeType != want.shapeType {
+			t.Errorf("shape %d: expected %s/%s, got %s/%s", i, want.kind, want.shapeType, shapes[i].Kind, info.ShapeType)
+		}
+	}
+
+	if shapes[2].Children != 2 {
+		t.Errorf("expected the group to contain 2 shapes, got %d", shapes[2].Children)
+	}
+	if len(shapes[3].Table) != 2 || shapes[3].Table[1][1] != "$1.2M" {
+		t.Errorf("unexpected table cells: %v", shapes[3].Table)
+	}
+	if shapes[5].Placeholder != "body" {
+		t.Errorf("expected an untyped placeholder to be a body placeholder, got %q", shapes[5].Placeholder)
+	}
+
+	if _, err := pkg.SlideShapes(2); !errors.Is(err, errSlideOutOfRange) {
+		t.Errorf("expected errSlideOutOfRange, got %v", err)
+	}
+}
+
+func TestReadSlideNativeDescribesPicturesAndCharts(t *testing.T) {
+	result, err := readSlideNative(filepath.Join("testdata", "mixed_shapes.pptx"), 1)
+	if err != nil {
+		t.Fatalf("readSlideNative failed: %v", err)
+	}
+
+	var slide struct {
+		Shapes []slideShapeInfo `json:"shapes"`
+	}
+	if err := json.Unmarshal([]byte(result), &slide); err != nil {
+		t.Fatalf("invalid result %s: %v", result, err)
+	}
+
+	var kinds, ids []string
+	for _, shape := range slide.Shapes {
+		kinds = append(kinds, shape.Kind)
+		ids = append(ids, shape.ShapeID)
+	}
+	if fmt.Sprint(kinds) != "[shape picture group table connector shape picture chart]" {
+		t.Errorf("unexpected kinds: %v", kinds)
+	}
+	// Shapes sharing a name are told apart by their position among that name
+	if ids[1] != "Picture 2 #1" || ids[6] != "Picture 2 #2" || ids[3] != "Table 6" {
+		t.Errorf("unexpected shape IDs: %v", ids)
+	}
+
+	picture := slide.Shapes[1]
+	if picture.AltText != "Map of the regions" || picture.Image == nil ||
+		picture.Image.Part != "ppt/media/image1.png" || picture.Image.PixelWidth != 1 || picture.Image.PixelHeight != 1 {
+		t.Errorf("unexpected picture: %+v (image %+v)", picture, picture.Image)
+	}
+	if slide.Shapes[6].Image != nil {
+		t.Errorf("expected no image details for a missing relationship, got %+v", slide.Shapes[6].Image)
+	}
+
+	chart := slide.Shapes[7].Chart
+	if chart == nil {
+		t.Fatalf("expected chart details, got %+v", slide.Shapes[7])
+	}
+	if chart.Type != "column" || chart.Title != "Revenue" || fmt.Sprint(chart.Categories) != "[North South]" || len(chart.Series) != 2 {
+		t.Fatalf("unexpected chart: %+v", chart)
+	}
+	if series := chart.Series[1]; series.Name != "2025" || *series.Values[0] != 1.5 || *series.Values[1] != 1.1 {
+		t.Errorf("unexpected second series: %+v", series)
+	}
+}
+
+func TestShapeIDs(t *testing.T) {
+	ids := shapeIDs([]string{"Title 1", "Logo", "", "Logo", ""})
+	if fmt.Sprint(ids) != "[Title 1 Logo #1 #1 Logo #2 #2]" {
+		t.Errorf("unexpected IDs: %q", ids)
+	}
+}
+
+func TestPresentationInfoNative(t *testing.T) {
+	info, err := presentationInfoNative(filepath.Join("testdata", "two_slides.pptx"))
+	if err != nil {
+		t.Fatalf("presentationInfoNative failed: %v", err)
+	}
+	want := presentationInfo{
+		Title:       "Fixture Deck",
+		Author:      "SlidePilot Tests",
+		SlideCount:  2,
+		SlideWidth:  13.33,
+		SlideHeight: 7.5,
+		AspectRatio: "16:9",
+	}
+	if info != want {
+		t.Errorf("expected %+v, got %+v", want, info)
+	}
+
+	for _, tc := range []struct {
+		width, height float64
+		want          string
+	}{{10, 7.5, "4:3"}, {10, 6.25, "16:10"}, {8.5, 11, "0.77:1"}, {0, 0, ""}} {
+		if got := aspectRatioName(tc.width, tc.height); got != tc.want {
+			t.Errorf("aspectRatioName(%v, %v) = %q, want %q", tc.width, tc.height, got, tc.want)
+		}
+	}
+}
+
+func TestLayoutsListsTemplateLayouts(t *testing.T) {
+	pkg, err := openPPTX(filepath.Join("testdata", "two_slides.pptx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pkg.Close()
+
+	layouts, err := pkg.Layouts()
+	if err != nil {
+		t.Fatalf("Layouts failed: %v", err)
+	}
+	if len(layouts) != 1 || layouts[0].Name != "Title and Content" || layouts[0].Type != "obj" {
+		t.Fatalf("unexpected layouts: %+v", layouts)
+	}
+	if fmt.Sprint(layouts[0].Slides) != "[1 2]" {
+		t.Errorf("expected both slides to use the layout, got %v", layouts[0].Slides)
+	}
+
+	for _, name := range []string{"title and content", "OBJ"} {
+		if _, ok := findLayout(layouts, name); !ok {
+			t.Errorf("expected %q to match the layout", name)
+		}
+	}
+	if _, ok := findLayout(layouts, "Two Content"); ok {
+		t.Error("expected no match for a missing layout")
+	}
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Sections are a PowerPoint 2010 extension of presentation.xml: a p14:sectionLst in the
+// presentation's extLst naming consecutive runs of slides by their sldId. LibreOffice
+// doesn't know them and leaves them out whenever it saves, so they are read and written
+// here and put back after edits that went through LibreOffice (restoreSections).
+
+const (
+	sectionExtURI    = "{521415D9-36F7-43E2-AB2F-B90AF26B5E84}"
+	sectionNamespace = "http://schemas.microsoft.com/office/powerpoint/2010/main"
+)
+
+var (
+	sectionListPattern   = regexp.MustCompile(`(?s)<(\w+:)?sectionLst\b(?:[^>]*/>|.*?</(?:\w+:)?sectionLst>)`)
+	sectionPattern       = regexp.MustCompile(`(?s)<(?:\w+:)?section\b([^>]*?)(?:/>|>(.*?)</(?:\w+:)?section>)`)
+	sectionSlidePattern  = regexp.MustCompile(`<(?:\w+:)?sldId\b[^>]*?\sid="(\d+)"`)
+	nameAttrPattern      = regexp.MustCompile(`\sname="([^"]*)"`)
+	extListPattern       = regexp.MustCompile(`<(\w+:)?extLst>`)
+	presentationEndTag   = regexp.MustCompile(`</(?:\w+:)?presentation>\s*$`)
+	sectionIDAttrPattern = regexp.MustCompile(`\sid="([^"]*)"`)
+)
+
+// pptxSection is one section of the deck: its name, GUID and slides by sldId
+type pptxSection struct {
+	Name     string
+	ID       string
+	SlideIDs []string
+}
+
+// slideIDs returns the sldId ids of the slides in presentation order
+func (p *pptxPackage) slideIDs() ([]string, error) {
+	presentationXML, err := p.readPart("ppt/presentation.xml")
+	if err != nil {
+		return nil, err
+	}
+	return slideIDsOf(presentationXML), nil
+}
+
+// slideIDsOf returns the sldId ids listed in presentation.xml's slide list
+func slideIDsOf(presentationXML []byte) []string {
+	list := slideIDListPattern.Find(presentationXML)
+	var ids []string
+	for _, entry := range slideIDPattern.FindAll(list, -1) {
+		if id := idAttrPattern.FindSubmatch(entry); id != nil {
+			ids = append(ids, string(id[2]))
+		}
+	}
+	return ids
+}
+
+// Sections returns the deck's sections in order; nil when it has none
+func (p *pptxPackage) Sections() ([]pptxSection, error) {
+	presentationXML, err := p.readPart("ppt/presentation.xml")
+	if err != nil {
+		return nil, err
+	}
+	sections, _ := parseSections(presentationXML)
+	return sections, nil
+}
+
+// parseSections reads the section list of presentation.xml and reports whether there is one
+func parseSections(presentationXML []byte) ([]pptxSection, bool) {
+	list := sectionListPattern.Find(presentationXML)
+	if list == nil {
+		return nil, false
+	}
+	var sections []pptxSection
+	for _, match := range sectionPattern.FindAllSubmatch(list, -1) {
+		section := pptxSection{}
+		if name := nameAttrPattern.FindSubmatch(match[1]); name != nil {
+			section.Name = html.UnescapeString(string(name[1]))
+		}
+		if id := sectionIDAttrPattern.FindSubmatch(match[1]); id != nil {
+			section.ID = string(id[1])
+		}
+		for _, slide := range sectionSlidePattern.FindAllSubmatch(match[2], -1) {
+			section.SlideIDs = append(section.SlideIDs, string(slide[1]))
+		}
+		sections = append(sections, section)
+	}
+	return sections, true
+}
+
+// withSlideOrder returns presentation.xml with its slide list in the order of ids, which
+// must hold the listed slides' ids
+func withSlideOrder(presentationXML []byte, ids []string) ([]byte, error) {
+	match := slideIDListPattern.FindSubmatchIndex(presentationXML)
+	if match == nil {
+		return presentationXML, nil
+	}
+	entries := map[string][]byte{}
+	for _, entry := range slideIDPattern.FindAll(presentationXML[match[0]:match[1]], -1) {
+		if id := idAttrPattern.FindSubmatch(entry); id != nil {
+			entries[string(id[2])] = entry
+		}
+	}
+	if len(ids) != len(entries) {
+		return nil, fmt.Errorf("slide order lists %d slides, the presentation has %d", len(ids), len(entries))
+	}
+	prefix := string(presentationXML[max(match[2], 0):max(match[3], 0)])
+	var list bytes.Buffer
+	fmt.Fprintf(&list, "<%ssldIdLst>", prefix)
+	for _, id := range ids {
+		entry, ok := entries[id]
+		if !ok {
+			return nil, fmt.Errorf("slide order lists unknown slide id %s", id)
+		}
+		list.Write(entry)
+	}
+	fmt.Fprintf(&list, "</%ssldIdLst>", prefix)
+	return spliceBytes(presentationXML, match[0], match[1], list.Bytes()), nil
+}
+
+// placeInSections adds slides to the section holding the slide after, right behind it, or
+// to the start of the first section when after is ""
+func placeInSections(sections []pptxSection, after string, ids []string) []pptxSection {
+	target, at := 0, 0
+	for i, section := range sections {
+		for j, id := range section.SlideIDs {
+			if id == after {
+				target, at = i, j+1
+			}
+		}
+	}
+	slides := append([]string(nil), sections[target].SlideIDs[:at]...)
+	slides = append(slides, ids...)
+	sections[target].SlideIDs = append(slides, sections[target].SlideIDs[at:]...)
+	return sections
+}
+
+// newSectionID returns a fresh section GUID such as {0F7A...}
+func newSectionID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40 // Version 4
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("{%X-%X-%X-%X-%X}", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// withSections returns presentation.xml with its section list replaced by sections,
+// adding one (and an extLst) when it has none
+func withSections(presentationXML []byte, sections []pptxSection) []byte {
+	prefix := "p14:"
+	if match := sectionListPattern.FindSubmatch(presentationXML); match != nil && len(match[1]) > 0 {
+		prefix = string(match[1])
+	}
+	var list bytes.Buffer
+	fmt.Fprintf(&list, `<%ssectionLst xmlns:%s="%s">`, prefix, strings.TrimSuffix(prefix, ":"), sectionNamespace)
+	for _, section := range sections {
+		if section.ID == "" {
+			section.ID = newSectionID()
+		}
+		fmt.Fprintf(&list, `<%ssection name="%s" id="%s"><%ssldIdLst>`, prefix, xmlAttr(section.Name), xmlAttr(section.ID), prefix)
+		for _, id := range section.SlideIDs {
+			fmt.Fprintf(&list, `<%ssldId id="%s"/>`, prefix, id)
+		}
+		fmt.Fprintf(&list, `</%ssldIdLst></%ssection>`, prefix, prefix)
+	}
+	fmt.Fprintf(&list, `</%ssectionLst>`, prefix)
+
+	if loc := sectionListPattern.FindIndex(presentationXML); loc != nil {
+		return spliceBytes(presentationXML, loc[0], loc[1], list.Bytes())
+	}
+	presentationPrefix := "p:"
+	if match := slideIDListPattern.FindSubmatch(presentationXML); match != nil {
+		presentationPrefix = string(match[1])
+	}
+	ext := []byte(fmt.Sprintf(`<%sext uri="%s">%s</%sext>`, presentationPrefix, sectionExtURI, list.Bytes(), presentationPrefix))
+	if loc := extListPattern.FindIndex(presentationXML); loc != nil {
+		return spliceBytes(presentationXML, loc[1], loc[1], ext)
+	}
+	extList := []byte(fmt.Sprintf(`<%sextLst>%s</%sextLst>`, presentationPrefix, ext, presentationPrefix))
+	loc := presentationEndTag.FindIndex(presentationXML)
+	if loc == nil {
+		return presentationXML
+	}
+	return spliceBytes(presentationXML, loc[0], loc[0], extList)
+}
+
+// rewritePresentationXML replaces presentation.xml of a .pptx with edit's result, writing
+// the package to a temporary file next to it and renaming that over it
+func rewritePresentationXML(presentationPath string, edit func(pkg *pptxPackage, presentationXML []byte) ([]byte, error)) error {
+	tempPath, err := writeEditedPresentationXML(presentationPath, edit)
+	if err != nil {
+		return err
+	}
+	return replacePresentation(tempPath, presentationPath)
+}
+
+// writeEditedPresentationXML writes the package with edit's presentation.xml to a temporary
+// file next to it and returns that file's path
+func writeEditedPresentationXML(presentationPath string, edit func(pkg *pptxPackage, presentationXML []byte) ([]byte, error)) (string, error) {
+	return writeEditedPackage(presentationPath, func(pkg *pptxPackage) (map[string][]byte, error) {
+		presentationXML, err := pkg.readPart("ppt/presentation.xml")
+		if err != nil {
+			return nil, err
+		}
+		presentationXML, err = edit(pkg, presentationXML)
+		if err != nil {
+			return nil, err
+		}
+		return map[string][]byte{"ppt/presentation.xml": presentationXML}, nil
+	})
+}
+
+// rewritePackage replaces or adds the parts edit returns, writing the package to a
+// temporary file next to it and renaming that over it
+func rewritePackage(presentationPath string, edit func(pkg *pptxPackage) (map[string][]byte, error)) error {
+	tempPath, err := writeEditedPackage(presentationPath, edit)
+	if err != nil {
+		return err
+	}
+	return replacePresentation(tempPath, presentationPath)
+}
+
+// writeEditedPackage writes the package with the parts edit returns to a temporary file
+// next to it and returns that file's path
+func writeEditedPackage(presentationPath string, edit func(pkg *pptxPackage) (map[string][]byte, error)) (string, error) {
+	pkg, err := openPPTX(presentationPath)
+	if err != nil {
+		return "", err
+	}
+	defer pkg.Close()
+	replaced, err := edit(pkg)
+	if err != nil {
+		return "", err
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(presentationPath), ".slidepilot-edit-*.pptx")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	tempPath := tempFile.Name()
+	err = writeMergedPackage(tempFile, pkg, nil, nil, nil, replaced, nil)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return "", fmt.Errorf("failed to write presentation: %v", err)
+	}
+	return tempPath, nil
+}
+
+// replacePresentation renames a rewritten package over the presentation
+func replacePresentation(tempPath, presentationPath string) error {
+	if err := os.Rename(tempPath, presentationPath); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to replace presentation: %v", err)
+	}
+	return nil
+}
+
+// slideFingerprints returns a string per slide built from its shapes' text, used to find
+// slides again after LibreOffice renumbered them
+func slideFingerprints(pkg *pptxPackage) ([]string, error) {
+	fingerprints := make([]string, pkg.SlideCount())
+	for i := range fingerprints {
+		shapes, err := pkg.SlideShapes(i + 1)
+		if err != nil {
+			return nil, err
+		}
+		var text strings.Builder
+		for _, shape := range shapes {
+			text.WriteString(shape.Kind)
+			text.WriteString("\x00")
+			text.WriteString(shape.Text)
+			text.WriteString("\x00")
+		}
+		fingerprints[i] = text.String()
+	}
+	return fingerprints, nil
+}
+
+// restoreSections puts the sections of the presentation as it was before an edit (saved at
+// beforePath) back into presentationPath when the edit dropped them, as LibreOffice does.
+// With the slide count unchanged slides keep their sections by position; otherwise slides
+// are matched by their text, and slides that match none join the section of the slide
+// before them. It reports whether sections were restored.
+func restoreSections(beforePath, presentationPath string) (bool, error) {
+	if !isOOXMLPackage(beforePath) || !isOOXMLPackage(presentationPath) {
+		return false, nil
+	}
+	before, err := openPPTX(beforePath)
+	if err != nil {
+		return false, err
+	}
+	defer before.Close()
+	beforeXML, err := before.readPart("ppt/presentation.xml")
+	if err != nil {
+		return false, err
+	}
+	sections, ok := parseSections(beforeXML)
+	if !ok || len(sections) == 0 {
+		return false, nil
+	}
+
+	after, err := openPPTX(presentationPath)
+	if err != nil {
+		return false, err
+	}
+	afterXML, err := after.readPart("ppt/presentation.xml")
+	if err != nil {
+		after.Close()
+		return false, err
+	}
+	if _, ok := parseSections(afterXML); ok {
+		// The edit kept or changed the sections itself
+		after.Close()
+		return false, nil
+	}
+
+	// Section of each slide before the edit, by position
+	beforeIDs := slideIDsOf(beforeXML)
+	sectionOf := map[string]int{}
+	for i, section := range sections {
+		for _, id := range section.SlideIDs {
+			sectionOf[id] = i
+		}
+	}
+	beforeSections := make([]int, len(beforeIDs))
+	for i, id := range beforeIDs {
+		beforeSections[i] = sectionOf[id]
+	}
+
+	afterIDs := slideIDsOf(afterXML)
+	afterSections := make([]int, len(afterIDs))
+	if len(afterIDs) == len(beforeIDs) {
+		copy(afterSections, beforeSections)
+	} else {
+		beforePrints, err := slideFingerprints(before)
+		if err != nil {
+			after.Close()
+			return false, err
+		}
+		afterPrints, err := slideFingerprints(after)
+		if err != nil {
+			after.Close()
+			return false, err
+		}
+		unused := map[string][]int{}
+		for i, print := range beforePrints {
+			unused[print] = append(unused[print], i)
+		}
+		for i, print := range afterPrints {
+			switch {
+			case len(unused[print]) > 0:
+				afterSections[i] = beforeSections[unused[print][0]]
+				unused[print] = unused[print][1:]
+			case i > 0:
+				afterSections[i] = afterSections[i-1]
+			}
+		}
+	}
+	after.Close()
+
+	// Sections stay in their order; a section whose slides moved out of order takes the
+	// slides up to the next one's first slide
+	restored := make([]pptxSection, len(sections))
+	for i, section := range sections {
+		restored[i] = pptxSection{Name: section.Name, ID: section.ID}
+	}
+	current := 0
+	for i, id := range afterIDs {
+		if afterSections[i] > current {
+			current = afterSections[i]
+		}
+		restored[current].SlideIDs = append(restored[current].SlideIDs, id)
+	}
+
+	err = rewritePresentationXML(presentationPath, func(_ *pptxPackage, presentationXML []byte) ([]byte, error) {
+		return withSections(presentationXML, restored), nil
+	})
+	return err == nil, err
+}
@@ -0,0 +1,621 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// apply_template works on the package directly, like the native readers: LibreOffice can
+// only bring master pages over from another document through its interactive dialogs.
+// The template's slide masters, with their layouts, themes and media, are copied into the
+// deck under fresh part names, the deck's own masters are dropped, and every slide is
+// pointed at the template layout matching its old one.
+
+const (
+	relTypeSlideMaster = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/slideMaster"
+	relTypeSlideLayout = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/slideLayout"
+	relTypeTheme       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/theme"
+	relNamespace       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+)
+
+// pptxRelationship is one entry of a part's .rels file
+type pptxRelationship struct {
+	ID         string `xml:"Id,attr"`
+	Type       string `xml:"Type,attr"`
+	Target     string `xml:"Target,attr"`
+	TargetMode string `xml:"TargetMode,attr"`
+}
+
+// pptxMasterRef is a slide master listed in presentation.xml
+type pptxMasterRef struct {
+	ID    string
+	RelID string
+	Part  string
+}
+
+// templateLayoutChange records the layout a slide was moved to
+type templateLayoutChange struct {
+	Slide int    `json:"slide"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// templateResult summarizes an applied template
+type templateResult struct {
+	Masters int                    `json:"masters"`
+	Layouts []string               `json:"layouts"`
+	Slides  []templateLayoutChange `json:"slides"`
+}
+
+// relsPartName returns the name of the .rels part holding a part's relationships
+func relsPartName(partName string) string {
+	dir, base := path.Split(partName)
+	return dir + "_rels/" + base + ".rels"
+}
+
+// resolvePartTarget resolves a relationship target against the part it belongs to
+func resolvePartTarget(partName, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(path.Dir(partName), target)
+}
+
+// relativePartTarget returns the relationship target pointing from one part to another
+func relativePartTarget(fromPart, toPart string) string {
+	var from []string
+	if dir := path.Dir(fromPart); dir != "." {
+		from = strings.Split(dir, "/")
+	}
+	to := strings.Split(toPart, "/")
+
+	common := 0
+	for common < len(from) && common < len(to)-1 && from[common] == to[common] {
+		common++
+	}
+	return strings.Repeat("../", len(from)-common) + strings.Join(to[common:], "/")
+}
+
+// readPart returns the raw bytes of a part
+func (p *pptxPackage) readPart(partName string) ([]byte, error) {
+	file, ok := p.parts[partName]
+	if !ok {
+		return nil, fmt.Errorf("presentation package is missing %s", partName)
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", partName, err)
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// rawRelationships returns a part's relationships as written, nil when it has none
+func (p *pptxPackage) rawRelationships(partName string) ([]pptxRelationship, error) {
+	relsName := relsPartName(partName)
+	if _, ok := p.parts[relsName]; !ok {
+		return nil, nil
+	}
+	var rels struct {
+		Relationships []pptxRelationship `xml:"Relationship"`
+	}
+	if err := p.decode(relsName, &rels); err != nil {
+		return nil, err
+	}
+	return rels.Relationships, nil
+}
+
+// masterRefs returns the slide masters of the presentation in order
+func (p *pptxPackage) masterRefs() ([]pptxMasterRef, error) {
+	var presentation struct {
+		MasterIDs []struct {
+			RelID string     `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+			Attrs []xml.Attr `xml:",any,attr"` // An unqualified "id" tag would match r:id too
+		} `xml:"sldMasterIdLst>sldMasterId"`
+	}
+	if err := p.decode("ppt/presentation.xml", &presentation); err != nil {
+		return nil, err
+	}
+	rels, err := p.relationships("ppt/presentation.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]pptxMasterRef, 0, len(presentation.MasterIDs))
+	for _, masterID := range presentation.MasterIDs {
+		part, ok := rels[masterID.RelID]
+		if !ok {
+			return nil, fmt.Errorf("presentation.xml references missing relationship %s", masterID.RelID)
+		}
+		ref := pptxMasterRef{RelID: masterID.RelID, Part: part}
+		for _, attr := range masterID.Attrs {
+			if attr.Name.Space == "" && attr.Name.Local == "id" {
+				ref.ID = attr.Value
+			}
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// closure returns the parts reachable from roots through internal relationships, roots first
+func (p *pptxPackage) closure(roots []string) ([]string, error) {
+	var order []string
+	seen := map[string]bool{}
+	queue := append([]string(nil), roots...)
+	for len(queue) > 0 {
+		part := queue[0]
+		queue = queue[1:]
+		if seen[part] {
+			continue
+		}
+		seen[part] = true
+		order = append(order, part)
+
+		rels, err := p.rawRelationships(part)
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range rels {
+			target := resolvePartTarget(part, rel.Target)
+			if _, ok := p.parts[target]; ok && rel.TargetMode != "External" {
+				queue = append(queue, target)
+			}
+		}
+	}
+	return order, nil
+}
+
+// partNumber splits a part name into its stem, number and extension, e.g. image12.png
+var partNumber = regexp.MustCompile(`^(.*?)(\d*)(\.[^./]*)?$`)
+
+// freePartName returns name, renumbered if it is already taken
+func freePartName(name string, taken func(string) bool) string {
+	if !taken(name) {
+		return name
+	}
+	match := partNumber.FindStringSubmatch(name)
+	for n := 1; ; n++ {
+		candidate := fmt.Sprintf("%s%d%s", match[1], n, match[3])
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+}
+
+// matchTemplateLayout picks the template layout for a slide that used old: the layout
+// with the same name, else one of the same type, else the first content layout
+func matchTemplateLayout(layouts []pptxLayout, old pptxLayout) pptxLayout {
+	if layout, ok := findLayout(layouts, old.Name); ok && old.Name != "" {
+		return layout
+	}
+	if old.Type != "cust" {
+		for _, layout := range layouts {
+			if layout.Type == old.Type {
+				return layout
+			}
+		}
+	}
+	for _, layout := range layouts {
+		if layout.Type == "obj" {
+			return layout
+		}
+	}
+	return layouts[0]
+}
+
+// marshalRelationships writes a .rels part
+func marshalRelationships(rels []pptxRelationship) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	buf.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for _, rel := range rels {
+		fmt.Fprintf(&buf, `<Relationship Id="%s" Type="%s" Target="%s"`, xmlAttr(rel.ID), xmlAttr(rel.Type), xmlAttr(rel.Target))
+		if rel.TargetMode != "" {
+			fmt.Fprintf(&buf, ` TargetMode="%s"`, xmlAttr(rel.TargetMode))
+		}
+		buf.WriteString(`/>`)
+	}
+	buf.WriteString(`</Relationships>`)
+	return buf.Bytes()
+}
+
+// xmlAttr escapes a string for use in an XML attribute
+func xmlAttr(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// contentTypes is the package's [Content_Types].xml
+type contentTypes struct {
+	Defaults  []contentTypeDefault  `xml:"Default"`
+	Overrides []contentTypeOverride `xml:"Override"`
+}
+
+// contentTypeDefault maps a file extension to a content type
+type contentTypeDefault struct {
+	Extension   string `xml:"Extension,attr"`
+	ContentType string `xml:"ContentType,attr"`
+}
+
+// contentTypeOverride sets the content type of a single part
+type contentTypeOverride struct {
+	PartName    string `xml:"PartName,attr"`
+	ContentType string `xml:"ContentType,attr"`
+}
+
+// lookup returns the content type of a part and whether it comes from an override
+func (c *contentTypes) lookup(partName string) (string, bool) {
+	for _, override := range c.Overrides {
+		if strings.TrimPrefix(override.PartName, "/") == partName {
+			return override.ContentType, true
+		}
+	}
+	ext := strings.TrimPrefix(path.Ext(partName), ".")
+	for _, def := range c.Defaults {
+		if strings.EqualFold(def.Extension, ext) {
+			return def.ContentType, false
+		}
+	}
+	return "", false
+}
+
+// marshal writes the [Content_Types].xml part
+func (c *contentTypes) marshal() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	buf.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	for _, def := range c.Defaults {
+		fmt.Fprintf(&buf, `<Default Extension="%s" ContentType="%s"/>`, xmlAttr(def.Extension), xmlAttr(def.ContentType))
+	}
+	for _, override := range c.Overrides {
+		fmt.Fprintf(&buf, `<Override PartName="%s" ContentType="%s"/>`, xmlAttr(override.PartName), xmlAttr(override.ContentType))
+	}
+	buf.WriteString(`</Types>`)
+	return buf.Bytes()
+}
+
+var (
+	masterIDListPattern = regexp.MustCompile(`(?s)<(\w+:)?sldMasterIdLst\b(?:/>|.*?</(?:\w+:)?sldMasterIdLst>)`)
+	relPrefixPattern    = regexp.MustCompile(`xmlns:(\w+)="` + regexp.QuoteMeta(relNamespace) + `"`)
+)
+
+// applyTemplate replaces the slide masters, layouts and themes of a .pptx with those of a
+// .pptx or .potx template and moves every slide onto the matching template layout. Slide
+// content, notes and the slide size are kept.
+func applyTemplate(presentationPath, templatePath string) (templateResult, error) {
+	tempPath, result, err := writeTemplatedCopy(presentationPath, templatePath)
+	if err != nil {
+		return templateResult{}, err
+	}
+	if err := os.Rename(tempPath, presentationPath); err != nil {
+		os.Remove(tempPath)
+		return templateResult{}, fmt.Errorf("failed to replace presentation: %v", err)
+	}
+	return result, nil
+}
+
+// writeTemplatedCopy writes the deck with the template applied to a temporary file next to
+// it and returns that file's path
+func writeTemplatedCopy(presentationPath, templatePath string) (string, templateResult, error) {
+	deck, err := openPPTX(presentationPath)
+	if err != nil {
+		return "", templateResult{}, err
+	}
+	defer deck.Close()
+	template, err := openPPTX(templatePath)
+	if err != nil {
+		return "", templateResult{}, fmt.Errorf("template: %w", err)
+	}
+	defer template.Close()
+
+	deckMasters, err := deck.masterRefs()
+	if err != nil {
+		return "", templateResult{}, err
+	}
+	deckLayouts, err := deck.Layouts()
+	if err != nil {
+		return "", templateResult{}, err
+	}
+	templateMasters, err := template.masterRefs()
+	if err != nil {
+		return "", templateResult{}, fmt.Errorf("template: %w", err)
+	}
+	templateLayouts, err := template.Layouts()
+	if err != nil {
+		return "", templateResult{}, fmt.Errorf("template: %w", err)
+	}
+	if len(templateLayouts) == 0 {
+		return "", templateResult{}, fmt.Errorf("template has no slide layouts")
+	}
+
+	// Give every part the template's masters need a name that is free in the deck
+	var masterParts []string
+	for _, master := range templateMasters {
+		masterParts = append(masterParts, master.Part)
+	}
+	importedParts, err := template.closure(masterParts)
+	if err != nil {
+		return "", templateResult{}, fmt.Errorf("template: %w", err)
+	}
+	imported := make(map[string]string, len(importedParts))
+	assigned := map[string]bool{}
+	taken := func(name string) bool {
+		_, inDeck := deck.parts[name]
+		return inDeck || assigned[name]
+	}
+	for _, part := range importedParts {
+		name := freePartName(part, taken)
+		assigned[name] = true
+		imported[part] = name
+	}
+
+	replaced := map[string][]byte{}
+	relsOf := map[string][]pptxRelationship{}
+
+	for _, part := range importedParts {
+		rels, err := template.rawRelationships(part)
+		if err != nil {
+			return "", templateResult{}, fmt.Errorf("template: %w", err)
+		}
+		if rels == nil {
+			continue
+		}
+		for i, rel := range rels {
+			if newTarget, ok := imported[resolvePartTarget(part, rel.Target)]; ok && rel.TargetMode != "External" {
+				rels[i].Target = relativePartTarget(imported[part], newTarget)
+			}
+		}
+		replaced[relsPartName(imported[part])] = marshalRelationships(rels)
+	}
+
+	// Point each slide at its new layout
+	result := templateResult{Masters: len(templateMasters), Slides: []templateLayoutChange{}}
+	for _, layout := range templateLayouts {
+		result.Layouts = append(result.Layouts, layout.Name)
+	}
+	for _, oldLayout := range deckLayouts {
+		newLayout := matchTemplateLayout(templateLayouts, oldLayout)
+		for _, slideNumber := range oldLayout.Slides {
+			slidePart := deck.slides[slideNumber-1]
+			rels, err := deck.rawRelationships(slidePart)
+			if err != nil {
+				return "", templateResult{}, err
+			}
+			for i, rel := range rels {
+				if rel.Type == relTypeSlideLayout {
+					rels[i].Target = relativePartTarget(slidePart, imported[newLayout.part])
+				}
+			}
+			relsOf[slidePart] = rels
+			replaced[relsPartName(slidePart)] = marshalRelationships(rels)
+			result.Slides = append(result.Slides, templateLayoutChange{Slide: slideNumber, From: oldLayout.Name, To: newLayout.Name})
+		}
+	}
+
+	// Swap the masters in presentation.xml and its relationships
+	presentationRels, err := deck.rawRelationships("ppt/presentation.xml")
+	if err != nil {
+		return "", templateResult{}, err
+	}
+	templateTheme := ""
+	masterRels, err := template.rawRelationships(templateMasters[0].Part)
+	if err != nil {
+		return "", templateResult{}, fmt.Errorf("template: %w", err)
+	}
+	for _, rel := range masterRels {
+		if rel.Type == relTypeTheme {
+			templateTheme = imported[resolvePartTarget(templateMasters[0].Part, rel.Target)]
+		}
+	}
+
+	usedIDs := map[string]bool{}
+	var newPresentationRels []pptxRelationship
+	for _, rel := range presentationRels {
+		switch {
+		case rel.Type == relTypeSlideMaster:
+			continue
+		case rel.Type == relTypeTheme && templateTheme != "":
+			rel.Target = relativePartTarget("ppt/presentation.xml", templateTheme)
+		}
+		usedIDs[rel.ID] = true
+		newPresentationRels = append(newPresentationRels, rel)
+	}
+
+	presentationXML, err := deck.readPart("ppt/presentation.xml")
+	if err != nil {
+		return "", templateResult{}, err
+	}
+	relPrefix := relPrefixPattern.FindSubmatch(presentationXML)
+	listMatch := masterIDListPattern.FindSubmatchIndex(presentationXML)
+	if relPrefix == nil || listMatch == nil {
+		return "", templateResult{}, fmt.Errorf("presentation.xml has no slide master list")
+	}
+	prefix := ""
+	if listMatch[2] >= 0 {
+		prefix = string(presentationXML[listMatch[2]:listMatch[3]])
+	}
+
+	var masterList bytes.Buffer
+	fmt.Fprintf(&masterList, "<%ssldMasterIdLst>", prefix)
+	next := 1
+	for _, master := range templateMasters {
+		for usedIDs[fmt.Sprintf("rId%d", next)] {
+			next++
+		}
+		relID := fmt.Sprintf("rId%d", next)
+		usedIDs[relID] = true
+		newPresentationRels = append(newPresentationRels, pptxRelationship{
+			ID:     relID,
+			Type:   relTypeSlideMaster,
+			Target: relativePartTarget("ppt/presentation.xml", imported[master.Part]),
+		})
+		fmt.Fprintf(&masterList, `<%ssldMasterId id="%s" %s:id="%s"/>`, prefix, xmlAttr(master.ID), relPrefix[1], relID)
+	}
+	fmt.Fprintf(&masterList, "</%ssldMasterIdLst>", prefix)
+
+	var newPresentation []byte
+	newPresentation = append(newPresentation, presentationXML[:listMatch[0]]...)
+	newPresentation = append(newPresentation, masterList.Bytes()...)
+	newPresentation = append(newPresentation, presentationXML[listMatch[1]:]...)
+	replaced["ppt/presentation.xml"] = newPresentation
+	replaced[relsPartName("ppt/presentation.xml")] = marshalRelationships(newPresentationRels)
+	relsOf["ppt/presentation.xml"] = newPresentationRels
+
+	// Drop whatever of the old masters nothing refers to any more
+	var oldMasterParts []string
+	for _, master := range deckMasters {
+		oldMasterParts = append(oldMasterParts, master.Part)
+	}
+	candidates, err := deck.closure(oldMasterParts)
+	if err != nil {
+		return "", templateResult{}, err
+	}
+	reachable, err := reachableParts(deck, relsOf)
+	if err != nil {
+		return "", templateResult{}, err
+	}
+	dropped := map[string]bool{}
+	for _, part := range candidates {
+		if !reachable[part] {
+			dropped[part] = true
+			dropped[relsPartName(part)] = true
+		}
+	}
+
+	// Register the new parts' content types
+	var deckTypes, templateTypes contentTypes
+	if err := deck.decode("[Content_Types].xml", &deckTypes); err != nil {
+		return "", templateResult{}, err
+	}
+	if err := template.decode("[Content_Types].xml", &templateTypes); err != nil {
+		return "", templateResult{}, fmt.Errorf("template: %w", err)
+	}
+	overrides := deckTypes.Overrides[:0]
+	for _, override := range deckTypes.Overrides {
+		if !dropped[strings.TrimPrefix(override.PartName, "/")] {
+			overrides = append(overrides, override)
+		}
+	}
+	deckTypes.Overrides = overrides
+	for _, part := range importedParts {
+		contentType, isOverride := templateTypes.lookup(part)
+		if contentType == "" {
+			continue
+		}
+		if existing, _ := deckTypes.lookup(imported[part]); !isOverride && existing == contentType {
+			continue
+		}
+		if isOverride {
+			deckTypes.Overrides = append(deckTypes.Overrides, contentTypeOverride{"/" + imported[part], contentType})
+		} else {
+			deckTypes.Defaults = append(deckTypes.Defaults, contentTypeDefault{strings.TrimPrefix(path.Ext(part), "."), contentType})
+		}
+	}
+	replaced["[Content_Types].xml"] = deckTypes.marshal()
+
+	// Write the new package next to the deck so it can be renamed over it
+	tempFile, err := os.CreateTemp(filepath.Dir(presentationPath), ".slidepilot-template-*.pptx")
+	if err != nil {
+		return "", templateResult{}, fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	tempPath := tempFile.Name()
+	err = writeMergedPackage(tempFile, deck, template, importedParts, imported, replaced, dropped)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return "", templateResult{}, fmt.Errorf("failed to write presentation: %v", err)
+	}
+	return tempPath, result, nil
+}
+
+// writeMergedPackage writes the deck's parts, minus dropped ones and with replaced ones
+// rewritten, followed by the parts imported from the other package under their new names.
+// Unchanged parts are copied without recompressing them.
+func writeMergedPackage(out io.Writer, deck, other *pptxPackage, importedParts []string, imported map[string]string, replaced map[string][]byte, dropped map[string]bool) error {
+	writer := zip.NewWriter(out)
+	written := map[string]bool{}
+
+	writeReplaced := func(name string) error {
+		part, err := writer.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+		if err != nil {
+			return err
+		}
+		written[name] = true
+		_, err = part.Write(replaced[name])
+		return err
+	}
+
+	for _, file := range deck.reader.File {
+		switch {
+		case dropped[file.Name]:
+			continue
+		case replaced[file.Name] != nil:
+			if err := writeReplaced(file.Name); err != nil {
+				return err
+			}
+		default:
+			if err := writer.Copy(file); err != nil {
+				return err
+			}
+		}
+	}
+
+	writeImported := func(part string) error {
+		if replaced[imported[part]] != nil {
+			return writeReplaced(imported[part])
+		}
+		file := other.parts[part]
+		header := file.FileHeader
+		header.Name = imported[part]
+		raw, err := file.OpenRaw()
+		if err != nil {
+			return err
+		}
+		dst, err := writer.CreateRaw(&header)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(dst, raw)
+		return err
+	}
+
+	for _, part := range importedParts {
+		if err := writeImported(part); err != nil {
+			return err
+		}
+		if relsName := relsPartName(imported[part]); replaced[relsName] != nil && !written[relsName] {
+			if err := writeReplaced(relsName); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Replaced parts the deck doesn't have yet are new ones
+	var added []string
+	for name := range replaced {
+		if !written[name] && deck.parts[name] == nil {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(added)
+	for _, name := range added {
+		if err := writeReplaced(name); err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyTemplateReplacesMastersAndRemapsSlides(t *testing.T) {
+	dir := t.TempDir()
+	deckPath := filepath.Join(dir, "deck.pptx")
+	if err := copyFile(filepath.Join("testdata", "two_slides.pptx"), deckPath); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := applyTemplate(deckPath, filepath.Join("testdata", "template.potx"))
+	if err != nil {
+		t.Fatalf("applyTemplate failed: %v", err)
+	}
+	if result.Masters != 1 || fmt.Sprint(result.Layouts) != "[Title Slide Title and Content]" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	for _, change := range result.Slides {
+		if change.From != "Title and Content" || change.To != "Title and Content" {
+			t.Errorf("slide %d should keep its layout by name, got %+v", change.Slide, change)
+		}
+	}
+
+	pkg, err := openPPTX(deckPath)
+	if err != nil {
+		t.Fatalf("templated deck does not open: %v", err)
+	}
+	defer pkg.Close()
+
+	layouts, err := pkg.Layouts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layouts) != 2 || layouts[1].Name != "Title and Content" || fmt.Sprint(layouts[1].Slides) != "[1 2]" {
+		t.Fatalf("expected the template's layouts with both slides on Title and Content, got %+v", layouts)
+	}
+	if shapes, err := pkg.SlideShapes(1); err != nil || slideTitle(shapes) != "Quarterly Review" {
+		t.Errorf("slide content should be kept, got %v (%v)", slideTitle(shapes), err)
+	}
+
+	// The old master, layout and theme are gone; the template's parts got free names
+	for _, part := range []string{"ppt/slideMasters/slideMaster1.xml", "ppt/slideLayouts/slideLayout1.xml", "ppt/theme/theme1.xml"} {
+		if _, ok := pkg.parts[part]; ok {
+			t.Errorf("expected %s to be dropped", part)
+		}
+	}
+	for _, part := range []string{"ppt/slideMasters/slideMaster2.xml", "ppt/slideLayouts/slideLayout3.xml", "ppt/theme/theme2.xml", "ppt/media/image1.png"} {
+		if _, ok := pkg.parts[part]; !ok {
+			t.Errorf("expected %s to be imported", part)
+		}
+	}
+
+	masters, err := pkg.masterRefs()
+	if err != nil || len(masters) != 1 || masters[0].ID != "2147483660" || masters[0].Part != "ppt/slideMasters/slideMaster2.xml" {
+		t.Errorf("expected the template master with its id, got %+v (%v)", masters, err)
+	}
+	presentationRels, err := pkg.relationships("ppt/presentation.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if presentationRels["rIdTheme"] != "ppt/theme/theme2.xml" {
+		t.Errorf("presentation theme should point at the template theme, got %q", presentationRels["rIdTheme"])
+	}
+
+	var types contentTypes
+	if err := pkg.decode("[Content_Types].xml", &types); err != nil {
+		t.Fatal(err)
+	}
+	if contentType, _ := types.lookup("ppt/media/image1.png"); contentType != "image/png" {
+		t.Errorf("expected a png content type, got %q", contentType)
+	}
+	if contentType, override := types.lookup("ppt/slideMasters/slideMaster2.xml"); !override || !strings.HasSuffix(contentType, "slideMaster+xml") {
+		t.Errorf("expected a slide master override, got %q", contentType)
+	}
+	if _, override := types.lookup("ppt/slideMasters/slideMaster1.xml"); override {
+		t.Error("the dropped master should lose its override")
+	}
+
+	// No leftovers from writing the package
+	if matches, _ := filepath.Glob(filepath.Join(dir, ".slidepilot-template-*")); len(matches) != 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
+
+func TestApplyTemplateLeavesDeckUnchangedOnError(t *testing.T) {
+	dir := t.TempDir()
+	deckPath := filepath.Join(dir, "deck.pptx")
+	if err := copyFile(filepath.Join("testdata", "two_slides.pptx"), deckPath); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(deckPath)
+
+	if _, err := applyTemplate(deckPath, filepath.Join("testdata", "missing.potx")); err == nil {
+		t.Error("expected an error for a missing template")
+	}
+	if after, _ := os.ReadFile(deckPath); !bytes.Equal(before, after) {
+		t.Error("deck should be unchanged")
+	}
+}
+
+func TestMatchTemplateLayout(t *testing.T) {
+	layouts := []pptxLayout{
+		{Name: "Cover", Type: "title"},
+		{Name: "Body", Type: "obj"},
+		{Name: "Section", Type: "cust"},
+	}
+	for _, tc := range []struct {
+		old  pptxLayout
+		want string
+	}{
+		{pptxLayout{Name: "section", Type: "secHead"}, "Section"},
+		{pptxLayout{Name: "Title Slide", Type: "title"}, "Cover"},
+		{pptxLayout{Name: "Two Content", Type: "twoObj"}, "Body"},
+		{pptxLayout{Name: "Custom", Type: "cust"}, "Body"},
+	} {
+		if got := matchTemplateLayout(layouts, tc.old); got.Name != tc.want {
+			t.Errorf("matchTemplateLayout(%+v) = %q, want %q", tc.old, got.Name, tc.want)
+		}
+	}
+}
+
+func TestRelativePartTarget(t *testing.T) {
+	for _, tc := range []struct{ from, to, want string }{
+		{"ppt/slides/slide1.xml", "ppt/slideLayouts/slideLayout3.xml", "../slideLayouts/slideLayout3.xml"},
+		{"ppt/presentation.xml", "ppt/theme/theme2.xml", "theme/theme2.xml"},
+		{"ppt/slideMasters/slideMaster2.xml", "ppt/slideMasters/slideMaster3.xml", "slideMaster3.xml"},
+		{"", "ppt/presentation.xml", "ppt/presentation.xml"},
+	} {
+		if got := relativePartTarget(tc.from, tc.to); got != tc.want {
+			t.Errorf("relativePartTarget(%q, %q) = %q, want %q", tc.from, tc.to, got, tc.want)
+		}
+		if got := resolvePartTarget(tc.from, tc.want); got != tc.to {
+			t.Errorf("resolvePartTarget(%q, %q) = %q, want %q", tc.from, tc.want, got, tc.to)
+		}
+	}
+}
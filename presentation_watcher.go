@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// presentationPollInterval is how often the loaded presentation is checked for changes
+// made by other programs
+const presentationPollInterval = 2 * time.Second
+
+// fileStamp identifies a version of a file by its modification time and size
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func statStamp(path string) (fileStamp, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, false
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}, true
+}
+
+// PresentationWatcher notices when the loaded presentation is changed by another program,
+// e.g. saved from PowerPoint while SlidePilot has it open. It remembers the version the
+// app last loaded or wrote; anything else on disk is an external change. The file is
+// polled by stat, which works the same on every platform and network drive.
+type PresentationWatcher struct {
+	mu       sync.Mutex
+	path     string
+	known    fileStamp // Version the app last loaded or wrote
+	reported fileStamp // Latest external version already reported, to report each change once
+}
+
+// NewPresentationWatcher creates a watcher with nothing to watch
+func NewPresentationWatcher() *PresentationWatcher {
+	return &PresentationWatcher{}
+}
+
+// Watch starts tracking path at its current version; "" stops watching
+func (w *PresentationWatcher) Watch(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.path = path
+	w.known, _ = statStamp(path)
+	w.reported = w.known
+}
+
+// Acknowledge accepts the current version of path as the app's own, after the app wrote it
+func (w *PresentationWatcher) Acknowledge(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if path != w.path {
+		return
+	}
+	w.known, _ = statStamp(path)
+	w.reported = w.known
+}
+
+// Changed reports whether path is the watched presentation and differs from the version
+// the app knows about
+func (w *PresentationWatcher) Changed(path string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if path == "" || path != w.path {
+		return false
+	}
+	current, ok := statStamp(path)
+	return ok && current != w.known
+}
+
+// Poll returns the watched presentation when it has changed externally since the last
+// report, so each change is reported once
+func (w *PresentationWatcher) Poll() (string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.path == "" {
+		return "", false
+	}
+	current, ok := statStamp(w.path)
+	if !ok || current == w.known || current == w.reported {
+		return "", false
+	}
+	w.reported = current
+	return w.path, true
+}
+
+// watchPresentation polls the loaded presentation until ctx is done and emits
+// "presentation-changed-externally" with its path when another program changes it
+func (a *App) watchPresentation(ctx context.Context) {
+	ticker := time.NewTicker(presentationPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// A running request writes the file itself; its tools check for changes before editing
+			if !a.aiAgent.mu.TryLock() {
+				continue
+			}
+			path, changed := a.watcher.Poll()
+			a.aiAgent.mu.Unlock()
+			if changed {
+				fmt.Printf("Presentation changed outside SlidePilot: %s\n", path)
+				a.events.Emit(a.ctx, "presentation-changed-externally", path)
+			}
+		}
+	}
+}
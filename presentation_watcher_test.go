@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// touchExternally changes a file the way another program saving it would
+func touchExternally(t *testing.T, path string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{0})
+	f.Close()
+	later := time.Now().Add(time.Second)
+	os.Chtimes(path, later, later)
+}
+
+func TestPresentationWatcherReportsExternalChangesOnce(t *testing.T) {
+	t.Setenv(visionFeedbackEnv, "0")
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	appendingEdit(env)
+
+	// The app's own edits are not external changes
+	env.app.aiAgent.executeTool(context.Background(), "toolu_1", "edit_slide_text", editSlideInput("one"))
+	if _, changed := env.app.watcher.Poll(); changed {
+		t.Fatal("the tool's own write was reported as external")
+	}
+
+	touchExternally(t, path)
+	if changed, ok := env.app.watcher.Poll(); !ok || changed != path {
+		t.Fatalf("expected the external change to be reported, got %q, %v", changed, ok)
+	}
+	if _, changed := env.app.watcher.Poll(); changed {
+		t.Error("the same change was reported twice")
+	}
+
+	// The next edit is refused once so the agent re-reads the slides, then goes through
+	result := env.app.aiAgent.executeTool(context.Background(), "toolu_2", "edit_slide_text", editSlideInput("two"))
+	if !result.OfToolResult.IsError.Value || !strings.Contains(result.OfToolResult.Content[0].OfText.Text, string(ErrCodeFileChanged)) {
+		t.Fatalf("expected %s, got %+v", ErrCodeFileChanged, result.OfToolResult.Content)
+	}
+	if len(env.events.Messages("presentation-changed-externally")) != 1 {
+		t.Error("expected the frontend to be told about the change")
+	}
+	result = env.app.aiAgent.executeTool(context.Background(), "toolu_3", "edit_slide_text", editSlideInput("two"))
+	if result.OfToolResult.IsError.Value {
+		t.Errorf("expected the retry to succeed, got %+v", result.OfToolResult.Content)
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// profilesDir is where --profile traces are written
+const profilesDir = "profiles"
+
+// profiler records timing spans when profiling is enabled; nil means profiling is off
+var profiler *Profiler
+
+// Profiler collects timing spans for tool calls and the subprocess stages inside them.
+// Traces use the Chrome trace event format, so they open in chrome://tracing or Perfetto.
+type Profiler struct {
+	mu        sync.Mutex
+	started   time.Time
+	tracePath string
+	events    []traceEvent
+}
+
+// ProfileSpan is one in-flight timing measurement
+type ProfileSpan struct {
+	profiler *Profiler
+	category string
+	name     string
+	start    time.Time
+}
+
+// traceEvent is a completed span in Chrome trace event format
+type traceEvent struct {
+	Name     string                 `json:"name"`
+	Category string                 `json:"cat"`
+	Phase    string                 `json:"ph"`
+	Start    int64                  `json:"ts"`
+	Duration int64                  `json:"dur"`
+	PID      int                    `json:"pid"`
+	TID      int                    `json:"tid"`
+	Args     map[string]interface{} `json:"args,omitempty"`
+}
+
+// stageSummary aggregates all spans with the same category and name
+type stageSummary struct {
+	Category string  `json:"category"`
+	Name     string  `json:"name"`
+	Count    int     `json:"count"`
+	TotalMS  float64 `json:"total_ms"`
+	MeanMS   float64 `json:"mean_ms"`
+	MaxMS    float64 `json:"max_ms"`
+}
+
+// profilingRequested reports whether --profile was passed or SLIDEPILOT_PROFILE is set
+func profilingRequested(args []string) bool {
+	for _, arg := range args {
+		if arg == "--profile" || arg == "-profile" {
+			return true
+		}
+	}
+	value := os.Getenv("SLIDEPILOT_PROFILE")
+	return value != "" && value != "0" && value != "false"
+}
+
+// NewProfiler creates a profiler that writes its trace into the profiles directory
+func NewProfiler() *Profiler {
+	now := time.Now()
+	return &Profiler{
+		started:   now,
+		tracePath: filepath.Join(profilesDir, fmt.Sprintf("trace-%s.json", now.Format("20060102-150405"))),
+	}
+}
+
+// Start begins a span; it is safe to call on a nil profiler
+func (p *Profiler) Start(category, name string) *ProfileSpan {
+	if p == nil {
+		return nil
+	}
+	return &ProfileSpan{profiler: p, category: category, name: name, start: time.Now()}
+}
+
+// End records the span; it is safe to call on a nil span
+func (s *ProfileSpan) End() {
+	s.EndWith(nil)
+}
+
+// EndWith records the span with extra arguments shown in the trace viewer
+func (s *ProfileSpan) EndWith(args map[string]interface{}) {
+	if s == nil {
+		return
+	}
+	p := s.profiler
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, traceEvent{
+		Name:     s.name,
+		Category: s.category,
+		Phase:    "X",
+		Start:    s.start.Sub(p.started).Microseconds(),
+		Duration: time.Since(s.start).Microseconds(),
+		PID:      1,
+		TID:      1,
+		Args:     args,
+	})
+}
+
+// Summary aggregates recorded spans, slowest total first
+func (p *Profiler) Summary() []stageSummary {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	byStage := make(map[string]*stageSummary)
+	for _, event := range p.events {
+		key := event.Category + "/" + event.Name
+		stage, ok := byStage[key]
+		if !ok {
+			stage = &stageSummary{Category: event.Category, Name: event.Name}
+			byStage[key] = stage
+		}
+		ms := float64(event.Duration) / 1000
+		stage.Count++
+		stage.TotalMS += ms
+		if ms > stage.MaxMS {
+			stage.MaxMS = ms
+		}
+	}
+
+	summary := make([]stageSummary, 0, len(byStage))
+	for _, stage := range byStage {
+		stage.MeanMS = stage.TotalMS / float64(stage.Count)
+		summary = append(summary, *stage)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		return summary[i].TotalMS > summary[j].TotalMS
+	})
+	return summary
+}
+
+// Flush rewrites the trace file with every span recorded so far. It is called after each
+// AI turn so a trace exists even if the app is killed.
+func (p *Profiler) Flush() error {
+	if p == nil {
+		return nil
+	}
+	summary := p.Summary()
+
+	p.mu.Lock()
+	trace := map[string]interface{}{
+		"traceEvents":     p.events,
+		"displayTimeUnit": "ms",
+		"otherData": map[string]interface{}{
+			"app":     "slidepilot",
+			"started": p.started.Format(time.RFC3339),
+			"summary": summary,
+		},
+	}
+	traceJSON, err := json.MarshalIndent(trace, "", "  ")
+	p.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode trace: %v", err)
+	}
+
+	if err := os.MkdirAll(profilesDir, 0755); err != nil {
+		return fmt.Errorf("failed to create profiles directory: %v", err)
+	}
+	if err := os.WriteFile(p.tracePath, traceJSON, 0644); err != nil {
+		return fmt.Errorf("failed to write trace: %v", err)
+	}
+	return nil
+}
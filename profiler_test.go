@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestProfilerRecordsToolStages(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_edit_slide.py", `{"success": true, "message": "Changed shape 0"}`)
+	profiler = NewProfiler()
+	defer func() { profiler = nil }()
+
+	env.app.aiAgent.executeTool(context.Background(), "toolu_1", "edit_slide_text", []byte(`{"slide_number": 1, "target_type": "shape_index", "target_value": "0", "new_text": "Hi"}`))
+	if err := profiler.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	data, err := os.ReadFile(profiler.tracePath)
+	if err != nil {
+		t.Fatalf("expected a trace file: %v", err)
+	}
+	var trace struct {
+		TraceEvents []traceEvent `json:"traceEvents"`
+		OtherData   struct {
+			Summary []stageSummary `json:"summary"`
+		} `json:"otherData"`
+	}
+	if err := json.Unmarshal(data, &trace); err != nil {
+		t.Fatalf("invalid trace: %v", err)
+	}
+	stages := map[string]bool{}
+	for _, event := range trace.TraceEvents {
+		if event.Phase != "X" {
+			t.Errorf("expected complete events, got phase %q", event.Phase)
+		}
+		stages[event.Category+"/"+event.Name] = true
+	}
+	for _, stage := range []string{"tool/edit_slide_text", "tool/backup", "tool/integrity_check"} {
+		if !stages[stage] {
+			t.Errorf("expected a %s span, got %v", stage, stages)
+		}
+	}
+	if len(trace.OtherData.Summary) != len(stages) {
+		t.Errorf("expected a summary entry per stage, got %+v", trace.OtherData.Summary)
+	}
+}
+
+func TestProfilingRequested(t *testing.T) {
+	t.Setenv("SLIDEPILOT_PROFILE", "")
+	if profilingRequested(nil) || !profilingRequested([]string{"--profile"}) {
+		t.Error("expected --profile to turn profiling on")
+	}
+	for value, want := range map[string]bool{"1": true, "0": false, "false": false} {
+		t.Setenv("SLIDEPILOT_PROFILE", value)
+		if got := profilingRequested(nil); got != want {
+			t.Errorf("SLIDEPILOT_PROFILE=%s: expected %v, got %v", value, want, got)
+		}
+	}
+}
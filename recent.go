@@ -0,0 +1,239 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// maxRecentPresentations is how many decks the recent list remembers
+	maxRecentPresentations = 10
+	// recentThumbnailWidth is the pixel width of the stored first-slide thumbnails
+	recentThumbnailWidth = 240
+)
+
+// RecentPresentation is one entry of the recent presentations list
+type RecentPresentation struct {
+	Path       string    `json:"path"`
+	Name       string    `json:"name"`
+	LastOpened time.Time `json:"last_opened"`
+	SlideCount int       `json:"slide_count"`
+	Thumbnail  string    `json:"thumbnail,omitempty"` // JPEG data URI of the first slide
+}
+
+// recentEntry is a RecentPresentation as it is saved; the thumbnail is a file of its own
+type recentEntry struct {
+	Path       string    `json:"path"`
+	LastOpened time.Time `json:"last_opened"`
+	SlideCount int       `json:"slide_count"`
+}
+
+// RecentStore remembers the presentations opened last, with a thumbnail of each
+type RecentStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewRecentStore creates a store keeping the list and thumbnails in dir
+func NewRecentStore(dir string) *RecentStore {
+	return &RecentStore{dir: dir}
+}
+
+// recentDir is where the recent list is kept: the user config directory, falling back to
+// the working directory
+func recentDir() string {
+	if configDir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(configDir, "slidepilot", "recent")
+	}
+	return "recent"
+}
+
+// thumbnailPath returns the thumbnail file of a presentation
+func (s *RecentStore) thumbnailPath(presentationPath string) string {
+	sum := sha256.Sum256([]byte(presentationPath))
+	return filepath.Join(s.dir, "thumbnails", hex.EncodeToString(sum[:])[:16]+".jpg")
+}
+
+// List returns the recent presentations, most recently opened first. Entries whose file
+// no longer exists are dropped from the list.
+func (s *RecentStore) List() ([]RecentPresentation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	kept := entries[:0]
+	for _, entry := range entries {
+		if _, err := os.Stat(entry.Path); err == nil {
+			kept = append(kept, entry)
+		} else {
+			os.Remove(s.thumbnailPath(entry.Path))
+		}
+	}
+	if len(kept) < len(entries) {
+		if err := s.save(kept); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}
+
+	presentations := make([]RecentPresentation, 0, len(kept))
+	for _, entry := range kept {
+		presentation := RecentPresentation{
+			Path:       entry.Path,
+			Name:       filepath.Base(entry.Path),
+			LastOpened: entry.LastOpened,
+			SlideCount: entry.SlideCount,
+		}
+		if thumbnail, err := encodeImageDataURI(s.thumbnailPath(entry.Path)); err == nil {
+			presentation.Thumbnail = thumbnail
+		}
+		presentations = append(presentations, presentation)
+	}
+	return presentations, nil
+}
+
+// Record moves a presentation to the top of the list, storing a thumbnail of its first
+// slide preview when one is given
+func (s *RecentStore) Record(presentationPath string, slideCount int, firstSlide string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	updated := []recentEntry{{Path: presentationPath, LastOpened: time.Now(), SlideCount: slideCount}}
+	for _, entry := range entries {
+		if entry.Path != presentationPath {
+			updated = append(updated, entry)
+		}
+	}
+	for _, dropped := range updated[min(len(updated), maxRecentPresentations):] {
+		os.Remove(s.thumbnailPath(dropped.Path))
+	}
+	updated = updated[:min(len(updated), maxRecentPresentations)]
+
+	if firstSlide != "" {
+		if err := writeThumbnail(firstSlide, s.thumbnailPath(presentationPath), recentThumbnailWidth); err != nil {
+			fmt.Printf("Warning: Failed to store thumbnail of %s: %v\n", filepath.Base(presentationPath), err)
+		}
+	}
+	return s.save(updated)
+}
+
+// Forget removes a presentation from the list
+func (s *RecentStore) Forget(presentationPath string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	kept := entries[:0]
+	for _, entry := range entries {
+		if entry.Path != presentationPath {
+			kept = append(kept, entry)
+		}
+	}
+	os.Remove(s.thumbnailPath(presentationPath))
+	return s.save(kept)
+}
+
+// load reads the list, newest first. A missing file is an empty list.
+func (s *RecentStore) load() ([]recentEntry, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, "recent.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recent presentations: %v", err)
+	}
+	var entries []recentEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse recent presentations: %v", err)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].LastOpened.After(entries[j].LastOpened) })
+	return entries, nil
+}
+
+// save writes the list, replacing the previous copy atomically
+func (s *RecentStore) save(entries []recentEntry) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create recent presentations directory: %v", err)
+	}
+	if entries == nil {
+		entries = []recentEntry{}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode recent presentations: %v", err)
+	}
+	tmp, err := os.CreateTemp(s.dir, ".recent-*")
+	if err != nil {
+		return fmt.Errorf("failed to save recent presentations: %v", err)
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save recent presentations: %v", errors.Join(writeErr, closeErr))
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, "recent.json")); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save recent presentations: %v", err)
+	}
+	return nil
+}
+
+// writeThumbnail scales a JPEG down to width pixels, keeping the aspect ratio, and
+// writes it to dst
+func writeThumbnail(src, dst string, width int) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	img, err := jpeg.Decode(in)
+	if err != nil {
+		return err
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() > width {
+		height := max(1, bounds.Dy()*width/bounds.Dx())
+		scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+		// Nearest neighbour is plenty for a list thumbnail
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				scaled.Set(x, y, img.At(bounds.Min.X+x*bounds.Dx()/width, bounds.Min.Y+y*bounds.Dy()/height))
+			}
+		}
+		img = scaled
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if err := jpeg.Encode(out, img, &jpeg.Options{Quality: 80}); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadPresentationRecordsRecentPresentations(t *testing.T) {
+	env := newTestEnv(t)
+	env.app.recent = NewRecentStore(filepath.Join(env.dir, "recent"))
+	first := env.loadFixture(t, "two_slides.pptx")
+	second := filepath.Join(env.dir, "second.pptx")
+	copyFile(first, second)
+
+	for _, path := range []string{first, second, first} {
+		if _, err := env.app.LoadPresentation(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recent, err := env.app.GetRecentPresentations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 2 || recent[0].Path != first || recent[1].Path != second {
+		t.Fatalf("expected the decks most recently opened first, got %+v", recent)
+	}
+	if recent[0].Name != "two_slides.pptx" || recent[0].SlideCount != 2 {
+		t.Errorf("unexpected entry %+v", recent[0])
+	}
+
+	// Deleted decks are pruned, and opening one reports it
+	os.Remove(second)
+	if recent, _ := env.app.GetRecentPresentations(); len(recent) != 1 {
+		t.Errorf("expected the deleted deck to be dropped, got %+v", recent)
+	}
+	if _, err := env.app.OpenRecent(second); err == nil || !strings.Contains(err.Error(), "no longer exists") {
+		t.Errorf("expected an error for a deleted deck, got %v", err)
+	}
+	if slides, err := env.app.OpenRecent(first); err != nil || len(slides) != 2 {
+		t.Errorf("expected the deck to open, got %v, %v", slides, err)
+	}
+}
+
+func TestRecentPresentationsKeepThumbnailsAndAreBounded(t *testing.T) {
+	dir := t.TempDir()
+	store := NewRecentStore(filepath.Join(dir, "recent"))
+
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1200, 675)), nil)
+	slide := filepath.Join(dir, "slide-000.jpg")
+	os.WriteFile(slide, buf.Bytes(), 0644)
+
+	var paths []string
+	for i := 0; i < maxRecentPresentations+2; i++ {
+		path := filepath.Join(dir, string(rune('a'+i))+".pptx")
+		os.WriteFile(path, []byte("deck"), 0644)
+		paths = append(paths, path)
+		if err := store.Record(path, 3, slide); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recent, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != maxRecentPresentations || recent[0].Path != paths[len(paths)-1] {
+		t.Fatalf("expected the %d newest decks, got %d starting with %s", maxRecentPresentations, len(recent), recent[0].Path)
+	}
+	if !strings.HasPrefix(recent[0].Thumbnail, "data:image/jpeg;base64,") {
+		t.Fatalf("expected a thumbnail, got %.40q", recent[0].Thumbnail)
+	}
+	file, _ := os.Open(store.thumbnailPath(paths[len(paths)-1]))
+	defer file.Close()
+	if config, err := jpeg.DecodeConfig(file); err != nil || config.Width != recentThumbnailWidth || config.Height != 135 {
+		t.Errorf("expected a %dx135 thumbnail, got %+v, %v", recentThumbnailWidth, config, err)
+	}
+	if _, err := os.Stat(store.thumbnailPath(paths[0])); !os.IsNotExist(err) {
+		t.Errorf("expected the thumbnail of a dropped deck to be removed")
+	}
+}
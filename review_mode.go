@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// reviewModePrompt is added to the system prompt while review mode is on
+const reviewModePrompt = `Review mode is on: the user wants suggestions, not edits. Inspect the presentation with the read-only tools and leave each suggestion as a review comment with add_comment on the slide it concerns, pinned to the shape with shape_id where it is about one shape. Make each comment specific and actionable, e.g. the shorter title you propose. Use list_comments to see what reviewers already wrote and resolve_comment only for comments the user asks you to resolve. You can't change slide content in review mode.`
+
+// reviewModeTools returns the tools offered in review mode: the tools that change nothing
+// and the comment tools
+func (a *AIAgent) reviewModeTools() []ToolDefinition {
+	var tools []ToolDefinition
+	for _, tool := range a.tools.List() {
+		if tool.changesNothing() || tool.Annotates {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// reviewModeToolUse runs a tool call of a turn in review mode, refusing edits other than
+// comments and tools that write files or switch the open presentation
+func (a *AIAgent) reviewModeToolUse(ctx context.Context, id, name string, input json.RawMessage) anthropic.ContentBlockParamUnion {
+	if tool, found := a.tools.Lookup(name); found && !tool.changesNothing() && !tool.Annotates {
+		return anthropic.NewToolResultBlock(id, toolErrorEnvelope(NewToolError(ErrCodeInvalidInput,
+			"review mode is on: %s changes the presentation or writes files; leave the suggestion with add_comment", name)), true)
+	}
+	return a.executeTool(ctx, id, name, input)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReviewModeLeavesCommentsInsteadOfEdits(t *testing.T) {
+	t.Setenv(visionFeedbackEnv, "0")
+	env := newTestEnv(t,
+		toolUseResponse("toolu_1", "edit_slide_text", string(editSlideInput("Direct"))),
+		toolUseResponse("toolu_2", "add_comment", `{"slide_number": 1, "text": "Try a shorter title"}`),
+		textResponse("I left a comment on slide 1."),
+	)
+	path := env.loadFixture(t, "two_slides.pptx")
+	appendingEdit(env)
+	settings := env.app.GetSettings()
+	settings.ReviewMode = true
+	settings.PlanMode = true
+	env.app.aiAgent.SetSettings(settings)
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Review slide 1"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	// Review mode offers the read-only tools and the comment tools, and takes over from plan mode
+	var offered []string
+	for _, tool := range env.llm.Requests[0].Tools {
+		offered = append(offered, tool.OfTool.Name)
+	}
+	joined := strings.Join(offered, " ")
+	if !strings.Contains(joined, "read_slide") || !strings.Contains(joined, "add_comment") ||
+		strings.Contains(joined, "edit_slide_text") || strings.Contains(joined, submitEditPlanName) {
+		t.Errorf("unexpected tools in review mode: %v", offered)
+	}
+	if system := env.llm.Requests[0].System[0].Text; !strings.Contains(system, reviewModePrompt) || strings.Contains(system, planModePrompt) {
+		t.Error("expected only the review mode instructions in the system prompt")
+	}
+
+	if calls := len(env.uno.Calls("uno_edit_slide.py")); calls != 0 {
+		t.Errorf("expected the direct edit to be refused, got %d edits", calls)
+	}
+	pkg, err := openPPTX(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pkg.Close()
+	if comments, err := pkg.Comments(); err != nil || len(comments) != 1 || comments[0].Text != "Try a shorter title" {
+		t.Errorf("expected the suggestion as a comment, got %+v (%v)", comments, err)
+	}
+}
+
+func TestReviewModeRefusesToolsThatWriteFiles(t *testing.T) {
+	t.Setenv(visionFeedbackEnv, "0")
+	env := newTestEnv(t,
+		toolUseResponse("toolu_1", "save_presentation_as", `{"output_path": "two_slides.pptx", "overwrite": true}`),
+		toolUseResponse("toolu_2", "create_presentation", `{"output_path": "new.pptx"}`),
+		textResponse("I can only leave comments."),
+	)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_save_as.py", `{"success": true}`)
+	settings := env.app.GetSettings()
+	settings.ReviewMode = true
+	env.app.aiAgent.SetSettings(settings)
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Review the deck"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	for _, tool := range env.llm.Requests[0].Tools {
+		if name := tool.OfTool.Name; name == "save_presentation_as" || name == "create_presentation" || name == "export_pdf" {
+			t.Errorf("expected %s to be left out in review mode", name)
+		}
+	}
+	if calls := len(env.uno.Calls("uno_save_as.py")); calls != 0 {
+		t.Errorf("expected the overwrite to be refused, got %d saves", calls)
+	}
+	if _, err := os.Stat("new.pptx"); err == nil || env.app.presentationPath() != path {
+		t.Errorf("expected no new presentation, got %s open", env.app.presentationPath())
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// The UNO scripts are embedded so the packaged app doesn't depend on its working
+// directory. They are extracted once per script version into the user cache directory,
+// since python3 needs real files (the scripts import each other).
+
+//go:embed scripts/*.py
+var embeddedScripts embed.FS
+
+// scriptsDirEnv points at a scripts directory to use instead of the embedded copy,
+// e.g. to try script changes without rebuilding
+const scriptsDirEnv = "SLIDEPILOT_SCRIPTS_DIR"
+
+// unoScripts resolves where the app's UNO scripts live
+var unoScripts = NewScriptManager(mustSub(embeddedScripts, "scripts"), scriptsCacheRoot())
+
+// ScriptManager extracts a set of scripts to disk on first use and resolves their paths
+type ScriptManager struct {
+	source    fs.FS
+	cacheRoot string
+
+	once sync.Once
+	dir  string
+	err  error
+}
+
+// NewScriptManager creates a manager extracting the scripts in source under cacheRoot
+func NewScriptManager(source fs.FS, cacheRoot string) *ScriptManager {
+	return &ScriptManager{source: source, cacheRoot: cacheRoot}
+}
+
+// Dir returns the directory holding the scripts, extracting them on the first call.
+// SLIDEPILOT_SCRIPTS_DIR overrides it.
+func (m *ScriptManager) Dir() (string, error) {
+	if override := os.Getenv(scriptsDirEnv); override != "" {
+		return override, nil
+	}
+	m.once.Do(func() {
+		m.dir, m.err = m.extract()
+	})
+	return m.dir, m.err
+}
+
+// Path returns the on-disk path of a script
+func (m *ScriptManager) Path(script string) (string, error) {
+	dir, err := m.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, script), nil
+}
+
+// extract writes the scripts to a directory named after their content hash. An existing
+// directory for the same hash is reused, so each script version is written only once.
+func (m *ScriptManager) extract() (string, error) {
+	names, err := fs.Glob(m.source, "*.py")
+	if err != nil {
+		return "", fmt.Errorf("failed to list embedded scripts: %v", err)
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	contents := make(map[string][]byte, len(names))
+	for _, name := range names {
+		data, err := fs.ReadFile(m.source, name)
+		if err != nil {
+			return "", fmt.Errorf("failed to read embedded script %s: %v", name, err)
+		}
+		contents[name] = data
+		fmt.Fprintf(hash, "%s\x00%d\x00", name, len(data))
+		hash.Write(data)
+	}
+
+	dir := filepath.Join(m.cacheRoot, "scripts-"+hex.EncodeToString(hash.Sum(nil))[:16])
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return dir, nil
+	}
+
+	if err := os.MkdirAll(m.cacheRoot, 0755); err != nil {
+		return "", fmt.Errorf("failed to create scripts cache directory: %v", err)
+	}
+	// Extract next to the final location and rename, so a concurrent instance never sees
+	// a half-written directory
+	staging, err := os.MkdirTemp(m.cacheRoot, "extract-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create scripts cache directory: %v", err)
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(staging, name), contents[name], 0644); err != nil {
+			os.RemoveAll(staging)
+			return "", fmt.Errorf("failed to extract script %s: %v", name, err)
+		}
+	}
+	if err := os.Rename(staging, dir); err != nil {
+		os.RemoveAll(staging)
+		// Another instance extracted the same version first
+		if info, statErr := os.Stat(dir); statErr == nil && info.IsDir() {
+			return dir, nil
+		}
+		return "", fmt.Errorf("failed to extract scripts: %v", err)
+	}
+
+	fmt.Printf("Extracted UNO scripts to %s\n", dir)
+	return dir, nil
+}
+
+// scriptsCacheRoot is where extracted scripts are kept. The temp fallback deliberately
+// avoids the slidepilot-<pid> names the janitor cleans up.
+func scriptsCacheRoot() string {
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(cacheDir, "slidepilot")
+	}
+	return filepath.Join(os.TempDir(), "slidepilot.cache")
+}
+
+// unoScriptsDir returns the scripts directory, falling back to ./scripts if the embedded
+// scripts can't be extracted
+func unoScriptsDir() string {
+	dir, err := unoScripts.Dir()
+	if err != nil {
+		fmt.Printf("Warning: %v, using ./scripts\n", err)
+		return "scripts"
+	}
+	return dir
+}
+
+// mustSub returns the subtree of an embedded filesystem
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
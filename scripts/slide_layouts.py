@@ -0,0 +1,65 @@
+#!/usr/bin/env python3
+"""
+Applying PowerPoint slide layouts through LibreOffice.
+
+Impress has no notion of OOXML layouts: a slide has a master page and an AutoLayout
+(the placeholder arrangement). LibreOffice imports the layouts of a .pptx as master pages
+named after them, so a layout is applied by switching to the master page of that name
+when there is one, and setting the AutoLayout that matches the layout type.
+
+Used by uno_set_slide_layout.py and uno_add_slide.py.
+"""
+
+# Impress AutoLayout for each OOXML layout type (sd's AutoLayout enum)
+AUTO_LAYOUTS = {
+    "title": 0,           # Title Slide
+    "secHead": 0,         # Section Header: title and text, closest to Title Slide
+    "obj": 1,             # Title, Content
+    "tx": 1,
+    "objTx": 1,
+    "picTx": 1,
+    "twoObj": 3,          # Title, 2 Content
+    "twoTxTwoObj": 3,
+    "fourObj": 18,        # Title, 4 Content
+    "titleOnly": 19,      # Title Only
+    "blank": 20,          # Blank Slide
+    "vertTitleAndTx": 28, # Vertical Title, Vertical Text
+    "vertTx": 29,         # Title, Vertical Text
+    "objOnly": 32,        # Centered Text
+}
+
+
+def find_master_page(doc, name):
+    """Return the master page called name (case-insensitive), or None"""
+    if not name:
+        return None
+    masters = doc.getMasterPages()
+    for i in range(masters.getCount()):
+        master = masters.getByIndex(i)
+        if master.Name.lower() == name.lower():
+            return master
+    return None
+
+
+def apply_layout(doc, slide, layout):
+    """Apply a layout ({"name": ..., "type": ...}) to a slide.
+
+    Returns what was changed: the master page switched to and the AutoLayout set.
+    Raises ValueError when neither applies.
+    """
+    applied = {}
+
+    master = find_master_page(doc, layout.get("name"))
+    if master is not None:
+        slide.MasterPage = master
+        applied["master_page"] = master.Name
+
+    auto_layout = AUTO_LAYOUTS.get(layout.get("type", ""))
+    if auto_layout is not None:
+        slide.Layout = auto_layout
+        applied["auto_layout"] = auto_layout
+
+    if not applied:
+        raise ValueError(f"Layout {layout.get('name') or layout.get('type')!r} can't be applied: "
+                         "no master page has its name and its type has no Impress equivalent")
+    return applied
@@ -0,0 +1,65 @@
+#!/usr/bin/env python3
+"""
+Fitting text into its shape through LibreOffice.
+
+A text frame either keeps its size and lets text run past its edge, shrinks the text
+to fit (TextFitToSize AUTOFIT, PowerPoint's "shrink text on overflow") or grows to fit
+the text (TextAutoGrowHeight, PowerPoint's "resize shape to fit text").
+
+Overflow is measured by letting a frame grow to its text and comparing the sizes; the
+caller opens the document read-only and never stores the change.
+
+Used by uno_check_text_overflow.py, uno_edit_slide.py, uno_set_rich_text.py and
+uno_apply_edits.py.
+"""
+import uno
+
+AUTOFIT_MODES = ("shrink", "resize", "none")
+
+
+def has_property(obj, name):
+    try:
+        return obj.getPropertySetInfo().hasPropertyByName(name)
+    except Exception:
+        return False
+
+
+def fit_mode(shape):
+    """Return how the shape fits its text: shrink, resize or none"""
+    if has_property(shape, "TextFitToSize") and shape.TextFitToSize.value == "AUTOFIT":
+        return "shrink"
+    if has_property(shape, "TextAutoGrowHeight") and shape.TextAutoGrowHeight:
+        return "resize"
+    return "none"
+
+
+def apply_autofit(shape, mode):
+    """Make the shape shrink its text, grow to its text, or neither"""
+    if not has_property(shape, "TextFitToSize"):
+        raise ValueError(f"Shape '{shape.Name}' can't fit its text automatically")
+    if mode == "shrink":
+        shape.TextAutoGrowHeight = False
+        shape.TextFitToSize = uno.Enum("com.sun.star.drawing.TextFitToSizeType", "AUTOFIT")
+    elif mode == "resize":
+        shape.TextFitToSize = uno.Enum("com.sun.star.drawing.TextFitToSizeType", "NONE")
+        shape.TextAutoGrowHeight = True
+    elif mode == "none":
+        shape.TextFitToSize = uno.Enum("com.sun.star.drawing.TextFitToSizeType", "NONE")
+        shape.TextAutoGrowHeight = False
+    else:
+        raise ValueError(f"Unknown autofit mode '{mode}', expected one of {', '.join(AUTOFIT_MODES)}")
+
+
+def needed_size(shape):
+    """Return the (width, height) the shape's text needs, in 1/100 mm.
+
+    Changes the shape's size; only call it on documents that aren't stored.
+    """
+    size = shape.getSize()
+    width, height = size.Width, size.Height
+    if has_property(shape, "TextWordWrap") and not shape.TextWordWrap:
+        shape.TextAutoGrowWidth = True
+        width = shape.getSize().Width
+    shape.TextAutoGrowHeight = True
+    height = shape.getSize().Height
+    return width, height
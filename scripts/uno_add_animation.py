@@ -0,0 +1,308 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.beans import NamedValue
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, get_slide
+
+# Effects offered to the agent, by class: (preset id, default duration in seconds)
+EFFECTS = {
+    "entrance": {
+        "appear": ("ooo-entrance-appear", 0.0),
+        "fade": ("ooo-entrance-fade-in", 0.5),
+        "fly_in": ("ooo-entrance-fly-in", 0.5),
+        "wipe": ("ooo-entrance-wipe", 0.5),
+    },
+    "exit": {
+        "disappear": ("ooo-exit-disappear", 0.0),
+        "fade": ("ooo-exit-fade-out", 0.5),
+        "fly_out": ("ooo-exit-fly-out", 0.5),
+        "wipe": ("ooo-exit-wipe", 0.5),
+    },
+    "emphasis": {
+        "spin": ("ooo-emphasis-spin", 2.0),
+        "grow_shrink": ("ooo-emphasis-grow-and-shrink", 2.0),
+    },
+}
+
+# Off-slide start (fly in) or end (fly out) of a shape, as (attribute, formula), by direction
+FLY_POSITIONS = {
+    "from_left": ("X", "0-width/2"),
+    "from_right": ("X", "1+width/2"),
+    "from_top": ("Y", "0-height/2"),
+    "from_bottom": ("Y", "1+height/2"),
+}
+
+# Wipe subtypes and whether the wipe runs backwards, by the edge it starts from
+WIPE_DIRECTIONS = {
+    "from_left": ("LEFTTORIGHT", False),
+    "from_right": ("LEFTTORIGHT", True),
+    "from_top": ("TOPTOBOTTOM", False),
+    "from_bottom": ("TOPTOBOTTOM", True),
+}
+
+PRESET_CLASSES = {"entrance": "ENTRANCE", "exit": "EXIT", "emphasis": "EMPHASIS"}
+TRIGGERS = {"on_click": "ON_CLICK", "with_previous": "WITH_PREVIOUS", "after_previous": "AFTER_PREVIOUS"}
+
+
+def constant(group, name):
+    """Look up a com.sun.star constant such as presentation.EffectNodeType.ON_CLICK"""
+    return uno.getConstantByName(f"com.sun.star.{group}.{name}")
+
+
+def create_node(context, service):
+    """Create an animation node service such as ParallelTimeContainer"""
+    return context.ServiceManager.createInstanceWithContext(f"com.sun.star.animations.{service}", context)
+
+
+def children(node):
+    """Return the child nodes of a time container"""
+    result = []
+    enumeration = node.createEnumeration()
+    while enumeration.hasMoreElements():
+        result.append(enumeration.nextElement())
+    return result
+
+
+def user_data(node, key):
+    """Return a value from a node's UserData, or None"""
+    for value in node.UserData:
+        if value.Name == key:
+            return value.Value
+    return None
+
+
+def begin_seconds(node):
+    """Return a node's begin offset in seconds; click-triggered and unset begins count as 0"""
+    return node.Begin if isinstance(node.Begin, float) else 0.0
+
+
+def end_seconds(node):
+    """Return when a node ends relative to its parent's start"""
+    if not hasattr(node, "createEnumeration"):
+        duration = node.Duration if isinstance(node.Duration, float) else 0.0
+        return begin_seconds(node) + duration
+    return begin_seconds(node) + max([end_seconds(child) for child in children(node)] or [0.0])
+
+
+def targets_shape(node, shape):
+    """Report whether an effect node animates the given shape"""
+    for child in children(node):
+        if child.Target == shape:
+            return True
+    return False
+
+
+def main_sequence(context, slide):
+    """Return the slide's main animation sequence, creating it when the slide has none"""
+    root = slide.getAnimationNode()
+    main_type = constant("presentation.EffectNodeType", "MAIN_SEQUENCE")
+    for child in children(root):
+        if user_data(child, "node-type") == main_type:
+            return child
+
+    sequence = create_node(context, "SequenceTimeContainer")
+    sequence.UserData = (NamedValue("node-type", main_type),)
+    root.appendChild(sequence)
+    return sequence
+
+
+def remove_shape_effects(sequence, shape):
+    """Remove every effect of the shape, and the groups that become empty; returns the count"""
+    removed = 0
+    for click_group in children(sequence):
+        for group in children(click_group):
+            for effect in children(group):
+                if targets_shape(effect, shape):
+                    group.removeChild(effect)
+                    removed += 1
+            if not children(group):
+                click_group.removeChild(group)
+        if not children(click_group):
+            sequence.removeChild(click_group)
+    return removed
+
+
+def animation(context, service, shape, begin, duration, **properties):
+    """Create one animation primitive (Set, Animate, ...) on a shape"""
+    node = create_node(context, service)
+    node.Target = shape
+    node.Begin = float(begin)
+    node.Duration = float(duration)
+    node.Fill = constant("animations.AnimationFill", "HOLD")
+    for name, value in properties.items():
+        setattr(node, name, value)
+    return node
+
+
+def visibility(context, shape, begin, visible):
+    """Create the Set node that shows or hides the shape"""
+    return animation(context, "AnimateSet", shape, begin, 0.001,
+                     AttributeName="Visibility", To="visible" if visible else "hidden")
+
+
+def effect_animations(context, shape, effect_class, effect, direction, duration):
+    """Build the animation primitives of an effect; returns (nodes, preset subtype)"""
+    if effect_class == "entrance":
+        nodes = [visibility(context, shape, 0, True)]
+    else:
+        nodes = []
+
+    subtype = ""
+    if effect in ("fade", "wipe"):
+        if effect == "fade":
+            filter_type, filter_subtype, reverse = "FADE", "CROSSFADE", False
+        else:
+            filter_type = "BARWIPE"
+            filter_subtype, reverse = WIPE_DIRECTIONS[direction]
+            subtype = direction.replace("_", "-")
+        nodes.append(animation(context, "TransitionFilter", shape, 0, duration,
+                               Transition=constant("animations.TransitionType", filter_type),
+                               Subtype=constant("animations.TransitionSubType", filter_subtype),
+                               Direction=not reverse,
+                               Mode=effect_class == "entrance"))
+    elif effect in ("fly_in", "fly_out"):
+        attribute, outside = FLY_POSITIONS[direction]
+        inside = attribute.lower()
+        values = (outside, inside) if effect == "fly_in" else (inside, outside)
+        nodes.append(animation(context, "Animate", shape, 0, duration,
+                               AttributeName=attribute, Values=values, KeyTimes=(0.0, 1.0)))
+        subtype = direction.replace("_", "-")
+    elif effect == "spin":
+        nodes.append(animation(context, "AnimateTransform", shape, 0, duration,
+                               TransformType=constant("animations.AnimationTransformType", "ROTATE"),
+                               By=360.0))
+    elif effect == "grow_shrink":
+        grown = uno.createUnoStruct("com.sun.star.animations.ValuePair")
+        grown.First = 1.5
+        grown.Second = 1.5
+        nodes.append(animation(context, "AnimateTransform", shape, 0, duration,
+                               TransformType=constant("animations.AnimationTransformType", "SCALE"),
+                               To=grown, AutoReverse=True))
+
+    if effect_class == "exit":
+        nodes.append(visibility(context, shape, duration, False))
+    return nodes, subtype
+
+
+def add_animation(pptx_path, slide_number, shape_index, settings):
+    """Add an entrance, exit or emphasis effect for a shape to the slide's animation sequence"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        shape_count = slide.getCount()
+        if shape_index < 0 or shape_index >= shape_count:
+            raise ValueError(f"Shape index {shape_index} out of range (0-{shape_count - 1})")
+        shape = slide.getByIndex(shape_index)
+
+        effect_class = settings["effect_type"]
+        effect = settings["effect"]
+        preset_id, default_duration = EFFECTS[effect_class][effect]
+        direction = settings.get("direction") or "from_left"
+        trigger = settings.get("trigger") or "on_click"
+        duration = settings.get("duration") or default_duration
+        delay = settings.get("delay") or 0.0
+        order = settings.get("order") or 0
+
+        sequence = main_sequence(context, slide)
+        removed = remove_shape_effects(sequence, shape) if settings.get("replace_existing") else 0
+        click_groups = children(sequence)
+        if order > len(click_groups) + (1 if trigger == "on_click" else 0):
+            raise ValueError(f"Order {order} out of range (1-{len(click_groups) + 1}): the slide has {len(click_groups)} animation steps")
+
+        if trigger == "on_click" or not click_groups:
+            # A new step; the slide's first step starts by itself unless it waits for a click
+            click_group = create_node(context, "ParallelTimeContainer")
+            if trigger == "on_click":
+                click_group.Begin = uno.Enum("com.sun.star.animations.Timing", "INDEFINITE")
+            else:
+                click_group.Begin = 0.0
+            if order and order <= len(click_groups):
+                sequence.insertBefore(click_group, click_groups[order - 1])
+            else:
+                sequence.appendChild(click_group)
+            group = None
+        else:
+            # Join the chosen step (the last one by default)
+            click_group = click_groups[order - 1] if order else click_groups[-1]
+            groups = children(click_group)
+            group = groups[-1] if groups and trigger == "with_previous" else None
+
+        if group is None:
+            group = create_node(context, "ParallelTimeContainer")
+            group.Begin = max([end_seconds(g) for g in children(click_group)] or [0.0])
+            click_group.appendChild(group)
+
+        effect_node = create_node(context, "ParallelTimeContainer")
+        effect_node.Begin = float(delay)
+        effect_node.Fill = constant("animations.AnimationFill", "HOLD")
+        nodes, subtype = effect_animations(context, shape, effect_class, effect, direction, duration)
+        effect_node.UserData = (
+            NamedValue("node-type", constant("presentation.EffectNodeType", TRIGGERS[trigger])),
+            NamedValue("preset-id", preset_id),
+            NamedValue("preset-sub-type", subtype),
+            NamedValue("preset-class", constant("presentation.EffectPresetClass", PRESET_CLASSES[effect_class])),
+        )
+        for node in nodes:
+            effect_node.appendChild(node)
+        group.appendChild(effect_node)
+
+        step = children(sequence).index(click_group) + 1
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        return {
+            "success": True,
+            "slide_number": slide_number,
+            "shape_index": shape_index,
+            "effect_type": effect_class,
+            "effect": effect,
+            "trigger": trigger,
+            "duration": duration,
+            "delay": delay,
+            "step": step,
+            "removed_effects": removed,
+            "message": f"Added {effect_class} effect '{effect}' to shape {shape_index} on slide {slide_number} (animation step {step})"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error adding animation: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 5:
+        print("Usage: python3 uno_add_animation.py <pptx_path> <slide_number> <shape_index> <settings_json>")
+        print("Settings: effect_type, effect, direction, trigger, duration, delay, order, replace_existing")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        slide_number = int(sys.argv[2])
+        shape_index = int(sys.argv[3])
+        settings = json.loads(sys.argv[4])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number and shape index must be integers and settings valid JSON"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = add_animation(pptx_path, slide_number, shape_index, settings)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
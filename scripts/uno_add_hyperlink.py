@@ -0,0 +1,125 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, get_slide
+
+
+def utf16_length(text):
+    """Length of text in UTF-16 code units, which text cursors move by"""
+    return len(text.encode("utf-16-le")) // 2
+
+
+def find_occurrence(content, text, occurrence):
+    """Return the index of the nth occurrence of text in content, or -1"""
+    index = -1
+    for _ in range(occurrence):
+        index = content.find(text, index + 1)
+        if index < 0:
+            return -1
+    return index
+
+
+def add_hyperlink(pptx_path, slide_number, shape_index, text, occurrence, url, target_slide):
+    """Link text inside a shape, or the whole shape, to a URL or another slide"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        shape_count = slide.getCount()
+        if shape_index < 0 or shape_index >= shape_count:
+            raise ValueError(f"Shape index {shape_index} out of range (0-{shape_count - 1})")
+        shape = slide.getByIndex(shape_index)
+
+        if target_slide:
+            # Slide jumps go by page name, which the PPTX export turns back into a slide link
+            target_page = get_slide(doc, target_slide)
+            link = "#" + target_page.getName()
+            description = f"slide {target_slide}"
+        else:
+            link = url
+            description = url
+
+        if text:
+            if not hasattr(shape, "getText"):
+                raise ValueError(f"Shape {shape_index} does not contain editable text")
+            content = shape.getString()
+            index = find_occurrence(content, text, occurrence)
+            if index < 0:
+                raise ValueError(f"Text '{text}' (occurrence {occurrence}) not found in shape {shape_index}")
+
+            shape_text = shape.getText()
+            cursor = shape_text.createTextCursor()
+            cursor.gotoStart(False)
+            cursor.goRight(utf16_length(content[:index]), False)
+            cursor.goRight(utf16_length(text), True)
+
+            field = doc.createInstance("com.sun.star.text.TextField.URL")
+            field.URL = link
+            field.Representation = text
+            shape_text.insertTextContent(cursor, field, True)
+            linked = f"text '{text}'"
+        else:
+            if target_slide:
+                shape.OnClick = uno.Enum("com.sun.star.presentation.ClickAction", "BOOKMARK")
+                shape.Bookmark = link[1:]
+            else:
+                shape.OnClick = uno.Enum("com.sun.star.presentation.ClickAction", "DOCUMENT")
+                shape.Bookmark = link
+            linked = "the shape"
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        return {
+            "success": True,
+            "slide_number": slide_number,
+            "shape_index": shape_index,
+            "text": text,
+            "url": url,
+            "target_slide": target_slide or None,
+            "message": f"Linked {linked} in shape {shape_index} on slide {slide_number} to {description}"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error adding hyperlink: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 8:
+        print("Usage: python3 uno_add_hyperlink.py <pptx_path> <slide_number> <shape_index> <text> <occurrence> <url> <target_slide>")
+        print("Pass '' as text to link the whole shape, and either a url or a target_slide (0 for none)")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+    text = sys.argv[4]
+    url = sys.argv[6]
+
+    try:
+        slide_number = int(sys.argv[2])
+        shape_index = int(sys.argv[3])
+        occurrence = int(sys.argv[5])
+        target_slide = int(sys.argv[7])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number, shape index, occurrence and target slide must be integers"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = add_hyperlink(pptx_path, slide_number, shape_index, text, occurrence, url, target_slide)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
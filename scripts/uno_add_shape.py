@@ -0,0 +1,150 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from com.sun.star.awt import Point, Size
+from com.sun.star.drawing.FillStyle import NONE as FILL_NONE, SOLID as FILL_SOLID
+from com.sun.star.drawing.LineStyle import NONE as LINE_NONE, SOLID as LINE_SOLID
+from uno_connection import connect, load_presentation, get_slide, inches_to_units, units_to_inches, unique_shape_name
+
+SHAPE_SERVICES = {
+    "rectangle": "com.sun.star.drawing.RectangleShape",
+    "ellipse": "com.sun.star.drawing.EllipseShape",
+    "line": "com.sun.star.drawing.LineShape",
+    "arrow": "com.sun.star.drawing.LineShape",
+    "text_box": "com.sun.star.drawing.TextShape",
+}
+
+# Base names of new shapes, numbered per slide to give them a unique shape_id
+SHAPE_NAMES = {
+    "rectangle": "Rectangle",
+    "ellipse": "Ellipse",
+    "line": "Line",
+    "arrow": "Arrow",
+    "text_box": "TextBox",
+}
+
+# Defaults in inches when no position or size is given
+DEFAULT_X = 1.0
+DEFAULT_Y = 1.0
+DEFAULT_WIDTH = 3.0
+DEFAULT_HEIGHT = 1.0
+
+# Line widths are given in points; LibreOffice uses 1/100mm
+UNITS_PER_POINT = 2540 / 72
+
+def parse_color(value):
+    """Parse an RRGGBB hex string into a LibreOffice color integer"""
+    return int(value.lstrip("#"), 16)
+
+def add_shape(pptx_path, slide_number, shape_type, x=None, y=None, width=None, height=None,
+              text="", fill_color="", line_color="", line_width=None):
+    """Add a new shape to a slide. For lines and arrows, (x, y) is the start point and
+    width/height the offset to the end point."""
+    try:
+        if shape_type not in SHAPE_SERVICES:
+            raise ValueError(f"Unknown shape_type '{shape_type}'. Use one of: {', '.join(SHAPE_SERVICES)}")
+        is_line = shape_type in ("line", "arrow")
+
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+
+        target_x = inches_to_units(x if x is not None else DEFAULT_X)
+        target_y = inches_to_units(y if y is not None else DEFAULT_Y)
+        target_width = inches_to_units(width if width is not None else DEFAULT_WIDTH)
+        target_height = inches_to_units(height if height is not None else (0 if is_line else DEFAULT_HEIGHT))
+
+        shape = doc.createInstance(SHAPE_SERVICES[shape_type])
+        shape_name = unique_shape_name(slide, SHAPE_NAMES[shape_type])
+        slide.add(shape)
+        shape.Name = shape_name
+        shape.setPosition(Point(target_x, target_y))
+        shape.setSize(Size(target_width, target_height))
+
+        if shape_type == "arrow":
+            shape.LineEndName = "Arrow"
+            shape.LineEndWidth = 300
+
+        if not is_line:
+            if fill_color:
+                shape.FillStyle = FILL_SOLID
+                shape.FillColor = parse_color(fill_color)
+            elif shape_type == "text_box":
+                shape.FillStyle = FILL_NONE
+
+        if line_color:
+            shape.LineStyle = LINE_SOLID
+            shape.LineColor = parse_color(line_color)
+        elif shape_type == "text_box":
+            shape.LineStyle = LINE_NONE
+        if line_width is not None:
+            shape.LineWidth = int(round(line_width * UNITS_PER_POINT))
+
+        if text and not is_line:
+            shape.setString(text)
+
+        shape_index = slide.getCount() - 1
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        return {
+            "success": True,
+            "slide_number": slide_number,
+            "shape_index": shape_index,
+            "shape_id": shape_name,
+            "shape_type": shape_type,
+            "x": units_to_inches(target_x),
+            "y": units_to_inches(target_y),
+            "width": units_to_inches(target_width),
+            "height": units_to_inches(target_height),
+            "message": f"Added {shape_type} as shape {shape_index} on slide {slide_number}"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error adding shape: {e}")
+
+def parse_optional_float(value):
+    return float(value) if value not in (None, "") else None
+
+if __name__ == "__main__":
+    if len(sys.argv) < 4:
+        print("Usage: python3 uno_add_shape.py <pptx_path> <slide_number> <shape_type> [x] [y] [width] [height] [text] [fill_color] [line_color] [line_width]")
+        print("Position and size are in inches, colors are RRGGBB hex and line width is in points; omit or pass '' to use defaults")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+    shape_type = sys.argv[3]
+
+    def arg(i):
+        return sys.argv[i] if len(sys.argv) > i else ""
+
+    try:
+        slide_number = int(sys.argv[2])
+        x, y, width, height = [parse_optional_float(arg(i)) for i in range(4, 8)]
+        line_width = parse_optional_float(arg(11))
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number must be an integer and position/size/line width must be numbers"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = add_shape(pptx_path, slide_number, shape_type, x, y, width, height,
+                           arg(8), arg(9), arg(10), line_width)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -4,8 +4,51 @@ import sys
 import os
 import json
 from com.sun.star.connection import NoConnectException
+from uno_connection import UNO_URL
+from slide_layouts import apply_layout
 
-def add_slide(pptx_path, position=None, layout="blank", title=None):
+TITLE_TYPES = (
+    "com.sun.star.presentation.TitleTextShape",
+)
+
+# Placeholders LibreOffice fills in itself keep the master's font
+FIELD_TYPES = (
+    "com.sun.star.presentation.FooterShape",
+    "com.sun.star.presentation.SlideNumberShape",
+    "com.sun.star.presentation.DateTimeShape",
+)
+
+
+def apply_brand(slide, brand, title_shape=None):
+    """Give the slide's text the brand's fonts and show its footer"""
+    heading_font = brand.get("heading_font")
+    body_font = brand.get("body_font")
+    for index in range(slide.getCount()):
+        shape = slide.getByIndex(index)
+        shape_type = shape.getShapeType()
+        if shape_type in FIELD_TYPES or not shape.supportsService("com.sun.star.drawing.Text"):
+            continue
+        is_title = shape_type in TITLE_TYPES or shape == title_shape
+        font = heading_font if is_title else body_font
+        if not font:
+            continue
+        try:
+            # Empty placeholders keep the font for the text typed into them
+            shape.setPropertyValue("CharFontName", font)
+            if shape.getString():
+                cursor = shape.createTextCursor()
+                cursor.gotoStart(False)
+                cursor.gotoEnd(True)
+                cursor.setPropertyValue("CharFontName", font)
+        except Exception:
+            pass  # Shapes without character properties keep their font
+
+    if brand.get("footer_text"):
+        slide.FooterText = brand["footer_text"]
+        slide.IsFooterVisible = True
+
+
+def add_slide(pptx_path, position=None, layout="blank", title=None, brand=None):
     """Add a new slide to a presentation with optional initial content"""
     try:
         # Connect to LibreOffice
@@ -14,7 +57,7 @@ def add_slide(pptx_path, position=None, layout="blank", title=None):
             "com.sun.star.bridge.UnoUrlResolver", local_context)
         
         # Connect to the running LibreOffice instance
-        context = resolver.resolve("uno:socket,host=localhost,port=8100;urp;StarOffice.ComponentContext")
+        context = resolver.resolve(UNO_URL)
         desktop = context.ServiceManager.createInstanceWithContext(
             "com.sun.star.frame.Desktop", context)
         
@@ -43,8 +86,14 @@ def add_slide(pptx_path, position=None, layout="blank", title=None):
         
         # Insert new slide at specified position
         new_slide = slides.insertNewByIndex(position)
+
+        # Apply a template layout; the app passes one as {"name": ..., "type": ...}
+        applied_layout = None
+        if layout.startswith("{"):
+            applied_layout = apply_layout(doc, new_slide, json.loads(layout))
         
         # Add title if provided
+        shape_service = None
         if title:
             # Create a title text box
             try:
@@ -85,7 +134,10 @@ def add_slide(pptx_path, position=None, layout="blank", title=None):
                     
             except Exception as e:
                 # If title creation fails, continue without it
-                pass
+                shape_service = None
+
+        if brand:
+            apply_brand(new_slide, brand, shape_service)
         
         # Save the document
         doc.store()
@@ -102,7 +154,9 @@ def add_slide(pptx_path, position=None, layout="blank", title=None):
             "new_slide_number": new_slide_number,
             "total_slides": new_slide_count,
             "message": f"Successfully added slide {new_slide_number} of {new_slide_count}",
-            "title": title if title else "Untitled"
+            "title": title if title else "Untitled",
+            "layout": applied_layout,
+            "brand_applied": bool(brand)
         }
         
     except NoConnectException:
@@ -112,13 +166,14 @@ def add_slide(pptx_path, position=None, layout="blank", title=None):
 
 if __name__ == "__main__":
     if len(sys.argv) < 2:
-        print("Usage: python3 uno_add_slide.py <pptx_path> [position] [layout] [title]")
+        print("Usage: python3 uno_add_slide.py <pptx_path> [position] [layout] [title] [brand_json]")
         sys.exit(1)
     
     pptx_path = sys.argv[1]
     position = None
     layout = "blank"
     title = None
+    brand = None
     
     # Parse optional arguments
     if len(sys.argv) > 2 and sys.argv[2].isdigit():
@@ -126,10 +181,16 @@ if __name__ == "__main__":
     if len(sys.argv) > 3:
         layout = sys.argv[3]
     if len(sys.argv) > 4:
-        title = sys.argv[4]
+        title = sys.argv[4] or None
+    if len(sys.argv) > 5:
+        try:
+            brand = json.loads(sys.argv[5])
+        except ValueError:
+            print(json.dumps({"success": False, "error": "Brand profile must be valid JSON"}, indent=2))
+            sys.exit(1)
     
     try:
-        result = add_slide(pptx_path, position, layout, title)
+        result = add_slide(pptx_path, position, layout, title, brand)
         print(json.dumps(result, indent=2))
     except Exception as e:
         error_result = {
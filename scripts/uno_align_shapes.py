@@ -0,0 +1,149 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from com.sun.star.awt import Point
+from uno_connection import connect, load_presentation, get_slide, shape_ids, units_to_inches
+
+ALIGNMENTS = ("left", "center", "right", "top", "middle", "bottom",
+              "distribute_horizontally", "distribute_vertically")
+
+
+class Box:
+    """A shape's bounding box in 1/100 mm"""
+
+    def __init__(self, shape):
+        self.shape = shape
+        position = shape.getPosition()
+        size = shape.getSize()
+        self.x, self.y = position.X, position.Y
+        self.width, self.height = size.Width, size.Height
+
+
+def target_positions(boxes, alignment, frame):
+    """Return the new (x, y) of each box; frame is the (left, top, right, bottom) to align to"""
+    left, top, right, bottom = frame
+    positions = []
+    if alignment.startswith("distribute_"):
+        horizontal = alignment == "distribute_horizontally"
+        ordered = sorted(boxes, key=lambda box: box.x if horizontal else box.y)
+        sizes = [box.width if horizontal else box.height for box in ordered]
+        start = left if horizontal else top
+        end = right if horizontal else bottom
+        gap = (end - start - sum(sizes)) / (len(ordered) - 1) if len(ordered) > 1 else 0
+        offset = start
+        moved = {}
+        for box, size in zip(ordered, sizes):
+            moved[id(box)] = int(round(offset))
+            offset += size + gap
+        for box in boxes:
+            positions.append((moved[id(box)], box.y) if horizontal else (box.x, moved[id(box)]))
+        return positions
+
+    for box in boxes:
+        x, y = box.x, box.y
+        if alignment == "left":
+            x = left
+        elif alignment == "center":
+            x = int(round((left + right - box.width) / 2))
+        elif alignment == "right":
+            x = right - box.width
+        elif alignment == "top":
+            y = top
+        elif alignment == "middle":
+            y = int(round((top + bottom - box.height) / 2))
+        elif alignment == "bottom":
+            y = bottom - box.height
+        positions.append((x, y))
+    return positions
+
+
+def align_shapes(pptx_path, slide_number, shape_indexes, alignment, relative_to):
+    """Align or distribute shapes of a slide, relative to each other or to the slide"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        shape_count = slide.getCount()
+        for shape_index in shape_indexes:
+            if shape_index < 0 or shape_index >= shape_count:
+                raise ValueError(f"Shape index {shape_index} out of range (0-{shape_count - 1})")
+
+        boxes = [Box(slide.getByIndex(index)) for index in shape_indexes]
+        if relative_to == "slide":
+            frame = (0, 0, slide.Width, slide.Height)
+        elif alignment.startswith("distribute_"):
+            # The outermost shapes stay where they are and the others spread between them
+            horizontal = alignment == "distribute_horizontally"
+            first = min(boxes, key=lambda box: box.x if horizontal else box.y)
+            last = max(boxes, key=lambda box: box.x if horizontal else box.y)
+            frame = (first.x, first.y, last.x + last.width, last.y + last.height)
+        else:
+            frame = (min(box.x for box in boxes), min(box.y for box in boxes),
+                     max(box.x + box.width for box in boxes), max(box.y + box.height for box in boxes))
+
+        ids = shape_ids([slide.getByIndex(index).Name for index in range(shape_count)])
+        shapes = []
+        for shape_index, box, (x, y) in zip(shape_indexes, boxes, target_positions(boxes, alignment, frame)):
+            if (x, y) != (box.x, box.y):
+                box.shape.setPosition(Point(x, y))
+            shapes.append({
+                "shape_index": shape_index,
+                "shape_id": ids[shape_index],
+                "x": units_to_inches(x),
+                "y": units_to_inches(y),
+                "moved": (x, y) != (box.x, box.y),
+            })
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        moved = sum(1 for shape in shapes if shape["moved"])
+        verb = "Distributed" if alignment.startswith("distribute_") else "Aligned"
+        return {
+            "success": True,
+            "slide_number": slide_number,
+            "alignment": alignment,
+            "relative_to": relative_to,
+            "shapes": shapes,
+            "message": f"{verb} {len(shapes)} shapes on slide {slide_number} ({alignment.replace('_', ' ')} relative to the {relative_to}); {moved} moved"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error aligning shapes: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 6 or sys.argv[4] not in ALIGNMENTS or sys.argv[5] not in ("shapes", "slide"):
+        print("Usage: python3 uno_align_shapes.py <pptx_path> <slide_number> <shape_indexes_json> <alignment> shapes|slide")
+        print("Alignments: " + ", ".join(ALIGNMENTS))
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        slide_number = int(sys.argv[2])
+        shape_indexes = json.loads(sys.argv[3])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number must be an integer and shape indexes a JSON list"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = align_shapes(pptx_path, slide_number, shape_indexes, sys.argv[4], sys.argv[5])
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -0,0 +1,231 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from com.sun.star.awt import Point, Size
+from com.sun.star.awt.FontWeight import BOLD, NORMAL
+from com.sun.star.style import LineSpacing
+from com.sun.star.style.LineSpacingMode import PROP
+from uno_connection import connect, load_presentation, get_slide, inches_to_units, shape_ids
+from text_fit import apply_autofit
+
+# ParagraphAdjust value for each format_text alignment
+PARAGRAPH_ADJUST = {
+    "left": "LEFT",
+    "center": "CENTER",
+    "right": "RIGHT",
+    "justify": "BLOCK",
+}
+
+
+def points_to_units(points):
+    """Convert points to LibreOffice 1/100mm units."""
+    return int(round(float(points) * 2540 / 72))
+
+
+def find_shape(slide, edit):
+    """Return the shape an edit targets, by shape_id or shape_index"""
+    if edit.get("shape_id"):
+        names = [slide.getByIndex(i).Name for i in range(slide.getCount())]
+        ids = shape_ids(names)
+        if edit["shape_id"] not in ids:
+            raise ValueError(f"No shape with shape_id '{edit['shape_id']}' on slide {edit['slide_number']}")
+        return slide.getByIndex(ids.index(edit["shape_id"]))
+
+    shape_index = edit.get("shape_index")
+    if shape_index is None:
+        raise ValueError("shape_id or shape_index is required")
+    if shape_index < 0 or shape_index >= slide.getCount():
+        raise ValueError(f"Shape index {shape_index} out of range (0-{slide.getCount() - 1})")
+    return slide.getByIndex(shape_index)
+
+
+def text_of(shape):
+    """Return the shape's text object, raising if it has none"""
+    if not hasattr(shape, "getString"):
+        raise ValueError(f"Shape '{shape.Name}' does not contain editable text")
+    return shape
+
+
+def set_text(shape, edit):
+    text_of(shape).setString(edit["text"])
+    if edit.get("autofit"):
+        apply_autofit(shape, edit["autofit"])
+    return f"Set the text of '{shape.Name}'"
+
+
+def replace_text(slide, shape, edit):
+    """Replace every occurrence of old_text in the shape, or in all text shapes of the slide"""
+    if shape is not None:
+        shapes = [text_of(shape)]
+    else:
+        shapes = [slide.getByIndex(i) for i in range(slide.getCount())]
+        shapes = [s for s in shapes if hasattr(s, "createReplaceDescriptor")]
+
+    replaced = 0
+    for target in shapes:
+        descriptor = target.createReplaceDescriptor()
+        descriptor.SearchString = edit["old_text"]
+        descriptor.ReplaceString = edit["text"]
+        descriptor.SearchCaseSensitive = True
+        replaced += target.replaceAll(descriptor)
+    if replaced == 0:
+        raise ValueError(f"Text '{edit['old_text']}' not found on slide {edit['slide_number']}")
+    return f"Replaced {replaced} occurrence(s) of '{edit['old_text']}'"
+
+
+def paragraphs_of(text):
+    """Return the paragraphs of a text in order"""
+    paragraphs = []
+    enumeration = text.createEnumeration()
+    while enumeration.hasMoreElements():
+        paragraph = enumeration.nextElement()
+        if paragraph.supportsService("com.sun.star.text.Paragraph"):
+            paragraphs.append(paragraph)
+    return paragraphs
+
+
+def format_range(target, edit):
+    """Apply an edit's character and paragraph formatting to a cursor or paragraph"""
+    if edit.get("bold") is not None:
+        target.CharWeight = BOLD if edit["bold"] else NORMAL
+    if edit.get("italic") is not None:
+        target.CharPosture = uno.Enum("com.sun.star.awt.FontSlant", "ITALIC" if edit["italic"] else "NONE")
+    if edit.get("color"):
+        target.CharColor = int(edit["color"], 16)
+    if edit.get("font_size"):
+        target.CharHeight = float(edit["font_size"])
+    if edit.get("font_name"):
+        target.CharFontName = edit["font_name"]
+    if edit.get("alignment"):
+        target.ParaAdjust = uno.Enum("com.sun.star.style.ParagraphAdjust", PARAGRAPH_ADJUST[edit["alignment"]])
+    if edit.get("line_spacing"):
+        target.ParaLineSpacing = LineSpacing(PROP, int(round(float(edit["line_spacing"]) * 100)))
+    if edit.get("space_before") is not None:
+        target.ParaTopMargin = points_to_units(edit["space_before"])
+    if edit.get("space_after") is not None:
+        target.ParaBottomMargin = points_to_units(edit["space_after"])
+
+
+def format_text(shape, edit):
+    """Apply character and paragraph formatting to all of the shape's text, or to the
+    paragraphs the edit lists"""
+    text = text_of(shape).getText()
+    if not edit.get("paragraphs"):
+        cursor = text.createTextCursor()
+        cursor.gotoStart(False)
+        cursor.gotoEnd(True)
+        format_range(cursor, edit)
+        return f"Formatted the text of '{shape.Name}'"
+
+    paragraphs = paragraphs_of(text)
+    for index in edit["paragraphs"]:
+        if index >= len(paragraphs):
+            raise ValueError(f"Paragraph {index} out of range (0-{len(paragraphs) - 1}) in '{shape.Name}'")
+    for index in edit["paragraphs"]:
+        format_range(paragraphs[index], edit)
+    listed = ", ".join(str(index) for index in edit["paragraphs"])
+    return f"Formatted paragraph(s) {listed} of '{shape.Name}'"
+
+
+def move_shape(shape, edit):
+    """Move and/or resize a shape; sides not given keep their current value"""
+    position = shape.getPosition()
+    size = shape.getSize()
+    x = inches_to_units(edit["x"]) if edit.get("x") is not None else position.X
+    y = inches_to_units(edit["y"]) if edit.get("y") is not None else position.Y
+    width = inches_to_units(edit["width"]) if edit.get("width") is not None else size.Width
+    height = inches_to_units(edit["height"]) if edit.get("height") is not None else size.Height
+    shape.setPosition(Point(x, y))
+    shape.setSize(Size(width, height))
+    return f"Moved '{shape.Name}'"
+
+
+def apply_edits(pptx_path, edits):
+    """Apply a list of edits in one session, saving only if every edit succeeds"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        try:
+            # Resolve every target before changing anything, so indexes and IDs refer to
+            # the slides as they were when the batch was written
+            targets = []
+            for number, edit in enumerate(edits, 1):
+                try:
+                    slide = get_slide(doc, edit["slide_number"])
+                    needs_shape = edit["op"] != "replace_text" or edit.get("shape_id") or edit.get("shape_index") is not None
+                    targets.append((slide, find_shape(slide, edit) if needs_shape else None))
+                except Exception as e:
+                    raise ValueError(f"Edit {number} ({edit['op']}): {e}")
+
+            results = []
+            for number, (edit, (slide, shape)) in enumerate(zip(edits, targets), 1):
+                try:
+                    op = edit["op"]
+                    if op == "set_text":
+                        message = set_text(shape, edit)
+                    elif op == "replace_text":
+                        message = replace_text(slide, shape, edit)
+                    elif op == "format_text":
+                        message = format_text(shape, edit)
+                    elif op == "move_shape":
+                        message = move_shape(shape, edit)
+                    elif op == "delete_shape":
+                        message = f"Deleted '{shape.Name}'"
+                        slide.remove(shape)
+                    else:
+                        raise ValueError(f"Unknown op '{op}'")
+                except Exception as e:
+                    raise ValueError(f"Edit {number} ({edit['op']}): {e}")
+                results.append({"edit": number, "op": op, "slide_number": edit["slide_number"], "message": message})
+
+            doc.store()
+        finally:
+            # Closing without storing discards every edit of a failed batch
+            doc.close(True)
+
+        slides = sorted({edit["slide_number"] for edit in edits})
+        return {
+            "success": True,
+            "applied": len(results),
+            "slides": slides,
+            "edits": results,
+            "message": f"Applied {len(results)} edit(s) to slide(s) {', '.join(str(s) for s in slides)}"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error applying edits, none were saved: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 3:
+        print("Usage: python3 uno_apply_edits.py <pptx_path> <edits_json>")
+        print("Each edit has op (set_text, replace_text, format_text, move_shape, delete_shape), slide_number and its own fields")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        edits = json.loads(sys.argv[2])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Edits must be valid JSON"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = apply_edits(pptx_path, edits)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -0,0 +1,237 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, get_slide, shape_ids
+
+# Shapes screen readers can only describe through their alternative text
+ALT_TEXT_SERVICES = (
+    "com.sun.star.drawing.GraphicObjectShape",
+    "com.sun.star.drawing.OLE2Shape",
+    "com.sun.star.drawing.MediaShape",
+)
+
+# WCAG 2 AA contrast ratios for normal and large text
+MIN_CONTRAST = 4.5
+MIN_CONTRAST_LARGE = 3.0
+
+# LibreOffice's "automatic" color, which always contrasts with the background
+COLOR_AUTO = -1
+
+# Background used when neither the slide nor its master sets one
+DEFAULT_BACKGROUND = 0xFFFFFF
+
+
+def has_property(obj, name):
+    try:
+        return obj.getPropertySetInfo().hasPropertyByName(name)
+    except Exception:
+        return False
+
+
+def solid_fill(obj):
+    """Return the fill color of an object filled with a single color, None for no or other fills"""
+    if obj is None or not has_property(obj, "FillStyle"):
+        return None
+    if obj.FillStyle.value != "SOLID":
+        return None
+    return obj.FillColor
+
+
+def slide_background(slide):
+    """Return the color behind a slide's shapes, or None when it is a gradient or picture"""
+    for page in (slide, slide.MasterPage):
+        background = page.Background if has_property(page, "Background") else None
+        if background is None or not has_property(background, "FillStyle"):
+            continue
+        if background.FillStyle.value == "NONE":
+            continue
+        return solid_fill(background)
+    return DEFAULT_BACKGROUND
+
+
+def luminance(color):
+    """Relative luminance of a 0xRRGGBB color as WCAG defines it"""
+    def channel(value):
+        value /= 255
+        return value / 12.92 if value <= 0.03928 else ((value + 0.055) / 1.055) ** 2.4
+    red, green, blue = (color >> 16) & 0xFF, (color >> 8) & 0xFF, color & 0xFF
+    return 0.2126 * channel(red) + 0.7152 * channel(green) + 0.0722 * channel(blue)
+
+
+def contrast_ratio(foreground, background):
+    lighter, darker = sorted((luminance(foreground), luminance(background)), reverse=True)
+    return (lighter + 0.05) / (darker + 0.05)
+
+
+def hex_color(color):
+    return f"#{color & 0xFFFFFF:06X}"
+
+
+def text_runs(text):
+    """Yield the non-blank text portions of a text"""
+    paragraphs = text.createEnumeration()
+    while paragraphs.hasMoreElements():
+        paragraph = paragraphs.nextElement()
+        if not paragraph.supportsService("com.sun.star.text.Paragraph"):
+            continue
+        portions = paragraph.createEnumeration()
+        while portions.hasMoreElements():
+            portion = portions.nextElement()
+            if portion.getString().strip():
+                yield portion
+
+
+def check_text(text, background, min_font_size, issue):
+    """Report the smallest font and the weakest contrast of a text, once each"""
+    smallest = None
+    weakest = None
+    for portion in text_runs(text):
+        size = portion.CharHeight
+        if size < min_font_size and (smallest is None or size < smallest[0]):
+            smallest = (size, portion.getString().strip())
+
+        color = portion.CharColor
+        if background is None or color == COLOR_AUTO:
+            continue
+        large = size >= 18 or (size >= 14 and portion.CharWeight >= 150)
+        ratio = contrast_ratio(color, background)
+        required = MIN_CONTRAST_LARGE if large else MIN_CONTRAST
+        if ratio < required and (weakest is None or ratio < weakest[0]):
+            weakest = (ratio, required, color, portion.getString().strip())
+
+    if smallest is not None:
+        size, sample = smallest
+        issue("small_font", f"Text is {size:g}pt, below the {min_font_size:g}pt minimum: \"{sample[:40]}\"",
+              font_size=size)
+    if weakest is not None:
+        ratio, required, color, sample = weakest
+        issue("low_contrast",
+              f"Text color {hex_color(color)} on {hex_color(background)} has a contrast of {ratio:.1f}:1, "
+              f"below {required:g}:1: \"{sample[:40]}\"",
+              contrast=round(ratio, 2), text_color=hex_color(color), background_color=hex_color(background))
+
+
+def check_shape(shape, background, min_font_size, issue):
+    """Check a shape, and the shapes of a group, for missing alt text and unreadable text"""
+    if shape.supportsService("com.sun.star.drawing.GroupShape"):
+        for index in range(shape.getCount()):
+            child = shape.getByIndex(index)
+            check_shape(child, background, min_font_size,
+                        lambda kind, message, **details: issue(kind, message, child=child.Name, **details))
+        return
+
+    if any(shape.supportsService(service) for service in ALT_TEXT_SERVICES):
+        decorative = has_property(shape, "Decorative") and shape.Decorative
+        if not decorative and not (shape.Description or "").strip() and not (shape.Title or "").strip():
+            issue("missing_alt_text", "Has no alternative text for screen readers")
+        return
+
+    fill = solid_fill(shape)
+    shape_background = fill if fill is not None else background
+    if shape.supportsService("com.sun.star.drawing.TableShape"):
+        table = shape.Model
+        for row in range(table.getRows().getCount()):
+            for column in range(table.getColumns().getCount()):
+                cell = table.getCellByPosition(column, row)
+                cell_fill = solid_fill(cell)
+                check_text(cell, cell_fill if cell_fill is not None else shape_background, min_font_size, issue)
+        return
+    if shape.supportsService("com.sun.star.drawing.Text") and shape.getString().strip():
+        check_text(shape.getText(), shape_background, min_font_size, issue)
+
+
+def has_title(slide):
+    """Whether a slide has a title placeholder with text"""
+    for index in range(slide.getCount()):
+        shape = slide.getByIndex(index)
+        if shape.getShapeType() == "com.sun.star.presentation.TitleTextShape" and shape.getString().strip():
+            return True
+    return False
+
+
+def check_accessibility(pptx_path, slide_numbers, min_font_size):
+    """Report accessibility issues of the given slides, or of every slide when none are given"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path, read_only=True)
+
+        if not slide_numbers:
+            slide_numbers = list(range(1, doc.getDrawPages().getCount() + 1))
+
+        slides = []
+        summary = {}
+        for slide_number in slide_numbers:
+            slide = get_slide(doc, slide_number)
+            background = slide_background(slide)
+            issues = []
+
+            def add_issue(kind, message, shape_index=None, shape_id=None, **details):
+                entry = {"type": kind, "message": message}
+                if shape_index is not None:
+                    entry["shape_index"] = shape_index
+                    entry["shape_id"] = shape_id
+                entry.update(details)
+                issues.append(entry)
+                summary[kind] = summary.get(kind, 0) + 1
+
+            if not has_title(slide):
+                add_issue("missing_title", "Has no title; screen reader users navigate slides by their titles")
+
+            shapes = [slide.getByIndex(index) for index in range(slide.getCount())]
+            ids = shape_ids([shape.Name for shape in shapes])
+            for index, shape in enumerate(shapes):
+                check_shape(shape, background, min_font_size,
+                            lambda kind, message, index=index, **details: add_issue(kind, message, index, ids[index], **details))
+
+            if issues:
+                slides.append({"slide_number": slide_number, "issues": issues})
+
+        doc.close(True)
+
+        issue_count = sum(summary.values())
+        return {
+            "success": True,
+            "checked_slides": len(slide_numbers),
+            "issue_count": issue_count,
+            "summary": summary,
+            "slides": slides,
+            "message": f"Found {issue_count} accessibility issue(s) on {len(slides)} of {len(slide_numbers)} slide(s)"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error checking accessibility: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 4:
+        print("Usage: python3 uno_check_accessibility.py <pptx_path> <slide_numbers_json> <min_font_size>")
+        print("Pass [] to check every slide")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        slide_numbers = json.loads(sys.argv[2])
+        min_font_size = float(sys.argv[3])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide numbers must be valid JSON and the minimum font size a number"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = check_accessibility(pptx_path, slide_numbers, min_font_size)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -0,0 +1,137 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, get_slide, shape_ids, units_to_inches
+from text_fit import fit_mode, needed_size
+
+# Overflow smaller than this (about 0.02 in) is rounding, not text past the edge
+TOLERANCE = 50
+
+# Placeholders LibreOffice fills in itself
+SKIPPED_TYPES = (
+    "com.sun.star.presentation.FooterShape",
+    "com.sun.star.presentation.SlideNumberShape",
+    "com.sun.star.presentation.DateTimeShape",
+)
+
+
+def excerpt(text, width=60):
+    text = " ".join(text.split())
+    return text[:width] + "…" if len(text) > width else text
+
+
+def check_shape(slide, shape, index, shape_id):
+    """Return the overflow of a text shape, or None when its text fits"""
+    if shape.getShapeType() in SKIPPED_TYPES:
+        return None
+    if not shape.supportsService("com.sun.star.drawing.Text") or not shape.getString().strip():
+        return None
+    # Shrink-to-fit frames scale their text down instead of overflowing
+    mode = fit_mode(shape)
+    if mode == "shrink":
+        return None
+
+    position = shape.getPosition()
+    size = shape.getSize()
+    width, height = needed_size(shape)
+    overflow_x = width - size.Width
+    overflow_y = height - size.Height
+    off_slide = position.Y + height > slide.Height + TOLERANCE or position.X + width > slide.Width + TOLERANCE
+    if overflow_x <= TOLERANCE and overflow_y <= TOLERANCE and not off_slide:
+        return None
+
+    issue = {
+        "shape_index": index,
+        "shape_id": shape_id,
+        "text": excerpt(shape.getString()),
+        "autofit": mode,
+        "frame_width": units_to_inches(size.Width),
+        "frame_height": units_to_inches(size.Height),
+        "text_width": units_to_inches(width),
+        "text_height": units_to_inches(height),
+        "overflow": units_to_inches(max(overflow_x, overflow_y, 0)),
+        "off_slide": off_slide,
+    }
+    if mode == "resize":
+        issue["message"] = "The frame grows with its text and runs past the edge of the slide"
+    elif overflow_y > TOLERANCE:
+        issue["message"] = f"Text needs {units_to_inches(height)} in but the frame is {units_to_inches(size.Height)} in tall"
+    elif overflow_x > TOLERANCE:
+        issue["message"] = f"Unwrapped text needs {units_to_inches(width)} in but the frame is {units_to_inches(size.Width)} in wide"
+    else:
+        issue["message"] = "The text fits its frame but the frame runs past the edge of the slide"
+    return issue
+
+
+def check_text_overflow(pptx_path, slide_numbers):
+    """Report text that doesn't fit its shape on the given slides, or on every slide"""
+    try:
+        context, desktop = connect()
+        # Frames are grown to measure their text, so the file must never be stored
+        doc = load_presentation(desktop, pptx_path, read_only=True)
+
+        if not slide_numbers:
+            slide_numbers = list(range(1, doc.getDrawPages().getCount() + 1))
+
+        slides = []
+        overflow_count = 0
+        for slide_number in slide_numbers:
+            slide = get_slide(doc, slide_number)
+            shapes = [slide.getByIndex(index) for index in range(slide.getCount())]
+            ids = shape_ids([shape.Name for shape in shapes])
+
+            issues = []
+            for index, shape in enumerate(shapes):
+                issue = check_shape(slide, shape, index, ids[index])
+                if issue:
+                    issues.append(issue)
+            if issues:
+                slides.append({"slide_number": slide_number, "shapes": issues})
+                overflow_count += len(issues)
+
+        doc.close(True)
+
+        return {
+            "success": True,
+            "checked_slides": len(slide_numbers),
+            "overflow_count": overflow_count,
+            "slides": slides,
+            "message": f"Found {overflow_count} shape(s) with overflowing text on {len(slides)} of {len(slide_numbers)} slide(s)"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error checking text overflow: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 3:
+        print("Usage: python3 uno_check_text_overflow.py <pptx_path> <slide_numbers_json>")
+        print("Pass [] to check every slide")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        slide_numbers = json.loads(sys.argv[2])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide numbers must be a JSON list"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = check_text_overflow(pptx_path, slide_numbers)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -0,0 +1,119 @@
+#!/usr/bin/env python3
+"""
+Shared LibreOffice connection helpers for UNO scripts.
+
+This module provides centralized logic for:
+- Connecting to the running headless LibreOffice instance
+- Loading presentations with consistent properties
+- Common unit conversions between inches and LibreOffice units
+- Naming new shapes so they can be found again by shape_id
+
+Used by the newer UNO scripts so each one doesn't repeat the resolver setup.
+"""
+
+import os
+import uno
+from com.sun.star.beans import PropertyValue
+
+# LibreOffice uses 1/100mm units, so 1 inch = 2540 units
+UNITS_PER_INCH = 2540
+
+# The app exports the port of the LibreOffice it started; 8100 when run standalone
+UNO_PORT = int(os.environ.get("SLIDEPILOT_UNO_PORT", "8100"))
+UNO_URL = f"uno:socket,host=localhost,port={UNO_PORT};urp;StarOffice.ComponentContext"
+
+
+# Connection reused across requests when scripts run inside uno_worker.py
+_cached_connection = None
+
+
+def connect():
+    """Connect to LibreOffice and return (context, desktop).
+
+    The connection is cached for the life of the process; a stale one (LibreOffice
+    restarted) is detected and replaced.
+    """
+    global _cached_connection
+    if _cached_connection is not None:
+        try:
+            _cached_connection[1].getComponents()
+            return _cached_connection
+        except Exception:
+            _cached_connection = None
+
+    local_context = uno.getComponentContext()
+    resolver = local_context.ServiceManager.createInstanceWithContext(
+        "com.sun.star.bridge.UnoUrlResolver", local_context)
+
+    # Connect to the running LibreOffice instance
+    context = resolver.resolve(UNO_URL)
+    desktop = context.ServiceManager.createInstanceWithContext(
+        "com.sun.star.frame.Desktop", context)
+
+    _cached_connection = (context, desktop)
+    return _cached_connection
+
+
+def load_presentation(desktop, pptx_path, read_only=False):
+    """Load a presentation hidden, optionally read-only."""
+    file_url = uno.systemPathToFileUrl(os.path.abspath(pptx_path))
+
+    props = (
+        PropertyValue("Hidden", 0, True, 0),
+        PropertyValue("ReadOnly", 0, read_only, 0),
+    )
+
+    return desktop.loadComponentFromURL(file_url, "_blank", 0, props)
+
+
+def get_slide(doc, slide_number):
+    """Return the slide for a 1-based slide number, validating the range."""
+    slides = doc.getDrawPages()
+    slide_count = slides.getCount()
+
+    slide_index = slide_number - 1
+    if slide_index < 0 or slide_index >= slide_count:
+        raise ValueError(f"Slide number {slide_number} out of range (1-{slide_count})")
+
+    return slides.getByIndex(slide_index)
+
+
+def shape_ids(names):
+    """Stable shape IDs: the name, plus " #n" when several shapes share it (mirrors shapeIDs in pptx_reader.go)"""
+    counts = {}
+    for name in names:
+        counts[name] = counts.get(name, 0) + 1
+    seen = {}
+    ids = []
+    for name in names:
+        seen[name] = seen.get(name, 0) + 1
+        if not name:
+            ids.append(f"#{seen[name]}")
+        elif counts[name] > 1:
+            ids.append(f"{name} #{seen[name]}")
+        else:
+            ids.append(name)
+    return ids
+
+
+def unique_shape_name(slide, base):
+    """Return "<base> <n>" with the lowest n no shape on the slide is named yet.
+
+    Shapes are found again by name (their shape_id), so every shape the tools create
+    gets one that is unique on its slide.
+    """
+    names = {slide.getByIndex(i).Name for i in range(slide.getCount())}
+    n = 1
+    while f"{base} {n}" in names:
+        n += 1
+    return f"{base} {n}"
+
+
+def inches_to_units(value):
+    """Convert inches to LibreOffice 1/100mm units."""
+    return int(round(float(value) * UNITS_PER_INCH))
+
+
+def units_to_inches(value):
+    """Convert LibreOffice 1/100mm units to inches."""
+    return round(value / UNITS_PER_INCH, 2)
@@ -0,0 +1,75 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import os
+import json
+from com.sun.star.beans import PropertyValue
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, UNITS_PER_INCH
+
+# AutoLayout of the first slide: a title slide when a title is given, otherwise blank
+AUTOLAYOUT_TITLE = 0
+AUTOLAYOUT_NONE = 20
+
+def create_presentation(output_path, width_in, height_in, title=None):
+    """Create a presentation with one slide of the given size and save it as .pptx"""
+    try:
+        context, desktop = connect()
+        doc = desktop.loadComponentFromURL(
+            "private:factory/simpress", "_blank", 0, (PropertyValue("Hidden", 0, True, 0),))
+        if doc is None:
+            raise Exception("LibreOffice could not create a presentation")
+
+        try:
+            # Impress shares one page size across all slides and masters
+            slide = doc.getDrawPages().getByIndex(0)
+            slide.Width = int(round(width_in * UNITS_PER_INCH))
+            slide.Height = int(round(height_in * UNITS_PER_INCH))
+
+            if title:
+                slide.Layout = AUTOLAYOUT_TITLE
+                for i in range(slide.getCount()):
+                    shape = slide.getByIndex(i)
+                    if shape.getShapeType() == "com.sun.star.presentation.TitleTextShape":
+                        shape.setString(title)
+                        break
+            else:
+                slide.Layout = AUTOLAYOUT_NONE
+
+            props = (
+                PropertyValue("FilterName", 0, "Impress MS PowerPoint 2007 XML", 0),
+                PropertyValue("Overwrite", 0, False, 0),
+            )
+            doc.storeToURL(uno.systemPathToFileUrl(os.path.abspath(output_path)), props)
+        finally:
+            doc.close(True)
+
+        return {
+            "success": True,
+            "output_path": os.path.abspath(output_path),
+            "slide_count": 1,
+            "width_inches": width_in,
+            "height_inches": height_in,
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error creating presentation: {e}")
+
+if __name__ == "__main__":
+    if len(sys.argv) < 4 or len(sys.argv) > 5:
+        print("Usage: python3 uno_create_presentation.py <output_path> <width_inches> <height_inches> [<title>]")
+        sys.exit(1)
+
+    try:
+        title = sys.argv[4] if len(sys.argv) > 4 and sys.argv[4] else None
+        result = create_presentation(sys.argv[1], float(sys.argv[2]), float(sys.argv[3]), title)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -0,0 +1,74 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, get_slide
+
+def delete_shape(pptx_path, slide_number, shape_index):
+    """Remove a shape from a slide"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        shape_count = slide.getCount()
+        if shape_index < 0 or shape_index >= shape_count:
+            raise ValueError(f"Shape index {shape_index} out of range (0-{shape_count - 1})")
+
+        shape = slide.getByIndex(shape_index)
+        shape_type = shape.getShapeType()
+        text = ""
+        if hasattr(shape, 'getString'):
+            text = shape.getString().strip()
+            text = text[:50] + "..." if len(text) > 50 else text
+
+        slide.remove(shape)
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        return {
+            "success": True,
+            "slide_number": slide_number,
+            "deleted_shape_index": shape_index,
+            "deleted_shape_type": shape_type,
+            "deleted_text": text,
+            "remaining_shapes": shape_count - 1,
+            "message": f"Deleted shape {shape_index} from slide {slide_number}; later shapes moved down one index"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error deleting shape: {e}")
+
+if __name__ == "__main__":
+    if len(sys.argv) != 4:
+        print("Usage: python3 uno_delete_shape.py <pptx_path> <slide_number> <shape_index>")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        slide_number = int(sys.argv[2])
+        shape_index = int(sys.argv[3])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number and shape index must be an integer"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = delete_shape(pptx_path, slide_number, shape_index)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
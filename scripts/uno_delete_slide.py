@@ -4,6 +4,7 @@ import sys
 import os
 import json
 from com.sun.star.connection import NoConnectException
+from uno_connection import UNO_URL
 
 def delete_slide(pptx_path, slide_number):
     """Delete a specific slide from a presentation"""
@@ -14,7 +15,7 @@ def delete_slide(pptx_path, slide_number):
             "com.sun.star.bridge.UnoUrlResolver", local_context)
         
         # Connect to the running LibreOffice instance
-        context = resolver.resolve("uno:socket,host=localhost,port=8100;urp;StarOffice.ComponentContext")
+        context = resolver.resolve(UNO_URL)
         desktop = context.ServiceManager.createInstanceWithContext(
             "com.sun.star.frame.Desktop", context)
         
@@ -0,0 +1,136 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, get_slide
+
+# Class ID of embedded chart2 objects
+CHART_CLSID = "12dcae26-281f-416f-a234-c3086127382e"
+
+
+def chart_document(slide, shape_index):
+    """Return the chart document embedded in a shape, raising if the shape isn't a chart"""
+    shape_count = slide.getCount()
+    if shape_index < 0 or shape_index >= shape_count:
+        raise ValueError(f"Shape index {shape_index} out of range (0-{shape_count - 1})")
+    shape = slide.getByIndex(shape_index)
+    if not shape.supportsService("com.sun.star.drawing.OLE2Shape") or shape.CLSID.lower() != CHART_CLSID:
+        raise ValueError(f"Shape {shape_index} is not a chart")
+    return shape.Model
+
+
+def read_chart(chart):
+    """Return the title, categories and series of a chart document"""
+    data = chart.getData()
+    rows = data.getData()
+    categories = list(data.getRowDescriptions())
+    names = list(data.getColumnDescriptions())
+    # Empty cells read as NaN, which JSON can't carry
+    series = [{"name": name, "values": [None if row[column] != row[column] else row[column] for row in rows]}
+              for column, name in enumerate(names)]
+    title = chart.getTitle().String if chart.HasMainTitle else ""
+    return {"title": title, "categories": categories, "series": series}
+
+
+def apply_changes(chart, current, changes):
+    """Write the changed title, categories, series and series names into the chart"""
+    categories = changes.get("categories") or current["categories"]
+    series = changes.get("series") or current["series"]
+    if "series" not in changes and len(categories) != len(current["categories"]):
+        raise ValueError(f"The chart has {len(current['categories'])} categories; give series too when changing how many there are")
+    for i, s in enumerate(series):
+        if len(s["values"]) != len(categories):
+            raise ValueError(f"Series {i} has {len(s['values'])} values but there are {len(categories)} categories")
+
+    names = [s.get("name") or f"Series {i + 1}" for i, s in enumerate(series)]
+    if changes.get("series_names"):
+        if len(changes["series_names"]) != len(series):
+            raise ValueError(f"The chart has {len(series)} series but {len(changes['series_names'])} names were given")
+        names = changes["series_names"]
+
+    chart.lockControllers()
+    try:
+        data = chart.getData()
+        data.setData(tuple(tuple(float("nan") if s["values"][row] is None else float(s["values"][row]) for s in series) for row in range(len(categories))))
+        data.setRowDescriptions(tuple(str(c) for c in categories))
+        data.setColumnDescriptions(tuple(str(n) for n in names))
+
+        if "title" in changes:
+            chart.HasMainTitle = bool(changes["title"])
+            if changes["title"]:
+                chart.getTitle().String = changes["title"]
+    finally:
+        chart.unlockControllers()
+
+
+def edit_chart_data(pptx_path, slide_number, shape_index, changes):
+    """Read an embedded chart's data and apply any changes given"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        chart = chart_document(slide, shape_index)
+        before = read_chart(chart)
+
+        if changes:
+            apply_changes(chart, before, changes)
+            after = read_chart(chart)
+            doc.store()
+        else:
+            after = before
+        doc.close(True)
+
+        result = {
+            "success": True,
+            "slide_number": slide_number,
+            "shape_index": shape_index,
+            "changed": bool(changes),
+            "title": after["title"],
+            "categories": after["categories"],
+            "series": after["series"],
+        }
+        if changes:
+            result["previous"] = before
+            result["message"] = f"Updated the chart data of shape {shape_index} on slide {slide_number}"
+        else:
+            result["message"] = f"Read the chart data of shape {shape_index} on slide {slide_number}"
+        return result
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error editing chart data: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 5:
+        print("Usage: python3 uno_edit_chart_data.py <pptx_path> <slide_number> <shape_index> <changes_json>")
+        print("Pass {} to only read the chart; changes may hold title, categories, series and series_names")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        slide_number = int(sys.argv[2])
+        shape_index = int(sys.argv[3])
+        changes = json.loads(sys.argv[4])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number and shape index must be integers and changes valid JSON"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = edit_chart_data(pptx_path, slide_number, shape_index, changes)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -4,10 +4,12 @@ import sys
 import os
 import json
 from com.sun.star.connection import NoConnectException
+from uno_connection import UNO_URL
 from com.sun.star.beans import PropertyValue
 from com.sun.star.text.WritingMode import LR_TB
 from com.sun.star.style.NumberingType import ARABIC
 from slide_analyzer import SlideAnalyzer
+from text_fit import apply_autofit
 
 def format_as_bullet_list(shape, bullet_text):
     """Format text shape as a proper bullet list using LibreOffice UNO API"""
@@ -54,8 +56,9 @@ def format_as_bullet_list(shape, bullet_text):
     
     return True
 
-def edit_slide_text(pptx_path, slide_number, target_type, target_value, new_text, old_text=None):
-    """Edit text content on a slide using various targeting methods"""
+def edit_slide_text(pptx_path, slide_number, target_type, target_value, new_text, old_text=None, autofit=None):
+    """Edit text content on a slide using various targeting methods; autofit sets how the
+    edited shape fits its text"""
     try:
         # Convert literal \n to actual newlines in new_text
         new_text = new_text.replace('\\n', '\n')
@@ -66,7 +69,7 @@ def edit_slide_text(pptx_path, slide_number, target_type, target_value, new_text
             "com.sun.star.bridge.UnoUrlResolver", local_context)
         
         # Connect to the running LibreOffice instance
-        context = resolver.resolve("uno:socket,host=localhost,port=8100;urp;StarOffice.ComponentContext")
+        context = resolver.resolve(UNO_URL)
         desktop = context.ServiceManager.createInstanceWithContext(
             "com.sun.star.frame.Desktop", context)
         
@@ -98,6 +101,7 @@ def edit_slide_text(pptx_path, slide_number, target_type, target_value, new_text
         # Track if we made any changes
         changes_made = False
         change_description = ""
+        edited_shape = None
         
         if target_type == "shape_index":
             # Edit specific shape by index
@@ -110,6 +114,7 @@ def edit_slide_text(pptx_path, slide_number, target_type, target_value, new_text
                 old_text_actual = shape.getString()
                 shape.setString(new_text)
                 changes_made = True
+                edited_shape = shape
                 change_description = f"Changed shape {shape_index} from '{old_text_actual}' to '{new_text}'"
             else:
                 raise ValueError(f"Shape {shape_index} does not contain editable text")
@@ -135,6 +140,7 @@ def edit_slide_text(pptx_path, slide_number, target_type, target_value, new_text
                     old_text_actual = shape.getString() if hasattr(shape, 'getString') else ""
                     shape.setString(new_text)
                     changes_made = True
+                    edited_shape = shape
                     change_description = f"Changed {detected_shape_type} (shape {i}) from '{old_text_actual}' to '{new_text}'"
                     break  # Only edit the first matching shape
             
@@ -154,6 +160,7 @@ def edit_slide_text(pptx_path, slide_number, target_type, target_value, new_text
                         new_full_text = current_text.replace(old_text, new_text)
                         shape.setString(new_full_text)
                         changes_made = True
+                        edited_shape = shape
                         change_description = f"Replaced '{old_text}' with '{new_text}' in shape {i}"
                         break  # Only replace in first matching shape
             
@@ -175,6 +182,7 @@ def edit_slide_text(pptx_path, slide_number, target_type, target_value, new_text
                             new_full_text = '\n'.join(lines)
                             shape.setString(new_full_text)
                             changes_made = True
+                            edited_shape = shape
                             change_description = f"Changed bullet point {bullet_index} to '{new_text}' in shape {i}"
                             break
             
@@ -193,12 +201,17 @@ def edit_slide_text(pptx_path, slide_number, target_type, target_value, new_text
                 # Use the bullet list formatting function
                 format_as_bullet_list(shape, new_text)
                 changes_made = True
+                edited_shape = shape
                 change_description = f"Set shape {shape_index} as bullet list: '{new_text[:50]}...'"
             else:
                 raise ValueError(f"Shape {shape_index} does not contain editable text")
         else:
             raise ValueError(f"Unknown target_type: {target_type}")
         
+        if changes_made and autofit:
+            apply_autofit(edited_shape, autofit)
+            change_description += f" (autofit: {autofit})"
+
         if changes_made:
             # Save the document
             doc.store()
@@ -222,7 +235,7 @@ def edit_slide_text(pptx_path, slide_number, target_type, target_value, new_text
 
 if __name__ == "__main__":
     if len(sys.argv) < 6:
-        print("Usage: python3 uno_edit_slide.py <pptx_path> <slide_number> <target_type> <target_value> <new_text> [old_text]")
+        print("Usage: python3 uno_edit_slide.py <pptx_path> <slide_number> <target_type> <target_value> <new_text> [old_text] [autofit]")
         print(f"target_type: {SlideAnalyzer.EDIT_TARGET_SHAPE_INDEX}, {SlideAnalyzer.EDIT_TARGET_SHAPE_TYPE}, {SlideAnalyzer.EDIT_TARGET_BULLET_POINT}, {SlideAnalyzer.EDIT_TARGET_BULLET_LIST}, {SlideAnalyzer.EDIT_TARGET_TEXT_REPLACE}")
         print("target_value: index/type/text depending on target_type")
         sys.exit(1)
@@ -232,11 +245,16 @@ if __name__ == "__main__":
     target_type = sys.argv[3]
     target_value = sys.argv[4]
     new_text = sys.argv[5]
-    old_text = sys.argv[6] if len(sys.argv) > 6 else None
+    old_text = (sys.argv[6] or None) if len(sys.argv) > 6 else None
+    autofit = (sys.argv[7] or None) if len(sys.argv) > 7 else None
     
     try:
-        result = edit_slide_text(pptx_path, slide_number, target_type, target_value, new_text, old_text)
+        result = edit_slide_text(pptx_path, slide_number, target_type, target_value, new_text, old_text, autofit)
         print(json.dumps(result, indent=2))
     except Exception as e:
-        print(f"Error: {e}")
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
         sys.exit(1)
@@ -0,0 +1,79 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, get_slide
+
+def edit_table_cell(pptx_path, slide_number, shape_index, row, column, new_text):
+    """Replace the text of one table cell (row and column are 0-based)"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        if shape_index < 0 or shape_index >= slide.getCount():
+            raise ValueError(f"Shape index {shape_index} out of range (0-{slide.getCount() - 1})")
+
+        shape = slide.getByIndex(shape_index)
+        if shape.getShapeType() != "com.sun.star.drawing.TableShape":
+            raise ValueError(f"Shape {shape_index} is not a table")
+
+        table = shape.Model
+        row_count = table.getRows().getCount()
+        column_count = table.getColumns().getCount()
+        if row < 0 or row >= row_count or column < 0 or column >= column_count:
+            raise ValueError(f"Cell position ({row}, {column}) out of range: table has {row_count} rows and {column_count} columns")
+
+        cell = table.getCellByPosition(column, row)
+        old_text = cell.getString()
+        cell.setString(new_text)
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        return {
+            "success": True,
+            "slide_number": slide_number,
+            "shape_index": shape_index,
+            "row": row,
+            "column": column,
+            "old_text": old_text,
+            "new_text": new_text,
+            "message": f"Changed cell ({row}, {column}) of table {shape_index} on slide {slide_number}"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error editing table cell: {e}")
+
+if __name__ == "__main__":
+    if len(sys.argv) != 7:
+        print("Usage: python3 uno_edit_table_cell.py <pptx_path> <slide_number> <shape_index> <row> <column> <new_text>")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+    new_text = sys.argv[6]
+
+    try:
+        slide_number, shape_index, row, column = [int(value) for value in sys.argv[2:6]]
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number, shape index, row and column must be an integer"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = edit_table_cell(pptx_path, slide_number, shape_index, row, column, new_text)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
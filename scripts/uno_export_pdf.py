@@ -0,0 +1,63 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import os
+import json
+from com.sun.star.beans import PropertyValue
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation
+
+def export_pdf(pptx_path, output_path, options):
+    """Export slides (or notes pages) of a presentation to PDF.
+
+    options: page_range ("2-5", 1-based slides), notes (notes pages instead of slides)
+    """
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path, read_only=True)
+
+        try:
+            slide_count = doc.getDrawPages().getCount()
+            filter_data = [PropertyValue("PageRange", 0, options.get("page_range", ""), 0)]
+            if options.get("notes"):
+                filter_data.append(PropertyValue("ExportNotesPages", 0, True, 0))
+                filter_data.append(PropertyValue("ExportOnlyNotesPages", 0, True, 0))
+
+            props = (
+                PropertyValue("FilterName", 0, "impress_pdf_Export", 0),
+                PropertyValue("FilterData", 0, uno.Any("[]com.sun.star.beans.PropertyValue", tuple(filter_data)), 0),
+                PropertyValue("Overwrite", 0, True, 0),
+            )
+            output_url = uno.systemPathToFileUrl(os.path.abspath(output_path))
+            uno.invoke(doc, "storeToURL", (output_url, props))
+        finally:
+            doc.close(True)
+
+        return {
+            "success": True,
+            "output_path": os.path.abspath(output_path),
+            "slide_count": slide_count,
+            "message": f"Exported {os.path.basename(pptx_path)} to {os.path.basename(output_path)}"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error exporting PDF: {e}")
+
+if __name__ == "__main__":
+    if len(sys.argv) not in (3, 4):
+        print("Usage: python3 uno_export_pdf.py <pptx_path> <output_path> [options_json]")
+        sys.exit(1)
+
+    try:
+        options = json.loads(sys.argv[3]) if len(sys.argv) == 4 and sys.argv[3] else {}
+        result = export_pdf(sys.argv[1], sys.argv[2], options)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -0,0 +1,120 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import os
+import json
+from com.sun.star.beans import PropertyValue
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, UNITS_PER_INCH
+
+# Defaults match the app's slide previews
+DEFAULT_OPTIONS = {"format": "jpeg", "dpi": 150, "quality": 90, "max_width": 0, "max_height": 0}
+
+# Media type and file extension per export format
+FORMATS = {
+    "jpeg": ("image/jpeg", "jpg"),
+    "png": ("image/png", "png"),
+    "webp": ("image/webp", "webp"),
+}
+
+def image_size(page, options):
+    """Pixel size of a page at the requested DPI, scaled down to fit the max dimensions."""
+    width = page.Width / UNITS_PER_INCH * options["dpi"]
+    height = page.Height / UNITS_PER_INCH * options["dpi"]
+
+    scale = 1.0
+    if options["max_width"]:
+        scale = min(scale, options["max_width"] / width)
+    if options["max_height"]:
+        scale = min(scale, options["max_height"] / height)
+    return max(1, int(round(width * scale))), max(1, int(round(height * scale)))
+
+def export_slides(pptx_path, output_dir, slide_numbers=None, options=None):
+    """Export the given 1-based slides (all slides when None) as images.
+
+    Each page is rendered straight from the open document with the graphic export
+    filter and written as slide-NNN.<ext>, numbered by its 0-based slide index. Slide
+    numbers past the end of the deck are ignored.
+    """
+    options = dict(DEFAULT_OPTIONS, **(options or {}))
+    if options["format"] not in FORMATS:
+        raise ValueError(f"Unsupported export format: {options['format']}")
+    media_type, extension = FORMATS[options["format"]]
+
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path, read_only=True)
+        if doc is None:
+            raise Exception("LibreOffice could not open the presentation")
+
+        try:
+            slides = doc.getDrawPages()
+            slide_count = slides.getCount()
+
+            if slide_numbers is None:
+                slide_numbers = range(1, slide_count + 1)
+            for number in slide_numbers:
+                if number < 1:
+                    raise ValueError(f"Slide number {number} out of range (1-{slide_count})")
+
+            exporter = context.ServiceManager.createInstanceWithContext(
+                "com.sun.star.drawing.GraphicExportFilter", context)
+
+            exported = []
+            for number in slide_numbers:
+                if number > slide_count:
+                    continue
+                page = slides.getByIndex(number - 1)
+                width, height = image_size(page, options)
+
+                filter_data = uno.Any("[]com.sun.star.beans.PropertyValue", (
+                    PropertyValue("PixelWidth", 0, width, 0),
+                    PropertyValue("PixelHeight", 0, height, 0),
+                    PropertyValue("Quality", 0, options["quality"], 0),
+                ))
+                image_path = os.path.join(os.path.abspath(output_dir), f"slide-{number - 1:03d}.{extension}")
+                props = (
+                    PropertyValue("URL", 0, uno.systemPathToFileUrl(image_path), 0),
+                    PropertyValue("MediaType", 0, media_type, 0),
+                    PropertyValue("FilterData", 0, filter_data, 0),
+                )
+
+                exporter.setSourceDocument(page)
+                uno.invoke(exporter, "filter", (props,))
+                exported.append(image_path)
+        finally:
+            doc.close(True)
+
+        return {
+            "success": True,
+            "total_slides": slide_count,
+            "exported": exported
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error exporting slides: {e}")
+
+if __name__ == "__main__":
+    if len(sys.argv) < 3 or len(sys.argv) > 5:
+        print("Usage: python3 uno_export_slides.py <pptx_path> <output_dir> [<slide_numbers> [<options_json>]]")
+        print("  slide_numbers: comma-separated 1-based slide numbers, e.g. 2,3,7; empty for all slides")
+        print('  options_json: e.g. {"format": "png", "dpi": 300, "quality": 90, "max_width": 0, "max_height": 0}')
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+    output_dir = sys.argv[2]
+
+    try:
+        slide_numbers = [int(n) for n in sys.argv[3].split(",")] if len(sys.argv) > 3 and sys.argv[3] else None
+        options = json.loads(sys.argv[4]) if len(sys.argv) > 4 and sys.argv[4] else None
+        result = export_slides(pptx_path, output_dir, slide_numbers, options)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
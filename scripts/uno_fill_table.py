@@ -0,0 +1,92 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, get_slide
+from uno_insert_table import resize_collection
+
+
+def fill_table(pptx_path, slide_number, shape_index, data):
+    """Resize an existing table to the rows of data and replace every cell's text"""
+    try:
+        rows = len(data)
+        columns = max((len(values) for values in data), default=0)
+        if rows == 0 or columns == 0:
+            raise ValueError("No data to fill the table with")
+
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        if shape_index < 0 or shape_index >= slide.getCount():
+            raise ValueError(f"Shape index {shape_index} out of range (0-{slide.getCount() - 1})")
+
+        shape = slide.getByIndex(shape_index)
+        if shape.getShapeType() != "com.sun.star.drawing.TableShape":
+            raise ValueError(f"Shape {shape_index} is not a table")
+
+        table = shape.Model
+        old_rows = table.getRows().getCount()
+        old_columns = table.getColumns().getCount()
+        resize_collection(table.getRows(), rows)
+        resize_collection(table.getColumns(), columns)
+
+        for row in range(rows):
+            for col in range(columns):
+                values = data[row]
+                value = values[col] if col < len(values) and values[col] is not None else ""
+                table.getCellByPosition(col, row).setString(str(value))
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        return {
+            "success": True,
+            "slide_number": slide_number,
+            "shape_index": shape_index,
+            "shape_id": shape.Name,
+            "rows": rows,
+            "columns": columns,
+            "previous_rows": old_rows,
+            "previous_columns": old_columns,
+            "message": f"Filled table {shape_index} on slide {slide_number} with {rows}x{columns} cells"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error filling table: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 5:
+        print("Usage: python3 uno_fill_table.py <pptx_path> <slide_number> <shape_index> <data_json>")
+        print("data_json is a list of rows, each a list of cell texts")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        slide_number = int(sys.argv[2])
+        shape_index = int(sys.argv[3])
+        data = json.loads(sys.argv[4])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number and shape index must be an integer and data valid JSON"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = fill_table(pptx_path, slide_number, shape_index, data)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -0,0 +1,179 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import re
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation
+
+NOTES_SHAPE_TYPE = "com.sun.star.presentation.NotesShape"
+
+# Before/after excerpts in the report are shortened to this many characters
+MAX_EXCERPT = 120
+
+def excerpt(text):
+    text = text.replace("\n", " ")
+    return text[:MAX_EXCERPT] + "..." if len(text) > MAX_EXCERPT else text
+
+def replace_in_text(text_object, pattern, substitute, dry_run):
+    """Replace matches in a text object paragraph by paragraph and return the number of
+    replacements. Text portions are replaced individually so their character formatting
+    survives; a paragraph is only rewritten as a whole when a match spans portions."""
+    count = 0
+    paragraphs = text_object.getText().createEnumeration()
+    while paragraphs.hasMoreElements():
+        paragraph = paragraphs.nextElement()
+        if not hasattr(paragraph, 'createEnumeration'):
+            continue  # Tables nested in text have no portions to search
+
+        original = paragraph.getString()
+        matches = len(pattern.findall(original))
+        if matches == 0:
+            continue
+        count += matches
+        if dry_run:
+            continue
+
+        replaced = 0
+        portions = paragraph.createEnumeration()
+        while portions.hasMoreElements():
+            portion = portions.nextElement()
+            portion_text = portion.getString()
+            new_text, n = pattern.subn(substitute, portion_text)
+            if n:
+                portion.setString(new_text)
+                replaced += n
+
+        if replaced != matches:
+            paragraph.setString(pattern.sub(substitute, original))
+
+    return count
+
+def record(changes, location, text_object, pattern, substitute, dry_run):
+    """Replace in one text object and add it to the change list if anything matched"""
+    before = text_object.getString()
+    count = replace_in_text(text_object, pattern, substitute, dry_run)
+    if count:
+        after = pattern.sub(substitute, before) if dry_run else text_object.getString()
+        changes.append({
+            "location": location,
+            "replacements": count,
+            "before": excerpt(before),
+            "after": excerpt(after)
+        })
+    return count
+
+def walk_shapes(container, location_prefix, changes, pattern, substitute, dry_run):
+    """Search and replace in shapes, descending into groups and tables"""
+    total = 0
+    for i in range(container.getCount()):
+        shape = container.getByIndex(i)
+        location = f"{location_prefix}{i}"
+        shape_type = shape.getShapeType() if hasattr(shape, 'getShapeType') else ""
+
+        if shape_type == "com.sun.star.drawing.GroupShape":
+            total += walk_shapes(shape, f"{location}.", changes, pattern, substitute, dry_run)
+            continue
+
+        if shape_type == "com.sun.star.drawing.TableShape":
+            table = shape.Model
+            for row in range(table.getRows().getCount()):
+                for col in range(table.getColumns().getCount()):
+                    cell = table.getCellByPosition(col, row)
+                    total += record(changes, f"shape {location} cell ({row}, {col})", cell, pattern, substitute, dry_run)
+            continue
+
+        if hasattr(shape, 'getText') and hasattr(shape, 'getString'):
+            total += record(changes, f"shape {location}", shape, pattern, substitute, dry_run)
+    return total
+
+def find_replace(pptx_path, find, replace, use_regex=False, match_case=True, include_notes=False, dry_run=False):
+    """Replace every occurrence of find across all slides, optionally including notes"""
+    try:
+        flags = 0 if match_case else re.IGNORECASE
+        try:
+            pattern = re.compile(find if use_regex else re.escape(find), flags)
+        except re.error as e:
+            raise ValueError(f"Invalid regular expression: {e}")
+
+        # Literal replacements must not interpret backslashes or group references
+        substitute = replace if use_regex else (lambda match: replace)
+
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path, read_only=dry_run)
+
+        slides = doc.getDrawPages()
+        report = []
+        total = 0
+
+        for i in range(slides.getCount()):
+            slide = slides.getByIndex(i)
+            slide_number = i + 1
+            changes = []
+
+            count = walk_shapes(slide, "", changes, pattern, substitute, dry_run)
+            if include_notes:
+                notes_page = slide.getNotesPage()
+                for j in range(notes_page.getCount()):
+                    shape = notes_page.getByIndex(j)
+                    if shape.getShapeType() == NOTES_SHAPE_TYPE:
+                        count += record(changes, "notes", shape, pattern, substitute, dry_run)
+
+            if count:
+                total += count
+                report.append({
+                    "slide_number": slide_number,
+                    "replacements": count,
+                    "changes": changes
+                })
+
+        if total and not dry_run:
+            doc.store()
+        doc.close(True)
+
+        verb = "Would replace" if dry_run else "Replaced"
+        return {
+            "success": True,
+            "dry_run": dry_run,
+            "total_replacements": total,
+            "slides_changed": len(report),
+            "slides": report,
+            "message": f"{verb} {total} occurrence(s) on {len(report)} slide(s)"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error replacing text: {e}")
+
+def parse_bool(value, default):
+    if value in (None, ""):
+        return default
+    return value.lower() == "true"
+
+if __name__ == "__main__":
+    if len(sys.argv) < 4:
+        print("Usage: python3 uno_find_replace.py <pptx_path> <find> <replace> [regex] [match_case] [include_notes] [dry_run]")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+    find = sys.argv[2]
+    replace = sys.argv[3]
+
+    def arg(i):
+        return sys.argv[i] if len(sys.argv) > i else ""
+
+    try:
+        result = find_replace(pptx_path, find, replace,
+                              use_regex=parse_bool(arg(4), False),
+                              match_case=parse_bool(arg(5), True),
+                              include_notes=parse_bool(arg(6), False),
+                              dry_run=parse_bool(arg(7), False))
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
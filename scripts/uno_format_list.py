@@ -0,0 +1,136 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from com.sun.star.style.NumberingType import (CHAR_SPECIAL, ARABIC, CHARS_LOWER_LETTER,
+                                              CHARS_UPPER_LETTER, ROMAN_LOWER, ROMAN_UPPER, NUMBER_NONE)
+from uno_connection import connect, load_presentation, get_slide
+
+# Numbering tokens of the number_format strings ("1.", "(a)", "I)", ...)
+NUMBERING_TYPES = {
+    "1": ARABIC,
+    "a": CHARS_LOWER_LETTER,
+    "A": CHARS_UPPER_LETTER,
+    "i": ROMAN_LOWER,
+    "I": ROMAN_UPPER,
+}
+
+# Indent per list level, in 1/100 mm (0.25")
+LEVEL_INDENT = 635
+
+
+def level_rule(rules, item, start_at):
+    """Return the numbering rule properties of an item's level, restyled for the item"""
+    props = {prop.Name: prop.Value for prop in rules.getByIndex(item["level"])}
+
+    style = item["style"]
+    if style == "number":
+        number_format = item["number_format"]
+        token = next(i for i, c in enumerate(number_format) if c in NUMBERING_TYPES)
+        props["NumberingType"] = NUMBERING_TYPES[number_format[token]]
+        props["Prefix"] = number_format[:token]
+        props["Suffix"] = number_format[token + 1:]
+        props["StartWith"] = start_at if item["level"] == 0 else 1
+    elif style == "bullet":
+        props["NumberingType"] = CHAR_SPECIAL
+        props["BulletChar"] = item["bullet"]
+        props["Prefix"] = ""
+        props["Suffix"] = ""
+    else:
+        props["NumberingType"] = NUMBER_NONE
+
+    props["LeftMargin"] = LEVEL_INDENT * (item["level"] + 1)
+    props["FirstLineOffset"] = -LEVEL_INDENT if style != "none" else 0
+
+    values = []
+    for name, value in props.items():
+        prop = uno.createUnoStruct("com.sun.star.beans.PropertyValue")
+        prop.Name = name
+        prop.Value = value
+        values.append(prop)
+    return tuple(values)
+
+
+def format_list(pptx_path, slide_number, shape_index, items, start_at):
+    """Replace a text shape's paragraphs with a list of leveled bullets or numbers"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        shape_count = slide.getCount()
+        if shape_index < 0 or shape_index >= shape_count:
+            raise ValueError(f"Shape index {shape_index} out of range (0-{shape_count - 1})")
+        shape = slide.getByIndex(shape_index)
+        if not hasattr(shape, "getText"):
+            raise ValueError(f"Shape {shape_index} does not contain editable text")
+
+        shape.setString("\n".join(item["text"] for item in items))
+
+        paragraphs = shape.getText().createEnumeration()
+        for index, item in enumerate(items):
+            if not paragraphs.hasMoreElements():
+                break
+            paragraph = paragraphs.nextElement()
+
+            rules = paragraph.getPropertyValue("NumberingRules")
+            uno.invoke(rules, "replaceByIndex", (item["level"], uno.Any(
+                "[]com.sun.star.beans.PropertyValue", level_rule(rules, item, start_at))))
+            paragraph.setPropertyValue("NumberingRules", rules)
+            paragraph.setPropertyValue("NumberingLevel", item["level"])
+            paragraph.setPropertyValue("NumberingIsNumber", item["style"] != "none")
+            if index == 0 and item["style"] == "number":
+                paragraph.setPropertyValue("ParaIsNumberingRestart", True)
+                paragraph.setPropertyValue("NumberingStartValue", start_at)
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        levels = sorted({item["level"] for item in items})
+        return {
+            "success": True,
+            "slide_number": slide_number,
+            "shape_index": shape_index,
+            "paragraphs": len(items),
+            "levels": levels,
+            "message": f"Formatted shape {shape_index} on slide {slide_number} as a {len(items)}-item list"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error formatting list: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 6:
+        print("Usage: python3 uno_format_list.py <pptx_path> <slide_number> <shape_index> <items_json> <start_at>")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        slide_number = int(sys.argv[2])
+        shape_index = int(sys.argv[3])
+        items = json.loads(sys.argv[4])
+        start_at = int(sys.argv[5])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number, shape index and start value must be integers and items valid JSON"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = format_list(pptx_path, slide_number, shape_index, items, start_at)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -0,0 +1,235 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import os
+import json
+from com.sun.star.connection import NoConnectException
+from com.sun.star.beans import PropertyValue
+from com.sun.star.awt import Point, Size
+from uno_connection import connect, load_presentation, unique_shape_name
+from slide_layouts import apply_layout
+from uno_insert_image import get_graphic_size
+
+# Margin kept free around generated content, as a fraction of the slide size
+MARGIN_RATIO = 0.06
+# Space between pictures laid out side by side, in 1/100mm (0.2")
+PICTURE_GAP = 508
+
+TITLE_SHAPE = "com.sun.star.presentation.TitleTextShape"
+BODY_SHAPES = ("com.sun.star.presentation.OutlinerShape", "com.sun.star.presentation.SubtitleShape")
+
+
+def find_placeholder(slide, shape_types):
+    """Return the first shape of one of the given types on the slide, or None"""
+    for i in range(slide.getCount()):
+        shape = slide.getByIndex(i)
+        if shape.getShapeType() in shape_types:
+            return shape
+    return None
+
+
+def add_text_box(doc, slide, name, x, y, width, height):
+    """Add an empty text box, for slides whose layout has no placeholder to fill"""
+    shape = doc.createInstance("com.sun.star.drawing.TextShape")
+    slide.add(shape)
+    shape.Name = unique_shape_name(slide, name)
+    shape.setPosition(Point(x, y))
+    shape.setSize(Size(width, height))
+    return shape
+
+
+def fill_title(doc, slide, title):
+    """Put the title into the title placeholder, or a text box at the top"""
+    shape = find_placeholder(slide, (TITLE_SHAPE,))
+    if shape is None:
+        margin_x, margin_y = int(slide.Width * MARGIN_RATIO), int(slide.Height * MARGIN_RATIO)
+        shape = add_text_box(doc, slide, "Title", margin_x, margin_y, slide.Width - 2 * margin_x, int(slide.Height * 0.15))
+        shape.setString(title)
+        cursor = shape.createTextCursor()
+        cursor.gotoStart(False)
+        cursor.gotoEnd(True)
+        cursor.CharHeight = 32.0
+    else:
+        shape.setString(title)
+    return shape
+
+
+def body_area(slide, title_shape):
+    """Return (x, y, width, height) of the space below the title"""
+    margin_x, margin_y = int(slide.Width * MARGIN_RATIO), int(slide.Height * MARGIN_RATIO)
+    top = margin_y
+    if title_shape is not None:
+        top = title_shape.getPosition().Y + title_shape.getSize().Height + margin_y // 2
+    return margin_x, top, slide.Width - 2 * margin_x, max(slide.Height - margin_y - top, margin_y)
+
+
+def fill_bullets(doc, slide, bullets, area):
+    """Put the bullets into the body placeholder, or a text box filling area, one
+    paragraph per bullet at its list level"""
+    shape = find_placeholder(slide, BODY_SHAPES)
+    if shape is None:
+        shape = add_text_box(doc, slide, "Content", *area)
+    else:
+        shape.setPosition(Point(area[0], area[1]))
+        shape.setSize(Size(area[2], area[3]))
+
+    shape.setString("\n".join(bullet["text"] for bullet in bullets))
+    paragraphs = shape.getText().createEnumeration()
+    for bullet in bullets:
+        if not paragraphs.hasMoreElements():
+            break
+        paragraph = paragraphs.nextElement()
+        try:
+            paragraph.setPropertyValue("NumberingLevel", bullet["level"])
+        except Exception:
+            pass  # Text boxes without list styles keep their paragraphs flat
+    return shape
+
+
+def add_pictures(context, doc, slide, images, area):
+    """Lay the images out side by side within area, each fit into its share of it"""
+    provider = context.ServiceManager.createInstanceWithContext(
+        "com.sun.star.graphic.GraphicProvider", context)
+    x, y, width, height = area
+    cell_width = (width - PICTURE_GAP * (len(images) - 1)) // len(images)
+
+    names = []
+    for index, image_path in enumerate(images):
+        image_url = uno.systemPathToFileUrl(os.path.abspath(image_path))
+        natural_size = get_graphic_size(context, image_url) or (cell_width, height)
+        scale = min(cell_width / natural_size[0], height / natural_size[1])
+        picture_width, picture_height = int(natural_size[0] * scale), int(natural_size[1] * scale)
+
+        shape = doc.createInstance("com.sun.star.drawing.GraphicObjectShape")
+        slide.add(shape)
+        shape.Name = unique_shape_name(slide, "Picture")
+        shape.Graphic = provider.queryGraphic((PropertyValue("URL", 0, image_url, 0),))
+        cell_x = x + index * (cell_width + PICTURE_GAP)
+        shape.setPosition(Point(cell_x + (cell_width - picture_width) // 2, y + (height - picture_height) // 2))
+        shape.setSize(Size(picture_width, picture_height))
+        names.append(shape.Name)
+    return names
+
+
+def move_to_front(context, doc, slide):
+    """Make a slide the first one; the draw page API can only insert after a slide"""
+    controller = doc.getCurrentController()
+    controller.setCurrentPage(slide)
+    dispatcher = context.ServiceManager.createInstanceWithContext(
+        "com.sun.star.frame.DispatchHelper", context)
+    dispatcher.executeDispatch(controller.getFrame(), ".uno:MovePageFirst", "", 0, ())
+
+
+def generate_slides(pptx_path, slides, position=None):
+    """Insert slides built from an outline, saving only if all of them were added"""
+    try:
+        for spec in slides:
+            for image_path in spec.get("images") or []:
+                if not os.path.exists(image_path):
+                    raise ValueError(f"Image file not found: {image_path}")
+
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        try:
+            pages = doc.getDrawPages()
+            if position is None or position > pages.getCount():
+                index = pages.getCount()
+            else:
+                index = max(0, position - 1)
+            first_index = index
+
+            created = []
+            for number, spec in enumerate(slides, 1):
+                try:
+                    # insertNewByIndex adds the slide after the one at the given index
+                    slide = pages.insertNewByIndex(max(index - 1, 0))
+                    if index == 0:
+                        move_to_front(context, doc, slide)
+                    apply_layout(doc, slide, spec["layout"])
+
+                    title_shape = fill_title(doc, slide, spec["title"]) if spec.get("title") else None
+                    if title_shape is None:
+                        # Drop an empty title placeholder rather than leave a prompt on the slide
+                        placeholder = find_placeholder(slide, (TITLE_SHAPE,))
+                        if placeholder is not None:
+                            slide.remove(placeholder)
+
+                    area = body_area(slide, title_shape)
+                    bullets = spec.get("bullets") or []
+                    images = spec.get("images") or []
+                    if bullets and images:
+                        # Text on the left, pictures on the right
+                        half = (area[2] - PICTURE_GAP) // 2
+                        fill_bullets(doc, slide, bullets, (area[0], area[1], half, area[3]))
+                        add_pictures(context, doc, slide, images, (area[0] + half + PICTURE_GAP, area[1], half, area[3]))
+                    elif bullets:
+                        fill_bullets(doc, slide, bullets, area)
+                    elif images:
+                        add_pictures(context, doc, slide, images, area)
+
+                    if not bullets:
+                        placeholder = find_placeholder(slide, BODY_SHAPES)
+                        if placeholder is not None:
+                            slide.remove(placeholder)
+                except Exception as e:
+                    raise ValueError(f"Slide {number} ({spec.get('title') or 'untitled'}): {e}")
+
+                created.append({
+                    "slide_number": index + 1,
+                    "title": spec.get("title", ""),
+                    "bullets": len(spec.get("bullets") or []),
+                    "images": len(spec.get("images") or []),
+                })
+                index += 1
+
+            doc.store()
+            total_slides = pages.getCount()
+        finally:
+            # Closing without storing discards the slides of a failed outline
+            doc.close(True)
+
+        return {
+            "success": True,
+            "first_slide": first_index + 1,
+            "slides_added": len(created),
+            "total_slides": total_slides,
+            "slides": created,
+            "message": f"Added {len(created)} slide(s) from the outline as slides {first_index + 1}-{first_index + len(created)}"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error generating slides, none were added: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) < 3 or len(sys.argv) > 4:
+        print("Usage: python3 uno_generate_slides.py <pptx_path> <slides_json> [position]")
+        print("Each slide has a layout ({name, type}), an optional title, bullets ({text, level}) and images (paths)")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        slides = json.loads(sys.argv[2])
+        position = int(sys.argv[3]) if len(sys.argv) > 3 and sys.argv[3] else None
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slides must be valid JSON and position an integer"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = generate_slides(pptx_path, slides, position)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -0,0 +1,122 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, get_slide, shape_ids, unique_shape_name
+
+
+def shape_at(slide, shape_index):
+    if shape_index < 0 or shape_index >= slide.getCount():
+        raise ValueError(f"Shape index {shape_index} out of range (0-{slide.getCount() - 1})")
+    return slide.getByIndex(shape_index)
+
+
+def index_of(slide, shape):
+    """Return the current index of a shape on the slide"""
+    for index in range(slide.getCount()):
+        if slide.getByIndex(index) == shape:
+            return index
+    return -1
+
+
+def group(context, slide, slide_number, shape_indexes, name):
+    """Group the shapes at the given indexes into a new named group shape"""
+    shapes = [shape_at(slide, index) for index in shape_indexes]
+
+    collection = context.ServiceManager.createInstanceWithContext("com.sun.star.drawing.ShapeCollection", context)
+    for shape in shapes:
+        collection.add(shape)
+    group_shape = slide.group(collection)
+    group_shape.Name = name or unique_shape_name(slide, "Group")
+
+    index = index_of(slide, group_shape)
+    ids = shape_ids([slide.getByIndex(i).Name for i in range(slide.getCount())])
+    return {
+        "success": True,
+        "slide_number": slide_number,
+        "shape_index": index,
+        "shape_id": ids[index],
+        "grouped_shapes": len(shapes),
+        "message": f"Grouped {len(shapes)} shapes on slide {slide_number} as '{group_shape.Name}'; other shapes may have new indexes"
+    }
+
+
+def ungroup(slide, slide_number, shape_index):
+    """Break the group at the given index into its shapes"""
+    group_shape = shape_at(slide, shape_index)
+    if not group_shape.supportsService("com.sun.star.drawing.GroupShape"):
+        raise ValueError(f"Shape {shape_index} ('{group_shape.Name}') is not a group")
+    children = [group_shape.getByIndex(i) for i in range(group_shape.getCount())]
+    name = group_shape.Name
+
+    slide.ungroup(group_shape)
+
+    ids = shape_ids([slide.getByIndex(i).Name for i in range(slide.getCount())])
+    freed = []
+    for child in children:
+        index = index_of(slide, child)
+        if index >= 0:
+            freed.append({"shape_index": index, "shape_id": ids[index]})
+    return {
+        "success": True,
+        "slide_number": slide_number,
+        "shapes": freed,
+        "message": f"Ungrouped '{name}' on slide {slide_number} into {len(freed)} shapes"
+    }
+
+
+def group_shapes(pptx_path, slide_number, action, shape_indexes, name=None):
+    """Group shapes of a slide, or ungroup a group shape"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        if action == "group":
+            result = group(context, slide, slide_number, shape_indexes, name)
+        else:
+            result = ungroup(slide, slide_number, shape_indexes[0])
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        return result
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error {'grouping' if action == 'group' else 'ungrouping'} shapes: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) not in (5, 6) or sys.argv[3] not in ("group", "ungroup"):
+        print("Usage: python3 uno_group_shapes.py <pptx_path> <slide_number> group|ungroup <shape_indexes_json> [group_name]")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+    action = sys.argv[3]
+    name = sys.argv[5] if len(sys.argv) > 5 else None
+
+    try:
+        slide_number = int(sys.argv[2])
+        shape_indexes = json.loads(sys.argv[4])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number must be an integer and shape indexes a JSON list"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = group_shapes(pptx_path, slide_number, action, shape_indexes, name)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
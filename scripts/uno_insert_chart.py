@@ -0,0 +1,145 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from com.sun.star.awt import Point, Size
+from uno_connection import connect, load_presentation, get_slide, inches_to_units, units_to_inches, unique_shape_name
+
+# Class ID of embedded chart2 objects
+CHART_CLSID = "12dcae26-281f-416f-a234-c3086127382e"
+
+# Chart types offered to the agent: (diagram service, horizontal bars)
+CHART_TYPES = {
+    "bar": ("com.sun.star.chart.BarDiagram", True),
+    "column": ("com.sun.star.chart.BarDiagram", False),
+    "line": ("com.sun.star.chart.LineDiagram", False),
+    "area": ("com.sun.star.chart.AreaDiagram", False),
+    "pie": ("com.sun.star.chart.PieDiagram", False),
+}
+
+# Default chart footprint: 70% of the slide width, 60% of its height
+DEFAULT_WIDTH_RATIO = 0.7
+DEFAULT_HEIGHT_RATIO = 0.6
+
+
+def fill_chart(chart, chart_type, chart_data):
+    """Set the diagram type, data, title and legend of an embedded chart document"""
+    service, horizontal = CHART_TYPES[chart_type]
+    diagram = chart.createInstance(service)
+    chart.setDiagram(diagram)
+    if service == "com.sun.star.chart.BarDiagram":
+        diagram.Vertical = horizontal
+
+    categories = chart_data["categories"]
+    series = chart_data["series"]
+
+    # Series are columns of the data array, categories its rows
+    data = chart.getData()
+    rows = tuple(tuple(float(s["values"][row]) for s in series) for row in range(len(categories)))
+    data.setData(rows)
+    data.setRowDescriptions(tuple(str(c) for c in categories))
+    data.setColumnDescriptions(tuple(s.get("name") or f"Series {i + 1}" for i, s in enumerate(series)))
+
+    title = chart_data.get("title") or ""
+    chart.HasMainTitle = bool(title)
+    if title:
+        chart.getTitle().String = title
+    chart.HasLegend = bool(chart_data.get("legend", True))
+
+
+def insert_chart(pptx_path, slide_number, chart_type, chart_data, x=None, y=None, width=None, height=None):
+    """Insert a native chart built from inline data onto a slide"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        slide_width = slide.Width
+        slide_height = slide.Height
+
+        target_width = inches_to_units(width) if width is not None else int(slide_width * DEFAULT_WIDTH_RATIO)
+        target_height = inches_to_units(height) if height is not None else int(slide_height * DEFAULT_HEIGHT_RATIO)
+
+        # Default to centering the chart on the slide
+        target_x = inches_to_units(x) if x is not None else int((slide_width - target_width) / 2)
+        target_y = inches_to_units(y) if y is not None else int((slide_height - target_height) / 2)
+
+        shape = doc.createInstance("com.sun.star.drawing.OLE2Shape")
+        shape_name = unique_shape_name(slide, "Chart")
+        slide.add(shape)
+        shape.Name = shape_name
+        shape.CLSID = CHART_CLSID
+        shape.setPosition(Point(target_x, target_y))
+        shape.setSize(Size(target_width, target_height))
+
+        chart = shape.Model
+        chart.lockControllers()
+        try:
+            fill_chart(chart, chart_type, chart_data)
+        finally:
+            chart.unlockControllers()
+
+        shape_index = slide.getCount() - 1
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        return {
+            "success": True,
+            "slide_number": slide_number,
+            "shape_index": shape_index,
+            "shape_id": shape_name,
+            "chart_type": chart_type,
+            "categories": len(chart_data["categories"]),
+            "series": len(chart_data["series"]),
+            "x": units_to_inches(target_x),
+            "y": units_to_inches(target_y),
+            "width": units_to_inches(target_width),
+            "height": units_to_inches(target_height),
+            "message": f"Inserted {chart_type} chart as shape {shape_index} on slide {slide_number}"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error inserting chart: {e}")
+
+
+def parse_optional_float(value):
+    return float(value) if value not in (None, "") else None
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 9:
+        print("Usage: python3 uno_insert_chart.py <pptx_path> <slide_number> <chart_type> <chart_json> <x> <y> <width> <height>")
+        print(f"Chart types: {', '.join(CHART_TYPES)}; chart_json holds categories, series, title and legend")
+        print("Position and size are in inches; pass '' to use defaults")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+    chart_type = sys.argv[3]
+
+    try:
+        slide_number = int(sys.argv[2])
+        chart_data = json.loads(sys.argv[4])
+        x, y, width, height = [parse_optional_float(sys.argv[i]) for i in range(5, 9)]
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number must be an integer, chart data valid JSON and position/size numbers"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = insert_chart(pptx_path, slide_number, chart_type, chart_data, x, y, width, height)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
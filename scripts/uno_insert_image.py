@@ -0,0 +1,146 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import os
+import json
+from com.sun.star.connection import NoConnectException
+from com.sun.star.beans import PropertyValue
+from com.sun.star.awt import Point, Size
+from uno_connection import connect, load_presentation, get_slide, inches_to_units, units_to_inches, unique_shape_name
+
+# Default image footprint when no size is given: fit inside 60% of the slide
+DEFAULT_FIT_RATIO = 0.6
+
+def get_graphic_size(context, image_url):
+    """Return the natural size of an image in 1/100mm, or None if unknown"""
+    provider = context.ServiceManager.createInstanceWithContext(
+        "com.sun.star.graphic.GraphicProvider", context)
+    descriptor = provider.queryGraphicDescriptor((PropertyValue("URL", 0, image_url, 0),))
+
+    try:
+        size = descriptor.getPropertyValue("Size100thMM")
+        if size.Width > 0 and size.Height > 0:
+            return size.Width, size.Height
+    except Exception:
+        pass
+
+    try:
+        # Fall back to pixels at 96 DPI when the image has no physical size
+        size = descriptor.getPropertyValue("SizePixel")
+        if size.Width > 0 and size.Height > 0:
+            return int(size.Width * 2540 / 96), int(size.Height * 2540 / 96)
+    except Exception:
+        pass
+
+    return None
+
+def insert_image(pptx_path, slide_number, image_path, x=None, y=None, width=None, height=None):
+    """Insert an image onto a slide at the given position and size (inches)"""
+    try:
+        if not os.path.exists(image_path):
+            raise ValueError(f"Image file not found: {image_path}")
+
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        slide_width = slide.Width
+        slide_height = slide.Height
+
+        image_url = uno.systemPathToFileUrl(os.path.abspath(image_path))
+        natural_size = get_graphic_size(context, image_url)
+
+        # Work out the target size, preserving aspect ratio where possible
+        if width is not None and height is not None:
+            target_width = inches_to_units(width)
+            target_height = inches_to_units(height)
+        elif natural_size is not None:
+            natural_width, natural_height = natural_size
+            if width is not None:
+                target_width = inches_to_units(width)
+                target_height = int(target_width * natural_height / natural_width)
+            elif height is not None:
+                target_height = inches_to_units(height)
+                target_width = int(target_height * natural_width / natural_height)
+            else:
+                scale = min(slide_width * DEFAULT_FIT_RATIO / natural_width,
+                            slide_height * DEFAULT_FIT_RATIO / natural_height)
+                target_width = int(natural_width * scale)
+                target_height = int(natural_height * scale)
+        else:
+            target_width = int(slide_width * DEFAULT_FIT_RATIO)
+            target_height = int(slide_height * DEFAULT_FIT_RATIO)
+
+        # Default to centering the image on the slide
+        target_x = inches_to_units(x) if x is not None else int((slide_width - target_width) / 2)
+        target_y = inches_to_units(y) if y is not None else int((slide_height - target_height) / 2)
+
+        provider = context.ServiceManager.createInstanceWithContext(
+            "com.sun.star.graphic.GraphicProvider", context)
+        graphic = provider.queryGraphic((PropertyValue("URL", 0, image_url, 0),))
+
+        shape = doc.createInstance("com.sun.star.drawing.GraphicObjectShape")
+        shape_name = unique_shape_name(slide, "Picture")
+        slide.add(shape)
+        shape.Name = shape_name
+        shape.Graphic = graphic
+        shape.setPosition(Point(target_x, target_y))
+        shape.setSize(Size(target_width, target_height))
+
+        shape_index = slide.getCount() - 1
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        return {
+            "success": True,
+            "slide_number": slide_number,
+            "shape_index": shape_index,
+            "shape_id": shape_name,
+            "image_path": os.path.abspath(image_path),
+            "x": units_to_inches(target_x),
+            "y": units_to_inches(target_y),
+            "width": units_to_inches(target_width),
+            "height": units_to_inches(target_height),
+            "message": f"Inserted image as shape {shape_index} on slide {slide_number}"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error inserting image: {e}")
+
+def parse_optional_float(value):
+    return float(value) if value not in (None, "") else None
+
+if __name__ == "__main__":
+    if len(sys.argv) < 4:
+        print("Usage: python3 uno_insert_image.py <pptx_path> <slide_number> <image_path> [x] [y] [width] [height]")
+        print("Position and size are in inches; omit or pass '' to use defaults")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+    image_path = sys.argv[3]
+
+    try:
+        slide_number = int(sys.argv[2])
+        x, y, width, height = [parse_optional_float(sys.argv[i]) if len(sys.argv) > i else None for i in range(4, 8)]
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number must be an integer and position/size must be numbers"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = insert_image(pptx_path, slide_number, image_path, x, y, width, height)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -0,0 +1,198 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import os
+import json
+from com.sun.star.beans import NamedValue, PropertyValue
+from com.sun.star.connection import NoConnectException
+from com.sun.star.awt import Point, Size
+from uno_connection import connect, load_presentation, get_slide, inches_to_units, units_to_inches, unique_shape_name
+from uno_add_animation import constant, create_node, children, main_sequence
+
+# Default video footprint when no size is given: 60% of the slide's width at 16:9
+DEFAULT_VIDEO_RATIO = 0.6
+# Default audio icon size in inches
+DEFAULT_AUDIO_SIZE = 1.0
+
+
+def has_property(obj, name):
+    try:
+        return obj.getPropertySetInfo().hasPropertyByName(name)
+    except Exception:
+        return False
+
+
+def target_size(slide, kind, width, height):
+    """Work out the media frame size in 1/100 mm; video keeps 16:9 when one side is given"""
+    if kind == "audio":
+        default = inches_to_units(DEFAULT_AUDIO_SIZE)
+        return (inches_to_units(width) if width is not None else default,
+                inches_to_units(height) if height is not None else default)
+    if width is not None and height is not None:
+        return inches_to_units(width), inches_to_units(height)
+    if width is not None:
+        return inches_to_units(width), int(inches_to_units(width) * 9 / 16)
+    if height is not None:
+        return int(inches_to_units(height) * 16 / 9), inches_to_units(height)
+    target_width = int(slide.Width * DEFAULT_VIDEO_RATIO)
+    return target_width, int(target_width * 9 / 16)
+
+
+def embed_media(context, shape, media_path):
+    """Store the media file inside the document, as the PowerPoint import does"""
+    access = context.ServiceManager.createInstanceWithContext("com.sun.star.ucb.SimpleFileAccess", context)
+    stream = access.openFileRead(uno.systemPathToFileUrl(media_path))
+    shape.PrivateStream = stream
+    shape.MediaURL = "vnd.sun.star.Package:Media/" + os.path.basename(media_path)
+
+
+def set_poster(context, shape, poster_path):
+    """Show an image while the media isn't playing; returns False when LibreOffice can't"""
+    if not has_property(shape, "Graphic"):
+        return False
+    provider = context.ServiceManager.createInstanceWithContext("com.sun.star.graphic.GraphicProvider", context)
+    graphic = provider.queryGraphic((PropertyValue("URL", 0, uno.systemPathToFileUrl(poster_path), 0),))
+    if graphic is None:
+        return False
+    shape.Graphic = graphic
+    return True
+
+
+def add_autoplay(context, slide, shape):
+    """Start playing the media as the slide shows, before any click-triggered animation"""
+    sequence = main_sequence(context, slide)
+    click_group = create_node(context, "ParallelTimeContainer")
+    click_group.Begin = 0.0
+    click_groups = children(sequence)
+    if click_groups:
+        sequence.insertBefore(click_group, click_groups[0])
+    else:
+        sequence.appendChild(click_group)
+
+    group = create_node(context, "ParallelTimeContainer")
+    group.Begin = 0.0
+    click_group.appendChild(group)
+
+    effect_node = create_node(context, "ParallelTimeContainer")
+    effect_node.Begin = 0.0
+    effect_node.Fill = constant("animations.AnimationFill", "HOLD")
+    effect_node.UserData = (
+        NamedValue("node-type", constant("presentation.EffectNodeType", "AFTER_PREVIOUS")),
+        NamedValue("preset-id", "ooo-media-start"),
+        NamedValue("preset-sub-type", ""),
+        NamedValue("preset-class", constant("presentation.EffectPresetClass", "MEDIACALL")),
+    )
+    command = create_node(context, "Command")
+    command.Target = shape
+    command.Command = constant("presentation.EffectCommands", "PLAY")
+    effect_node.appendChild(command)
+    group.appendChild(effect_node)
+
+
+def insert_media(pptx_path, slide_number, media_path, kind, settings):
+    """Embed or link a video or audio file on a slide at the given position and size (inches)"""
+    try:
+        if not os.path.exists(media_path):
+            raise ValueError(f"Media file not found: {media_path}")
+        poster_path = settings.get("poster_image") or None
+        if poster_path and not os.path.exists(poster_path):
+            raise ValueError(f"Poster image not found: {poster_path}")
+
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        width, height = target_size(slide, kind, settings.get("width"), settings.get("height"))
+
+        # Default to centering the media on the slide
+        x = settings.get("x")
+        y = settings.get("y")
+        target_x = inches_to_units(x) if x is not None else int((slide.Width - width) / 2)
+        target_y = inches_to_units(y) if y is not None else int((slide.Height - height) / 2)
+
+        shape = doc.createInstance("com.sun.star.presentation.MediaShape")
+        shape_name = unique_shape_name(slide, "Video" if kind == "video" else "Audio")
+        slide.add(shape)
+        shape.Name = shape_name
+        linked = bool(settings.get("link"))
+        if linked:
+            shape.MediaURL = uno.systemPathToFileUrl(media_path)
+        else:
+            embed_media(context, shape, media_path)
+        if has_property(shape, "Loop"):
+            shape.Loop = bool(settings.get("loop"))
+        shape.setPosition(Point(target_x, target_y))
+        shape.setSize(Size(width, height))
+
+        warnings = []
+        poster_applied = False
+        if poster_path:
+            poster_applied = set_poster(context, shape, poster_path)
+            if not poster_applied:
+                warnings.append("This LibreOffice version can't set a poster frame; the media shows its first frame or icon instead")
+
+        autoplay = bool(settings.get("autoplay"))
+        if autoplay:
+            add_autoplay(context, slide, shape)
+
+        shape_index = slide.getCount() - 1
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        return {
+            "success": True,
+            "slide_number": slide_number,
+            "shape_index": shape_index,
+            "shape_id": shape_name,
+            "media_path": media_path,
+            "kind": kind,
+            "linked": linked,
+            "poster_applied": poster_applied,
+            "autoplay": autoplay,
+            "x": units_to_inches(target_x),
+            "y": units_to_inches(target_y),
+            "width": units_to_inches(width),
+            "height": units_to_inches(height),
+            "warnings": warnings,
+            "message": f"{'Linked' if linked else 'Embedded'} {kind} as shape {shape_index} on slide {slide_number}"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error inserting media: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 6:
+        print("Usage: python3 uno_insert_media.py <pptx_path> <slide_number> <media_path> <video|audio> <settings_json>")
+        print("Settings: x, y, width, height (inches), link, poster_image, autoplay, loop")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+    media_path = sys.argv[3]
+    kind = sys.argv[4]
+
+    try:
+        slide_number = int(sys.argv[2])
+        settings = json.loads(sys.argv[5])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number must be an integer and settings valid JSON"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = insert_media(pptx_path, slide_number, media_path, kind, settings)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -0,0 +1,116 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from com.sun.star.awt import Point, Size
+from uno_connection import connect, load_presentation, get_slide, inches_to_units, units_to_inches, unique_shape_name
+
+# Default table footprint: 80% of the slide width, one row per 0.4 inches
+DEFAULT_WIDTH_RATIO = 0.8
+DEFAULT_ROW_HEIGHT_INCHES = 0.4
+
+def resize_collection(collection, count):
+    """Grow or shrink a table's rows or columns to count entries"""
+    current = collection.getCount()
+    if count > current:
+        collection.insertByIndex(current, count - current)
+    elif count < current:
+        collection.removeByIndex(count, current - count)
+
+def insert_table(pptx_path, slide_number, rows, columns, x=None, y=None, width=None, height=None, data=None):
+    """Insert a rows x columns table onto a slide, optionally filled with data"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        slide_width = slide.Width
+        slide_height = slide.Height
+
+        target_width = inches_to_units(width) if width is not None else int(slide_width * DEFAULT_WIDTH_RATIO)
+        target_height = inches_to_units(height) if height is not None else inches_to_units(rows * DEFAULT_ROW_HEIGHT_INCHES)
+
+        # Default to centering the table on the slide
+        target_x = inches_to_units(x) if x is not None else int((slide_width - target_width) / 2)
+        target_y = inches_to_units(y) if y is not None else int((slide_height - target_height) / 2)
+
+        shape = doc.createInstance("com.sun.star.drawing.TableShape")
+        shape_name = unique_shape_name(slide, "Table")
+        slide.add(shape)
+        shape.Name = shape_name
+        shape.setPosition(Point(target_x, target_y))
+        shape.setSize(Size(target_width, target_height))
+
+        table = shape.Model
+        resize_collection(table.getRows(), rows)
+        resize_collection(table.getColumns(), columns)
+
+        filled = 0
+        for row, values in enumerate(data or []):
+            for col, value in enumerate(values):
+                if row < rows and col < columns and value is not None:
+                    table.getCellByPosition(col, row).setString(str(value))
+                    filled += 1
+
+        shape_index = slide.getCount() - 1
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        return {
+            "success": True,
+            "slide_number": slide_number,
+            "shape_index": shape_index,
+            "shape_id": shape_name,
+            "rows": rows,
+            "columns": columns,
+            "filled_cells": filled,
+            "x": units_to_inches(target_x),
+            "y": units_to_inches(target_y),
+            "width": units_to_inches(target_width),
+            "height": units_to_inches(target_height),
+            "message": f"Inserted {rows}x{columns} table as shape {shape_index} on slide {slide_number}"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error inserting table: {e}")
+
+def parse_optional_float(value):
+    return float(value) if value not in (None, "") else None
+
+if __name__ == "__main__":
+    if len(sys.argv) < 5:
+        print("Usage: python3 uno_insert_table.py <pptx_path> <slide_number> <rows> <columns> [x] [y] [width] [height] [data_json]")
+        print("Position and size are in inches; omit or pass '' to use defaults")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        slide_number = int(sys.argv[2])
+        rows = int(sys.argv[3])
+        columns = int(sys.argv[4])
+        x, y, width, height = [parse_optional_float(sys.argv[i]) if len(sys.argv) > i else None for i in range(5, 9)]
+        data = json.loads(sys.argv[9]) if len(sys.argv) > 9 and sys.argv[9] else None
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number, rows and columns must be an integer, position/size must be numbers and data must be JSON"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = insert_table(pptx_path, slide_number, rows, columns, x, y, width, height, data)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
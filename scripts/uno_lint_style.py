@@ -0,0 +1,153 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, shape_ids, units_to_inches
+
+# LibreOffice's "automatic" color, which follows the background
+COLOR_AUTO = -1
+
+ROLES = {
+    "com.sun.star.presentation.TitleTextShape": "title",
+    "com.sun.star.presentation.SubtitleShape": "subtitle",
+    "com.sun.star.presentation.OutlinerShape": "body",
+}
+
+# Placeholders LibreOffice fills in itself
+SKIPPED_TYPES = (
+    "com.sun.star.presentation.FooterShape",
+    "com.sun.star.presentation.SlideNumberShape",
+    "com.sun.star.presentation.DateTimeShape",
+)
+
+
+def has_property(obj, name):
+    try:
+        return obj.getPropertySetInfo().hasPropertyByName(name)
+    except Exception:
+        return False
+
+
+def hex_color(color):
+    return f"#{color & 0xFFFFFF:06X}"
+
+
+def solid_fill(obj):
+    """Return the hex fill color of an object filled with a single color, None otherwise"""
+    if not has_property(obj, "FillStyle") or obj.FillStyle.value != "SOLID":
+        return None
+    return hex_color(obj.FillColor)
+
+
+def collect_text(text, facts):
+    """Count the characters of a text per font and per text color"""
+    paragraphs = text.createEnumeration()
+    while paragraphs.hasMoreElements():
+        paragraph = paragraphs.nextElement()
+        if not paragraph.supportsService("com.sun.star.text.Paragraph"):
+            continue
+        portions = paragraph.createEnumeration()
+        while portions.hasMoreElements():
+            portion = portions.nextElement()
+            chars = len(portion.getString().strip())
+            if not chars:
+                continue
+            fonts = facts.setdefault("fonts", {})
+            fonts[portion.CharFontName] = fonts.get(portion.CharFontName, 0) + chars
+            if portion.CharColor != COLOR_AUTO:
+                colors = facts.setdefault("text_colors", {})
+                color = hex_color(portion.CharColor)
+                colors[color] = colors.get(color, 0) + chars
+
+
+def collect_shape(shape, facts):
+    """Gather the fonts, text colors and fills of a shape, descending into groups and tables"""
+    if shape.supportsService("com.sun.star.drawing.GroupShape"):
+        for index in range(shape.getCount()):
+            collect_shape(shape.getByIndex(index), facts)
+        return
+
+    fill = solid_fill(shape)
+    if fill is not None:
+        facts.setdefault("fill_colors", []).append(fill)
+    if shape.supportsService("com.sun.star.drawing.TableShape"):
+        table = shape.Model
+        for row in range(table.getRows().getCount()):
+            for column in range(table.getColumns().getCount()):
+                cell = table.getCellByPosition(column, row)
+                cell_fill = solid_fill(cell)
+                if cell_fill is not None:
+                    facts.setdefault("fill_colors", []).append(cell_fill)
+                collect_text(cell, facts)
+        return
+    if shape.supportsService("com.sun.star.drawing.Text") and shape.getString().strip():
+        collect_text(shape.getText(), facts)
+
+
+def lint_facts(pptx_path):
+    """Report what lint_style compares for every top-level shape of every slide"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path, read_only=True)
+
+        slides = []
+        pages = doc.getDrawPages()
+        for slide_index in range(pages.getCount()):
+            slide = pages.getByIndex(slide_index)
+            shapes = [slide.getByIndex(index) for index in range(slide.getCount())]
+            ids = shape_ids([shape.Name for shape in shapes])
+
+            facts_list = []
+            for index, shape in enumerate(shapes):
+                shape_type = shape.getShapeType()
+                if shape_type in SKIPPED_TYPES:
+                    continue
+                if has_property(shape, "IsEmptyPresentationObject") and shape.IsEmptyPresentationObject:
+                    continue
+                position = shape.getPosition()
+                size = shape.getSize()
+                facts = {
+                    "shape_index": index,
+                    "shape_id": ids[index],
+                    "role": ROLES.get(shape_type, "shape"),
+                    "x": units_to_inches(position.X),
+                    "y": units_to_inches(position.Y),
+                    "width": units_to_inches(size.Width),
+                    "height": units_to_inches(size.Height),
+                }
+                if facts["role"] == "title":
+                    facts["text"] = shape.getString()
+                collect_shape(shape, facts)
+                facts_list.append(facts)
+
+            slides.append({"slide_number": slide_index + 1, "shapes": facts_list})
+
+        doc.close(True)
+
+        return {
+            "success": True,
+            "slides": slides,
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error reading slide styles: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 2:
+        print("Usage: python3 uno_lint_style.py <pptx_path>")
+        sys.exit(1)
+
+    try:
+        result = lint_facts(sys.argv[1])
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -4,9 +4,14 @@ import sys
 import os
 import json
 from com.sun.star.connection import NoConnectException
+from uno_connection import UNO_URL
 
-def list_slides(pptx_path):
-    """List all slides in a presentation with basic information"""
+def list_slides(pptx_path, offset=0, limit=None, titles_only=False):
+    """List slides in a presentation with basic information.
+
+    Only slides offset+1 .. offset+limit are inspected, so paging through a large
+    deck doesn't walk every shape on every call.
+    """
     try:
         # Connect to LibreOffice
         local_context = uno.getComponentContext()
@@ -14,7 +19,7 @@ def list_slides(pptx_path):
             "com.sun.star.bridge.UnoUrlResolver", local_context)
         
         # Connect to the running LibreOffice instance
-        context = resolver.resolve("uno:socket,host=localhost,port=8100;urp;StarOffice.ComponentContext")
+        context = resolver.resolve(UNO_URL)
         desktop = context.ServiceManager.createInstanceWithContext(
             "com.sun.star.frame.Desktop", context)
         
@@ -36,8 +41,9 @@ def list_slides(pptx_path):
         slide_count = slides.getCount()
         
         slides_info = []
+        end = slide_count if limit is None else min(slide_count, offset + limit)
         
-        for i in range(slide_count):
+        for i in range(offset, end):
             slide = slides.getByIndex(i)
             slide_info = {
                 "slide_number": i + 1,
@@ -61,15 +67,23 @@ def list_slides(pptx_path):
                             slide_info["title"] = text[:50] + "..." if len(text) > 50 else text
             
             slide_info["text_shapes"] = text_shape_count
+            if titles_only:
+                slide_info = {"slide_number": slide_info["slide_number"], "title": slide_info["title"]}
             slides_info.append(slide_info)
         
         # Close the document
         doc.close(True)
         
-        return {
+        result = {
             "total_slides": slide_count,
+            "offset": offset,
+            "returned": len(slides_info),
+            "has_more": end < slide_count,
             "slides": slides_info
         }
+        if end < slide_count:
+            result["next_offset"] = end
+        return result
         
     except NoConnectException:
         raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
@@ -77,15 +91,22 @@ def list_slides(pptx_path):
         raise Exception(f"Error listing slides: {e}")
 
 if __name__ == "__main__":
-    if len(sys.argv) != 2:
-        print("Usage: python3 uno_list_slides.py <pptx_path>")
+    if len(sys.argv) < 2 or len(sys.argv) > 5:
+        print("Usage: python3 uno_list_slides.py <pptx_path> [offset] [limit] [titles_only]")
         sys.exit(1)
     
     pptx_path = sys.argv[1]
     
     try:
-        result = list_slides(pptx_path)
+        offset = int(sys.argv[2]) if len(sys.argv) > 2 and sys.argv[2] else 0
+        limit = int(sys.argv[3]) if len(sys.argv) > 3 and sys.argv[3] else None
+        titles_only = len(sys.argv) > 4 and sys.argv[4].lower() == "true"
+        result = list_slides(pptx_path, offset, limit, titles_only)
         print(json.dumps(result, indent=2))
     except Exception as e:
-        print(f"Error: {e}")
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
         sys.exit(1)
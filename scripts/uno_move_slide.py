@@ -0,0 +1,109 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, get_slide
+
+def slide_title(slide):
+    """Return the first non-empty text on a slide, shortened for messages"""
+    try:
+        for i in range(slide.getCount()):
+            shape = slide.getByIndex(i)
+            if hasattr(shape, 'getString'):
+                text = shape.getString().strip()
+                if text:
+                    return text[:50] + "..." if len(text) > 50 else text
+    except Exception:
+        pass
+    return "Untitled"
+
+def slide_index(slides, slide):
+    """Return the 0-based position of a slide in the deck, or -1"""
+    for i in range(slides.getCount()):
+        if slides.getByIndex(i) == slide:
+            return i
+    return -1
+
+def move_slide(pptx_path, slide_number, target_position):
+    """Move a slide so it ends up at target_position (both 1-based)"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slides = doc.getDrawPages()
+        slide_count = slides.getCount()
+        slide = get_slide(doc, slide_number)
+        if target_position < 1 or target_position > slide_count:
+            raise ValueError(f"Target slide number {target_position} out of range (1-{slide_count})")
+
+        title = slide_title(slide)
+
+        if target_position != slide_number:
+            # The draw page API has no reorder call, so select the slide in the (hidden)
+            # view and use the slide sorter's move commands
+            controller = doc.getCurrentController()
+            controller.setCurrentPage(slide)
+            dispatcher = context.ServiceManager.createInstanceWithContext(
+                "com.sun.star.frame.DispatchHelper", context)
+            frame = controller.getFrame()
+
+            if target_position == 1:
+                dispatcher.executeDispatch(frame, ".uno:MovePageFirst", "", 0, ())
+            elif target_position == slide_count:
+                dispatcher.executeDispatch(frame, ".uno:MovePageLast", "", 0, ())
+            else:
+                command = ".uno:MovePageUp" if target_position < slide_number else ".uno:MovePageDown"
+                for _ in range(abs(target_position - slide_number)):
+                    dispatcher.executeDispatch(frame, command, "", 0, ())
+
+            new_position = slide_index(slides, slide) + 1
+            if new_position != target_position:
+                raise Exception(f"Slide ended up at position {new_position} instead of {target_position}")
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        return {
+            "success": True,
+            "slide_number": slide_number,
+            "target_position": target_position,
+            "slide_title": title,
+            "slide_count": slide_count,
+            "message": f"Moved slide {slide_number} ('{title}') to position {target_position}"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error moving slide: {e}")
+
+if __name__ == "__main__":
+    if len(sys.argv) != 4:
+        print("Usage: python3 uno_move_slide.py <pptx_path> <slide_number> <target_position>")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        slide_number = int(sys.argv[2])
+        target_position = int(sys.argv[3])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number and target position must be integers"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = move_slide(pptx_path, slide_number, target_position)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
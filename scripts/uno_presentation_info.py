@@ -0,0 +1,117 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, units_to_inches
+
+# Document properties the set action can change, by their JSON name
+SETTABLE = ("title", "author", "subject", "company")
+
+# Company isn't a standard document property; LibreOffice keeps it as a user-defined one
+# and writes it to docProps/app.xml when saving .pptx
+COMPANY_PROPERTY = "Company"
+
+# PropertyAttribute.REMOVABLE
+REMOVABLE = 128
+
+def aspect_ratio(width, height):
+    """Name the aspect ratio of a slide size, e.g. 16:9"""
+    if width <= 0 or height <= 0:
+        return ""
+    ratio = width / height
+    for name, value in (("16:9", 16 / 9), ("16:10", 16 / 10), ("4:3", 4 / 3), ("3:2", 3 / 2),
+                        ("1:1", 1), ("9:16", 9 / 16), ("3:4", 3 / 4)):
+        if abs(ratio - value) / value < 0.01:
+            return name
+    return f"{ratio:.2f}:1"
+
+def get_company(props):
+    user_props = props.getUserDefinedProperties()
+    if user_props.getPropertySetInfo().hasPropertyByName(COMPANY_PROPERTY):
+        return str(user_props.getPropertyValue(COMPANY_PROPERTY))
+    return ""
+
+def set_company(props, company):
+    user_props = props.getUserDefinedProperties()
+    if user_props.getPropertySetInfo().hasPropertyByName(COMPANY_PROPERTY):
+        user_props.setPropertyValue(COMPANY_PROPERTY, company)
+    else:
+        user_props.addProperty(COMPANY_PROPERTY, REMOVABLE, company)
+
+def describe(doc):
+    """Return the document properties, slide size and slide count"""
+    props = doc.getDocumentProperties()
+    slides = doc.getDrawPages()
+    width = height = 0
+    if slides.getCount() > 0:
+        page = slides.getByIndex(0)
+        width, height = page.Width, page.Height
+
+    return {
+        "title": props.Title,
+        "author": props.Author,
+        "subject": props.Subject,
+        "company": get_company(props),
+        "keywords": ", ".join(props.Keywords),
+        "last_modified_by": props.ModifiedBy,
+        "slide_count": slides.getCount(),
+        "slide_width": units_to_inches(width),
+        "slide_height": units_to_inches(height),
+        "aspect_ratio": aspect_ratio(width, height),
+    }
+
+def presentation_info(pptx_path, action, changes):
+    """Read the presentation's properties, or set some of them and save"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path, read_only=(action == "get"))
+
+        try:
+            if action == "set":
+                props = doc.getDocumentProperties()
+                for key, value in changes.items():
+                    if key not in SETTABLE:
+                        raise ValueError(f"Unknown property {key}")
+                    if key == "title":
+                        props.Title = value
+                    elif key == "author":
+                        props.Author = value
+                    elif key == "subject":
+                        props.Subject = value
+                    elif key == "company":
+                        set_company(props, value)
+
+                # Save the document
+                doc.store()
+
+            result = describe(doc)
+        finally:
+            doc.close(True)
+
+        result["success"] = True
+        if action == "set":
+            result["updated"] = sorted(changes.keys())
+        return result
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error accessing presentation properties: {e}")
+
+if __name__ == "__main__":
+    if len(sys.argv) not in (3, 4) or sys.argv[2] not in ("get", "set"):
+        print("Usage: python3 uno_presentation_info.py <pptx_path> get|set [properties_json]")
+        sys.exit(1)
+
+    try:
+        changes = json.loads(sys.argv[3]) if len(sys.argv) == 4 and sys.argv[3] else {}
+        result = presentation_info(sys.argv[1], sys.argv[2], changes)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
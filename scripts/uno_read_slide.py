@@ -4,8 +4,67 @@ import sys
 import os
 import json
 from com.sun.star.connection import NoConnectException
+from uno_connection import UNO_URL, units_to_inches, shape_ids
 from slide_analyzer import SlideAnalyzer, convert_shape_info_to_dict
 
+# Class ID of embedded chart2 objects
+CHART_CLSID = "12dcae26-281f-416f-a234-c3086127382e"
+
+def shape_kind(shape):
+    """What a shape is, whatever its text (mirrors shapeKind in pptx_reader.go)"""
+    if shape.supportsService("com.sun.star.drawing.TableShape"):
+        return "table"
+    if shape.supportsService("com.sun.star.drawing.OLE2Shape"):
+        return "chart" if shape.CLSID.lower() == CHART_CLSID else "graphic"
+    if shape.supportsService("com.sun.star.drawing.GraphicObjectShape"):
+        return "picture"
+    if shape.supportsService("com.sun.star.drawing.GroupShape"):
+        return "group"
+    if shape.supportsService("com.sun.star.drawing.ConnectorShape"):
+        return "connector"
+    if shape.supportsService("com.sun.star.drawing.MediaShape"):
+        return "graphic"
+    return "shape"
+
+def describe_content(shape, kind, shape_dict):
+    """Add the geometry and the picture, table or chart details of a shape"""
+    position = shape.getPosition()
+    size = shape.getSize()
+    shape_dict["x"] = units_to_inches(position.X)
+    shape_dict["y"] = units_to_inches(position.Y)
+    shape_dict["width"] = units_to_inches(size.Width)
+    shape_dict["height"] = units_to_inches(size.Height)
+    if shape.Description:
+        shape_dict["alt_text"] = shape.Description
+
+    if kind == "picture" and shape.Graphic is not None:
+        pixels = shape.Graphic.SizePixel
+        shape_dict["image"] = {"pixel_width": pixels.Width, "pixel_height": pixels.Height}
+        shape_dict["description"] = f"Picture ({pixels.Width}x{pixels.Height} px)"
+    elif kind == "table":
+        table = shape.Model
+        rows = table.getRows().getCount()
+        columns = table.getColumns().getCount()
+        shape_dict["table"] = [[table.getCellByPosition(col, row).getString() for col in range(columns)] for row in range(rows)]
+        shape_dict["shape_type"] = "table"
+        shape_dict["description"] = f"Table with {rows} rows"
+        shape_dict["edit_hint"] = f"Use edit_table_cell with shape_index={shape_dict['shape_index']} to edit cells"
+    elif kind == "chart":
+        chart = shape.Model
+        data = chart.getData()
+        rows = data.getData()
+        series = [{"name": name, "values": [None if row[column] != row[column] else row[column] for row in rows]}
+                  for column, name in enumerate(data.getColumnDescriptions())]
+        diagram = chart.getDiagram().getDiagramType()
+        shape_dict["chart"] = {
+            "chart_type": diagram.rsplit(".", 1)[-1].replace("Diagram", "").lower(),
+            "title": chart.getTitle().String if chart.HasMainTitle else "",
+            "categories": list(data.getRowDescriptions()),
+            "series": series,
+        }
+        shape_dict["description"] = f"{shape_dict['chart']['chart_type']} chart with {len(series)} series"
+        shape_dict["edit_hint"] = f"Use edit_chart_data with shape_index={shape_dict['shape_index']} to read or update its data"
+
 def read_slide(pptx_path, slide_number):
     """Read detailed content from a specific slide"""
     try:
@@ -15,7 +74,7 @@ def read_slide(pptx_path, slide_number):
             "com.sun.star.bridge.UnoUrlResolver", local_context)
         
         # Connect to the running LibreOffice instance
-        context = resolver.resolve("uno:socket,host=localhost,port=8100;urp;StarOffice.ComponentContext")
+        context = resolver.resolve(UNO_URL)
         desktop = context.ServiceManager.createInstanceWithContext(
             "com.sun.star.frame.Desktop", context)
         
@@ -50,15 +109,22 @@ def read_slide(pptx_path, slide_number):
             "shapes": []
         }
         
+        shapes = [slide.getByIndex(shape_index) for shape_index in range(slide.getCount())]
+        ids = shape_ids([shape.Name for shape in shapes])
+
         # Extract information from each shape using the shared analyzer
-        for shape_index in range(slide.getCount()):
-            shape = slide.getByIndex(shape_index)
-            
+        for shape_index, shape in enumerate(shapes):
             # Use the shared analyzer for consistent shape analysis
             shape_info = SlideAnalyzer.analyze_shape(shape, shape_index)
             
             # Convert to dictionary format for JSON output
             shape_dict = convert_shape_info_to_dict(shape_info)
+            kind = shape_kind(shape)
+            shape_dict["shape_id"] = ids[shape_index]
+            shape_dict["kind"] = kind
+            if shape.Name:
+                shape_dict["name"] = shape.Name
+            describe_content(shape, kind, shape_dict)
             
             slide_info["shapes"].append(shape_dict)
         
@@ -84,5 +150,9 @@ if __name__ == "__main__":
         result = read_slide(pptx_path, slide_number)
         print(json.dumps(result, indent=2))
     except Exception as e:
-        print(f"Error: {e}")
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
         sys.exit(1)
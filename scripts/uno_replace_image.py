@@ -0,0 +1,167 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import os
+import json
+from com.sun.star.connection import NoConnectException
+from com.sun.star.beans import PropertyValue
+from com.sun.star.text import GraphicCrop
+from uno_connection import connect, load_presentation, get_slide
+
+FITS = ("stretch", "fill")
+
+
+def graphic_size(graphic):
+    """Return the natural size of a graphic in 1/100mm, or None if unknown"""
+    try:
+        size = graphic.Size100thMM
+        if size.Width > 0 and size.Height > 0:
+            return size.Width, size.Height
+    except Exception:
+        pass
+
+    try:
+        # Fall back to pixels at 96 DPI when the image has no physical size
+        size = graphic.SizePixel
+        if size.Width > 0 and size.Height > 0:
+            return int(size.Width * 2540 / 96), int(size.Height * 2540 / 96)
+    except Exception:
+        pass
+
+    return None
+
+
+def scaled_crop(crop, old_size, new_size):
+    """Carry a crop over to a graphic of another size, cutting off the same share of each side"""
+    old_width, old_height = old_size
+    new_width, new_height = new_size
+    return GraphicCrop(
+        int(crop.Top * new_height / old_height),
+        int(crop.Bottom * new_height / old_height),
+        int(crop.Left * new_width / old_width),
+        int(crop.Right * new_width / old_width))
+
+
+def fill_crop(frame, new_size):
+    """Crop a graphic evenly on two sides so it fills the frame without distortion"""
+    new_width, new_height = new_size
+    if new_width * frame.Height > new_height * frame.Width:
+        excess = new_width - new_height * frame.Width / frame.Height
+        return GraphicCrop(0, 0, int(excess / 2), int(excess / 2))
+    excess = new_height - new_width * frame.Height / frame.Width
+    return GraphicCrop(int(excess / 2), int(excess / 2), 0, 0)
+
+
+def replace_image(pptx_path, slide_number, shape_index, image_path, fit, alt_text=None):
+    """Swap the picture of an image shape for another file, keeping its frame and crop"""
+    try:
+        if not os.path.exists(image_path):
+            raise ValueError(f"Image file not found: {image_path}")
+
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        if shape_index < 0 or shape_index >= slide.getCount():
+            raise ValueError(f"Shape index {shape_index} out of range (0-{slide.getCount() - 1})")
+
+        shape = slide.getByIndex(shape_index)
+        if shape.getShapeType() != "com.sun.star.drawing.GraphicObjectShape":
+            raise ValueError(f"Shape {shape_index} is not a picture")
+
+        position = shape.getPosition()
+        frame = shape.getSize()
+        old_size = graphic_size(shape.Graphic) if shape.Graphic else None
+        crop = shape.GraphicCrop
+
+        image_url = uno.systemPathToFileUrl(os.path.abspath(image_path))
+        provider = context.ServiceManager.createInstanceWithContext(
+            "com.sun.star.graphic.GraphicProvider", context)
+        graphic = provider.queryGraphic((PropertyValue("URL", 0, image_url, 0),))
+        if graphic is None:
+            raise ValueError(f"Could not read image: {image_path}")
+        new_size = graphic_size(graphic)
+
+        shape.Graphic = graphic
+        if new_size is None:
+            new_crop = GraphicCrop(0, 0, 0, 0)
+        elif fit == "fill":
+            new_crop = fill_crop(frame, new_size)
+        elif old_size is not None:
+            new_crop = scaled_crop(crop, old_size, new_size)
+        else:
+            new_crop = GraphicCrop(0, 0, 0, 0)
+        shape.GraphicCrop = new_crop
+        # Setting the graphic may resize the shape to the image; put the frame back
+        shape.setPosition(position)
+        shape.setSize(frame)
+        if alt_text is not None:
+            shape.Description = alt_text
+
+        # Warn when the picture will look stretched in the unchanged frame
+        distorted = False
+        if fit == "stretch" and new_size is not None:
+            visible_width = new_size[0] - new_crop.Left - new_crop.Right
+            visible_height = new_size[1] - new_crop.Top - new_crop.Bottom
+            if visible_width > 0 and visible_height > 0:
+                ratio = (visible_width / visible_height) / (frame.Width / frame.Height)
+                distorted = abs(ratio - 1) > 0.02
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        result = {
+            "success": True,
+            "slide_number": slide_number,
+            "shape_index": shape_index,
+            "shape_id": shape.Name,
+            "image_path": os.path.abspath(image_path),
+            "fit": fit,
+            "message": f"Replaced the picture of shape {shape_index} on slide {slide_number}"
+        }
+        if distorted:
+            result["warning"] = "The new image has a different aspect ratio than the picture frame, so it looks stretched; use fit 'fill' to crop it instead"
+        return result
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error replacing image: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) not in (6, 7):
+        print("Usage: python3 uno_replace_image.py <pptx_path> <slide_number> <shape_index> <image_path> <fit> [alt_text]")
+        print(f"fit: {', '.join(FITS)}")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+    image_path = sys.argv[4]
+    fit = sys.argv[5]
+    alt_text = sys.argv[6] if len(sys.argv) == 7 else None
+
+    try:
+        slide_number = int(sys.argv[2])
+        shape_index = int(sys.argv[3])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number and shape index must be integers"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+    if fit not in FITS:
+        print(json.dumps({"success": False, "error": f"fit must be one of {', '.join(FITS)}"}, indent=2))
+        sys.exit(1)
+
+    try:
+        result = replace_image(pptx_path, slide_number, shape_index, image_path, fit, alt_text)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -0,0 +1,66 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import os
+import json
+from com.sun.star.beans import PropertyValue
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation
+
+# Export filter for each supported output format
+FILTERS = {
+    ".pptx": "Impress MS PowerPoint 2007 XML",
+    ".odp": "impress8",
+    ".pdf": "impress_pdf_Export",
+}
+
+def save_as(pptx_path, output_path):
+    """Write a copy of a presentation in the format given by output_path's extension"""
+    ext = os.path.splitext(output_path)[1].lower()
+    if ext not in FILTERS:
+        raise ValueError(f"Unsupported format {ext}; use .pptx, .odp or .pdf")
+
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path, read_only=True)
+
+        try:
+            slide_count = doc.getDrawPages().getCount()
+            output_url = uno.systemPathToFileUrl(os.path.abspath(output_path))
+            props = (
+                PropertyValue("FilterName", 0, FILTERS[ext], 0),
+                PropertyValue("Overwrite", 0, True, 0),
+            )
+            # storeToURL writes a copy; the source document stays as it is
+            doc.storeToURL(output_url, props)
+        finally:
+            doc.close(True)
+
+        return {
+            "success": True,
+            "output_path": os.path.abspath(output_path),
+            "format": ext[1:],
+            "slide_count": slide_count,
+            "message": f"Saved a copy of the presentation to {os.path.basename(output_path)}"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error saving presentation: {e}")
+
+if __name__ == "__main__":
+    if len(sys.argv) != 3:
+        print("Usage: python3 uno_save_as.py <pptx_path> <output_path>")
+        sys.exit(1)
+
+    try:
+        result = save_as(sys.argv[1], sys.argv[2])
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
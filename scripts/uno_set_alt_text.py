@@ -0,0 +1,73 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, get_slide
+
+
+def set_alt_text(pptx_path, slide_number, shape_index, alt_text):
+    """Set the alternative text screen readers announce for a shape"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        if shape_index < 0 or shape_index >= slide.getCount():
+            raise ValueError(f"Shape index {shape_index} out of range (0-{slide.getCount() - 1})")
+
+        shape = slide.getByIndex(shape_index)
+        previous = shape.Description
+        shape.Description = alt_text
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        result = {
+            "success": True,
+            "slide_number": slide_number,
+            "shape_index": shape_index,
+            "shape_id": shape.Name,
+            "alt_text": alt_text,
+            "message": f"Set the alt text of shape {shape_index} on slide {slide_number}"
+        }
+        if previous:
+            result["previous_alt_text"] = previous
+        return result
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error setting alt text: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 5:
+        print("Usage: python3 uno_set_alt_text.py <pptx_path> <slide_number> <shape_index> <alt_text>")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+    alt_text = sys.argv[4]
+
+    try:
+        slide_number = int(sys.argv[2])
+        shape_index = int(sys.argv[3])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number and shape index must be integers"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = set_alt_text(pptx_path, slide_number, shape_index, alt_text)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -0,0 +1,88 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, get_slide
+
+
+def apply_footer(slide, settings):
+    """Set the footer, slide number and date fields given in settings on a slide"""
+    footer_text = settings.get("footer_text")
+    if footer_text is not None:
+        slide.FooterText = footer_text
+    if settings.get("show_footer") is not None:
+        slide.IsFooterVisible = bool(settings["show_footer"])
+
+    if settings.get("show_slide_number") is not None:
+        slide.IsPageNumberVisible = bool(settings["show_slide_number"])
+
+    date_text = settings.get("date_text")
+    if date_text is not None:
+        # A fixed date shows the text as given; without one the date follows the clock
+        slide.IsDateTimeFixed = date_text != ""
+        slide.DateTimeText = date_text
+    if settings.get("show_date") is not None:
+        slide.IsDateTimeVisible = bool(settings["show_date"])
+
+
+def set_footer(pptx_path, slide_numbers, settings):
+    """Configure the footer fields of the given slides, or of every slide when none are given"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide_count = doc.getDrawPages().getCount()
+        if not slide_numbers:
+            slide_numbers = list(range(1, slide_count + 1))
+        for slide_number in slide_numbers:
+            apply_footer(get_slide(doc, slide_number), settings)
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        scope = "all slides" if len(slide_numbers) == slide_count else f"slides {slide_numbers}"
+        changed = {key: value for key, value in settings.items() if value is not None}
+        return {
+            "success": True,
+            "slides": slide_numbers,
+            "settings": changed,
+            "message": f"Updated the footer of {scope}"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error setting footer: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 4:
+        print("Usage: python3 uno_set_footer.py <pptx_path> <slide_numbers_json> <settings_json>")
+        print("Pass [] to change every slide; settings: footer_text, show_footer, show_slide_number, show_date, date_text")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        slide_numbers = json.loads(sys.argv[2])
+        settings = json.loads(sys.argv[3])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide numbers and settings must be valid JSON"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = set_footer(pptx_path, slide_numbers, settings)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
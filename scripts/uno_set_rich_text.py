@@ -0,0 +1,137 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from com.sun.star.text.ControlCharacter import PARAGRAPH_BREAK
+from com.sun.star.awt.FontWeight import BOLD, NORMAL
+from com.sun.star.awt.FontUnderline import SINGLE, NONE
+from uno_connection import connect, load_presentation, get_slide
+from text_fit import apply_autofit
+
+# Character properties a run can override; everything else follows the shape's text style
+RUN_PROPERTIES = ("CharWeight", "CharPosture", "CharUnderline", "CharColor", "CharHeight")
+
+
+def utf16_length(text):
+    """Length of text in UTF-16 code units, which text cursors move by"""
+    return len(text.encode("utf-16-le")) // 2
+
+
+def run_properties(run, base):
+    """Return the character properties of a run: the base style with its overrides"""
+    props = dict(base)
+    if run.get("bold") is not None:
+        props["CharWeight"] = BOLD if run["bold"] else NORMAL
+    if run.get("italic") is not None:
+        props["CharPosture"] = uno.Enum("com.sun.star.awt.FontSlant", "ITALIC" if run["italic"] else "NONE")
+    if run.get("underline") is not None:
+        props["CharUnderline"] = SINGLE if run["underline"] else NONE
+    if run.get("color"):
+        props["CharColor"] = int(run["color"], 16)
+    if run.get("size"):
+        props["CharHeight"] = float(run["size"])
+    return props
+
+
+def set_rich_text(pptx_path, slide_number, shape_index, runs, autofit=None):
+    """Replace a text shape's content with formatted runs; autofit sets how the shape fits them"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        shape_count = slide.getCount()
+        if shape_index < 0 or shape_index >= shape_count:
+            raise ValueError(f"Shape index {shape_index} out of range (0-{shape_count - 1})")
+        shape = slide.getByIndex(shape_index)
+        if not hasattr(shape, "getText"):
+            raise ValueError(f"Shape {shape_index} does not contain editable text")
+
+        shape.setString("")
+        text = shape.getText()
+        cursor = text.createTextCursor()
+        base = {name: cursor.getPropertyValue(name) for name in RUN_PROPERTIES}
+
+        paragraphs = 1
+        links = 0
+        for run in runs:
+            for i, piece in enumerate(run["text"].split("\n")):
+                if i > 0:
+                    text.insertControlCharacter(cursor, PARAGRAPH_BREAK, False)
+                    paragraphs += 1
+                if not piece:
+                    continue
+
+                if run.get("hyperlink"):
+                    # A URL field is a single character as far as the cursor is concerned
+                    field = doc.createInstance("com.sun.star.text.TextField.URL")
+                    field.URL = run["hyperlink"]
+                    field.Representation = piece
+                    text.insertTextContent(cursor, field, False)
+                    length = 1
+                    links += 1
+                else:
+                    text.insertString(cursor, piece, False)
+                    length = utf16_length(piece)
+
+                cursor.goLeft(length, True)
+                for name, value in run_properties(run, base).items():
+                    cursor.setPropertyValue(name, value)
+                cursor.collapseToEnd()
+
+        if autofit:
+            apply_autofit(shape, autofit)
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        return {
+            "success": True,
+            "slide_number": slide_number,
+            "shape_index": shape_index,
+            "runs": len(runs),
+            "paragraphs": paragraphs,
+            "hyperlinks": links,
+            "text": "".join(run["text"] for run in runs),
+            "autofit": autofit,
+            "message": f"Set {len(runs)} formatted runs in shape {shape_index} on slide {slide_number}"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error setting rich text: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) not in (5, 6):
+        print("Usage: python3 uno_set_rich_text.py <pptx_path> <slide_number> <shape_index> <runs_json> [autofit]")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        slide_number = int(sys.argv[2])
+        shape_index = int(sys.argv[3])
+        runs = json.loads(sys.argv[4])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number and shape index must be integers and runs valid JSON"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        autofit = sys.argv[5] if len(sys.argv) > 5 else None
+        result = set_rich_text(pptx_path, slide_number, shape_index, runs, autofit)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -0,0 +1,88 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, get_slide, shape_ids
+
+ORDERS = ("front", "back", "forward", "backward")
+
+
+def set_shape_order(pptx_path, slide_number, shape_index, order):
+    """Move a shape in the slide's stacking order; index 0 is at the back"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        shape_count = slide.getCount()
+        if shape_index < 0 or shape_index >= shape_count:
+            raise ValueError(f"Shape index {shape_index} out of range (0-{shape_count - 1})")
+
+        shape = slide.getByIndex(shape_index)
+        current = shape.ZOrder
+        target = {
+            "front": shape_count - 1,
+            "back": 0,
+            "forward": min(current + 1, shape_count - 1),
+            "backward": max(current - 1, 0),
+        }[order]
+        if target != current:
+            shape.ZOrder = target
+
+        # ZOrder is the index, so find the shape again to report its place
+        new_index = next(index for index in range(shape_count) if slide.getByIndex(index) == shape)
+        ids = shape_ids([slide.getByIndex(index).Name for index in range(shape_count)])
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        if new_index == shape_index:
+            message = f"'{shape.Name}' is already at the {'front' if new_index == shape_count - 1 else 'back'}"
+        else:
+            message = f"Moved '{shape.Name}' from index {shape_index} to {new_index} on slide {slide_number}; shapes in between have new indexes"
+        return {
+            "success": True,
+            "slide_number": slide_number,
+            "shape_index": new_index,
+            "shape_id": ids[new_index],
+            "previous_index": shape_index,
+            "shape_count": shape_count,
+            "message": message
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error changing shape order: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 5 or sys.argv[4] not in ORDERS:
+        print("Usage: python3 uno_set_shape_order.py <pptx_path> <slide_number> <shape_index> front|back|forward|backward")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        slide_number = int(sys.argv[2])
+        shape_index = int(sys.argv[3])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number and shape index must be an integer"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = set_shape_order(pptx_path, slide_number, shape_index, sys.argv[4])
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
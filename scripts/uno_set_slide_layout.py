@@ -0,0 +1,63 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, get_slide
+from slide_layouts import apply_layout
+
+def set_slide_layout(pptx_path, slide_number, layout):
+    """Apply a layout ({"name": ..., "type": ...}) to an existing slide"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide = get_slide(doc, slide_number)
+        applied = apply_layout(doc, slide, layout)
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        return {
+            "success": True,
+            "slide_number": slide_number,
+            "layout": layout.get("name") or layout.get("type"),
+            "applied": applied,
+            "message": f"Applied layout {layout.get('name') or layout.get('type')} to slide {slide_number}; "
+                       "existing content stays, re-read the slide to see the new placeholders"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error setting slide layout: {e}")
+
+if __name__ == "__main__":
+    if len(sys.argv) != 4:
+        print("Usage: python3 uno_set_slide_layout.py <pptx_path> <slide_number> <layout_json>")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        slide_number = int(sys.argv[2])
+        layout = json.loads(sys.argv[3])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide number must be an integer and layout valid JSON"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = set_slide_layout(pptx_path, slide_number, layout)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
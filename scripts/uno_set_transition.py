@@ -0,0 +1,139 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation, get_slide
+
+# Transition names offered to the agent: (TransitionType, TransitionSubType, reversed).
+# Directional transitions get their subtype from the direction instead.
+TRANSITIONS = {
+    "none": (None, None, False),
+    "fade": ("FADE", "CROSSFADE", False),
+    "fade_through_black": ("FADE", "FADEOVERCOLOR", False),
+    "dissolve": ("DISSOLVE", "DEFAULT", False),
+    "push": ("PUSHWIPE", None, False),
+    "cover": ("SLIDEWIPE", None, False),
+    "uncover": ("SLIDEWIPE", None, True),
+    "wipe": ("BARWIPE", None, False),
+    "split": ("BARNDOORWIPE", "VERTICAL", False),
+    "circle": ("ELLIPSEWIPE", "CIRCLE", False),
+    "random_bars": ("RANDOMBARWIPE", "VERTICAL", False),
+    "checkerboard": ("CHECKERBOARDWIPE", "DOWN", False),
+}
+
+# Subtypes of the directional transitions, by where the new slide comes from: slides
+# (push, cover) move in from that side, wipes run away from it
+SLIDE_DIRECTIONS = {
+    "from_left": "FROMLEFT",
+    "from_right": "FROMRIGHT",
+    "from_top": "FROMTOP",
+    "from_bottom": "FROMBOTTOM",
+}
+WIPE_DIRECTIONS = {
+    "from_left": ("LEFTTORIGHT", False),
+    "from_right": ("LEFTTORIGHT", True),
+    "from_top": ("TOPTOBOTTOM", False),
+    "from_bottom": ("TOPTOBOTTOM", True),
+}
+
+
+def constant(group, name):
+    """Look up a com.sun.star.animations constant"""
+    return uno.getConstantByName(f"com.sun.star.animations.{group}.{name}")
+
+
+def apply_transition(slide, settings):
+    """Set the transition and advance settings given in settings on a slide"""
+    name = settings.get("transition")
+    if name == "none":
+        slide.TransitionType = 0
+        slide.TransitionSubtype = 0
+    elif name:
+        type_name, subtype_name, reverse = TRANSITIONS[name]
+        direction = settings.get("direction") or "from_right"
+        if type_name == "BARWIPE":
+            subtype_name, reverse = WIPE_DIRECTIONS[direction]
+        elif subtype_name is None:
+            subtype_name = SLIDE_DIRECTIONS[direction]
+
+        slide.TransitionType = constant("TransitionType", type_name)
+        slide.TransitionSubtype = constant("TransitionSubType", subtype_name)
+        slide.TransitionDirection = not reverse
+        if name == "fade_through_black":
+            slide.TransitionFadeColor = 0
+
+    if settings.get("duration"):
+        slide.TransitionDuration = float(settings["duration"])
+
+    advance_after = settings.get("advance_after")
+    if advance_after is not None:
+        if advance_after > 0:
+            # Automatic: the slide moves on after advance_after seconds (a click still advances)
+            slide.Change = 1
+            slide.HighResDuration = float(advance_after)
+        else:
+            slide.Change = 0
+
+
+def set_transition(pptx_path, slide_numbers, settings):
+    """Configure the transition of the given slides, or of every slide when none are given"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        slide_count = doc.getDrawPages().getCount()
+        if not slide_numbers:
+            slide_numbers = list(range(1, slide_count + 1))
+        for slide_number in slide_numbers:
+            apply_transition(get_slide(doc, slide_number), settings)
+
+        # Save the document
+        doc.store()
+        doc.close(True)
+
+        scope = "all slides" if len(slide_numbers) == slide_count else f"slides {slide_numbers}"
+        return {
+            "success": True,
+            "slides": slide_numbers,
+            "transition": settings.get("transition"),
+            "duration": settings.get("duration"),
+            "advance_after": settings.get("advance_after"),
+            "message": f"Updated the transition of {scope}"
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error setting transition: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 4:
+        print("Usage: python3 uno_set_transition.py <pptx_path> <slide_numbers_json> <settings_json>")
+        print(f"Pass [] to change every slide; transitions: {', '.join(TRANSITIONS)}")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        slide_numbers = json.loads(sys.argv[2])
+        settings = json.loads(sys.argv[3])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide numbers and settings must be valid JSON"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = set_transition(pptx_path, slide_numbers, settings)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -0,0 +1,215 @@
+#!/usr/bin/env python3
+import uno
+import re
+import sys
+import json
+from com.sun.star.connection import NoConnectException
+from com.sun.star.lang import Locale
+from uno_connection import connect, load_presentation, get_slide, shape_ids
+
+NOTES_SHAPE_TYPE = "com.sun.star.presentation.NotesShape"
+
+# Letters with inner apostrophes, so "don't" is one word
+WORD_PATTERN = re.compile(r"[^\W\d_]+(?:['’][^\W\d_]+)*")
+
+# Links and addresses aren't words
+SKIP_PATTERN = re.compile(r"\S+@\S+|https?://\S+|www\.\S+")
+
+REPEATED_WORD_PATTERN = re.compile(r"\b([^\W\d_]+)\s+\1\b", re.IGNORECASE)
+
+MAX_SUGGESTIONS = 5
+
+# Languages marked as not to be proofread, e.g. code samples
+NO_PROOFING = ("", "zxx")
+
+
+def should_skip(word, ignored):
+    """Acronyms, names like iPhone and ignored words aren't spell checked"""
+    if len(word) < 2 or word.lower() in ignored:
+        return True
+    if word.isupper():
+        return True
+    return any(letter.isupper() for letter in word[1:])
+
+
+def excerpt(text, start, end, width=30):
+    """Return the text around a match, for finding it again"""
+    before = text[max(0, start - width):start]
+    after = text[end:end + width]
+    prefix = "…" if start > width else ""
+    suffix = "…" if end + width < len(text) else ""
+    return (prefix + before + text[start:end] + after + suffix).replace("\n", " ")
+
+
+class Checker:
+    """Spell checks text portions in the language each is marked with"""
+
+    def __init__(self, context, language, ignored):
+        lingu = context.ServiceManager.createInstanceWithContext(
+            "com.sun.star.linguistic2.LinguServiceManager", context)
+        self.speller = lingu.getSpellChecker()
+        self.locale = None
+        if language:
+            parts = language.split("-")
+            self.locale = Locale(parts[0], parts[1] if len(parts) > 1 else "", "")
+        self.ignored = {word.lower() for word in ignored}
+        self.unsupported = set()
+
+    def portion_locale(self, portion):
+        if self.locale is not None:
+            return self.locale
+        return portion.CharLocale
+
+    def check(self, text, report):
+        """Report misspelled words of a text once each, then words typed twice in a row"""
+        seen = set()
+        for portion in text_portions(text):
+            locale = self.portion_locale(portion)
+            if locale.Language in NO_PROOFING:
+                continue
+            tag = f"{locale.Language}-{locale.Country}" if locale.Country else locale.Language
+            if not self.speller.hasLocale(locale):
+                self.unsupported.add(tag)
+                continue
+            content = SKIP_PATTERN.sub(lambda match: " " * len(match.group()), portion.getString())
+            for match in WORD_PATTERN.finditer(content):
+                word = match.group().replace("’", "'")
+                if word in seen or should_skip(word, self.ignored):
+                    continue
+                seen.add(word)
+                if self.speller.isValid(word, locale, ()):
+                    continue
+                alternatives = self.speller.spell(word, locale, ())
+                suggestions = list(alternatives.getAlternatives())[:MAX_SUGGESTIONS] if alternatives else []
+                report({
+                    "type": "spelling",
+                    "word": match.group(),
+                    "suggestions": suggestions,
+                    "language": tag,
+                    "context": excerpt(content, match.start(), match.end()),
+                })
+
+        whole = text.getString()
+        for match in REPEATED_WORD_PATTERN.finditer(whole):
+            report({
+                "type": "repeated_word",
+                "word": match.group(1),
+                "suggestions": [match.group(1)],
+                "context": excerpt(whole, match.start(), match.end()),
+            })
+
+
+def text_portions(text):
+    """Yield the text portions of a text, paragraph by paragraph"""
+    paragraphs = text.createEnumeration()
+    while paragraphs.hasMoreElements():
+        paragraph = paragraphs.nextElement()
+        if not paragraph.supportsService("com.sun.star.text.Paragraph"):
+            continue
+        portions = paragraph.createEnumeration()
+        while portions.hasMoreElements():
+            portion = portions.nextElement()
+            if portion.getString().strip():
+                yield portion
+
+
+def check_shape(shape, checker, report):
+    """Check the text of a shape, the cells of a table and the shapes of a group"""
+    if shape.supportsService("com.sun.star.drawing.GroupShape"):
+        for index in range(shape.getCount()):
+            check_shape(shape.getByIndex(index), checker, report)
+        return
+    if shape.supportsService("com.sun.star.drawing.TableShape"):
+        table = shape.Model
+        for row in range(table.getRows().getCount()):
+            for column in range(table.getColumns().getCount()):
+                cell = table.getCellByPosition(column, row)
+                checker.check(cell, lambda issue: report(dict(issue, location="table_cell", row=row, column=column)))
+        return
+    if shape.supportsService("com.sun.star.drawing.Text") and shape.getString().strip():
+        checker.check(shape.getText(), report)
+
+
+def spellcheck(pptx_path, slide_numbers, options):
+    """Report misspelled and repeated words of the given slides, or of every slide when none are given"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path, read_only=True)
+        checker = Checker(context, options.get("language"), options.get("ignore_words") or [])
+
+        if not slide_numbers:
+            slide_numbers = list(range(1, doc.getDrawPages().getCount() + 1))
+
+        slides = []
+        issue_count = 0
+        for slide_number in slide_numbers:
+            slide = get_slide(doc, slide_number)
+            issues = []
+
+            shapes = [slide.getByIndex(index) for index in range(slide.getCount())]
+            ids = shape_ids([shape.Name for shape in shapes])
+            for index, shape in enumerate(shapes):
+                def report(issue, index=index):
+                    issues.append(dict({"location": "shape", "shape_index": index, "shape_id": ids[index]}, **issue))
+                check_shape(shape, checker, report)
+
+            if options.get("include_notes"):
+                notes_page = slide.getNotesPage()
+                for index in range(notes_page.getCount()):
+                    shape = notes_page.getByIndex(index)
+                    if shape.getShapeType() == NOTES_SHAPE_TYPE and shape.getString().strip():
+                        checker.check(shape.getText(), lambda issue: issues.append(dict({"location": "notes"}, **issue)))
+
+            if issues:
+                slides.append({"slide_number": slide_number, "issues": issues})
+                issue_count += len(issues)
+
+        doc.close(True)
+
+        result = {
+            "success": True,
+            "checked_slides": len(slide_numbers),
+            "issue_count": issue_count,
+            "slides": slides,
+            "message": f"Found {issue_count} possible spelling issue(s) on {len(slides)} of {len(slide_numbers)} slide(s)"
+        }
+        if checker.unsupported:
+            result["unchecked_languages"] = sorted(checker.unsupported)
+            result["warning"] = "No spelling dictionary is installed for " + ", ".join(sorted(checker.unsupported)) + "; text in those languages was not checked"
+        return result
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error checking spelling: {e}")
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 4:
+        print("Usage: python3 uno_spellcheck.py <pptx_path> <slide_numbers_json> <options_json>")
+        print("Pass [] to check every slide; options: include_notes, ignore_words, language")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        slide_numbers = json.loads(sys.argv[2])
+        options = json.loads(sys.argv[3])
+    except ValueError:
+        error_result = {
+            "success": False,
+            "error": "Slide numbers and options must be valid JSON"
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
+
+    try:
+        result = spellcheck(pptx_path, slide_numbers, options)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
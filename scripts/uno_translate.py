@@ -0,0 +1,357 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import os
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation
+
+# Shape types whose content is not text we can translate
+NON_TEXT_SHAPE_REASONS = {
+    "com.sun.star.drawing.GraphicObjectShape": "image (text inside images cannot be translated)",
+    "com.sun.star.drawing.OLE2Shape": "embedded object such as a chart",
+    "com.sun.star.presentation.ChartShape": "chart",
+    "com.sun.star.drawing.MediaShape": "media",
+    "com.sun.star.presentation.MediaShape": "media",
+}
+
+NOTES_SHAPE_TYPE = "com.sun.star.presentation.NotesShape"
+
+def walk_shapes(container, slide_number, id_prefix, add_text, untranslatable):
+    """Pass every text object of the shapes to add_text(target, element_id, kind), descending
+    into groups and tables"""
+    for i in range(container.getCount()):
+        shape = container.getByIndex(i)
+        shape_id = f"{id_prefix}{i}"
+        shape_type = shape.getShapeType() if hasattr(shape, 'getShapeType') else ""
+
+        if shape_type == "com.sun.star.drawing.GroupShape":
+            walk_shapes(shape, slide_number, f"{shape_id}.", add_text, untranslatable)
+            continue
+
+        if shape_type == "com.sun.star.drawing.TableShape":
+            table = shape.Model
+            for row in range(table.getRows().getCount()):
+                for col in range(table.getColumns().getCount()):
+                    add_text(table.getCellByPosition(col, row), f"{shape_id}:{row},{col}", "table_cell")
+            continue
+
+        if shape_type in NON_TEXT_SHAPE_REASONS:
+            untranslatable.append({
+                "slide_number": slide_number,
+                "element": shape_id,
+                "reason": NON_TEXT_SHAPE_REASONS[shape_type]
+            })
+            continue
+
+        if hasattr(shape, 'getString'):
+            add_text(shape, shape_id, "shape")
+
+def collect_notes(slide, slide_number, add_text):
+    """Pass the speaker notes text objects of a slide to add_text"""
+    notes_page = slide.getNotesPage()
+    for i in range(notes_page.getCount()):
+        shape = notes_page.getByIndex(i)
+        if shape.getShapeType() == NOTES_SHAPE_TYPE:
+            add_text(shape, f"notes-{slide_number}-{i}", "notes")
+
+def text_adder(slide_number, elements):
+    """add_text callback collecting the whole text of each non-empty text object"""
+    def add_text(target, element_id, kind):
+        text = target.getString()
+        if text.strip():
+            elements.append({
+                "id": element_id,
+                "slide_number": slide_number,
+                "kind": kind,
+                "text": text
+            })
+    return add_text
+
+def paragraph_portions(paragraph):
+    """Return the text portions of a paragraph and whether it holds fields (links, dates,
+    slide numbers) that can't be moved around"""
+    portions = []
+    has_fields = False
+    enumeration = paragraph.createEnumeration()
+    while enumeration.hasMoreElements():
+        portion = enumeration.nextElement()
+        if portion.TextPortionType == "Text":
+            portions.append(portion)
+        else:
+            has_fields = True
+    return portions, has_fields
+
+def paragraphs_of(target):
+    """Return the paragraphs of a text object"""
+    paragraphs = []
+    enumeration = target.createEnumeration()
+    while enumeration.hasMoreElements():
+        paragraphs.append(enumeration.nextElement())
+    return paragraphs
+
+def paragraph_adder(slide_number, paragraphs):
+    """add_text callback collecting each non-empty paragraph as its runs of uniform formatting"""
+    def add_text(target, element_id, kind):
+        for index, paragraph in enumerate(paragraphs_of(target)):
+            if not paragraph.getString().strip():
+                continue
+            portions, has_fields = paragraph_portions(paragraph)
+            paragraphs.append({
+                "id": f"{element_id}/{index}",
+                "slide_number": slide_number,
+                "kind": kind,
+                "runs": [portion.getString() for portion in portions],
+                "fixed_order": has_fields
+            })
+    return add_text
+
+def extract_text(pptx_path, include_notes=True):
+    """Extract every translatable text element from a presentation"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path, read_only=True)
+
+        slides = doc.getDrawPages()
+        elements = []
+        untranslatable = []
+
+        for i in range(slides.getCount()):
+            slide = slides.getByIndex(i)
+            slide_number = i + 1
+            add_text = text_adder(slide_number, elements)
+            walk_shapes(slide, slide_number, f"slide-{slide_number}-", add_text, untranslatable)
+            if include_notes:
+                collect_notes(slide, slide_number, add_text)
+
+        doc.close(True)
+
+        return {
+            "success": True,
+            "total_slides": slides.getCount(),
+            "elements": elements,
+            "untranslatable": untranslatable
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error extracting text: {e}")
+
+def extract_paragraphs(pptx_path, slide_numbers, include_notes=True):
+    """Extract the paragraphs of the given slides (all when empty) split into formatting runs"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path, read_only=True)
+
+        slides = doc.getDrawPages()
+        total = slides.getCount()
+        for slide_number in slide_numbers:
+            if slide_number < 1 or slide_number > total:
+                raise ValueError(f"Slide {slide_number} out of range (1-{total})")
+
+        paragraphs = []
+        untranslatable = []
+        for slide_number in slide_numbers or range(1, total + 1):
+            slide = slides.getByIndex(slide_number - 1)
+            add_text = paragraph_adder(slide_number, paragraphs)
+            walk_shapes(slide, slide_number, f"slide-{slide_number}-", add_text, untranslatable)
+            if include_notes:
+                collect_notes(slide, slide_number, add_text)
+
+        doc.close(True)
+
+        return {
+            "success": True,
+            "total_slides": total,
+            "paragraphs": paragraphs,
+            "untranslatable": untranslatable
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error extracting text: {e}")
+
+def char_properties(portion):
+    """Capture the character formatting of a text portion"""
+    properties = {}
+    for prop in portion.getPropertySetInfo().getProperties():
+        if prop.Name.startswith("Char"):
+            try:
+                properties[prop.Name] = portion.getPropertyValue(prop.Name)
+            except Exception:
+                pass
+    return properties
+
+def utf16_length(text):
+    """Length of text in the UTF-16 units text cursors move by"""
+    return len(text.encode("utf-16-le")) // 2
+
+def apply_segments(target, paragraph, segments):
+    """Replace a paragraph's text with translated segments, each formatted like the run it
+    came from; the paragraph's own properties (alignment, bullets) stay"""
+    portions, has_fields = paragraph_portions(paragraph)
+    if not portions:
+        raise ValueError("paragraph has no text runs")
+    for segment in segments:
+        if segment["run"] < 0 or segment["run"] >= len(portions):
+            raise ValueError(f"run {segment['run']} out of range (0-{len(portions) - 1})")
+
+    if has_fields:
+        # Fields stay where they are, so each run is translated in place
+        for segment in segments:
+            portions[segment["run"]].setString(segment["text"])
+        return
+
+    formats = [char_properties(portion) for portion in portions]
+    cursor = target.createTextCursorByRange(paragraph)
+    cursor.setString("")
+    for segment in segments:
+        target.insertString(cursor, segment["text"], False)
+        cursor.goLeft(utf16_length(segment["text"]), True)
+        for name, value in formats[segment["run"]].items():
+            try:
+                cursor.setPropertyValue(name, value)
+            except Exception:
+                pass
+        cursor.collapseToEnd()
+
+def apply_paragraphs(pptx_path, translations_path):
+    """Write translated paragraphs back by paragraph ID, keeping the formatting of their runs"""
+    try:
+        with open(translations_path, "r", encoding="utf-8") as f:
+            translations = json.load(f)
+
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        applied = 0
+        failed = []
+
+        for paragraph_id, segments in translations.items():
+            try:
+                element_id, index = paragraph_id.rsplit("/", 1)
+                target = resolve_element(doc, element_id)
+                paragraphs = paragraphs_of(target)
+                if int(index) >= len(paragraphs):
+                    raise ValueError(f"paragraph {index} out of range (0-{len(paragraphs) - 1})")
+                apply_segments(target, paragraphs[int(index)], segments)
+                applied += 1
+            except Exception as e:
+                failed.append({"element": paragraph_id, "reason": str(e)})
+
+        if applied > 0:
+            doc.store()
+        doc.close(True)
+
+        return {
+            "success": True,
+            "applied": applied,
+            "failed": failed
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error applying translations: {e}")
+
+def resolve_element(doc, element_id):
+    """Find the text object referenced by an element ID produced by extract_text"""
+    slides = doc.getDrawPages()
+
+    if element_id.startswith("notes-"):
+        _, slide_number, shape_index = element_id.split("-")
+        notes_page = slides.getByIndex(int(slide_number) - 1).getNotesPage()
+        return notes_page.getByIndex(int(shape_index))
+
+    # slide-<n>-<index>[.<child>...][:<row>,<col>]
+    _, slide_number, path = element_id.split("-", 2)
+    cell = None
+    if ":" in path:
+        path, cell = path.split(":")
+
+    container = slides.getByIndex(int(slide_number) - 1)
+    shape = None
+    for index in path.split("."):
+        shape = container.getByIndex(int(index))
+        container = shape
+
+    if cell is not None:
+        row, col = [int(value) for value in cell.split(",")]
+        return shape.Model.getCellByPosition(col, row)
+
+    return shape
+
+def apply_translations(pptx_path, translations_path):
+    """Write translated text back into the presentation by element ID"""
+    try:
+        with open(translations_path, "r", encoding="utf-8") as f:
+            translations = json.load(f)
+
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path)
+
+        applied = 0
+        failed = []
+
+        for element_id, text in translations.items():
+            try:
+                target = resolve_element(doc, element_id)
+                target.setString(text)
+                applied += 1
+            except Exception as e:
+                failed.append({"element": element_id, "reason": str(e)})
+
+        if applied > 0:
+            doc.store()
+        doc.close(True)
+
+        return {
+            "success": True,
+            "applied": applied,
+            "failed": failed
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error applying translations: {e}")
+
+if __name__ == "__main__":
+    if len(sys.argv) < 3 or sys.argv[1] not in ("extract", "apply", "extract_paragraphs", "apply_paragraphs"):
+        print("Usage: python3 uno_translate.py extract <pptx_path> [include_notes]")
+        print("       python3 uno_translate.py apply <pptx_path> <translations_json_path>")
+        print("       python3 uno_translate.py extract_paragraphs <pptx_path> <slides_json> [include_notes]")
+        print("       python3 uno_translate.py apply_paragraphs <pptx_path> <translations_json_path>")
+        print("slides_json is a list of slide numbers, [] for all; apply_paragraphs takes a map of")
+        print("paragraph IDs to lists of {\"run\": index, \"text\": text} segments")
+        sys.exit(1)
+
+    mode = sys.argv[1]
+    pptx_path = sys.argv[2]
+
+    try:
+        if mode == "extract":
+            include_notes = len(sys.argv) < 4 or sys.argv[3].lower() != "false"
+            result = extract_text(pptx_path, include_notes)
+        elif mode == "extract_paragraphs":
+            if len(sys.argv) < 4:
+                raise ValueError("slides_json is required for extract_paragraphs mode")
+            include_notes = len(sys.argv) < 5 or sys.argv[4].lower() != "false"
+            result = extract_paragraphs(pptx_path, [int(number) for number in json.loads(sys.argv[3])], include_notes)
+        else:
+            if len(sys.argv) < 4:
+                raise ValueError(f"translations_json_path is required for {mode} mode")
+            if mode == "apply":
+                result = apply_translations(pptx_path, sys.argv[3])
+            else:
+                result = apply_paragraphs(pptx_path, sys.argv[3])
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -0,0 +1,52 @@
+#!/usr/bin/env python3
+import uno
+import sys
+import os
+import json
+from com.sun.star.connection import NoConnectException
+from uno_connection import connect, load_presentation
+
+def verify_presentation(pptx_path):
+    """Open a presentation read-only and confirm every slide is readable"""
+    try:
+        context, desktop = connect()
+        doc = load_presentation(desktop, pptx_path, read_only=True)
+        if doc is None:
+            raise Exception("LibreOffice could not open the presentation")
+
+        slides = doc.getDrawPages()
+        slide_count = slides.getCount()
+
+        # Touch each slide so unreadable pages surface as errors
+        for i in range(slide_count):
+            slides.getByIndex(i).getCount()
+
+        doc.close(True)
+
+        return {
+            "success": True,
+            "slide_count": slide_count
+        }
+
+    except NoConnectException:
+        raise Exception("Could not connect to LibreOffice. Make sure it's running with UNO socket.")
+    except Exception as e:
+        raise Exception(f"Error verifying presentation: {e}")
+
+if __name__ == "__main__":
+    if len(sys.argv) != 2:
+        print("Usage: python3 uno_verify.py <pptx_path>")
+        sys.exit(1)
+
+    pptx_path = sys.argv[1]
+
+    try:
+        result = verify_presentation(pptx_path)
+        print(json.dumps(result, indent=2))
+    except Exception as e:
+        error_result = {
+            "success": False,
+            "error": str(e)
+        }
+        print(json.dumps(error_result, indent=2))
+        sys.exit(1)
@@ -0,0 +1,73 @@
+#!/usr/bin/env python3
+"""
+Long-lived UNO worker driven by the Go side over stdin/stdout.
+
+Each request is one JSON line: {"id": 1, "script": "uno_edit_slide.py", "args": [...]}.
+The script runs in this process exactly as if started with python3 (sys.argv is set and
+it runs as __main__), and everything it prints is captured and returned as one JSON line:
+{"id": 1, "output": "...", "exit_code": 0}.
+
+Keeping the interpreter alive saves the Python startup and `import uno` cost on every
+tool call, and uno_connection caches the LibreOffice connection between requests.
+"""
+
+import io
+import json
+import os
+import runpy
+import sys
+import traceback
+from contextlib import redirect_stderr, redirect_stdout
+
+SCRIPTS_DIR = os.path.dirname(os.path.abspath(__file__))
+
+
+def run_script(script, args):
+    """Run a script as __main__ and return (combined output, exit code)"""
+    path = os.path.join(SCRIPTS_DIR, os.path.basename(script))
+    output = io.StringIO()
+    exit_code = 0
+
+    saved_argv = sys.argv
+    sys.argv = [path] + list(args)
+    try:
+        with redirect_stdout(output), redirect_stderr(output):
+            runpy.run_path(path, run_name="__main__")
+    except SystemExit as e:
+        if e.code is None:
+            exit_code = 0
+        elif isinstance(e.code, int):
+            exit_code = e.code
+        else:
+            output.write(str(e.code) + "\n")
+            exit_code = 1
+    except BaseException:
+        output.write(traceback.format_exc())
+        exit_code = 1
+    finally:
+        sys.argv = saved_argv
+
+    return output.getvalue(), exit_code
+
+
+def main():
+    sys.path.insert(0, SCRIPTS_DIR)
+    protocol = sys.stdout
+
+    for line in sys.stdin:
+        line = line.strip()
+        if not line:
+            continue
+        try:
+            request = json.loads(line)
+            output, exit_code = run_script(request["script"], request.get("args", []))
+            response = {"id": request.get("id"), "output": output, "exit_code": exit_code}
+        except Exception as e:
+            response = {"id": None, "output": f"Invalid worker request: {e}\n", "exit_code": 2}
+
+        protocol.write(json.dumps(response) + "\n")
+        protocol.flush()
+
+
+if __name__ == "__main__":
+    main()
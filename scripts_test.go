@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestScriptManagerExtractsOncePerVersion(t *testing.T) {
+	t.Setenv(scriptsDirEnv, "")
+	cacheRoot := t.TempDir()
+	source := fstest.MapFS{
+		"uno_a.py": {Data: []byte("print('a')\n")},
+		"uno_b.py": {Data: []byte("from uno_a import *\n")},
+	}
+
+	dir, err := NewScriptManager(source, cacheRoot).Dir()
+	if err != nil {
+		t.Fatalf("Dir failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "uno_b.py"))
+	if err != nil || string(data) != "from uno_a import *\n" {
+		t.Fatalf("expected extracted script, got %q, %v", data, err)
+	}
+
+	// A second run with the same scripts reuses the directory
+	again, err := NewScriptManager(source, cacheRoot).Dir()
+	if err != nil || again != dir {
+		t.Errorf("expected %s to be reused, got %s, %v", dir, again, err)
+	}
+
+	// Changed scripts get their own directory
+	source["uno_a.py"] = &fstest.MapFile{Data: []byte("print('changed')\n")}
+	changed, err := NewScriptManager(source, cacheRoot).Dir()
+	if err != nil || changed == dir {
+		t.Errorf("expected a new directory for changed scripts, got %s, %v", changed, err)
+	}
+
+	entries, _ := os.ReadDir(cacheRoot)
+	if len(entries) != 2 {
+		t.Errorf("expected only the two version directories in the cache, got %d entries", len(entries))
+	}
+}
+
+func TestScriptManagerEmbedsEveryScript(t *testing.T) {
+	t.Setenv(scriptsDirEnv, "")
+	dir, err := NewScriptManager(mustSub(embeddedScripts, "scripts"), t.TempDir()).Dir()
+	if err != nil {
+		t.Fatalf("Dir failed: %v", err)
+	}
+
+	onDisk, _ := filepath.Glob(filepath.Join("scripts", "*.py"))
+	for _, path := range onDisk {
+		if _, err := os.Stat(filepath.Join(dir, filepath.Base(path))); err != nil {
+			t.Errorf("%s was not embedded: %v", filepath.Base(path), err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, unoWorkerScript)); err != nil {
+		t.Errorf("expected the UNO worker to be extracted: %v", err)
+	}
+}
+
+func TestScriptManagerHonorsOverride(t *testing.T) {
+	t.Setenv(scriptsDirEnv, "/opt/slidepilot/scripts")
+
+	path, err := NewScriptManager(fstest.MapFS{}, t.TempDir()).Path("uno_read_slide.py")
+	if err != nil || path != filepath.Join("/opt/slidepilot/scripts", "uno_read_slide.py") {
+		t.Errorf("expected the override directory, got %s, %v", path, err)
+	}
+}
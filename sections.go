@@ -0,0 +1,461 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SectionSummary describes one section in section tool results
+type SectionSummary struct {
+	SectionNumber int    `json:"section_number"`
+	Name          string `json:"name"`
+	FirstSlide    int    `json:"first_slide,omitempty"` // 0 for an empty section
+	LastSlide     int    `json:"last_slide,omitempty"`
+	SlideCount    int    `json:"slide_count"`
+}
+
+// ListSectionsDefinition defines the list_sections tool
+var ListSectionsDefinition = ToolDefinition{
+	Name: "list_sections",
+	Description: `List the presentation's sections: each section's number, name and the slides it holds (first_slide to last_slide).
+
+Sections group consecutive slides of large decks, e.g. "Intro", "Results", "Appendix". list_slides shows each slide's section as well.`,
+	InputSchema: ListSectionsInputSchema,
+	Function:    ListSections,
+	ReadOnly:    true,
+}
+
+type ListSectionsInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+}
+
+var ListSectionsInputSchema = GenerateSchema[ListSectionsInput]()
+
+func ListSections(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	listInput := ListSectionsInput{}
+	if err := json.Unmarshal(input, &listInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+	path, err := pptxDeckPath(app, listInput.PresentationPath, "sections")
+	if err != nil {
+		return "", err
+	}
+
+	pkg, err := openPPTX(path)
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to read presentation: %v", err)
+	}
+	defer pkg.Close()
+	sections, err := pkg.Sections()
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to read sections: %v", err)
+	}
+	ids, err := pkg.slideIDs()
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to read slides: %v", err)
+	}
+
+	result := map[string]interface{}{
+		"sections":     summarizeSections(sections, ids),
+		"total_slides": len(ids),
+	}
+	if len(sections) == 0 {
+		result["message"] = "The presentation has no sections; use add_section to create one"
+	}
+	return marshalResult(result)
+}
+
+// AddSectionDefinition defines the add_section tool
+var AddSectionDefinition = ToolDefinition{
+	Name: "add_section",
+	Description: `Start a new section at a slide. The section runs from first_slide up to the next section, taking those slides from the section they were in.
+
+In a deck without sections, the slides before first_slide go into a "Default Section", as in PowerPoint. Use move_slides_to_section to put other slides into the section.`,
+	InputSchema: AddSectionInputSchema,
+	Function:    AddSection,
+	Mutating:    true,
+}
+
+type AddSectionInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Name             string `json:"name" jsonschema_description:"Name of the new section"`
+	FirstSlide       int    `json:"first_slide" jsonschema_description:"Slide the section starts at (1-based indexing)"`
+}
+
+var AddSectionInputSchema = GenerateSchema[AddSectionInput]()
+
+func AddSection(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	addInput := AddSectionInput{}
+	if err := json.Unmarshal(input, &addInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+	path, err := pptxDeckPath(app, addInput.PresentationPath, "sections")
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimSpace(addInput.Name)
+	if name == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "name is required")
+	}
+
+	return editSections(path, func(sections []pptxSection, ids []string) ([]pptxSection, []string, string, error) {
+		if addInput.FirstSlide < 1 || addInput.FirstSlide > len(ids) {
+			return nil, nil, "", NewToolError(ErrCodeSlideOutOfRange, "first_slide %d is not between 1 and %d", addInput.FirstSlide, len(ids))
+		}
+		start := ids[addInput.FirstSlide-1]
+		if len(sections) == 0 {
+			sections = []pptxSection{{Name: "Default Section", SlideIDs: ids}}
+		}
+		for i, section := range sections {
+			for j, id := range section.SlideIDs {
+				if id != start {
+					continue
+				}
+				if j == 0 {
+					return nil, nil, "", NewToolError(ErrCodeInvalidInput, "section %q already starts at slide %d; use rename_section to rename it", section.Name, addInput.FirstSlide)
+				}
+				added := pptxSection{Name: name, SlideIDs: append([]string(nil), section.SlideIDs[j:]...)}
+				sections[i].SlideIDs = section.SlideIDs[:j]
+				sections = append(sections[:i+1], append([]pptxSection{added}, sections[i+1:]...)...)
+				return sections, ids, fmt.Sprintf("Added section %q starting at slide %d", name, addInput.FirstSlide), nil
+			}
+		}
+		return nil, nil, "", NewToolError(ErrCodeInternal, "slide %d is in no section", addInput.FirstSlide)
+	})
+}
+
+// RenameSectionDefinition defines the rename_section tool
+var RenameSectionDefinition = ToolDefinition{
+	Name:        "rename_section",
+	Description: `Rename a section, given by its name or section_number from list_sections.`,
+	InputSchema: RenameSectionInputSchema,
+	Function:    RenameSection,
+	Mutating:    true,
+}
+
+type RenameSectionInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Section          string `json:"section" jsonschema_description:"Current name of the section, or its section_number from list_sections"`
+	NewName          string `json:"new_name" jsonschema_description:"New name for the section"`
+}
+
+var RenameSectionInputSchema = GenerateSchema[RenameSectionInput]()
+
+func RenameSection(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	renameInput := RenameSectionInput{}
+	if err := json.Unmarshal(input, &renameInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+	path, err := pptxDeckPath(app, renameInput.PresentationPath, "sections")
+	if err != nil {
+		return "", err
+	}
+	newName := strings.TrimSpace(renameInput.NewName)
+	if newName == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "new_name is required")
+	}
+
+	return editSections(path, func(sections []pptxSection, ids []string) ([]pptxSection, []string, string, error) {
+		i, err := findSection(sections, renameInput.Section)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		oldName := sections[i].Name
+		sections[i].Name = newName
+		return sections, ids, fmt.Sprintf("Renamed section %q to %q", oldName, newName), nil
+	})
+}
+
+// DeleteSectionDefinition defines the delete_section tool
+var DeleteSectionDefinition = ToolDefinition{
+	Name: "delete_section",
+	Description: `Remove a section, given by its name or section_number from list_sections.
+
+By default only the section goes: its slides join the section before it (or the one after, for the first section). Set delete_slides to delete the section's slides as well; at least one slide must remain in the presentation.`,
+	InputSchema: DeleteSectionInputSchema,
+	Function:    DeleteSection,
+	Mutating:    true,
+	Destructive: true,
+}
+
+type DeleteSectionInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Section          string `json:"section" jsonschema_description:"Name of the section, or its section_number from list_sections"`
+	DeleteSlides     bool   `json:"delete_slides,omitempty" jsonschema_description:"(Optional) Delete the section's slides too, defaults to false"`
+}
+
+var DeleteSectionInputSchema = GenerateSchema[DeleteSectionInput]()
+
+func DeleteSection(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	deleteInput := DeleteSectionInput{}
+	if err := json.Unmarshal(input, &deleteInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+	path, err := pptxDeckPath(app, deleteInput.PresentationPath, "sections")
+	if err != nil {
+		return "", err
+	}
+
+	var removed []string
+	result, err := editSections(path, func(sections []pptxSection, ids []string) ([]pptxSection, []string, string, error) {
+		i, err := findSection(sections, deleteInput.Section)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		section := sections[i]
+		sections = append(sections[:i], sections[i+1:]...)
+		if deleteInput.DeleteSlides {
+			if len(section.SlideIDs) == len(ids) {
+				return nil, nil, "", NewToolError(ErrCodeInvalidInput, "section %q holds every slide; a presentation needs at least one slide", section.Name)
+			}
+			removed = section.SlideIDs
+			return sections, ids, fmt.Sprintf("Deleted section %q and its %d slide(s)", section.Name, len(section.SlideIDs)), nil
+		}
+		if len(sections) > 0 {
+			// The slides join the previous section, or the next one when it was the first
+			if i > 0 {
+				sections[i-1].SlideIDs = append(sections[i-1].SlideIDs, section.SlideIDs...)
+			} else {
+				sections[0].SlideIDs = append(append([]string(nil), section.SlideIDs...), sections[0].SlideIDs...)
+			}
+		}
+		return sections, ids, fmt.Sprintf("Removed section %q; its slides were kept", section.Name), nil
+	})
+	if err != nil || len(removed) == 0 {
+		return result, err
+	}
+
+	// Remove the slides by writing the others to a copy and putting it in place
+	pkg, err := openPPTX(path)
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to read presentation: %v", err)
+	}
+	ids, err := pkg.slideIDs()
+	pkg.Close()
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to read slides: %v", err)
+	}
+	gone := map[string]bool{}
+	for _, id := range removed {
+		gone[id] = true
+	}
+	var keep []int
+	for i, id := range ids {
+		if !gone[id] {
+			keep = append(keep, i+1)
+		}
+	}
+	tempFile, err := os.CreateTemp(filepath.Dir(path), ".slidepilot-sections-*.pptx")
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to create temporary file: %v", err)
+	}
+	tempPath := tempFile.Name()
+	tempFile.Close()
+	if _, err := extractSlides(path, tempPath, keep); err != nil {
+		os.Remove(tempPath)
+		return "", NewToolError(ErrCodeInternal, "failed to delete the section's slides: %v", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return "", NewToolError(ErrCodeInternal, "failed to replace presentation: %v", err)
+	}
+	schedulePreviewExport(ctx, app, path)
+	return result, nil
+}
+
+// MoveSlidesToSectionDefinition defines the move_slides_to_section tool
+var MoveSlidesToSectionDefinition = ToolDefinition{
+	Name:        "move_slides_to_section",
+	Description: `Put slides into a section, given by its name or section_number from list_sections. Sections hold consecutive slides, so the slides are moved to the end of the section in the order listed, and slide numbers after them shift. Use list_sections or list_slides afterwards for the new numbers.`,
+	InputSchema: MoveSlidesToSectionInputSchema,
+	Function:    MoveSlidesToSection,
+	Mutating:    true,
+}
+
+type MoveSlidesToSectionInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Slides           []int  `json:"slides" jsonschema_description:"Slide numbers to move (1-based indexing), in the order they should appear"`
+	Section          string `json:"section" jsonschema_description:"Name of the target section, or its section_number from list_sections"`
+}
+
+var MoveSlidesToSectionInputSchema = GenerateSchema[MoveSlidesToSectionInput]()
+
+func MoveSlidesToSection(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	moveInput := MoveSlidesToSectionInput{}
+	if err := json.Unmarshal(input, &moveInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+	path, err := pptxDeckPath(app, moveInput.PresentationPath, "sections")
+	if err != nil {
+		return "", err
+	}
+	if len(moveInput.Slides) == 0 {
+		return "", NewToolError(ErrCodeInvalidInput, "slides is required: list the slide numbers to move")
+	}
+
+	result, err := editSections(path, func(sections []pptxSection, ids []string) ([]pptxSection, []string, string, error) {
+		target, err := findSection(sections, moveInput.Section)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		moving := map[string]bool{}
+		var moved []string
+		for _, n := range moveInput.Slides {
+			if n < 1 || n > len(ids) {
+				return nil, nil, "", NewToolError(ErrCodeSlideOutOfRange, "slide %d is not between 1 and %d", n, len(ids))
+			}
+			if moving[ids[n-1]] {
+				return nil, nil, "", NewToolError(ErrCodeInvalidInput, "slide %d is listed twice", n)
+			}
+			moving[ids[n-1]] = true
+			moved = append(moved, ids[n-1])
+		}
+		for i := range sections {
+			var rest []string
+			for _, id := range sections[i].SlideIDs {
+				if !moving[id] {
+					rest = append(rest, id)
+				}
+			}
+			sections[i].SlideIDs = rest
+		}
+		sections[target].SlideIDs = append(sections[target].SlideIDs, moved...)
+
+		// The slide order follows the sections
+		var order []string
+		for _, section := range sections {
+			order = append(order, section.SlideIDs...)
+		}
+		return sections, order, fmt.Sprintf("Moved %s into section %q", slideListLabel(moveInput.Slides), sections[target].Name), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	schedulePreviewExport(ctx, app, path)
+	return result, nil
+}
+
+// pptxDeckPath returns the .pptx a tool working on the package natively uses, defaulting to
+// the loaded one. feature names what needs a .pptx in the error, e.g. "sections".
+func pptxDeckPath(app *App, presentationPath, feature string) (string, error) {
+	// Use current presentation path if not provided
+	if presentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			presentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+	if _, err := os.Stat(presentationPath); os.IsNotExist(err) {
+		return "", NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", presentationPath)
+	}
+	if !isOOXMLPackage(presentationPath) {
+		return "", NewToolError(ErrCodeInvalidInput, "%s are only supported in .pptx presentations", feature)
+	}
+	return presentationPath, nil
+}
+
+// editSections applies edit to the deck's sections and slide order (sldId ids) and writes
+// the result back, returning the tool result with the sections afterwards
+func editSections(presentationPath string, edit func(sections []pptxSection, ids []string) ([]pptxSection, []string, string, error)) (string, error) {
+	var sections []pptxSection
+	var ids []string
+	var message string
+	err := rewritePresentationXML(presentationPath, func(_ *pptxPackage, presentationXML []byte) ([]byte, error) {
+		current, _ := parseSections(presentationXML)
+		var err error
+		sections, ids, message, err = edit(current, slideIDsOf(presentationXML))
+		if err != nil {
+			return nil, err
+		}
+		presentationXML, err = withSlideOrder(presentationXML, ids)
+		if err != nil {
+			return nil, err
+		}
+		return withSections(presentationXML, sections), nil
+	})
+	if err != nil {
+		var toolErr *ToolError
+		if errors.As(err, &toolErr) {
+			return "", err
+		}
+		return "", NewToolError(ErrCodeInternal, "failed to update sections: %v", err)
+	}
+	return marshalResult(map[string]interface{}{
+		"success":  true,
+		"sections": summarizeSections(sections, ids),
+		"message":  message,
+	})
+}
+
+// findSection returns the index of the section named ref, or numbered ref (1-based)
+func findSection(sections []pptxSection, ref string) (int, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return 0, NewToolError(ErrCodeInvalidInput, "section is required")
+	}
+	if len(sections) == 0 {
+		return 0, NewToolError(ErrCodeInvalidInput, "the presentation has no sections; use add_section to create one")
+	}
+	for i, section := range sections {
+		if strings.EqualFold(section.Name, ref) {
+			return i, nil
+		}
+	}
+	if n, err := strconv.Atoi(ref); err == nil && n >= 1 && n <= len(sections) {
+		return n - 1, nil
+	}
+	names := make([]string, len(sections))
+	for i, section := range sections {
+		names[i] = fmt.Sprintf("%q", section.Name)
+	}
+	return 0, NewToolError(ErrCodeInvalidInput, "no section %q; sections are %s", ref, strings.Join(names, ", "))
+}
+
+// summarizeSections describes sections by slide number
+func summarizeSections(sections []pptxSection, ids []string) []SectionSummary {
+	numbers := make(map[string]int, len(ids))
+	for i, id := range ids {
+		numbers[id] = i + 1
+	}
+	summaries := make([]SectionSummary, 0, len(sections))
+	for i, section := range sections {
+		summary := SectionSummary{SectionNumber: i + 1, Name: section.Name}
+		for _, id := range section.SlideIDs {
+			n, ok := numbers[id]
+			if !ok {
+				continue
+			}
+			if summary.FirstSlide == 0 || n < summary.FirstSlide {
+				summary.FirstSlide = n
+			}
+			summary.LastSlide = max(summary.LastSlide, n)
+			summary.SlideCount++
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// sectionNames maps each slide number to the name of its section
+func sectionNames(sections []pptxSection, ids []string) map[int]string {
+	sectionOf := map[string]string{}
+	for _, section := range sections {
+		for _, id := range section.SlideIDs {
+			sectionOf[id] = section.Name
+		}
+	}
+	names := make(map[int]string, len(ids))
+	for i, id := range ids {
+		if name, ok := sectionOf[id]; ok {
+			names[i+1] = name
+		}
+	}
+	return names
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runSectionTool runs a section tool through the agent and decodes its sections
+func runSectionTool(t *testing.T, env *testEnv, name, input string) []SectionSummary {
+	t.Helper()
+	result := env.app.aiAgent.executeTool(context.Background(), "toolu_1", name, []byte(input))
+	text := result.OfToolResult.Content[0].OfText.Text
+	if result.OfToolResult.IsError.Value {
+		t.Fatalf("%s failed: %s", name, text)
+	}
+	var decoded struct {
+		Data struct {
+			Sections []SectionSummary `json:"sections"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+		t.Fatalf("%s returned invalid JSON: %v\n%s", name, err, text)
+	}
+	return decoded.Data.Sections
+}
+
+func TestSectionTools(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+
+	if sections := runSectionTool(t, env, "list_sections", `{}`); len(sections) != 0 {
+		t.Fatalf("expected no sections, got %+v", sections)
+	}
+
+	sections := runSectionTool(t, env, "add_section", `{"name": "What's Next", "first_slide": 2}`)
+	want := []SectionSummary{
+		{SectionNumber: 1, Name: "Default Section", FirstSlide: 1, LastSlide: 1, SlideCount: 1},
+		{SectionNumber: 2, Name: "What's Next", FirstSlide: 2, LastSlide: 2, SlideCount: 1},
+	}
+	if len(sections) != 2 || sections[0] != want[0] || sections[1] != want[1] {
+		t.Fatalf("unexpected sections after add_section: %+v", sections)
+	}
+	if listed := runSectionTool(t, env, "list_sections", `{}`); len(listed) != 2 || listed[1] != want[1] {
+		t.Errorf("list_sections should read the new section back, got %+v", listed)
+	}
+	listing, err := listSlidesNative(path, 0, 50, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(listing, `"section":"Default Section"`) || !strings.Contains(listing, `"section":"What's Next"`) {
+		t.Errorf("expected list_slides to show sections, got %s", listing)
+	}
+
+	sections = runSectionTool(t, env, "rename_section", `{"section": "1", "new_name": "Intro"}`)
+	if sections[0].Name != "Intro" {
+		t.Errorf("expected section 1 to be renamed, got %+v", sections)
+	}
+
+	// Moving the first slide into the second section puts it last
+	sections = runSectionTool(t, env, "move_slides_to_section", `{"slides": [1], "section": "what's next"}`)
+	if sections[0].SlideCount != 0 || sections[1].FirstSlide != 1 || sections[1].LastSlide != 2 {
+		t.Errorf("unexpected sections after the move: %+v", sections)
+	}
+	pkg, err := openPPTX(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shapes, _ := pkg.SlideShapes(2)
+	pkg.Close()
+	if slideTitle(shapes) != "Quarterly Review" {
+		t.Errorf("expected the old first slide to be second now, got %q", slideTitle(shapes))
+	}
+
+	sections = runSectionTool(t, env, "delete_section", `{"section": "Intro"}`)
+	if len(sections) != 1 || sections[0].SlideCount != 2 {
+		t.Errorf("expected one section holding both slides, got %+v", sections)
+	}
+	result := env.app.aiAgent.executeTool(context.Background(), "toolu_2", "delete_section", []byte(`{"section": "1", "delete_slides": true}`))
+	if !result.OfToolResult.IsError.Value || !strings.Contains(result.OfToolResult.Content[0].OfText.Text, "at least one slide") {
+		t.Errorf("expected deleting every slide to be refused, got %s", result.OfToolResult.Content[0].OfText.Text)
+	}
+	result = env.app.aiAgent.executeTool(context.Background(), "toolu_3", "rename_section", []byte(`{"section": "Missing", "new_name": "X"}`))
+	if !result.OfToolResult.IsError.Value || !strings.Contains(result.OfToolResult.Content[0].OfText.Text, `sections are \"What's Next\"`) {
+		t.Errorf("expected an unknown section to list the sections, got %s", result.OfToolResult.Content[0].OfText.Text)
+	}
+}
+
+func TestDeleteSectionWithSlides(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	runSectionTool(t, env, "add_section", `{"name": "Appendix", "first_slide": 2}`)
+
+	sections := runSectionTool(t, env, "delete_section", `{"section": "Appendix", "delete_slides": true}`)
+	if len(sections) != 1 || sections[0].Name != "Default Section" {
+		t.Errorf("unexpected sections: %+v", sections)
+	}
+	pkg, err := openPPTX(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pkg.Close()
+	if pkg.SlideCount() != 1 {
+		t.Errorf("expected the section's slide to be deleted, got %d slides", pkg.SlideCount())
+	}
+	if _, ok := pkg.parts["ppt/slides/slide2.xml"]; ok {
+		t.Error("expected slide2.xml to be removed from the package")
+	}
+}
+
+func TestRestoreSectionsAfterLibreOfficeSave(t *testing.T) {
+	dir := t.TempDir()
+	beforePath := filepath.Join(dir, "before.pptx")
+	deckPath := filepath.Join(dir, "deck.pptx")
+	if err := copyFile(filepath.Join("testdata", "two_slides.pptx"), beforePath); err != nil {
+		t.Fatal(err)
+	}
+	err := rewritePresentationXML(beforePath, func(pkg *pptxPackage, presentationXML []byte) ([]byte, error) {
+		ids := slideIDsOf(presentationXML)
+		return withSections(presentationXML, []pptxSection{{Name: "Intro", SlideIDs: ids[:1]}, {Name: "Results", SlideIDs: ids[1:]}}), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Slides imported into a deck with sections join the section of the slide before them
+	if err := copyFile(beforePath, deckPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := importSlides(deckPath, filepath.Join("testdata", "two_slides.pptx"), 2, 2, 2, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := sectionSlideCounts(t, deckPath); got != "Intro:2 Results:1" {
+		t.Errorf("expected the imported slide in Intro, got %s", got)
+	}
+
+	// A save that drops the sections, as LibreOffice's does, gets them back by position
+	dropSections := func(path string) {
+		err := rewritePresentationXML(path, func(_ *pptxPackage, presentationXML []byte) ([]byte, error) {
+			return sectionListPattern.ReplaceAll(presentationXML, nil), nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := copyFile(beforePath, deckPath); err != nil {
+		t.Fatal(err)
+	}
+	dropSections(deckPath)
+	if restored, err := restoreSections(beforePath, deckPath); err != nil || !restored {
+		t.Fatalf("expected sections to be restored, got %v (%v)", restored, err)
+	}
+	if got := sectionSlideCounts(t, deckPath); got != "Intro:1 Results:1" {
+		t.Errorf("unexpected sections after restoring: %s", got)
+	}
+	if restored, err := restoreSections(beforePath, deckPath); err != nil || restored {
+		t.Errorf("sections that are there should be left alone, got %v (%v)", restored, err)
+	}
+
+	// With slides added, they are found by their text and new ones join the slide before
+	if err := copyFile(beforePath, deckPath); err != nil {
+		t.Fatal(err)
+	}
+	dropSections(deckPath)
+	if _, err := importSlides(deckPath, filepath.Join("testdata", "mixed_shapes.pptx"), 1, 1, 2, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := restoreSections(beforePath, deckPath); err != nil {
+		t.Fatal(err)
+	}
+	if got := sectionSlideCounts(t, deckPath); got != "Intro:2 Results:1" {
+		t.Errorf("expected the new slide to join Intro, got %s", got)
+	}
+}
+
+// sectionSlideCounts describes a deck's sections as "name:count ..."
+func sectionSlideCounts(t *testing.T, path string) string {
+	t.Helper()
+	pkg, err := openPPTX(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pkg.Close()
+	sections, err := pkg.Sections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parts []string
+	for _, section := range sections {
+		parts = append(parts, fmt.Sprintf("%s:%d", section.Name, len(section.SlideIDs)))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// Settings are the user's model preferences, kept across restarts
+type Settings struct {
+	Model       string   `json:"model"`
+	MaxTokens   int64    `json:"max_tokens"`            // Output token limit of each response
+	Temperature *float64 `json:"temperature,omitempty"` // Unset uses the provider's default
+	// SystemPrompt replaces the built-in instructions; empty uses defaultSystemPrompt
+	SystemPrompt string `json:"system_prompt,omitempty"`
+	// PlanMode has the agent propose its edits as a plan for review before making them
+	PlanMode bool `json:"plan_mode"`
+	// ReviewMode has the agent leave its suggestions as slide comments instead of editing
+	ReviewMode bool `json:"review_mode"`
+	// Brand holds the house style new slides follow; nil when none is set up
+	Brand *BrandProfile `json:"brand,omitempty"`
+	// UnsplashAccessKey and PexelsAPIKey are the stock photo API keys; empty falls back to
+	// the UNSPLASH_ACCESS_KEY and PEXELS_API_KEY environment variables
+	UnsplashAccessKey string `json:"unsplash_access_key,omitempty"`
+	PexelsAPIKey      string `json:"pexels_api_key,omitempty"`
+	// APIToken is the bearer token clients of the --api server send; SLIDEPILOT_API_TOKEN
+	// overrides it
+	APIToken string `json:"api_token,omitempty"`
+}
+
+// BrandProfile is the user's house style: the fonts, colors, logo and footer their decks use
+type BrandProfile struct {
+	HeadingFont string   `json:"heading_font,omitempty"` // Font for titles
+	BodyFont    string   `json:"body_font,omitempty"`    // Font for all other text
+	Palette     []string `json:"palette,omitempty"`      // #RRGGBB colors, the primary color first
+	LogoPath    string   `json:"logo_path,omitempty"`    // Image file of the logo
+	FooterText  string   `json:"footer_text,omitempty"`  // Footer shown on new slides
+}
+
+// maxPaletteColors is more colors than any brand guide lists
+const maxPaletteColors = 16
+
+// Validate reports the first invalid brand setting. The logo file isn't checked, so
+// settings still load while a network drive is away.
+func (b BrandProfile) Validate() error {
+	if len(b.Palette) > maxPaletteColors {
+		return fmt.Errorf("brand palette must have at most %d colors", maxPaletteColors)
+	}
+	for _, color := range b.Palette {
+		if !hexColorPattern.MatchString(color) {
+			return fmt.Errorf("brand palette colors must be #RRGGBB, got %q", color)
+		}
+	}
+	if b.LogoPath != "" && !filepath.IsAbs(b.LogoPath) {
+		return fmt.Errorf("brand logo_path must be an absolute path")
+	}
+	return nil
+}
+
+// maxOutputTokens bounds MaxTokens to what current models accept
+const maxOutputTokens = 64000
+
+// maxSystemPromptChars keeps a pasted system prompt from crowding out the conversation
+const maxSystemPromptChars = 20000
+
+// DefaultSettings are used until the user changes them
+func DefaultSettings() Settings {
+	return Settings{
+		Model:     string(anthropic.ModelClaudeSonnet4_0),
+		MaxTokens: 8192,
+	}
+}
+
+// Validate reports the first invalid setting
+func (s Settings) Validate() error {
+	if s.Model == "" {
+		return fmt.Errorf("model is required")
+	}
+	if s.MaxTokens < 1 || s.MaxTokens > maxOutputTokens {
+		return fmt.Errorf("max_tokens must be between 1 and %d", maxOutputTokens)
+	}
+	if s.Temperature != nil && (*s.Temperature < 0 || *s.Temperature > 1) {
+		return fmt.Errorf("temperature must be between 0 and 1")
+	}
+	if len(s.SystemPrompt) > maxSystemPromptChars {
+		return fmt.Errorf("system_prompt must be at most %d characters", maxSystemPromptChars)
+	}
+	if s.Brand != nil {
+		return s.Brand.Validate()
+	}
+	return nil
+}
+
+// SettingsStore keeps the settings in a JSON file
+type SettingsStore struct {
+	path         string
+	defaultModel string // Model until the user picks one; empty uses DefaultSettings' model
+}
+
+// NewSettingsStore creates a store for the settings file at path
+func NewSettingsStore(path string) *SettingsStore {
+	return &SettingsStore{path: path}
+}
+
+// settingsPath is the settings file in the user config directory, falling back to the
+// working directory
+func settingsPath() string {
+	if configDir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(configDir, "slidepilot", "settings.json")
+	}
+	return "settings.json"
+}
+
+// Load reads the settings, starting from the defaults for anything the file leaves out.
+// A missing file gives the defaults.
+func (s *SettingsStore) Load() (Settings, error) {
+	settings := DefaultSettings()
+	if s.defaultModel != "" {
+		settings.Model = s.defaultModel
+	}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, fmt.Errorf("failed to read settings: %v", err)
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return DefaultSettings(), fmt.Errorf("invalid settings file %s: %v", s.path, err)
+	}
+	if err := settings.Validate(); err != nil {
+		return DefaultSettings(), fmt.Errorf("invalid settings file %s: %v", s.path, err)
+	}
+	return settings, nil
+}
+
+// Save writes the settings, replacing the previous file atomically
+func (s *SettingsStore) Save(settings Settings) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create settings directory: %v", err)
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode settings: %v", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".settings-*")
+	if err != nil {
+		return fmt.Errorf("failed to save settings: %v", err)
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save settings: %v", errors.Join(writeErr, closeErr))
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save settings: %v", err)
+	}
+	return nil
+}
+
+// Settings returns the agent's current settings
+func (a *AIAgent) Settings() Settings {
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+	return a.settings
+}
+
+// SetSettings replaces the agent's settings; the next model request uses them
+func (a *AIAgent) SetSettings(settings Settings) {
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+	a.settings = settings
+}
+
+// messageParams starts a model request with the model, output limit and temperature from
+// the settings
+func (a *AIAgent) messageParams() anthropic.MessageNewParams {
+	settings := a.Settings()
+	params := anthropic.MessageNewParams{
+		Model:     anthropic.Model(settings.Model),
+		MaxTokens: settings.MaxTokens,
+	}
+	if settings.Temperature != nil {
+		params.Temperature = anthropic.Float(*settings.Temperature)
+	}
+	return params
+}
+
+// ModelOption is a model the user can pick
+type ModelOption struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+}
+
+// ModelLister is implemented by clients that can list the provider's models
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]ModelOption, error)
+}
+
+// knownModels are offered when the provider can't list its models
+var knownModels = []ModelOption{
+	{ID: string(anthropic.ModelClaudeSonnet4_0), DisplayName: "Claude Sonnet 4"},
+	{ID: string(anthropic.ModelClaudeOpus4_0), DisplayName: "Claude Opus 4"},
+	{ID: string(anthropic.ModelClaude3_7SonnetLatest), DisplayName: "Claude Sonnet 3.7"},
+	{ID: string(anthropic.ModelClaude3_5HaikuLatest), DisplayName: "Claude Haiku 3.5"},
+}
+
+// ListModels lists the models available to the API key
+func (c *AnthropicClient) ListModels(ctx context.Context) ([]ModelOption, error) {
+	pager := c.client.Models.ListAutoPaging(ctx, anthropic.ModelListParams{})
+	models := []ModelOption{}
+	for pager.Next() {
+		model := pager.Current()
+		models = append(models, ModelOption{ID: model.ID, DisplayName: model.DisplayName})
+	}
+	if err := pager.Err(); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+// listModels returns the models the configured provider offers. Claude falls back to the
+// known Claude models; OpenAI-style providers that can't list theirs offer none, which
+// hides the model picker. Otherwise the current model is always included.
+func listModels(ctx context.Context, llm LLMClient, current string) []ModelOption {
+	var models []ModelOption
+	if lister, ok := llm.(ModelLister); ok {
+		listed, err := lister.ListModels(ctx)
+		if err != nil {
+			fmt.Printf("Warning: failed to list models: %v\n", err)
+		}
+		models = listed
+	}
+	if len(models) == 0 {
+		if _, ok := llm.(*OpenAIClient); ok {
+			return []ModelOption{}
+		}
+		models = knownModels
+	}
+	models = append([]ModelOption(nil), models...)
+	found := false
+	for _, model := range models {
+		found = found || model.ID == current
+	}
+	if !found && current != "" {
+		models = append(models, ModelOption{ID: current, DisplayName: current})
+	}
+	sort.SliceStable(models, func(i, j int) bool {
+		return models[i].ID == current && models[j].ID != current
+	})
+	return models
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSettingsStoreRoundTrip(t *testing.T) {
+	store := NewSettingsStore(filepath.Join(t.TempDir(), "slidepilot", "settings.json"))
+
+	settings, err := store.Load()
+	if err != nil || settings != DefaultSettings() {
+		t.Fatalf("expected the defaults without a file, got %+v, %v", settings, err)
+	}
+
+	temperature := 0.2
+	saved := Settings{Model: "claude-opus-4-0", MaxTokens: 16000, Temperature: &temperature}
+	if err := store.Save(saved); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Model != saved.Model || loaded.MaxTokens != saved.MaxTokens || loaded.Temperature == nil || *loaded.Temperature != 0.2 {
+		t.Errorf("expected %+v back, got %+v", saved, loaded)
+	}
+}
+
+func TestSettingsStoreFillsMissingFieldsWithDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(path, []byte(`{"model": "claude-3-5-haiku-latest"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := NewSettingsStore(path).Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.Model != "claude-3-5-haiku-latest" || settings.MaxTokens != DefaultSettings().MaxTokens {
+		t.Errorf("expected the saved model with the default max tokens, got %+v", settings)
+	}
+
+	// The provider's configured model is only a default for settings that name none
+	store := NewSettingsStore(path)
+	store.defaultModel = "gpt-4.1"
+	if settings, _ := store.Load(); settings.Model != "claude-3-5-haiku-latest" {
+		t.Errorf("expected the saved model to win over the default, got %s", settings.Model)
+	}
+	os.WriteFile(path, []byte(`{"max_tokens": 2048}`), 0644)
+	if settings, _ := store.Load(); settings.Model != "gpt-4.1" {
+		t.Errorf("expected the provider's default model, got %s", settings.Model)
+	}
+}
+
+func TestUpdateSettingsAppliesToTheNextRequest(t *testing.T) {
+	env := newTestEnv(t, textResponse("Done."))
+	env.loadFixture(t, "two_slides.pptx")
+	env.app.settings = NewSettingsStore(filepath.Join(t.TempDir(), "settings.json"))
+
+	temperature := 0.5
+	if _, err := env.app.UpdateSettings(Settings{Model: "claude-opus-4-0", MaxTokens: 32000, Temperature: &temperature}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Hi"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	params := env.llm.Requests[0]
+	if params.Model != "claude-opus-4-0" || params.MaxTokens != 32000 || params.Temperature.Value != 0.5 {
+		t.Errorf("expected the request to use the new settings, got model %s, max tokens %d, temperature %v",
+			params.Model, params.MaxTokens, params.Temperature)
+	}
+	if saved, err := env.app.settings.Load(); err != nil || saved.Model != "claude-opus-4-0" {
+		t.Errorf("expected the settings to be saved, got %+v, %v", saved, err)
+	}
+}
+
+func TestUpdateSettingsRejectsInvalidValues(t *testing.T) {
+	env := newTestEnv(t)
+	temperature := 1.5
+
+	for _, settings := range []Settings{
+		{Model: "", MaxTokens: 1024},
+		{Model: "claude-opus-4-0", MaxTokens: 0},
+		{Model: "claude-opus-4-0", MaxTokens: 1024, Temperature: &temperature},
+	} {
+		current, err := env.app.UpdateSettings(settings)
+		if err == nil {
+			t.Errorf("expected %+v to be rejected", settings)
+		}
+		if current != DefaultSettings() {
+			t.Errorf("expected the settings to stay unchanged, got %+v", current)
+		}
+	}
+}
+
+func TestListModelsFallsBackToKnownModels(t *testing.T) {
+	env := newTestEnv(t)
+	env.app.aiAgent.SetSettings(Settings{Model: "my-fine-tune", MaxTokens: 1024})
+
+	models := env.app.ListModels()
+	if len(models) != len(knownModels)+1 || models[0].ID != "my-fine-tune" {
+		t.Errorf("expected the known models with the current one first, got %+v", models)
+	}
+}
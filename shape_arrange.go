@@ -0,0 +1,336 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// shapeOrders are the stacking changes set_shape_order makes
+var shapeOrders = []string{"front", "back", "forward", "backward"}
+
+// maxGroupShapes caps how many shapes group_shapes and align_shapes take in one call
+const maxGroupShapes = 50
+
+// GroupShapesDefinition defines the group_shapes tool
+var GroupShapesDefinition = ToolDefinition{
+	Name: "group_shapes",
+	Description: `Group two or more shapes of a slide into one group shape, which then moves, resizes and stacks as a unit, e.g. an icon with its caption or the boxes and arrows of a diagram.
+
+Give the shapes by shape_id from read_slide. The group takes the place of the topmost grouped shape in the stacking order; the result gives its shape_id, which later calls use to move, reorder or ungroup it. read_slide lists a group as one shape of kind group.`,
+	InputSchema: GroupShapesInputSchema,
+	Function:    GroupShapes,
+	Mutating:    true,
+	Screenshot:  true,
+}
+
+type GroupShapesInput struct {
+	PresentationPath string   `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int      `json:"slide_number" jsonschema_description:"Slide containing the shapes (1-based indexing)"`
+	ShapeIDs         []string `json:"shape_ids" jsonschema_description:"shape_ids of the shapes to group, from read_slide; at least two"`
+	Name             string   `json:"name,omitempty" jsonschema_description:"(Optional) Name of the group, which becomes its shape_id; defaults to 'Group n'"`
+}
+
+var GroupShapesInputSchema = GenerateSchema[GroupShapesInput]()
+
+func GroupShapes(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	groupInput := GroupShapesInput{}
+	if err := json.Unmarshal(input, &groupInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if groupInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			groupInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if groupInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if len(groupInput.ShapeIDs) < 2 || len(groupInput.ShapeIDs) > maxGroupShapes {
+		return "", NewToolError(ErrCodeInvalidInput, "give between 2 and %d shape_ids to group", maxGroupShapes)
+	}
+	for i, shapeID := range groupInput.ShapeIDs {
+		if slices.Contains(groupInput.ShapeIDs[:i], shapeID) {
+			return "", NewToolError(ErrCodeInvalidInput, "shape_id %q is given twice", shapeID)
+		}
+	}
+	indexes, err := resolveShapeIDs(ctx, app, groupInput.PresentationPath, groupInput.SlideNumber, groupInput.ShapeIDs)
+	if err != nil {
+		return "", err
+	}
+	indexesJSON, _ := json.Marshal(indexes)
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_group_shapes.py",
+		groupInput.PresentationPath,
+		fmt.Sprintf("%d", groupInput.SlideNumber),
+		"group",
+		string(indexesJSON),
+		strings.TrimSpace(groupInput.Name))
+	if err != nil {
+		return "", scriptError("failed to group shapes", err, output)
+	}
+
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, groupInput.PresentationPath, groupInput.SlideNumber)
+
+	return marshalResult(result)
+}
+
+// UngroupShapesDefinition defines the ungroup_shapes tool
+var UngroupShapesDefinition = ToolDefinition{
+	Name: "ungroup_shapes",
+	Description: `Break a group shape apart into the shapes it contains, so each can be edited, moved or deleted on its own. The shapes keep their position on the slide and take the group's place in the stacking order.
+
+The result lists the shape_ids of the freed shapes; shape indexes after the group change, so read them from the result or read_slide rather than reusing old ones.`,
+	InputSchema: UngroupShapesInputSchema,
+	Function:    UngroupShapes,
+	Mutating:    true,
+}
+
+type UngroupShapesInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int    `json:"slide_number" jsonschema_description:"Slide containing the group (1-based indexing)"`
+	ShapeID          string `json:"shape_id,omitempty" jsonschema_description:"(Optional) shape_id of the group from read_slide; used instead of shape_index"`
+	ShapeIndex       int    `json:"shape_index" jsonschema_description:"Index of the group; ignored when shape_id is given"`
+}
+
+var UngroupShapesInputSchema = GenerateSchema[UngroupShapesInput]()
+
+func UngroupShapes(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	ungroupInput := UngroupShapesInput{}
+	if err := json.Unmarshal(input, &ungroupInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if ungroupInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			ungroupInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if ungroupInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if ungroupInput.ShapeID != "" {
+		index, err := resolveShapeID(ctx, app, ungroupInput.PresentationPath, ungroupInput.SlideNumber, ungroupInput.ShapeID)
+		if err != nil {
+			return "", err
+		}
+		ungroupInput.ShapeIndex = index
+	}
+	if ungroupInput.ShapeIndex < 0 {
+		return "", NewToolError(ErrCodeShapeNotFound, "shape_index must be 0 or greater")
+	}
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_group_shapes.py",
+		ungroupInput.PresentationPath,
+		fmt.Sprintf("%d", ungroupInput.SlideNumber),
+		"ungroup",
+		fmt.Sprintf("[%d]", ungroupInput.ShapeIndex))
+	if err != nil {
+		return "", scriptError("failed to ungroup shapes", err, output)
+	}
+
+	// Ungrouping doesn't change how the slide looks, so the previews stay as they are
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+	return marshalResult(result)
+}
+
+// SetShapeOrderDefinition defines the set_shape_order tool
+var SetShapeOrderDefinition = ToolDefinition{
+	Name: "set_shape_order",
+	Description: `Change where a shape sits in the slide's stacking order (z-order), which decides what covers what where shapes overlap:
+- "front": bring to front, above every other shape
+- "back": send to back, behind every other shape, e.g. a background picture or panel
+- "forward": bring forward one step, above the next shape up
+- "backward": send backward one step, below the next shape down
+
+Shapes are stacked in index order, so read_slide's shape_index is also the stacking position (0 is at the back) and indexes change with the order. The result gives the shape's new index; address shapes by shape_id in later calls.`,
+	InputSchema: SetShapeOrderInputSchema,
+	Function:    SetShapeOrder,
+	Mutating:    true,
+	Screenshot:  true,
+}
+
+type SetShapeOrderInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int    `json:"slide_number" jsonschema_description:"Slide containing the shape (1-based indexing)"`
+	ShapeID          string `json:"shape_id,omitempty" jsonschema_description:"(Optional) shape_id of the shape from read_slide; used instead of shape_index"`
+	ShapeIndex       int    `json:"shape_index" jsonschema_description:"Index of the shape; ignored when shape_id is given"`
+	Order            string `json:"order" jsonschema_description:"'front', 'back', 'forward' or 'backward'"`
+}
+
+var SetShapeOrderInputSchema = GenerateSchema[SetShapeOrderInput]()
+
+func SetShapeOrder(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	orderInput := SetShapeOrderInput{}
+	if err := json.Unmarshal(input, &orderInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if orderInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			orderInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if orderInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if !slices.Contains(shapeOrders, orderInput.Order) {
+		return "", NewToolError(ErrCodeInvalidInput, "order must be one of: %s", strings.Join(shapeOrders, ", "))
+	}
+	if orderInput.ShapeID != "" {
+		index, err := resolveShapeID(ctx, app, orderInput.PresentationPath, orderInput.SlideNumber, orderInput.ShapeID)
+		if err != nil {
+			return "", err
+		}
+		orderInput.ShapeIndex = index
+	}
+	if orderInput.ShapeIndex < 0 {
+		return "", NewToolError(ErrCodeShapeNotFound, "shape_index must be 0 or greater")
+	}
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_set_shape_order.py",
+		orderInput.PresentationPath,
+		fmt.Sprintf("%d", orderInput.SlideNumber),
+		fmt.Sprintf("%d", orderInput.ShapeIndex),
+		orderInput.Order)
+	if err != nil {
+		return "", scriptError("failed to change shape order", err, output)
+	}
+
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, orderInput.PresentationPath, orderInput.SlideNumber)
+
+	return marshalResult(result)
+}
+
+// shapeAlignments are the ways align_shapes lines shapes up
+var shapeAlignments = []string{"left", "center", "right", "top", "middle", "bottom", "distribute_horizontally", "distribute_vertically"}
+
+// AlignShapesDefinition defines the align_shapes tool
+var AlignShapesDefinition = ToolDefinition{
+	Name: "align_shapes",
+	Description: `Line up shapes of a slide in one call instead of computing positions for move_shape:
+- "left", "center", "right": align the shapes' left edges, horizontal centers or right edges
+- "top", "middle", "bottom": align their top edges, vertical centers or bottom edges
+- "distribute_horizontally", "distribute_vertically": space the shapes evenly, with equal gaps between them
+
+By default shapes are aligned to each other: to the outermost edge among them (e.g. the leftmost left edge), to the center of the area they cover, or, when distributing, between the two outermost shapes, which stay put. Set relative_to to 'slide' to align them to the slide's edges or center instead, or to spread them across the slide's full width or height; a single shape can be centered on the slide that way. Sizes don't change. Give the shapes by shape_id from read_slide.`,
+	InputSchema: AlignShapesInputSchema,
+	Function:    AlignShapes,
+	Mutating:    true,
+	Screenshot:  true,
+}
+
+type AlignShapesInput struct {
+	PresentationPath string   `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int      `json:"slide_number" jsonschema_description:"Slide containing the shapes (1-based indexing)"`
+	ShapeIDs         []string `json:"shape_ids" jsonschema_description:"shape_ids of the shapes to align, from read_slide"`
+	Alignment        string   `json:"alignment" jsonschema_description:"'left', 'center', 'right', 'top', 'middle', 'bottom', 'distribute_horizontally' or 'distribute_vertically'"`
+	RelativeTo       string   `json:"relative_to,omitempty" jsonschema_description:"(Optional) 'shapes' to align them to each other (default) or 'slide' to align them to the slide"`
+}
+
+var AlignShapesInputSchema = GenerateSchema[AlignShapesInput]()
+
+func AlignShapes(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	alignInput := AlignShapesInput{}
+	if err := json.Unmarshal(input, &alignInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if alignInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			alignInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if alignInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if !slices.Contains(shapeAlignments, alignInput.Alignment) {
+		return "", NewToolError(ErrCodeInvalidInput, "alignment must be one of: %s", strings.Join(shapeAlignments, ", "))
+	}
+	if alignInput.RelativeTo == "" {
+		alignInput.RelativeTo = "shapes"
+	}
+	if alignInput.RelativeTo != "shapes" && alignInput.RelativeTo != "slide" {
+		return "", NewToolError(ErrCodeInvalidInput, "relative_to must be 'shapes' or 'slide'")
+	}
+
+	// Aligning to each other takes two shapes, spacing them evenly three
+	minShapes := 1
+	if alignInput.RelativeTo == "shapes" {
+		minShapes = 2
+		if strings.HasPrefix(alignInput.Alignment, "distribute_") {
+			minShapes = 3
+		}
+	}
+	if len(alignInput.ShapeIDs) < minShapes || len(alignInput.ShapeIDs) > maxGroupShapes {
+		return "", NewToolError(ErrCodeInvalidInput, "%s relative to the %s takes between %d and %d shape_ids", alignInput.Alignment, alignInput.RelativeTo, minShapes, maxGroupShapes)
+	}
+	for i, shapeID := range alignInput.ShapeIDs {
+		if slices.Contains(alignInput.ShapeIDs[:i], shapeID) {
+			return "", NewToolError(ErrCodeInvalidInput, "shape_id %q is given twice", shapeID)
+		}
+	}
+	indexes, err := resolveShapeIDs(ctx, app, alignInput.PresentationPath, alignInput.SlideNumber, alignInput.ShapeIDs)
+	if err != nil {
+		return "", err
+	}
+	indexesJSON, _ := json.Marshal(indexes)
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_align_shapes.py",
+		alignInput.PresentationPath,
+		fmt.Sprintf("%d", alignInput.SlideNumber),
+		string(indexesJSON),
+		alignInput.Alignment,
+		alignInput.RelativeTo)
+	if err != nil {
+		return "", scriptError("failed to align shapes", err, output)
+	}
+
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, alignInput.PresentationPath, alignInput.SlideNumber)
+
+	return marshalResult(result)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestGroupShapesResolvesShapeIDs(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "mixed_shapes.pptx")
+	env.uno.Respond("uno_group_shapes.py", `{"success": true, "slide_number": 1, "shape_index": 6, "shape_id": "Logo", "grouped_shapes": 2}`)
+
+	output, err := GroupShapes(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "shape_ids": ["Title 1", "Picture 2 #2"], "name": " Logo "}`))
+	if err != nil {
+		t.Fatalf("GroupShapes failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_group_shapes.py")
+	if len(calls) != 1 || calls[0].Args[2] != "group" || calls[0].Args[3] != "[0,6]" || calls[0].Args[4] != "Logo" {
+		t.Fatalf("expected shapes 0 and 6 to be grouped as Logo, got %+v", calls)
+	}
+	var result EditResult
+	if err := json.Unmarshal([]byte(output), &result); err != nil || result.ShapeID != "Logo" {
+		t.Errorf("expected the group's shape_id, got %s (%v)", output, err)
+	}
+
+	for _, input := range []string{
+		`{"slide_number": 1, "shape_ids": ["Title 1"]}`,
+		`{"slide_number": 1, "shape_ids": ["Title 1", "Title 1"]}`,
+	} {
+		if _, err := GroupShapes(context.Background(), env.app, json.RawMessage(input)); toolErrorCode(err) != ErrCodeInvalidInput {
+			t.Errorf("expected %s for %s, got %v", ErrCodeInvalidInput, input, err)
+		}
+	}
+	_, err = GroupShapes(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "shape_ids": ["Title 1", "Picture 9"]}`))
+	if code := toolErrorCode(err); code != ErrCodeShapeNotFound {
+		t.Errorf("expected %s, got %s (%v)", ErrCodeShapeNotFound, code, err)
+	}
+	if calls := env.uno.Calls("uno_group_shapes.py"); len(calls) != 1 {
+		t.Errorf("script should only run for valid input, got %d calls", len(calls))
+	}
+}
+
+func TestUngroupShapes(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "mixed_shapes.pptx")
+	env.uno.Respond("uno_group_shapes.py", `{"success": true, "slide_number": 1, "shapes": [{"shape_index": 2, "shape_id": "Oval 4"}]}`)
+
+	if _, err := UngroupShapes(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "shape_id": "Group 3"}`)); err != nil {
+		t.Fatalf("UngroupShapes failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_group_shapes.py")
+	if len(calls) != 1 || calls[0].Args[2] != "ungroup" || calls[0].Args[3] != "[2]" {
+		t.Fatalf("expected shape 2 to be ungrouped, got %+v", calls)
+	}
+}
+
+func TestSetShapeOrder(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "mixed_shapes.pptx")
+	env.uno.Respond("uno_set_shape_order.py", `{"success": true, "slide_number": 1, "shape_index": 0, "shape_id": "Chart 10"}`)
+
+	if _, err := SetShapeOrder(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "shape_id": "Chart 10", "order": "back"}`)); err != nil {
+		t.Fatalf("SetShapeOrder failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_set_shape_order.py")
+	if len(calls) != 1 || calls[0].Args[2] != "7" || calls[0].Args[3] != "back" {
+		t.Fatalf("expected shape 7 to be sent to the back, got %+v", calls)
+	}
+
+	_, err := SetShapeOrder(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "shape_index": 1, "order": "top"}`))
+	if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+		t.Errorf("expected %s for an unknown order, got %s (%v)", ErrCodeInvalidInput, code, err)
+	}
+}
+
+func TestAlignShapes(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "mixed_shapes.pptx")
+	env.uno.Respond("uno_align_shapes.py", `{"success": true, "slide_number": 1, "shapes": []}`)
+
+	if _, err := AlignShapes(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "shape_ids": ["Table 6", "Chart 10"], "alignment": "top"}`)); err != nil {
+		t.Fatalf("AlignShapes failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_align_shapes.py")
+	if len(calls) != 1 || calls[0].Args[2] != "[3,7]" || calls[0].Args[3] != "top" || calls[0].Args[4] != "shapes" {
+		t.Fatalf("expected shapes 3 and 7 to be top-aligned to each other, got %+v", calls)
+	}
+
+	// A single shape can only be aligned to the slide, and spacing evenly takes three
+	if _, err := AlignShapes(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "shape_ids": ["Chart 10"], "alignment": "center", "relative_to": "slide"}`)); err != nil {
+		t.Fatalf("AlignShapes failed: %v", err)
+	}
+	for _, input := range []string{
+		`{"slide_number": 1, "shape_ids": ["Chart 10"], "alignment": "center"}`,
+		`{"slide_number": 1, "shape_ids": ["Table 6", "Chart 10"], "alignment": "distribute_horizontally"}`,
+		`{"slide_number": 1, "shape_ids": ["Table 6", "Chart 10"], "alignment": "justify"}`,
+		`{"slide_number": 1, "shape_ids": ["Table 6", "Chart 10"], "alignment": "left", "relative_to": "page"}`,
+	} {
+		if _, err := AlignShapes(context.Background(), env.app, json.RawMessage(input)); toolErrorCode(err) != ErrCodeInvalidInput {
+			t.Errorf("expected %s for %s, got %v", ErrCodeInvalidInput, input, err)
+		}
+	}
+	if calls := env.uno.Calls("uno_align_shapes.py"); len(calls) != 2 {
+		t.Errorf("script should only run for valid input, got %d calls", len(calls))
+	}
+}
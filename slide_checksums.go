@@ -0,0 +1,165 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Incremental export skips slides whose rendered content can't have changed. After every
+// export the checksum of each exported slide is recorded next to its image, along with the
+// export options; the next export with the same options only re-renders slides whose
+// checksum differs or whose image is missing.
+
+// slideChecksumsFile holds the checksums of the slide images in a directory
+const slideChecksumsFile = "checksums.json"
+
+// slideChecksumManifest is the on-disk record of what the slide images in a directory show
+type slideChecksumManifest struct {
+	PresentationPath string        `json:"presentation_path"`
+	Options          ExportOptions `json:"options"`
+	Slides           []string      `json:"slides"` // By 0-based slide index; "" when unknown
+}
+
+// slideChecksums fingerprints what each slide renders from: the slide part and every part
+// it references directly or indirectly (layout, master, theme, media), the slide size, and
+// the slide's position, which slide number fields show. Parts are identified by the CRC32
+// and size recorded in the zip directory, so no part has to be decompressed. Speaker
+// notes and links to other slides are left out since they don't change how the slide looks.
+func slideChecksums(presentationPath string) ([]string, error) {
+	pkg, err := openPPTX(presentationPath)
+	if err != nil {
+		return nil, err
+	}
+	defer pkg.Close()
+
+	var presentation struct {
+		SlideSize struct {
+			Width  string `xml:"cx,attr"`
+			Height string `xml:"cy,attr"`
+		} `xml:"sldSz"`
+	}
+	if err := pkg.decode("ppt/presentation.xml", &presentation); err != nil {
+		return nil, err
+	}
+
+	checksums := make([]string, len(pkg.slides))
+	for i, slide := range pkg.slides {
+		parts, err := pkg.renderedParts(slide)
+		if err != nil {
+			return nil, err
+		}
+
+		hash := sha256.New()
+		fmt.Fprintf(hash, "slide %d size %sx%s\n", i+1, presentation.SlideSize.Width, presentation.SlideSize.Height)
+		for _, partName := range parts {
+			file := pkg.parts[partName]
+			fmt.Fprintf(hash, "%s %08x %d\n", partName, file.CRC32, file.UncompressedSize64)
+		}
+		checksums[i] = hex.EncodeToString(hash.Sum(nil))
+	}
+	return checksums, nil
+}
+
+// renderedParts returns the sorted names of the parts a slide is rendered from,
+// including the .rels parts that tie them together
+func (p *pptxPackage) renderedParts(slidePart string) ([]string, error) {
+	seen := map[string]bool{}
+	queue := []string{slidePart}
+	for len(queue) > 0 {
+		partName := queue[0]
+		queue = queue[1:]
+		if seen[partName] {
+			continue
+		}
+		if _, ok := p.parts[partName]; !ok {
+			continue
+		}
+		seen[partName] = true
+
+		dir, base := path.Split(partName)
+		if relsName := dir + "_rels/" + base + ".rels"; p.parts[relsName] != nil {
+			seen[relsName] = true
+		}
+
+		rels, err := p.relationships(partName)
+		if err != nil {
+			return nil, err
+		}
+		for _, target := range rels {
+			if strings.HasPrefix(target, "ppt/notesSlides/") || (strings.HasPrefix(target, "ppt/slides/") && target != slidePart) {
+				continue
+			}
+			queue = append(queue, target)
+		}
+	}
+
+	parts := make([]string, 0, len(seen))
+	for partName := range seen {
+		parts = append(parts, partName)
+	}
+	sort.Strings(parts)
+	return parts, nil
+}
+
+// readSlideChecksums returns the recorded checksums of the images in dir, or nil when
+// they were rendered from another presentation or with other options, or nothing was
+// recorded
+func readSlideChecksums(dir, presentationPath string, options ExportOptions) []string {
+	data, err := os.ReadFile(filepath.Join(dir, slideChecksumsFile))
+	if err != nil {
+		return nil
+	}
+	var manifest slideChecksumManifest
+	if err := json.Unmarshal(data, &manifest); err != nil || manifest.PresentationPath != presentationPath || manifest.Options != options {
+		return nil
+	}
+	return manifest.Slides
+}
+
+// writeSlideChecksums records the checksums of the images in dir
+func writeSlideChecksums(dir, presentationPath string, options ExportOptions, checksums []string) error {
+	data, err := json.Marshal(slideChecksumManifest{PresentationPath: presentationPath, Options: options, Slides: checksums})
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, slideChecksumsFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, slideChecksumsFile))
+}
+
+// changedSlides returns the 0-based indexes in first..last whose image in dir is
+// missing or was rendered from different content than the current checksums
+func changedSlides(dir string, recorded, current []string, first, last int, ext string) []int {
+	var changed []int
+	for i := first; i <= last && i < len(current); i++ {
+		if i < len(recorded) && recorded[i] == current[i] {
+			if _, err := os.Stat(slideImagePath(dir, i, ext)); err == nil {
+				continue
+			}
+		}
+		changed = append(changed, i)
+	}
+	return changed
+}
+
+// updateSlideChecksums records the current checksums of the exported slides, keeping the
+// recorded ones of the other slides and dropping slides past the end of the deck
+func updateSlideChecksums(dir, presentationPath string, options ExportOptions, recorded, current []string, exported []int) {
+	checksums := make([]string, len(current))
+	copy(checksums, recorded)
+	for _, i := range exported {
+		checksums[i] = current[i]
+	}
+	if err := writeSlideChecksums(dir, presentationPath, options, checksums); err != nil {
+		fmt.Printf("Warning: Failed to record slide checksums: %v\n", err)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// After a tool edits a slide, a screenshot of that slide is attached to the tool result so
+// the model can check the layout itself instead of trusting the script's success message.
+
+// visionFeedbackEnv turns slide screenshots in tool results off when set to 0 or false
+const visionFeedbackEnv = "SLIDEPILOT_VISION_FEEDBACK"
+
+// maxScreenshotBytes keeps screenshots within the API's 5 MB image limit
+const maxScreenshotBytes = 5 * 1024 * 1024
+
+// visionFeedbackEnabled reports whether edited slides are shown to the model
+func visionFeedbackEnabled() bool {
+	value := os.Getenv(visionFeedbackEnv)
+	return value != "0" && value != "false"
+}
+
+// feedbackSlideNumber returns the slide a tool call changed: slide_number from the input,
+// or new_slide_number from the result data for tools that create a slide. Zero means none.
+func feedbackSlideNumber(input json.RawMessage, response string) int {
+	var target struct {
+		SlideNumber int `json:"slide_number"`
+	}
+	if json.Unmarshal(input, &target) == nil && target.SlideNumber > 0 {
+		return target.SlideNumber
+	}
+	var created struct {
+		Data struct {
+			NewSlideNumber int `json:"new_slide_number"`
+		} `json:"data"`
+	}
+	if json.Unmarshal([]byte(response), &created) == nil && created.Data.NewSlideNumber > 0 {
+		return created.Data.NewSlideNumber
+	}
+	return 0
+}
+
+// slideScreenshot returns the preview image of a slide of the loaded presentation,
+// rendering it now unless the existing preview is already newer than the file. The slide
+// is dropped from the pending exports, since it no longer needs re-rendering at turn end.
+func slideScreenshot(ctx context.Context, app *App, presentationPath string, slideNumber int) (string, error) {
+	if presentationPath != app.presentationPath() {
+		return "", fmt.Errorf("%s is not the loaded presentation", presentationPath)
+	}
+
+	index := slideNumber - 1
+	preview := slidePreviewPath("slides", index)
+	if previewIsFresh(preview, presentationPath) {
+		return preview, nil
+	}
+
+	if _, err := convertSlideRange(ctx, app, presentationPath, "slides", index, index); err != nil {
+		return "", err
+	}
+	if app.exports != nil {
+		app.exports.ForgetRange(presentationPath, index, index)
+	}
+	return preview, nil
+}
+
+// previewIsFresh reports whether a preview image was rendered after the presentation was last saved
+func previewIsFresh(preview, presentationPath string) bool {
+	previewInfo, err := os.Stat(preview)
+	if err != nil {
+		return false
+	}
+	deckInfo, err := os.Stat(presentationPath)
+	if err != nil {
+		return false
+	}
+	return !previewInfo.ModTime().Before(deckInfo.ModTime())
+}
+
+// toolResultWithScreenshot builds a successful tool result carrying the slide's screenshot
+// after the JSON result
+func toolResultWithScreenshot(id, response, imagePath string, slideNumber int) (anthropic.ContentBlockParamUnion, error) {
+	data, err := os.ReadFile(imagePath)
+	if err != nil {
+		return anthropic.ContentBlockParamUnion{}, fmt.Errorf("failed to read slide preview: %v", err)
+	}
+	if len(data) > maxScreenshotBytes {
+		return anthropic.ContentBlockParamUnion{}, fmt.Errorf("slide preview is too large to attach (%d bytes)", len(data))
+	}
+
+	image := anthropic.NewImageBlockBase64(imageMimeType(imagePath), base64.StdEncoding.EncodeToString(data))
+	result := anthropic.NewToolResultBlock(id, response, false)
+	result.OfToolResult.Content = append(result.OfToolResult.Content,
+		anthropic.ToolResultBlockParamContentUnion{OfText: &anthropic.TextBlockParam{
+			Text: fmt.Sprintf("Screenshot of slide %d after this change. Check it for overlapping, cut-off or misplaced content before reporting success.", slideNumber),
+		}},
+		anthropic.ToolResultBlockParamContentUnion{OfImage: image.OfImage},
+	)
+	return result, nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// slideImageRoute is the asset server path slide previews are streamed from
+const slideImageRoute = "/slide-images/"
+
+// imageBufferPool reuses copy buffers across slide image requests
+var imageBufferPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 64*1024)
+		return &buf
+	},
+}
+
+// encodeBufferPool reuses the buffers data URIs are built in
+var encodeBufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// SlideImageHandler streams slide preview images from disk to the webview in fixed-size
+// chunks, so the frontend can use plain <img> URLs instead of base64 strings
+type SlideImageHandler struct {
+	Dir string
+}
+
+// NewSlideImageHandler serves images from the given slides directory
+func NewSlideImageHandler(dir string) *SlideImageHandler {
+	return &SlideImageHandler{Dir: dir}
+}
+
+func (h *SlideImageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, slideImageRoute) {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Only plain file names inside the slides directory are served
+	name := strings.TrimPrefix(r.URL.Path, slideImageRoute)
+	if name == "" || name != filepath.Base(name) {
+		http.NotFound(w, r)
+		return
+	}
+
+	file, err := os.Open(filepath.Join(h.Dir, name))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Previews are rewritten in place after edits, so a versioned URL names one rendering
+	// for good, while an unversioned one has to be checked every time
+	if r.URL.Query().Get("v") != "" {
+		w.Header().Set("Cache-Control", "private, max-age=31536000, immutable")
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	modified := info.ModTime().UTC().Truncate(time.Second)
+	w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+	if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !modified.After(since) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", imageMimeType(name))
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", info.Size()))
+
+	buf := imageBufferPool.Get().(*[]byte)
+	defer imageBufferPool.Put(buf)
+	io.CopyBuffer(w, file, *buf)
+}
+
+// slideImageURL returns the asset server URL for a slide image, versioned by modification
+// time so the webview reloads previews that were re-rendered
+func slideImageURL(slidesDir, slidePath string) (string, error) {
+	absDir, err := filepath.Abs(slidesDir)
+	if err != nil {
+		return "", err
+	}
+	absPath, err := filepath.Abs(slidePath)
+	if err != nil {
+		return "", err
+	}
+	if filepath.Dir(absPath) != absDir {
+		return "", fmt.Errorf("slide image is outside the slides directory: %s", slidePath)
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image file: %v", err)
+	}
+
+	return fmt.Sprintf("%s%s?v=%d", slideImageRoute, url.PathEscape(filepath.Base(absPath)), info.ModTime().UnixNano()), nil
+}
+
+// encodeImageDataURI streams an image file through a base64 encoder into a pooled buffer
+// sized up front, avoiding the intermediate copies of reading the whole file first
+func encodeImageDataURI(imagePath string) (string, error) {
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image file: %v", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to read image file: %v", err)
+	}
+
+	prefix := fmt.Sprintf("data:%s;base64,", imageMimeType(imagePath))
+	out := encodeBufferPool.Get().(*bytes.Buffer)
+	out.Reset()
+	defer encodeBufferPool.Put(out)
+	out.Grow(len(prefix) + base64.StdEncoding.EncodedLen(int(info.Size())))
+	out.WriteString(prefix)
+
+	buf := imageBufferPool.Get().(*[]byte)
+	defer imageBufferPool.Put(buf)
+
+	encoder := base64.NewEncoder(base64.StdEncoding, out)
+	if _, err := io.CopyBuffer(encoder, file, *buf); err != nil {
+		return "", fmt.Errorf("failed to read image file: %v", err)
+	}
+	encoder.Close()
+
+	return out.String(), nil
+}
+
+// imageMimeType determines the MIME type based on file extension
+func imageMimeType(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		return "image/png"
+	default:
+		return "image/jpeg"
+	}
+}
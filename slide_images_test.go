@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSlideImageHandlerStreamsPreviews(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	if _, err := convertSlides(context.Background(), env.app, env.app.presentationPath(), "slides"); err != nil {
+		t.Fatal(err)
+	}
+
+	url, err := env.app.GetSlideImageURL("slides/slide-001.jpg")
+	if err != nil {
+		t.Fatalf("GetSlideImageURL failed: %v", err)
+	}
+	if !strings.HasPrefix(url, "/slide-images/slide-001.jpg?v=") {
+		t.Errorf("unexpected URL: %s", url)
+	}
+
+	recorder := httptest.NewRecorder()
+	NewSlideImageHandler("slides").ServeHTTP(recorder, httptest.NewRequest("GET", url, nil))
+	if recorder.Code != 200 || recorder.Body.String() != "fake jpeg" {
+		t.Fatalf("expected image body, got %d %q", recorder.Code, recorder.Body.String())
+	}
+	if recorder.Header().Get("Content-Type") != "image/jpeg" {
+		t.Errorf("unexpected content type: %s", recorder.Header().Get("Content-Type"))
+	}
+	if cache := recorder.Header().Get("Cache-Control"); !strings.Contains(cache, "immutable") {
+		t.Errorf("expected versioned URLs to be cached for good, got %q", cache)
+	}
+
+	// Revalidating an unversioned URL doesn't send the image again
+	request := httptest.NewRequest("GET", "/slide-images/slide-001.jpg", nil)
+	request.Header.Set("If-Modified-Since", recorder.Header().Get("Last-Modified"))
+	recorder = httptest.NewRecorder()
+	NewSlideImageHandler("slides").ServeHTTP(recorder, request)
+	if recorder.Code != 304 || recorder.Body.Len() != 0 {
+		t.Errorf("expected 304 without a body, got %d with %d bytes", recorder.Code, recorder.Body.Len())
+	}
+	if cache := recorder.Header().Get("Cache-Control"); cache != "no-cache" {
+		t.Errorf("expected unversioned URLs to be revalidated, got %q", cache)
+	}
+}
+
+func TestSlideImageHandlerRejectsTraversal(t *testing.T) {
+	newTestEnv(t)
+	os.WriteFile("secret.jpg", []byte("secret"), 0644)
+	os.MkdirAll("slides", 0755)
+
+	recorder := httptest.NewRecorder()
+	NewSlideImageHandler("slides").ServeHTTP(recorder, httptest.NewRequest("GET", "/slide-images/..%2Fsecret.jpg", nil))
+	if recorder.Code != 404 {
+		t.Fatalf("expected 404, got %d", recorder.Code)
+	}
+
+	if _, err := slideImageURL("slides", "secret.jpg"); err == nil {
+		t.Error("expected images outside the slides directory to be rejected")
+	}
+}
+
+func TestImageCacheIsBoundedByBytes(t *testing.T) {
+	env := newTestEnv(t)
+	env.converter.SlideCount = 12
+	env.loadFixture(t, "two_slides.pptx")
+	slides, err := convertSlides(context.Background(), env.app, env.app.presentationPath(), "slides")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const dataURI = "data:image/jpeg;base64,ZmFrZSBqcGVn"
+	env.app.imageCache = NewImageCache(8 * len(dataURI))
+
+	for _, slide := range slides {
+		got, err := env.app.GetSlideImageAsBase64(slide)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != dataURI {
+			t.Fatalf("unexpected data URI: %s", got)
+		}
+		// Keep the first slide in use so it outlives the others
+		env.app.GetSlideImageAsBase64(slides[0])
+	}
+	if env.app.imageCache.Len() != 8 || env.app.imageCache.Size() != 8*len(dataURI) {
+		t.Errorf("expected 8 cached images, got %d (%d bytes)", env.app.imageCache.Len(), env.app.imageCache.Size())
+	}
+	if _, ok := env.app.imageCache.Get(slides[0]); !ok {
+		t.Error("expected the most recently used image to stay cached")
+	}
+	if _, ok := env.app.imageCache.Get(slides[1]); ok {
+		t.Error("expected the least recently used image to be evicted")
+	}
+}
+
+func TestImageCacheOnlyReloadsChangedSlides(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	slides, err := convertSlides(context.Background(), env.app, env.app.presentationPath(), "slides")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, slide := range slides {
+		env.app.GetSlideImageAsBase64(slide)
+	}
+
+	// Re-render only the second slide, as a range export after an edit would
+	if _, err := convertSlideRange(context.Background(), env.app, env.app.presentationPath(), "slides", 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := env.app.imageCache.Get(slides[0]); !ok {
+		t.Error("expected the unchanged slide to stay cached")
+	}
+	if _, ok := env.app.imageCache.Get(slides[1]); ok {
+		t.Error("expected the re-rendered slide to miss the cache")
+	}
+	if dataURI, _ := env.app.GetSlideImageAsBase64(slides[1]); dataURI != "data:image/jpeg;base64,cmVuZGVyZWQgMQ==" {
+		t.Errorf("expected the new rendering, got %s", dataURI)
+	}
+}
+
+// Run with -race: bindings and the agent goroutine touch App state concurrently
+func TestAppStateConcurrentAccess(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	slides, err := convertSlides(context.Background(), env.app, env.app.presentationPath(), "slides")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			env.app.GetSlideImageAsBase64(slides[0])
+			env.app.GetSlideImageQuiet(slides[1])
+		}()
+		go func() {
+			defer wg.Done()
+			env.app.LoadPresentation(env.app.presentationPath())
+			env.app.ClearImageCache()
+		}()
+		go func() {
+			defer wg.Done()
+			env.app.HasPresentationLoaded()
+			env.app.GetCurrentPresentationName()
+			ListSlides(context.Background(), env.app, []byte(`{}`))
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,27 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+// configureSofficeCommand needs no setup on Windows; taskkill finds the children
+func configureSofficeCommand(cmd *exec.Cmd) {}
+
+// terminateProcess asks soffice and its children to exit
+func terminateProcess(cmd *exec.Cmd) error {
+	if output, err := exec.Command("taskkill", "/T", "/PID", strconv.Itoa(cmd.Process.Pid)).CombinedOutput(); err != nil {
+		return fmt.Errorf("taskkill failed: %v: %s", err, output)
+	}
+	return nil
+}
+
+// killProcess kills soffice and its children
+func killProcess(cmd *exec.Cmd) {
+	if err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run(); err != nil {
+		cmd.Process.Kill()
+	}
+}
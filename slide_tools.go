@@ -1,10 +1,20 @@
 package main
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net/url"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 // ListSlidesDefinition defines the list_slides tool
@@ -12,65 +22,99 @@ var ListSlidesDefinition = ToolDefinition{
 	Name: "list_slides",
 	Description: `List all slides in a PowerPoint presentation with basic information.
 
-Use this tool to get an overview of the presentation structure, including slide numbers, titles, and layout information. This is typically the first tool to use when working with a presentation.`,
+Use this tool to get an overview of the presentation structure, including slide numbers, titles, layout information and, in decks with sections, the section of each slide. This is typically the first tool to use when working with a presentation.
+
+Results are paged: at most 50 slides are returned per call. The response includes total_slides, and has_more/next_offset when more slides remain; pass next_offset as offset to continue. For large decks, set titles_only to get a compact outline first.`,
 	InputSchema: ListSlidesInputSchema,
 	Function:    ListSlides,
+	ReadOnly:    true,
 }
 
 type ListSlidesInput struct {
 	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Offset           int    `json:"offset,omitempty" jsonschema_description:"(Optional) Number of slides to skip before listing, defaults to 0"`
+	Limit            int    `json:"limit,omitempty" jsonschema_description:"(Optional) Maximum number of slides to return, defaults to 50 (max 200)"`
+	TitlesOnly       bool   `json:"titles_only,omitempty" jsonschema_description:"(Optional) Return only slide numbers and titles, defaults to false"`
 }
 
+// Page size bounds for list_slides, keeping large decks from flooding the model context
+const (
+	defaultListSlidesLimit = 50
+	maxListSlidesLimit     = 200
+)
+
 var ListSlidesInputSchema = GenerateSchema[ListSlidesInput]()
 
-func ListSlides(app *App, input json.RawMessage) (string, error) {
+func ListSlides(ctx context.Context, app *App, input json.RawMessage) (string, error) {
 	listSlidesInput := ListSlidesInput{}
 	err := json.Unmarshal(input, &listSlidesInput)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse input: %v", err)
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
 	}
 
 	// Use current presentation path if not provided
 	if listSlidesInput.PresentationPath == "" {
-		if app != nil && app.currentPresentationPath != "" {
-			listSlidesInput.PresentationPath = app.currentPresentationPath
-			fmt.Printf("Using current presentation path: %s\n", app.currentPresentationPath)
+		if currentPath := app.presentationPath(); currentPath != "" {
+			listSlidesInput.PresentationPath = currentPath
+			fmt.Printf("Using current presentation path: %s\n", currentPath)
 		} else {
-			return "", fmt.Errorf("no presentation loaded - please load a presentation first")
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
 		}
 	}
 
-	fmt.Printf("Listing slides in: %s\n", listSlidesInput.PresentationPath)
+	if listSlidesInput.Offset < 0 {
+		return "", NewToolError(ErrCodeInvalidInput, "offset must be 0 or greater")
+	}
+	if listSlidesInput.Limit < 0 {
+		return "", NewToolError(ErrCodeInvalidInput, "limit must be 1 or greater")
+	}
+	limit := listSlidesInput.Limit
+	if limit == 0 {
+		limit = defaultListSlidesLimit
+	}
+	if limit > maxListSlidesLimit {
+		limit = maxListSlidesLimit
+	}
+
+	fmt.Printf("Listing slides in: %s (offset %d, limit %d)\n", listSlidesInput.PresentationPath, listSlidesInput.Offset, limit)
 
 	// Check if file exists
 	if _, err := os.Stat(listSlidesInput.PresentationPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("presentation file not found: %s", listSlidesInput.PresentationPath)
+		return "", NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", listSlidesInput.PresentationPath)
+	}
+
+	// Read .pptx packages directly; other formats, or packages the reader can't parse, go through LibreOffice
+	if isOOXMLPackage(listSlidesInput.PresentationPath) {
+		result, err := listSlidesNative(listSlidesInput.PresentationPath, listSlidesInput.Offset, limit, listSlidesInput.TitlesOnly)
+		if err == nil {
+			return result, nil
+		}
+		fmt.Printf("Native slide reader failed, falling back to LibreOffice: %v\n", err)
 	}
 
 	// Call Python UNO script
-	cmd := exec.Command("python3", "scripts/uno_list_slides.py", listSlidesInput.PresentationPath)
-	output, err := cmd.CombinedOutput()
+	output, err := runUnoScript(ctx, app, "uno_list_slides.py", listSlidesInput.PresentationPath,
+		fmt.Sprintf("%d", listSlidesInput.Offset), fmt.Sprintf("%d", limit), fmt.Sprintf("%t", listSlidesInput.TitlesOnly))
 	if err != nil {
-		return "", fmt.Errorf("failed to list slides: %v\nOutput: %s", err, string(output))
+		return "", scriptError("failed to list slides", err, output)
 	}
 
-	// Validate that the output is valid JSON
-	var result interface{}
-	if err := json.Unmarshal(output, &result); err != nil {
-		return "", fmt.Errorf("invalid JSON output from UNO script: %v", err)
+	result, err := decodeScriptResult[SlideList](output)
+	if err != nil {
+		return "", err
 	}
-
-	return string(output), nil
+	return marshalResult(result)
 }
 
 // ReadSlideDefinition defines the read_slide tool
 var ReadSlideDefinition = ToolDefinition{
 	Name: "read_slide",
-	Description: `Read detailed content from a specific slide including all text shapes and their content.
+	Description: `Read detailed content from a specific slide: every shape with its text, position and size, including pictures, tables and charts.
 
-Use this tool to get detailed information about a specific slide's content, including shape indices, types, and text content. This is essential for understanding slide structure before making edits.`,
+Use this tool to get detailed information about a specific slide's content, including shape indices, types, and text content. This is essential for understanding slide structure before making edits. Each shape has a kind (shape, picture, table, chart, group, connector or graphic) and a shape_id that, unlike shape_index, doesn't change when other shapes are added or deleted. Pictures report their image's pixel size and alt text, tables their cell texts, and charts their type, title, categories and series values. Text inside pictures isn't read; use ocr_slide for screenshots and scans.`,
 	InputSchema: ReadSlideInputSchema,
 	Function:    ReadSlide,
+	ReadOnly:    true,
 }
 
 type ReadSlideInput struct {
@@ -80,42 +124,120 @@ type ReadSlideInput struct {
 
 var ReadSlideInputSchema = GenerateSchema[ReadSlideInput]()
 
-func ReadSlide(app *App, input json.RawMessage) (string, error) {
+func ReadSlide(ctx context.Context, app *App, input json.RawMessage) (string, error) {
 	readSlideInput := ReadSlideInput{}
 	err := json.Unmarshal(input, &readSlideInput)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse input: %v", err)
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
 	}
 
 	// Use current presentation path if not provided
 	if readSlideInput.PresentationPath == "" {
-		if app != nil && app.currentPresentationPath != "" {
-			readSlideInput.PresentationPath = app.currentPresentationPath
+		if currentPath := app.presentationPath(); currentPath != "" {
+			readSlideInput.PresentationPath = currentPath
 		} else {
-			return "", fmt.Errorf("no presentation loaded - please load a presentation first")
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
 		}
 	}
 
 	if readSlideInput.SlideNumber < 1 {
-		return "", fmt.Errorf("slide_number must be 1 or greater")
+		return "", NewToolError(ErrCodeSlideOutOfRange, "slide_number must be 1 or greater")
 	}
 
 	fmt.Printf("Reading slide %d from: %s\n", readSlideInput.SlideNumber, readSlideInput.PresentationPath)
 
+	// Read .pptx packages directly; other formats, or packages the reader can't parse, go through LibreOffice
+	if isOOXMLPackage(readSlideInput.PresentationPath) {
+		result, err := readSlideNative(readSlideInput.PresentationPath, readSlideInput.SlideNumber)
+		if err == nil {
+			return result, nil
+		}
+		if errors.Is(err, errSlideOutOfRange) {
+			return "", NewToolError(ErrCodeSlideOutOfRange, "failed to read slide: %v", err)
+		}
+		if errors.Is(err, os.ErrNotExist) {
+			return "", NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", readSlideInput.PresentationPath)
+		}
+		fmt.Printf("Native slide reader failed, falling back to LibreOffice: %v\n", err)
+	}
+
 	// Call Python UNO script
-	cmd := exec.Command("python3", "scripts/uno_read_slide.py", readSlideInput.PresentationPath, fmt.Sprintf("%d", readSlideInput.SlideNumber))
-	output, err := cmd.CombinedOutput()
+	output, err := runUnoScript(ctx, app, "uno_read_slide.py", readSlideInput.PresentationPath, fmt.Sprintf("%d", readSlideInput.SlideNumber))
 	if err != nil {
-		return "", fmt.Errorf("failed to read slide: %v\nOutput: %s", err, string(output))
+		return "", scriptError("failed to read slide", err, output)
 	}
 
-	// Validate that the output is valid JSON
-	var result interface{}
-	if err := json.Unmarshal(output, &result); err != nil {
-		return "", fmt.Errorf("invalid JSON output from UNO script: %v", err)
+	result, err := decodeScriptResult[SlideDetail](output)
+	if err != nil {
+		return "", err
 	}
+	return marshalResult(result)
+}
 
-	return string(output), nil
+// resolveShapeID returns the current index of the shape with a shape_id as reported by
+// read_slide. IDs survive shapes being added or deleted; indexes don't.
+func resolveShapeID(ctx context.Context, app *App, presentationPath string, slideNumber int, shapeID string) (int, error) {
+	indexes, err := resolveShapeIDs(ctx, app, presentationPath, slideNumber, []string{shapeID})
+	if err != nil {
+		return 0, err
+	}
+	return indexes[0], nil
+}
+
+// resolveShapeIDs returns the current indexes of several shapes of a slide, reading the
+// slide once
+func resolveShapeIDs(ctx context.Context, app *App, presentationPath string, slideNumber int, shapeIDs []string) ([]int, error) {
+	ids, err := slideShapeIDs(ctx, app, presentationPath, slideNumber)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]int, len(shapeIDs))
+	for i, shapeID := range shapeIDs {
+		index := slices.Index(ids, shapeID)
+		if index < 0 {
+			return nil, NewToolError(ErrCodeShapeNotFound, "no shape with shape_id %q on slide %d; use read_slide to see the current shapes", shapeID, slideNumber).
+				WithDetail("shape_ids", ids)
+		}
+		indexes[i] = index
+	}
+	return indexes, nil
+}
+
+// slideShapeIDs returns the shape_ids of a slide's shapes in index order
+func slideShapeIDs(ctx context.Context, app *App, presentationPath string, slideNumber int) ([]string, error) {
+	if isOOXMLPackage(presentationPath) {
+		pkg, err := openPPTX(presentationPath)
+		if err != nil {
+			return nil, NewToolError(ErrCodeInvalidInput, "failed to read presentation: %v", err)
+		}
+		defer pkg.Close()
+		shapes, err := pkg.SlideShapes(slideNumber)
+		if errors.Is(err, errSlideOutOfRange) {
+			return nil, NewToolError(ErrCodeSlideOutOfRange, "failed to find shape: %v", err)
+		}
+		if err != nil {
+			return nil, NewToolError(ErrCodeInvalidInput, "failed to read slide %d: %v", slideNumber, err)
+		}
+		names := make([]string, len(shapes))
+		for i, shape := range shapes {
+			names[i] = shape.Name
+		}
+		return shapeIDs(names), nil
+	}
+
+	output, err := runUnoScript(ctx, app, "uno_read_slide.py", presentationPath, fmt.Sprintf("%d", slideNumber))
+	if err != nil {
+		return nil, scriptError("failed to read slide", err, output)
+	}
+	slide, err := decodeScriptResult[SlideDetail](output)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, shape := range slide.Shapes {
+		ids = append(ids, shape.ShapeID)
+	}
+	return ids, nil
 }
 
 // EditSlideTextDefinition defines the edit_slide_text tool
@@ -126,6 +248,7 @@ var EditSlideTextDefinition = ToolDefinition{
 Can target by shape index, shape type, or replace specific text. This tool allows precise editing of slide content including titles, text boxes, and bullet points.
 
 Target types:
+- "shape_id": Edit specific shape by the shape_id read_slide reports (preferred)
 - "shape_index": Edit specific shape by index (0, 1, 2, ...)
 - "shape_type": Edit by type ("title", "content", "text_box")
 - "text_replace": Replace specific text (requires old_text)
@@ -134,65 +257,84 @@ Target types:
   
 IMPORTANT for bullet_list: Provide text with each line representing a bullet point, 
 but WITHOUT bullet characters (•, *, -). LibreOffice will add proper bullets automatically.
-Example: "First point\nSecond point\nThird point" (not "• First point\n• Second point")`,
+Example: "First point\nSecond point\nThird point" (not "• First point\n• Second point")
+
+Set autofit to 'shrink' when the new text may be longer than the shape has room for; check_text_overflow finds text that doesn't fit.`,
 	InputSchema: EditSlideTextInputSchema,
 	Function:    EditSlideText,
+	Mutating:    true,
+	Screenshot:  true,
 }
 
 type EditSlideTextInput struct {
 	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
 	SlideNumber      int    `json:"slide_number" jsonschema_description:"Slide number to edit (1-based indexing)"`
-	TargetType       string `json:"target_type" jsonschema_description:"How to target: 'shape_index', 'shape_type', 'bullet_point', 'bullet_list', or 'text_replace'"`
-	TargetValue      string `json:"target_value" jsonschema_description:"Shape index (0,1,2...), shape type ('title','content','text_box'), bullet index, or text to find"`
+	TargetType       string `json:"target_type" jsonschema_description:"How to target: 'shape_id', 'shape_index', 'shape_type', 'bullet_point', 'bullet_list', or 'text_replace'"`
+	TargetValue      string `json:"target_value" jsonschema_description:"Shape ID, shape index (0,1,2...), shape type ('title','content','text_box'), bullet index, or text to find"`
 	NewText          string `json:"new_text" jsonschema_description:"New text content to set"`
 	OldText          string `json:"old_text,omitempty" jsonschema_description:"(Optional) For text_replace mode, the exact text to replace"`
+	Autofit          string `json:"autofit,omitempty" jsonschema_description:"(Optional) How the shape fits its text: 'shrink' scales text down to fit, 'resize' grows the shape, 'none' lets text overflow; omit to leave it as is"`
 }
 
 var EditSlideTextInputSchema = GenerateSchema[EditSlideTextInput]()
 
-func EditSlideText(app *App, input json.RawMessage) (string, error) {
+func EditSlideText(ctx context.Context, app *App, input json.RawMessage) (string, error) {
 	editInput := EditSlideTextInput{}
 	err := json.Unmarshal(input, &editInput)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse input: %v", err)
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
 	}
 
 	// Use current presentation path if not provided
 	if editInput.PresentationPath == "" {
-		if app != nil && app.currentPresentationPath != "" {
-			editInput.PresentationPath = app.currentPresentationPath
-			fmt.Printf("EditSlideText using current presentation path: %s\n", app.currentPresentationPath)
+		if currentPath := app.presentationPath(); currentPath != "" {
+			editInput.PresentationPath = currentPath
+			fmt.Printf("EditSlideText using current presentation path: %s\n", currentPath)
 		} else {
-			return "", fmt.Errorf("no presentation loaded - please load a presentation first")
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
 		}
 	}
 
 	// Check if file exists and is accessible
 	if _, err := os.Stat(editInput.PresentationPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("presentation file not found: %s", editInput.PresentationPath)
+		return "", NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", editInput.PresentationPath)
 	}
 
 	fmt.Printf("EditSlideText operating on: %s (slide %d, target: %s)\n",
 		editInput.PresentationPath, editInput.SlideNumber, editInput.TargetType)
 
 	if editInput.SlideNumber < 1 {
-		return "", fmt.Errorf("slide_number must be 1 or greater")
+		return "", NewToolError(ErrCodeSlideOutOfRange, "slide_number must be 1 or greater")
 	}
 
 	if editInput.TargetType == "" {
-		return "", fmt.Errorf("target_type is required")
+		return "", NewToolError(ErrCodeInvalidInput, "target_type is required")
 	}
 
 	if editInput.TargetValue == "" {
-		return "", fmt.Errorf("target_value is required")
+		return "", NewToolError(ErrCodeInvalidInput, "target_value is required")
 	}
 
 	if editInput.NewText == "" {
-		return "", fmt.Errorf("new_text is required")
+		return "", NewToolError(ErrCodeInvalidInput, "new_text is required")
 	}
 
 	if editInput.TargetType == "text_replace" && editInput.OldText == "" {
-		return "", fmt.Errorf("old_text is required for text_replace mode")
+		return "", NewToolError(ErrCodeInvalidInput, "old_text is required for text_replace mode")
+	}
+
+	if !validAutofit(editInput.Autofit) {
+		return "", NewToolError(ErrCodeInvalidInput, "autofit must be 'shrink', 'resize' or 'none'")
+	}
+
+	// The script addresses shapes by index
+	if editInput.TargetType == "shape_id" {
+		index, err := resolveShapeID(ctx, app, editInput.PresentationPath, editInput.SlideNumber, editInput.TargetValue)
+		if err != nil {
+			return "", err
+		}
+		editInput.TargetType = "shape_index"
+		editInput.TargetValue = fmt.Sprintf("%d", index)
 	}
 
 	fmt.Printf("Editing slide %d: %s=%s -> '%s'\n",
@@ -200,7 +342,6 @@ func EditSlideText(app *App, input json.RawMessage) (string, error) {
 
 	// Build command arguments
 	args := []string{
-		"scripts/uno_edit_slide.py",
 		editInput.PresentationPath,
 		fmt.Sprintf("%d", editInput.SlideNumber),
 		editInput.TargetType,
@@ -208,125 +349,158 @@ func EditSlideText(app *App, input json.RawMessage) (string, error) {
 		editInput.NewText,
 	}
 
-	// Add old_text if provided
-	if editInput.OldText != "" {
+	// Add old_text if provided; autofit follows it
+	if editInput.OldText != "" || editInput.Autofit != "" {
 		args = append(args, editInput.OldText)
 	}
+	if editInput.Autofit != "" {
+		args = append(args, editInput.Autofit)
+	}
 
-	// Call Python UNO script
-	cmd := exec.Command("python3", args...)
-	
 	// Log working directory for debugging
 	wd, _ := os.Getwd()
 	fmt.Printf("EditSlideText working directory: %s\n", wd)
-	fmt.Printf("EditSlideText command: python3 %v\n", args)
-	
-	output, err := cmd.CombinedOutput()
+	fmt.Printf("EditSlideText command: uno_edit_slide.py %v\n", args)
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_edit_slide.py", args...)
 	if err != nil {
-		return "", fmt.Errorf("failed to edit slide: %v\nOutput: %s", err, string(output))
+		return "", scriptError("failed to edit slide", err, output)
 	}
 
-	// Validate that the output is valid JSON
-	var result interface{}
-	if err := json.Unmarshal(output, &result); err != nil {
-		return "", fmt.Errorf("invalid JSON output from UNO script: %v", err)
-	}
-
-	// Parse result to check if edit was successful
-	var editResult map[string]interface{}
-	if err := json.Unmarshal(output, &editResult); err == nil {
-		if success, ok := editResult["success"].(bool); ok && success {
-			// Auto-export the edited slide to update UI
-			fmt.Printf("EditSlideText: Auto-exporting slide %d to update UI\n", editInput.SlideNumber)
-			exportInput := ExportSlidesInput{
-				PresentationPath: editInput.PresentationPath,
-				SlideNumbers:     []int{editInput.SlideNumber},
-				OutputDir:        "slides",
-			}
-			exportInputJSON, _ := json.Marshal(exportInput)
-			_, exportErr := ExportSlides(app, exportInputJSON)
-			if exportErr != nil {
-				fmt.Printf("Warning: Failed to auto-export slide after edit: %v\n", exportErr)
-			}
-		}
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
 	}
 
-	return string(output), nil
+	// Queue the edited slide for export; repeated edits in a turn are coalesced
+	fmt.Printf("EditSlideText: Scheduling export of slide %d to update UI\n", editInput.SlideNumber)
+	schedulePreviewExport(ctx, app, editInput.PresentationPath, editInput.SlideNumber)
+
+	return marshalResult(result)
 }
 
 // ExportSlidesDefinition defines the export_slides tool
 var ExportSlidesDefinition = ToolDefinition{
 	Name: "export_slides",
-	Description: `Export slides as JPEG images for preview or verification.
+	Description: `Export slides as images for preview, verification, or use outside the app.
 
-Use this tool to generate visual representations of slides, especially useful after making edits to verify changes. Can export all slides or specific slides.`,
+Use this tool to generate visual representations of slides, especially useful after making edits to verify changes. Can export all slides or specific slides; slides that haven't changed since their last export are not rendered again.
+
+By default slides are rendered as 150 DPI JPEGs into the app's preview directory. Set format (jpeg, png, webp), dpi, quality, or max_width/max_height for other uses, e.g. dpi 300 for print-quality PNGs or max_width 320 for thumbnails; custom exports are written to output_dir (default 'exports/').`,
 	InputSchema: ExportSlidesInputSchema,
 	Function:    ExportSlides,
+	Timeout:     5 * time.Minute, // Renders every slide of large decks
+	ReadOnly:    true,
+	WritesFiles: true,
 }
 
 type ExportSlidesInput struct {
 	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
 	SlideNumbers     []int  `json:"slide_numbers,omitempty" jsonschema_description:"Specific slides to export (optional, defaults to all slides)"`
-	OutputDir        string `json:"output_dir,omitempty" jsonschema_description:"Directory to save images (optional, defaults to 'slides/')"`
+	OutputDir        string `json:"output_dir,omitempty" jsonschema_description:"Directory to save images (optional, defaults to 'slides/', or 'exports/' when any image option is set)"`
+	Format           string `json:"format,omitempty" jsonschema_description:"(Optional) Image format: 'jpeg', 'png', or 'webp', defaults to 'jpeg'"`
+	DPI              int    `json:"dpi,omitempty" jsonschema_description:"(Optional) Resolution in pixels per inch of the slide, 10-1200, defaults to 150"`
+	Quality          int    `json:"quality,omitempty" jsonschema_description:"(Optional) JPEG/WebP quality, 1-100, defaults to 90"`
+	MaxWidth         int    `json:"max_width,omitempty" jsonschema_description:"(Optional) Maximum image width in pixels; larger images are scaled down keeping the aspect ratio"`
+	MaxHeight        int    `json:"max_height,omitempty" jsonschema_description:"(Optional) Maximum image height in pixels; larger images are scaled down keeping the aspect ratio"`
 }
 
 var ExportSlidesInputSchema = GenerateSchema[ExportSlidesInput]()
 
-func ExportSlides(app *App, input json.RawMessage) (string, error) {
+func ExportSlides(ctx context.Context, app *App, input json.RawMessage) (string, error) {
 	exportInput := ExportSlidesInput{}
 	err := json.Unmarshal(input, &exportInput)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse input: %v", err)
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
 	}
 
 	// Use current presentation path if not provided
 	if exportInput.PresentationPath == "" {
-		if app != nil && app.currentPresentationPath != "" {
-			exportInput.PresentationPath = app.currentPresentationPath
+		if currentPath := app.presentationPath(); currentPath != "" {
+			exportInput.PresentationPath = currentPath
 		} else {
-			return "", fmt.Errorf("no presentation loaded - please load a presentation first")
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
 		}
 	}
 
-	// Set default output directory
+	options := ExportOptions{
+		Format:    exportInput.Format,
+		DPI:       exportInput.DPI,
+		Quality:   exportInput.Quality,
+		MaxWidth:  exportInput.MaxWidth,
+		MaxHeight: exportInput.MaxHeight,
+	}.WithDefaults()
+	if err := options.Validate(); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "%v", err)
+	}
+	custom := options != previewExportOptions
+
+	// Set default output directory; the previews must stay 150 DPI JPEGs for the UI
 	outputDir := exportInput.OutputDir
 	if outputDir == "" {
 		outputDir = "slides"
+		if custom {
+			outputDir = "exports"
+		}
+	}
+	if custom && filepath.Clean(outputDir) == "slides" {
+		return "", NewToolError(ErrCodeInvalidInput, "slides/ holds the app's JPEG previews; choose another output_dir for custom image options")
 	}
 
 	fmt.Printf("Exporting slides from: %s to %s/\n", exportInput.PresentationPath, outputDir)
 
-	// Use our existing conversion function
-	slides, err := ConvertPPTXToJPEG(exportInput.PresentationPath, outputDir)
+	// Only render the requested slides; unchanged slides are skipped either way
+	first, last := 0, math.MaxInt
+	if len(exportInput.SlideNumbers) > 0 {
+		first, last = math.MaxInt, 0
+		for _, num := range exportInput.SlideNumbers {
+			if num < 1 {
+				return "", NewToolError(ErrCodeInvalidInput, "slide numbers start at 1, got %d", num)
+			}
+			first, last = min(first, num-1), max(last, num-1)
+		}
+	}
+
+	var slides []string
+	switch {
+	case custom:
+		slides, err = exportSlideImagesWith(ctx, app, exportInput.PresentationPath, outputDir, first, last, options)
+	case len(exportInput.SlideNumbers) > 0:
+		slides, err = convertSlideRange(ctx, app, exportInput.PresentationPath, outputDir, first, last)
+	default:
+		slides, err = convertSlides(ctx, app, exportInput.PresentationPath, outputDir)
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to export slides: %v", err)
+		return "", NewToolError(toolErrorCodeOr(err, ErrCodeExportFailed), "failed to export slides: %v", err)
+	}
+	if outputDir == "slides" && app != nil && app.exports != nil {
+		app.exports.ForgetRange(exportInput.PresentationPath, first, last)
 	}
 
 	// Filter slides if specific slide numbers were requested
-	var filteredSlides []string
 	if len(exportInput.SlideNumbers) > 0 {
-		slideMap := make(map[int]bool)
+		requested := make(map[string]bool)
 		for _, num := range exportInput.SlideNumbers {
-			slideMap[num-1] = true // Convert to 0-based indexing
+			requested[slideImagePath(outputDir, num-1, options.Extension())] = true
 		}
 
-		for i, slide := range slides {
-			if slideMap[i] {
+		var filteredSlides []string
+		for _, slide := range slides {
+			if requested[slide] {
 				filteredSlides = append(filteredSlides, slide)
 			}
 		}
 		slides = filteredSlides
 	}
 
-	result := map[string]interface{}{
-		"success":     true,
-		"slide_count": len(slides),
-		"slides":      slides,
-		"output_dir":  outputDir,
-	}
-
-	resultJSON, _ := json.Marshal(result)
-	return string(resultJSON), nil
+	return marshalResult(ExportResult{
+		Format:     options.Format,
+		OutputDir:  outputDir,
+		Slides:     slides,
+		SlideCount: len(slides),
+		DPI:        options.DPI,
+	})
 }
 
 // AddSlideDefinition defines the add_slide tool
@@ -334,33 +508,35 @@ var AddSlideDefinition = ToolDefinition{
 	Name: "add_slide",
 	Description: `Add a new slide to the presentation with optional initial content.
 
-Use this tool to create new slides in the presentation. You can specify position, layout type, and initial title content.`,
+Use this tool to create new slides in the presentation. You can specify position, layout, and initial title content; use list_layouts to see the layouts the template offers. When the user has a brand profile, the slide's text takes its heading and body fonts and shows its footer.`,
 	InputSchema: AddSlideInputSchema,
 	Function:    AddSlide,
+	Mutating:    true,
+	Screenshot:  true,
 }
 
 type AddSlideInput struct {
 	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
 	Position         int    `json:"position,omitempty" jsonschema_description:"Position to insert slide (optional, defaults to end, 1-based indexing)"`
-	Layout           string `json:"layout,omitempty" jsonschema_description:"Slide layout type (optional, defaults to 'blank')"`
+	Layout           string `json:"layout,omitempty" jsonschema_description:"Slide layout name or type from list_layouts (optional, defaults to a blank slide)"`
 	Title            string `json:"title,omitempty" jsonschema_description:"Initial title text for the slide (optional)"`
 }
 
 var AddSlideInputSchema = GenerateSchema[AddSlideInput]()
 
-func AddSlide(app *App, input json.RawMessage) (string, error) {
+func AddSlide(ctx context.Context, app *App, input json.RawMessage) (string, error) {
 	addSlideInput := AddSlideInput{}
 	err := json.Unmarshal(input, &addSlideInput)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse input: %v", err)
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
 	}
 
 	// Use current presentation path if not provided
 	if addSlideInput.PresentationPath == "" {
-		if app != nil && app.currentPresentationPath != "" {
-			addSlideInput.PresentationPath = app.currentPresentationPath
+		if currentPath := app.presentationPath(); currentPath != "" {
+			addSlideInput.PresentationPath = currentPath
 		} else {
-			return "", fmt.Errorf("no presentation loaded - please load a presentation first")
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
 		}
 	}
 
@@ -368,6 +544,12 @@ func AddSlide(app *App, input json.RawMessage) (string, error) {
 	layout := addSlideInput.Layout
 	if layout == "" {
 		layout = "blank"
+	} else {
+		resolved, err := resolveLayoutArg(addSlideInput.PresentationPath, layout)
+		if err != nil {
+			return "", err
+		}
+		layout = resolved
 	}
 
 	fmt.Printf("Adding slide to: %s\n", addSlideInput.PresentationPath)
@@ -380,7 +562,6 @@ func AddSlide(app *App, input json.RawMessage) (string, error) {
 
 	// Build command arguments
 	args := []string{
-		"scripts/uno_add_slide.py",
 		addSlideInput.PresentationPath,
 	}
 
@@ -397,28 +578,45 @@ func AddSlide(app *App, input json.RawMessage) (string, error) {
 		args = append(args, addSlideInput.Title)
 	}
 
+	// New slides take the brand's fonts and footer
+	if brand := app.brandProfile(); brand != nil {
+		brandJSON, err := json.Marshal(map[string]string{
+			"heading_font": brand.HeadingFont,
+			"body_font":    brand.BodyFont,
+			"footer_text":  brand.FooterText,
+		})
+		if err != nil {
+			return "", NewToolError(ErrCodeInternal, "failed to encode brand profile: %v", err)
+		}
+		if addSlideInput.Title == "" {
+			args = append(args, "")
+		}
+		args = append(args, string(brandJSON))
+	}
+
 	// Call Python UNO script
-	cmd := exec.Command("python3", args...)
-	output, err := cmd.CombinedOutput()
+	output, err := runUnoScript(ctx, app, "uno_add_slide.py", args...)
 	if err != nil {
-		return "", fmt.Errorf("failed to add slide: %v\nOutput: %s", err, string(output))
+		return "", scriptError("failed to add slide", err, output)
 	}
 
 	// Validate that the output is valid JSON
 	var result interface{}
 	if err := json.Unmarshal(output, &result); err != nil {
-		return "", fmt.Errorf("invalid JSON output from UNO script: %v", err)
+		return "", invalidScriptOutput(err)
 	}
 
 	// Parse the result to get slide information
 	var addResult map[string]interface{}
 	if err := json.Unmarshal(output, &addResult); err != nil {
-		return "", fmt.Errorf("failed to parse add slide result: %v", err)
+		return "", NewToolError(ErrCodeScriptFailed, "failed to parse add slide result: %v", err)
 	}
 
-	// Automatically export slides for visual verification (like edit_slide_text does)
+	// Re-render previews from the new slide onward (like edit_slide_text does for its slide)
 	fmt.Printf("Exporting slides for visual verification...\n")
-	slides, exportErr := ConvertPPTXToJPEG(addSlideInput.PresentationPath, "slides")
+	newSlideNumber, _ := addResult["new_slide_number"].(float64)
+	totalSlides, _ := addResult["total_slides"].(float64)
+	slides, exportErr := refreshPreviewsAfterStructuralChange(ctx, app, addSlideInput.PresentationPath, int(newSlideNumber)-1, 1, int(totalSlides))
 	if exportErr != nil {
 		// Don't fail the add operation if export fails, just warn
 		fmt.Printf("Warning: Failed to export slides for preview: %v\n", exportErr)
@@ -443,6 +641,8 @@ var DeleteSlideDefinition = ToolDefinition{
 Use this tool to remove unwanted slides from the presentation. The slide numbers will be automatically adjusted after deletion.`,
 	InputSchema: DeleteSlideInputSchema,
 	Function:    DeleteSlide,
+	Mutating:    true,
+	Destructive: true,
 }
 
 type DeleteSlideInput struct {
@@ -452,50 +652,50 @@ type DeleteSlideInput struct {
 
 var DeleteSlideInputSchema = GenerateSchema[DeleteSlideInput]()
 
-func DeleteSlide(app *App, input json.RawMessage) (string, error) {
+func DeleteSlide(ctx context.Context, app *App, input json.RawMessage) (string, error) {
 	deleteSlideInput := DeleteSlideInput{}
 	err := json.Unmarshal(input, &deleteSlideInput)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse input: %v", err)
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
 	}
 
 	// Use current presentation path if not provided
 	if deleteSlideInput.PresentationPath == "" {
-		if app != nil && app.currentPresentationPath != "" {
-			deleteSlideInput.PresentationPath = app.currentPresentationPath
+		if currentPath := app.presentationPath(); currentPath != "" {
+			deleteSlideInput.PresentationPath = currentPath
 		} else {
-			return "", fmt.Errorf("no presentation loaded - please load a presentation first")
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
 		}
 	}
 
 	if deleteSlideInput.SlideNumber < 1 {
-		return "", fmt.Errorf("slide_number must be 1 or greater")
+		return "", NewToolError(ErrCodeSlideOutOfRange, "slide_number must be 1 or greater")
 	}
 
 	fmt.Printf("Deleting slide %d from: %s\n", deleteSlideInput.SlideNumber, deleteSlideInput.PresentationPath)
 
 	// Call Python UNO script
-	cmd := exec.Command("python3", "scripts/uno_delete_slide.py", deleteSlideInput.PresentationPath, fmt.Sprintf("%d", deleteSlideInput.SlideNumber))
-	output, err := cmd.CombinedOutput()
+	output, err := runUnoScript(ctx, app, "uno_delete_slide.py", deleteSlideInput.PresentationPath, fmt.Sprintf("%d", deleteSlideInput.SlideNumber))
 	if err != nil {
-		return "", fmt.Errorf("failed to delete slide: %v\nOutput: %s", err, string(output))
+		return "", scriptError("failed to delete slide", err, output)
 	}
 
 	// Validate that the output is valid JSON
 	var result interface{}
 	if err := json.Unmarshal(output, &result); err != nil {
-		return "", fmt.Errorf("invalid JSON output from UNO script: %v", err)
+		return "", invalidScriptOutput(err)
 	}
 
 	// Parse the result to get slide information
 	var deleteResult map[string]interface{}
 	if err := json.Unmarshal(output, &deleteResult); err != nil {
-		return "", fmt.Errorf("failed to parse delete slide result: %v", err)
+		return "", NewToolError(ErrCodeScriptFailed, "failed to parse delete slide result: %v", err)
 	}
 
-	// Automatically export slides for visual verification (like add_slide does)
+	// Re-render previews from the deleted position onward (like add_slide does)
 	fmt.Printf("Exporting slides for visual verification...\n")
-	slides, exportErr := ConvertPPTXToJPEG(deleteSlideInput.PresentationPath, "slides")
+	newSlideCount, _ := deleteResult["new_slide_count"].(float64)
+	slides, exportErr := refreshPreviewsAfterStructuralChange(ctx, app, deleteSlideInput.PresentationPath, deleteSlideInput.SlideNumber-1, -1, int(newSlideCount))
 	if exportErr != nil {
 		// Don't fail the delete operation if export fails, just warn
 		fmt.Printf("Warning: Failed to export slides for preview: %v\n", exportErr)
@@ -511,3 +711,2847 @@ func DeleteSlide(app *App, input json.RawMessage) (string, error) {
 
 	return string(output), nil
 }
+
+// MoveSlideDefinition defines the move_slide tool
+var MoveSlideDefinition = ToolDefinition{
+	Name: "move_slide",
+	Description: `Move a slide to a different position in the presentation.
+
+Use this tool to reorder the deck, e.g. "put the summary slide first". target_position is where the slide ends up (1-based); the slides in between shift by one to make room. Slide numbers of the shifted slides change accordingly.`,
+	InputSchema: MoveSlideInputSchema,
+	Function:    MoveSlide,
+	Mutating:    true,
+}
+
+type MoveSlideInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int    `json:"slide_number" jsonschema_description:"Slide number to move (1-based indexing)"`
+	TargetPosition   int    `json:"target_position" jsonschema_description:"Position the slide should end up at (1-based indexing)"`
+}
+
+var MoveSlideInputSchema = GenerateSchema[MoveSlideInput]()
+
+func MoveSlide(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	moveInput := MoveSlideInput{}
+	err := json.Unmarshal(input, &moveInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if moveInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			moveInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if moveInput.SlideNumber < 1 || moveInput.TargetPosition < 1 {
+		return "", NewToolError(ErrCodeSlideOutOfRange, "slide_number and target_position must be 1 or greater")
+	}
+
+	fmt.Printf("Moving slide %d to position %d in: %s\n", moveInput.SlideNumber, moveInput.TargetPosition, moveInput.PresentationPath)
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_move_slide.py", moveInput.PresentationPath,
+		fmt.Sprintf("%d", moveInput.SlideNumber), fmt.Sprintf("%d", moveInput.TargetPosition))
+	if err != nil {
+		return "", scriptError("failed to move slide", err, output)
+	}
+
+	var moveResult map[string]interface{}
+	if err := json.Unmarshal(output, &moveResult); err != nil {
+		return "", invalidScriptOutput(err)
+	}
+
+	if moveInput.SlideNumber == moveInput.TargetPosition {
+		return string(output), nil
+	}
+
+	// Only the slides between the old and new position changed places
+	fmt.Printf("Exporting slides for visual verification...\n")
+	slideCount, _ := moveResult["slide_count"].(float64)
+	first := min(moveInput.SlideNumber, moveInput.TargetPosition) - 1
+	last := max(moveInput.SlideNumber, moveInput.TargetPosition) - 1
+	slides, exportErr := refreshPreviewsAfterMove(ctx, app, moveInput.PresentationPath, first, last, int(slideCount))
+	if exportErr != nil {
+		// Don't fail the move operation if export fails, just warn
+		fmt.Printf("Warning: Failed to export slides for preview: %v\n", exportErr)
+		return string(output), nil
+	}
+
+	moveResult["exported_slides"] = slides
+	moveResult["slides_directory"] = "slides"
+	enhancedResult, _ := json.Marshal(moveResult)
+	return string(enhancedResult), nil
+}
+
+// refreshPreviewsAfterMove re-renders the previews of slides first..last (0-based, inclusive),
+// the only ones a move reorders. It falls back to a full export when the previews on disk
+// don't match the deck's slide count.
+func refreshPreviewsAfterMove(ctx context.Context, app *App, presentationPath string, first, last, totalSlides int) ([]string, error) {
+	slidesDir := "slides"
+	if app != nil && app.exports != nil {
+		app.exports.ForgetRange(presentationPath, first, last)
+	}
+	if totalSlides < 1 || last >= totalSlides || countSlidePreviews(slidesDir) != totalSlides {
+		return convertSlides(ctx, app, presentationPath, slidesDir)
+	}
+	return convertSlideRange(ctx, app, presentationPath, slidesDir, first, last)
+}
+
+// refreshPreviewsAfterStructuralChange updates the slide previews after one slide was inserted
+// (delta 1) or removed (delta -1) at the 0-based index. Existing previews are renumbered and only
+// slides from index onward are re-rendered, since earlier slides are unchanged. It falls back to a
+// full export when the previews on disk don't match the deck's previous slide count.
+func refreshPreviewsAfterStructuralChange(ctx context.Context, app *App, presentationPath string, index, delta, totalSlides int) ([]string, error) {
+	slidesDir := "slides"
+	previousTotal := totalSlides - delta
+	if app != nil && app.exports != nil {
+		// Slides from index onward are re-rendered here, so pending exports for them are moot
+		app.exports.Forget(presentationPath, index)
+	}
+	if index < 0 || totalSlides < 0 || previousTotal < 1 || countSlidePreviews(slidesDir) != previousTotal {
+		return convertSlides(ctx, app, presentationPath, slidesDir)
+	}
+
+	var shiftErr error
+	if delta > 0 {
+		shiftErr = shiftSlidePreviews(slidesDir, index, previousTotal, delta)
+	} else if shiftErr = os.Remove(slidePreviewPath(slidesDir, index)); shiftErr == nil {
+		shiftErr = shiftSlidePreviews(slidesDir, index+1, previousTotal, delta)
+	}
+	if shiftErr != nil {
+		fmt.Printf("Warning: %v, falling back to full export\n", shiftErr)
+		return convertSlides(ctx, app, presentationPath, slidesDir)
+	}
+	// Renamed previews keep their modification time, so drop what was cached under their names
+	if app != nil && app.imageCache != nil {
+		for i := index; i < max(previousTotal, totalSlides); i++ {
+			app.imageCache.Invalidate(slidePreviewPath(slidesDir, i))
+		}
+	}
+
+	// Deleting the last slide leaves nothing to re-render
+	if index >= totalSlides {
+		return listSlidePreviews(slidesDir)
+	}
+	return convertSlideRange(ctx, app, presentationPath, slidesDir, index, totalSlides-1)
+}
+
+// GenerateImageDefinition defines the generate_image tool
+var GenerateImageDefinition = ToolDefinition{
+	Name: "generate_image",
+	Description: `Generate an illustration from a text prompt using the configured image generation provider (OpenAI Images, Stability AI or a local Stable Diffusion web UI), save it into the project assets, and optionally place it on a slide.
+
+Use this tool for requests like "make a hero illustration for the title slide"; for photos of real-world subjects, search_stock_image is usually the better fit. Write a descriptive prompt covering subject, style, and mood. If slide_number is given, the image is inserted on that slide; position and size are in inches and default to a centered image that fits within 60% of the slide.`,
+	InputSchema: GenerateImageInputSchema,
+	Function:    GenerateImage,
+	Mutating:    true,
+	Screenshot:  true,
+	Timeout:     5 * time.Minute, // Image providers can take a while
+}
+
+type GenerateImageInput struct {
+	PresentationPath string   `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Prompt           string   `json:"prompt" jsonschema_description:"Description of the image to generate"`
+	Size             string   `json:"size,omitempty" jsonschema_description:"(Optional) Image size such as '1024x1024', '1536x1024' or '1024x1536', defaults to '1024x1024'; Stability AI uses the closest supported aspect ratio"`
+	FileName         string   `json:"file_name,omitempty" jsonschema_description:"(Optional) Base file name for the saved image in the assets directory"`
+	SlideNumber      int      `json:"slide_number,omitempty" jsonschema_description:"(Optional) Slide to insert the image on (1-based indexing); omit to only save the image"`
+	X                *float64 `json:"x,omitempty" jsonschema_description:"(Optional) Left position in inches"`
+	Y                *float64 `json:"y,omitempty" jsonschema_description:"(Optional) Top position in inches"`
+	Width            *float64 `json:"width,omitempty" jsonschema_description:"(Optional) Width in inches; height follows the aspect ratio if omitted"`
+	Height           *float64 `json:"height,omitempty" jsonschema_description:"(Optional) Height in inches; width follows the aspect ratio if omitted"`
+}
+
+var GenerateImageInputSchema = GenerateSchema[GenerateImageInput]()
+
+func GenerateImage(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	generateInput := GenerateImageInput{}
+	err := json.Unmarshal(input, &generateInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	if generateInput.Prompt == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "prompt is required")
+	}
+
+	// Use current presentation path if not provided and we need to insert
+	if generateInput.SlideNumber > 0 && generateInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			generateInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	provider, err := NewImageProviderFromEnv()
+	if err != nil {
+		return "", NewToolError(ErrCodeProviderError, "%v", err)
+	}
+
+	fmt.Printf("Generating image with %s: %s\n", provider.Name(), generateInput.Prompt)
+
+	imageData, err := provider.Generate(ctx, generateInput.Prompt, generateInput.Size)
+	if err != nil {
+		return "", NewToolError(ErrCodeProviderError, "failed to generate image: %v", err)
+	}
+
+	imagePath, err := saveGeneratedImage(imageData, generateInput.FileName)
+	if err != nil {
+		return "", err
+	}
+
+	result := map[string]interface{}{
+		"success":    true,
+		"provider":   provider.Name(),
+		"image_path": imagePath,
+		"message":    fmt.Sprintf("Generated image saved to %s", imagePath),
+	}
+
+	if generateInput.SlideNumber < 1 {
+		resultJSON, _ := json.Marshal(result)
+		return string(resultJSON), nil
+	}
+
+	// Insert the generated image on the requested slide
+	insertOutput, err := insertImageOnSlide(ctx, app, generateInput.PresentationPath, generateInput.SlideNumber, imagePath,
+		generateInput.X, generateInput.Y, generateInput.Width, generateInput.Height)
+	if err != nil {
+		return "", NewToolError(toolErrorCode(err), "image saved to %s but could not be inserted: %v", imagePath, err)
+	}
+	result["inserted"] = insertOutput
+	result["message"] = fmt.Sprintf("Generated image saved to %s and inserted on slide %d", imagePath, generateInput.SlideNumber)
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, generateInput.PresentationPath, generateInput.SlideNumber)
+
+	resultJSON, _ := json.Marshal(result)
+	return string(resultJSON), nil
+}
+
+// insertImageOnSlide places an image file on a slide via the UNO insert script.
+// Nil position or size values fall back to the script defaults.
+func insertImageOnSlide(ctx context.Context, app *App, presentationPath string, slideNumber int, imagePath string, x, y, width, height *float64) (map[string]interface{}, error) {
+	args := []string{
+		presentationPath,
+		fmt.Sprintf("%d", slideNumber),
+		imagePath,
+	}
+	for _, value := range []*float64{x, y, width, height} {
+		if value != nil {
+			args = append(args, fmt.Sprintf("%g", *value))
+		} else {
+			args = append(args, "")
+		}
+	}
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_insert_image.py", args...)
+	if err != nil {
+		return nil, scriptError("failed to insert image", err, output)
+	}
+
+	var insertResult map[string]interface{}
+	if err := json.Unmarshal(output, &insertResult); err != nil {
+		return nil, invalidScriptOutput(err)
+	}
+
+	return insertResult, nil
+}
+
+// InsertImageDefinition defines the insert_image tool
+var InsertImageDefinition = ToolDefinition{
+	Name: "insert_image",
+	Description: `Place an existing image file (PNG, JPEG, GIF, SVG, ...) onto a slide.
+
+Use this tool for requests like "add our logo to slide 3". Position and size are in inches. If only width or height is given the other follows the image's aspect ratio; with neither, the image is scaled to fit within 60% of the slide. Without x and y the image is centered on the slide.`,
+	InputSchema: InsertImageInputSchema,
+	Function:    InsertImage,
+	Mutating:    true,
+	Screenshot:  true,
+}
+
+type InsertImageInput struct {
+	PresentationPath string   `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int      `json:"slide_number" jsonschema_description:"Slide to insert the image on (1-based indexing)"`
+	ImagePath        string   `json:"image_path" jsonschema_description:"Path to the image file to insert"`
+	X                *float64 `json:"x,omitempty" jsonschema_description:"(Optional) Left position in inches"`
+	Y                *float64 `json:"y,omitempty" jsonschema_description:"(Optional) Top position in inches"`
+	Width            *float64 `json:"width,omitempty" jsonschema_description:"(Optional) Width in inches; height follows the aspect ratio if omitted"`
+	Height           *float64 `json:"height,omitempty" jsonschema_description:"(Optional) Height in inches; width follows the aspect ratio if omitted"`
+}
+
+var InsertImageInputSchema = GenerateSchema[InsertImageInput]()
+
+func InsertImage(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	insertInput := InsertImageInput{}
+	err := json.Unmarshal(input, &insertInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if insertInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			insertInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if insertInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if insertInput.ImagePath == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "image_path is required")
+	}
+	if (insertInput.Width != nil && *insertInput.Width <= 0) || (insertInput.Height != nil && *insertInput.Height <= 0) {
+		return "", NewToolError(ErrCodeInvalidInput, "width and height must be greater than 0")
+	}
+
+	// The script runs in its own working directory, so hand it an absolute path
+	imagePath, err := filepath.Abs(insertInput.ImagePath)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "invalid image_path: %v", err)
+	}
+	info, err := os.Stat(imagePath)
+	if err != nil || info.IsDir() {
+		return "", NewToolError(ErrCodeFileNotFound, "image file not found: %s", insertInput.ImagePath).
+			WithDetail("image_path", imagePath)
+	}
+
+	insertOutput, err := insertImageOnSlide(ctx, app, insertInput.PresentationPath, insertInput.SlideNumber, imagePath,
+		insertInput.X, insertInput.Y, insertInput.Width, insertInput.Height)
+	if err != nil {
+		return "", err
+	}
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, insertInput.PresentationPath, insertInput.SlideNumber)
+
+	resultJSON, _ := json.Marshal(insertOutput)
+	return string(resultJSON), nil
+}
+
+// imageFits are the ways replace_image can fit a new image into the old picture's frame
+var imageFits = []string{"stretch", "fill"}
+
+// ReplaceImageDefinition defines the replace_image tool
+var ReplaceImageDefinition = ToolDefinition{
+	Name: "replace_image",
+	Description: `Swap the picture of an existing image shape for another image file, keeping the shape's position, size, cropping, name and animations.
+
+Use this tool for requests like "update the screenshot on slide 4" or "use the new logo" instead of deleting the picture and inserting a new one. Find the picture's shape_id with read_slide.
+
+fit 'stretch' (default) keeps the frame and cuts off the same share of each side of the new image as the old crop did; an image of another aspect ratio then looks stretched, which the result warns about. fit 'fill' crops the new image evenly so it fills the frame undistorted. alt_text updates the picture's alternative text, which otherwise stays as it was.`,
+	InputSchema: ReplaceImageInputSchema,
+	Function:    ReplaceImage,
+	Mutating:    true,
+	Screenshot:  true,
+}
+
+type ReplaceImageInput struct {
+	PresentationPath string  `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int     `json:"slide_number" jsonschema_description:"Slide containing the picture (1-based indexing)"`
+	ShapeID          string  `json:"shape_id,omitempty" jsonschema_description:"(Optional) shape_id of the picture from read_slide; used instead of shape_index"`
+	ShapeIndex       int     `json:"shape_index" jsonschema_description:"Shape index of the picture on the slide; ignored when shape_id is given"`
+	ImagePath        string  `json:"image_path" jsonschema_description:"Path to the new image file"`
+	Fit              string  `json:"fit,omitempty" jsonschema_description:"(Optional) 'stretch' (default) keeps the old crop, 'fill' crops the new image to fill the frame undistorted"`
+	AltText          *string `json:"alt_text,omitempty" jsonschema_description:"(Optional) New alternative text for the picture"`
+}
+
+var ReplaceImageInputSchema = GenerateSchema[ReplaceImageInput]()
+
+func ReplaceImage(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	replaceInput := ReplaceImageInput{}
+	err := json.Unmarshal(input, &replaceInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if replaceInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			replaceInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if replaceInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if replaceInput.ImagePath == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "image_path is required")
+	}
+	if replaceInput.Fit == "" {
+		replaceInput.Fit = "stretch"
+	}
+	if !slices.Contains(imageFits, replaceInput.Fit) {
+		return "", NewToolError(ErrCodeInvalidInput, "fit must be one of: %s", strings.Join(imageFits, ", "))
+	}
+
+	// The script runs in its own working directory, so hand it an absolute path
+	imagePath, err := filepath.Abs(replaceInput.ImagePath)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "invalid image_path: %v", err)
+	}
+	info, err := os.Stat(imagePath)
+	if err != nil || info.IsDir() {
+		return "", NewToolError(ErrCodeFileNotFound, "image file not found: %s", replaceInput.ImagePath).
+			WithDetail("image_path", imagePath)
+	}
+
+	if replaceInput.ShapeID != "" {
+		index, err := resolveShapeID(ctx, app, replaceInput.PresentationPath, replaceInput.SlideNumber, replaceInput.ShapeID)
+		if err != nil {
+			return "", err
+		}
+		replaceInput.ShapeIndex = index
+	}
+	if replaceInput.ShapeIndex < 0 {
+		return "", NewToolError(ErrCodeInvalidInput, "shape_index must be 0 or greater")
+	}
+
+	args := []string{
+		replaceInput.PresentationPath,
+		fmt.Sprintf("%d", replaceInput.SlideNumber),
+		fmt.Sprintf("%d", replaceInput.ShapeIndex),
+		imagePath,
+		replaceInput.Fit,
+	}
+	if replaceInput.AltText != nil {
+		args = append(args, *replaceInput.AltText)
+	}
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_replace_image.py", args...)
+	if err != nil {
+		return "", scriptError("failed to replace image", err, output)
+	}
+
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, replaceInput.PresentationPath, replaceInput.SlideNumber)
+
+	return marshalResult(result)
+}
+
+// optionalFloatArgs formats optional script arguments such as position and size, passing
+// an empty string for the unset ones so the script uses its defaults
+func optionalFloatArgs(values ...*float64) []string {
+	args := make([]string, len(values))
+	for i, value := range values {
+		if value != nil {
+			args[i] = fmt.Sprintf("%g", *value)
+		}
+	}
+	return args
+}
+
+// Table dimensions beyond these are unreadable on a slide and almost certainly a mistake
+const (
+	maxTableRows    = 50
+	maxTableColumns = 20
+)
+
+// InsertTableDefinition defines the insert_table tool
+var InsertTableDefinition = ToolDefinition{
+	Name: "insert_table",
+	Description: `Insert a table with the given number of rows and columns onto a slide, optionally filled with data.
+
+Use this tool when content is naturally tabular, e.g. comparisons, schedules, or figures. data is a list of rows, each a list of cell texts; the first row is usually the header. Position and size are in inches; by default the table spans 80% of the slide width, is 0.4 inches per row tall, and is centered. The result includes the table's shape_id for later edit_table_cell calls. For data in a CSV or Excel file, use import_data instead.`,
+	InputSchema: InsertTableInputSchema,
+	Function:    InsertTable,
+	Mutating:    true,
+	Screenshot:  true,
+}
+
+type InsertTableInput struct {
+	PresentationPath string     `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int        `json:"slide_number" jsonschema_description:"Slide to insert the table on (1-based indexing)"`
+	Rows             int        `json:"rows" jsonschema_description:"Number of rows, including any header row"`
+	Columns          int        `json:"columns" jsonschema_description:"Number of columns"`
+	Data             [][]string `json:"data,omitempty" jsonschema_description:"(Optional) Cell texts as a list of rows, each a list of column values"`
+	X                *float64   `json:"x,omitempty" jsonschema_description:"(Optional) Left position in inches"`
+	Y                *float64   `json:"y,omitempty" jsonschema_description:"(Optional) Top position in inches"`
+	Width            *float64   `json:"width,omitempty" jsonschema_description:"(Optional) Table width in inches"`
+	Height           *float64   `json:"height,omitempty" jsonschema_description:"(Optional) Table height in inches"`
+}
+
+var InsertTableInputSchema = GenerateSchema[InsertTableInput]()
+
+func InsertTable(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	tableInput := InsertTableInput{}
+	err := json.Unmarshal(input, &tableInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if tableInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			tableInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if tableInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if tableInput.Rows < 1 || tableInput.Rows > maxTableRows || tableInput.Columns < 1 || tableInput.Columns > maxTableColumns {
+		return "", NewToolError(ErrCodeInvalidInput, "rows must be between 1 and %d and columns between 1 and %d", maxTableRows, maxTableColumns)
+	}
+	if len(tableInput.Data) > tableInput.Rows {
+		return "", NewToolError(ErrCodeInvalidInput, "data has %d rows but the table only has %d", len(tableInput.Data), tableInput.Rows)
+	}
+	for i, row := range tableInput.Data {
+		if len(row) > tableInput.Columns {
+			return "", NewToolError(ErrCodeInvalidInput, "data row %d has %d values but the table only has %d columns", i, len(row), tableInput.Columns)
+		}
+	}
+	if (tableInput.Width != nil && *tableInput.Width <= 0) || (tableInput.Height != nil && *tableInput.Height <= 0) {
+		return "", NewToolError(ErrCodeInvalidInput, "width and height must be greater than 0")
+	}
+
+	args := []string{
+		tableInput.PresentationPath,
+		fmt.Sprintf("%d", tableInput.SlideNumber),
+		fmt.Sprintf("%d", tableInput.Rows),
+		fmt.Sprintf("%d", tableInput.Columns),
+	}
+	args = append(args, optionalFloatArgs(tableInput.X, tableInput.Y, tableInput.Width, tableInput.Height)...)
+	if len(tableInput.Data) > 0 {
+		data, _ := json.Marshal(tableInput.Data)
+		args = append(args, string(data))
+	}
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_insert_table.py", args...)
+	if err != nil {
+		return "", scriptError("failed to insert table", err, output)
+	}
+
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, tableInput.PresentationPath, tableInput.SlideNumber)
+
+	return marshalResult(result)
+}
+
+// EditTableCellDefinition defines the edit_table_cell tool
+var EditTableCellDefinition = ToolDefinition{
+	Name: "edit_table_cell",
+	Description: `Replace the text of a single table cell.
+
+Use read_slide (or the insert_table result) to find the table's shape_id. Row and column are 0-based, with row 0 usually being the header.`,
+	InputSchema: EditTableCellInputSchema,
+	Function:    EditTableCell,
+	Mutating:    true,
+	Screenshot:  true,
+}
+
+type EditTableCellInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int    `json:"slide_number" jsonschema_description:"Slide containing the table (1-based indexing)"`
+	ShapeID          string `json:"shape_id,omitempty" jsonschema_description:"(Optional) shape_id of the table from read_slide; used instead of shape_index"`
+	ShapeIndex       int    `json:"shape_index" jsonschema_description:"Shape index of the table on the slide; ignored when shape_id is given"`
+	Row              int    `json:"row" jsonschema_description:"Row of the cell (0-based indexing)"`
+	Column           int    `json:"column" jsonschema_description:"Column of the cell (0-based indexing)"`
+	NewText          string `json:"new_text" jsonschema_description:"New text for the cell"`
+}
+
+var EditTableCellInputSchema = GenerateSchema[EditTableCellInput]()
+
+func EditTableCell(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	cellInput := EditTableCellInput{}
+	err := json.Unmarshal(input, &cellInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if cellInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			cellInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if cellInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if cellInput.ShapeID != "" {
+		index, err := resolveShapeID(ctx, app, cellInput.PresentationPath, cellInput.SlideNumber, cellInput.ShapeID)
+		if err != nil {
+			return "", err
+		}
+		cellInput.ShapeIndex = index
+	}
+	if cellInput.ShapeIndex < 0 || cellInput.Row < 0 || cellInput.Column < 0 {
+		return "", NewToolError(ErrCodeInvalidInput, "shape_index, row and column must be 0 or greater")
+	}
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_edit_table_cell.py",
+		cellInput.PresentationPath,
+		fmt.Sprintf("%d", cellInput.SlideNumber),
+		fmt.Sprintf("%d", cellInput.ShapeIndex),
+		fmt.Sprintf("%d", cellInput.Row),
+		fmt.Sprintf("%d", cellInput.Column),
+		cellInput.NewText)
+	if err != nil {
+		return "", scriptError("failed to edit table cell", err, output)
+	}
+
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, cellInput.PresentationPath, cellInput.SlideNumber)
+
+	return marshalResult(result)
+}
+
+// shapeTypes are the shape kinds add_shape can create
+var shapeTypes = []string{"rectangle", "ellipse", "line", "arrow", "text_box"}
+
+// hexColorPattern matches RRGGBB colors with an optional leading #
+var hexColorPattern = regexp.MustCompile(`^#?[0-9A-Fa-f]{6}$`)
+
+// AddShapeDefinition defines the add_shape tool
+var AddShapeDefinition = ToolDefinition{
+	Name: "add_shape",
+	Description: `Add a new rectangle, ellipse, line, arrow, or text box to a slide.
+
+Use this tool to build slide content from scratch, e.g. callout boxes, dividers, or labels. Position and size are in inches (defaults: 1 inch from the top-left corner, 3 x 1 inches). For lines and arrows, x/y is the start point and width/height the offset to the end point (negative values allowed; the arrowhead is at the end). Colors are hex RRGGBB values such as "1F4E79"; line_width is in points. The result includes the new shape's shape_id.`,
+	InputSchema: AddShapeInputSchema,
+	Function:    AddShape,
+	Mutating:    true,
+	Screenshot:  true,
+}
+
+type AddShapeInput struct {
+	PresentationPath string   `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int      `json:"slide_number" jsonschema_description:"Slide to add the shape to (1-based indexing)"`
+	ShapeType        string   `json:"shape_type" jsonschema_description:"Kind of shape: 'rectangle', 'ellipse', 'line', 'arrow', or 'text_box'"`
+	X                *float64 `json:"x,omitempty" jsonschema_description:"(Optional) Left position (or line start) in inches"`
+	Y                *float64 `json:"y,omitempty" jsonschema_description:"(Optional) Top position (or line start) in inches"`
+	Width            *float64 `json:"width,omitempty" jsonschema_description:"(Optional) Width in inches, or horizontal offset of the line end"`
+	Height           *float64 `json:"height,omitempty" jsonschema_description:"(Optional) Height in inches, or vertical offset of the line end"`
+	Text             string   `json:"text,omitempty" jsonschema_description:"(Optional) Text inside the shape; ignored for lines and arrows"`
+	FillColor        string   `json:"fill_color,omitempty" jsonschema_description:"(Optional) Fill color as hex RRGGBB"`
+	LineColor        string   `json:"line_color,omitempty" jsonschema_description:"(Optional) Outline or line color as hex RRGGBB"`
+	LineWidth        *float64 `json:"line_width,omitempty" jsonschema_description:"(Optional) Outline or line width in points"`
+}
+
+var AddShapeInputSchema = GenerateSchema[AddShapeInput]()
+
+func AddShape(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	shapeInput := AddShapeInput{}
+	err := json.Unmarshal(input, &shapeInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if shapeInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			shapeInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if shapeInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if !slices.Contains(shapeTypes, shapeInput.ShapeType) {
+		return "", NewToolError(ErrCodeInvalidInput, "unknown shape_type %q, must be one of %s", shapeInput.ShapeType, strings.Join(shapeTypes, ", ")).
+			WithDetail("valid_shape_types", shapeTypes)
+	}
+	isLine := shapeInput.ShapeType == "line" || shapeInput.ShapeType == "arrow"
+	if !isLine && ((shapeInput.Width != nil && *shapeInput.Width <= 0) || (shapeInput.Height != nil && *shapeInput.Height <= 0)) {
+		return "", NewToolError(ErrCodeInvalidInput, "width and height must be greater than 0")
+	}
+	if shapeInput.LineWidth != nil && *shapeInput.LineWidth < 0 {
+		return "", NewToolError(ErrCodeInvalidInput, "line_width must not be negative")
+	}
+	for _, color := range []string{shapeInput.FillColor, shapeInput.LineColor} {
+		if color != "" && !hexColorPattern.MatchString(color) {
+			return "", NewToolError(ErrCodeInvalidInput, "invalid color %q, expected hex RRGGBB such as 1F4E79", color)
+		}
+	}
+
+	args := []string{
+		shapeInput.PresentationPath,
+		fmt.Sprintf("%d", shapeInput.SlideNumber),
+		shapeInput.ShapeType,
+	}
+	for _, value := range []*float64{shapeInput.X, shapeInput.Y, shapeInput.Width, shapeInput.Height} {
+		if value != nil {
+			args = append(args, fmt.Sprintf("%g", *value))
+		} else {
+			args = append(args, "")
+		}
+	}
+	args = append(args, shapeInput.Text, strings.TrimPrefix(shapeInput.FillColor, "#"), strings.TrimPrefix(shapeInput.LineColor, "#"))
+	if shapeInput.LineWidth != nil {
+		args = append(args, fmt.Sprintf("%g", *shapeInput.LineWidth))
+	} else {
+		args = append(args, "")
+	}
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_add_shape.py", args...)
+	if err != nil {
+		return "", scriptError("failed to add shape", err, output)
+	}
+
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, shapeInput.PresentationPath, shapeInput.SlideNumber)
+
+	return marshalResult(result)
+}
+
+// DeleteShapeDefinition defines the delete_shape tool
+var DeleteShapeDefinition = ToolDefinition{
+	Name: "delete_shape",
+	Description: `Remove a shape (text box, image, table, line, ...) from a slide.
+
+Use read_slide first to find the shape's shape_id. Shapes after the deleted one move down by one index, so when deleting several shapes address them by shape_id rather than shape_index.`,
+	InputSchema: DeleteShapeInputSchema,
+	Function:    DeleteShape,
+	Mutating:    true,
+	Destructive: true,
+	Screenshot:  true,
+}
+
+type DeleteShapeInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int    `json:"slide_number" jsonschema_description:"Slide containing the shape (1-based indexing)"`
+	ShapeID          string `json:"shape_id,omitempty" jsonschema_description:"(Optional) shape_id of the shape from read_slide; used instead of shape_index"`
+	ShapeIndex       int    `json:"shape_index" jsonschema_description:"Index of the shape to delete; ignored when shape_id is given"`
+}
+
+var DeleteShapeInputSchema = GenerateSchema[DeleteShapeInput]()
+
+func DeleteShape(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	deleteInput := DeleteShapeInput{}
+	err := json.Unmarshal(input, &deleteInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if deleteInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			deleteInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if deleteInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if deleteInput.ShapeID != "" {
+		index, err := resolveShapeID(ctx, app, deleteInput.PresentationPath, deleteInput.SlideNumber, deleteInput.ShapeID)
+		if err != nil {
+			return "", err
+		}
+		deleteInput.ShapeIndex = index
+	}
+	if deleteInput.ShapeIndex < 0 {
+		return "", NewToolError(ErrCodeShapeNotFound, "shape_index must be 0 or greater")
+	}
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_delete_shape.py", deleteInput.PresentationPath,
+		fmt.Sprintf("%d", deleteInput.SlideNumber), fmt.Sprintf("%d", deleteInput.ShapeIndex))
+	if err != nil {
+		return "", scriptError("failed to delete shape", err, output)
+	}
+
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, deleteInput.PresentationPath, deleteInput.SlideNumber)
+
+	return marshalResult(result)
+}
+
+// FindReplaceAllDefinition defines the find_replace_all tool
+var FindReplaceAllDefinition = ToolDefinition{
+	Name: "find_replace_all",
+	Description: `Find and replace text across every slide of the presentation, including table cells and grouped shapes, and optionally speaker notes.
+
+Use this tool instead of repeated edit_slide_text calls for deck-wide changes such as renaming a product. Matching is case-sensitive by default. With regex set, find is a Python regular expression and replace may use group references like \1. Set dry_run to preview the matches without changing the file. The result reports the number of replacements per slide with before/after excerpts.`,
+	InputSchema: FindReplaceAllInputSchema,
+	Function:    FindReplaceAll,
+	Mutating:    true,
+	Destructive: true,
+}
+
+type FindReplaceAllInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Find             string `json:"find" jsonschema_description:"Text or regular expression to search for"`
+	Replace          string `json:"replace" jsonschema_description:"Replacement text (may be empty to delete matches)"`
+	Regex            bool   `json:"regex,omitempty" jsonschema_description:"(Optional) Treat find as a regular expression"`
+	IgnoreCase       bool   `json:"ignore_case,omitempty" jsonschema_description:"(Optional) Match regardless of case"`
+	IncludeNotes     bool   `json:"include_notes,omitempty" jsonschema_description:"(Optional) Also replace in speaker notes"`
+	DryRun           bool   `json:"dry_run,omitempty" jsonschema_description:"(Optional) Only report matches without changing the presentation"`
+}
+
+var FindReplaceAllInputSchema = GenerateSchema[FindReplaceAllInput]()
+
+func FindReplaceAll(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	replaceInput := FindReplaceAllInput{}
+	err := json.Unmarshal(input, &replaceInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if replaceInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			replaceInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if replaceInput.Find == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "find is required")
+	}
+
+	fmt.Printf("Replacing %q with %q in: %s\n", replaceInput.Find, replaceInput.Replace, replaceInput.PresentationPath)
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_find_replace.py",
+		replaceInput.PresentationPath,
+		replaceInput.Find,
+		replaceInput.Replace,
+		fmt.Sprintf("%t", replaceInput.Regex),
+		fmt.Sprintf("%t", !replaceInput.IgnoreCase),
+		fmt.Sprintf("%t", replaceInput.IncludeNotes),
+		fmt.Sprintf("%t", replaceInput.DryRun))
+	if err != nil {
+		return "", scriptError("failed to replace text", err, output)
+	}
+
+	var replaceResult struct {
+		Slides []struct {
+			SlideNumber int `json:"slide_number"`
+		} `json:"slides"`
+	}
+	if err := json.Unmarshal(output, &replaceResult); err != nil {
+		return "", invalidScriptOutput(err)
+	}
+
+	// Queue the changed slides for export to update UI
+	if !replaceInput.DryRun && len(replaceResult.Slides) > 0 {
+		changed := make([]int, 0, len(replaceResult.Slides))
+		for _, slide := range replaceResult.Slides {
+			changed = append(changed, slide.SlideNumber)
+		}
+		schedulePreviewExport(ctx, app, replaceInput.PresentationPath, changed...)
+	}
+
+	return string(output), nil
+}
+
+// TranslatePresentationDefinition defines the translate_presentation tool
+var TranslatePresentationDefinition = ToolDefinition{
+	Name: "translate_presentation",
+	Description: `Translate every text shape, table cell, and speaker notes field in the presentation into a target language.
+
+Use this tool for whole-deck translation instead of editing slides one by one. Translations are written back in place, or into a copy when output_path is given (the original is left untouched). The result reports how many elements were translated and lists untranslatable elements such as images, charts, or texts the translator could not handle. Each shape's text is replaced as a whole, so mixed formatting within a shape is flattened; use translate_slides to keep it or to translate only some slides.`,
+	InputSchema: TranslatePresentationInputSchema,
+	Function:    TranslatePresentation,
+	Mutating:    true,
+	Destructive: true,
+	Timeout:     15 * time.Minute, // One model call per batch of texts
+}
+
+type TranslatePresentationInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	TargetLanguage   string `json:"target_language" jsonschema_description:"Language to translate into, e.g. 'German' or 'DE'"`
+	SourceLanguage   string `json:"source_language,omitempty" jsonschema_description:"(Optional) Source language, detected automatically if omitted"`
+	OutputPath       string `json:"output_path,omitempty" jsonschema_description:"(Optional) Write the translated deck to this .pptx path instead of modifying the original"`
+	SkipNotes        bool   `json:"skip_notes,omitempty" jsonschema_description:"(Optional) Leave speaker notes untranslated"`
+}
+
+var TranslatePresentationInputSchema = GenerateSchema[TranslatePresentationInput]()
+
+func TranslatePresentation(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	translateInput := TranslatePresentationInput{}
+	err := json.Unmarshal(input, &translateInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if translateInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			translateInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if translateInput.TargetLanguage == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "target_language is required")
+	}
+
+	translator, err := NewTranslatorFromEnv(app)
+	if err != nil {
+		return "", NewToolError(ErrCodeProviderError, "%v", err)
+	}
+
+	fmt.Printf("Translating %s into %s\n", translateInput.PresentationPath, translateInput.TargetLanguage)
+
+	// Extract all translatable text from the deck
+	output, err := runUnoScript(ctx, app, "uno_translate.py", "extract", translateInput.PresentationPath,
+		fmt.Sprintf("%t", !translateInput.SkipNotes))
+	if err != nil {
+		return "", scriptError("failed to extract text", err, output)
+	}
+
+	var extracted struct {
+		TotalSlides    int                     `json:"total_slides"`
+		Elements       []TextElement           `json:"elements"`
+		Untranslatable []UntranslatableElement `json:"untranslatable"`
+	}
+	if err := json.Unmarshal(output, &extracted); err != nil {
+		return "", invalidScriptOutput(err)
+	}
+
+	translations, failed := translateElements(ctx, translator, extracted.Elements, translateInput.TargetLanguage, translateInput.SourceLanguage)
+	untranslatable := append(extracted.Untranslatable, failed...)
+
+	// Write into a copy when requested so the original stays untouched
+	targetPath := translateInput.PresentationPath
+	if translateInput.OutputPath != "" {
+		targetPath, err = filepath.Abs(translateInput.OutputPath)
+		if err != nil {
+			return "", NewToolError(ErrCodeInternal, "failed to resolve output path: %v", err)
+		}
+		if err := copyFile(translateInput.PresentationPath, targetPath); err != nil {
+			return "", NewToolError(ErrCodeInternal, "failed to copy presentation: %v", err)
+		}
+	}
+
+	applied := 0
+	if len(translations) > 0 {
+		translationsFile, err := os.CreateTemp(sessionTempDir(), "translations-*.json")
+		if err != nil {
+			return "", NewToolError(ErrCodeInternal, "failed to create translations file: %v", err)
+		}
+		defer os.Remove(translationsFile.Name())
+
+		if err := json.NewEncoder(translationsFile).Encode(translations); err != nil {
+			translationsFile.Close()
+			return "", NewToolError(ErrCodeInternal, "failed to write translations file: %v", err)
+		}
+		translationsFile.Close()
+
+		output, err = runUnoScript(ctx, app, "uno_translate.py", "apply", targetPath, translationsFile.Name())
+		if err != nil {
+			return "", scriptError("failed to apply translations", err, output)
+		}
+
+		var applyResult struct {
+			Applied int                     `json:"applied"`
+			Failed  []UntranslatableElement `json:"failed"`
+		}
+		if err := json.Unmarshal(output, &applyResult); err != nil {
+			return "", invalidScriptOutput(err)
+		}
+		applied = applyResult.Applied
+		untranslatable = append(untranslatable, applyResult.Failed...)
+	}
+
+	// Refresh previews when the loaded deck itself was translated
+	if targetPath == translateInput.PresentationPath && applied > 0 {
+		schedulePreviewExport(ctx, app, targetPath)
+	}
+
+	result := map[string]interface{}{
+		"success":              true,
+		"translator":           translator.Name(),
+		"target_language":      translateInput.TargetLanguage,
+		"output_path":          targetPath,
+		"total_slides":         extracted.TotalSlides,
+		"elements_found":       len(extracted.Elements),
+		"elements_translated":  applied,
+		"untranslatable":       untranslatable,
+		"untranslatable_count": len(untranslatable),
+	}
+
+	resultJSON, _ := json.Marshal(result)
+	return string(resultJSON), nil
+}
+
+// UndoLastChangeDefinition defines the undo_last_change tool
+var UndoLastChangeDefinition = ToolDefinition{
+	Name: "undo_last_change",
+	Description: `Undo the most recent change to the presentation by restoring the version saved before it.
+
+Every successful editing tool call saves the previous version of the file, so this tool can step back one change at a time, including changes made in earlier requests. Use it when an edit went wrong or the user asks to revert something, instead of trying to reconstruct the old content by hand.`,
+	InputSchema:    UndoLastChangeInputSchema,
+	Function:       UndoLastChange,
+	Mutating:       true,
+	ManagesHistory: true,
+}
+
+type UndoLastChangeInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+}
+
+var UndoLastChangeInputSchema = GenerateSchema[UndoLastChangeInput]()
+
+func UndoLastChange(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	undoInput := UndoLastChangeInput{}
+	err := json.Unmarshal(input, &undoInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if undoInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			undoInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	undone, err := app.history.Undo(undoInput.PresentationPath, app.aiAgent.turnID)
+	if errors.Is(err, errNothingToUndo) {
+		return "", NewToolError(ErrCodeNothingToUndo, "there are no saved changes to undo for this presentation")
+	}
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to undo: %v", err)
+	}
+
+	// The restored version may have a different number of slides, so refresh them all
+	schedulePreviewExport(ctx, app, undoInput.PresentationPath)
+
+	state := app.history.State(undoInput.PresentationPath)
+	result := map[string]interface{}{
+		"success":  true,
+		"undone":   undone,
+		"can_undo": state.CanUndo,
+		"can_redo": state.CanRedo,
+	}
+
+	resultJSON, _ := json.Marshal(result)
+	return string(resultJSON), nil
+}
+
+// SavePresentationAsDefinition defines the save_presentation_as tool
+var SavePresentationAsDefinition = ToolDefinition{
+	Name: "save_presentation_as",
+	Description: `Save a copy of the presentation to a new file, leaving the original untouched.
+
+The format follows the extension of output_path: .pptx (PowerPoint), .odp (OpenDocument) or .pdf. A relative output_path is resolved against the presentation's folder. Set open_copy (.pptx only) to switch to the copy, so that all later edits apply to the copy instead of the original - use this when the user wants to keep the original as it is.`,
+	InputSchema:   SavePresentationAsInputSchema,
+	Function:      SavePresentationAs,
+	NeedsApproval: saveAsOverwrites,
+}
+
+type SavePresentationAsInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	OutputPath       string `json:"output_path" jsonschema_description:"Where to write the copy, ending in .pptx, .odp or .pdf"`
+	Overwrite        bool   `json:"overwrite,omitempty" jsonschema_description:"(Optional) Replace output_path if it already exists, defaults to false"`
+	OpenCopy         bool   `json:"open_copy,omitempty" jsonschema_description:"(Optional) Continue working on the copy (.pptx only), defaults to false"`
+}
+
+var SavePresentationAsInputSchema = GenerateSchema[SavePresentationAsInput]()
+
+// saveAsFormats are the extensions a presentation can be saved as
+var saveAsFormats = []string{".pptx", ".odp", ".pdf"}
+
+func SavePresentationAs(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	saveInput := SavePresentationAsInput{}
+	err := json.Unmarshal(input, &saveInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if saveInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			saveInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+	if saveInput.OpenCopy && strings.ToLower(filepath.Ext(saveInput.OutputPath)) != ".pptx" {
+		return "", NewToolError(ErrCodeInvalidInput, "open_copy requires a .pptx output_path")
+	}
+
+	outputPath, output, err := savePresentationCopy(ctx, app, saveInput.PresentationPath, saveInput.OutputPath, saveInput.Overwrite)
+	if err != nil {
+		return "", err
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return "", invalidScriptOutput(err)
+	}
+	if saveInput.OpenCopy {
+		app.setPresentationPath(outputPath)
+		result["opened_copy"] = true
+		result["message"] = fmt.Sprintf("Saved a copy to %s and switched to it; use it as presentation_path for further edits", outputPath)
+	}
+
+	resultJSON, _ := json.Marshal(result)
+	return string(resultJSON), nil
+}
+
+// saveAsOverwrites reports whether a save_presentation_as call replaces an existing file,
+// which needs the user's approval like other destructive calls
+func saveAsOverwrites(app *App, input json.RawMessage) bool {
+	var saveInput SavePresentationAsInput
+	if json.Unmarshal(input, &saveInput) != nil || !saveInput.Overwrite || saveInput.OutputPath == "" {
+		return false
+	}
+	if saveInput.PresentationPath == "" {
+		saveInput.PresentationPath = app.presentationPath()
+	}
+	outputPath := saveInput.OutputPath
+	if !filepath.IsAbs(outputPath) {
+		outputPath = filepath.Join(filepath.Dir(absPresentationPath(saveInput.PresentationPath)), outputPath)
+	}
+	_, err := os.Stat(outputPath)
+	return err == nil
+}
+
+// savePresentationCopy writes a copy of presentationPath to outputPath through LibreOffice,
+// in the format given by outputPath's extension, and returns the copy's absolute path and
+// the script output. A relative outputPath is resolved against the presentation's folder.
+func savePresentationCopy(ctx context.Context, app *App, presentationPath, outputPath string, overwrite bool) (string, []byte, error) {
+	if outputPath == "" {
+		return "", nil, NewToolError(ErrCodeInvalidInput, "output_path is required")
+	}
+	ext := strings.ToLower(filepath.Ext(outputPath))
+	if !slices.Contains(saveAsFormats, ext) {
+		return "", nil, NewToolError(ErrCodeInvalidInput, "unsupported format %q: output_path must end in %s", ext, strings.Join(saveAsFormats, ", "))
+	}
+
+	sourcePath, err := filepath.Abs(presentationPath)
+	if err != nil {
+		return "", nil, NewToolError(ErrCodeInvalidInput, "invalid presentation path: %v", err)
+	}
+	outputPath, err = resolveOutputPath(sourcePath, outputPath, overwrite)
+	if err != nil {
+		return "", nil, err
+	}
+
+	output, err := runUnoScript(ctx, app, "uno_save_as.py", sourcePath, outputPath)
+	if err != nil {
+		return "", output, scriptError("failed to save presentation", err, output)
+	}
+	return outputPath, output, nil
+}
+
+// resolveOutputPath checks where a file derived from presentationPath (absolute) may be
+// written: a relative outputPath is resolved against the presentation's folder, the
+// presentation itself is refused, and an existing file only replaced with overwrite. The
+// output folder is created.
+func resolveOutputPath(presentationPath, outputPath string, overwrite bool) (string, error) {
+	if !filepath.IsAbs(outputPath) {
+		outputPath = filepath.Join(filepath.Dir(presentationPath), outputPath)
+	}
+	outputPath = filepath.Clean(outputPath)
+
+	sourceInfo, err := os.Stat(presentationPath)
+	if err != nil {
+		return "", NewToolError(ErrCodeFileNotFound, "presentation not found: %s", presentationPath)
+	}
+	if outputInfo, err := os.Stat(outputPath); err == nil {
+		if os.SameFile(sourceInfo, outputInfo) {
+			return "", NewToolError(ErrCodeInvalidInput, "output_path is the presentation itself; choose a new file name")
+		}
+		if !overwrite {
+			return "", NewToolError(ErrCodeInvalidInput, "%s already exists; set overwrite to replace it", outputPath)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to create output folder: %v", err)
+	}
+	return outputPath, nil
+}
+
+// ExportPDFDefinition defines the export_pdf tool
+var ExportPDFDefinition = ToolDefinition{
+	Name: "export_pdf",
+	Description: `Export the presentation, or a range of its slides, to a PDF file.
+
+Layouts: 'slides' (one slide per page, the default), 'notes' (notes pages showing each slide above its speaker notes) and 'handout' (slides_per_page slides per page: 1, 2, 3, 4, 6 or 9, default 6). The PDF is written next to the presentation as '<name>.pdf' ('<name> notes.pdf', '<name> handout.pdf') unless output_path is given; a relative output_path is resolved against the presentation's folder. The presentation itself is not changed.`,
+	InputSchema: ExportPDFInputSchema,
+	Function:    ExportPDF,
+	Timeout:     5 * time.Minute, // Renders every slide of large decks
+}
+
+type ExportPDFInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	OutputPath       string `json:"output_path,omitempty" jsonschema_description:"(Optional) Where to write the PDF, defaults to a file next to the presentation"`
+	Layout           string `json:"layout,omitempty" jsonschema_description:"(Optional) Page layout: 'slides', 'notes', or 'handout', defaults to 'slides'"`
+	SlidesPerPage    int    `json:"slides_per_page,omitempty" jsonschema_description:"(Optional) Slides per handout page: 1, 2, 3, 4, 6 or 9, defaults to 6"`
+	FirstSlide       int    `json:"first_slide,omitempty" jsonschema_description:"(Optional) First slide to export (1-based indexing), defaults to 1"`
+	LastSlide        int    `json:"last_slide,omitempty" jsonschema_description:"(Optional) Last slide to export (1-based indexing), defaults to the last slide"`
+	Overwrite        bool   `json:"overwrite,omitempty" jsonschema_description:"(Optional) Replace the PDF if it already exists, defaults to false"`
+}
+
+var ExportPDFInputSchema = GenerateSchema[ExportPDFInput]()
+
+func ExportPDF(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	pdfInput := ExportPDFInput{}
+	err := json.Unmarshal(input, &pdfInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if pdfInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			pdfInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	options := PDFExportOptions{
+		Layout:        pdfInput.Layout,
+		SlidesPerPage: pdfInput.SlidesPerPage,
+		FirstSlide:    pdfInput.FirstSlide,
+		LastSlide:     pdfInput.LastSlide,
+	}
+	result, err := exportPresentationPDF(ctx, app, pdfInput.PresentationPath, pdfInput.OutputPath, options, pdfInput.Overwrite)
+	if err != nil {
+		return "", err
+	}
+	return marshalResult(result)
+}
+
+// exportPresentationPDF validates options against the deck and exports it to outputPath,
+// or to the default PDF path when outputPath is empty
+func exportPresentationPDF(ctx context.Context, app *App, presentationPath, outputPath string, options PDFExportOptions, overwrite bool) (*ExportResult, error) {
+	sourcePath, err := filepath.Abs(presentationPath)
+	if err != nil {
+		return nil, NewToolError(ErrCodeInvalidInput, "invalid presentation path: %v", err)
+	}
+	pkg, err := openPPTX(sourcePath)
+	if err != nil {
+		return nil, NewToolError(ErrCodeFileNotFound, "failed to open presentation: %v", err)
+	}
+	slideCount := len(pkg.slides)
+	pkg.Close()
+
+	options = options.WithDefaults(slideCount)
+	if err := options.Validate(); err != nil {
+		return nil, NewToolError(ErrCodeInvalidInput, "%v", err)
+	}
+	if options.FirstSlide < 1 || options.LastSlide > slideCount || options.FirstSlide > options.LastSlide {
+		return nil, NewToolError(ErrCodeSlideOutOfRange, "slide range %d-%d is outside the presentation (1-%d)", options.FirstSlide, options.LastSlide, slideCount)
+	}
+
+	if outputPath == "" {
+		outputPath = defaultPDFPath(sourcePath, options)
+	}
+	if ext := strings.ToLower(filepath.Ext(outputPath)); ext != ".pdf" {
+		return nil, NewToolError(ErrCodeInvalidInput, "output_path must end in .pdf")
+	}
+	outputPath, err = resolveOutputPath(sourcePath, outputPath, overwrite)
+	if err != nil {
+		return nil, err
+	}
+
+	return exportPDF(ctx, app, sourcePath, outputPath, options)
+}
+
+// GetPresentationInfoDefinition defines the get_presentation_info tool
+var GetPresentationInfoDefinition = ToolDefinition{
+	Name: "get_presentation_info",
+	Description: `Get presentation-level information: document properties (title, author, subject, company, keywords, last modified by, created/modified dates), slide size in inches with its aspect ratio, and the slide count.
+
+Use this tool to answer questions about the deck as a whole, such as who made it or whether it is 16:9 or 4:3.`,
+	InputSchema: GetPresentationInfoInputSchema,
+	Function:    GetPresentationInfo,
+	ReadOnly:    true,
+}
+
+type GetPresentationInfoInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+}
+
+var GetPresentationInfoInputSchema = GenerateSchema[GetPresentationInfoInput]()
+
+func GetPresentationInfo(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	infoInput := GetPresentationInfoInput{}
+	err := json.Unmarshal(input, &infoInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if infoInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			infoInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if _, err := os.Stat(infoInput.PresentationPath); os.IsNotExist(err) {
+		return "", NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", infoInput.PresentationPath)
+	}
+
+	// Read .pptx packages directly; other formats, or packages the reader can't parse, go through LibreOffice
+	if isOOXMLPackage(infoInput.PresentationPath) {
+		info, err := presentationInfoNative(infoInput.PresentationPath)
+		if err == nil {
+			resultJSON, _ := json.Marshal(info)
+			return string(resultJSON), nil
+		}
+		fmt.Printf("Native presentation reader failed, falling back to LibreOffice: %v\n", err)
+	}
+
+	output, err := runUnoScript(ctx, app, "uno_presentation_info.py", infoInput.PresentationPath, "get")
+	if err != nil {
+		return "", scriptError("failed to read presentation info", err, output)
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return "", invalidScriptOutput(err)
+	}
+
+	return string(output), nil
+}
+
+// SetPresentationInfoDefinition defines the set_presentation_info tool
+var SetPresentationInfoDefinition = ToolDefinition{
+	Name: "set_presentation_info",
+	Description: `Set document properties of the presentation: title, author, subject and company.
+
+Only the properties you pass are changed; pass an empty string to clear one. These are the file's metadata shown in PowerPoint's File > Info, not text on the slides - use edit_slide_text to change a slide's title. The result lists the updated properties along with the presentation info.`,
+	InputSchema: SetPresentationInfoInputSchema,
+	Function:    SetPresentationInfo,
+	Mutating:    true,
+}
+
+type SetPresentationInfoInput struct {
+	PresentationPath string  `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Title            *string `json:"title,omitempty" jsonschema_description:"(Optional) New document title"`
+	Author           *string `json:"author,omitempty" jsonschema_description:"(Optional) New author"`
+	Subject          *string `json:"subject,omitempty" jsonschema_description:"(Optional) New subject"`
+	Company          *string `json:"company,omitempty" jsonschema_description:"(Optional) New company"`
+}
+
+var SetPresentationInfoInputSchema = GenerateSchema[SetPresentationInfoInput]()
+
+func SetPresentationInfo(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	setInput := SetPresentationInfoInput{}
+	err := json.Unmarshal(input, &setInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if setInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			setInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	changes := map[string]string{}
+	for name, value := range map[string]*string{
+		"title":   setInput.Title,
+		"author":  setInput.Author,
+		"subject": setInput.Subject,
+		"company": setInput.Company,
+	} {
+		if value != nil {
+			changes[name] = *value
+		}
+	}
+	if len(changes) == 0 {
+		return "", NewToolError(ErrCodeInvalidInput, "pass at least one of title, author, subject or company")
+	}
+
+	changesJSON, _ := json.Marshal(changes)
+	output, err := runUnoScript(ctx, app, "uno_presentation_info.py", setInput.PresentationPath, "set", string(changesJSON))
+	if err != nil {
+		return "", scriptError("failed to set presentation info", err, output)
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return "", invalidScriptOutput(err)
+	}
+
+	return string(output), nil
+}
+
+// ListLayoutsDefinition defines the list_layouts tool
+var ListLayoutsDefinition = ToolDefinition{
+	Name: "list_layouts",
+	Description: `List the slide layouts the presentation's template offers, such as Title Slide, Title and Content or Two Content.
+
+Each layout has its name, its type (title, obj, twoObj, titleOnly, blank, ...), the placeholders it provides (title, body, ...) and the slides currently using it. Use a layout's name with add_slide or set_slide_layout.`,
+	InputSchema: ListLayoutsInputSchema,
+	Function:    ListLayouts,
+	ReadOnly:    true,
+}
+
+type ListLayoutsInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+}
+
+var ListLayoutsInputSchema = GenerateSchema[ListLayoutsInput]()
+
+func ListLayouts(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	listInput := ListLayoutsInput{}
+	err := json.Unmarshal(input, &listInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if listInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			listInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if _, err := os.Stat(listInput.PresentationPath); os.IsNotExist(err) {
+		return "", NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", listInput.PresentationPath)
+	}
+	if !isOOXMLPackage(listInput.PresentationPath) {
+		return "", NewToolError(ErrCodeInvalidInput, "layouts can only be listed for .pptx presentations")
+	}
+
+	result, err := listLayoutsNative(listInput.PresentationPath)
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to read layouts: %v", err)
+	}
+	return result, nil
+}
+
+// SetSlideLayoutDefinition defines the set_slide_layout tool
+var SetSlideLayoutDefinition = ToolDefinition{
+	Name: "set_slide_layout",
+	Description: `Apply one of the template's layouts to an existing slide.
+
+Use list_layouts first to see the available layouts, then pass a layout name (or type). The slide's existing shapes and text are kept; placeholders of the new layout that the slide doesn't fill yet are added. Re-read the slide afterwards, since shape indexes can change.`,
+	InputSchema: SetSlideLayoutInputSchema,
+	Function:    SetSlideLayout,
+	Mutating:    true,
+	Screenshot:  true,
+}
+
+type SetSlideLayoutInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int    `json:"slide_number" jsonschema_description:"Slide to change (1-based indexing)"`
+	Layout           string `json:"layout" jsonschema_description:"Layout name or type from list_layouts, e.g. 'Title and Content' or 'twoObj'"`
+}
+
+var SetSlideLayoutInputSchema = GenerateSchema[SetSlideLayoutInput]()
+
+func SetSlideLayout(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	layoutInput := SetSlideLayoutInput{}
+	err := json.Unmarshal(input, &layoutInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if layoutInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			layoutInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if layoutInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeSlideOutOfRange, "slide_number must be 1 or greater")
+	}
+	if layoutInput.Layout == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "layout is required")
+	}
+	layout, err := resolveLayoutArg(layoutInput.PresentationPath, layoutInput.Layout)
+	if err != nil {
+		return "", err
+	}
+
+	output, err := runUnoScript(ctx, app, "uno_set_slide_layout.py", layoutInput.PresentationPath,
+		fmt.Sprintf("%d", layoutInput.SlideNumber), layout)
+	if err != nil {
+		return "", scriptError("failed to set slide layout", err, output)
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return "", invalidScriptOutput(err)
+	}
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, layoutInput.PresentationPath, layoutInput.SlideNumber)
+
+	return string(output), nil
+}
+
+// resolveLayoutArg looks up a layout name or type in a .pptx template and returns the
+// layout argument for the UNO scripts: {"name": ..., "type": ...}. For other formats the
+// name is passed on as it is, to be matched against LibreOffice's master pages.
+func resolveLayoutArg(presentationPath, name string) (string, error) {
+	arg := map[string]string{"name": name}
+	if isOOXMLPackage(presentationPath) {
+		pkg, err := openPPTX(presentationPath)
+		if err != nil {
+			return "", NewToolError(ErrCodeFileNotFound, "failed to open presentation: %v", err)
+		}
+		layouts, err := pkg.Layouts()
+		pkg.Close()
+		if err != nil {
+			return "", NewToolError(ErrCodeInternal, "failed to read layouts: %v", err)
+		}
+
+		layout, ok := findLayout(layouts, name)
+		if !ok {
+			names := make([]string, len(layouts))
+			for i, layout := range layouts {
+				names[i] = layout.Name
+			}
+			return "", NewToolError(ErrCodeInvalidInput, "unknown layout %q; available layouts: %s", name, strings.Join(names, ", "))
+		}
+		arg = map[string]string{"name": layout.Name, "type": layout.Type}
+	}
+
+	argJSON, _ := json.Marshal(arg)
+	return string(argJSON), nil
+}
+
+// ApplyTemplateDefinition defines the apply_template tool
+var ApplyTemplateDefinition = ToolDefinition{
+	Name: "apply_template",
+	Description: `Apply the slide masters, layouts and theme of a template (.potx or .pptx) to the presentation, e.g. to move a deck into the corporate template.
+
+The presentation's own masters and theme are replaced. Every slide moves to the template layout with the same name, else the same type (title, obj, twoObj, ...), else the template's content layout; the result lists which layout each slide got. Slide content, notes and the slide size are kept. Use list_layouts afterwards to see the new layouts.`,
+	InputSchema: ApplyTemplateInputSchema,
+	Function:    ApplyTemplate,
+	Mutating:    true,
+	Timeout:     2 * time.Minute,
+}
+
+type ApplyTemplateInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	TemplatePath     string `json:"template_path" jsonschema_description:"Path to the template (.potx or .pptx) whose masters and theme to apply"`
+}
+
+var ApplyTemplateInputSchema = GenerateSchema[ApplyTemplateInput]()
+
+func ApplyTemplate(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	templateInput := ApplyTemplateInput{}
+	err := json.Unmarshal(input, &templateInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if templateInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			templateInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if _, err := os.Stat(templateInput.PresentationPath); os.IsNotExist(err) {
+		return "", NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", templateInput.PresentationPath)
+	}
+	if !isOOXMLPackage(templateInput.PresentationPath) {
+		return "", NewToolError(ErrCodeInvalidInput, "templates can only be applied to .pptx presentations")
+	}
+
+	if templateInput.TemplatePath == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "template_path is required")
+	}
+	templatePath, err := filepath.Abs(templateInput.TemplatePath)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "invalid template_path: %v", err)
+	}
+	if ext := strings.ToLower(filepath.Ext(templatePath)); ext != ".potx" && ext != ".pptx" {
+		return "", NewToolError(ErrCodeInvalidInput, "template_path must be a .potx or .pptx file")
+	}
+	if _, err := os.Stat(templatePath); os.IsNotExist(err) {
+		return "", NewToolError(ErrCodeFileNotFound, "template file not found: %s", templateInput.TemplatePath).
+			WithDetail("template_path", templatePath)
+	}
+
+	applied, err := applyTemplate(templateInput.PresentationPath, templatePath)
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to apply template: %v", err)
+	}
+
+	// Every slide's background and placeholders may have changed
+	schedulePreviewExport(ctx, app, templateInput.PresentationPath)
+
+	resultJSON, _ := json.Marshal(map[string]interface{}{
+		"success":  true,
+		"template": filepath.Base(templatePath),
+		"masters":  applied.Masters,
+		"layouts":  applied.Layouts,
+		"slides":   applied.Slides,
+		"message":  fmt.Sprintf("Applied %s: %d layouts, %d slides updated", filepath.Base(templatePath), len(applied.Layouts), len(applied.Slides)),
+	})
+	return string(resultJSON), nil
+}
+
+// ImportSlidesDefinition defines the import_slides tool
+var ImportSlidesDefinition = ToolDefinition{
+	Name: "import_slides",
+	Description: `Copy slides from another presentation (.pptx, .potx, .ppt, .odp or .key) into this one, e.g. to reuse slides from an older deck.
+
+from_slide and to_slide pick the source slides (1-based, inclusive; to_slide 0 means the source's last slide). They are inserted so the first becomes slide number position; position 0 or past the end appends them. Pictures, charts, media and speaker notes come along. By default each slide moves onto this deck's layout with the same name, else the same type, so it picks up this deck's look; set keep_source_formatting to keep the source layouts, masters and theme instead. Comments aren't copied. Use get_slides afterwards to check the result.`,
+	InputSchema: ImportSlidesInputSchema,
+	Function:    ImportSlides,
+	Mutating:    true,
+	Timeout:     2 * time.Minute,
+}
+
+type ImportSlidesInput struct {
+	PresentationPath     string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SourcePath           string `json:"source_path" jsonschema_description:"Presentation to copy slides from; relative paths are resolved against the presentation's folder"`
+	FromSlide            int    `json:"from_slide,omitempty" jsonschema_description:"(Optional) First source slide to copy (1-based, default 1)"`
+	ToSlide              int    `json:"to_slide,omitempty" jsonschema_description:"(Optional) Last source slide to copy (1-based, inclusive; default the source's last slide)"`
+	Position             int    `json:"position,omitempty" jsonschema_description:"(Optional) Slide number the first copied slide gets; 0 (default) appends them"`
+	KeepSourceFormatting bool   `json:"keep_source_formatting,omitempty" jsonschema_description:"(Optional) Keep the source slides' layouts, masters and theme instead of using this deck's"`
+}
+
+var ImportSlidesInputSchema = GenerateSchema[ImportSlidesInput]()
+
+func ImportSlides(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	importInput := ImportSlidesInput{}
+	err := json.Unmarshal(input, &importInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if importInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			importInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if _, err := os.Stat(importInput.PresentationPath); os.IsNotExist(err) {
+		return "", NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", importInput.PresentationPath)
+	}
+	if !isOOXMLPackage(importInput.PresentationPath) {
+		return "", NewToolError(ErrCodeInvalidInput, "slides can only be imported into .pptx presentations")
+	}
+	if importInput.FromSlide < 0 || importInput.ToSlide < 0 || importInput.Position < 0 {
+		return "", NewToolError(ErrCodeInvalidInput, "from_slide, to_slide and position must not be negative")
+	}
+	if importInput.FromSlide == 0 {
+		importInput.FromSlide = 1
+	}
+
+	if importInput.SourcePath == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "source_path is required")
+	}
+	sourcePath := importInput.SourcePath
+	if !filepath.IsAbs(sourcePath) {
+		sourcePath = filepath.Join(filepath.Dir(importInput.PresentationPath), sourcePath)
+	}
+	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+		return "", NewToolError(ErrCodeFileNotFound, "source presentation not found: %s", importInput.SourcePath).
+			WithDetail("source_path", sourcePath)
+	}
+	packagePath := sourcePath
+	switch ext := strings.ToLower(filepath.Ext(sourcePath)); {
+	case ext == ".pptx" || ext == ".potx":
+	case importFormats[ext] != "":
+		// Other formats are read from a .pptx copy made by LibreOffice
+		tempDir, err := os.MkdirTemp(sessionTempDir(), "import-")
+		if err != nil {
+			return "", NewToolError(ErrCodeInternal, "failed to create temporary folder: %v", err)
+		}
+		defer os.RemoveAll(tempDir)
+		packagePath = filepath.Join(tempDir, "source.pptx")
+		if _, _, err := savePresentationCopy(ctx, app, sourcePath, packagePath, true); err != nil {
+			return "", NewToolError(toolErrorCode(err), "failed to convert the source presentation to .pptx: %v", err)
+		}
+	default:
+		return "", NewToolError(ErrCodeInvalidInput, "source_path must be a .pptx, .potx, .ppt, .odp or .key file")
+	}
+
+	result, err := importSlides(importInput.PresentationPath, packagePath,
+		importInput.FromSlide, importInput.ToSlide, importInput.Position, importInput.KeepSourceFormatting)
+	if err != nil {
+		if errors.Is(err, errSlideOutOfRange) {
+			return "", NewToolError(ErrCodeSlideOutOfRange, "failed to import slides: %v", err)
+		}
+		return "", NewToolError(ErrCodeInternal, "failed to import slides: %v", err)
+	}
+
+	if _, err := refreshPreviewsAfterStructuralChange(ctx, app, importInput.PresentationPath, result.FirstSlide-1, result.Imported, result.TotalSlides); err != nil {
+		// Don't fail the import if export fails, just warn
+		fmt.Printf("Warning: Failed to export slides for preview: %v\n", err)
+	}
+
+	last := result.FirstSlide + result.Imported - 1
+	resultJSON, _ := json.Marshal(map[string]interface{}{
+		"success":       true,
+		"source":        filepath.Base(sourcePath),
+		"first_slide":   result.FirstSlide,
+		"slides_added":  result.Imported,
+		"total_slides":  result.TotalSlides,
+		"slides":        result.Slides,
+		"masters_added": result.Masters,
+		"warnings":      result.Warnings,
+		"message":       fmt.Sprintf("Imported %d slide(s) from %s as slides %d-%d", result.Imported, filepath.Base(sourcePath), result.FirstSlide, last),
+	})
+	return string(resultJSON), nil
+}
+
+// ExtractSlidesDefinition defines the extract_slides tool
+var ExtractSlidesDefinition = ToolDefinition{
+	Name: "extract_slides",
+	Description: `Write selected slides of the presentation to a new standalone .pptx, e.g. "just slides 4-9 for the customer version". The presentation itself is not changed.
+
+slides lists the slide numbers to keep (1-based), in the order they should appear in the new file. The copy keeps the deck's masters, layouts and theme, and the chosen slides' notes, pictures and media; parts only the other slides used are left out. The file is written next to the presentation as '<name> (slides 4-9).pptx' unless output_path is given; a relative output_path is resolved against the presentation's folder.`,
+	InputSchema: ExtractSlidesInputSchema,
+	Function:    ExtractSlides,
+	ReadOnly:    true,
+	WritesFiles: true,
+}
+
+type ExtractSlidesInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Slides           []int  `json:"slides" jsonschema_description:"Slide numbers to write to the new file (1-based), in the order they should appear"`
+	OutputPath       string `json:"output_path,omitempty" jsonschema_description:"(Optional) Where to write the .pptx, defaults to a file next to the presentation"`
+	Overwrite        bool   `json:"overwrite,omitempty" jsonschema_description:"(Optional) Replace output_path if it already exists, defaults to false"`
+}
+
+var ExtractSlidesInputSchema = GenerateSchema[ExtractSlidesInput]()
+
+func ExtractSlides(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	extractInput := ExtractSlidesInput{}
+	err := json.Unmarshal(input, &extractInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if extractInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			extractInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	sourcePath, err := filepath.Abs(extractInput.PresentationPath)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "invalid presentation path: %v", err)
+	}
+	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+		return "", NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", extractInput.PresentationPath)
+	}
+	if !isOOXMLPackage(sourcePath) {
+		return "", NewToolError(ErrCodeInvalidInput, "slides can only be extracted from .pptx presentations")
+	}
+	if len(extractInput.Slides) == 0 {
+		return "", NewToolError(ErrCodeInvalidInput, "slides is required: list the slide numbers to extract")
+	}
+
+	outputPath := extractInput.OutputPath
+	if outputPath == "" {
+		name := strings.TrimSuffix(filepath.Base(sourcePath), filepath.Ext(sourcePath))
+		outputPath = filepath.Join(filepath.Dir(sourcePath), fmt.Sprintf("%s (%s).pptx", name, slideListLabel(extractInput.Slides)))
+	}
+	if ext := strings.ToLower(filepath.Ext(outputPath)); ext != ".pptx" {
+		return "", NewToolError(ErrCodeInvalidInput, "output_path must end in .pptx")
+	}
+	outputPath, err = resolveOutputPath(sourcePath, outputPath, extractInput.Overwrite)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := extractSlides(sourcePath, outputPath, extractInput.Slides)
+	if err != nil {
+		if errors.Is(err, errSlideOutOfRange) {
+			return "", NewToolError(ErrCodeSlideOutOfRange, "failed to extract slides: %v", err)
+		}
+		return "", NewToolError(ErrCodeInternal, "failed to extract slides: %v", err)
+	}
+
+	return marshalResult(map[string]interface{}{
+		"success":       true,
+		"output_path":   result.OutputPath,
+		"slides":        result.Slides,
+		"parts_dropped": result.Dropped,
+		"message":       fmt.Sprintf("Wrote %s to %s", slideListLabel(result.Slides), result.OutputPath),
+	})
+}
+
+// slideListLabel describes slide numbers compactly, e.g. "slide 3", "slides 4-9" or
+// "slides 1, 3, 5-7"
+func slideListLabel(slides []int) string {
+	if len(slides) == 1 {
+		return fmt.Sprintf("slide %d", slides[0])
+	}
+	var parts []string
+	for i := 0; i < len(slides); {
+		j := i
+		for j+1 < len(slides) && slides[j+1] == slides[j]+1 {
+			j++
+		}
+		if j > i {
+			parts = append(parts, fmt.Sprintf("%d-%d", slides[i], slides[j]))
+		} else {
+			parts = append(parts, fmt.Sprint(slides[i]))
+		}
+		i = j + 1
+	}
+	return "slides " + strings.Join(parts, ", ")
+}
+
+// FormatListDefinition defines the format_list tool
+var FormatListDefinition = ToolDefinition{
+	Name: "format_list",
+	Description: `Replace a text shape's content with a list whose paragraphs each have their own indentation level and marker.
+
+Each item is one paragraph: its text (without bullet characters or numbers), a level from 0 (top) to 8, and a style - 'bullet', 'number' or 'none' (an indented paragraph without a marker, e.g. a continuation line). Bulleted items can use a custom bullet character; numbered items a number_format such as '1.', '1)', '(a)', 'A.' or 'i.'. Numbering restarts at start_at for the first item and each nested level counts on its own. Style, bullet and number_format set at the top level apply to every item that doesn't set its own.
+
+Use read_slide first to find the shape_id (or shape_index). For a plain single-level bullet list, edit_slide_text with target_type 'bullet_list' is enough.`,
+	InputSchema: FormatListInputSchema,
+	Function:    FormatList,
+	Mutating:    true,
+	Screenshot:  true,
+}
+
+type FormatListInput struct {
+	PresentationPath string     `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int        `json:"slide_number" jsonschema_description:"Slide containing the shape (1-based indexing)"`
+	ShapeID          string     `json:"shape_id,omitempty" jsonschema_description:"(Optional) shape_id of the text shape from read_slide; used instead of shape_index"`
+	ShapeIndex       int        `json:"shape_index" jsonschema_description:"Index of the text shape to fill; ignored when shape_id is given"`
+	Items            []ListItem `json:"items" jsonschema_description:"The list's paragraphs in order; they replace the shape's current text"`
+	Style            string     `json:"style,omitempty" jsonschema_description:"(Optional) Default marker: 'bullet' (default), 'number' or 'none'"`
+	Bullet           string     `json:"bullet,omitempty" jsonschema_description:"(Optional) Default bullet character, e.g. '•' (default), '–', '▪', '➤' or '✓'"`
+	NumberFormat     string     `json:"number_format,omitempty" jsonschema_description:"(Optional) Default numbering: '1.' (default), '1)', '(1)', 'a.', 'a)', 'A.', 'i.' or 'I.'"`
+	StartAt          int        `json:"start_at,omitempty" jsonschema_description:"(Optional) First number of a numbered list (default 1)"`
+}
+
+type ListItem struct {
+	Text         string `json:"text" jsonschema_description:"Paragraph text, without a bullet character or number"`
+	Level        int    `json:"level,omitempty" jsonschema_description:"(Optional) Indentation level, 0 (top, default) to 8"`
+	Style        string `json:"style,omitempty" jsonschema_description:"(Optional) This item's marker: 'bullet', 'number' or 'none'"`
+	Bullet       string `json:"bullet,omitempty" jsonschema_description:"(Optional) This item's bullet character"`
+	NumberFormat string `json:"number_format,omitempty" jsonschema_description:"(Optional) This item's numbering format"`
+}
+
+var FormatListInputSchema = GenerateSchema[FormatListInput]()
+
+// maxListLevel is the deepest indentation level LibreOffice's numbering rules support
+const maxListLevel = 8
+
+// numberFormatPattern matches numbering formats: a 1/a/A/i/I token with optional
+// punctuation around it, e.g. "1.", "(a)" or "I)"
+var numberFormatPattern = regexp.MustCompile(`^[(\[]?[1aAiI][.):\]]?$`)
+
+func FormatList(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	listInput := FormatListInput{}
+	err := json.Unmarshal(input, &listInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if listInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			listInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if listInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if listInput.ShapeID != "" {
+		index, err := resolveShapeID(ctx, app, listInput.PresentationPath, listInput.SlideNumber, listInput.ShapeID)
+		if err != nil {
+			return "", err
+		}
+		listInput.ShapeIndex = index
+	}
+	if listInput.ShapeIndex < 0 {
+		return "", NewToolError(ErrCodeShapeNotFound, "shape_index must be 0 or greater")
+	}
+	if len(listInput.Items) == 0 {
+		return "", NewToolError(ErrCodeInvalidInput, "items must contain at least one paragraph")
+	}
+	if listInput.StartAt == 0 {
+		listInput.StartAt = 1
+	}
+	if listInput.StartAt < 0 {
+		return "", NewToolError(ErrCodeInvalidInput, "start_at must be 1 or greater")
+	}
+
+	// Resolve each item's marker against the list defaults
+	items := make([]ListItem, len(listInput.Items))
+	for i, item := range listInput.Items {
+		if item.Level < 0 || item.Level > maxListLevel {
+			return "", NewToolError(ErrCodeInvalidInput, "item %d: level must be between 0 and %d", i, maxListLevel)
+		}
+		if strings.ContainsAny(item.Text, "\r\n") {
+			return "", NewToolError(ErrCodeInvalidInput, "item %d: text must be a single paragraph; use separate items", i)
+		}
+		item.Style = cmp.Or(item.Style, listInput.Style, "bullet")
+		item.Bullet = cmp.Or(item.Bullet, listInput.Bullet, "•")
+		item.NumberFormat = cmp.Or(item.NumberFormat, listInput.NumberFormat, "1.")
+
+		switch item.Style {
+		case "bullet", "number", "none":
+		default:
+			return "", NewToolError(ErrCodeInvalidInput, "item %d: style must be 'bullet', 'number' or 'none'", i)
+		}
+		if utf8.RuneCountInString(item.Bullet) != 1 {
+			return "", NewToolError(ErrCodeInvalidInput, "item %d: bullet must be a single character", i)
+		}
+		if !numberFormatPattern.MatchString(item.NumberFormat) {
+			return "", NewToolError(ErrCodeInvalidInput, "item %d: unsupported number_format %q; use e.g. '1.', '1)', '(a)', 'A.' or 'i.'", i, item.NumberFormat)
+		}
+		items[i] = item
+	}
+	itemsJSON, _ := json.Marshal(items)
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_format_list.py", listInput.PresentationPath,
+		fmt.Sprintf("%d", listInput.SlideNumber), fmt.Sprintf("%d", listInput.ShapeIndex),
+		string(itemsJSON), fmt.Sprintf("%d", listInput.StartAt))
+	if err != nil {
+		return "", scriptError("failed to format list", err, output)
+	}
+
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, listInput.PresentationPath, listInput.SlideNumber)
+
+	return marshalResult(result)
+}
+
+// SetRichTextDefinition defines the set_rich_text tool
+var SetRichTextDefinition = ToolDefinition{
+	Name: "set_rich_text",
+	Description: `Replace a text shape's content with runs of individually formatted text, e.g. a bold lead-in followed by normal text, a highlighted figure or an inline link.
+
+Runs are written one after another; put "\n" in a run's text to start a new paragraph. Each run can set bold, italic, underline, color (hex RRGGBB), size (points) and a hyperlink URL. Anything a run doesn't set follows the shape's own text style, so only give the properties that differ.
+
+Use read_slide first to find the shape_id (or shape_index).`,
+	InputSchema: SetRichTextInputSchema,
+	Function:    SetRichText,
+	Mutating:    true,
+	Screenshot:  true,
+}
+
+type SetRichTextInput struct {
+	PresentationPath string    `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int       `json:"slide_number" jsonschema_description:"Slide containing the shape (1-based indexing)"`
+	ShapeID          string    `json:"shape_id,omitempty" jsonschema_description:"(Optional) shape_id of the text shape from read_slide; used instead of shape_index"`
+	ShapeIndex       int       `json:"shape_index" jsonschema_description:"Index of the text shape to fill; ignored when shape_id is given"`
+	Runs             []TextRun `json:"runs" jsonschema_description:"Text runs in order; they replace the shape's current text"`
+	Autofit          string    `json:"autofit,omitempty" jsonschema_description:"(Optional) How the shape fits its text: 'shrink' scales text down to fit, 'resize' grows the shape, 'none' lets text overflow; omit to leave it as is"`
+}
+
+type TextRun struct {
+	Text      string  `json:"text" jsonschema_description:"Run text; a line break starts a new paragraph"`
+	Bold      *bool   `json:"bold,omitempty" jsonschema_description:"(Optional) Bold on or off"`
+	Italic    *bool   `json:"italic,omitempty" jsonschema_description:"(Optional) Italic on or off"`
+	Underline *bool   `json:"underline,omitempty" jsonschema_description:"(Optional) Underline on or off"`
+	Color     string  `json:"color,omitempty" jsonschema_description:"(Optional) Text color as hex RRGGBB"`
+	Size      float64 `json:"size,omitempty" jsonschema_description:"(Optional) Font size in points"`
+	Hyperlink string  `json:"hyperlink,omitempty" jsonschema_description:"(Optional) URL the run links to (http, https or mailto)"`
+}
+
+var SetRichTextInputSchema = GenerateSchema[SetRichTextInput]()
+
+func SetRichText(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	richInput := SetRichTextInput{}
+	err := json.Unmarshal(input, &richInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if richInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			richInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if richInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if richInput.ShapeID != "" {
+		index, err := resolveShapeID(ctx, app, richInput.PresentationPath, richInput.SlideNumber, richInput.ShapeID)
+		if err != nil {
+			return "", err
+		}
+		richInput.ShapeIndex = index
+	}
+	if richInput.ShapeIndex < 0 {
+		return "", NewToolError(ErrCodeShapeNotFound, "shape_index must be 0 or greater")
+	}
+	if len(richInput.Runs) == 0 {
+		return "", NewToolError(ErrCodeInvalidInput, "runs must contain at least one run")
+	}
+	if !validAutofit(richInput.Autofit) {
+		return "", NewToolError(ErrCodeInvalidInput, "autofit must be 'shrink', 'resize' or 'none'")
+	}
+
+	for i, run := range richInput.Runs {
+		if run.Text == "" {
+			return "", NewToolError(ErrCodeInvalidInput, "run %d: text is required", i)
+		}
+		if run.Color != "" && !hexColorPattern.MatchString(run.Color) {
+			return "", NewToolError(ErrCodeInvalidInput, "run %d: invalid color %q, expected hex RRGGBB such as 1F4E79", i, run.Color)
+		}
+		if run.Size < 0 || run.Size > 400 {
+			return "", NewToolError(ErrCodeInvalidInput, "run %d: size must be between 1 and 400 points", i)
+		}
+		if run.Hyperlink != "" && !isLinkURL(run.Hyperlink) {
+			return "", NewToolError(ErrCodeInvalidInput, "run %d: hyperlink must be an http, https or mailto URL", i)
+		}
+		richInput.Runs[i].Text = strings.ReplaceAll(run.Text, "\r\n", "\n")
+		richInput.Runs[i].Color = strings.TrimPrefix(run.Color, "#")
+	}
+	runsJSON, _ := json.Marshal(richInput.Runs)
+
+	// Call Python UNO script
+	args := []string{richInput.PresentationPath,
+		fmt.Sprintf("%d", richInput.SlideNumber), fmt.Sprintf("%d", richInput.ShapeIndex), string(runsJSON)}
+	if richInput.Autofit != "" {
+		args = append(args, richInput.Autofit)
+	}
+	output, err := runUnoScript(ctx, app, "uno_set_rich_text.py", args...)
+	if err != nil {
+		return "", scriptError("failed to set rich text", err, output)
+	}
+
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, richInput.PresentationPath, richInput.SlideNumber)
+
+	return marshalResult(result)
+}
+
+// isLinkURL reports whether a hyperlink target is an http, https or mailto URL
+func isLinkURL(link string) bool {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	switch parsed.Scheme {
+	case "http", "https":
+		return parsed.Host != ""
+	case "mailto":
+		return parsed.Opaque != ""
+	}
+	return false
+}
+
+// AddHyperlinkDefinition defines the add_hyperlink tool
+var AddHyperlinkDefinition = ToolDefinition{
+	Name: "add_hyperlink",
+	Description: `Attach a link to text in a shape or to a whole shape: either a web/email URL ('learn more' links) or a jump to another slide (agenda entries, 'back to overview' buttons).
+
+Give text to link just that text inside the shape (its first occurrence, or the nth with occurrence); leave it out to make the whole shape clickable in the slide show. Give exactly one of url (http, https or mailto) and target_slide. Use read_slide first to find the shape_id (or shape_index).`,
+	InputSchema: AddHyperlinkInputSchema,
+	Function:    AddHyperlink,
+	Mutating:    true,
+	Screenshot:  true,
+}
+
+type AddHyperlinkInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int    `json:"slide_number" jsonschema_description:"Slide containing the shape (1-based indexing)"`
+	ShapeID          string `json:"shape_id,omitempty" jsonschema_description:"(Optional) shape_id of the shape from read_slide; used instead of shape_index"`
+	ShapeIndex       int    `json:"shape_index" jsonschema_description:"Index of the shape to link; ignored when shape_id is given"`
+	Text             string `json:"text,omitempty" jsonschema_description:"(Optional) Text inside the shape to turn into the link; omit to link the whole shape"`
+	Occurrence       int    `json:"occurrence,omitempty" jsonschema_description:"(Optional) Which occurrence of text to link, starting at 1 (default 1)"`
+	URL              string `json:"url,omitempty" jsonschema_description:"(Optional) Web or email link: an http, https or mailto URL"`
+	TargetSlide      int    `json:"target_slide,omitempty" jsonschema_description:"(Optional) Slide number to jump to (1-based)"`
+}
+
+var AddHyperlinkInputSchema = GenerateSchema[AddHyperlinkInput]()
+
+func AddHyperlink(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	linkInput := AddHyperlinkInput{}
+	err := json.Unmarshal(input, &linkInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if linkInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			linkInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if linkInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if linkInput.ShapeID != "" {
+		index, err := resolveShapeID(ctx, app, linkInput.PresentationPath, linkInput.SlideNumber, linkInput.ShapeID)
+		if err != nil {
+			return "", err
+		}
+		linkInput.ShapeIndex = index
+	}
+	if linkInput.ShapeIndex < 0 {
+		return "", NewToolError(ErrCodeShapeNotFound, "shape_index must be 0 or greater")
+	}
+	if (linkInput.URL == "") == (linkInput.TargetSlide == 0) {
+		return "", NewToolError(ErrCodeInvalidInput, "give exactly one of url and target_slide")
+	}
+	if linkInput.URL != "" && !isLinkURL(linkInput.URL) {
+		return "", NewToolError(ErrCodeInvalidInput, "url must be an http, https or mailto URL")
+	}
+	if linkInput.Occurrence == 0 {
+		linkInput.Occurrence = 1
+	}
+	if linkInput.Occurrence < 0 || (linkInput.Text == "" && linkInput.Occurrence != 1) {
+		return "", NewToolError(ErrCodeInvalidInput, "occurrence must be 1 or greater and needs text")
+	}
+	if linkInput.TargetSlide != 0 {
+		if linkInput.TargetSlide < 0 {
+			return "", NewToolError(ErrCodeSlideOutOfRange, "target_slide must be 1 or greater")
+		}
+		if isOOXMLPackage(linkInput.PresentationPath) {
+			if pkg, err := openPPTX(linkInput.PresentationPath); err == nil {
+				slideCount := pkg.SlideCount()
+				pkg.Close()
+				if linkInput.TargetSlide > slideCount {
+					return "", NewToolError(ErrCodeSlideOutOfRange, "target_slide %d is not between 1 and %d", linkInput.TargetSlide, slideCount)
+				}
+			}
+		}
+	}
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_add_hyperlink.py", linkInput.PresentationPath,
+		fmt.Sprintf("%d", linkInput.SlideNumber), fmt.Sprintf("%d", linkInput.ShapeIndex),
+		linkInput.Text, fmt.Sprintf("%d", linkInput.Occurrence), linkInput.URL, fmt.Sprintf("%d", linkInput.TargetSlide))
+	if err != nil {
+		return "", scriptError("failed to add hyperlink", err, output)
+	}
+
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, linkInput.PresentationPath, linkInput.SlideNumber)
+
+	return marshalResult(result)
+}
+
+// SetTransitionDefinition defines the set_transition tool
+var SetTransitionDefinition = ToolDefinition{
+	Name: "set_transition",
+	Description: `Configure slide transitions and automatic advance, for chosen slides or the whole deck.
+
+Transitions: 'none', 'fade' (subtle crossfade), 'fade_through_black', 'dissolve', 'push', 'cover', 'uncover', 'wipe', 'split', 'circle', 'random_bars' and 'checkerboard'. push, cover, uncover and wipe take a direction: 'from_right' (default), 'from_left', 'from_top' or 'from_bottom'. duration is the transition's length in seconds. advance_after makes slides move on by themselves after that many seconds (0 goes back to advancing on click; clicking always advances). Settings left out stay as they are, so e.g. only advance_after can be changed.
+
+Leave slides empty to apply the settings to every slide.`,
+	InputSchema: SetTransitionInputSchema,
+	Function:    SetTransition,
+	Mutating:    true,
+}
+
+type SetTransitionInput struct {
+	PresentationPath string   `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Slides           []int    `json:"slides,omitempty" jsonschema_description:"(Optional) Slide numbers to change (1-based); omit for the whole deck"`
+	Transition       string   `json:"transition,omitempty" jsonschema_description:"(Optional) Transition effect, e.g. 'fade', 'push' or 'none'"`
+	Direction        string   `json:"direction,omitempty" jsonschema_description:"(Optional) For push, cover, uncover and wipe: 'from_right' (default), 'from_left', 'from_top' or 'from_bottom'"`
+	Duration         float64  `json:"duration,omitempty" jsonschema_description:"(Optional) Transition length in seconds, e.g. 0.5"`
+	AdvanceAfter     *float64 `json:"advance_after,omitempty" jsonschema_description:"(Optional) Seconds after which slides advance automatically; 0 to advance on click only"`
+}
+
+var SetTransitionInputSchema = GenerateSchema[SetTransitionInput]()
+
+// slideTransitions are the transition names uno_set_transition.py understands
+var slideTransitions = []string{"none", "fade", "fade_through_black", "dissolve", "push", "cover", "uncover", "wipe", "split", "circle", "random_bars", "checkerboard"}
+
+// directionalTransitions take a direction
+var directionalTransitions = []string{"push", "cover", "uncover", "wipe"}
+
+func SetTransition(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	transitionInput := SetTransitionInput{}
+	err := json.Unmarshal(input, &transitionInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if transitionInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			transitionInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if transitionInput.Transition == "" && transitionInput.Duration == 0 && transitionInput.AdvanceAfter == nil {
+		return "", NewToolError(ErrCodeInvalidInput, "give at least one of transition, duration and advance_after")
+	}
+	if transitionInput.Transition != "" && !slices.Contains(slideTransitions, transitionInput.Transition) {
+		return "", NewToolError(ErrCodeInvalidInput, "unknown transition %q; use one of: %s", transitionInput.Transition, strings.Join(slideTransitions, ", "))
+	}
+	switch transitionInput.Direction {
+	case "":
+	case "from_left", "from_right", "from_top", "from_bottom":
+		if !slices.Contains(directionalTransitions, transitionInput.Transition) {
+			return "", NewToolError(ErrCodeInvalidInput, "direction only applies to the %s transitions", strings.Join(directionalTransitions, ", "))
+		}
+	default:
+		return "", NewToolError(ErrCodeInvalidInput, "direction must be 'from_left', 'from_right', 'from_top' or 'from_bottom'")
+	}
+	if transitionInput.Duration < 0 || transitionInput.Duration > 60 {
+		return "", NewToolError(ErrCodeInvalidInput, "duration must be between 0 and 60 seconds")
+	}
+	if advance := transitionInput.AdvanceAfter; advance != nil && (*advance < 0 || *advance > 3600) {
+		return "", NewToolError(ErrCodeInvalidInput, "advance_after must be between 0 and 3600 seconds")
+	}
+	for _, slideNumber := range transitionInput.Slides {
+		if slideNumber < 1 {
+			return "", NewToolError(ErrCodeSlideOutOfRange, "slide numbers must be 1 or greater")
+		}
+	}
+
+	slidesJSON, _ := json.Marshal(transitionInput.Slides)
+	if transitionInput.Slides == nil {
+		slidesJSON = []byte("[]")
+	}
+	settingsJSON, _ := json.Marshal(map[string]interface{}{
+		"transition":    transitionInput.Transition,
+		"direction":     transitionInput.Direction,
+		"duration":      transitionInput.Duration,
+		"advance_after": transitionInput.AdvanceAfter,
+	})
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_set_transition.py", transitionInput.PresentationPath,
+		string(slidesJSON), string(settingsJSON))
+	if err != nil {
+		return "", scriptError("failed to set transition", err, output)
+	}
+
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+
+	return marshalResult(result)
+}
+
+// AddAnimationDefinition defines the add_animation tool
+var AddAnimationDefinition = ToolDefinition{
+	Name: "add_animation",
+	Description: `Animate a shape during the slide show with an entrance, exit or emphasis effect.
+
+Effects by effect_type:
+- entrance: 'appear', 'fade', 'fly_in', 'wipe'
+- exit: 'disappear', 'fade', 'fly_out', 'wipe'
+- emphasis: 'spin', 'grow_shrink'
+fly_in, fly_out and wipe take a direction: 'from_left' (default), 'from_right', 'from_top' or 'from_bottom'.
+
+trigger decides when the effect plays: 'on_click' (default) starts a new animation step, 'with_previous' plays together with the previous effect and 'after_previous' once it has finished. Effects are added at the end of the slide's sequence; order inserts an on_click step at that position (1-based) or, for the other triggers, joins that step. delay and duration are in seconds. Set replace_existing to drop the shape's earlier effects first. Use read_slide first to find the shape_id (or shape_index).`,
+	InputSchema: AddAnimationInputSchema,
+	Function:    AddAnimation,
+	Mutating:    true,
+}
+
+type AddAnimationInput struct {
+	PresentationPath string  `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int     `json:"slide_number" jsonschema_description:"Slide containing the shape (1-based indexing)"`
+	ShapeID          string  `json:"shape_id,omitempty" jsonschema_description:"(Optional) shape_id of the shape from read_slide; used instead of shape_index"`
+	ShapeIndex       int     `json:"shape_index" jsonschema_description:"Index of the shape to animate; ignored when shape_id is given"`
+	EffectType       string  `json:"effect_type" jsonschema_description:"Kind of effect: 'entrance', 'exit' or 'emphasis'"`
+	Effect           string  `json:"effect" jsonschema_description:"Effect name, e.g. 'appear', 'fade' or 'fly_in'"`
+	Direction        string  `json:"direction,omitempty" jsonschema_description:"(Optional) For fly_in, fly_out and wipe: 'from_left' (default), 'from_right', 'from_top' or 'from_bottom'"`
+	Trigger          string  `json:"trigger,omitempty" jsonschema_description:"(Optional) 'on_click' (default), 'with_previous' or 'after_previous'"`
+	Duration         float64 `json:"duration,omitempty" jsonschema_description:"(Optional) Effect length in seconds; defaults depend on the effect"`
+	Delay            float64 `json:"delay,omitempty" jsonschema_description:"(Optional) Seconds to wait after the trigger before the effect starts"`
+	Order            int     `json:"order,omitempty" jsonschema_description:"(Optional) Animation step to insert at or join (1-based); defaults to the end"`
+	ReplaceExisting  bool    `json:"replace_existing,omitempty" jsonschema_description:"(Optional) Remove the shape's existing effects first"`
+}
+
+var AddAnimationInputSchema = GenerateSchema[AddAnimationInput]()
+
+// animationEffects are the effects uno_add_animation.py understands, by effect type
+var animationEffects = map[string][]string{
+	"entrance": {"appear", "fade", "fly_in", "wipe"},
+	"exit":     {"disappear", "fade", "fly_out", "wipe"},
+	"emphasis": {"spin", "grow_shrink"},
+}
+
+// directionalAnimations take a direction
+var directionalAnimations = []string{"fly_in", "fly_out", "wipe"}
+
+func AddAnimation(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	animationInput := AddAnimationInput{}
+	err := json.Unmarshal(input, &animationInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if animationInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			animationInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if animationInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if animationInput.ShapeID != "" {
+		index, err := resolveShapeID(ctx, app, animationInput.PresentationPath, animationInput.SlideNumber, animationInput.ShapeID)
+		if err != nil {
+			return "", err
+		}
+		animationInput.ShapeIndex = index
+	}
+	if animationInput.ShapeIndex < 0 {
+		return "", NewToolError(ErrCodeShapeNotFound, "shape_index must be 0 or greater")
+	}
+	effects, ok := animationEffects[animationInput.EffectType]
+	if !ok {
+		return "", NewToolError(ErrCodeInvalidInput, "effect_type must be 'entrance', 'exit' or 'emphasis'")
+	}
+	if !slices.Contains(effects, animationInput.Effect) {
+		return "", NewToolError(ErrCodeInvalidInput, "unknown %s effect %q; use one of: %s", animationInput.EffectType, animationInput.Effect, strings.Join(effects, ", "))
+	}
+	switch animationInput.Direction {
+	case "":
+	case "from_left", "from_right", "from_top", "from_bottom":
+		if !slices.Contains(directionalAnimations, animationInput.Effect) {
+			return "", NewToolError(ErrCodeInvalidInput, "direction only applies to the %s effects", strings.Join(directionalAnimations, ", "))
+		}
+	default:
+		return "", NewToolError(ErrCodeInvalidInput, "direction must be 'from_left', 'from_right', 'from_top' or 'from_bottom'")
+	}
+	switch animationInput.Trigger {
+	case "", "on_click", "with_previous", "after_previous":
+	default:
+		return "", NewToolError(ErrCodeInvalidInput, "trigger must be 'on_click', 'with_previous' or 'after_previous'")
+	}
+	if animationInput.Duration < 0 || animationInput.Duration > 60 {
+		return "", NewToolError(ErrCodeInvalidInput, "duration must be between 0 and 60 seconds")
+	}
+	if animationInput.Delay < 0 || animationInput.Delay > 60 {
+		return "", NewToolError(ErrCodeInvalidInput, "delay must be between 0 and 60 seconds")
+	}
+	if animationInput.Order < 0 {
+		return "", NewToolError(ErrCodeInvalidInput, "order must be 1 or greater")
+	}
+
+	settingsJSON, _ := json.Marshal(map[string]interface{}{
+		"effect_type":      animationInput.EffectType,
+		"effect":           animationInput.Effect,
+		"direction":        animationInput.Direction,
+		"trigger":          animationInput.Trigger,
+		"duration":         animationInput.Duration,
+		"delay":            animationInput.Delay,
+		"order":            animationInput.Order,
+		"replace_existing": animationInput.ReplaceExisting,
+	})
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_add_animation.py", animationInput.PresentationPath,
+		fmt.Sprintf("%d", animationInput.SlideNumber), fmt.Sprintf("%d", animationInput.ShapeIndex), string(settingsJSON))
+	if err != nil {
+		return "", scriptError("failed to add animation", err, output)
+	}
+
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+
+	return marshalResult(result)
+}
+
+// Chart sizes beyond these are unreadable on a slide and almost certainly a mistake
+const (
+	maxChartCategories = 100
+	maxChartSeries     = 20
+)
+
+// chartTypes are the chart kinds uno_insert_chart.py can create
+var chartTypes = []string{"bar", "column", "line", "area", "pie"}
+
+// InsertChartDefinition defines the insert_chart tool
+var InsertChartDefinition = ToolDefinition{
+	Name: "insert_chart",
+	Description: `Insert a native, editable chart built from the given data onto a slide.
+
+chart_type is 'column' (vertical bars), 'bar' (horizontal bars), 'line', 'area' or 'pie'. categories are the labels along the axis (or the pie slices); each series has a name and one value per category. Pie charts take exactly one series. Position and size are in inches; by default the chart covers 70% x 60% of the slide and is centered. The result includes the chart's shape_id. Use this instead of drawing charts out of shapes and text boxes, and import_data for data in a CSV or Excel file.`,
+	InputSchema: InsertChartInputSchema,
+	Function:    InsertChart,
+	Mutating:    true,
+	Screenshot:  true,
+}
+
+type InsertChartInput struct {
+	PresentationPath string        `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int           `json:"slide_number" jsonschema_description:"Slide to insert the chart on (1-based indexing)"`
+	ChartType        string        `json:"chart_type" jsonschema_description:"Kind of chart: 'column', 'bar', 'line', 'area' or 'pie'"`
+	Categories       []string      `json:"categories" jsonschema_description:"Category labels, e.g. ['Q1', 'Q2', 'Q3', 'Q4']"`
+	Series           []ChartSeries `json:"series" jsonschema_description:"Data series, each with one value per category"`
+	Title            string        `json:"title,omitempty" jsonschema_description:"(Optional) Chart title"`
+	HideLegend       bool          `json:"hide_legend,omitempty" jsonschema_description:"(Optional) Leave out the legend"`
+	X                *float64      `json:"x,omitempty" jsonschema_description:"(Optional) Left position in inches"`
+	Y                *float64      `json:"y,omitempty" jsonschema_description:"(Optional) Top position in inches"`
+	Width            *float64      `json:"width,omitempty" jsonschema_description:"(Optional) Chart width in inches"`
+	Height           *float64      `json:"height,omitempty" jsonschema_description:"(Optional) Chart height in inches"`
+}
+
+// ChartSeries is one named row of chart values
+type ChartSeries struct {
+	Name   string    `json:"name" jsonschema_description:"Series name shown in the legend, e.g. 'Revenue'"`
+	Values []float64 `json:"values" jsonschema_description:"One value per category"`
+}
+
+var InsertChartInputSchema = GenerateSchema[InsertChartInput]()
+
+// validateChartData checks that the series line up with the categories
+func validateChartData(chartType string, categories []string, series []ChartSeries) error {
+	if len(categories) == 0 || len(categories) > maxChartCategories {
+		return NewToolError(ErrCodeInvalidInput, "give between 1 and %d categories", maxChartCategories)
+	}
+	if len(series) == 0 || len(series) > maxChartSeries {
+		return NewToolError(ErrCodeInvalidInput, "give between 1 and %d series", maxChartSeries)
+	}
+	if chartType == "pie" && len(series) != 1 {
+		return NewToolError(ErrCodeInvalidInput, "pie charts take exactly one series, got %d", len(series))
+	}
+	for i, s := range series {
+		if len(s.Values) != len(categories) {
+			return NewToolError(ErrCodeInvalidInput, "series %d (%s) has %d values but there are %d categories", i, s.Name, len(s.Values), len(categories))
+		}
+	}
+	return nil
+}
+
+func InsertChart(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	chartInput := InsertChartInput{}
+	err := json.Unmarshal(input, &chartInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if chartInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			chartInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if chartInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if !slices.Contains(chartTypes, chartInput.ChartType) {
+		return "", NewToolError(ErrCodeInvalidInput, "unknown chart_type %q, must be one of %s", chartInput.ChartType, strings.Join(chartTypes, ", ")).
+			WithDetail("valid_chart_types", chartTypes)
+	}
+	if err := validateChartData(chartInput.ChartType, chartInput.Categories, chartInput.Series); err != nil {
+		return "", err
+	}
+	if (chartInput.Width != nil && *chartInput.Width <= 0) || (chartInput.Height != nil && *chartInput.Height <= 0) {
+		return "", NewToolError(ErrCodeInvalidInput, "width and height must be greater than 0")
+	}
+
+	chartJSON, _ := json.Marshal(map[string]interface{}{
+		"categories": chartInput.Categories,
+		"series":     chartInput.Series,
+		"title":      chartInput.Title,
+		"legend":     !chartInput.HideLegend,
+	})
+	args := []string{
+		chartInput.PresentationPath,
+		fmt.Sprintf("%d", chartInput.SlideNumber),
+		chartInput.ChartType,
+		string(chartJSON),
+	}
+	args = append(args, optionalFloatArgs(chartInput.X, chartInput.Y, chartInput.Width, chartInput.Height)...)
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_insert_chart.py", args...)
+	if err != nil {
+		return "", scriptError("failed to insert chart", err, output)
+	}
+
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, chartInput.PresentationPath, chartInput.SlideNumber)
+
+	return marshalResult(result)
+}
+
+// EditChartDataDefinition defines the edit_chart_data tool
+var EditChartDataDefinition = ToolDefinition{
+	Name: "edit_chart_data",
+	Description: `Read or update the data of an existing chart: its title, categories, series names and values.
+
+Call it with only slide_number and shape_id (or shape_index) to read the chart's current title, categories and series. To update, give any of:
+- title: new chart title ('' removes it)
+- categories: new category labels; the series must still have one value per category, so give series too when their number changes
+- series: replaces all series, each with a name and one value per category
+- series_names: renames the series in order without touching their values
+The result holds the chart's data after the change and its previous data. Use read_slide first to find the chart's shape_id (or shape_index).`,
+	InputSchema: EditChartDataInputSchema,
+	Function:    EditChartData,
+	Mutating:    true,
+	Screenshot:  true,
+}
+
+type EditChartDataInput struct {
+	PresentationPath string        `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int           `json:"slide_number" jsonschema_description:"Slide containing the chart (1-based indexing)"`
+	ShapeID          string        `json:"shape_id,omitempty" jsonschema_description:"(Optional) shape_id of the chart from read_slide; used instead of shape_index"`
+	ShapeIndex       int           `json:"shape_index" jsonschema_description:"Index of the chart shape; ignored when shape_id is given"`
+	Title            *string       `json:"title,omitempty" jsonschema_description:"(Optional) New chart title; empty removes it"`
+	Categories       []string      `json:"categories,omitempty" jsonschema_description:"(Optional) New category labels"`
+	Series           []ChartSeries `json:"series,omitempty" jsonschema_description:"(Optional) New data series replacing the existing ones"`
+	SeriesNames      []string      `json:"series_names,omitempty" jsonschema_description:"(Optional) New names for the existing series, in order"`
+}
+
+var EditChartDataInputSchema = GenerateSchema[EditChartDataInput]()
+
+func EditChartData(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	chartInput := EditChartDataInput{}
+	err := json.Unmarshal(input, &chartInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if chartInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			chartInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if chartInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	if chartInput.ShapeID != "" {
+		index, err := resolveShapeID(ctx, app, chartInput.PresentationPath, chartInput.SlideNumber, chartInput.ShapeID)
+		if err != nil {
+			return "", err
+		}
+		chartInput.ShapeIndex = index
+	}
+	if chartInput.ShapeIndex < 0 {
+		return "", NewToolError(ErrCodeShapeNotFound, "shape_index must be 0 or greater")
+	}
+
+	// Only changed fields are passed on; an empty object just reads the chart
+	changes := map[string]interface{}{}
+	if chartInput.Title != nil {
+		changes["title"] = *chartInput.Title
+	}
+	if len(chartInput.Series) > 0 {
+		categories := chartInput.Categories
+		if len(categories) == 0 {
+			// Check the series against each other; the script checks them against the chart
+			categories = make([]string, len(chartInput.Series[0].Values))
+		}
+		if err := validateChartData("", categories, chartInput.Series); err != nil {
+			return "", err
+		}
+		changes["series"] = chartInput.Series
+	}
+	if len(chartInput.Categories) > 0 {
+		if len(chartInput.Categories) > maxChartCategories {
+			return "", NewToolError(ErrCodeInvalidInput, "give between 1 and %d categories", maxChartCategories)
+		}
+		changes["categories"] = chartInput.Categories
+	}
+	if len(chartInput.SeriesNames) > 0 {
+		if len(chartInput.Series) > 0 && len(chartInput.SeriesNames) != len(chartInput.Series) {
+			return "", NewToolError(ErrCodeInvalidInput, "series_names has %d names but %d series were given", len(chartInput.SeriesNames), len(chartInput.Series))
+		}
+		changes["series_names"] = chartInput.SeriesNames
+	}
+	changesJSON, _ := json.Marshal(changes)
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_edit_chart_data.py", chartInput.PresentationPath,
+		fmt.Sprintf("%d", chartInput.SlideNumber), fmt.Sprintf("%d", chartInput.ShapeIndex), string(changesJSON))
+	if err != nil {
+		return "", scriptError("failed to edit chart data", err, output)
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return "", invalidScriptOutput(err)
+	}
+
+	// Queue the slide for export to update UI
+	if len(changes) > 0 {
+		schedulePreviewExport(ctx, app, chartInput.PresentationPath, chartInput.SlideNumber)
+	}
+
+	return string(output), nil
+}
+
+// ApplyEditsDefinition defines the apply_edits tool
+var ApplyEditsDefinition = ToolDefinition{
+	Name: "apply_edits",
+	Description: `Apply several edits in one go: the presentation is opened once, every edit is applied, and it is saved only if all of them succeed. If any edit fails, none are kept and the error names the failing edit.
+
+Use this instead of many separate edit_slide_text, delete_shape, etc. calls when a request needs several simple changes, e.g. rewording a few titles, restyling text and moving shapes around. Each edit has an op, a slide_number and the fields of that op:
+- "set_text": replace a shape's text with text, and with autofit set how the shape fits it
+- "replace_text": replace every occurrence of old_text with text, in one shape or (without a shape) in the whole slide
+- "format_text": set bold, italic, color (hex RRGGBB), font_size (points) and/or font_name, and the paragraph alignment (left, center, right, justify), line_spacing (multiple of single spacing, e.g. 1.5) and space_before/space_after (points); for all of a shape's text, or only the paragraphs listed in paragraphs (0-based, one per line of the shape's text)
+- "move_shape": set x, y, width and/or height in inches
+- "delete_shape": remove a shape
+
+Shapes are given by shape_id (preferred) or shape_index, both as read_slide reported them before the batch: deleting a shape doesn't shift the targets of later edits.`,
+	InputSchema: ApplyEditsInputSchema,
+	Function:    ApplyEdits,
+	Mutating:    true,
+	Screenshot:  true,
+}
+
+// maxBatchEdits caps the number of edits apply_edits runs in one session
+const maxBatchEdits = 50
+
+// paragraphAlignments are the alignments the format_text edit sets
+var paragraphAlignments = []string{"left", "center", "right", "justify"}
+
+// maxParagraphSpacing is the most space in points format_text puts above or below a paragraph
+const maxParagraphSpacing = 200
+
+// batchEditOps are the operations apply_edits supports and whether each needs a target shape
+var batchEditOps = map[string]bool{
+	"set_text":     true,
+	"replace_text": false,
+	"format_text":  true,
+	"move_shape":   true,
+	"delete_shape": true,
+}
+
+type ApplyEditsInput struct {
+	PresentationPath string      `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Edits            []BatchEdit `json:"edits" jsonschema_description:"Edits to apply in order"`
+}
+
+type BatchEdit struct {
+	Op          string   `json:"op" jsonschema_description:"'set_text', 'replace_text', 'format_text', 'move_shape' or 'delete_shape'"`
+	SlideNumber int      `json:"slide_number" jsonschema_description:"Slide to edit (1-based indexing)"`
+	ShapeID     string   `json:"shape_id,omitempty" jsonschema_description:"(Optional) shape_id of the target shape from read_slide"`
+	ShapeIndex  *int     `json:"shape_index,omitempty" jsonschema_description:"(Optional) Index of the target shape; used when shape_id is not given"`
+	Text        string   `json:"text,omitempty" jsonschema_description:"New text for set_text, replacement text for replace_text"`
+	OldText     string   `json:"old_text,omitempty" jsonschema_description:"Text to find for replace_text"`
+	Autofit     string   `json:"autofit,omitempty" jsonschema_description:"(Optional) set_text: 'shrink', 'resize' or 'none', how the shape fits its new text"`
+	Bold        *bool    `json:"bold,omitempty" jsonschema_description:"(Optional) format_text: bold on or off"`
+	Italic      *bool    `json:"italic,omitempty" jsonschema_description:"(Optional) format_text: italic on or off"`
+	Color       string   `json:"color,omitempty" jsonschema_description:"(Optional) format_text: text color as hex RRGGBB"`
+	FontSize    float64  `json:"font_size,omitempty" jsonschema_description:"(Optional) format_text: font size in points"`
+	FontName    string   `json:"font_name,omitempty" jsonschema_description:"(Optional) format_text: font family"`
+	Alignment   string   `json:"alignment,omitempty" jsonschema_description:"(Optional) format_text: paragraph alignment, 'left', 'center', 'right' or 'justify'"`
+	LineSpacing float64  `json:"line_spacing,omitempty" jsonschema_description:"(Optional) format_text: line spacing as a multiple of single spacing, e.g. 1.5"`
+	SpaceBefore *float64 `json:"space_before,omitempty" jsonschema_description:"(Optional) format_text: space above each paragraph in points"`
+	SpaceAfter  *float64 `json:"space_after,omitempty" jsonschema_description:"(Optional) format_text: space below each paragraph in points"`
+	Paragraphs  []int    `json:"paragraphs,omitempty" jsonschema_description:"(Optional) format_text: paragraph indexes to format (0-based); omit for all of the shape's text"`
+	X           *float64 `json:"x,omitempty" jsonschema_description:"(Optional) move_shape: left edge in inches"`
+	Y           *float64 `json:"y,omitempty" jsonschema_description:"(Optional) move_shape: top edge in inches"`
+	Width       *float64 `json:"width,omitempty" jsonschema_description:"(Optional) move_shape: width in inches"`
+	Height      *float64 `json:"height,omitempty" jsonschema_description:"(Optional) move_shape: height in inches"`
+}
+
+var ApplyEditsInputSchema = GenerateSchema[ApplyEditsInput]()
+
+func ApplyEdits(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	editsInput := ApplyEditsInput{}
+	err := json.Unmarshal(input, &editsInput)
+	if err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if editsInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			editsInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if len(editsInput.Edits) == 0 || len(editsInput.Edits) > maxBatchEdits {
+		return "", NewToolError(ErrCodeInvalidInput, "give between 1 and %d edits", maxBatchEdits)
+	}
+
+	var slides []int
+	for i, edit := range editsInput.Edits {
+		if err := validateBatchEdit(edit); err != nil {
+			return "", NewToolError(ErrCodeInvalidInput, "edit %d (%s): %v", i+1, edit.Op, err)
+		}
+		editsInput.Edits[i].Text = strings.ReplaceAll(edit.Text, "\r\n", "\n")
+		editsInput.Edits[i].Color = strings.TrimPrefix(edit.Color, "#")
+		if !slices.Contains(slides, edit.SlideNumber) {
+			slides = append(slides, edit.SlideNumber)
+		}
+	}
+	editsJSON, _ := json.Marshal(editsInput.Edits)
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_apply_edits.py", editsInput.PresentationPath, string(editsJSON))
+	if err != nil {
+		return "", scriptError("failed to apply edits", err, output)
+	}
+
+	result, err := editResultFromScript(output)
+	if err != nil {
+		return "", err
+	}
+
+	// One export for the whole batch
+	schedulePreviewExport(ctx, app, editsInput.PresentationPath, slides...)
+
+	return marshalResult(result)
+}
+
+// validateBatchEdit checks that an edit has the fields its op needs
+func validateBatchEdit(edit BatchEdit) error {
+	needsShape, known := batchEditOps[edit.Op]
+	if !known {
+		return fmt.Errorf("unknown op, expected set_text, replace_text, format_text, move_shape or delete_shape")
+	}
+	if edit.SlideNumber < 1 {
+		return fmt.Errorf("slide_number must be 1 or greater")
+	}
+	if needsShape && edit.ShapeID == "" && edit.ShapeIndex == nil {
+		return fmt.Errorf("shape_id or shape_index is required")
+	}
+	if edit.ShapeIndex != nil && *edit.ShapeIndex < 0 {
+		return fmt.Errorf("shape_index must be 0 or greater")
+	}
+
+	switch edit.Op {
+	case "set_text":
+		if edit.Text == "" {
+			return fmt.Errorf("text is required")
+		}
+		if !validAutofit(edit.Autofit) {
+			return fmt.Errorf("autofit must be 'shrink', 'resize' or 'none'")
+		}
+	case "replace_text":
+		if edit.OldText == "" {
+			return fmt.Errorf("old_text is required")
+		}
+	case "format_text":
+		if edit.Bold == nil && edit.Italic == nil && edit.Color == "" && edit.FontSize == 0 && edit.FontName == "" &&
+			edit.Alignment == "" && edit.LineSpacing == 0 && edit.SpaceBefore == nil && edit.SpaceAfter == nil {
+			return fmt.Errorf("give at least one of bold, italic, color, font_size, font_name, alignment, line_spacing, space_before and space_after")
+		}
+		if edit.Color != "" && !hexColorPattern.MatchString(edit.Color) {
+			return fmt.Errorf("invalid color %q, expected hex RRGGBB such as 1F4E79", edit.Color)
+		}
+		if edit.FontSize < 0 || edit.FontSize > 400 {
+			return fmt.Errorf("font_size must be between 1 and 400 points")
+		}
+		if edit.Alignment != "" && !slices.Contains(paragraphAlignments, edit.Alignment) {
+			return fmt.Errorf("alignment must be one of: %s", strings.Join(paragraphAlignments, ", "))
+		}
+		if edit.LineSpacing != 0 && (edit.LineSpacing < 0.5 || edit.LineSpacing > 5) {
+			return fmt.Errorf("line_spacing must be between 0.5 and 5")
+		}
+		for _, space := range []*float64{edit.SpaceBefore, edit.SpaceAfter} {
+			if space != nil && (*space < 0 || *space > maxParagraphSpacing) {
+				return fmt.Errorf("space_before and space_after must be between 0 and %d points", maxParagraphSpacing)
+			}
+		}
+		for _, paragraph := range edit.Paragraphs {
+			if paragraph < 0 {
+				return fmt.Errorf("paragraph indexes must be 0 or greater")
+			}
+		}
+	case "move_shape":
+		if edit.X == nil && edit.Y == nil && edit.Width == nil && edit.Height == nil {
+			return fmt.Errorf("give at least one of x, y, width and height")
+		}
+		for _, size := range []*float64{edit.Width, edit.Height} {
+			if size != nil && *size <= 0 {
+				return fmt.Errorf("width and height must be greater than 0")
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,1076 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListSlidesReadsPackageNatively(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+
+	result, err := ListSlides(context.Background(), env.app, json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("ListSlides failed: %v", err)
+	}
+
+	var listing struct {
+		TotalSlides int  `json:"total_slides"`
+		HasMore     bool `json:"has_more"`
+		Slides      []struct {
+			SlideNumber int    `json:"slide_number"`
+			Title       string `json:"title"`
+			Layout      string `json:"layout"`
+			TextShapes  int    `json:"text_shapes"`
+		} `json:"slides"`
+	}
+	if err := json.Unmarshal([]byte(result), &listing); err != nil {
+		t.Fatalf("invalid result %s: %v", result, err)
+	}
+	if listing.TotalSlides != 2 || listing.HasMore || len(listing.Slides) != 2 {
+		t.Fatalf("unexpected listing: %s", result)
+	}
+	first := listing.Slides[0]
+	if first.Title != "Quarterly Review" || first.Layout != "Title and Content" || first.TextShapes != 2 {
+		t.Errorf("unexpected first slide: %+v", first)
+	}
+	if listing.Slides[1].Title != "Next Steps" {
+		t.Errorf("unexpected second slide title %q", listing.Slides[1].Title)
+	}
+	if calls := env.uno.Calls("uno_list_slides.py"); len(calls) != 0 {
+		t.Errorf("expected no UNO calls for a .pptx, got %+v", calls)
+	}
+}
+
+func TestListSlidesPagesNativeResults(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+
+	result, err := ListSlides(context.Background(), env.app, json.RawMessage(`{"limit": 1, "titles_only": true}`))
+	if err != nil {
+		t.Fatalf("ListSlides failed: %v", err)
+	}
+	if result != `{"total_slides":2,"offset":0,"returned":1,"has_more":true,"next_offset":1,"slides":[{"slide_number":1,"title":"Quarterly Review"}]}` {
+		t.Errorf("unexpected first page: %s", result)
+	}
+
+	result, err = ListSlides(context.Background(), env.app, json.RawMessage(`{"offset": 5}`))
+	if err != nil {
+		t.Fatalf("ListSlides failed: %v", err)
+	}
+	if result != `{"total_slides":2,"offset":5,"returned":0,"has_more":false,"slides":[]}` {
+		t.Errorf("unexpected page past the end: %s", result)
+	}
+
+	_, err = ListSlides(context.Background(), env.app, json.RawMessage(`{"offset": -1}`))
+	if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+		t.Errorf("expected %s for negative offset, got %s", ErrCodeInvalidInput, code)
+	}
+}
+
+func TestListSlidesFallsBackToUnoForOtherFormats(t *testing.T) {
+	env := newTestEnv(t)
+	fixture := env.loadFixture(t, "two_slides.pptx")
+	path := strings.TrimSuffix(fixture, ".pptx") + ".odp"
+	if err := os.Rename(fixture, path); err != nil {
+		t.Fatal(err)
+	}
+	env.uno.Respond("uno_list_slides.py", `{"total_slides": 300, "slides": []}`)
+
+	input := fmt.Sprintf(`{"presentation_path": %q, "offset": 100, "limit": 500, "titles_only": true}`, path)
+	if _, err := ListSlides(context.Background(), env.app, json.RawMessage(input)); err != nil {
+		t.Fatalf("ListSlides failed: %v", err)
+	}
+
+	calls := env.uno.Calls("uno_list_slides.py")
+	expected := []string{path, "100", "200", "true"}
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint(expected) {
+		t.Errorf("expected one call with %v, got %+v", expected, calls)
+	}
+}
+
+func TestListSlidesWithoutPresentation(t *testing.T) {
+	env := newTestEnv(t)
+
+	_, err := ListSlides(context.Background(), env.app, json.RawMessage(`{}`))
+	if code := toolErrorCode(err); code != ErrCodeNoPresentation {
+		t.Fatalf("expected %s, got %s (%v)", ErrCodeNoPresentation, code, err)
+	}
+}
+
+func TestReadSlideReportsOutOfRangeSlides(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+
+	_, err := ReadSlide(context.Background(), env.app, json.RawMessage(`{"slide_number": 9}`))
+	if code := toolErrorCode(err); code != ErrCodeSlideOutOfRange {
+		t.Fatalf("expected %s, got %s (%v)", ErrCodeSlideOutOfRange, code, err)
+	}
+}
+
+func TestReadSlideClassifiesScriptErrors(t *testing.T) {
+	env := newTestEnv(t)
+	fixture := env.loadFixture(t, "two_slides.pptx")
+	path := strings.TrimSuffix(fixture, ".pptx") + ".odp"
+	if err := os.Rename(fixture, path); err != nil {
+		t.Fatal(err)
+	}
+	env.uno.Handle("uno_read_slide.py", func(args []string) ([]byte, error) {
+		return []byte(`{"success": false, "error": "Error reading slide: Slide number 9 out of range (1-2)"}`), fmt.Errorf("exit status 1")
+	})
+
+	input := fmt.Sprintf(`{"presentation_path": %q, "slide_number": 9}`, path)
+	_, err := ReadSlide(context.Background(), env.app, json.RawMessage(input))
+	if code := toolErrorCode(err); code != ErrCodeSlideOutOfRange {
+		t.Fatalf("expected %s, got %s (%v)", ErrCodeSlideOutOfRange, code, err)
+	}
+}
+
+func TestEditSlideTextAutoExports(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_edit_slide.py", `{"success": true, "message": "Changed shape 0"}`)
+
+	_, err := EditSlideText(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "target_type": "shape_index", "target_value": "0", "new_text": "Hello"}`))
+	if err != nil {
+		t.Fatalf("EditSlideText failed: %v", err)
+	}
+
+	calls := env.uno.Calls("uno_edit_slide.py")
+	if len(calls) != 1 {
+		t.Fatalf("expected one edit call, got %d", len(calls))
+	}
+	expected := []string{path, "1", "shape_index", "0", "Hello"}
+	for i, arg := range expected {
+		if calls[0].Args[i] != arg {
+			t.Errorf("arg %d: expected %q, got %q", i, arg, calls[0].Args[i])
+		}
+	}
+	env.app.exports.Flush(context.Background())
+	if len(env.converter.RangeCalls) != 1 || env.converter.RangeCalls[0] != [2]int{0, 0} {
+		t.Errorf("expected only the edited slide to be exported, got %v", env.converter.RangeCalls)
+	}
+}
+
+func TestEditResultTypesCommonFields(t *testing.T) {
+	result, err := editResultFromScript([]byte(`{"success": true, "slide_number": 2, "shape_index": 3, "shape_id": "Table 1", "message": "Done", "rows": 4}`))
+	if err != nil {
+		t.Fatalf("editResultFromScript failed: %v", err)
+	}
+	if result.SlideNumber != 2 || result.ShapeIndex == nil || *result.ShapeIndex != 3 || result.ShapeID != "Table 1" || result.Message != "Done" {
+		t.Errorf("unexpected typed fields: %+v", result)
+	}
+	if len(result.Details) != 1 || result.Details["rows"] != float64(4) {
+		t.Errorf("expected only rows in details, got %v", result.Details)
+	}
+
+	resultJSON, _ := marshalResult(result)
+	envelope := normalizeToolResult(resultJSON)
+	if envelope != `{"success":true,"data":{"message":"Done","slide_number":2,"shape_index":3,"shape_id":"Table 1","details":{"rows":4}}}` {
+		t.Errorf("unexpected envelope: %s", envelope)
+	}
+	failure := `{"success": false, "error": "boom"}`
+	if normalizeToolResult(failure) != failure {
+		t.Errorf("failures should pass through unchanged")
+	}
+}
+
+func TestEditSlideTextValidatesInput(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+
+	_, err := EditSlideText(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "target_type": "text_replace", "target_value": "x", "new_text": "y"}`))
+	if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+		t.Fatalf("expected %s, got %s (%v)", ErrCodeInvalidInput, code, err)
+	}
+	if len(env.uno.Calls("uno_edit_slide.py")) != 0 {
+		t.Error("script should not run for invalid input")
+	}
+}
+
+func TestVerifyPackageCountsSlides(t *testing.T) {
+	slides, err := verifyPackage(fixtureDir + "/two_slides.pptx")
+	if err != nil {
+		t.Fatalf("verifyPackage failed: %v", err)
+	}
+	if slides != 2 {
+		t.Errorf("expected 2 slides, got %d", slides)
+	}
+}
+
+func TestVerifyPackageRejectsCorruptFile(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	if err := os.WriteFile(path, []byte("not a zip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := verifyPackage(path); err == nil {
+		t.Fatal("expected corrupt package to fail verification")
+	}
+}
+
+func TestExportSlidesWithCustomOptions(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+
+	result, err := ExportSlides(context.Background(), env.app, json.RawMessage(`{"format": "webp", "dpi": 300, "slide_numbers": [2]}`))
+	if err != nil {
+		t.Fatalf("ExportSlides failed: %v", err)
+	}
+	var exported struct {
+		Slides    []string `json:"slides"`
+		OutputDir string   `json:"output_dir"`
+	}
+	json.Unmarshal([]byte(result), &exported)
+	if exported.OutputDir != "exports" || len(exported.Slides) != 1 || filepath.Base(exported.Slides[0]) != "slide-001.webp" {
+		t.Errorf("unexpected result %s", result)
+	}
+	if len(env.converter.Exports) != 1 || env.converter.Exports[0].DPI != 300 || len(env.converter.Calls) != 0 {
+		t.Errorf("expected one custom export and no preview export, got %+v and %v", env.converter.Exports, env.converter.Calls)
+	}
+
+	// Custom images must not replace the app's previews
+	_, err = ExportSlides(context.Background(), env.app, json.RawMessage(`{"format": "png", "output_dir": "slides"}`))
+	if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+		t.Errorf("expected %s, got %s (%v)", ErrCodeInvalidInput, code, err)
+	}
+	_, err = ExportSlides(context.Background(), env.app, json.RawMessage(`{"quality": 0, "dpi": 2}`))
+	if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+		t.Errorf("expected %s for an invalid dpi, got %s (%v)", ErrCodeInvalidInput, code, err)
+	}
+}
+
+func TestAddSlideRerendersOnlyFromInsertedPosition(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	if _, err := convertSlides(context.Background(), env.app, env.app.presentationPath(), "slides"); err != nil {
+		t.Fatal(err)
+	}
+	env.converter.Calls = nil
+	env.uno.Respond("uno_add_slide.py", `{"success": true, "new_slide_number": 2, "total_slides": 3}`)
+
+	if _, err := AddSlide(context.Background(), env.app, json.RawMessage(`{"position": 2}`)); err != nil {
+		t.Fatalf("AddSlide failed: %v", err)
+	}
+
+	if len(env.converter.Calls) != 0 {
+		t.Errorf("expected no full export, got %d", len(env.converter.Calls))
+	}
+	if len(env.converter.RangeCalls) != 1 || env.converter.RangeCalls[0] != [2]int{1, 2} {
+		t.Errorf("expected slides 1-2 to be re-rendered, got %v", env.converter.RangeCalls)
+	}
+	if first, _ := os.ReadFile(slidePreviewPath("slides", 0)); string(first) != "fake jpeg" {
+		t.Errorf("slide before the insertion should keep its preview, got %q", first)
+	}
+	if countSlidePreviews("slides") != 3 {
+		t.Errorf("expected 3 previews, got %d", countSlidePreviews("slides"))
+	}
+}
+
+func TestDeleteSlideRenumbersPreviews(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	if _, err := convertSlides(context.Background(), env.app, env.app.presentationPath(), "slides"); err != nil {
+		t.Fatal(err)
+	}
+	env.converter.Calls = nil
+	env.uno.Respond("uno_delete_slide.py", `{"success": true, "deleted_slide_number": 2, "new_slide_count": 1}`)
+
+	if _, err := DeleteSlide(context.Background(), env.app, json.RawMessage(`{"slide_number": 2}`)); err != nil {
+		t.Fatalf("DeleteSlide failed: %v", err)
+	}
+
+	if len(env.converter.Calls) != 0 || len(env.converter.RangeCalls) != 0 {
+		t.Errorf("deleting the last slide should not re-render, got %d full and %v range exports",
+			len(env.converter.Calls), env.converter.RangeCalls)
+	}
+	if countSlidePreviews("slides") != 1 {
+		t.Errorf("expected 1 preview, got %d", countSlidePreviews("slides"))
+	}
+	if _, err := os.Stat(slidePreviewPath("slides", 1)); !os.IsNotExist(err) {
+		t.Error("expected the deleted slide's preview to be removed")
+	}
+}
+
+func TestMoveSlideRerendersOnlyReorderedRange(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	if _, err := convertSlides(context.Background(), env.app, path, "slides"); err != nil {
+		t.Fatal(err)
+	}
+	env.converter.Calls = nil
+	env.uno.Respond("uno_move_slide.py", `{"success": true, "slide_number": 2, "target_position": 1, "slide_count": 2}`)
+
+	if _, err := MoveSlide(context.Background(), env.app, json.RawMessage(`{"slide_number": 2, "target_position": 1}`)); err != nil {
+		t.Fatalf("MoveSlide failed: %v", err)
+	}
+
+	calls := env.uno.Calls("uno_move_slide.py")
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint([]string{path, "2", "1"}) {
+		t.Fatalf("unexpected script calls: %+v", calls)
+	}
+	if len(env.converter.Calls) != 0 || len(env.converter.RangeCalls) != 1 || env.converter.RangeCalls[0] != [2]int{0, 1} {
+		t.Errorf("expected slides 1-2 to be re-rendered, got %d full and %v range exports",
+			len(env.converter.Calls), env.converter.RangeCalls)
+	}
+
+	_, err := MoveSlide(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "target_position": 0}`))
+	if code := toolErrorCode(err); code != ErrCodeSlideOutOfRange {
+		t.Errorf("expected %s, got %s (%v)", ErrCodeSlideOutOfRange, code, err)
+	}
+}
+
+func TestMutatingToolsRefuseLockedPresentations(t *testing.T) {
+	locks := map[string]func(dir, name string) string{
+		"libreoffice": func(dir, name string) string {
+			return filepath.Join(dir, ".~lock."+name+"#")
+		},
+		"powerpoint": func(dir, name string) string {
+			return filepath.Join(dir, "~$"+name[2:])
+		},
+	}
+
+	for app, lockPath := range locks {
+		t.Run(app, func(t *testing.T) {
+			env := newTestEnv(t)
+			env.loadFixture(t, "two_slides.pptx")
+			os.WriteFile(lockPath(env.dir, "two_slides.pptx"), []byte(",Jordan,workstation,15.10.2026 09:00,file:///home/jordan/.config/libreoffice/4;"), 0644)
+			env.uno.Respond("uno_edit_slide.py", `{"success": true}`)
+
+			result := env.app.aiAgent.executeTool(context.Background(), "toolu_1", "edit_slide_text",
+				[]byte(`{"slide_number": 1, "target_type": "shape_index", "target_value": "0", "new_text": "x"}`))
+
+			content := result.OfToolResult.Content[0].OfText.Text
+			if !result.OfToolResult.IsError.Value || !strings.Contains(content, string(ErrCodeFileLocked)) {
+				t.Fatalf("expected %s, got %s", ErrCodeFileLocked, content)
+			}
+			if !strings.Contains(content, `"retryable":true`) {
+				t.Errorf("expected a retry hint, got %s", content)
+			}
+			if len(env.uno.Calls("uno_edit_slide.py")) != 0 {
+				t.Error("the edit must not run against a locked file")
+			}
+			if app == "libreoffice" && !strings.Contains(content, "Jordan@workstation") {
+				t.Errorf("expected the lock owner in the error, got %s", content)
+			}
+		})
+	}
+}
+
+func TestInsertImagePlacesFileOnSlide(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_insert_image.py", `{"success": true, "message": "Inserted image on slide 2"}`)
+
+	imagePath := filepath.Join(t.TempDir(), "logo.png")
+	if err := os.WriteFile(imagePath, []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	input := fmt.Sprintf(`{"slide_number": 2, "image_path": %q, "x": 0.5, "width": 2}`, imagePath)
+	if _, err := InsertImage(context.Background(), env.app, json.RawMessage(input)); err != nil {
+		t.Fatalf("InsertImage failed: %v", err)
+	}
+
+	calls := env.uno.Calls("uno_insert_image.py")
+	expected := []string{path, "2", imagePath, "0.5", "", "2", ""}
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint(expected) {
+		t.Fatalf("expected one call with %v, got %+v", expected, calls)
+	}
+	env.app.exports.Flush(context.Background())
+	if len(env.converter.RangeCalls) != 1 || env.converter.RangeCalls[0] != [2]int{1, 1} {
+		t.Errorf("expected only slide 2 to be exported, got %v", env.converter.RangeCalls)
+	}
+
+	_, err := InsertImage(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "image_path": "missing.png"}`))
+	if code := toolErrorCode(err); code != ErrCodeFileNotFound {
+		t.Errorf("expected %s for a missing image, got %s (%v)", ErrCodeFileNotFound, code, err)
+	}
+	if len(env.uno.Calls("uno_insert_image.py")) != 1 {
+		t.Error("script should not run for a missing image")
+	}
+}
+
+func TestReplaceImageKeepsTheShape(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_replace_image.py", `{"success": true, "shape_index": 1, "warning": "stretched"}`)
+
+	imagePath := filepath.Join(t.TempDir(), "screenshot.png")
+	if err := os.WriteFile(imagePath, []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	input := fmt.Sprintf(`{"slide_number": 1, "shape_id": "Content Placeholder 2", "image_path": %q}`, imagePath)
+	result, err := ReplaceImage(context.Background(), env.app, json.RawMessage(input))
+	if err != nil {
+		t.Fatalf("ReplaceImage failed: %v", err)
+	}
+	if !strings.Contains(result, "stretched") {
+		t.Errorf("expected the script's warning in the result, got %s", result)
+	}
+	input = fmt.Sprintf(`{"slide_number": 1, "shape_index": 1, "image_path": %q, "fit": "fill", "alt_text": "Dashboard"}`, imagePath)
+	if _, err := ReplaceImage(context.Background(), env.app, json.RawMessage(input)); err != nil {
+		t.Fatalf("ReplaceImage failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_replace_image.py")
+	if len(calls) != 2 ||
+		fmt.Sprint(calls[0].Args) != fmt.Sprint([]string{path, "1", "1", imagePath, "stretch"}) ||
+		fmt.Sprint(calls[1].Args) != fmt.Sprint([]string{path, "1", "1", imagePath, "fill", "Dashboard"}) {
+		t.Fatalf("unexpected script calls: %+v", calls)
+	}
+
+	for _, bad := range []string{
+		fmt.Sprintf(`{"slide_number": 1, "shape_index": 1, "image_path": %q, "fit": "tile"}`, imagePath),
+		`{"slide_number": 1, "shape_index": 1}`,
+	} {
+		if _, err := ReplaceImage(context.Background(), env.app, json.RawMessage(bad)); toolErrorCode(err) != ErrCodeInvalidInput {
+			t.Errorf("expected %s for %s, got %v", ErrCodeInvalidInput, bad, err)
+		}
+	}
+
+	env.uno.Handle("uno_replace_image.py", func(args []string) ([]byte, error) {
+		return []byte(`{"success": false, "error": "Error replacing image: Shape 0 is not a picture"}`), errors.New("exit status 1")
+	})
+	input = fmt.Sprintf(`{"slide_number": 1, "shape_index": 0, "image_path": %q}`, imagePath)
+	if _, err := ReplaceImage(context.Background(), env.app, json.RawMessage(input)); toolErrorCode(err) != ErrCodeNotEditable {
+		t.Errorf("expected %s for a shape that isn't a picture, got %v", ErrCodeNotEditable, err)
+	}
+}
+
+func TestInsertTablePassesDimensionsAndData(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_insert_table.py", `{"success": true, "shape_index": 2}`)
+
+	input := `{"slide_number": 1, "rows": 2, "columns": 2, "data": [["Q", "Revenue"], ["Q1", "10"]], "width": 6}`
+	if _, err := InsertTable(context.Background(), env.app, json.RawMessage(input)); err != nil {
+		t.Fatalf("InsertTable failed: %v", err)
+	}
+
+	calls := env.uno.Calls("uno_insert_table.py")
+	expected := []string{path, "1", "2", "2", "", "", "6", "", `[["Q","Revenue"],["Q1","10"]]`}
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint(expected) {
+		t.Fatalf("expected one call with %v, got %+v", expected, calls)
+	}
+
+	for _, input := range []string{
+		`{"slide_number": 1, "rows": 0, "columns": 2}`,
+		`{"slide_number": 1, "rows": 1, "columns": 2, "data": [["a"], ["b"]]}`,
+		`{"slide_number": 1, "rows": 1, "columns": 1, "data": [["a", "b"]]}`,
+	} {
+		_, err := InsertTable(context.Background(), env.app, json.RawMessage(input))
+		if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+			t.Errorf("%s: expected %s, got %s (%v)", input, ErrCodeInvalidInput, code, err)
+		}
+	}
+}
+
+func TestEditTableCellClassifiesScriptErrors(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	env.uno.Handle("uno_edit_table_cell.py", func(args []string) ([]byte, error) {
+		if args[2] == "0" {
+			return []byte(`{"success": false, "error": "Error editing table cell: Shape 0 is not a table"}`), fmt.Errorf("exit status 1")
+		}
+		return []byte(`{"success": false, "error": "Error editing table cell: Cell position (5, 0) out of range: table has 2 rows and 2 columns"}`), fmt.Errorf("exit status 1")
+	})
+
+	_, err := EditTableCell(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "shape_index": 0, "row": 0, "column": 0, "new_text": "x"}`))
+	if code := toolErrorCode(err); code != ErrCodeNotEditable {
+		t.Errorf("expected %s, got %s (%v)", ErrCodeNotEditable, code, err)
+	}
+	_, err = EditTableCell(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "shape_index": 1, "row": 5, "column": 0, "new_text": "x"}`))
+	if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+		t.Errorf("expected %s, got %s (%v)", ErrCodeInvalidInput, code, err)
+	}
+}
+
+func TestAddShapeValidatesAndPassesOptions(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_add_shape.py", `{"success": true, "shape_index": 3}`)
+
+	input := `{"slide_number": 1, "shape_type": "rectangle", "x": 1, "y": 2, "text": "Note", "fill_color": "#1f4e79", "line_width": 1.5}`
+	if _, err := AddShape(context.Background(), env.app, json.RawMessage(input)); err != nil {
+		t.Fatalf("AddShape failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_add_shape.py")
+	expected := []string{path, "1", "rectangle", "1", "2", "", "", "Note", "1f4e79", "", "1.5"}
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint(expected) {
+		t.Fatalf("expected one call with %v, got %+v", expected, calls)
+	}
+
+	// Lines may run up or left, boxes may not have negative sizes
+	if _, err := AddShape(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "shape_type": "arrow", "width": -2}`)); err != nil {
+		t.Errorf("expected a negative arrow offset to be accepted, got %v", err)
+	}
+	for _, input := range []string{
+		`{"slide_number": 1, "shape_type": "star"}`,
+		`{"slide_number": 1, "shape_type": "ellipse", "height": -1}`,
+		`{"slide_number": 1, "shape_type": "text_box", "line_color": "blue"}`,
+	} {
+		_, err := AddShape(context.Background(), env.app, json.RawMessage(input))
+		if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+			t.Errorf("%s: expected %s, got %s (%v)", input, ErrCodeInvalidInput, code, err)
+		}
+	}
+	if calls := env.uno.Calls("uno_add_shape.py"); len(calls) != 2 {
+		t.Errorf("script should not run for invalid input, got %d calls", len(calls))
+	}
+}
+
+func TestDeleteShapeClassifiesMissingShape(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	env.uno.Handle("uno_delete_shape.py", func(args []string) ([]byte, error) {
+		return []byte(`{"success": false, "error": "Error deleting shape: Shape index 7 out of range (0-2)"}`), fmt.Errorf("exit status 1")
+	})
+
+	_, err := DeleteShape(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "shape_index": 7}`))
+	if code := toolErrorCode(err); code != ErrCodeShapeNotFound {
+		t.Fatalf("expected %s, got %s (%v)", ErrCodeShapeNotFound, code, err)
+	}
+}
+
+func TestDeleteShapeResolvesShapeID(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "mixed_shapes.pptx")
+	env.uno.Respond("uno_delete_shape.py", `{"success": true}`)
+
+	if _, err := DeleteShape(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "shape_id": "Picture 2 #2", "shape_index": 0}`)); err != nil {
+		t.Fatalf("DeleteShape failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_delete_shape.py")
+	if len(calls) != 1 || calls[0].Args[2] != "6" {
+		t.Fatalf("expected shape 6 to be deleted, got %+v", calls)
+	}
+
+	_, err := DeleteShape(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "shape_id": "Picture 9"}`))
+	if code := toolErrorCode(err); code != ErrCodeShapeNotFound {
+		t.Fatalf("expected %s, got %s (%v)", ErrCodeShapeNotFound, code, err)
+	}
+	if calls := env.uno.Calls("uno_delete_shape.py"); len(calls) != 1 {
+		t.Errorf("script should not run for an unknown shape_id, got %d calls", len(calls))
+	}
+}
+
+func TestFindReplaceAllExportsChangedSlides(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_find_replace.py", `{"success": true, "total_replacements": 3, "slides": [{"slide_number": 2, "replacements": 3}]}`)
+
+	if _, err := FindReplaceAll(context.Background(), env.app, json.RawMessage(`{"find": "Acme", "replace": "Globex", "ignore_case": true}`)); err != nil {
+		t.Fatalf("FindReplaceAll failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_find_replace.py")
+	expected := []string{path, "Acme", "Globex", "false", "false", "false", "false"}
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint(expected) {
+		t.Fatalf("expected one call with %v, got %+v", expected, calls)
+	}
+	env.app.exports.Flush(context.Background())
+	if len(env.converter.RangeCalls) != 1 || env.converter.RangeCalls[0] != [2]int{1, 1} {
+		t.Errorf("expected only slide 2 to be exported, got %v", env.converter.RangeCalls)
+	}
+
+	// A dry run reports matches without touching the previews
+	if _, err := FindReplaceAll(context.Background(), env.app, json.RawMessage(`{"find": "Acme", "replace": "Globex", "dry_run": true}`)); err != nil {
+		t.Fatalf("FindReplaceAll dry run failed: %v", err)
+	}
+	env.app.exports.Flush(context.Background())
+	if len(env.converter.RangeCalls) != 1 {
+		t.Errorf("dry run should not export, got %v", env.converter.RangeCalls)
+	}
+
+	_, err := FindReplaceAll(context.Background(), env.app, json.RawMessage(`{"replace": "x"}`))
+	if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+		t.Errorf("expected %s for empty find, got %s (%v)", ErrCodeInvalidInput, code, err)
+	}
+}
+
+func TestSavePresentationAsWritesCopyAndOpensIt(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Handle("uno_save_as.py", func(args []string) ([]byte, error) {
+		if err := os.WriteFile(args[1], []byte("copy"), 0644); err != nil {
+			return nil, err
+		}
+		return []byte(fmt.Sprintf(`{"success": true, "output_path": %q, "format": "pptx", "slide_count": 2}`, args[1])), nil
+	})
+
+	output, err := SavePresentationAs(context.Background(), env.app, json.RawMessage(`{"output_path": "copies/deck copy.pptx", "open_copy": true}`))
+	if err != nil {
+		t.Fatalf("SavePresentationAs failed: %v", err)
+	}
+
+	copyPath := filepath.Join(filepath.Dir(path), "copies", "deck copy.pptx")
+	calls := env.uno.Calls("uno_save_as.py")
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint([]string{path, copyPath}) {
+		t.Fatalf("expected the copy to be written next to the presentation, got %+v", calls)
+	}
+	if env.app.presentationPath() != copyPath || !strings.Contains(output, `"opened_copy":true`) {
+		t.Errorf("expected to continue on the copy, current is %s, result %s", env.app.presentationPath(), output)
+	}
+
+	// The copy now exists, and the current presentation is the copy itself
+	for input, want := range map[string]ToolErrorCode{
+		fmt.Sprintf(`{"presentation_path": %q, "output_path": %q}`, path, copyPath): ErrCodeInvalidInput,
+		fmt.Sprintf(`{"output_path": %q, "overwrite": true}`, copyPath):             ErrCodeInvalidInput,
+		`{"output_path": "deck.key"}`:                                               ErrCodeInvalidInput,
+		`{"output_path": "deck.pdf", "open_copy": true}`:                            ErrCodeInvalidInput,
+	} {
+		_, err := SavePresentationAs(context.Background(), env.app, json.RawMessage(input))
+		if code := toolErrorCode(err); code != want {
+			t.Errorf("%s: expected %s, got %s (%v)", input, want, code, err)
+		}
+	}
+	if len(env.uno.Calls("uno_save_as.py")) != 1 {
+		t.Error("expected rejected saves not to run the script")
+	}
+
+	// Other formats are exports and don't switch presentations
+	if _, err := SavePresentationAs(context.Background(), env.app, json.RawMessage(`{"output_path": "deck.pdf"}`)); err != nil {
+		t.Fatalf("saving as PDF failed: %v", err)
+	}
+	if env.app.presentationPath() != copyPath {
+		t.Errorf("expected a PDF export to keep the current presentation, got %s", env.app.presentationPath())
+	}
+}
+
+func TestExportPDFLayouts(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Handle("uno_export_pdf.py", func(args []string) ([]byte, error) {
+		if err := os.WriteFile(args[1], []byte("%PDF"), 0644); err != nil {
+			return nil, err
+		}
+		return []byte(`{"success": true, "slide_count": 2}`), nil
+	})
+
+	// Notes pages go through LibreOffice's PDF filter, next to the presentation by default
+	if _, err := ExportPDF(context.Background(), env.app, json.RawMessage(`{"layout": "notes", "last_slide": 1}`)); err != nil {
+		t.Fatalf("ExportPDF failed: %v", err)
+	}
+	notesPath := filepath.Join(filepath.Dir(path), "two_slides notes.pdf")
+	calls := env.uno.Calls("uno_export_pdf.py")
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint([]string{path, notesPath, `{"notes":true,"page_range":"1-1"}`}) {
+		t.Fatalf("unexpected script calls: %+v", calls)
+	}
+
+	// Handouts are laid out from rendered slide images
+	output, err := ExportPDF(context.Background(), env.app, json.RawMessage(`{"layout": "handout", "slides_per_page": 4, "output_path": "out/handout.pdf"}`))
+	if err != nil {
+		t.Fatalf("handout export failed: %v", err)
+	}
+	if !strings.Contains(output, `"pages":1`) || len(env.converter.Exports) != 1 {
+		t.Errorf("expected a one page handout rendered from slide images, got %s", output)
+	}
+	if data, err := os.ReadFile(filepath.Join(filepath.Dir(path), "out", "handout.pdf")); err != nil || !bytes.HasPrefix(data, []byte("%PDF")) {
+		t.Errorf("expected the handout PDF to be written: %v", err)
+	}
+
+	for input, want := range map[string]ToolErrorCode{
+		`{"layout": "notes", "last_slide": 1}`:            ErrCodeInvalidInput, // Exists already
+		`{"first_slide": 2, "last_slide": 3}`:             ErrCodeSlideOutOfRange,
+		`{"layout": "outline"}`:                           ErrCodeInvalidInput,
+		`{"layout": "handout", "slides_per_page": 5}`:     ErrCodeInvalidInput,
+		`{"slides_per_page": 2}`:                          ErrCodeInvalidInput,
+		`{"output_path": "deck.pptx", "overwrite": true}`: ErrCodeInvalidInput,
+	} {
+		_, err := ExportPDF(context.Background(), env.app, json.RawMessage(input))
+		if code := toolErrorCode(err); code != want {
+			t.Errorf("%s: expected %s, got %s (%v)", input, want, code, err)
+		}
+	}
+}
+
+func TestSetPresentationInfoChangesOnlyGivenProperties(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_presentation_info.py", `{"success": true, "title": "Q3 Review", "company": "", "updated": ["company", "title"]}`)
+
+	if _, err := SetPresentationInfo(context.Background(), env.app, json.RawMessage(`{"title": "Q3 Review", "company": ""}`)); err != nil {
+		t.Fatalf("SetPresentationInfo failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_presentation_info.py")
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint([]string{path, "set", `{"company":"","title":"Q3 Review"}`}) {
+		t.Fatalf("unexpected script calls: %+v", calls)
+	}
+
+	_, err := SetPresentationInfo(context.Background(), env.app, json.RawMessage(`{}`))
+	if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+		t.Errorf("expected %s without properties, got %s (%v)", ErrCodeInvalidInput, code, err)
+	}
+
+	// Reading goes through the native reader, not LibreOffice
+	output, err := GetPresentationInfo(context.Background(), env.app, json.RawMessage(`{}`))
+	if err != nil || !strings.Contains(output, `"aspect_ratio":"16:9"`) || len(env.uno.Calls("uno_presentation_info.py")) != 1 {
+		t.Errorf("expected native presentation info, got %s (%v)", output, err)
+	}
+}
+
+func TestSetSlideLayoutResolvesTemplateLayout(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_set_slide_layout.py", `{"success": true, "slide_number": 2}`)
+
+	if _, err := SetSlideLayout(context.Background(), env.app, json.RawMessage(`{"slide_number": 2, "layout": "title and content"}`)); err != nil {
+		t.Fatalf("SetSlideLayout failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_set_slide_layout.py")
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint([]string{path, "2", `{"name":"Title and Content","type":"obj"}`}) {
+		t.Fatalf("unexpected script calls: %+v", calls)
+	}
+
+	_, err := SetSlideLayout(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "layout": "Two Content"}`))
+	if code := toolErrorCode(err); code != ErrCodeInvalidInput || !strings.Contains(err.Error(), "Title and Content") {
+		t.Errorf("expected %s listing the available layouts, got %s (%v)", ErrCodeInvalidInput, code, err)
+	}
+	if len(env.uno.Calls("uno_set_slide_layout.py")) != 1 {
+		t.Error("an unknown layout should not reach LibreOffice")
+	}
+}
+
+func TestApplyTemplateRefreshesAllPreviews(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+
+	_, err := ApplyTemplate(context.Background(), env.app, json.RawMessage(`{"template_path": "notes.txt"}`))
+	if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+		t.Errorf("expected %s for a non-template file, got %s (%v)", ErrCodeInvalidInput, code, err)
+	}
+
+	input, _ := json.Marshal(map[string]string{"template_path": filepath.Join(fixtureDir, "template.potx")})
+	output, err := ApplyTemplate(context.Background(), env.app, input)
+	if err != nil {
+		t.Fatalf("ApplyTemplate failed: %v", err)
+	}
+	if !strings.Contains(output, `"template":"template.potx"`) || !strings.Contains(output, `"to":"Title and Content"`) {
+		t.Errorf("unexpected result: %s", output)
+	}
+	env.app.exports.Flush(context.Background())
+	if len(env.converter.Calls) != 1 {
+		t.Errorf("expected a full preview export, got %d", len(env.converter.Calls))
+	}
+}
+
+func TestFormatListResolvesItemDefaults(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_format_list.py", `{"success": true, "paragraphs": 3}`)
+
+	input := `{"slide_number": 1, "shape_index": 1, "style": "number", "number_format": "a)", "start_at": 3, "items": [
+		{"text": "Revenue"},
+		{"text": "Up 12%", "level": 1, "style": "bullet", "bullet": "–"},
+		{"text": "Churn", "number_format": "(i)"}]}`
+	if _, err := FormatList(context.Background(), env.app, json.RawMessage(input)); err != nil {
+		t.Fatalf("FormatList failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_format_list.py")
+	if len(calls) != 1 || calls[0].Args[0] != path || calls[0].Args[4] != "3" {
+		t.Fatalf("unexpected script calls: %+v", calls)
+	}
+	var items []ListItem
+	if err := json.Unmarshal([]byte(calls[0].Args[3]), &items); err != nil {
+		t.Fatal(err)
+	}
+	want := []ListItem{
+		{Text: "Revenue", Style: "number", Bullet: "•", NumberFormat: "a)"},
+		{Text: "Up 12%", Level: 1, Style: "bullet", Bullet: "–", NumberFormat: "a)"},
+		{Text: "Churn", Style: "number", Bullet: "•", NumberFormat: "(i)"},
+	}
+	if fmt.Sprint(items) != fmt.Sprint(want) {
+		t.Errorf("expected items %+v, got %+v", want, items)
+	}
+
+	for _, bad := range []string{
+		`{"slide_number": 1, "shape_index": 1, "items": []}`,
+		`{"slide_number": 1, "shape_index": 1, "items": [{"text": "x", "level": 9}]}`,
+		`{"slide_number": 1, "shape_index": 1, "items": [{"text": "x", "bullet": "->"}]}`,
+		`{"slide_number": 1, "shape_index": 1, "items": [{"text": "x", "style": "number", "number_format": "1-"}]}`,
+		`{"slide_number": 1, "shape_index": 1, "items": [{"text": "one\ntwo"}]}`,
+	} {
+		_, err := FormatList(context.Background(), env.app, json.RawMessage(bad))
+		if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+			t.Errorf("expected %s for %s, got %s (%v)", ErrCodeInvalidInput, bad, code, err)
+		}
+	}
+	if len(env.uno.Calls("uno_format_list.py")) != 1 {
+		t.Error("invalid lists should not reach LibreOffice")
+	}
+}
+
+func TestSetRichTextPassesRuns(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_set_rich_text.py", `{"success": true, "runs": 2}`)
+
+	input := `{"slide_number": 2, "shape_index": 1, "runs": [
+		{"text": "Note: ", "bold": true, "color": "#C00000"},
+		{"text": "see the appendix", "bold": false, "hyperlink": "https://example.com/appendix"}]}`
+	if _, err := SetRichText(context.Background(), env.app, json.RawMessage(input)); err != nil {
+		t.Fatalf("SetRichText failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_set_rich_text.py")
+	if len(calls) != 1 {
+		t.Fatalf("expected one script call, got %+v", calls)
+	}
+	want := `[{"text":"Note: ","bold":true,"color":"C00000"},{"text":"see the appendix","bold":false,"hyperlink":"https://example.com/appendix"}]`
+	if calls[0].Args[3] != want {
+		t.Errorf("expected runs %s, got %s", want, calls[0].Args[3])
+	}
+
+	for _, bad := range []string{
+		`{"slide_number": 2, "shape_index": 1, "runs": []}`,
+		`{"slide_number": 2, "shape_index": 1, "runs": [{"text": ""}]}`,
+		`{"slide_number": 2, "shape_index": 1, "runs": [{"text": "x", "color": "red"}]}`,
+		`{"slide_number": 2, "shape_index": 1, "runs": [{"text": "x", "size": 500}]}`,
+		`{"slide_number": 2, "shape_index": 1, "runs": [{"text": "x", "hyperlink": "javascript:alert(1)"}]}`,
+	} {
+		_, err := SetRichText(context.Background(), env.app, json.RawMessage(bad))
+		if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+			t.Errorf("expected %s for %s, got %s (%v)", ErrCodeInvalidInput, bad, code, err)
+		}
+	}
+}
+
+func TestAddHyperlinkValidatesTarget(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_add_hyperlink.py", `{"success": true}`)
+
+	if _, err := AddHyperlink(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "shape_index": 1, "text": "Churn", "url": "https://example.com/churn"}`)); err != nil {
+		t.Fatalf("AddHyperlink failed: %v", err)
+	}
+	if _, err := AddHyperlink(context.Background(), env.app, json.RawMessage(`{"slide_number": 2, "shape_index": 0, "target_slide": 1}`)); err != nil {
+		t.Fatalf("AddHyperlink failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_add_hyperlink.py")
+	if len(calls) != 2 ||
+		fmt.Sprint(calls[0].Args) != fmt.Sprint([]string{path, "1", "1", "Churn", "1", "https://example.com/churn", "0"}) ||
+		fmt.Sprint(calls[1].Args) != fmt.Sprint([]string{path, "2", "0", "", "1", "", "1"}) {
+		t.Fatalf("unexpected script calls: %+v", calls)
+	}
+
+	for input, want := range map[string]ToolErrorCode{
+		`{"slide_number": 1, "shape_index": 1}`:                                                  ErrCodeInvalidInput,
+		`{"slide_number": 1, "shape_index": 1, "url": "https://example.com", "target_slide": 2}`: ErrCodeInvalidInput,
+		`{"slide_number": 1, "shape_index": 1, "url": "file:///etc/passwd"}`:                     ErrCodeInvalidInput,
+		`{"slide_number": 1, "shape_index": 1, "url": "https://example.com", "occurrence": 2}`:   ErrCodeInvalidInput,
+		`{"slide_number": 1, "shape_index": 1, "target_slide": 3}`:                               ErrCodeSlideOutOfRange,
+	} {
+		_, err := AddHyperlink(context.Background(), env.app, json.RawMessage(input))
+		if code := toolErrorCode(err); code != want {
+			t.Errorf("expected %s for %s, got %s (%v)", want, input, code, err)
+		}
+	}
+}
+
+func TestSetTransitionPassesSettings(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_set_transition.py", `{"success": true, "slides": [1, 2]}`)
+
+	if _, err := SetTransition(context.Background(), env.app, json.RawMessage(`{"transition": "fade", "duration": 0.5}`)); err != nil {
+		t.Fatalf("SetTransition failed: %v", err)
+	}
+	if _, err := SetTransition(context.Background(), env.app, json.RawMessage(`{"slides": [2], "advance_after": 0}`)); err != nil {
+		t.Fatalf("SetTransition failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_set_transition.py")
+	if len(calls) != 2 ||
+		fmt.Sprint(calls[0].Args) != fmt.Sprint([]string{path, "[]", `{"advance_after":null,"direction":"","duration":0.5,"transition":"fade"}`}) ||
+		fmt.Sprint(calls[1].Args) != fmt.Sprint([]string{path, "[2]", `{"advance_after":0,"direction":"","duration":0,"transition":""}`}) {
+		t.Fatalf("unexpected script calls: %+v", calls)
+	}
+
+	for _, bad := range []string{
+		`{}`,
+		`{"transition": "spin"}`,
+		`{"transition": "fade", "direction": "from_left"}`,
+		`{"transition": "push", "direction": "sideways"}`,
+		`{"advance_after": -1}`,
+	} {
+		_, err := SetTransition(context.Background(), env.app, json.RawMessage(bad))
+		if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+			t.Errorf("expected %s for %s, got %s (%v)", ErrCodeInvalidInput, bad, code, err)
+		}
+	}
+}
+
+func TestAddAnimationPassesSettings(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_add_animation.py", `{"success": true, "step": 1}`)
+
+	input := `{"slide_number": 2, "shape_index": 1, "effect_type": "entrance", "effect": "fly_in", "direction": "from_bottom", "trigger": "after_previous", "delay": 0.25}`
+	if _, err := AddAnimation(context.Background(), env.app, json.RawMessage(input)); err != nil {
+		t.Fatalf("AddAnimation failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_add_animation.py")
+	want := []string{path, "2", "1", `{"delay":0.25,"direction":"from_bottom","duration":0,"effect":"fly_in","effect_type":"entrance","order":0,"replace_existing":false,"trigger":"after_previous"}`}
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint(want) {
+		t.Fatalf("unexpected script calls: %+v", calls)
+	}
+
+	for _, bad := range []string{
+		`{"slide_number": 1, "shape_index": 0, "effect_type": "entry", "effect": "fade"}`,
+		`{"slide_number": 1, "shape_index": 0, "effect_type": "exit", "effect": "fly_in"}`,
+		`{"slide_number": 1, "shape_index": 0, "effect_type": "entrance", "effect": "fade", "direction": "from_left"}`,
+		`{"slide_number": 1, "shape_index": 0, "effect_type": "entrance", "effect": "appear", "trigger": "on_hover"}`,
+		`{"slide_number": 1, "shape_index": 0, "effect_type": "emphasis", "effect": "spin", "delay": -1}`,
+	} {
+		_, err := AddAnimation(context.Background(), env.app, json.RawMessage(bad))
+		if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+			t.Errorf("expected %s for %s, got %s (%v)", ErrCodeInvalidInput, bad, code, err)
+		}
+	}
+}
+
+func TestInsertChartPassesData(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_insert_chart.py", `{"success": true, "slide_number": 2, "shape_index": 2}`)
+
+	input := `{"slide_number": 2, "chart_type": "column", "categories": ["Q1", "Q2"], "series": [{"name": "Revenue", "values": [1.5, 2]}], "title": "Sales", "width": 6}`
+	if _, err := InsertChart(context.Background(), env.app, json.RawMessage(input)); err != nil {
+		t.Fatalf("InsertChart failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_insert_chart.py")
+	want := []string{path, "2", "column", `{"categories":["Q1","Q2"],"legend":true,"series":[{"name":"Revenue","values":[1.5,2]}],"title":"Sales"}`, "", "", "6", ""}
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint(want) {
+		t.Fatalf("unexpected script calls: %+v", calls)
+	}
+
+	for _, bad := range []string{
+		`{"slide_number": 1, "chart_type": "radar", "categories": ["A"], "series": [{"name": "S", "values": [1]}]}`,
+		`{"slide_number": 1, "chart_type": "line", "categories": [], "series": [{"name": "S", "values": []}]}`,
+		`{"slide_number": 1, "chart_type": "line", "categories": ["A", "B"], "series": [{"name": "S", "values": [1]}]}`,
+		`{"slide_number": 1, "chart_type": "pie", "categories": ["A"], "series": [{"name": "S", "values": [1]}, {"name": "T", "values": [2]}]}`,
+	} {
+		_, err := InsertChart(context.Background(), env.app, json.RawMessage(bad))
+		if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+			t.Errorf("expected %s for %s, got %s (%v)", ErrCodeInvalidInput, bad, code, err)
+		}
+	}
+}
+
+func TestEditChartDataPassesOnlyChanges(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_edit_chart_data.py", `{"success": true, "changed": false}`)
+
+	if _, err := EditChartData(context.Background(), env.app, json.RawMessage(`{"slide_number": 2, "shape_index": 3}`)); err != nil {
+		t.Fatalf("EditChartData failed: %v", err)
+	}
+	input := `{"slide_number": 2, "shape_index": 3, "title": "", "series": [{"name": "2025", "values": [4, 5]}]}`
+	if _, err := EditChartData(context.Background(), env.app, json.RawMessage(input)); err != nil {
+		t.Fatalf("EditChartData failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_edit_chart_data.py")
+	if len(calls) != 2 ||
+		fmt.Sprint(calls[0].Args) != fmt.Sprint([]string{path, "2", "3", `{}`}) ||
+		fmt.Sprint(calls[1].Args) != fmt.Sprint([]string{path, "2", "3", `{"series":[{"name":"2025","values":[4,5]}],"title":""}`}) {
+		t.Fatalf("unexpected script calls: %+v", calls)
+	}
+
+	for _, bad := range []string{
+		`{"slide_number": 1, "shape_index": 0, "series": [{"name": "A", "values": [1, 2]}, {"name": "B", "values": [1]}]}`,
+		`{"slide_number": 1, "shape_index": 0, "categories": ["Q1"], "series": [{"name": "A", "values": [1, 2]}]}`,
+		`{"slide_number": 1, "shape_index": 0, "series": [{"name": "A", "values": [1]}], "series_names": ["X", "Y"]}`,
+	} {
+		_, err := EditChartData(context.Background(), env.app, json.RawMessage(bad))
+		if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+			t.Errorf("expected %s for %s, got %s (%v)", ErrCodeInvalidInput, bad, code, err)
+		}
+	}
+}
+
+func TestApplyEditsRunsBatchInOneCall(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_apply_edits.py", `{"success": true, "applied": 3, "slides": [1, 2]}`)
+
+	input := `{"edits": [
+		{"op": "set_text", "slide_number": 1, "shape_id": "Title 1", "text": "Q3 Review"},
+		{"op": "format_text", "slide_number": 1, "shape_index": 1, "bold": true, "color": "#1F4E79"},
+		{"op": "move_shape", "slide_number": 2, "shape_index": 0, "x": 1.5}
+	]}`
+	if _, err := ApplyEdits(context.Background(), env.app, json.RawMessage(input)); err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_apply_edits.py")
+	if len(calls) != 1 || calls[0].Args[0] != path {
+		t.Fatalf("expected one call for the whole batch, got %+v", calls)
+	}
+	var edits []map[string]interface{}
+	if err := json.Unmarshal([]byte(calls[0].Args[1]), &edits); err != nil || len(edits) != 3 {
+		t.Fatalf("unexpected edits argument %q: %v", calls[0].Args[1], err)
+	}
+	if edits[1]["color"] != "1F4E79" || edits[1]["shape_index"] != float64(1) {
+		t.Errorf("unexpected format edit: %v", edits[1])
+	}
+	env.app.exports.Flush(context.Background())
+	if len(env.converter.RangeCalls) != 1 || env.converter.RangeCalls[0] != [2]int{0, 1} {
+		t.Errorf("expected one export of slides 1-2, got %v", env.converter.RangeCalls)
+	}
+
+	for _, bad := range []string{
+		`{"edits": []}`,
+		`{"edits": [{"op": "rotate", "slide_number": 1, "shape_index": 0}]}`,
+		`{"edits": [{"op": "set_text", "slide_number": 1, "text": "No target"}]}`,
+		`{"edits": [{"op": "format_text", "slide_number": 1, "shape_index": 0}]}`,
+		`{"edits": [{"op": "move_shape", "slide_number": 1, "shape_index": 0, "width": 0}]}`,
+	} {
+		_, err := ApplyEdits(context.Background(), env.app, json.RawMessage(bad))
+		if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+			t.Errorf("%s: expected %s, got %s (%v)", bad, ErrCodeInvalidInput, code, err)
+		}
+	}
+	if calls := env.uno.Calls("uno_apply_edits.py"); len(calls) != 1 {
+		t.Errorf("script should not run for invalid input, got %d calls", len(calls))
+	}
+}
+
+func TestApplyEditsFormatsParagraphs(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_apply_edits.py", `{"success": true, "applied": 1, "slides": [1]}`)
+
+	input := `{"edits": [{"op": "format_text", "slide_number": 1, "shape_index": 1, "alignment": "justify",
+		"line_spacing": 1.5, "space_before": 0, "space_after": 6, "paragraphs": [0, 2]}]}`
+	if _, err := ApplyEdits(context.Background(), env.app, json.RawMessage(input)); err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	var edits []map[string]interface{}
+	if err := json.Unmarshal([]byte(env.uno.Calls("uno_apply_edits.py")[0].Args[1]), &edits); err != nil {
+		t.Fatal(err)
+	}
+	edit := edits[0]
+	if edit["alignment"] != "justify" || edit["line_spacing"] != 1.5 || edit["space_before"] != float64(0) || edit["space_after"] != float64(6) {
+		t.Errorf("unexpected paragraph formatting: %v", edit)
+	}
+	if paragraphs, _ := edit["paragraphs"].([]interface{}); len(paragraphs) != 2 || paragraphs[1] != float64(2) {
+		t.Errorf("expected paragraphs 0 and 2, got %v", edit["paragraphs"])
+	}
+
+	for _, bad := range []string{
+		`{"edits": [{"op": "format_text", "slide_number": 1, "shape_index": 0, "alignment": "middle"}]}`,
+		`{"edits": [{"op": "format_text", "slide_number": 1, "shape_index": 0, "line_spacing": 0.2}]}`,
+		`{"edits": [{"op": "format_text", "slide_number": 1, "shape_index": 0, "space_after": -3}]}`,
+		`{"edits": [{"op": "format_text", "slide_number": 1, "shape_index": 0, "bold": true, "paragraphs": [-1]}]}`,
+	} {
+		_, err := ApplyEdits(context.Background(), env.app, json.RawMessage(bad))
+		if code := toolErrorCode(err); code != ErrCodeInvalidInput {
+			t.Errorf("%s: expected %s, got %s (%v)", bad, ErrCodeInvalidInput, code, err)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"regexp"
+	"time"
+)
+
+// languageTagPattern matches language tags the spell checker takes, e.g. en, en-US or de-CH
+var languageTagPattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+// SpellingIssue is a misspelled or doubled word found by spellcheck_presentation
+type SpellingIssue struct {
+	Type        string   `json:"type"`     // spelling or repeated_word
+	Location    string   `json:"location"` // shape, table_cell or notes
+	ShapeIndex  *int     `json:"shape_index,omitempty"`
+	ShapeID     string   `json:"shape_id,omitempty"`
+	Row         *int     `json:"row,omitempty"` // Table cell, 0-based
+	Column      *int     `json:"column,omitempty"`
+	Word        string   `json:"word"`
+	Suggestions []string `json:"suggestions"`
+	Language    string   `json:"language,omitempty"`
+	Context     string   `json:"context"`
+}
+
+// SpellcheckReport is the result of uno_spellcheck.py; slides without issues are left out
+type SpellcheckReport struct {
+	CheckedSlides int `json:"checked_slides"`
+	IssueCount    int `json:"issue_count"`
+	Slides        []struct {
+		SlideNumber int             `json:"slide_number"`
+		Issues      []SpellingIssue `json:"issues"`
+	} `json:"slides"`
+	UncheckedLanguages []string `json:"unchecked_languages,omitempty"`
+	Warning            string   `json:"warning,omitempty"`
+	Message            string   `json:"message"`
+}
+
+// SpellcheckPresentationDefinition defines the spellcheck_presentation tool
+var SpellcheckPresentationDefinition = ToolDefinition{
+	Name: "spellcheck_presentation",
+	Description: `Spell check the text of slides with LibreOffice's dictionaries and report each misspelled word once per shape, with up to five suggested corrections and the text around it. Words typed twice in a row ("the the") are reported as repeated_word.
+
+Each word is checked in the language its text is marked with; set language (e.g. 'en-US') to check everything in one language instead. Acronyms, words with inner capitals (iPhone), links and email addresses are skipped; add product names and jargon to ignore_words. Tables and grouped shapes are checked, and speaker notes with include_notes.
+
+The result is a list of suggestions, not a verdict: names and terms may be correct as written. Fix real typos with edit_slide_text, or find_replace_all for a word misspelled across slides. Run this after larger edits to catch typos before the deck goes out.`,
+	InputSchema: SpellcheckPresentationInputSchema,
+	Function:    SpellcheckPresentation,
+	ReadOnly:    true,
+	Timeout:     5 * time.Minute, // Looks up every word of large decks
+}
+
+type SpellcheckPresentationInput struct {
+	PresentationPath string   `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Slides           []int    `json:"slides,omitempty" jsonschema_description:"(Optional) Slide numbers to check (1-based); omit for the whole deck"`
+	IncludeNotes     bool     `json:"include_notes,omitempty" jsonschema_description:"(Optional) Also check speaker notes"`
+	IgnoreWords      []string `json:"ignore_words,omitempty" jsonschema_description:"(Optional) Words to accept as spelled, e.g. product names"`
+	Language         string   `json:"language,omitempty" jsonschema_description:"(Optional) Language tag to check all text in, e.g. 'en-US'; defaults to each text's own language"`
+}
+
+var SpellcheckPresentationInputSchema = GenerateSchema[SpellcheckPresentationInput]()
+
+func SpellcheckPresentation(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	spellInput := SpellcheckPresentationInput{}
+	if err := json.Unmarshal(input, &spellInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if spellInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			spellInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+	if _, err := os.Stat(spellInput.PresentationPath); os.IsNotExist(err) {
+		return "", NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", spellInput.PresentationPath)
+	}
+
+	if spellInput.Language != "" && !languageTagPattern.MatchString(spellInput.Language) {
+		return "", NewToolError(ErrCodeInvalidInput, "language must be a tag such as 'en' or 'en-US', got %q", spellInput.Language)
+	}
+	for _, slideNumber := range spellInput.Slides {
+		if slideNumber < 1 {
+			return "", NewToolError(ErrCodeSlideOutOfRange, "slide numbers must be 1 or greater")
+		}
+	}
+	if spellInput.Slides == nil {
+		spellInput.Slides = []int{}
+	}
+	if spellInput.IgnoreWords == nil {
+		spellInput.IgnoreWords = []string{}
+	}
+	slides, err := json.Marshal(spellInput.Slides)
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to encode slides: %v", err)
+	}
+	options, err := json.Marshal(map[string]interface{}{
+		"include_notes": spellInput.IncludeNotes,
+		"ignore_words":  spellInput.IgnoreWords,
+		"language":      spellInput.Language,
+	})
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to encode options: %v", err)
+	}
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_spellcheck.py", spellInput.PresentationPath, string(slides), string(options))
+	if err != nil {
+		return "", scriptError("failed to check spelling", err, output)
+	}
+
+	result, err := decodeScriptResult[SpellcheckReport](output)
+	if err != nil {
+		return "", err
+	}
+	return marshalResult(result)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestSpellcheckPassesOptions(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_spellcheck.py", `{"success": true, "checked_slides": 2, "issue_count": 1,
+		"slides": [{"slide_number": 1, "issues": [{"type": "spelling", "location": "shape", "shape_index": 0, "shape_id": "Title 1",
+			"word": "Quartrely", "suggestions": ["Quarterly"], "language": "en-US", "context": "Quartrely results"}]}]}`)
+
+	output, err := SpellcheckPresentation(context.Background(), env.app, json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("SpellcheckPresentation failed: %v", err)
+	}
+	input := `{"slides": [2], "include_notes": true, "ignore_words": ["SlidePilot"], "language": "en-GB"}`
+	if _, err := SpellcheckPresentation(context.Background(), env.app, json.RawMessage(input)); err != nil {
+		t.Fatalf("SpellcheckPresentation failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_spellcheck.py")
+	if len(calls) != 2 ||
+		fmt.Sprint(calls[0].Args) != fmt.Sprint([]string{path, "[]", `{"ignore_words":[],"include_notes":false,"language":""}`}) ||
+		fmt.Sprint(calls[1].Args) != fmt.Sprint([]string{path, "[2]", `{"ignore_words":["SlidePilot"],"include_notes":true,"language":"en-GB"}`}) {
+		t.Fatalf("unexpected script calls: %+v", calls)
+	}
+
+	var report SpellcheckReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Slides) != 1 || report.Slides[0].Issues[0].Suggestions[0] != "Quarterly" {
+		t.Errorf("unexpected report: %s", output)
+	}
+
+	if _, err := SpellcheckPresentation(context.Background(), env.app, json.RawMessage(`{"language": "English"}`)); toolErrorCode(err) != ErrCodeInvalidInput {
+		t.Errorf("expected an unknown language tag to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,506 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stock photos come from Unsplash or Pexels. Both license their photos for free use but ask
+// that the photographer is credited, so every candidate carries its attribution. Unsplash
+// also requires its download endpoint to be called when a photo is used, which Download does.
+
+// stockPhotoEndpoints are the API base URLs of the stock photo providers; tests point them
+// at a local server
+var stockPhotoEndpoints = map[string]string{
+	"unsplash": "https://api.unsplash.com",
+	"pexels":   "https://api.pexels.com",
+}
+
+// stockPhotoMaxBytes bounds a downloaded photo
+const stockPhotoMaxBytes = 50 << 20
+
+// StockPhoto is a search result of a stock photo provider
+type StockPhoto struct {
+	ID              string `json:"id"` // <provider>:<photo id>, as insert_stock_image takes it
+	Description     string `json:"description,omitempty"`
+	Width           int    `json:"width"`
+	Height          int    `json:"height"`
+	Photographer    string `json:"photographer"`
+	PhotographerURL string `json:"photographer_url,omitempty"`
+	PageURL         string `json:"page_url"`
+	PreviewURL      string `json:"preview_url"`
+	Attribution     string `json:"attribution"` // Credit line such as "Photo by Jane Doe on Unsplash"
+
+	downloadURL string // Image file at slide resolution
+	trackURL    string // Unsplash download endpoint to call when the photo is used
+}
+
+// StockPhotoProvider searches a stock photo library
+type StockPhotoProvider interface {
+	Name() string
+	// Search returns up to count photos matching query; orientation is "", landscape,
+	// portrait or square
+	Search(ctx context.Context, query, orientation string, count int) ([]StockPhoto, error)
+	// Photo looks up a photo by the provider's own id
+	Photo(ctx context.Context, id string) (StockPhoto, error)
+	// Download returns the image data of a photo found by Search or Photo
+	Download(ctx context.Context, photo StockPhoto) ([]byte, error)
+}
+
+// stockPhotoProvider returns the named provider ("" for the first one with a key) using the
+// API keys from the settings, falling back to UNSPLASH_ACCESS_KEY and PEXELS_API_KEY
+func stockPhotoProvider(app *App, name string) (StockPhotoProvider, error) {
+	settings := app.aiAgent.Settings()
+	unsplashKey := settings.UnsplashAccessKey
+	if unsplashKey == "" {
+		unsplashKey = os.Getenv("UNSPLASH_ACCESS_KEY")
+	}
+	pexelsKey := settings.PexelsAPIKey
+	if pexelsKey == "" {
+		pexelsKey = os.Getenv("PEXELS_API_KEY")
+	}
+	client := &http.Client{Timeout: time.Minute}
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch {
+	case name == "unsplash" || name == "" && unsplashKey != "":
+		if unsplashKey == "" {
+			return nil, NewToolError(ErrCodeProviderError, "Unsplash needs an access key: add one under Stock photos in the settings or set UNSPLASH_ACCESS_KEY")
+		}
+		return &UnsplashProvider{endpoint: stockPhotoEndpoints["unsplash"], accessKey: unsplashKey, client: client}, nil
+	case name == "pexels" || name == "" && pexelsKey != "":
+		if pexelsKey == "" {
+			return nil, NewToolError(ErrCodeProviderError, "Pexels needs an API key: add one under Stock photos in the settings or set PEXELS_API_KEY")
+		}
+		return &PexelsProvider{endpoint: stockPhotoEndpoints["pexels"], apiKey: pexelsKey, client: client}, nil
+	case name == "":
+		return nil, NewToolError(ErrCodeProviderError, "stock photo search needs an Unsplash access key or a Pexels API key: add one under Stock photos in the settings")
+	}
+	return nil, NewToolError(ErrCodeInvalidInput, "unknown stock photo provider %q: use unsplash or pexels", name)
+}
+
+// getStockJSON sends an authorized GET request and decodes the JSON response into result
+func getStockJSON(ctx context.Context, client *http.Client, requestURL, authorization string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("invalid API response: %v", err)
+	}
+	return nil
+}
+
+// downloadStockImage fetches a photo's image file
+func downloadStockImage(ctx context.Context, client *http.Client, imageURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", imageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download photo: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("photo download returned %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, stockPhotoMaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download photo: %v", err)
+	}
+	if len(data) > stockPhotoMaxBytes {
+		return nil, fmt.Errorf("photo is larger than %d MB", stockPhotoMaxBytes>>20)
+	}
+	return data, nil
+}
+
+// UnsplashProvider searches Unsplash (https://unsplash.com/developers)
+type UnsplashProvider struct {
+	endpoint  string
+	accessKey string
+	client    *http.Client
+}
+
+// unsplashPhoto is a photo in Unsplash API responses
+type unsplashPhoto struct {
+	ID             string `json:"id"`
+	Width          int    `json:"width"`
+	Height         int    `json:"height"`
+	Description    string `json:"description"`
+	AltDescription string `json:"alt_description"`
+	URLs           struct {
+		Raw   string `json:"raw"`
+		Small string `json:"small"`
+	} `json:"urls"`
+	Links struct {
+		HTML             string `json:"html"`
+		DownloadLocation string `json:"download_location"`
+	} `json:"links"`
+	User struct {
+		Name  string `json:"name"`
+		Links struct {
+			HTML string `json:"html"`
+		} `json:"links"`
+	} `json:"user"`
+}
+
+// unsplashReferral is the referral Unsplash asks links back to it to carry
+const unsplashReferral = "utm_source=slidepilot&utm_medium=referral"
+
+func (p *UnsplashProvider) Name() string {
+	return "unsplash"
+}
+
+func (p *UnsplashProvider) Search(ctx context.Context, query, orientation string, count int) ([]StockPhoto, error) {
+	params := url.Values{"query": {query}, "per_page": {strconv.Itoa(count)}, "content_filter": {"high"}}
+	if orientation == "square" {
+		orientation = "squarish"
+	}
+	if orientation != "" {
+		params.Set("orientation", orientation)
+	}
+
+	var response struct {
+		Results []unsplashPhoto `json:"results"`
+	}
+	if err := getStockJSON(ctx, p.client, p.endpoint+"/search/photos?"+params.Encode(), "Client-ID "+p.accessKey, &response); err != nil {
+		return nil, fmt.Errorf("Unsplash search failed: %v", err)
+	}
+
+	photos := make([]StockPhoto, len(response.Results))
+	for i, photo := range response.Results {
+		photos[i] = p.stockPhoto(photo)
+	}
+	return photos, nil
+}
+
+func (p *UnsplashProvider) Photo(ctx context.Context, id string) (StockPhoto, error) {
+	var photo unsplashPhoto
+	if err := getStockJSON(ctx, p.client, p.endpoint+"/photos/"+url.PathEscape(id), "Client-ID "+p.accessKey, &photo); err != nil {
+		return StockPhoto{}, fmt.Errorf("Unsplash photo %s: %v", id, err)
+	}
+	return p.stockPhoto(photo), nil
+}
+
+func (p *UnsplashProvider) Download(ctx context.Context, photo StockPhoto) ([]byte, error) {
+	// The download endpoint counts the use; the image itself comes from the CDN
+	var tracked struct {
+		URL string `json:"url"`
+	}
+	if err := getStockJSON(ctx, p.client, photo.trackURL, "Client-ID "+p.accessKey, &tracked); err != nil {
+		return nil, fmt.Errorf("Unsplash download failed: %v", err)
+	}
+	return downloadStockImage(ctx, p.client, photo.downloadURL)
+}
+
+// stockPhoto converts an API photo, sizing the download for a full-width slide
+func (p *UnsplashProvider) stockPhoto(photo unsplashPhoto) StockPhoto {
+	description := photo.Description
+	if description == "" {
+		description = photo.AltDescription
+	}
+	downloadURL := photo.URLs.Raw
+	if raw, err := url.Parse(photo.URLs.Raw); err == nil {
+		query := raw.Query()
+		query.Set("w", "2400")
+		query.Set("fm", "jpg")
+		query.Set("q", "85")
+		raw.RawQuery = query.Encode()
+		downloadURL = raw.String()
+	}
+	return StockPhoto{
+		ID:              "unsplash:" + photo.ID,
+		Description:     description,
+		Width:           photo.Width,
+		Height:          photo.Height,
+		Photographer:    photo.User.Name,
+		PhotographerURL: photo.User.Links.HTML + "?" + unsplashReferral,
+		PageURL:         photo.Links.HTML + "?" + unsplashReferral,
+		PreviewURL:      photo.URLs.Small,
+		Attribution:     fmt.Sprintf("Photo by %s on Unsplash", photo.User.Name),
+		downloadURL:     downloadURL,
+		trackURL:        photo.Links.DownloadLocation,
+	}
+}
+
+// PexelsProvider searches Pexels (https://www.pexels.com/api/)
+type PexelsProvider struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+// pexelsPhoto is a photo in Pexels API responses
+type pexelsPhoto struct {
+	ID              int    `json:"id"`
+	Width           int    `json:"width"`
+	Height          int    `json:"height"`
+	URL             string `json:"url"`
+	Alt             string `json:"alt"`
+	Photographer    string `json:"photographer"`
+	PhotographerURL string `json:"photographer_url"`
+	Src             struct {
+		Large2x string `json:"large2x"`
+		Medium  string `json:"medium"`
+	} `json:"src"`
+}
+
+func (p *PexelsProvider) Name() string {
+	return "pexels"
+}
+
+func (p *PexelsProvider) Search(ctx context.Context, query, orientation string, count int) ([]StockPhoto, error) {
+	params := url.Values{"query": {query}, "per_page": {strconv.Itoa(count)}}
+	if orientation != "" {
+		params.Set("orientation", orientation)
+	}
+
+	var response struct {
+		Photos []pexelsPhoto `json:"photos"`
+	}
+	if err := getStockJSON(ctx, p.client, p.endpoint+"/v1/search?"+params.Encode(), p.apiKey, &response); err != nil {
+		return nil, fmt.Errorf("Pexels search failed: %v", err)
+	}
+
+	photos := make([]StockPhoto, len(response.Photos))
+	for i, photo := range response.Photos {
+		photos[i] = p.stockPhoto(photo)
+	}
+	return photos, nil
+}
+
+func (p *PexelsProvider) Photo(ctx context.Context, id string) (StockPhoto, error) {
+	var photo pexelsPhoto
+	if err := getStockJSON(ctx, p.client, p.endpoint+"/v1/photos/"+url.PathEscape(id), p.apiKey, &photo); err != nil {
+		return StockPhoto{}, fmt.Errorf("Pexels photo %s: %v", id, err)
+	}
+	return p.stockPhoto(photo), nil
+}
+
+func (p *PexelsProvider) Download(ctx context.Context, photo StockPhoto) ([]byte, error) {
+	return downloadStockImage(ctx, p.client, photo.downloadURL)
+}
+
+func (p *PexelsProvider) stockPhoto(photo pexelsPhoto) StockPhoto {
+	return StockPhoto{
+		ID:              fmt.Sprintf("pexels:%d", photo.ID),
+		Description:     photo.Alt,
+		Width:           photo.Width,
+		Height:          photo.Height,
+		Photographer:    photo.Photographer,
+		PhotographerURL: photo.PhotographerURL,
+		PageURL:         photo.URL,
+		PreviewURL:      photo.Src.Medium,
+		Attribution:     fmt.Sprintf("Photo by %s on Pexels", photo.Photographer),
+		downloadURL:     photo.Src.Large2x,
+	}
+}
+
+// SearchStockImageDefinition defines the search_stock_image tool
+var SearchStockImageDefinition = ToolDefinition{
+	Name: "search_stock_image",
+	Description: `Search Unsplash or Pexels for free-to-use stock photos, such as "a picture of a data center".
+
+Returns candidates with an id, description, size, preview URL and the attribution the photographer is owed. Pick the one that fits best, or show the user the choices, then place it with insert_stock_image. Prefer this over generate_image for real-world subjects. Keep queries short and concrete (2-4 words work best).`,
+	InputSchema: SearchStockImageInputSchema,
+	Function:    SearchStockImage,
+	ReadOnly:    true,
+}
+
+type SearchStockImageInput struct {
+	Query       string `json:"query" jsonschema_description:"What the photo should show, e.g. 'data center' or 'team meeting'"`
+	Provider    string `json:"provider,omitempty" jsonschema_description:"(Optional) Photo library to search: 'unsplash' or 'pexels'; defaults to the first one with an API key, Unsplash before Pexels"`
+	Orientation string `json:"orientation,omitempty" jsonschema_description:"(Optional) Photo orientation: 'landscape', 'portrait' or 'square'; landscape suits full-slide backgrounds"`
+	Count       int    `json:"count,omitempty" jsonschema_description:"(Optional) Number of candidates to return, 1-20, defaults to 6"`
+}
+
+var SearchStockImageInputSchema = GenerateSchema[SearchStockImageInput]()
+
+// StockSearchResult is the output of search_stock_image
+type StockSearchResult struct {
+	Success  bool         `json:"success"`
+	Provider string       `json:"provider"`
+	Query    string       `json:"query"`
+	Photos   []StockPhoto `json:"photos"`
+	Message  string       `json:"message"`
+}
+
+func SearchStockImage(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	searchInput := SearchStockImageInput{}
+	if err := json.Unmarshal(input, &searchInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	searchInput.Query = strings.TrimSpace(searchInput.Query)
+	if searchInput.Query == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "query is required")
+	}
+	switch searchInput.Orientation {
+	case "", "landscape", "portrait", "square":
+	default:
+		return "", NewToolError(ErrCodeInvalidInput, "orientation must be landscape, portrait or square")
+	}
+	if searchInput.Count == 0 {
+		searchInput.Count = 6
+	}
+	if searchInput.Count < 1 || searchInput.Count > 20 {
+		return "", NewToolError(ErrCodeInvalidInput, "count must be between 1 and 20")
+	}
+
+	provider, err := stockPhotoProvider(app, searchInput.Provider)
+	if err != nil {
+		return "", err
+	}
+	photos, err := provider.Search(ctx, searchInput.Query, searchInput.Orientation, searchInput.Count)
+	if err != nil {
+		return "", NewToolError(ErrCodeProviderError, "%v", err)
+	}
+
+	message := fmt.Sprintf("Found %d photos for %q on %s", len(photos), searchInput.Query, provider.Name())
+	if len(photos) == 0 {
+		message = fmt.Sprintf("No photos found for %q on %s; try a broader query", searchInput.Query, provider.Name())
+	}
+	return marshalResult(StockSearchResult{
+		Success:  true,
+		Provider: provider.Name(),
+		Query:    searchInput.Query,
+		Photos:   photos,
+		Message:  message,
+	})
+}
+
+// InsertStockImageDefinition defines the insert_stock_image tool
+var InsertStockImageDefinition = ToolDefinition{
+	Name: "insert_stock_image",
+	Description: `Download a stock photo found by search_stock_image, save it into the project assets and place it on a slide.
+
+The photo's description becomes its alt text unless alt_text is given. The result includes the attribution line; tell the user about it and, when they want a visible credit, add it as a small caption or to the speaker notes. Position and size are in inches and default to a centered image that fits within 60% of the slide.`,
+	InputSchema: InsertStockImageInputSchema,
+	Function:    InsertStockImage,
+	Mutating:    true,
+	Screenshot:  true,
+	Timeout:     3 * time.Minute, // Full-size photos can be slow to download
+}
+
+type InsertStockImageInput struct {
+	PresentationPath string   `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	SlideNumber      int      `json:"slide_number" jsonschema_description:"Slide to insert the photo on (1-based indexing)"`
+	ImageID          string   `json:"image_id" jsonschema_description:"Photo id from search_stock_image, e.g. 'unsplash:Xyz123' or 'pexels:12345'"`
+	AltText          string   `json:"alt_text,omitempty" jsonschema_description:"(Optional) Alternative text; defaults to the photo's description"`
+	FileName         string   `json:"file_name,omitempty" jsonschema_description:"(Optional) Base file name for the saved photo in the assets directory"`
+	X                *float64 `json:"x,omitempty" jsonschema_description:"(Optional) Left position in inches"`
+	Y                *float64 `json:"y,omitempty" jsonschema_description:"(Optional) Top position in inches"`
+	Width            *float64 `json:"width,omitempty" jsonschema_description:"(Optional) Width in inches; height follows the aspect ratio if omitted"`
+	Height           *float64 `json:"height,omitempty" jsonschema_description:"(Optional) Height in inches; width follows the aspect ratio if omitted"`
+}
+
+var InsertStockImageInputSchema = GenerateSchema[InsertStockImageInput]()
+
+func InsertStockImage(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	insertInput := InsertStockImageInput{}
+	if err := json.Unmarshal(input, &insertInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if insertInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			insertInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if insertInput.SlideNumber < 1 {
+		return "", NewToolError(ErrCodeInvalidInput, "slide_number must be 1 or greater")
+	}
+	providerName, photoID, ok := strings.Cut(insertInput.ImageID, ":")
+	if !ok || photoID == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "image_id must be an id from search_stock_image such as 'unsplash:Xyz123', got %q", insertInput.ImageID)
+	}
+
+	provider, err := stockPhotoProvider(app, providerName)
+	if err != nil {
+		return "", err
+	}
+	photo, err := provider.Photo(ctx, photoID)
+	if err != nil {
+		return "", NewToolError(ErrCodeProviderError, "%v", err)
+	}
+	imageData, err := provider.Download(ctx, photo)
+	if err != nil {
+		return "", NewToolError(ErrCodeProviderError, "%v", err)
+	}
+
+	fileName := insertInput.FileName
+	if fileName == "" {
+		fileName = provider.Name() + "-" + photoID
+	}
+	imagePath, err := saveGeneratedImage(imageData, fileName)
+	if err != nil {
+		return "", err
+	}
+
+	insertResult, err := insertImageOnSlide(ctx, app, insertInput.PresentationPath, insertInput.SlideNumber, imagePath,
+		insertInput.X, insertInput.Y, insertInput.Width, insertInput.Height)
+	if err != nil {
+		return "", NewToolError(toolErrorCode(err), "photo saved to %s but could not be inserted: %v", imagePath, err)
+	}
+
+	result := map[string]interface{}{
+		"success":     true,
+		"image_path":  imagePath,
+		"photo":       photo,
+		"attribution": photo.Attribution,
+		"inserted":    insertResult,
+		"message":     fmt.Sprintf("Inserted %s on slide %d", strings.Replace(photo.Attribution, "Photo", "photo", 1), insertInput.SlideNumber),
+	}
+
+	altText := strings.TrimSpace(insertInput.AltText)
+	if altText == "" {
+		altText = photo.Description
+	}
+	if shapeIndex, ok := insertResult["shape_index"].(float64); ok && altText != "" {
+		output, err := runUnoScript(ctx, app, "uno_set_alt_text.py",
+			insertInput.PresentationPath,
+			fmt.Sprintf("%d", insertInput.SlideNumber),
+			fmt.Sprintf("%d", int(shapeIndex)),
+			altText)
+		if err != nil {
+			result["warnings"] = []string{fmt.Sprintf("alt text was not set: %v", scriptError("failed to set alt text", err, output))}
+		} else {
+			result["alt_text"] = altText
+		}
+	}
+
+	// Queue the slide for export to update UI
+	schedulePreviewExport(ctx, app, insertInput.PresentationPath, insertInput.SlideNumber)
+
+	return marshalResult(result)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// fakeStockServer answers the Unsplash and Pexels API calls the stock photo tools make,
+// recording the requests
+func fakeStockServer(t *testing.T) (*httptest.Server, *[]string) {
+	var requests []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Header.Get("Authorization")+" "+r.URL.RequestURI())
+		unsplashPhoto := map[string]interface{}{
+			"id": "abc", "width": 4000, "height": 3000, "description": nil, "alt_description": "rows of server racks",
+			"urls":  map[string]string{"raw": server.URL + "/images/abc?ixid=1", "small": server.URL + "/images/abc-small"},
+			"links": map[string]string{"html": "https://unsplash.com/photos/abc", "download_location": server.URL + "/photos/abc/download"},
+			"user":  map[string]interface{}{"name": "Jane Doe", "links": map[string]string{"html": "https://unsplash.com/@jane"}},
+		}
+		pexelsPhoto := map[string]interface{}{
+			"id": 42, "width": 3000, "height": 2000, "url": "https://www.pexels.com/photo/42/", "alt": "Blue network cables",
+			"photographer": "Sam Lee", "photographer_url": "https://www.pexels.com/@sam",
+			"src": map[string]string{"large2x": server.URL + "/images/42", "medium": server.URL + "/images/42-medium"},
+		}
+		switch r.URL.Path {
+		case "/search/photos":
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{unsplashPhoto}})
+		case "/photos/abc":
+			json.NewEncoder(w).Encode(unsplashPhoto)
+		case "/photos/abc/download":
+			json.NewEncoder(w).Encode(map[string]string{"url": server.URL + "/images/abc"})
+		case "/v1/photos/42":
+			json.NewEncoder(w).Encode(pexelsPhoto)
+		case "/images/abc", "/images/42":
+			w.Write([]byte("\xff\xd8\xff\xe0 jpeg"))
+		default:
+			http.Error(w, `{"errors": ["Couldn't find Photo"]}`, http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	endpoints := stockPhotoEndpoints
+	stockPhotoEndpoints = map[string]string{"unsplash": server.URL, "pexels": server.URL}
+	t.Cleanup(func() { stockPhotoEndpoints = endpoints })
+	return server, &requests
+}
+
+func TestSearchStockImage(t *testing.T) {
+	env := newTestEnv(t)
+	_, requests := fakeStockServer(t)
+	t.Setenv("UNSPLASH_ACCESS_KEY", "")
+	t.Setenv("PEXELS_API_KEY", "")
+
+	_, err := SearchStockImage(context.Background(), env.app, json.RawMessage(`{"query": "data center"}`))
+	if code := toolErrorCode(err); code != ErrCodeProviderError {
+		t.Errorf("expected %s without API keys, got %s (%v)", ErrCodeProviderError, code, err)
+	}
+
+	settings := env.app.aiAgent.Settings()
+	settings.UnsplashAccessKey = "unsplash-key"
+	env.app.aiAgent.SetSettings(settings)
+	output, err := SearchStockImage(context.Background(), env.app, json.RawMessage(`{"query": "data center", "orientation": "square", "count": 3}`))
+	if err != nil {
+		t.Fatalf("SearchStockImage failed: %v", err)
+	}
+	if want := "Client-ID unsplash-key /search/photos?content_filter=high&orientation=squarish&per_page=3&query=data+center"; len(*requests) != 1 || (*requests)[0] != want {
+		t.Errorf("expected request %q, got %q", want, *requests)
+	}
+
+	var result StockSearchResult
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Provider != "unsplash" || len(result.Photos) != 1 {
+		t.Fatalf("unexpected result: %s", output)
+	}
+	photo := result.Photos[0]
+	if photo.ID != "unsplash:abc" || photo.Description != "rows of server racks" || photo.Attribution != "Photo by Jane Doe on Unsplash" ||
+		!strings.HasSuffix(photo.PageURL, "?"+unsplashReferral) {
+		t.Errorf("unexpected photo: %+v", photo)
+	}
+
+	for input, want := range map[string]ToolErrorCode{
+		`{"query": " "}`: ErrCodeInvalidInput,
+		`{"query": "servers", "orientation": "wide"}`: ErrCodeInvalidInput,
+		`{"query": "servers", "count": 21}`:           ErrCodeInvalidInput,
+		`{"query": "servers", "provider": "flickr"}`:  ErrCodeInvalidInput,
+		`{"query": "servers", "provider": "pexels"}`:  ErrCodeProviderError,
+	} {
+		_, err := SearchStockImage(context.Background(), env.app, json.RawMessage(input))
+		if code := toolErrorCode(err); code != want {
+			t.Errorf("expected %s for %s, got %s (%v)", want, input, code, err)
+		}
+	}
+}
+
+func TestInsertStockImage(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	_, requests := fakeStockServer(t)
+	t.Setenv("UNSPLASH_ACCESS_KEY", "unsplash-key")
+	t.Setenv("PEXELS_API_KEY", "pexels-key")
+	env.uno.Respond("uno_insert_image.py", `{"success": true, "slide_number": 2, "shape_index": 3, "shape_id": "Picture 3"}`)
+	env.uno.Respond("uno_set_alt_text.py", `{"success": true}`)
+
+	output, err := InsertStockImage(context.Background(), env.app, json.RawMessage(`{"slide_number": 2, "image_id": "unsplash:abc", "width": 5}`))
+	if err != nil {
+		t.Fatalf("InsertStockImage failed: %v", err)
+	}
+	// Unsplash counts the download before the image is fetched at slide resolution
+	want := []string{
+		"Client-ID unsplash-key /photos/abc",
+		"Client-ID unsplash-key /photos/abc/download",
+		" /images/abc?fm=jpg&ixid=1&q=85&w=2400",
+	}
+	if fmt.Sprint(*requests) != fmt.Sprint(want) {
+		t.Errorf("expected requests %q, got %q", want, *requests)
+	}
+
+	var result struct {
+		ImagePath   string `json:"image_path"`
+		Attribution string `json:"attribution"`
+		AltText     string `json:"alt_text"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(result.ImagePath, "unsplash-abc.jpg") || result.Attribution != "Photo by Jane Doe on Unsplash" {
+		t.Errorf("unexpected result: %s", output)
+	}
+	if _, err := os.Stat(result.ImagePath); err != nil {
+		t.Errorf("expected the photo saved in the assets: %v", err)
+	}
+	calls := env.uno.Calls("uno_insert_image.py")
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint([]string{path, "2", result.ImagePath, "", "", "5", ""}) {
+		t.Errorf("unexpected insert calls: %+v", calls)
+	}
+	calls = env.uno.Calls("uno_set_alt_text.py")
+	if len(calls) != 1 || fmt.Sprint(calls[0].Args) != fmt.Sprint([]string{path, "2", "3", "rows of server racks"}) {
+		t.Errorf("expected the description as alt text, got %+v", calls)
+	}
+
+	// Pexels photos are fetched directly, and alt_text overrides the description
+	if _, err := InsertStockImage(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "image_id": "pexels:42", "alt_text": "Network cables"}`)); err != nil {
+		t.Fatalf("InsertStockImage failed for Pexels: %v", err)
+	}
+	if calls := env.uno.Calls("uno_set_alt_text.py"); len(calls) != 2 || calls[1].Args[3] != "Network cables" {
+		t.Errorf("expected the given alt text, got %+v", calls)
+	}
+
+	for input, want := range map[string]ToolErrorCode{
+		`{"slide_number": 0, "image_id": "unsplash:abc"}`:  ErrCodeInvalidInput,
+		`{"slide_number": 1, "image_id": "abc"}`:           ErrCodeInvalidInput,
+		`{"slide_number": 1, "image_id": "unsplash:nope"}`: ErrCodeProviderError,
+	} {
+		_, err := InsertStockImage(context.Background(), env.app, json.RawMessage(input))
+		if code := toolErrorCode(err); code != want {
+			t.Errorf("expected %s for %s, got %s (%v)", want, input, code, err)
+		}
+	}
+}
@@ -0,0 +1,488 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// styleChecks are the checks lint_style runs, in report order
+var styleChecks = []string{"fonts", "capitalization", "colors", "alignment"}
+
+const (
+	// Colors this close to a palette color (RGB distance) count as that color
+	paletteTolerance = 24
+	// Shapes whose edges are this close, but not closer, look nudged out of line (inches)
+	nearAlignment    = 0.1
+	alignedTolerance = 0.02
+)
+
+// titleCaseSmallWords stay lowercase inside Title Case titles
+var titleCaseSmallWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "but": true, "or": true, "nor": true, "for": true,
+	"of": true, "in": true, "on": true, "at": true, "to": true, "by": true, "with": true, "vs": true,
+	"via": true, "per": true, "from": true, "as": true, "into": true, "over": true,
+}
+
+// styleShapeFacts is what uno_lint_style.py reports about a top-level shape
+type styleShapeFacts struct {
+	ShapeIndex int            `json:"shape_index"`
+	ShapeID    string         `json:"shape_id"`
+	Role       string         `json:"role"` // title, subtitle, body or shape
+	X          float64        `json:"x"`
+	Y          float64        `json:"y"`
+	Width      float64        `json:"width"`
+	Height     float64        `json:"height"`
+	Text       string         `json:"text"`        // Titles only
+	Fonts      map[string]int `json:"fonts"`       // Characters per font
+	TextColors map[string]int `json:"text_colors"` // Characters per #RRGGBB text color
+	FillColors []string       `json:"fill_colors"`
+}
+
+type styleSlideFacts struct {
+	SlideNumber int               `json:"slide_number"`
+	Shapes      []styleShapeFacts `json:"shapes"`
+}
+
+// StyleIssue is a deviation lint_style found from the deck's own conventions or the palette
+type StyleIssue struct {
+	Type        string `json:"type"` // font, capitalization, color or alignment
+	SlideNumber int    `json:"slide_number"`
+	ShapeIndex  int    `json:"shape_index"`
+	ShapeID     string `json:"shape_id"`
+	Message     string `json:"message"`
+	Found       string `json:"found,omitempty"`
+	Expected    string `json:"expected,omitempty"`
+}
+
+// styleConventions are what most of the deck does, which the other slides are held to
+type styleConventions struct {
+	TitleFont     string      `json:"title_font,omitempty"`
+	BodyFont      string      `json:"body_font,omitempty"`
+	TitleCase     string      `json:"title_case,omitempty"` // title, sentence or upper
+	TitlePosition *[2]float64 `json:"title_position,omitempty"`
+}
+
+// styleLintOptions are the checks to run and the style guide to hold the deck to
+type styleLintOptions struct {
+	Checks  []string
+	Fonts   []string // Allowed fonts; empty holds each shape to the deck's most used font
+	Palette []string // #RRGGBB brand colors; empty skips the color check
+}
+
+// lintStyle compares the shapes of every slide against the deck's conventions and the
+// style guide in options
+func lintStyle(slides []styleSlideFacts, options styleLintOptions) (styleConventions, []StyleIssue) {
+	var conventions styleConventions
+	issues := []StyleIssue{}
+	if slices.Contains(options.Checks, "fonts") {
+		issues = append(issues, lintFonts(slides, options.Fonts, &conventions)...)
+	}
+	if slices.Contains(options.Checks, "capitalization") {
+		issues = append(issues, lintTitleCase(slides, &conventions)...)
+	}
+	if slices.Contains(options.Checks, "colors") && len(options.Palette) > 0 {
+		issues = append(issues, lintColors(slides, options.Palette)...)
+	}
+	if slices.Contains(options.Checks, "alignment") {
+		issues = append(issues, lintAlignment(slides, &conventions)...)
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].SlideNumber != issues[j].SlideNumber {
+			return issues[i].SlideNumber < issues[j].SlideNumber
+		}
+		return issues[i].ShapeIndex < issues[j].ShapeIndex
+	})
+	return conventions, issues
+}
+
+// lintFonts flags fonts outside the allowed ones, or, without a list, fonts other than the
+// one most of the deck's titles (or other text) use
+func lintFonts(slides []styleSlideFacts, allowed []string, conventions *styleConventions) []StyleIssue {
+	usage := map[bool]map[string]int{true: {}, false: {}} // By whether the text is a title
+	for _, slide := range slides {
+		for _, shape := range slide.Shapes {
+			for font, chars := range shape.Fonts {
+				usage[shape.Role == "title"][font] += chars
+			}
+		}
+	}
+	conventions.TitleFont = mostUsed(usage[true])
+	conventions.BodyFont = mostUsed(usage[false])
+
+	var issues []StyleIssue
+	for _, slide := range slides {
+		for _, shape := range slide.Shapes {
+			expected := conventions.BodyFont
+			if shape.Role == "title" {
+				expected = conventions.TitleFont
+			}
+			var off []string
+			for font := range shape.Fonts {
+				if len(allowed) > 0 && !slices.ContainsFunc(allowed, func(name string) bool { return strings.EqualFold(name, font) }) ||
+					len(allowed) == 0 && font != expected {
+					off = append(off, font)
+				}
+			}
+			if len(off) == 0 {
+				continue
+			}
+			slices.Sort(off)
+			want := expected
+			if len(allowed) > 0 {
+				want = strings.Join(allowed, ", ")
+			}
+			issues = append(issues, StyleIssue{
+				Type:        "font",
+				SlideNumber: slide.SlideNumber,
+				ShapeIndex:  shape.ShapeIndex,
+				ShapeID:     shape.ShapeID,
+				Message:     fmt.Sprintf("Uses %s instead of %s", strings.Join(off, ", "), want),
+				Found:       strings.Join(off, ", "),
+				Expected:    want,
+			})
+		}
+	}
+	return issues
+}
+
+// mostUsed returns the key with the highest count, the alphabetically first on a tie
+func mostUsed(counts map[string]int) string {
+	best := ""
+	for key, count := range counts {
+		if best == "" || count > counts[best] || count == counts[best] && key < best {
+			best = key
+		}
+	}
+	return best
+}
+
+// titleCase classifies how a title is capitalized: "title" (Title Case), "sentence",
+// "upper" or "mixed", or "" when it has too few words to tell
+func titleCase(text string) string {
+	var words []string
+	for _, field := range strings.Fields(text) {
+		word := strings.TrimFunc(field, func(r rune) bool { return !unicode.IsLetter(r) })
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	if len(words) < 2 {
+		return ""
+	}
+	if strings.ToUpper(text) == text {
+		return "upper"
+	}
+	if !unicode.IsUpper([]rune(words[0])[0]) {
+		return "mixed"
+	}
+
+	significant, capitalized := 0, 0
+	for _, word := range words[1:] {
+		runes := []rune(word)
+		// Acronyms and names like iPhone are written the same in any style
+		if strings.ToUpper(word) == word || slices.ContainsFunc(runes[1:], unicode.IsUpper) || titleCaseSmallWords[strings.ToLower(word)] {
+			continue
+		}
+		significant++
+		if unicode.IsUpper(runes[0]) {
+			capitalized++
+		}
+	}
+	switch {
+	case significant == 0:
+		return ""
+	case capitalized == significant:
+		return "title"
+	case capitalized == 0:
+		return "sentence"
+	}
+	return "mixed"
+}
+
+// lintTitleCase flags titles capitalized differently from most of the deck's titles. In a
+// sentence-case deck a title with some capitalized words is taken to name something.
+func lintTitleCase(slides []styleSlideFacts, conventions *styleConventions) []StyleIssue {
+	counts := map[string]int{}
+	for _, slide := range slides {
+		for _, shape := range slide.Shapes {
+			if style := titleCase(shape.Text); shape.Role == "title" && style != "" && style != "mixed" {
+				counts[style]++
+			}
+		}
+	}
+	conventions.TitleCase = mostUsed(counts)
+	if conventions.TitleCase == "" {
+		return nil
+	}
+
+	var issues []StyleIssue
+	for _, slide := range slides {
+		for _, shape := range slide.Shapes {
+			style := titleCase(shape.Text)
+			if shape.Role != "title" || style == "" || style == conventions.TitleCase ||
+				style == "mixed" && conventions.TitleCase == "sentence" {
+				continue
+			}
+			issues = append(issues, StyleIssue{
+				Type:        "capitalization",
+				SlideNumber: slide.SlideNumber,
+				ShapeIndex:  shape.ShapeIndex,
+				ShapeID:     shape.ShapeID,
+				Message:     fmt.Sprintf("Title %q is in %s case while most titles are in %s case", shape.Text, style, conventions.TitleCase),
+				Found:       style,
+				Expected:    conventions.TitleCase,
+			})
+		}
+	}
+	return issues
+}
+
+// parseHexColor turns #RRGGBB into its channels
+func parseHexColor(color string) ([3]float64, bool) {
+	value, err := strconv.ParseUint(strings.TrimPrefix(color, "#"), 16, 32)
+	if err != nil || len(strings.TrimPrefix(color, "#")) != 6 {
+		return [3]float64{}, false
+	}
+	return [3]float64{float64(value >> 16 & 0xFF), float64(value >> 8 & 0xFF), float64(value & 0xFF)}, true
+}
+
+// nearestPaletteColor returns the palette color closest to color and their distance
+func nearestPaletteColor(color [3]float64, palette []string) (string, float64) {
+	nearest, best := "", math.Inf(1)
+	for _, entry := range palette {
+		candidate, ok := parseHexColor(entry)
+		if !ok {
+			continue
+		}
+		distance := math.Sqrt(math.Pow(color[0]-candidate[0], 2) + math.Pow(color[1]-candidate[1], 2) + math.Pow(color[2]-candidate[2], 2))
+		if distance < best {
+			nearest, best = entry, distance
+		}
+	}
+	return nearest, best
+}
+
+// lintColors flags text and fill colors that are neither in the palette nor neutral
+// (black, white and grays go with any palette)
+func lintColors(slides []styleSlideFacts, palette []string) []StyleIssue {
+	var issues []StyleIssue
+	for _, slide := range slides {
+		for _, shape := range slide.Shapes {
+			colors := append(slices.Collect(maps.Keys(shape.TextColors)), shape.FillColors...)
+			slices.Sort(colors)
+			colors = slices.Compact(colors)
+
+			for _, color := range colors {
+				channels, ok := parseHexColor(color)
+				if !ok || slices.Max(channels[:])-slices.Min(channels[:]) <= 10 {
+					continue
+				}
+				nearest, distance := nearestPaletteColor(channels, palette)
+				if distance <= paletteTolerance {
+					continue
+				}
+				issues = append(issues, StyleIssue{
+					Type:        "color",
+					SlideNumber: slide.SlideNumber,
+					ShapeIndex:  shape.ShapeIndex,
+					ShapeID:     shape.ShapeID,
+					Message:     fmt.Sprintf("Uses %s, which is not in the palette; the closest palette color is %s", color, nearest),
+					Found:       color,
+					Expected:    nearest,
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// lintAlignment flags titles slightly off the position most titles share, and shapes whose
+// left or top edge is almost, but not quite, in line with another shape on the slide
+func lintAlignment(slides []styleSlideFacts, conventions *styleConventions) []StyleIssue {
+	var issues []StyleIssue
+
+	positions := map[[2]float64]int{}
+	for _, slide := range slides {
+		for _, shape := range slide.Shapes {
+			if shape.Role == "title" {
+				positions[[2]float64{math.Round(shape.X*20) / 20, math.Round(shape.Y*20) / 20}]++
+			}
+		}
+	}
+	var common [2]float64
+	best := 0
+	for position, count := range positions {
+		if count > best || count == best && (position[1] < common[1] || position[1] == common[1] && position[0] < common[0]) {
+			common, best = position, count
+		}
+	}
+	if best >= 2 {
+		conventions.TitlePosition = &common
+	}
+
+	for _, slide := range slides {
+		misplaced := map[int]bool{}
+		for i, shape := range slide.Shapes {
+			if shape.Role == "title" && conventions.TitlePosition != nil {
+				dx, dy := math.Abs(shape.X-common[0]), math.Abs(shape.Y-common[1])
+				if max(dx, dy) > alignedTolerance*2 && max(dx, dy) <= 0.5 {
+					issues = append(issues, StyleIssue{
+						Type:        "alignment",
+						SlideNumber: slide.SlideNumber,
+						ShapeIndex:  shape.ShapeIndex,
+						ShapeID:     shape.ShapeID,
+						Message:     fmt.Sprintf("Title is at (%.2f, %.2f) in while most titles are at (%.2f, %.2f) in", shape.X, shape.Y, common[0], common[1]),
+						Found:       fmt.Sprintf("%.2f,%.2f", shape.X, shape.Y),
+						Expected:    fmt.Sprintf("%.2f,%.2f", common[0], common[1]),
+					})
+					misplaced[i] = true
+					continue
+				}
+			}
+
+			// Compare with the shapes before it, reporting the first near miss only; a title
+			// already off its usual position is no reference
+			for j, other := range slide.Shapes[:i] {
+				if misplaced[j] {
+					continue
+				}
+				edge, found, expected := "", 0.0, 0.0
+				if d := math.Abs(shape.X - other.X); d > alignedTolerance && d <= nearAlignment {
+					edge, found, expected = "left edge", shape.X, other.X
+				} else if d := math.Abs(shape.Y - other.Y); d > alignedTolerance && d <= nearAlignment {
+					edge, found, expected = "top edge", shape.Y, other.Y
+				}
+				if edge == "" {
+					continue
+				}
+				issues = append(issues, StyleIssue{
+					Type:        "alignment",
+					SlideNumber: slide.SlideNumber,
+					ShapeIndex:  shape.ShapeIndex,
+					ShapeID:     shape.ShapeID,
+					Message:     fmt.Sprintf("Its %s is at %.2f in, just off %s's at %.2f in", edge, found, other.ShapeID, expected),
+					Found:       fmt.Sprintf("%.2f", found),
+					Expected:    fmt.Sprintf("%.2f", expected),
+				})
+				break
+			}
+		}
+	}
+	return issues
+}
+
+// LintStyleDefinition defines the lint_style tool
+var LintStyleDefinition = ToolDefinition{
+	Name: "lint_style",
+	Description: `Check the deck for style inconsistencies and report them per slide with the shape they concern:
+- fonts: fonts other than the one most titles (or most other text) use, or outside the given fonts
+- capitalization: titles in a different case (Title Case, Sentence case, UPPER CASE) than most titles
+- colors: text and fill colors not in the given palette; black, white and grays are always accepted
+- alignment: titles slightly off the position most titles share, and shapes whose left or top edge is a hair off another shape's on the slide
+
+The result also gives the conventions found (title and body font, title case, title position) that the rest is held to. Fonts and palette default to the user's brand profile (see get_brand_profile); without a palette the color check is skipped. Fix fonts, colors and positions with the format_text and move_shape edits of apply_edits, and titles with edit_slide_text.`,
+	InputSchema: LintStyleInputSchema,
+	Function:    LintStyle,
+	ReadOnly:    true,
+}
+
+type LintStyleInput struct {
+	PresentationPath string   `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Checks           []string `json:"checks,omitempty" jsonschema_description:"(Optional) Checks to run: 'fonts', 'capitalization', 'colors' and/or 'alignment'; defaults to all"`
+	Fonts            []string `json:"fonts,omitempty" jsonschema_description:"(Optional) Fonts the style guide allows, e.g. ['Montserrat', 'Open Sans']"`
+	Palette          []string `json:"palette,omitempty" jsonschema_description:"(Optional) Brand colors as #RRGGBB, e.g. ['#0B3D91', '#FC3D21']"`
+}
+
+var LintStyleInputSchema = GenerateSchema[LintStyleInput]()
+
+func LintStyle(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	lintInput := LintStyleInput{}
+	if err := json.Unmarshal(input, &lintInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if lintInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			lintInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+	if _, err := os.Stat(lintInput.PresentationPath); os.IsNotExist(err) {
+		return "", NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", lintInput.PresentationPath)
+	}
+
+	checks := lintInput.Checks
+	if len(checks) == 0 {
+		checks = styleChecks
+	}
+	for _, check := range checks {
+		if !slices.Contains(styleChecks, check) {
+			return "", NewToolError(ErrCodeInvalidInput, "checks must be among: %s", strings.Join(styleChecks, ", "))
+		}
+	}
+	// The brand profile stands in for fonts and colors the caller leaves out
+	if brand := app.brandProfile(); brand != nil {
+		if len(lintInput.Fonts) == 0 {
+			for _, font := range []string{brand.HeadingFont, brand.BodyFont} {
+				if font != "" && !slices.Contains(lintInput.Fonts, font) {
+					lintInput.Fonts = append(lintInput.Fonts, font)
+				}
+			}
+		}
+		if len(lintInput.Palette) == 0 {
+			lintInput.Palette = brand.Palette
+		}
+	}
+	for _, color := range lintInput.Palette {
+		if !hexColorPattern.MatchString(color) {
+			return "", NewToolError(ErrCodeInvalidInput, "palette colors must be #RRGGBB, got %q", color)
+		}
+	}
+	if len(lintInput.Checks) > 0 && slices.Contains(lintInput.Checks, "colors") && len(lintInput.Palette) == 0 {
+		return "", NewToolError(ErrCodeInvalidInput, "the colors check needs a palette of brand colors")
+	}
+	palette := make([]string, len(lintInput.Palette))
+	for i, color := range lintInput.Palette {
+		palette[i] = "#" + strings.ToUpper(strings.TrimPrefix(color, "#"))
+	}
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_lint_style.py", lintInput.PresentationPath)
+	if err != nil {
+		return "", scriptError("failed to read slide styles", err, output)
+	}
+	facts, err := decodeScriptResult[struct {
+		Slides []styleSlideFacts `json:"slides"`
+	}](output)
+	if err != nil {
+		return "", err
+	}
+
+	conventions, issues := lintStyle(facts.Slides, styleLintOptions{Checks: checks, Fonts: lintInput.Fonts, Palette: palette})
+	summary := map[string]int{}
+	for _, issue := range issues {
+		summary[issue.Type]++
+	}
+	result := map[string]interface{}{
+		"conventions": conventions,
+		"issues":      issues,
+		"issue_count": len(issues),
+		"summary":     summary,
+		"message":     fmt.Sprintf("Found %d style issue(s) across %d slide(s)", len(issues), len(facts.Slides)),
+	}
+	if slices.Contains(checks, "colors") && len(palette) == 0 {
+		result["note"] = "No palette given, so colors were not checked"
+	}
+	return marshalResult(result)
+}
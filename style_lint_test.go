@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestTitleCase(t *testing.T) {
+	for text, want := range map[string]string{
+		"Quarterly Results for the Region": "title",
+		"Quarterly results for the region": "sentence",
+		"QUARTERLY RESULTS":                "upper",
+		"Our results at Acme":              "mixed",
+		"Roadmap":                          "",
+		"Why the iPhone and NASA matter":   "sentence",
+	} {
+		if got := titleCase(text); got != want {
+			t.Errorf("titleCase(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestLintStyleHoldsSlidesToTheDeck(t *testing.T) {
+	title := func(slide int, text string, x float64, font string) styleSlideFacts {
+		return styleSlideFacts{SlideNumber: slide, Shapes: []styleShapeFacts{
+			{ShapeIndex: 0, ShapeID: "Title 1", Role: "title", X: x, Y: 0.5, Text: text, Fonts: map[string]int{font: len(text)}},
+			{ShapeIndex: 1, ShapeID: "Content 2", Role: "body", X: 0.5, Y: 1.5, Fonts: map[string]int{"Calibri": 200},
+				TextColors: map[string]int{"#1F3864": 200}, FillColors: []string{"#FFFFFF"}},
+		}}
+	}
+	slides := []styleSlideFacts{
+		title(1, "Market Overview", 0.5, "Montserrat"),
+		title(2, "Growth by Region", 0.5, "Montserrat"),
+		title(3, "Next steps for the team", 0.56, "Arial"),
+	}
+	slides[2].Shapes = append(slides[2].Shapes, styleShapeFacts{ShapeIndex: 2, ShapeID: "Callout 3", Role: "shape", X: 0.55, Y: 4,
+		Fonts: map[string]int{"Calibri": 10}, FillColors: []string{"#FF00FF", "#808080"}})
+
+	conventions, issues := lintStyle(slides, styleLintOptions{Checks: styleChecks, Palette: []string{"#1F3864", "#C00000"}})
+	if conventions.TitleFont != "Montserrat" || conventions.BodyFont != "Calibri" || conventions.TitleCase != "title" ||
+		conventions.TitlePosition == nil || conventions.TitlePosition[0] != 0.5 {
+		t.Errorf("unexpected conventions: %+v", conventions)
+	}
+
+	found := map[string]StyleIssue{}
+	for _, issue := range issues {
+		if issue.SlideNumber != 3 {
+			t.Errorf("only slide 3 strays from the deck, got %+v", issue)
+		}
+		found[issue.Type+" "+issue.ShapeID] = issue
+	}
+	for _, want := range []string{"font Title 1", "capitalization Title 1", "alignment Title 1", "color Callout 3", "alignment Callout 3"} {
+		if _, ok := found[want]; !ok {
+			t.Errorf("expected a %s issue, got %+v", want, issues)
+		}
+	}
+	if len(issues) != 5 {
+		t.Errorf("expected 5 issues, got %+v", issues)
+	}
+	if issue := found["color Callout 3"]; issue.Found != "#FF00FF" {
+		t.Errorf("gray should go with any palette, got %+v", issue)
+	}
+
+	// An allowed font list replaces the deck's own majority
+	_, issues = lintStyle(slides, styleLintOptions{Checks: []string{"fonts"}, Fonts: []string{"montserrat", "Calibri"}})
+	if len(issues) != 1 || issues[0].Found != "Arial" {
+		t.Errorf("expected only Arial to be off-style, got %+v", issues)
+	}
+}
+
+func TestLintStyleTool(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_lint_style.py", `{"success": true, "slides": [{"slide_number": 1, "shapes": [
+		{"shape_index": 0, "shape_id": "Title 1", "role": "title", "x": 0.5, "y": 0.5, "text": "Hello World", "fonts": {"Calibri": 10}}]}]}`)
+
+	output, err := LintStyle(context.Background(), env.app, json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("LintStyle failed: %v", err)
+	}
+	var result struct {
+		IssueCount int    `json:"issue_count"`
+		Note       string `json:"note"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil || result.IssueCount != 0 || result.Note == "" {
+		t.Errorf("unexpected result: %s (%v)", output, err)
+	}
+
+	for _, bad := range []string{`{"checks": ["spacing"]}`, `{"palette": ["blue"]}`, `{"checks": ["colors"]}`} {
+		if _, err := LintStyle(context.Background(), env.app, json.RawMessage(bad)); toolErrorCode(err) != ErrCodeInvalidInput {
+			t.Errorf("expected %s to be refused, got %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestInferenceSendsTheSystemPrompt(t *testing.T) {
+	env := newTestEnv(t, textResponse("Done."), textResponse("Done again."))
+	env.loadFixture(t, "two_slides.pptx")
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Hi"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	system := env.llm.Requests[0].System
+	if len(system) != 1 || !strings.HasPrefix(system[0].Text, defaultSystemPrompt) ||
+		!strings.Contains(system[0].Text, "two_slides.pptx") || !strings.Contains(system[0].Text, "with 2 slides") {
+		t.Errorf("expected the default prompt and the loaded presentation, got %+v", system)
+	}
+
+	// A prompt from the settings replaces the default on the next request
+	settings := env.app.GetSettings()
+	settings.SystemPrompt = "Answer in French."
+	if _, err := env.app.UpdateSettings(settings); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	if err := env.app.aiAgent.SendMessage(context.Background(), "Hi again"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	text := env.llm.Requests[1].System[0].Text
+	if !strings.HasPrefix(text, "Answer in French.") || strings.Contains(text, "SlidePilot") {
+		t.Errorf("expected the custom prompt, got %q", text)
+	}
+}
+
+func TestSystemPromptWithoutPresentation(t *testing.T) {
+	env := newTestEnv(t)
+	if prompt := env.app.aiAgent.systemPrompt(); !strings.Contains(prompt, "No presentation is loaded") {
+		t.Errorf("expected the prompt to say no presentation is loaded, got %q", prompt)
+	}
+}
@@ -0,0 +1,349 @@
+#!/usr/bin/env python3
+"""
+Generates the minimal .pptx fixtures used by the Go tests.
+
+The packages contain just enough Office Open XML (presentation, slides,
+one layout, master, and theme) for zip validation and the tool layer.
+template.potx is a slide-less template with two layouts and a logo for
+apply_template; master_shapes.pptx has placeholders, a logo and text styles on its
+master and layout for the master editing tools. sales.xlsx is a workbook with shared
+and inline strings, a percentage and a date column for import_data.
+Run from this directory: python3 make_fixtures.py
+"""
+
+import zipfile
+from xml.sax.saxutils import escape
+
+NS = ('xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" '
+      'xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" '
+      'xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"')
+
+REL_NS = "http://schemas.openxmlformats.org/package/2006/relationships"
+R_TYPE = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+
+
+def text_shape(shape_id, name, paragraphs, placeholder=None):
+    ph = f'<p:nvPr><p:ph type="{placeholder}"/></p:nvPr>' if placeholder else '<p:nvPr/>'
+    paras = "".join(f'<a:p><a:r><a:rPr lang="en-US"/><a:t>{escape(p)}</a:t></a:r></a:p>' for p in paragraphs)
+    return (f'<p:sp><p:nvSpPr><p:cNvPr id="{shape_id}" name="{escape(name)}"/><p:cNvSpPr/>{ph}</p:nvSpPr>'
+            f'<p:spPr><a:xfrm><a:off x="838200" y="365125"/><a:ext cx="10515600" cy="1325563"/></a:xfrm></p:spPr>'
+            f'<p:txBody><a:bodyPr/><a:lstStyle/>{paras}</p:txBody></p:sp>')
+
+
+def xfrm(x, y, cx, cy, prefix="a"):
+    return f'<{prefix}:xfrm><a:off x="{x}" y="{y}"/><a:ext cx="{cx}" cy="{cy}"/></{prefix}:xfrm>'
+
+
+def picture(shape_id, name, rel="rIdMissing", descr=""):
+    return (f'<p:pic><p:nvPicPr><p:cNvPr id="{shape_id}" name="{escape(name)}" descr="{escape(descr)}"/><p:cNvPicPr/><p:nvPr/></p:nvPicPr>'
+            f'<p:blipFill><a:blip r:embed="{rel}"/></p:blipFill>'
+            f'<p:spPr>{xfrm(914400, 1828800, 1828800, 914400)}</p:spPr></p:pic>')
+
+
+def group(shape_id, name, children):
+    return (f'<p:grpSp><p:nvGrpSpPr><p:cNvPr id="{shape_id}" name="{escape(name)}"/><p:cNvGrpSpPr/><p:nvPr/></p:nvGrpSpPr>'
+            f'<p:grpSpPr>{xfrm(0, 0, 914400, 914400)}</p:grpSpPr>{"".join(children)}</p:grpSp>')
+
+
+def table(shape_id, name, rows):
+    body = "".join(
+        '<a:tr h="370840">' + "".join(
+            f'<a:tc><a:txBody><a:bodyPr/><a:lstStyle/><a:p><a:r><a:rPr lang="en-US"/><a:t>{escape(cell)}</a:t></a:r></a:p></a:txBody><a:tcPr/></a:tc>'
+            for cell in row) + '</a:tr>'
+        for row in rows)
+    return (f'<p:graphicFrame><p:nvGraphicFramePr><p:cNvPr id="{shape_id}" name="{escape(name)}"/><p:cNvGraphicFramePr/><p:nvPr/></p:nvGraphicFramePr>'
+            f'{xfrm(914400, 3657600, 6096000, 741680, "p")}'
+            f'<a:graphic><a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/table"><a:tbl><a:tblGrid>'
+            + "".join('<a:gridCol w="3048000"/>' for _ in rows[0])
+            + f'</a:tblGrid>{body}</a:tbl></a:graphicData></a:graphic></p:graphicFrame>')
+
+
+def chart_frame(shape_id, name, rel):
+    return (f'<p:graphicFrame><p:nvGraphicFramePr><p:cNvPr id="{shape_id}" name="{escape(name)}"/><p:cNvGraphicFramePr/><p:nvPr/></p:nvGraphicFramePr>'
+            f'{xfrm(6400800, 1828800, 4572000, 2743200, "p")}'
+            f'<a:graphic><a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/chart">'
+            f'<c:chart xmlns:c="http://schemas.openxmlformats.org/drawingml/2006/chart" r:id="{rel}"/></a:graphicData></a:graphic></p:graphicFrame>')
+
+
+def chart_xml(title, categories, series):
+    def points(values, cache):
+        return (f'<c:{cache}><c:ptCount val="{len(values)}"/>'
+                + "".join(f'<c:pt idx="{i}"><c:v>{escape(str(v))}</c:v></c:pt>' for i, v in enumerate(values))
+                + f'</c:{cache}>')
+    sers = "".join(
+        f'<c:ser><c:idx val="{i}"/><c:order val="{i}"/><c:tx><c:strRef><c:f>Sheet1!$B$1</c:f>{points([name], "strCache")}</c:strRef></c:tx>'
+        f'<c:cat><c:strRef><c:f>Sheet1!$A$2</c:f>{points(categories, "strCache")}</c:strRef></c:cat>'
+        f'<c:val><c:numRef><c:f>Sheet1!$B$2</c:f>{points(values, "numCache")}</c:numRef></c:val></c:ser>'
+        for i, (name, values) in enumerate(series))
+    return ('<?xml version="1.0" encoding="UTF-8" standalone="yes"?>'
+            '<c:chartSpace xmlns:c="http://schemas.openxmlformats.org/drawingml/2006/chart" '
+            'xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main">'
+            f'<c:chart><c:title><c:tx><c:rich><a:bodyPr/><a:p><a:r><a:t>{escape(title)}</a:t></a:r></a:p></c:rich></c:tx></c:title>'
+            f'<c:plotArea><c:layout/><c:barChart><c:barDir val="col"/><c:grouping val="clustered"/>{sers}</c:barChart></c:plotArea>'
+            '</c:chart></c:chartSpace>')
+
+
+def connector(shape_id, name):
+    return (f'<p:cxnSp><p:nvCxnSpPr><p:cNvPr id="{shape_id}" name="{escape(name)}"/><p:cNvCxnSpPr/><p:nvPr/></p:nvCxnSpPr>'
+            f'<p:spPr>{xfrm(0, 6400800, 12192000, 0)}</p:spPr></p:cxnSp>')
+
+
+def empty_placeholder(shape_id, name):
+    return (f'<p:sp><p:nvSpPr><p:cNvPr id="{shape_id}" name="{escape(name)}"/><p:cNvSpPr/><p:nvPr><p:ph idx="1"/></p:nvPr></p:nvSpPr>'
+            f'<p:spPr/></p:sp>')
+
+
+def placeholder(shape_id, name, kind, paragraphs, geometry=None, idx=None):
+    """A placeholder as masters and layouts hold it; without geometry it inherits its position"""
+    idx_attr = f' idx="{idx}"' if idx is not None else ""
+    sp_pr = f'<p:spPr>{xfrm(*geometry)}</p:spPr>' if geometry else '<p:spPr/>'
+    paras = "".join(p if p.startswith("<a:p>") else f'<a:p><a:r><a:rPr lang="en-US"/><a:t>{escape(p)}</a:t></a:r></a:p>' for p in paragraphs)
+    return (f'<p:sp><p:nvSpPr><p:cNvPr id="{shape_id}" name="{escape(name)}"/><p:cNvSpPr/><p:nvPr><p:ph type="{kind}"{idx_attr}/></p:nvPr></p:nvSpPr>'
+            f'{sp_pr}<p:txBody><a:bodyPr/><a:lstStyle/>{paras}</p:txBody></p:sp>')
+
+
+def slide_xml(shapes):
+    return (f'<?xml version="1.0" encoding="UTF-8" standalone="yes"?>'
+            f'<p:sld {NS}><p:cSld><p:spTree><p:nvGrpSpPr><p:cNvPr id="1" name=""/><p:cNvGrpSpPr/><p:nvPr/></p:nvGrpSpPr>'
+            f'<p:grpSpPr/>{"".join(shapes)}</p:spTree></p:cSld></p:sld>')
+
+
+def rels(entries):
+    body = "".join(f'<Relationship Id="{rid}" Type="{R_TYPE}/{kind}" Target="{target}"/>' for rid, kind, target in entries)
+    return f'<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="{REL_NS}">{body}</Relationships>'
+
+
+def build(path, slides, title="Fixture Deck", parts=None, master_shapes=(), layout_shapes=(), tx_styles="",
+          theme_elements="<a:themeElements/>", master_parts=()):
+    """parts maps slide numbers to extra (rel id, rel type, part name, content type, data) entries;
+    master_parts holds the same for the slide master"""
+    count = len(slides)
+    parts = parts or {}
+    extra = [entry for entries in parts.values() for entry in entries] + list(master_parts)
+    content_types = (
+        '<?xml version="1.0" encoding="UTF-8" standalone="yes"?>'
+        '<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">'
+        '<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>'
+        '<Default Extension="xml" ContentType="application/xml"/>'
+        '<Default Extension="png" ContentType="image/png"/>'
+        '<Override PartName="/ppt/presentation.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.presentation.main+xml"/>'
+        '<Override PartName="/ppt/slideMasters/slideMaster1.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.slideMaster+xml"/>'
+        '<Override PartName="/ppt/slideLayouts/slideLayout1.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.slideLayout+xml"/>'
+        '<Override PartName="/ppt/theme/theme1.xml" ContentType="application/vnd.openxmlformats-officedocument.theme+xml"/>'
+        '<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>'
+        + "".join(f'<Override PartName="/ppt/slides/slide{i}.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.slide+xml"/>' for i in range(1, count + 1))
+        + "".join(f'<Override PartName="/{part}" ContentType="{content_type}"/>' for _, _, part, content_type, _ in extra if content_type)
+        + '</Types>')
+
+    presentation = (
+        f'<?xml version="1.0" encoding="UTF-8" standalone="yes"?><p:presentation {NS}>'
+        '<p:sldMasterIdLst><p:sldMasterId id="2147483648" r:id="rIdMaster"/></p:sldMasterIdLst>'
+        '<p:sldIdLst>' + "".join(f'<p:sldId id="{255 + i}" r:id="rId{i}"/>' for i in range(1, count + 1)) + '</p:sldIdLst>'
+        '<p:sldSz cx="12192000" cy="6858000"/><p:notesSz cx="6858000" cy="9144000"/></p:presentation>')
+
+    presentation_rels = rels(
+        [(f"rId{i}", "slide", f"slides/slide{i}.xml") for i in range(1, count + 1)]
+        + [("rIdMaster", "slideMaster", "slideMasters/slideMaster1.xml"), ("rIdTheme", "theme", "theme/theme1.xml")])
+
+    layout = (f'<?xml version="1.0" encoding="UTF-8" standalone="yes"?><p:sldLayout {NS} type="obj">'
+              '<p:cSld name="Title and Content"><p:spTree><p:nvGrpSpPr><p:cNvPr id="1" name=""/><p:cNvGrpSpPr/><p:nvPr/></p:nvGrpSpPr><p:grpSpPr/>'
+              f'{"".join(layout_shapes)}</p:spTree></p:cSld></p:sldLayout>')
+    master = (f'<?xml version="1.0" encoding="UTF-8" standalone="yes"?><p:sldMaster {NS}>'
+              '<p:cSld><p:spTree><p:nvGrpSpPr><p:cNvPr id="1" name=""/><p:cNvGrpSpPr/><p:nvPr/></p:nvGrpSpPr><p:grpSpPr/>'
+              f'{"".join(master_shapes)}</p:spTree></p:cSld>'
+              f'<p:sldLayoutIdLst><p:sldLayoutId id="2147483649" r:id="rId1"/></p:sldLayoutIdLst>{tx_styles}</p:sldMaster>')
+    theme = ('<?xml version="1.0" encoding="UTF-8" standalone="yes"?>'
+             f'<a:theme xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" name="Fixture">{theme_elements}</a:theme>')
+    core = ('<?xml version="1.0" encoding="UTF-8" standalone="yes"?>'
+            '<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" '
+            'xmlns:dc="http://purl.org/dc/elements/1.1/">'
+            f'<dc:title>{escape(title)}</dc:title><dc:creator>SlidePilot Tests</dc:creator></cp:coreProperties>')
+
+    with zipfile.ZipFile(path, "w", zipfile.ZIP_DEFLATED) as z:
+        z.writestr("[Content_Types].xml", content_types)
+        z.writestr("_rels/.rels", rels([("rId1", "officeDocument", "ppt/presentation.xml"),
+                                         ("rId2", "../package/2006/relationships/metadata/core-properties", "docProps/core.xml")]))
+        z.writestr("docProps/core.xml", core)
+        z.writestr("ppt/presentation.xml", presentation)
+        z.writestr("ppt/_rels/presentation.xml.rels", presentation_rels)
+        z.writestr("ppt/slideLayouts/slideLayout1.xml", layout)
+        z.writestr("ppt/slideLayouts/_rels/slideLayout1.xml.rels", rels([("rId1", "slideMaster", "../slideMasters/slideMaster1.xml")]))
+        z.writestr("ppt/slideMasters/slideMaster1.xml", master)
+        z.writestr("ppt/slideMasters/_rels/slideMaster1.xml.rels", rels([("rId1", "slideLayout", "../slideLayouts/slideLayout1.xml"),
+                                                                         ("rId2", "theme", "../theme/theme1.xml")]
+                                                                        + [(rid, kind, "../" + part[len("ppt/"):]) for rid, kind, part, _, _ in master_parts]))
+        z.writestr("ppt/theme/theme1.xml", theme)
+        for i, shapes in enumerate(slides, start=1):
+            z.writestr(f"ppt/slides/slide{i}.xml", slide_xml(shapes))
+            z.writestr(f"ppt/slides/_rels/slide{i}.xml.rels", rels([("rId1", "slideLayout", "../slideLayouts/slideLayout1.xml")]
+                                                                   + [(rid, kind, "../" + part[len("ppt/"):]) for rid, kind, part, _, _ in parts.get(i, [])]))
+        for _, _, part, _, data in extra:
+            z.writestr(part, data)
+
+
+def build_workbook(path, sheets, shared_strings):
+    """sheets holds (name, rows) pairs; each row is a list of (column letter, cell type, style, value)
+    cells, with None standing for a row that isn't written"""
+    ns = 'xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"'
+
+    def cell(ref, kind, style, value):
+        attrs = f' r="{ref}"' + (f' t="{kind}"' if kind else "") + (f' s="{style}"' if style else "")
+        if kind == "inlineStr":
+            return f'<c{attrs}><is><t>{escape(value)}</t></is></c>'
+        return f'<c{attrs}><v>{escape(str(value))}</v></c>'
+
+    def sheet_xml(rows):
+        body = "".join(
+            f'<row r="{i}">' + "".join(cell(f"{col}{i}", kind, style, value) for col, kind, style, value in row) + '</row>'
+            for i, row in enumerate(rows, start=1) if row is not None)
+        return f'<?xml version="1.0" encoding="UTF-8" standalone="yes"?><worksheet {ns}><sheetData>{body}</sheetData></worksheet>'
+
+    content_types = (
+        '<?xml version="1.0" encoding="UTF-8" standalone="yes"?>'
+        '<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">'
+        '<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>'
+        '<Default Extension="xml" ContentType="application/xml"/>'
+        '<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>'
+        + "".join(f'<Override PartName="/xl/worksheets/sheet{i}.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>'
+                  for i in range(1, len(sheets) + 1))
+        + '<Override PartName="/xl/sharedStrings.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sharedStrings+xml"/>'
+        '<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>'
+        '</Types>')
+    workbook = (f'<?xml version="1.0" encoding="UTF-8" standalone="yes"?><workbook {ns} xmlns:r="{R_TYPE}"><sheets>'
+                + "".join(f'<sheet name="{escape(name)}" sheetId="{i}" r:id="rId{i}"/>' for i, (name, _) in enumerate(sheets, start=1))
+                + '</sheets></workbook>')
+    # Style 1 is a built-in percentage, style 2 a custom date format
+    styles = (f'<?xml version="1.0" encoding="UTF-8" standalone="yes"?><styleSheet {ns}>'
+              '<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy\\-mm\\-dd"/></numFmts>'
+              '<cellXfs count="3"><xf numFmtId="0"/><xf numFmtId="9"/><xf numFmtId="164"/></cellXfs></styleSheet>')
+    strings = (f'<?xml version="1.0" encoding="UTF-8" standalone="yes"?><sst {ns}>'
+               + "".join(f'<si><t>{escape(text)}</t></si>' for text in shared_strings) + '</sst>')
+
+    with zipfile.ZipFile(path, "w", zipfile.ZIP_DEFLATED) as z:
+        z.writestr("[Content_Types].xml", content_types)
+        z.writestr("_rels/.rels", rels([("rId1", "officeDocument", "xl/workbook.xml")]))
+        z.writestr("xl/workbook.xml", workbook)
+        z.writestr("xl/_rels/workbook.xml.rels", rels(
+            [(f"rId{i}", "worksheet", f"worksheets/sheet{i}.xml") for i in range(1, len(sheets) + 1)]
+            + [("rIdStrings", "sharedStrings", "sharedStrings.xml"), ("rIdStyles", "styles", "styles.xml")]))
+        z.writestr("xl/sharedStrings.xml", strings)
+        z.writestr("xl/styles.xml", styles)
+        for i, (_, rows) in enumerate(sheets, start=1):
+            z.writestr(f"xl/worksheets/sheet{i}.xml", sheet_xml(rows))
+
+
+# A 1x1 transparent PNG
+TRANSPARENT_PNG = bytes.fromhex("89504e470d0a1a0a0000000d4948445200000001000000010806000000"
+                                "1f15c4890000000d49444154789c63000100000500010d0a2db40000000049454e44ae426082")
+
+
+def build_template(path):
+    def layout_xml(kind, name):
+        return (f'<?xml version="1.0" encoding="UTF-8" standalone="yes"?><p:sldLayout {NS} type="{kind}">'
+                f'<p:cSld name="{escape(name)}"><p:spTree><p:nvGrpSpPr><p:cNvPr id="1" name=""/><p:cNvGrpSpPr/><p:nvPr/></p:nvGrpSpPr><p:grpSpPr/>'
+                f'{text_shape(2, "Title 1", [], "title")}</p:spTree></p:cSld></p:sldLayout>')
+
+    content_types = (
+        '<?xml version="1.0" encoding="UTF-8" standalone="yes"?>'
+        '<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">'
+        '<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>'
+        '<Default Extension="xml" ContentType="application/xml"/>'
+        '<Default Extension="png" ContentType="image/png"/>'
+        '<Override PartName="/ppt/presentation.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.template.main+xml"/>'
+        '<Override PartName="/ppt/slideMasters/slideMaster1.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.slideMaster+xml"/>'
+        + "".join(f'<Override PartName="/ppt/slideLayouts/slideLayout{i}.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.slideLayout+xml"/>' for i in (1, 2))
+        + '<Override PartName="/ppt/theme/theme1.xml" ContentType="application/vnd.openxmlformats-officedocument.theme+xml"/>'
+        '</Types>')
+    presentation = (
+        f'<?xml version="1.0" encoding="UTF-8" standalone="yes"?><p:presentation {NS}>'
+        '<p:sldMasterIdLst><p:sldMasterId id="2147483660" r:id="rId1"/></p:sldMasterIdLst>'
+        '<p:sldSz cx="12192000" cy="6858000"/><p:notesSz cx="6858000" cy="9144000"/></p:presentation>')
+    master = (f'<?xml version="1.0" encoding="UTF-8" standalone="yes"?><p:sldMaster {NS}>'
+              '<p:cSld><p:spTree><p:nvGrpSpPr><p:cNvPr id="1" name=""/><p:cNvGrpSpPr/><p:nvPr/></p:nvGrpSpPr><p:grpSpPr/>'
+              '<p:pic><p:nvPicPr><p:cNvPr id="2" name="Logo"/><p:cNvPicPr/><p:nvPr/></p:nvPicPr>'
+              f'<p:blipFill><a:blip r:embed="rId4"/></p:blipFill><p:spPr>{xfrm(11277600, 6096000, 762000, 609600)}</p:spPr></p:pic>'
+              '</p:spTree></p:cSld>'
+              '<p:sldLayoutIdLst><p:sldLayoutId id="2147483661" r:id="rId1"/><p:sldLayoutId id="2147483662" r:id="rId2"/></p:sldLayoutIdLst></p:sldMaster>')
+    theme = ('<?xml version="1.0" encoding="UTF-8" standalone="yes"?>'
+             '<a:theme xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" name="Corporate"><a:themeElements/></a:theme>')
+    logo = TRANSPARENT_PNG
+
+    with zipfile.ZipFile(path, "w", zipfile.ZIP_DEFLATED) as z:
+        z.writestr("[Content_Types].xml", content_types)
+        z.writestr("_rels/.rels", rels([("rId1", "officeDocument", "ppt/presentation.xml")]))
+        z.writestr("ppt/presentation.xml", presentation)
+        z.writestr("ppt/_rels/presentation.xml.rels", rels([("rId1", "slideMaster", "slideMasters/slideMaster1.xml"),
+                                                            ("rId2", "theme", "theme/theme1.xml")]))
+        z.writestr("ppt/slideMasters/slideMaster1.xml", master)
+        z.writestr("ppt/slideMasters/_rels/slideMaster1.xml.rels", rels([("rId1", "slideLayout", "../slideLayouts/slideLayout1.xml"),
+                                                                         ("rId2", "slideLayout", "../slideLayouts/slideLayout2.xml"),
+                                                                         ("rId3", "theme", "../theme/theme1.xml"),
+                                                                         ("rId4", "image", "../media/image1.png")]))
+        for i, (kind, name) in enumerate([("title", "Title Slide"), ("obj", "Title and Content")], start=1):
+            z.writestr(f"ppt/slideLayouts/slideLayout{i}.xml", layout_xml(kind, name))
+            z.writestr(f"ppt/slideLayouts/_rels/slideLayout{i}.xml.rels", rels([("rId1", "slideMaster", "../slideMasters/slideMaster1.xml")]))
+        z.writestr("ppt/theme/theme1.xml", theme)
+        z.writestr("ppt/media/image1.png", logo)
+
+
+if __name__ == "__main__":
+    build("two_slides.pptx", [
+        [text_shape(2, "Title 1", ["Quarterly Review"], "title"),
+         text_shape(3, "Content Placeholder 2", ["Revenue up 12%", "Churn down 3%", "Two new regions"], "body")],
+        [text_shape(2, "Title 1", ["Next Steps"], "title"),
+         text_shape(3, "TextBox 3", ["Questions? Contact the team."])],
+    ])
+    build("mixed_shapes.pptx", [
+        [text_shape(2, "Title 1", ["Regional Results"], "title"),
+         picture(3, "Picture 2", "rId2", "Map of the regions"),
+         group(4, "Group 3", [text_shape(5, "TextBox 4", ["North"]), text_shape(6, "TextBox 5", ["South"])]),
+         table(7, "Table 6", [["Region", "Revenue"], ["North", "$1.2M"]]),
+         connector(8, "Straight Connector 7"),
+         empty_placeholder(9, "Content Placeholder 8"),
+         picture(10, "Picture 2"),
+         chart_frame(11, "Chart 10", "rId3")],
+    ], parts={1: [
+        ("rId2", "image", "ppt/media/image1.png", None, TRANSPARENT_PNG),
+        ("rId3", "chart", "ppt/charts/chart1.xml", "application/vnd.openxmlformats-officedocument.drawingml.chart+xml",
+         chart_xml("Revenue", ["North", "South"], [("2024", [1.2, 0.8]), ("2025", [1.5, 1.1])])),
+    ]})
+    build_template("template.potx")
+    build("master_shapes.pptx", [
+        [text_shape(2, "Title 1", ["Quarterly Review"], "title"),
+         text_shape(3, "Footer 2", ["Acme Corp"], "ftr")],
+        [text_shape(2, "Title 1", ["Next Steps"], "title")],
+    ], master_shapes=[
+        placeholder(2, "Title Placeholder 1", "title", ["Click to edit Master title style"], (838200, 365125, 10515600, 1325563)),
+        placeholder(3, "Text Placeholder 2", "body", ["Click to edit Master text styles", "Second level"], (838200, 1825625, 10515600, 4351338), idx=1),
+        placeholder(4, "Footer Placeholder 3", "ftr", ["Acme Corp"], (4038600, 6356350, 4114800, 365125), idx=11),
+        placeholder(5, "Slide Number Placeholder 4", "sldNum",
+                    ['<a:p><a:fld id="{B6F15528-21DE-4FAA-801E-634DDDAF4B2B}" type="slidenum"><a:rPr lang="en-US"/><a:t>‹#›</a:t></a:fld></a:p>'],
+                    (8610600, 6356350, 2743200, 365125), idx=12),
+        picture(6, "Logo", "rId3"),
+    ], layout_shapes=[
+        placeholder(2, "Title 1", "title", []),
+        placeholder(3, "Footer Placeholder 2", "ftr", ["Acme Corp"], idx=11),
+    ], tx_styles=(
+        '<p:txStyles>'
+        '<p:titleStyle><a:lvl1pPr algn="l"><a:defRPr sz="4400" kern="1200"><a:solidFill><a:schemeClr val="tx1"/></a:solidFill>'
+        '<a:latin typeface="+mj-lt"/><a:ea typeface="+mj-ea"/></a:defRPr></a:lvl1pPr></p:titleStyle>'
+        '<p:bodyStyle><a:lvl1pPr><a:defRPr sz="2800"><a:latin typeface="+mn-lt"/></a:defRPr></a:lvl1pPr>'
+        '<a:lvl2pPr><a:defRPr sz="2400"/></a:lvl2pPr></p:bodyStyle>'
+        '<p:otherStyle><a:lvl1pPr><a:defRPr sz="1800"/></a:lvl1pPr></p:otherStyle>'
+        '</p:txStyles>'),
+        theme_elements=('<a:themeElements><a:fontScheme name="Office">'
+                        '<a:majorFont><a:latin typeface="Calibri Light"/></a:majorFont>'
+                        '<a:minorFont><a:latin typeface="Calibri"/></a:minorFont></a:fontScheme></a:themeElements>'),
+        master_parts=[("rId3", "image", "ppt/media/image1.png", None, TRANSPARENT_PNG)])
+    shared = ["Region", "Quarter", "Revenue", "Growth", "Updated", "North", "South", "Q3", "Q4", "Figures are in $K"]
+    build_workbook("sales.xlsx", [
+        ("Notes", [[("A", "s", 0, 9)]]),
+        ("Sales", [
+            [("A", "s", 0, 0), ("B", "s", 0, 1), ("C", "s", 0, 2), ("D", "s", 0, 3), ("E", "s", 0, 4)],
+            [("A", "s", 0, 5), ("B", "s", 0, 7), ("C", None, 0, 1200.5), ("D", None, 1, 0.125), ("E", None, 2, 45565)],
+            [("A", "s", 0, 6), ("B", "s", 0, 7), ("C", None, 0, 980), ("D", None, 1, 0.3), ("E", None, 2, 45565)],
+            None,
+            [("A", "s", 0, 5), ("B", "s", 0, 8), ("C", None, 0, 1350), ("D", None, 1, 0.05), ("E", None, 2, 45657)],
+            [("A", "inlineStr", 0, "West"), ("B", "s", 0, 7), ("C", None, 0, "1000.3000000000001"), ("E", None, 2, 45565)],
+        ]),
+    ], shared)
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"slices"
+)
+
+// autofitModes are how a text shape can fit its text: shrink the text, grow the shape, or
+// neither
+var autofitModes = []string{"shrink", "resize", "none"}
+
+// validAutofit reports whether mode is empty (leave the shape as it is) or an autofit mode
+func validAutofit(mode string) bool {
+	return mode == "" || slices.Contains(autofitModes, mode)
+}
+
+// TextOverflow is a shape whose text doesn't fit, as check_text_overflow reports it
+type TextOverflow struct {
+	ShapeIndex  int     `json:"shape_index"`
+	ShapeID     string  `json:"shape_id"`
+	Text        string  `json:"text"`
+	Autofit     string  `json:"autofit"` // resize or none; shrinking shapes never overflow
+	FrameWidth  float64 `json:"frame_width"`
+	FrameHeight float64 `json:"frame_height"`
+	TextWidth   float64 `json:"text_width"`
+	TextHeight  float64 `json:"text_height"`
+	Overflow    float64 `json:"overflow"` // Inches of text past the frame
+	OffSlide    bool    `json:"off_slide"`
+	Message     string  `json:"message"`
+}
+
+// TextOverflowReport is the result of uno_check_text_overflow.py; slides where all text
+// fits are left out
+type TextOverflowReport struct {
+	CheckedSlides int `json:"checked_slides"`
+	OverflowCount int `json:"overflow_count"`
+	Slides        []struct {
+		SlideNumber int            `json:"slide_number"`
+		Shapes      []TextOverflow `json:"shapes"`
+	} `json:"slides"`
+	Message string `json:"message"`
+}
+
+// CheckTextOverflowDefinition defines the check_text_overflow tool
+var CheckTextOverflowDefinition = ToolDefinition{
+	Name: "check_text_overflow",
+	Description: `Find text that doesn't fit its shape: LibreOffice lays the text out as it renders and reports each shape whose text runs past the bottom of its frame (or past its side, for text that doesn't wrap), and frames that grow with their text beyond the edge of the slide.
+
+Each shape comes with the frame's size, the size its text needs and the overflow in inches. Shapes set to shrink text on overflow are never reported, since they scale their text down instead.
+
+Run this after writing or rewriting text, which easily ends up longer than the slide has room for. Fix overflow by shortening the text (the best fix for slides), splitting it over two slides, enlarging the shape with the move_shape edit of apply_edits, or setting autofit 'shrink' with edit_slide_text or set_rich_text. Leave slides empty to check the whole deck.`,
+	InputSchema: CheckTextOverflowInputSchema,
+	Function:    CheckTextOverflow,
+	ReadOnly:    true,
+}
+
+type CheckTextOverflowInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Slides           []int  `json:"slides,omitempty" jsonschema_description:"(Optional) Slide numbers to check (1-based); omit for the whole deck"`
+}
+
+var CheckTextOverflowInputSchema = GenerateSchema[CheckTextOverflowInput]()
+
+func CheckTextOverflow(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	overflowInput := CheckTextOverflowInput{}
+	if err := json.Unmarshal(input, &overflowInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if overflowInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			overflowInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+	if _, err := os.Stat(overflowInput.PresentationPath); os.IsNotExist(err) {
+		return "", NewToolError(ErrCodeFileNotFound, "presentation file not found: %s", overflowInput.PresentationPath)
+	}
+
+	for _, slideNumber := range overflowInput.Slides {
+		if slideNumber < 1 {
+			return "", NewToolError(ErrCodeSlideOutOfRange, "slide numbers must be 1 or greater")
+		}
+	}
+	if overflowInput.Slides == nil {
+		overflowInput.Slides = []int{}
+	}
+	slides, err := json.Marshal(overflowInput.Slides)
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to encode slides: %v", err)
+	}
+
+	// Call Python UNO script
+	output, err := runUnoScript(ctx, app, "uno_check_text_overflow.py", overflowInput.PresentationPath, string(slides))
+	if err != nil {
+		return "", scriptError("failed to check text overflow", err, output)
+	}
+
+	result, err := decodeScriptResult[TextOverflowReport](output)
+	if err != nil {
+		return "", err
+	}
+	return marshalResult(result)
+}
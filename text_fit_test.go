@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestCheckTextOverflowTool(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_check_text_overflow.py", `{"success": true, "checked_slides": 1, "overflow_count": 1, "slides": [{"slide_number": 2, "shapes": [
+		{"shape_index": 1, "shape_id": "Content 2", "text": "A long list", "autofit": "none", "frame_height": 2, "text_height": 3.5, "overflow": 1.5, "off_slide": false}]}]}`)
+
+	output, err := CheckTextOverflow(context.Background(), env.app, json.RawMessage(`{"slides": [2]}`))
+	if err != nil {
+		t.Fatalf("CheckTextOverflow failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_check_text_overflow.py")
+	if len(calls) != 1 || calls[0].Args[1] != "[2]" {
+		t.Fatalf("expected slide 2 to be checked, got %+v", calls)
+	}
+	var report TextOverflowReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.OverflowCount != 1 || report.Slides[0].Shapes[0].Overflow != 1.5 {
+		t.Errorf("expected the overflowing shape, got %s", output)
+	}
+
+	_, err = CheckTextOverflow(context.Background(), env.app, json.RawMessage(`{"slides": [0]}`))
+	if code := toolErrorCode(err); code != ErrCodeSlideOutOfRange {
+		t.Errorf("expected %s, got %s (%v)", ErrCodeSlideOutOfRange, code, err)
+	}
+}
+
+func TestTextToolsPassAutofit(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_edit_slide.py", `{"success": true, "message": "Changed shape 0"}`)
+	env.uno.Respond("uno_set_rich_text.py", `{"success": true}`)
+
+	if _, err := EditSlideText(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "target_type": "shape_index", "target_value": "0", "new_text": "Hello", "autofit": "shrink"}`)); err != nil {
+		t.Fatalf("EditSlideText failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_edit_slide.py")
+	if len(calls) != 1 || len(calls[0].Args) != 7 || calls[0].Args[5] != "" || calls[0].Args[6] != "shrink" {
+		t.Fatalf("expected an empty old_text before autofit, got %+v", calls)
+	}
+
+	if _, err := SetRichText(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "shape_index": 0, "runs": [{"text": "Hi"}], "autofit": "resize"}`)); err != nil {
+		t.Fatalf("SetRichText failed: %v", err)
+	}
+	if calls := env.uno.Calls("uno_set_rich_text.py"); len(calls) != 1 || calls[0].Args[4] != "resize" {
+		t.Fatalf("expected autofit to be passed, got %+v", calls)
+	}
+
+	for _, bad := range []func() error{
+		func() error {
+			_, err := EditSlideText(context.Background(), env.app, json.RawMessage(`{"slide_number": 1, "target_type": "shape_index", "target_value": "0", "new_text": "Hello", "autofit": "fit"}`))
+			return err
+		},
+		func() error {
+			_, err := ApplyEdits(context.Background(), env.app, json.RawMessage(`{"edits": [{"op": "set_text", "slide_number": 1, "shape_index": 0, "text": "Hi", "autofit": "grow"}]}`))
+			return err
+		},
+	} {
+		if code := toolErrorCode(bad()); code != ErrCodeInvalidInput {
+			t.Errorf("expected %s for an unknown autofit mode, got %s", ErrCodeInvalidInput, code)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// confirmDestructiveEnv turns on approval of destructive tool calls at startup
+const confirmDestructiveEnv = "SLIDEPILOT_CONFIRM_DESTRUCTIVE"
+
+// ToolApprovalRequest is the payload of "tool-approval-request" events. The frontend
+// answers with RespondToolApproval(ID, approved).
+type ToolApprovalRequest struct {
+	ID          string          `json:"id"`
+	Tool        string          `json:"tool"`
+	DisplayName string          `json:"display_name"`
+	Input       json.RawMessage `json:"input"`
+}
+
+// ApprovalGate holds destructive tool calls until the user approves or denies them
+type ApprovalGate struct {
+	mu      sync.Mutex
+	enabled bool
+	pending map[string]chan bool // By tool call ID
+}
+
+// NewApprovalGate creates a gate, enabled when SLIDEPILOT_CONFIRM_DESTRUCTIVE is set
+func NewApprovalGate() *ApprovalGate {
+	value := os.Getenv(confirmDestructiveEnv)
+	return &ApprovalGate{
+		enabled: value != "" && value != "0" && value != "false",
+		pending: make(map[string]chan bool),
+	}
+}
+
+// Enabled reports whether destructive tool calls need approval
+func (g *ApprovalGate) Enabled() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.enabled
+}
+
+// SetEnabled turns approval of destructive tool calls on or off
+func (g *ApprovalGate) SetEnabled(enabled bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.enabled = enabled
+}
+
+// Await registers a tool call and blocks until it is answered or ctx ends
+func (g *ApprovalGate) Await(ctx context.Context, id string) (bool, error) {
+	answer := make(chan bool, 1)
+	g.mu.Lock()
+	g.pending[id] = answer
+	g.mu.Unlock()
+	defer func() {
+		g.mu.Lock()
+		delete(g.pending, id)
+		g.mu.Unlock()
+	}()
+
+	select {
+	case approved := <-answer:
+		return approved, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// Respond answers a pending tool call
+func (g *ApprovalGate) Respond(id string, approved bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	answer, ok := g.pending[id]
+	if !ok {
+		return fmt.Errorf("no tool call %s is waiting for approval", id)
+	}
+	answer <- approved
+	delete(g.pending, id)
+	return nil
+}
+
+// needsApproval reports whether a call of a destructive tool actually changes anything;
+// dry runs are previews and go through without asking. Other tools ask when their
+// NeedsApproval hook says the call is destructive.
+func needsApproval(app *App, tool ToolDefinition, input json.RawMessage) bool {
+	if tool.NeedsApproval != nil && tool.NeedsApproval(app, input) {
+		return true
+	}
+	if !tool.Destructive {
+		return false
+	}
+	var options struct {
+		DryRun bool `json:"dry_run"`
+	}
+	json.Unmarshal(input, &options)
+	return !options.DryRun
+}
+
+// awaitApproval asks the frontend to approve a destructive tool call. A denied call
+// returns a USER_DENIED error for the model; a cancelled turn returns CANCELLED.
+func (a *AIAgent) awaitApproval(ctx context.Context, id, name string, input json.RawMessage) error {
+	a.logToFile("TOOL_APPROVAL", fmt.Sprintf("Waiting for approval of %s", name), string(input))
+	if a.app.events != nil {
+		a.app.events.Emit(a.ctx, "tool-approval-request", ToolApprovalRequest{
+			ID:          id,
+			Tool:        name,
+			DisplayName: getToolDisplayName(name),
+			Input:       input,
+		})
+	}
+
+	approved, err := a.app.approvals.Await(ctx, id)
+	if err != nil {
+		return NewToolError(ErrCodeCancelled, "the request was cancelled while %s was waiting for approval", name)
+	}
+	if !approved {
+		a.logToFile("TOOL_APPROVAL", fmt.Sprintf("User denied %s", name), "")
+		return NewToolError(ErrCodeUserDenied, "the user denied this %s call; nothing was changed. Ask the user how to proceed instead of retrying", name)
+	}
+	a.logToFile("TOOL_APPROVAL", fmt.Sprintf("User approved %s", name), "")
+	return nil
+}
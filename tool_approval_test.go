@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// answerApproval waits for the approval request of a tool call and answers it
+func answerApproval(t *testing.T, env *testEnv, id string, approved bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, message := range env.events.Messages("tool-approval-request") {
+			if request := message.(ToolApprovalRequest); request.ID == id {
+				if err := env.app.RespondToolApproval(id, approved); err != nil {
+					t.Errorf("RespondToolApproval failed: %v", err)
+				}
+				return
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Errorf("no approval request for %s", id)
+}
+
+func TestDestructiveToolWaitsForApproval(t *testing.T) {
+	for _, approved := range []bool{true, false} {
+		env := newTestEnv(t)
+		env.loadFixture(t, "two_slides.pptx")
+		env.uno.Respond("uno_delete_slide.py", `{"success": true, "deleted_slide_number": 2, "new_slide_count": 1}`)
+		env.app.SetConfirmDestructive(true)
+
+		go answerApproval(t, env, "toolu_1", approved)
+		result := env.app.aiAgent.executeTool(context.Background(), "toolu_1", "delete_slide", []byte(`{"slide_number": 2}`))
+
+		calls := len(env.uno.Calls("uno_delete_slide.py"))
+		content := result.OfToolResult.Content[0].OfText.Text
+		if approved && (calls != 1 || result.OfToolResult.IsError.Value) {
+			t.Errorf("approved call should run, got %d calls and %s", calls, content)
+		}
+		if !approved && (calls != 0 || !strings.Contains(content, string(ErrCodeUserDenied))) {
+			t.Errorf("denied call should not run, got %d calls and %s", calls, content)
+		}
+	}
+}
+
+func TestSaveAsOverwriteWaitsForApproval(t *testing.T) {
+	env := newTestEnv(t)
+	path := env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_save_as.py", `{"success": true}`)
+	env.app.SetConfirmDestructive(true)
+	existing := filepath.Join(filepath.Dir(path), "final.pptx")
+	os.WriteFile(existing, []byte("keep me"), 0644)
+
+	// A new file is only a copy and needs no approval
+	result := env.app.aiAgent.executeTool(context.Background(), "toolu_1", "save_presentation_as", []byte(`{"output_path": "copy.pptx", "overwrite": true}`))
+	if result.OfToolResult.IsError.Value || len(env.events.Messages("tool-approval-request")) != 0 {
+		t.Fatalf("expected a new copy without approval, got %s", result.OfToolResult.Content[0].OfText.Text)
+	}
+
+	go answerApproval(t, env, "toolu_2", false)
+	result = env.app.aiAgent.executeTool(context.Background(), "toolu_2", "save_presentation_as", []byte(`{"output_path": "final.pptx", "overwrite": true, "open_copy": true}`))
+	content := result.OfToolResult.Content[0].OfText.Text
+	if calls := len(env.uno.Calls("uno_save_as.py")); calls != 1 || !strings.Contains(content, string(ErrCodeUserDenied)) {
+		t.Errorf("expected the denied overwrite not to run, got %d saves and %s", calls, content)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "keep me" || env.app.presentationPath() != path {
+		t.Errorf("expected the existing file kept and %s still open, got %s", path, env.app.presentationPath())
+	}
+}
+
+func TestApprovalSkippedWhenDisabledOrHarmless(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	env.uno.Respond("uno_find_replace.py", `{"success": true, "dry_run": true, "total_replacements": 0, "slides": []}`)
+	env.uno.Respond("uno_edit_slide.py", `{"success": true}`)
+
+	// Dry runs only preview, and non-destructive tools never ask
+	env.app.SetConfirmDestructive(true)
+	env.app.aiAgent.executeTool(context.Background(), "toolu_1", "find_replace_all", []byte(`{"find": "a", "replace": "b", "dry_run": true}`))
+	env.app.aiAgent.executeTool(context.Background(), "toolu_2", "edit_slide_text",
+		[]byte(`{"slide_number": 1, "target_type": "shape_index", "target_value": "0", "new_text": "x"}`))
+
+	// With confirmation off destructive tools run straight away
+	env.app.SetConfirmDestructive(false)
+	env.app.aiAgent.executeTool(context.Background(), "toolu_3", "find_replace_all", []byte(`{"find": "a", "replace": "b"}`))
+
+	if requests := env.events.Messages("tool-approval-request"); len(requests) != 0 {
+		t.Errorf("expected no approval requests, got %v", requests)
+	}
+	if calls := len(env.uno.Calls("uno_find_replace.py")); calls != 2 {
+		t.Errorf("expected both replace calls to run, got %d", calls)
+	}
+}
+
+func TestCancelWhileAwaitingApproval(t *testing.T) {
+	env := newTestEnv(t)
+	env.loadFixture(t, "two_slides.pptx")
+	env.app.SetConfirmDestructive(true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	result := env.app.aiAgent.executeTool(ctx, "toolu_1", "delete_slide", []byte(`{"slide_number": 2}`))
+
+	if content := result.OfToolResult.Content[0].OfText.Text; !strings.Contains(content, string(ErrCodeCancelled)) {
+		t.Errorf("expected a cancelled result, got %s", content)
+	}
+	if err := env.app.RespondToolApproval("toolu_1", true); err == nil {
+		t.Error("a late answer should find no pending call")
+	}
+}
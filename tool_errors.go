@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ToolErrorCode is a machine-readable failure category the agent can branch on
+type ToolErrorCode string
+
+const (
+	ErrCodeInvalidInput         ToolErrorCode = "INVALID_INPUT"
+	ErrCodeNoPresentation       ToolErrorCode = "NO_PRESENTATION_LOADED"
+	ErrCodeFileNotFound         ToolErrorCode = "FILE_NOT_FOUND"
+	ErrCodeFileLocked           ToolErrorCode = "FILE_LOCKED"
+	ErrCodeFileChanged          ToolErrorCode = "FILE_CHANGED_EXTERNALLY"
+	ErrCodeSlideOutOfRange      ToolErrorCode = "SLIDE_OUT_OF_RANGE"
+	ErrCodeShapeNotFound        ToolErrorCode = "SHAPE_NOT_FOUND"
+	ErrCodeTextNotFound         ToolErrorCode = "TEXT_NOT_FOUND"
+	ErrCodeNotEditable          ToolErrorCode = "SHAPE_NOT_EDITABLE"
+	ErrCodeUnoConnection        ToolErrorCode = "UNO_CONNECTION_FAILED"
+	ErrCodeUnoTimeout           ToolErrorCode = "UNO_TIMEOUT"
+	ErrCodeCancelled            ToolErrorCode = "CANCELLED"
+	ErrCodeUserDenied           ToolErrorCode = "USER_DENIED"
+	ErrCodeNothingToUndo        ToolErrorCode = "NOTHING_TO_UNDO"
+	ErrCodeScriptFailed         ToolErrorCode = "SCRIPT_FAILED"
+	ErrCodeExportFailed         ToolErrorCode = "EXPORT_FAILED"
+	ErrCodeIntegrityCheckFailed ToolErrorCode = "INTEGRITY_CHECK_FAILED"
+	ErrCodeBackupFailed         ToolErrorCode = "BACKUP_FAILED"
+	ErrCodeDiskSpaceLow         ToolErrorCode = "DISK_SPACE_LOW"
+	ErrCodeRolledBack           ToolErrorCode = "TRANSACTION_ROLLED_BACK"
+	ErrCodePlanStepFailed       ToolErrorCode = "PLAN_STEP_FAILED"
+	ErrCodeArtifactNotFound     ToolErrorCode = "ARTIFACT_NOT_FOUND"
+	ErrCodeProviderError        ToolErrorCode = "PROVIDER_ERROR"
+	ErrCodeToolNotFound         ToolErrorCode = "TOOL_NOT_FOUND"
+	ErrCodeUnauthorized         ToolErrorCode = "UNAUTHORIZED"
+	ErrCodeInternal             ToolErrorCode = "INTERNAL_ERROR"
+)
+
+// ToolError is an error carrying a machine-readable code and optional structured details
+type ToolError struct {
+	Code    ToolErrorCode
+	Message string
+	Details map[string]interface{}
+}
+
+func (e *ToolError) Error() string {
+	return e.Message
+}
+
+// NewToolError creates a ToolError with a formatted message
+func NewToolError(code ToolErrorCode, format string, args ...interface{}) *ToolError {
+	return &ToolError{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// WithDetail attaches a structured detail to the error
+func (e *ToolError) WithDetail(key string, value interface{}) *ToolError {
+	if e.Details == nil {
+		e.Details = make(map[string]interface{})
+	}
+	e.Details[key] = value
+	return e
+}
+
+// toolErrorCode extracts the code from an error, defaulting to INTERNAL_ERROR
+func toolErrorCode(err error) ToolErrorCode {
+	var toolErr *ToolError
+	if errors.As(err, &toolErr) {
+		return toolErr.Code
+	}
+	return ErrCodeInternal
+}
+
+// toolErrorCodeOr returns the error's own code when it carries one, otherwise fallback
+func toolErrorCodeOr(err error, fallback ToolErrorCode) ToolErrorCode {
+	var toolErr *ToolError
+	if errors.As(err, &toolErr) {
+		return toolErr.Code
+	}
+	return fallback
+}
+
+// toolErrorEnvelope renders an error as the failure form of the ToolResult envelope:
+// {"success": false, "error_code": "...", "error": "...", "details": {...}}
+func toolErrorEnvelope(err error) string {
+	envelope := ToolResult{
+		Success:   false,
+		ErrorCode: toolErrorCode(err),
+		Error:     err.Error(),
+	}
+
+	var toolErr *ToolError
+	if errors.As(err, &toolErr) && len(toolErr.Details) > 0 {
+		envelope.Details = toolErr.Details
+	}
+
+	envelopeJSON, _ := json.Marshal(envelope)
+	return string(envelopeJSON)
+}
+
+// scriptError converts a failed UNO script run into a ToolError. The script's own
+// error_code is used when present, otherwise the code is derived from its message.
+func scriptError(action string, err error, output []byte) *ToolError {
+	var scriptResult struct {
+		Error     string `json:"error"`
+		ErrorCode string `json:"error_code"`
+	}
+
+	message := strings.TrimSpace(string(output))
+	if jsonErr := json.Unmarshal(output, &scriptResult); jsonErr == nil && scriptResult.Error != "" {
+		message = scriptResult.Error
+	}
+
+	code := ToolErrorCode(scriptResult.ErrorCode)
+	if code == "" {
+		code = classifyErrorMessage(message)
+	}
+
+	return NewToolError(code, "%s: %s", action, message).WithDetail("exit_error", err.Error())
+}
+
+// invalidScriptOutput reports a script whose output could not be parsed
+func invalidScriptOutput(err error) *ToolError {
+	return NewToolError(ErrCodeScriptFailed, "invalid JSON output from UNO script: %v", err)
+}
+
+// classifyErrorMessage maps the free-form errors raised by the UNO scripts onto error codes
+func classifyErrorMessage(message string) ToolErrorCode {
+	lower := strings.ToLower(message)
+	switch {
+	case strings.Contains(lower, "could not connect to libreoffice"), strings.Contains(lower, "connection refused"):
+		return ErrCodeUnoConnection
+	case strings.Contains(lower, "timed out"), strings.Contains(lower, "timeout"):
+		return ErrCodeUnoTimeout
+	case strings.Contains(lower, "slide number") && (strings.Contains(lower, "out of range") || strings.Contains(lower, "invalid")):
+		return ErrCodeSlideOutOfRange
+	case strings.Contains(lower, "shape index") || strings.Contains(lower, "no shape of type") || strings.Contains(lower, "no shape with shape_id"):
+		return ErrCodeShapeNotFound
+	case strings.Contains(lower, "does not contain editable text"), strings.Contains(lower, "is not a table"), strings.Contains(lower, "is not a picture"):
+		return ErrCodeNotEditable
+	case strings.Contains(lower, "not found on slide"), strings.Contains(lower, "bullet point"):
+		return ErrCodeTextNotFound
+	case strings.Contains(lower, "errorcodeioexception"), strings.Contains(lower, "locked by another"), strings.Contains(lower, "permission denied"):
+		return ErrCodeFileLocked
+	case strings.Contains(lower, "file not found"), strings.Contains(lower, "no such file"):
+		return ErrCodeFileNotFound
+	case strings.Contains(lower, "unknown target_type"), strings.Contains(lower, "must be an integer"), strings.Contains(lower, "usage:"),
+		strings.Contains(lower, "cell position"), strings.Contains(lower, "invalid regular expression"):
+		return ErrCodeInvalidInput
+	}
+	return ErrCodeScriptFailed
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestToolErrorEnvelope(t *testing.T) {
+	err := fmt.Errorf("while editing: %w", NewToolError(ErrCodeSlideOutOfRange, "slide %d does not exist", 9).WithDetail("slide_count", 2))
+
+	var envelope struct {
+		Success   bool                   `json:"success"`
+		ErrorCode string                 `json:"error_code"`
+		Error     string                 `json:"error"`
+		Details   map[string]interface{} `json:"details"`
+	}
+	if jsonErr := json.Unmarshal([]byte(toolErrorEnvelope(err)), &envelope); jsonErr != nil {
+		t.Fatalf("invalid envelope: %v", jsonErr)
+	}
+	if envelope.Success || envelope.ErrorCode != "SLIDE_OUT_OF_RANGE" || envelope.Details["slide_count"] != float64(2) {
+		t.Errorf("unexpected envelope %+v", envelope)
+	}
+	if code := toolErrorCode(errors.New("plain")); code != ErrCodeInternal {
+		t.Errorf("expected errors without a code to be INTERNAL_ERROR, got %s", code)
+	}
+}
+
+func TestScriptErrorCodes(t *testing.T) {
+	exitErr := errors.New("exit status 1")
+	// The script's own code wins over one derived from its message
+	coded := scriptError("Error editing slide", exitErr, []byte(`{"success": false, "error": "Shape index 7 is out of range", "error_code": "SHAPE_NOT_FOUND"}`))
+	if coded.Code != ErrCodeShapeNotFound || coded.Message != "Error editing slide: Shape index 7 is out of range" {
+		t.Errorf("unexpected error %+v", coded)
+	}
+
+	for message, want := range map[string]ToolErrorCode{
+		"Could not connect to LibreOffice":                   ErrCodeUnoConnection,
+		"Invalid slide number 5. Presentation has 2 slides.": ErrCodeSlideOutOfRange,
+		"Text 'Intro' not found on slide 1":                  ErrCodeTextNotFound,
+		"Usage: uno_edit_slide.py <file>":                    ErrCodeInvalidInput,
+		"something unexpected":                               ErrCodeScriptFailed,
+	} {
+		if got := scriptError("Error", exitErr, []byte(message)).Code; got != want {
+			t.Errorf("%q: expected %s, got %s", message, want, got)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ToolRegistry holds the tools offered to the model, in registration order. Built-in
+// tools are registered by NewAIAgent; plugins and embedders can add or remove tools later.
+type ToolRegistry struct {
+	mu    sync.RWMutex
+	tools []ToolDefinition
+}
+
+// NewToolRegistry creates a registry holding the given tools
+func NewToolRegistry(tools ...ToolDefinition) *ToolRegistry {
+	registry := &ToolRegistry{}
+	for _, tool := range tools {
+		if err := registry.Register(tool); err != nil {
+			panic(err)
+		}
+	}
+	return registry
+}
+
+// builtinTools returns the tools that ship with the app
+func builtinTools() []ToolDefinition {
+	return []ToolDefinition{
+		ListSlidesDefinition,
+		ReadSlideDefinition,
+		OCRSlideDefinition,
+		EditSlideTextDefinition,
+		ExportSlidesDefinition,
+		AddSlideDefinition,
+		DeleteSlideDefinition,
+		MoveSlideDefinition,
+		GenerateImageDefinition,
+		SearchStockImageDefinition,
+		InsertStockImageDefinition,
+		InsertImageDefinition,
+		ReplaceImageDefinition,
+		InsertMediaDefinition,
+		ExtractMediaDefinition,
+		OptimizePresentationDefinition,
+		InsertTableDefinition,
+		EditTableCellDefinition,
+		AddShapeDefinition,
+		DeleteShapeDefinition,
+		GroupShapesDefinition,
+		UngroupShapesDefinition,
+		SetShapeOrderDefinition,
+		AlignShapesDefinition,
+		FindReplaceAllDefinition,
+		TranslatePresentationDefinition,
+		TranslateSlidesDefinition,
+		UndoLastChangeDefinition,
+		SavePresentationAsDefinition,
+		ExportPDFDefinition,
+		GetPresentationInfoDefinition,
+		SetPresentationInfoDefinition,
+		ListLayoutsDefinition,
+		SetSlideLayoutDefinition,
+		ApplyTemplateDefinition,
+		ReadMasterDefinition,
+		EditMasterTextDefinition,
+		EditMasterPlaceholderDefinition,
+		ImportSlidesDefinition,
+		ExtractSlidesDefinition,
+		ListSectionsDefinition,
+		AddSectionDefinition,
+		RenameSectionDefinition,
+		DeleteSectionDefinition,
+		MoveSlidesToSectionDefinition,
+		ListCommentsDefinition,
+		AddCommentDefinition,
+		ResolveCommentDefinition,
+		CheckAccessibilityDefinition,
+		SetAltTextDefinition,
+		SpellcheckPresentationDefinition,
+		LintStyleDefinition,
+		CheckTextOverflowDefinition,
+		GetBrandProfileDefinition,
+		FormatListDefinition,
+		SetRichTextDefinition,
+		AddHyperlinkDefinition,
+		SetTransitionDefinition,
+		SetFooterDefinition,
+		AddAnimationDefinition,
+		InsertChartDefinition,
+		EditChartDataDefinition,
+		ImportDataDefinition,
+		ApplyEditsDefinition,
+		FetchArtifactDefinition,
+		CreatePresentationDefinition,
+		GenerateFromOutlineDefinition,
+	}
+}
+
+// Register adds a tool. Names must be unique, so replacing a tool means unregistering it first.
+func (r *ToolRegistry) Register(tool ToolDefinition) error {
+	if tool.Name == "" {
+		return fmt.Errorf("tool name is required")
+	}
+	if tool.Function == nil {
+		return fmt.Errorf("tool %s has no function", tool.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.indexOf(tool.Name) >= 0 {
+		return fmt.Errorf("tool %s is already registered", tool.Name)
+	}
+	r.tools = append(r.tools, tool)
+	return nil
+}
+
+// Unregister removes a tool, reporting whether it was registered
+func (r *ToolRegistry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	index := r.indexOf(name)
+	if index < 0 {
+		return false
+	}
+	r.tools = slices.Delete(r.tools, index, index+1)
+	return true
+}
+
+// Lookup returns the tool with the given name
+func (r *ToolRegistry) Lookup(name string) (ToolDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if index := r.indexOf(name); index >= 0 {
+		return r.tools[index], true
+	}
+	return ToolDefinition{}, false
+}
+
+// List returns the registered tools in registration order
+func (r *ToolRegistry) List() []ToolDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.tools)
+}
+
+// indexOf returns the position of a tool by name, or -1; the caller holds the lock
+func (r *ToolRegistry) indexOf(name string) int {
+	return slices.IndexFunc(r.tools, func(tool ToolDefinition) bool {
+		return tool.Name == name
+	})
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+)
+
+// ToolResult is the envelope every tool result reaches the model in:
+// {"success": true, "data": {...}} or
+// {"success": false, "error_code": "...", "error": "...", "details": {...}}
+type ToolResult struct {
+	Success   bool                   `json:"success"`
+	ErrorCode ToolErrorCode          `json:"error_code,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	Data      interface{}            `json:"data,omitempty"`
+}
+
+// SlideSummary is one slide in a SlideList; layout and text_shapes are left out for titles_only listings
+type SlideSummary struct {
+	SlideNumber int    `json:"slide_number"`
+	Title       string `json:"title"`
+	Layout      string `json:"layout,omitempty"`
+	TextShapes  *int   `json:"text_shapes,omitempty"`
+	Section     string `json:"section,omitempty"`
+}
+
+// SlideList is the list_slides result: one page of slide summaries
+type SlideList struct {
+	TotalSlides int            `json:"total_slides"`
+	Offset      int            `json:"offset"`
+	Returned    int            `json:"returned"`
+	HasMore     bool           `json:"has_more"`
+	NextOffset  *int           `json:"next_offset,omitempty"`
+	Slides      []SlideSummary `json:"slides"`
+}
+
+// SlideDetail is the read_slide result
+type SlideDetail struct {
+	SlideNumber int              `json:"slide_number"`
+	TotalShapes int              `json:"total_shapes"`
+	Shapes      []slideShapeInfo `json:"shapes"`
+}
+
+// EditResult is the result of a tool that changes a slide's content. The fields every
+// edit reports are typed; anything else the script reports is kept in Details.
+type EditResult struct {
+	Message     string                 `json:"message,omitempty"`
+	SlideNumber int                    `json:"slide_number,omitempty"`
+	ShapeIndex  *int                   `json:"shape_index,omitempty"`
+	ShapeID     string                 `json:"shape_id,omitempty"`
+	Details     map[string]interface{} `json:"details,omitempty"`
+}
+
+// ExportResult is the result of export_slides and export_pdf
+type ExportResult struct {
+	Format        string   `json:"format"`
+	OutputDir     string   `json:"output_dir,omitempty"`
+	OutputPath    string   `json:"output_path,omitempty"`
+	Slides        []string `json:"slides,omitempty"`
+	SlideCount    int      `json:"slide_count,omitempty"`
+	DPI           int      `json:"dpi,omitempty"`
+	Layout        string   `json:"layout,omitempty"`
+	FirstSlide    int      `json:"first_slide,omitempty"`
+	LastSlide     int      `json:"last_slide,omitempty"`
+	SlidesPerPage int      `json:"slides_per_page,omitempty"`
+	Pages         int      `json:"pages,omitempty"`
+}
+
+// marshalResult renders a typed tool result as the string tools return
+func marshalResult(result interface{}) (string, error) {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to encode result: %v", err)
+	}
+	return string(resultJSON), nil
+}
+
+// decodeScriptResult parses a UNO script's JSON output into a typed result
+func decodeScriptResult[T any](output []byte) (T, error) {
+	var result T
+	if err := json.Unmarshal(output, &result); err != nil {
+		return result, invalidScriptOutput(err)
+	}
+	return result, nil
+}
+
+// editResultFromScript turns the output of an editing script into an EditResult
+func editResultFromScript(output []byte) (*EditResult, error) {
+	fields, err := decodeScriptResult[map[string]interface{}](output)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &EditResult{}
+	if err := json.Unmarshal(output, result); err != nil {
+		return nil, invalidScriptOutput(err)
+	}
+	for _, key := range []string{"success", "message", "slide_number", "shape_index", "shape_id"} {
+		delete(fields, key)
+	}
+	if len(fields) > 0 {
+		result.Details = fields
+	}
+	return result, nil
+}
+
+// normalizeToolResult wraps a successful tool result in the ToolResult envelope. Results
+// that already report failure are passed on as they are.
+func normalizeToolResult(result string) string {
+	var data interface{} = json.RawMessage(result)
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(result), &fields); err == nil {
+		if success, ok := fields["success"].(bool); ok {
+			if !success {
+				return result
+			}
+			// Older script output carries its own success flag
+			delete(fields, "success")
+			data = fields
+		}
+	} else if !json.Valid([]byte(result)) {
+		data = result
+	}
+
+	envelope, err := json.Marshal(ToolResult{Success: true, Data: data})
+	if err != nil {
+		return result
+	}
+	return string(envelope)
+}
@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestNormalizeToolResult(t *testing.T) {
+	for result, want := range map[string]string{
+		`{"slides": 2}`:                  `{"success":true,"data":{"slides":2}}`,
+		`{"success": true, "slides": 2}`: `{"success":true,"data":{"slides":2}}`,
+		"plain text":                     `{"success":true,"data":"plain text"}`,
+	} {
+		if got := normalizeToolResult(result); got != want {
+			t.Errorf("normalizeToolResult(%s) = %s, expected %s", result, got, want)
+		}
+	}
+	failure := `{"success": false, "error": "x"}`
+	if got := normalizeToolResult(failure); got != failure {
+		t.Errorf("expected a failure passed on unchanged, got %s", got)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TransactionStep records one mutating tool call applied during a turn
+type TransactionStep struct {
+	Number int
+	Tool   string
+	Path   string
+}
+
+// EditTransaction groups the mutating tool calls of a single agent turn so the
+// whole turn can be rolled back if any step fails
+type EditTransaction struct {
+	snapshots map[string]*PresentationBackup // Snapshot per file, taken before its first mutation
+	steps     []TransactionStep
+}
+
+// NewEditTransaction starts an empty transaction for a new turn
+func NewEditTransaction() *EditTransaction {
+	return &EditTransaction{
+		snapshots: make(map[string]*PresentationBackup),
+	}
+}
+
+// Begin snapshots the file the first time the turn is about to modify it; any name for
+// the same file shares one snapshot
+func (t *EditTransaction) Begin(path string) error {
+	path = absPresentationPath(path)
+	if _, exists := t.snapshots[path]; exists {
+		return nil
+	}
+
+	snapshot, err := BackupPresentation(path)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot presentation for transaction: %w", err)
+	}
+	t.snapshots[path] = snapshot
+	return nil
+}
+
+// RecordStep adds a mutating tool call to the transaction and returns its step number
+func (t *EditTransaction) RecordStep(tool, path string) int {
+	step := TransactionStep{
+		Number: len(t.steps) + 1,
+		Tool:   tool,
+		Path:   path,
+	}
+	t.steps = append(t.steps, step)
+	return step.Number
+}
+
+// Steps returns the steps recorded so far
+func (t *EditTransaction) Steps() []TransactionStep {
+	return t.steps
+}
+
+// Verify checks that every file touched by the turn is still present, non-empty, and a valid package
+func (t *EditTransaction) Verify() error {
+	for path := range t.snapshots {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("presentation %s is missing after edits: %v", path, err)
+		}
+		if info.Size() == 0 {
+			return fmt.Errorf("presentation %s is empty after edits", path)
+		}
+		if isOOXMLPackage(path) {
+			if _, err := verifyPackage(path); err != nil {
+				return fmt.Errorf("presentation %s is corrupt after edits: %v", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Rollback restores every touched file to its state at the start of the turn
+func (t *EditTransaction) Rollback() error {
+	var failures []string
+	for path, snapshot := range t.snapshots {
+		if err := snapshot.Restore(); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", path, err))
+		}
+	}
+	t.discard()
+
+	if len(failures) > 0 {
+		return fmt.Errorf("transaction rollback failed for %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// Commit keeps all changes and drops the snapshots
+func (t *EditTransaction) Commit() {
+	t.discard()
+}
+
+// Paths returns the files touched by the transaction
+func (t *EditTransaction) Paths() []string {
+	paths := make([]string, 0, len(t.snapshots))
+	for path := range t.snapshots {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func (t *EditTransaction) discard() {
+	for _, snapshot := range t.snapshots {
+		snapshot.Discard()
+	}
+	t.snapshots = make(map[string]*PresentationBackup)
+	t.steps = nil
+}
+
+// describeRollback builds the report sent to the model and UI after a turn is rolled back
+func describeRollback(failedStep TransactionStep, cause error, applied []TransactionStep) string {
+	var report strings.Builder
+	fmt.Fprintf(&report, "Transaction rolled back: step %d (%s) failed: %v\n", failedStep.Number, failedStep.Tool, cause)
+
+	if len(applied) == 0 {
+		report.WriteString("No earlier edits from this turn needed to be reverted.")
+	} else {
+		names := make([]string, len(applied))
+		for i, step := range applied {
+			names[i] = fmt.Sprintf("%d (%s)", step.Number, step.Tool)
+		}
+		fmt.Fprintf(&report, "Reverted the %d earlier edit(s) from this turn: steps %s. The presentation is back to its state before this request.",
+			len(applied), strings.Join(names, ", "))
+	}
+
+	return report.String()
+}
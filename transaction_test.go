@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEditTransactionRollsBackToTurnStart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deck.odp")
+	os.WriteFile(path, []byte("original"), 0644)
+
+	transaction := NewEditTransaction()
+	for i, content := range []string{"first edit", "second edit"} {
+		// Only the first Begin snapshots; later ones must not capture the edited file
+		if err := transaction.Begin(path); err != nil {
+			t.Fatalf("Begin failed: %v", err)
+		}
+		os.WriteFile(path, []byte(content), 0644)
+		if number := transaction.RecordStep("edit_slide_text", path); number != i+1 {
+			t.Errorf("expected step %d, got %d", i+1, number)
+		}
+	}
+	if err := transaction.Verify(); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+
+	if err := transaction.Rollback(); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "original" {
+		t.Errorf("expected the file as it was before the turn, got %q", data)
+	}
+	if len(transaction.Steps()) != 0 || len(transaction.Paths()) != 0 {
+		t.Error("expected the rollback to clear the transaction")
+	}
+}
+
+func TestEditTransactionVerifyCatchesEmptyFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deck.odp")
+	os.WriteFile(path, []byte("original"), 0644)
+
+	transaction := NewEditTransaction()
+	transaction.Begin(path)
+	defer transaction.Commit()
+	os.WriteFile(path, nil, 0644)
+	if err := transaction.Verify(); err == nil || !strings.Contains(err.Error(), "empty") {
+		t.Errorf("expected an empty file to fail verification, got %v", err)
+	}
+	os.Remove(path)
+	if err := transaction.Verify(); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected a deleted file to fail verification, got %v", err)
+	}
+}
+
+func TestDescribeRollback(t *testing.T) {
+	applied := []TransactionStep{{Number: 1, Tool: "edit_slide_text"}}
+	report := describeRollback(TransactionStep{Number: 2, Tool: "delete_slide"}, os.ErrNotExist, applied)
+	if !strings.Contains(report, "step 2 (delete_slide) failed") || !strings.Contains(report, "steps 1 (edit_slide_text)") {
+		t.Errorf("expected the failed and reverted steps named, got %q", report)
+	}
+	if report := describeRollback(TransactionStep{Number: 1, Tool: "delete_slide"}, os.ErrNotExist, nil); !strings.Contains(report, "No earlier edits") {
+		t.Errorf("expected a report without reverted edits, got %q", report)
+	}
+}
@@ -0,0 +1,534 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// translationBatchSize limits how many text elements are sent per translation request
+const translationBatchSize = 40
+
+// Translator translates a batch of texts, returning results in the same order
+type Translator interface {
+	Name() string
+	Translate(ctx context.Context, texts []string, targetLanguage, sourceLanguage string) ([]string, error)
+}
+
+// NewTranslatorFromEnv picks the translation backend from SLIDEPILOT_TRANSLATION_PROVIDER.
+// The default "llm" provider reuses the agent's Anthropic client; "deepl" uses DEEPL_API_KEY.
+func NewTranslatorFromEnv(app *App) (Translator, error) {
+	provider := strings.ToLower(os.Getenv("SLIDEPILOT_TRANSLATION_PROVIDER"))
+	if provider == "" {
+		provider = "llm"
+	}
+
+	switch provider {
+	case "llm":
+		if app == nil || app.aiAgent == nil {
+			return nil, fmt.Errorf("LLM translation requires the AI agent to be initialized")
+		}
+		return &LLMTranslator{llm: app.aiAgent.llm, model: app.aiAgent.Settings().Model, recordUsage: app.recordUsage}, nil
+	case "deepl":
+		apiKey := os.Getenv("DEEPL_API_KEY")
+		if apiKey == "" {
+			return nil, fmt.Errorf("DeepL translation requires DEEPL_API_KEY to be set")
+		}
+		// Free-tier keys end in ":fx" and use a separate endpoint
+		endpoint := "https://api.deepl.com/v2/translate"
+		if strings.HasSuffix(apiKey, ":fx") {
+			endpoint = "https://api-free.deepl.com/v2/translate"
+		}
+		return &DeepLTranslator{
+			endpoint: endpoint,
+			apiKey:   apiKey,
+			client:   &http.Client{Timeout: time.Minute},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown translation provider: %s", provider)
+	}
+}
+
+// LLMTranslator translates text with a dedicated Claude request per batch
+type LLMTranslator struct {
+	llm         LLMClient
+	model       string                                                                // The chat model from the user's settings
+	recordUsage func(ctx context.Context, purpose string, message *anthropic.Message) // Optional
+}
+
+func (t *LLMTranslator) Name() string {
+	return "llm"
+}
+
+func (t *LLMTranslator) Translate(ctx context.Context, texts []string, targetLanguage, sourceLanguage string) ([]string, error) {
+	source := "the source language (detect it)"
+	if sourceLanguage != "" {
+		source = sourceLanguage
+	}
+
+	// Run tags go to the model as written rather than as \u003c escapes
+	var textsJSON bytes.Buffer
+	encoder := json.NewEncoder(&textsJSON)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(texts); err != nil {
+		return nil, fmt.Errorf("failed to encode texts: %v", err)
+	}
+
+	prompt := fmt.Sprintf(`Translate each string in the following JSON array from %s into %s.
+
+Rules:
+- Respond with ONLY a JSON array of strings, with exactly %d items in the same order.
+- Preserve line breaks, numbers, product names, and placeholders exactly.
+- If a string should not be translated (code, URLs, proper nouns only), return it unchanged.
+- Keep markup tags such as <g1>...</g1> around the words they mark (they carry formatting), and keep &amp;, &lt; and &gt; as they are.
+
+%s`, source, targetLanguage, len(texts), strings.TrimSpace(textsJSON.String()))
+
+	message, err := t.llm.CreateMessage(ctx, anthropic.MessageNewParams{
+		Model:     anthropic.Model(t.model),
+		MaxTokens: int64(8192),
+		Messages: []anthropic.MessageParam{
+			anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("translation request failed: %v", err)
+	}
+	if t.recordUsage != nil {
+		t.recordUsage(ctx, "translation", message)
+	}
+
+	var responseText strings.Builder
+	for _, content := range message.Content {
+		if content.Type == "text" {
+			responseText.WriteString(content.Text)
+		}
+	}
+
+	// Tolerate surrounding prose or code fences around the JSON array
+	raw := responseText.String()
+	start := strings.Index(raw, "[")
+	end := strings.LastIndex(raw, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("translation response did not contain a JSON array")
+	}
+
+	var translated []string
+	if err := json.Unmarshal([]byte(raw[start:end+1]), &translated); err != nil {
+		return nil, fmt.Errorf("invalid translation response: %v", err)
+	}
+	if len(translated) != len(texts) {
+		return nil, fmt.Errorf("translation returned %d items, expected %d", len(translated), len(texts))
+	}
+
+	return translated, nil
+}
+
+// DeepLTranslator translates text with the DeepL API
+type DeepLTranslator struct {
+	endpoint    string
+	apiKey      string
+	client      *http.Client
+	tagHandling string // "xml" when the texts carry markup tags
+}
+
+func (t *DeepLTranslator) Name() string {
+	return "deepl"
+}
+
+func (t *DeepLTranslator) Translate(ctx context.Context, texts []string, targetLanguage, sourceLanguage string) ([]string, error) {
+	form := url.Values{}
+	for _, text := range texts {
+		form.Add("text", text)
+	}
+	form.Set("target_lang", strings.ToUpper(targetLanguage))
+	if sourceLanguage != "" {
+		form.Set("source_lang", strings.ToUpper(sourceLanguage))
+	}
+	form.Set("preserve_formatting", "1")
+	if t.tagHandling != "" {
+		form.Set("tag_handling", t.tagHandling)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", t.endpoint, bytes.NewBufferString(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create translation request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+t.apiKey)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("translation request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read translation response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DeepL returned %s: %s", resp.Status, string(body))
+	}
+
+	var result struct {
+		Translations []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("invalid DeepL response: %v", err)
+	}
+	if len(result.Translations) != len(texts) {
+		return nil, fmt.Errorf("DeepL returned %d translations, expected %d", len(result.Translations), len(texts))
+	}
+
+	translated := make([]string, len(result.Translations))
+	for i, translation := range result.Translations {
+		translated[i] = translation.Text
+	}
+	return translated, nil
+}
+
+// TextElement is a translatable piece of text reported by the UNO translate script
+type TextElement struct {
+	ID          string `json:"id"`
+	SlideNumber int    `json:"slide_number"`
+	Kind        string `json:"kind"`
+	Text        string `json:"text"`
+}
+
+// UntranslatableElement describes content that could not be translated
+type UntranslatableElement struct {
+	SlideNumber int    `json:"slide_number"`
+	Element     string `json:"element"`
+	Reason      string `json:"reason"`
+}
+
+// translateElements runs texts through the translator in batches, keyed by element ID.
+// Batches that fail are reported as untranslatable instead of aborting the whole deck.
+func translateElements(ctx context.Context, translator Translator, elements []TextElement, targetLanguage, sourceLanguage string) (map[string]string, []UntranslatableElement) {
+	translations := make(map[string]string)
+	var failed []UntranslatableElement
+
+	for start := 0; start < len(elements); start += translationBatchSize {
+		end := start + translationBatchSize
+		if end > len(elements) {
+			end = len(elements)
+		}
+		batch := elements[start:end]
+
+		texts := make([]string, len(batch))
+		for i, element := range batch {
+			texts[i] = element.Text
+		}
+
+		fmt.Printf("Translating elements %d-%d of %d with %s\n", start+1, end, len(elements), translator.Name())
+		translated, err := translator.Translate(ctx, texts, targetLanguage, sourceLanguage)
+		if err != nil {
+			for _, element := range batch {
+				failed = append(failed, UntranslatableElement{
+					SlideNumber: element.SlideNumber,
+					Element:     element.ID,
+					Reason:      fmt.Sprintf("translation failed: %v", err),
+				})
+			}
+			continue
+		}
+
+		for i, element := range batch {
+			if strings.TrimSpace(translated[i]) == "" {
+				failed = append(failed, UntranslatableElement{
+					SlideNumber: element.SlideNumber,
+					Element:     element.ID,
+					Reason:      "translation returned empty text",
+				})
+				continue
+			}
+			translations[element.ID] = translated[i]
+		}
+	}
+
+	return translations, failed
+}
+
+// TextParagraph is a paragraph of a text shape, table cell or notes field reported by the
+// UNO translate script, split into runs of uniform character formatting
+type TextParagraph struct {
+	ID          string   `json:"id"`
+	SlideNumber int      `json:"slide_number"`
+	Kind        string   `json:"kind"`
+	Runs        []string `json:"runs"`
+	FixedOrder  bool     `json:"fixed_order"` // Holds fields, so runs are translated one by one in place
+}
+
+// runSegment is translated text carrying the formatting of one run of its paragraph
+type runSegment struct {
+	Run  int    `json:"run"`
+	Text string `json:"text"`
+}
+
+// runTagPattern matches the <gN> and </gN> tags marking the runs of a paragraph
+var runTagPattern = regexp.MustCompile(`</?g([0-9]+)>`)
+
+var (
+	markupEscaper   = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	markupUnescaper = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+)
+
+// runMarkup writes a paragraph's runs as one text for the translator, wrapping each run in
+// <gN> tags (N 1-based) when there is more than one so the formatting can follow the words
+// it marks. Texts are XML-escaped either way.
+func runMarkup(runs []string) string {
+	if len(runs) == 1 {
+		return markupEscaper.Replace(runs[0])
+	}
+	var markup strings.Builder
+	for i, run := range runs {
+		fmt.Fprintf(&markup, "<g%d>%s</g%d>", i+1, markupEscaper.Replace(run), i+1)
+	}
+	return markup.String()
+}
+
+// parseRunMarkup splits a translated paragraph back into segments by run. Text outside the
+// tags joins the segment before it, or the first one at the start. ok is false when the
+// tags came back broken; the segments then put the whole text in the first run.
+func parseRunMarkup(text string, runCount int) (segments []runSegment, ok bool) {
+	plain := func() []runSegment {
+		return []runSegment{{Run: 0, Text: markupUnescaper.Replace(runTagPattern.ReplaceAllString(text, ""))}}
+	}
+	if runCount <= 1 {
+		return plain(), !runTagPattern.MatchString(text)
+	}
+
+	open := -1
+	leading := ""
+	add := func(run int, part string) {
+		if part == "" {
+			return
+		}
+		if len(segments) > 0 && segments[len(segments)-1].Run == run {
+			segments[len(segments)-1].Text += part
+			return
+		}
+		segments = append(segments, runSegment{Run: run, Text: part})
+	}
+	position := 0
+	for _, match := range runTagPattern.FindAllStringSubmatchIndex(text, -1) {
+		part := markupUnescaper.Replace(text[position:match[0]])
+		position = match[1]
+		run, _ := strconv.Atoi(text[match[2]:match[3]])
+		run--
+		closing := text[match[0]+1] == '/'
+
+		switch {
+		case open >= 0:
+			add(open, part)
+		case len(segments) > 0:
+			add(segments[len(segments)-1].Run, part)
+		default:
+			leading += part
+		}
+
+		if run < 0 || run >= runCount || closing != (open >= 0) || closing && run != open {
+			return plain(), false
+		}
+		if closing {
+			open = -1
+			continue
+		}
+		open = run
+		if leading != "" {
+			add(run, leading)
+			leading = ""
+		}
+	}
+	if open >= 0 || len(segments) == 0 {
+		return plain(), false
+	}
+	add(segments[len(segments)-1].Run, markupUnescaper.Replace(text[position:]))
+	return segments, true
+}
+
+// TranslateSlidesDefinition defines the translate_slides tool
+var TranslateSlidesDefinition = ToolDefinition{
+	Name: "translate_slides",
+	Description: `Translate the text shapes, table cells and optionally speaker notes of selected slides into a target language, keeping their formatting.
+
+Each paragraph is translated as a whole with its bold, italic, colored or linked words marked, so the formatting stays on the translated words and paragraph alignment, bullets and indents are untouched. Use it for localizing part of a deck or for translating in place without losing formatting; translate_presentation can write a whole deck into a copy instead. Omit slides to translate every slide. Text inside images and charts can't be translated and is listed in untranslatable.`,
+	InputSchema: TranslateSlidesInputSchema,
+	Function:    TranslateSlides,
+	Mutating:    true,
+	Destructive: true,
+	Timeout:     15 * time.Minute, // One model call per batch of paragraphs
+}
+
+type TranslateSlidesInput struct {
+	PresentationPath string `json:"presentation_path" jsonschema_description:"Path to the PowerPoint (.pptx) file"`
+	Slides           []int  `json:"slides,omitempty" jsonschema_description:"(Optional) Slide numbers to translate (1-based); omit for the whole deck"`
+	TargetLanguage   string `json:"target_language" jsonschema_description:"Language to translate into, e.g. 'German' or 'DE'"`
+	SourceLanguage   string `json:"source_language,omitempty" jsonschema_description:"(Optional) Source language, detected automatically if omitted"`
+	IncludeNotes     bool   `json:"include_notes,omitempty" jsonschema_description:"(Optional) Also translate the speaker notes of the slides"`
+}
+
+var TranslateSlidesInputSchema = GenerateSchema[TranslateSlidesInput]()
+
+func TranslateSlides(ctx context.Context, app *App, input json.RawMessage) (string, error) {
+	translateInput := TranslateSlidesInput{}
+	if err := json.Unmarshal(input, &translateInput); err != nil {
+		return "", NewToolError(ErrCodeInvalidInput, "failed to parse input: %v", err)
+	}
+
+	// Use current presentation path if not provided
+	if translateInput.PresentationPath == "" {
+		if currentPath := app.presentationPath(); currentPath != "" {
+			translateInput.PresentationPath = currentPath
+		} else {
+			return "", NewToolError(ErrCodeNoPresentation, "no presentation loaded - please load a presentation first")
+		}
+	}
+
+	if strings.TrimSpace(translateInput.TargetLanguage) == "" {
+		return "", NewToolError(ErrCodeInvalidInput, "target_language is required")
+	}
+	for _, slideNumber := range translateInput.Slides {
+		if slideNumber < 1 {
+			return "", NewToolError(ErrCodeSlideOutOfRange, "slide numbers must be 1 or greater")
+		}
+	}
+	slides, err := json.Marshal(translateInput.Slides)
+	if err != nil {
+		return "", NewToolError(ErrCodeInternal, "failed to encode slides: %v", err)
+	}
+	if translateInput.Slides == nil {
+		slides = []byte("[]")
+	}
+
+	translator, err := NewTranslatorFromEnv(app)
+	if err != nil {
+		return "", NewToolError(ErrCodeProviderError, "%v", err)
+	}
+	// The texts carry run tags and escaped markup characters
+	if deepl, ok := translator.(*DeepLTranslator); ok {
+		deepl.tagHandling = "xml"
+	}
+
+	output, err := runUnoScript(ctx, app, "uno_translate.py", "extract_paragraphs", translateInput.PresentationPath,
+		string(slides), fmt.Sprintf("%t", translateInput.IncludeNotes))
+	if err != nil {
+		return "", scriptError("failed to extract text", err, output)
+	}
+	extracted, err := decodeScriptResult[struct {
+		TotalSlides    int                     `json:"total_slides"`
+		Paragraphs     []TextParagraph         `json:"paragraphs"`
+		Untranslatable []UntranslatableElement `json:"untranslatable"`
+	}](output)
+	if err != nil {
+		return "", err
+	}
+
+	// Paragraphs with fields are sent run by run, since their runs can't be reordered
+	var elements []TextElement
+	for _, paragraph := range extracted.Paragraphs {
+		if !paragraph.FixedOrder {
+			elements = append(elements, TextElement{ID: paragraph.ID, SlideNumber: paragraph.SlideNumber, Kind: paragraph.Kind, Text: runMarkup(paragraph.Runs)})
+			continue
+		}
+		for i, run := range paragraph.Runs {
+			if strings.TrimSpace(run) != "" {
+				elements = append(elements, TextElement{ID: fmt.Sprintf("%s#%d", paragraph.ID, i), SlideNumber: paragraph.SlideNumber, Kind: paragraph.Kind, Text: runMarkup([]string{run})})
+			}
+		}
+	}
+	translations, untranslatable := translateElements(ctx, translator, elements, translateInput.TargetLanguage, translateInput.SourceLanguage)
+	untranslatable = append(extracted.Untranslatable, untranslatable...)
+
+	segments := make(map[string][]runSegment)
+	simplified := 0
+	for _, paragraph := range extracted.Paragraphs {
+		if paragraph.FixedOrder {
+			for i := range paragraph.Runs {
+				if text, ok := translations[fmt.Sprintf("%s#%d", paragraph.ID, i)]; ok {
+					segments[paragraph.ID] = append(segments[paragraph.ID], runSegment{Run: i, Text: markupUnescaper.Replace(runTagPattern.ReplaceAllString(text, ""))})
+				}
+			}
+			continue
+		}
+		if text, ok := translations[paragraph.ID]; ok {
+			parsed, ok := parseRunMarkup(text, len(paragraph.Runs))
+			if !ok {
+				simplified++
+			}
+			segments[paragraph.ID] = parsed
+		}
+	}
+
+	applied := 0
+	var changedSlides []int
+	if len(segments) > 0 {
+		translationsFile, err := os.CreateTemp(sessionTempDir(), "translations-*.json")
+		if err != nil {
+			return "", NewToolError(ErrCodeInternal, "failed to create translations file: %v", err)
+		}
+		defer os.Remove(translationsFile.Name())
+
+		if err := json.NewEncoder(translationsFile).Encode(segments); err != nil {
+			translationsFile.Close()
+			return "", NewToolError(ErrCodeInternal, "failed to write translations file: %v", err)
+		}
+		translationsFile.Close()
+
+		output, err = runUnoScript(ctx, app, "uno_translate.py", "apply_paragraphs", translateInput.PresentationPath, translationsFile.Name())
+		if err != nil {
+			return "", scriptError("failed to apply translations", err, output)
+		}
+		applyResult, err := decodeScriptResult[struct {
+			Applied int                     `json:"applied"`
+			Failed  []UntranslatableElement `json:"failed"`
+		}](output)
+		if err != nil {
+			return "", err
+		}
+		applied = applyResult.Applied
+		untranslatable = append(untranslatable, applyResult.Failed...)
+
+		seen := make(map[int]bool)
+		for _, paragraph := range extracted.Paragraphs {
+			if _, ok := segments[paragraph.ID]; ok && !seen[paragraph.SlideNumber] {
+				seen[paragraph.SlideNumber] = true
+				changedSlides = append(changedSlides, paragraph.SlideNumber)
+			}
+		}
+		if applied > 0 {
+			schedulePreviewExport(ctx, app, translateInput.PresentationPath, changedSlides...)
+		}
+	}
+
+	message := fmt.Sprintf("Translated %d of %d paragraphs into %s", applied, len(extracted.Paragraphs), translateInput.TargetLanguage)
+	if simplified > 0 {
+		message += fmt.Sprintf("; %d paragraphs lost their inline formatting because the run markup came back broken", simplified)
+	}
+	return marshalResult(map[string]interface{}{
+		"success":               true,
+		"translator":            translator.Name(),
+		"target_language":       translateInput.TargetLanguage,
+		"slides":                changedSlides,
+		"paragraphs_found":      len(extracted.Paragraphs),
+		"paragraphs_translated": applied,
+		"formatting_simplified": simplified,
+		"untranslatable":        untranslatable,
+		"untranslatable_count":  len(untranslatable),
+		"message":               message,
+	})
+}
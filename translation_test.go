@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// batchTranslator upper-cases texts and fails the batches that contain "fail"
+type batchTranslator struct {
+	batches int
+}
+
+func (t *batchTranslator) Name() string {
+	return "test"
+}
+
+func (t *batchTranslator) Translate(ctx context.Context, texts []string, targetLanguage, sourceLanguage string) ([]string, error) {
+	t.batches++
+	translated := make([]string, len(texts))
+	for i, text := range texts {
+		if text == "fail" {
+			return nil, fmt.Errorf("provider unavailable")
+		}
+		translated[i] = strings.ToUpper(text)
+	}
+	return translated, nil
+}
+
+func TestTranslateElementsReportsFailedBatches(t *testing.T) {
+	var elements []TextElement
+	for i := 0; i < translationBatchSize+1; i++ {
+		elements = append(elements, TextElement{ID: fmt.Sprintf("s1-%d", i), SlideNumber: 1, Text: "hello"})
+	}
+	elements = append(elements, TextElement{ID: "s2-0", SlideNumber: 2, Text: "fail"})
+
+	translator := &batchTranslator{}
+	translations, failed := translateElements(context.Background(), translator, elements, "de", "")
+	if translator.batches != 2 {
+		t.Errorf("expected 2 batches, got %d", translator.batches)
+	}
+	if len(translations) != translationBatchSize || translations["s1-0"] != "HELLO" {
+		t.Errorf("expected the first batch translated, got %d translations", len(translations))
+	}
+	// The second batch failed as a whole, but the deck's other translations are kept
+	if len(failed) != 2 || failed[0].Element != "s1-40" || !strings.Contains(failed[1].Reason, "provider unavailable") {
+		t.Errorf("expected both elements of the failed batch reported, got %+v", failed)
+	}
+}
+
+func TestDeepLTranslator(t *testing.T) {
+	var form map[string][]string
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		r.ParseForm()
+		form = r.PostForm
+		w.Write([]byte(`{"translations": [{"text": "Hallo"}, {"text": "Welt"}]}`))
+	}))
+	defer server.Close()
+
+	translator := &DeepLTranslator{endpoint: server.URL, apiKey: "key", client: server.Client()}
+	translated, err := translator.Translate(context.Background(), []string{"Hello", "World"}, "de", "en")
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if strings.Join(translated, " ") != "Hallo Welt" || auth != "DeepL-Auth-Key key" {
+		t.Errorf("unexpected translation %v with authorization %q", translated, auth)
+	}
+	if form["target_lang"][0] != "DE" || form["source_lang"][0] != "EN" || len(form["text"]) != 2 {
+		t.Errorf("unexpected request form %v", form)
+	}
+}
+
+func TestParseRunMarkup(t *testing.T) {
+	if markup := runMarkup([]string{"Revenue", " grew ", "12% & more"}); markup != "<g1>Revenue</g1><g2> grew </g2><g3>12% &amp; more</g3>" {
+		t.Errorf("unexpected markup: %s", markup)
+	}
+	if markup := runMarkup([]string{"R&D <beta>"}); markup != "R&amp;D &lt;beta&gt;" {
+		t.Errorf("expected a single run without tags, got %s", markup)
+	}
+
+	for _, test := range []struct {
+		text string
+		runs int
+		want string
+		ok   bool
+	}{
+		// Reordered runs keep their formatting, and stray text joins the run before it
+		{"Der <g1>Umsatz</g1><g2> stieg um </g2><g3>12 % &amp; mehr</g3>.", 3, "[{0 Der Umsatz} {1  stieg um } {2 12 % & mehr.}]", true},
+		{"<g3>12 %</g3><g2> Wachstum beim </g2><g1>Umsatz</g1>", 3, "[{2 12 %} {1  Wachstum beim } {0 Umsatz}]", true},
+		{"<g1>Umsatz</g1> und <g1>Gewinn</g1>", 2, "[{0 Umsatz und Gewinn}]", true},
+		{"F&amp;E", 1, "[{0 F&E}]", true},
+		// Broken tags put the whole text in the first run
+		{"<g1>Umsatz <g2>stieg</g2></g1>", 2, "[{0 Umsatz stieg}]", false},
+		{"<g1>Umsatz</g1> <g5>stieg</g5>", 2, "[{0 Umsatz stieg}]", false},
+		{"<g1>Umsatz stieg", 2, "[{0 Umsatz stieg}]", false},
+		{"Umsatz stieg", 2, "[{0 Umsatz stieg}]", false},
+	} {
+		segments, ok := parseRunMarkup(test.text, test.runs)
+		if got := fmt.Sprint(segments); got != test.want || ok != test.ok {
+			t.Errorf("parseRunMarkup(%q) = %s, %t, want %s, %t", test.text, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestTranslateSlides(t *testing.T) {
+	env := newTestEnv(t, textResponse(`Here you go: ["<g1>Der Umsatz</g1><g2> stieg</g2>", "Quelle: ", "Notizen"]`))
+	path := env.loadFixture(t, "two_slides.pptx")
+	t.Setenv("SLIDEPILOT_TRANSLATION_PROVIDER", "")
+
+	var applied map[string][]runSegment
+	env.uno.Handle("uno_translate.py", func(args []string) ([]byte, error) {
+		if args[0] == "extract_paragraphs" {
+			return []byte(`{"success": true, "total_slides": 2, "paragraphs": [
+				{"id": "slide-2-0/0", "slide_number": 2, "kind": "shape", "runs": ["Revenue", " grew"]},
+				{"id": "slide-2-1/0", "slide_number": 2, "kind": "shape", "runs": ["Source: ", " "], "fixed_order": true},
+				{"id": "notes-2-1/0", "slide_number": 2, "kind": "notes", "runs": ["Notes"]}
+			], "untranslatable": [{"slide_number": 2, "element": "slide-2-2", "reason": "chart"}]}`), nil
+		}
+		data, err := os.ReadFile(args[2])
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &applied); err != nil {
+			return nil, err
+		}
+		return []byte(`{"success": true, "applied": 3, "failed": []}`), nil
+	})
+
+	output, err := TranslateSlides(context.Background(), env.app, json.RawMessage(`{"slides": [2], "target_language": "German", "include_notes": true}`))
+	if err != nil {
+		t.Fatalf("TranslateSlides failed: %v", err)
+	}
+	calls := env.uno.Calls("uno_translate.py")
+	if len(calls) != 2 || fmt.Sprint(calls[0].Args) != fmt.Sprint([]string{"extract_paragraphs", path, "[2]", "true"}) || calls[1].Args[0] != "apply_paragraphs" {
+		t.Fatalf("unexpected script calls: %+v", calls)
+	}
+
+	// The fixed-order paragraph is sent run by run, skipping the blank one
+	prompt := env.llm.Requests[0].Messages[0].Content[0].OfText.Text
+	if !strings.Contains(prompt, `["<g1>Revenue</g1><g2> grew</g2>","Source: ","Notes"]`) {
+		t.Errorf("unexpected translation prompt: %s", prompt)
+	}
+	want := map[string][]runSegment{
+		"slide-2-0/0": {{Run: 0, Text: "Der Umsatz"}, {Run: 1, Text: " stieg"}},
+		"slide-2-1/0": {{Run: 0, Text: "Quelle: "}},
+		"notes-2-1/0": {{Run: 0, Text: "Notizen"}},
+	}
+	if fmt.Sprint(applied) != fmt.Sprint(want) {
+		t.Errorf("applied %v, want %v", applied, want)
+	}
+
+	var result struct {
+		Translated          int   `json:"paragraphs_translated"`
+		Slides              []int `json:"slides"`
+		UntranslatableCount int   `json:"untranslatable_count"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Translated != 3 || fmt.Sprint(result.Slides) != "[2]" || result.UntranslatableCount != 1 {
+		t.Errorf("unexpected result: %s", output)
+	}
+
+	for input, code := range map[string]ToolErrorCode{
+		`{"slides": [2]}`: ErrCodeInvalidInput,
+		`{"slides": [0], "target_language": "German"}`:   ErrCodeSlideOutOfRange,
+		`{"slides": "all", "target_language": "German"}`: ErrCodeInvalidInput,
+	} {
+		_, err := TranslateSlides(context.Background(), env.app, json.RawMessage(input))
+		if got := toolErrorCode(err); got != code {
+			t.Errorf("expected %s for %s, got %s (%v)", code, input, got, err)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+)
+
+// unoWorkerScript is the long-lived Python process UnoWorkerBridge talks to
+const unoWorkerScript = "uno_worker.py"
+
+// maxWorkerResponse bounds one response line; read_slide on a dense slide can be large
+const maxWorkerResponse = 64 * 1024 * 1024
+
+// UnoWorkerBridge runs UNO scripts inside one persistent python3 process instead of
+// starting a new interpreter per call. Requests and responses are JSON lines over the
+// worker's stdin/stdout. The worker runs one script at a time, so calls are serialized.
+// If the worker cannot be started, calls fall back to a fresh python3 process each.
+type UnoWorkerBridge struct {
+	ScriptsDir string
+
+	sem    chan struct{} // Held by the call talking to the worker
+	mu     sync.Mutex    // Guards worker for Close
+	worker *unoWorker
+	nextID int
+}
+
+// unoWorker is one running worker process
+type unoWorker struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Scanner
+	done   chan struct{} // Closed when the process has exited
+}
+
+type unoWorkerRequest struct {
+	ID     int      `json:"id"`
+	Script string   `json:"script"`
+	Args   []string `json:"args"`
+}
+
+type unoWorkerResponse struct {
+	ID       int    `json:"id"`
+	Output   string `json:"output"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// NewUnoWorkerBridge creates a bridge running scripts from scriptsDir. The worker starts
+// lazily on the first call.
+func NewUnoWorkerBridge(scriptsDir string) *UnoWorkerBridge {
+	return &UnoWorkerBridge{ScriptsDir: scriptsDir, sem: make(chan struct{}, 1)}
+}
+
+func (b *UnoWorkerBridge) Run(ctx context.Context, script string, args ...string) ([]byte, error) {
+	select {
+	case b.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-b.sem }()
+
+	worker, err := b.ensureWorker()
+	if err != nil {
+		fmt.Printf("UNO worker unavailable, running %s in a new process: %v\n", script, err)
+		return PythonUnoBridge{ScriptsDir: b.ScriptsDir}.Run(ctx, script, args...)
+	}
+
+	b.nextID++
+	request := unoWorkerRequest{ID: b.nextID, Script: script, Args: args}
+	if request.Args == nil {
+		request.Args = []string{}
+	}
+	line, _ := json.Marshal(request)
+
+	type result struct {
+		response unoWorkerResponse
+		err      error
+	}
+	results := make(chan result, 1)
+	go func() {
+		if _, err := worker.stdin.Write(append(line, '\n')); err != nil {
+			results <- result{err: fmt.Errorf("failed to send request to UNO worker: %v", err)}
+			return
+		}
+		if !worker.stdout.Scan() {
+			err := worker.stdout.Err()
+			if err == nil {
+				err = io.ErrUnexpectedEOF
+			}
+			results <- result{err: fmt.Errorf("UNO worker exited while running %s: %v", script, err)}
+			return
+		}
+		var response unoWorkerResponse
+		if err := json.Unmarshal(worker.stdout.Bytes(), &response); err != nil {
+			results <- result{err: fmt.Errorf("invalid response from UNO worker: %v", err)}
+			return
+		}
+		results <- result{response: response}
+	}()
+
+	select {
+	case <-ctx.Done():
+		// The script can't be interrupted in-process, so the worker is killed and restarted
+		// on the next call, like a cancelled per-call python3 process
+		b.stopWorker(worker)
+		<-results
+		return nil, ctx.Err()
+	case res := <-results:
+		if res.err != nil {
+			b.stopWorker(worker)
+			return nil, res.err
+		}
+		if res.response.ID != request.ID {
+			b.stopWorker(worker)
+			return nil, fmt.Errorf("UNO worker answered request %d instead of %d", res.response.ID, request.ID)
+		}
+		output := []byte(res.response.Output)
+		if res.response.ExitCode != 0 {
+			return output, fmt.Errorf("exit status %d", res.response.ExitCode)
+		}
+		return output, nil
+	}
+}
+
+// Close stops the worker process
+func (b *UnoWorkerBridge) Close() error {
+	b.mu.Lock()
+	worker := b.worker
+	b.mu.Unlock()
+	if worker != nil {
+		b.stopWorker(worker)
+	}
+	return nil
+}
+
+// ensureWorker returns the running worker, starting one if needed
+func (b *UnoWorkerBridge) ensureWorker() (*unoWorker, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.worker != nil {
+		select {
+		case <-b.worker.done:
+			b.worker = nil
+		default:
+			return b.worker, nil
+		}
+	}
+
+	workerPath := filepath.Join(b.ScriptsDir, unoWorkerScript)
+	if _, err := os.Stat(workerPath); err != nil {
+		return nil, err
+	}
+
+	// Not tied to a turn's context: the worker outlives individual calls
+	cmd := exec.Command("python3", "-u", workerPath)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxWorkerResponse)
+	worker := &unoWorker{cmd: cmd, stdin: stdin, stdout: scanner, done: make(chan struct{})}
+	go func() {
+		cmd.Wait()
+		close(worker.done)
+	}()
+
+	fmt.Printf("Started UNO worker (pid %d)\n", cmd.Process.Pid)
+	b.worker = worker
+	return worker, nil
+}
+
+// stopWorker kills a worker and forgets it if it is still the current one
+func (b *UnoWorkerBridge) stopWorker(worker *unoWorker) {
+	worker.stdin.Close()
+	worker.cmd.Process.Kill()
+	<-worker.done
+
+	b.mu.Lock()
+	if b.worker == worker {
+		b.worker = nil
+	}
+	b.mu.Unlock()
+}
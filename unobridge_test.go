@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestWorkerBridge returns a bridge whose scripts directory holds the real worker and
+// the given scripts
+func newTestWorkerBridge(t *testing.T, scripts map[string]string) *UnoWorkerBridge {
+	t.Helper()
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+
+	dir := t.TempDir()
+	if err := copyFile(filepath.Join("scripts", unoWorkerScript), filepath.Join(dir, unoWorkerScript)); err != nil {
+		t.Fatal(err)
+	}
+	for name, source := range scripts {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bridge := NewUnoWorkerBridge(dir)
+	t.Cleanup(func() { bridge.Close() })
+	return bridge
+}
+
+func TestUnoWorkerBridgeReusesOneProcess(t *testing.T) {
+	bridge := newTestWorkerBridge(t, map[string]string{
+		"echo.py": "import os, sys, json\nprint(json.dumps({'pid': os.getpid(), 'args': sys.argv[1:]}))\n",
+		"fail.py": "import sys\nprint('{\"success\": false, \"error\": \"boom\"}')\nsys.exit(1)\n",
+	})
+
+	first, err := bridge.Run(context.Background(), "echo.py", "a b", "c")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.Contains(string(first), `"args": ["a b", "c"]`) {
+		t.Errorf("unexpected output: %s", first)
+	}
+	second, err := bridge.Run(context.Background(), "echo.py")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	pid := func(output []byte) string {
+		return strings.SplitN(strings.SplitN(string(output), `"pid": `, 2)[1], ",", 2)[0]
+	}
+	if pid(first) != pid(second) {
+		t.Errorf("expected both calls to run in one worker, got pids %s and %s", pid(first), pid(second))
+	}
+
+	output, err := bridge.Run(context.Background(), "fail.py")
+	if err == nil || err.Error() != "exit status 1" {
+		t.Errorf("expected exit status 1, got %v", err)
+	}
+	if !strings.Contains(string(output), "boom") {
+		t.Errorf("expected the script's error output, got %s", output)
+	}
+}
+
+func TestUnoWorkerBridgeRestartsAfterCancellation(t *testing.T) {
+	bridge := newTestWorkerBridge(t, map[string]string{
+		"hang.py": "import time\ntime.sleep(60)\n",
+		"ok.py":   "print('ok')\n",
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := bridge.Run(ctx, "hang.py"); err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("cancellation took %v", elapsed)
+	}
+
+	output, err := bridge.Run(context.Background(), "ok.py")
+	if err != nil || strings.TrimSpace(string(output)) != "ok" {
+		t.Fatalf("expected a fresh worker to run the next call, got %q, %v", output, err)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// modelPrice is a model's list price in US dollars per million tokens
+type modelPrice struct {
+	Input      float64
+	Output     float64
+	CacheWrite float64
+	CacheRead  float64
+}
+
+// modelPrices maps model name prefixes to their prices; the longest matching prefix wins,
+// so dated snapshots such as claude-sonnet-4-20250514 use their family's price
+var modelPrices = map[string]modelPrice{
+	"claude-opus-4":     {Input: 15, Output: 75, CacheWrite: 18.75, CacheRead: 1.5},
+	"claude-sonnet-4":   {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.3},
+	"claude-3-7-sonnet": {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.3},
+	"claude-3-5-sonnet": {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.3},
+	"claude-3-5-haiku":  {Input: 0.8, Output: 4, CacheWrite: 1, CacheRead: 0.08},
+	"gpt-4.1":           {Input: 2, Output: 8},
+	"gpt-4.1-mini":      {Input: 0.4, Output: 1.6},
+	"gpt-4o":            {Input: 2.5, Output: 10},
+	"gpt-4o-mini":       {Input: 0.15, Output: 0.6},
+}
+
+// priceForModel returns the price of a model, and false when it isn't known
+func priceForModel(model string) (modelPrice, bool) {
+	best := ""
+	for prefix := range modelPrices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	price, found := modelPrices[best]
+	return price, found
+}
+
+// TokenUsage counts the tokens of one or more model requests
+type TokenUsage struct {
+	Requests            int     `json:"requests"`
+	InputTokens         int64   `json:"input_tokens"`
+	OutputTokens        int64   `json:"output_tokens"`
+	CacheCreationTokens int64   `json:"cache_creation_tokens"`
+	CacheReadTokens     int64   `json:"cache_read_tokens"`
+	EstimatedCostUSD    float64 `json:"estimated_cost_usd"`
+}
+
+// add counts one response's usage, priced for model
+func (u *TokenUsage) add(model string, usage anthropic.Usage) {
+	u.Requests++
+	u.InputTokens += usage.InputTokens
+	u.OutputTokens += usage.OutputTokens
+	u.CacheCreationTokens += usage.CacheCreationInputTokens
+	u.CacheReadTokens += usage.CacheReadInputTokens
+	if price, found := priceForModel(model); found {
+		u.EstimatedCostUSD += (float64(usage.InputTokens)*price.Input +
+			float64(usage.OutputTokens)*price.Output +
+			float64(usage.CacheCreationInputTokens)*price.CacheWrite +
+			float64(usage.CacheReadInputTokens)*price.CacheRead) / 1e6
+	}
+}
+
+// UsageStats is the token usage and estimated cost of the session so far
+type UsageStats struct {
+	TokenUsage
+	ByModel map[string]TokenUsage `json:"by_model"`
+	// UnpricedModels lists models used without a known price; their tokens count but cost nothing
+	UnpricedModels []string `json:"unpriced_models,omitempty"`
+}
+
+// UsageTracker adds up the token usage of every model response in a session
+type UsageTracker struct {
+	mu    sync.Mutex
+	stats UsageStats
+}
+
+// NewUsageTracker creates a tracker with nothing recorded
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{stats: UsageStats{ByModel: make(map[string]TokenUsage)}}
+}
+
+// Record adds a response's usage and returns the new totals
+func (t *UsageTracker) Record(message *anthropic.Message) UsageStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	model := string(message.Model)
+	t.stats.add(model, message.Usage)
+	byModel := t.stats.ByModel[model]
+	byModel.add(model, message.Usage)
+	t.stats.ByModel[model] = byModel
+	if _, found := priceForModel(model); !found && !slices.Contains(t.stats.UnpricedModels, model) {
+		t.stats.UnpricedModels = append(t.stats.UnpricedModels, model)
+	}
+	return t.snapshot()
+}
+
+// Stats returns the totals so far
+func (t *UsageTracker) Stats() UsageStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.snapshot()
+}
+
+// snapshot copies the stats so callers can't race with later records; the caller holds the lock
+func (t *UsageTracker) snapshot() UsageStats {
+	stats := t.stats
+	stats.ByModel = make(map[string]TokenUsage, len(t.stats.ByModel))
+	for model, usage := range t.stats.ByModel {
+		stats.ByModel[model] = usage
+	}
+	stats.UnpricedModels = append([]string(nil), t.stats.UnpricedModels...)
+	return stats
+}
+
+// recordUsage counts a model response towards the session totals, logs it with what it
+// was for, and sends the totals to the frontend as "usage-updated"
+func (a *App) recordUsage(ctx context.Context, purpose string, message *anthropic.Message) {
+	if a.usage == nil || message == nil {
+		return
+	}
+	stats := a.usage.Record(message)
+	if a.aiAgent != nil {
+		a.aiAgent.logToFile("USAGE", fmt.Sprintf("%s: %d input, %d output tokens (%s)", purpose,
+			message.Usage.InputTokens, message.Usage.OutputTokens, message.Model),
+			fmt.Sprintf("Session total: %d input, %d output tokens, ~$%.4f", stats.InputTokens, stats.OutputTokens, stats.EstimatedCostUSD))
+	}
+	if a.events != nil && ctx != nil {
+		a.events.Emit(ctx, "usage-updated", stats)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+func TestUsageTrackerPricesByModelFamily(t *testing.T) {
+	tracker := NewUsageTracker()
+	tracker.Record(&anthropic.Message{Model: "claude-sonnet-4-20250514", Usage: anthropic.Usage{InputTokens: 1_000_000, OutputTokens: 100_000}})
+	stats := tracker.Record(&anthropic.Message{Model: "gpt-4.1-mini", Usage: anthropic.Usage{InputTokens: 1_000_000}})
+
+	// $3 input + $1.50 output for Sonnet 4, $0.40 input for gpt-4.1-mini rather than gpt-4.1's $2
+	if math.Abs(stats.EstimatedCostUSD-4.9) > 1e-9 {
+		t.Errorf("expected $4.90, got $%f", stats.EstimatedCostUSD)
+	}
+	if stats.Requests != 2 || stats.InputTokens != 2_000_000 || stats.ByModel["gpt-4.1-mini"].Requests != 1 {
+		t.Errorf("unexpected totals %+v", stats)
+	}
+	if len(stats.UnpricedModels) != 0 {
+		t.Errorf("expected every model to be priced, got %v", stats.UnpricedModels)
+	}
+}
+
+func TestSendMessageRecordsUsage(t *testing.T) {
+	env := newTestEnv(t,
+		toolUseResponse("toolu_1", "list_slides", `{}`),
+		textResponse("The deck has two slides."),
+	)
+	env.loadFixture(t, "two_slides.pptx")
+
+	if err := env.app.aiAgent.SendMessage(context.Background(), "How many slides?"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	stats := env.app.GetUsageStats()
+	if stats.Requests != 2 || stats.InputTokens != 2 || stats.OutputTokens != 2 {
+		t.Errorf("expected both inference requests to be counted, got %+v", stats)
+	}
+	if len(stats.UnpricedModels) != 1 || stats.UnpricedModels[0] != "fake" {
+		t.Errorf("expected the fake model to be reported as unpriced, got %v", stats.UnpricedModels)
+	}
+	updates := env.events.Messages("usage-updated")
+	if len(updates) != 2 || updates[1].(UsageStats).Requests != 2 {
+		t.Errorf("expected a usage-updated event per request, got %v", updates)
+	}
+}
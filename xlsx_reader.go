@@ -0,0 +1,349 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An .xlsx workbook is an OPC package like a .pptx, so it is read with the same package
+// helpers. Cells are read as Excel displays them where that's cheap to get right: shared
+// and inline strings, booleans, dates and percentages; other numbers keep their stored
+// value rather than their number format.
+
+// xlsxWorkbook is an open .xlsx file
+type xlsxWorkbook struct {
+	pkg           *pptxPackage
+	sheets        []xlsxSheet
+	sharedStrings []string
+	dateStyles    map[int]bool // cellXfs indexes with a date number format
+	percentStyles map[int]bool // cellXfs indexes with a percentage number format
+	date1904      bool
+}
+
+// xlsxSheet is a worksheet of a workbook
+type xlsxSheet struct {
+	Name string
+	part string
+}
+
+// xlsxDateFormats are the built-in number formats that show dates and times
+var xlsxDateFormats = map[int]bool{14: true, 15: true, 16: true, 17: true, 18: true, 19: true, 20: true, 21: true, 22: true, 45: true, 46: true, 47: true}
+
+// xlsxLiteralPattern matches the quoted text, escaped characters and [color]/[locale]
+// sections of a number format code, which don't make it a date or percentage
+var xlsxLiteralPattern = regexp.MustCompile(`"[^"]*"|\\.|\[[^\]]*\]`)
+
+// xlsxCellRefPattern splits a cell reference such as AB12 into its column and row
+var xlsxCellRefPattern = regexp.MustCompile(`^([A-Za-z]{1,3})([0-9]+)$`)
+
+// Limits on the cells read from a worksheet. Sheets are read in full before the data is
+// filtered, so these are far above what a slide table or chart shows, but they keep a
+// stray value near XFD1048576 from making the reader allocate the whole grid.
+const (
+	maxSheetRows    = 20000
+	maxSheetColumns = 200
+)
+
+// openXLSX opens a workbook and reads its sheet list, shared strings and cell styles
+func openXLSX(workbookPath string) (*xlsxWorkbook, error) {
+	reader, err := zip.OpenReader(workbookPath)
+	if err != nil {
+		return nil, fmt.Errorf("workbook is not a valid zip package: %w", err)
+	}
+	pkg := &pptxPackage{reader: reader, parts: make(map[string]*zip.File, len(reader.File))}
+	for _, file := range reader.File {
+		pkg.parts[file.Name] = file
+	}
+	w := &xlsxWorkbook{pkg: pkg}
+	if err := w.load(); err != nil {
+		reader.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *xlsxWorkbook) load() error {
+	var workbook struct {
+		Properties struct {
+			Date1904 string `xml:"date1904,attr"`
+		} `xml:"workbookPr"`
+		Sheets []struct {
+			Name  string `xml:"name,attr"`
+			RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := w.pkg.decode("xl/workbook.xml", &workbook); err != nil {
+		return err
+	}
+	w.date1904 = workbook.Properties.Date1904 == "1" || workbook.Properties.Date1904 == "true"
+
+	rels, err := w.pkg.relationships("xl/workbook.xml")
+	if err != nil {
+		return err
+	}
+	for _, sheet := range workbook.Sheets {
+		// Chart sheets and other non-worksheet targets have no cells to read
+		if target, ok := rels[sheet.RelID]; ok && strings.HasPrefix(target, "xl/worksheets/") {
+			w.sheets = append(w.sheets, xlsxSheet{Name: sheet.Name, part: target})
+		}
+	}
+	if len(w.sheets) == 0 {
+		return fmt.Errorf("workbook has no worksheets")
+	}
+
+	if _, ok := w.pkg.parts["xl/sharedStrings.xml"]; ok {
+		var shared struct {
+			Items []struct {
+				Text string   `xml:"t"`
+				Runs []string `xml:"r>t"`
+			} `xml:"si"`
+		}
+		if err := w.pkg.decode("xl/sharedStrings.xml", &shared); err != nil {
+			return err
+		}
+		for _, item := range shared.Items {
+			w.sharedStrings = append(w.sharedStrings, item.Text+strings.Join(item.Runs, ""))
+		}
+	}
+
+	w.dateStyles, w.percentStyles = map[int]bool{}, map[int]bool{}
+	if _, ok := w.pkg.parts["xl/styles.xml"]; ok {
+		var styles struct {
+			NumFmts []struct {
+				ID   int    `xml:"numFmtId,attr"`
+				Code string `xml:"formatCode,attr"`
+			} `xml:"numFmts>numFmt"`
+			CellXfs []struct {
+				NumFmtID int `xml:"numFmtId,attr"`
+			} `xml:"cellXfs>xf"`
+		}
+		if err := w.pkg.decode("xl/styles.xml", &styles); err != nil {
+			return err
+		}
+		custom := map[int]string{}
+		for _, format := range styles.NumFmts {
+			custom[format.ID] = strings.ToLower(xlsxLiteralPattern.ReplaceAllString(format.Code, ""))
+		}
+		for i, xf := range styles.CellXfs {
+			code, isCustom := custom[xf.NumFmtID]
+			switch {
+			case xlsxDateFormats[xf.NumFmtID], isCustom && strings.ContainsAny(code, "yd"):
+				w.dateStyles[i] = true
+			case xf.NumFmtID == 9 || xf.NumFmtID == 10, isCustom && strings.Contains(code, "%"):
+				w.percentStyles[i] = true
+			}
+		}
+	}
+	return nil
+}
+
+// Close releases the underlying zip file
+func (w *xlsxWorkbook) Close() error {
+	return w.pkg.Close()
+}
+
+// SheetNames returns the names of the worksheets in workbook order
+func (w *xlsxWorkbook) SheetNames() []string {
+	names := make([]string, len(w.sheets))
+	for i, sheet := range w.sheets {
+		names[i] = sheet.Name
+	}
+	return names
+}
+
+// ReadSheet returns the cell texts of a worksheet, the first one when name is "", as
+// rows padded to the same width. Trailing empty rows and columns are dropped; a sheet
+// with data beyond maxSheetRows or maxSheetColumns is refused.
+func (w *xlsxWorkbook) ReadSheet(name string) ([][]string, error) {
+	part, sheetName := w.sheets[0].part, w.sheets[0].Name
+	if name != "" {
+		part = ""
+		for _, sheet := range w.sheets {
+			if strings.EqualFold(sheet.Name, name) {
+				part, sheetName = sheet.part, sheet.Name
+			}
+		}
+		if part == "" {
+			return nil, fmt.Errorf("no sheet named %q; the workbook has %s", name, strings.Join(w.SheetNames(), ", "))
+		}
+	}
+
+	var sheet struct {
+		Rows []struct {
+			Number int `xml:"r,attr"`
+			Cells  []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Style  int    `xml:"s,attr"`
+				Value  string `xml:"v"`
+				Inline struct {
+					Text string   `xml:"t"`
+					Runs []string `xml:"r>t"`
+				} `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := w.pkg.decode(part, &sheet); err != nil {
+		return nil, err
+	}
+
+	// Collect the cells first: the extent of the data, not the highest reference, sets
+	// the size of the grid, since sheets often carry formatted empty cells far out
+	type sheetCell struct {
+		row, column int
+		text        string
+	}
+	var cells []sheetCell
+	height, width := 0, 0
+	nextRow := 0
+	for _, row := range sheet.Rows {
+		// Rows and cells without a reference follow the one before them
+		rowIndex := nextRow
+		if row.Number > 0 {
+			rowIndex = row.Number - 1
+		}
+		nextRow = rowIndex + 1
+		nextColumn := 0
+		for _, cell := range row.Cells {
+			column := nextColumn
+			if cell.Ref != "" {
+				ref, _, err := parseCellRef(cell.Ref)
+				if err != nil {
+					return nil, err
+				}
+				column = ref
+			}
+			nextColumn = column + 1
+			text := w.cellText(cell.Type, cell.Style, cell.Value, cell.Inline.Text+strings.Join(cell.Inline.Runs, ""))
+			if text == "" {
+				continue
+			}
+			cells = append(cells, sheetCell{row: rowIndex, column: column, text: text})
+			if strings.TrimSpace(text) != "" {
+				height, width = max(height, rowIndex+1), max(width, column+1)
+			}
+		}
+	}
+	if height > maxSheetRows || width > maxSheetColumns {
+		return nil, NewToolError(ErrCodeInvalidInput, "sheet %s has data as far as row %d and column %s; at most %d rows and %d columns can be read",
+			sheetName, height, columnLetter(width-1), maxSheetRows, maxSheetColumns)
+	}
+
+	rows := make([][]string, height)
+	for i := range rows {
+		rows[i] = make([]string, width)
+	}
+	for _, cell := range cells {
+		if cell.row < height && cell.column < width {
+			rows[cell.row][cell.column] = cell.text
+		}
+	}
+	return rows, nil
+}
+
+// cellText returns the text a cell shows
+func (w *xlsxWorkbook) cellText(cellType string, style int, value, inline string) string {
+	switch cellType {
+	case "s":
+		index, err := strconv.Atoi(value)
+		if err != nil || index < 0 || index >= len(w.sharedStrings) {
+			return ""
+		}
+		return w.sharedStrings[index]
+	case "inlineStr":
+		return inline
+	case "b":
+		if value == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	case "str", "e":
+		return value
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	switch {
+	case w.dateStyles[style]:
+		return xlsxDateText(number, w.date1904)
+	case w.percentStyles[style]:
+		return formatDataNumber(number*100) + "%"
+	}
+	return formatDataNumber(number)
+}
+
+// xlsxDateText converts an Excel date serial number to text; times of day are only
+// shown when there is one
+func xlsxDateText(serial float64, date1904 bool) string {
+	// The 1900 system's epoch accounts for Excel counting 29 February 1900
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if date1904 {
+		epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	t := epoch.Add(time.Duration(math.Round(serial*86400)) * time.Second)
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// formatDataNumber writes a number without the binary noise of stored floating point
+// values, e.g. 0.30000000000000004 as 0.3
+func formatDataNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*1e10)/1e10, 'f', -1, 64)
+}
+
+// parseCellRef returns the 0-based column and row of a cell reference such as B3
+func parseCellRef(ref string) (column, row int, err error) {
+	match := xlsxCellRefPattern.FindStringSubmatch(ref)
+	if match == nil {
+		return 0, 0, fmt.Errorf("%q is not a cell reference like B3", ref)
+	}
+	column, _ = columnIndex(match[1])
+	row, _ = strconv.Atoi(match[2])
+	if row < 1 {
+		return 0, 0, fmt.Errorf("%q is not a cell reference like B3", ref)
+	}
+	return column, row - 1, nil
+}
+
+// columnIndex returns the 0-based index of a column letter such as A or AB
+func columnIndex(letters string) (int, bool) {
+	if letters == "" || len(letters) > 3 {
+		return 0, false
+	}
+	index := 0
+	for _, c := range strings.ToUpper(letters) {
+		if c < 'A' || c > 'Z' {
+			return 0, false
+		}
+		index = index*26 + int(c-'A') + 1
+	}
+	return index - 1, true
+}
+
+// columnLetter returns the letter of a 0-based column index
+func columnLetter(index int) string {
+	letters := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		letters = string(rune('A'+(index-1)%26)) + letters
+	}
+	return letters
+}
+
+// isEmptyRow reports whether every cell of a row is blank
+func isEmptyRow(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}